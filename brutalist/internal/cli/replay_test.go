@@ -14,6 +14,9 @@ import (
 	"github.com/roach88/nysm/internal/store"
 )
 
+// testSecurityContext is a valid context (CP-6) for records written in tests.
+var testSecurityContext = ir.NewSecurityContext("test-tenant", "test-user")
+
 func TestReplayMissingDatabaseFlag(t *testing.T) {
 	buf := &bytes.Buffer{}
 	errBuf := &bytes.Buffer{}
@@ -59,23 +62,25 @@ func TestReplayWithFlow(t *testing.T) {
 
 	// Write an invocation and completion
 	inv := ir.Invocation{
-		ID:            "inv-1",
-		FlowToken:     "test-flow-1",
-		ActionURI:     "Test.action",
-		Args:          ir.IRObject{},
-		Seq:           1,
-		SpecHash:      "test-hash",
-		EngineVersion: "test",
-		IRVersion:     ir.IRVersion,
+		ID:              "inv-1",
+		FlowToken:       "test-flow-1",
+		ActionURI:       "Test.action",
+		Args:            ir.IRObject{},
+		Seq:             1,
+		SpecHash:        "test-hash",
+		EngineVersion:   "test",
+		IRVersion:       ir.IRVersion,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, st.WriteInvocation(ctx, inv))
 
 	comp := ir.Completion{
-		ID:           "comp-1",
-		InvocationID: "inv-1",
-		OutputCase:   "Success",
-		Result:       ir.IRObject{},
-		Seq:          2,
+		ID:              "comp-1",
+		InvocationID:    "inv-1",
+		OutputCase:      "Success",
+		Result:          ir.IRObject{},
+		Seq:             2,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, st.WriteCompletion(ctx, comp))
 	st.Close()
@@ -107,23 +112,25 @@ func TestReplayWithFlowJSON(t *testing.T) {
 
 	// Write an invocation and completion
 	inv := ir.Invocation{
-		ID:            "inv-1",
-		FlowToken:     "test-flow-1",
-		ActionURI:     "Test.action",
-		Args:          ir.IRObject{},
-		Seq:           1,
-		SpecHash:      "test-hash",
-		EngineVersion: "test",
-		IRVersion:     ir.IRVersion,
+		ID:              "inv-1",
+		FlowToken:       "test-flow-1",
+		ActionURI:       "Test.action",
+		Args:            ir.IRObject{},
+		Seq:             1,
+		SpecHash:        "test-hash",
+		EngineVersion:   "test",
+		IRVersion:       ir.IRVersion,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, st.WriteInvocation(ctx, inv))
 
 	comp := ir.Completion{
-		ID:           "comp-1",
-		InvocationID: "inv-1",
-		OutputCase:   "Success",
-		Result:       ir.IRObject{},
-		Seq:          2,
+		ID:              "comp-1",
+		InvocationID:    "inv-1",
+		OutputCase:      "Success",
+		Result:          ir.IRObject{},
+		Seq:             2,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, st.WriteCompletion(ctx, comp))
 	st.Close()
@@ -154,27 +161,29 @@ func TestReplaySpecificFlow(t *testing.T) {
 
 	// Flow 1
 	inv1 := ir.Invocation{
-		ID:            "inv-1",
-		FlowToken:     "flow-1",
-		ActionURI:     "Test.action",
-		Args:          ir.IRObject{},
-		Seq:           1,
-		SpecHash:      "test-hash",
-		EngineVersion: "test",
-		IRVersion:     ir.IRVersion,
+		ID:              "inv-1",
+		FlowToken:       "flow-1",
+		ActionURI:       "Test.action",
+		Args:            ir.IRObject{},
+		Seq:             1,
+		SpecHash:        "test-hash",
+		EngineVersion:   "test",
+		IRVersion:       ir.IRVersion,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, st.WriteInvocation(ctx, inv1))
 
 	// Flow 2
 	inv2 := ir.Invocation{
-		ID:            "inv-2",
-		FlowToken:     "flow-2",
-		ActionURI:     "Test.action",
-		Args:          ir.IRObject{},
-		Seq:           2,
-		SpecHash:      "test-hash",
-		EngineVersion: "test",
-		IRVersion:     ir.IRVersion,
+		ID:              "inv-2",
+		FlowToken:       "flow-2",
+		ActionURI:       "Test.action",
+		Args:            ir.IRObject{},
+		Seq:             2,
+		SpecHash:        "test-hash",
+		EngineVersion:   "test",
+		IRVersion:       ir.IRVersion,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, st.WriteInvocation(ctx, inv2))
 	st.Close()
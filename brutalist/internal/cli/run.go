@@ -10,6 +10,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/roach88/nysm/internal/compiler"
 	"github.com/roach88/nysm/internal/engine"
 	"github.com/roach88/nysm/internal/ir"
 	"github.com/roach88/nysm/internal/store"
@@ -92,7 +93,8 @@ func runEngine(opts *RunOptions, specsDir string, cmd *cobra.Command) error {
 	if flowGen == nil {
 		flowGen = engine.UUIDv7Generator{}
 	}
-	eng := engine.New(st, specs, syncs, flowGen)
+	// Record the spec hash on generated invocations for versioning
+	eng := engine.New(st, specs, syncs, flowGen, engine.WithSpecHash(compiler.SpecHash(specs, syncs)))
 
 	// Setup signal handling for graceful shutdown
 	// Use command's context if available (for testing), otherwise create one
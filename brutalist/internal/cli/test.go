@@ -122,7 +122,7 @@ func runTests(opts *TestOptions, specsDir, scenariosDir string, cmd *cobra.Comma
 	return outputTestText(cmd, result)
 }
 
-// findScenarioFiles finds all YAML scenario files in a directory.
+// findScenarioFiles finds all YAML and JSON scenario files in a directory.
 func findScenarioFiles(dir string, filter string) ([]string, error) {
 	var files []string
 
@@ -135,9 +135,9 @@ func findScenarioFiles(dir string, filter string) ([]string, error) {
 			return nil
 		}
 
-		// Only process .yaml and .yml files
+		// Only process .yaml, .yml, and .json files
 		ext := filepath.Ext(path)
-		if ext != ".yaml" && ext != ".yml" {
+		if ext != ".yaml" && ext != ".yml" && ext != ".json" {
 			return nil
 		}
 
@@ -129,6 +129,18 @@ func TestFindScenarioFiles(t *testing.T) {
 	assert.Len(t, files, 2)
 }
 
+func TestFindScenarioFilesJSON(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "test1.yaml"), []byte(""), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "generated.json"), []byte(""), 0644))
+
+	files, err := findScenarioFiles(tmpDir, "")
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+	assert.Contains(t, files, filepath.Join(tmpDir, "generated.json"))
+}
+
 func TestFindScenarioFilesWithFilter(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -93,23 +93,25 @@ func TestTraceWithFlow(t *testing.T) {
 
 	// Write an invocation and completion
 	inv := ir.Invocation{
-		ID:            "inv-1",
-		FlowToken:     "test-flow-1",
-		ActionURI:     "Cart.addItem",
-		Args:          ir.IRObject{"item": ir.IRString("widget")},
-		Seq:           1,
-		SpecHash:      "test-hash",
-		EngineVersion: "test",
-		IRVersion:     ir.IRVersion,
+		ID:              "inv-1",
+		FlowToken:       "test-flow-1",
+		ActionURI:       "Cart.addItem",
+		Args:            ir.IRObject{"item": ir.IRString("widget")},
+		Seq:             1,
+		SpecHash:        "test-hash",
+		EngineVersion:   "test",
+		IRVersion:       ir.IRVersion,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, st.WriteInvocation(ctx, inv))
 
 	comp := ir.Completion{
-		ID:           "comp-1",
-		InvocationID: "inv-1",
-		OutputCase:   "Success",
-		Result:       ir.IRObject{"count": ir.IRInt(1)},
-		Seq:          2,
+		ID:              "comp-1",
+		InvocationID:    "inv-1",
+		OutputCase:      "Success",
+		Result:          ir.IRObject{"count": ir.IRInt(1)},
+		Seq:             2,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, st.WriteCompletion(ctx, comp))
 	st.Close()
@@ -142,23 +144,25 @@ func TestTraceWithFlowJSON(t *testing.T) {
 
 	// Write an invocation and completion
 	inv := ir.Invocation{
-		ID:            "inv-1",
-		FlowToken:     "test-flow-1",
-		ActionURI:     "Cart.addItem",
-		Args:          ir.IRObject{},
-		Seq:           1,
-		SpecHash:      "test-hash",
-		EngineVersion: "test",
-		IRVersion:     ir.IRVersion,
+		ID:              "inv-1",
+		FlowToken:       "test-flow-1",
+		ActionURI:       "Cart.addItem",
+		Args:            ir.IRObject{},
+		Seq:             1,
+		SpecHash:        "test-hash",
+		EngineVersion:   "test",
+		IRVersion:       ir.IRVersion,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, st.WriteInvocation(ctx, inv))
 
 	comp := ir.Completion{
-		ID:           "comp-1",
-		InvocationID: "inv-1",
-		OutputCase:   "Success",
-		Result:       ir.IRObject{},
-		Seq:          2,
+		ID:              "comp-1",
+		InvocationID:    "inv-1",
+		OutputCase:      "Success",
+		Result:          ir.IRObject{},
+		Seq:             2,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, st.WriteCompletion(ctx, comp))
 	st.Close()
@@ -188,26 +192,28 @@ func TestTraceWithActionFilter(t *testing.T) {
 
 	// Write two invocations with different actions
 	inv1 := ir.Invocation{
-		ID:            "inv-1",
-		FlowToken:     "test-flow-1",
-		ActionURI:     "Cart.addItem",
-		Args:          ir.IRObject{},
-		Seq:           1,
-		SpecHash:      "test-hash",
-		EngineVersion: "test",
-		IRVersion:     ir.IRVersion,
+		ID:              "inv-1",
+		FlowToken:       "test-flow-1",
+		ActionURI:       "Cart.addItem",
+		Args:            ir.IRObject{},
+		Seq:             1,
+		SpecHash:        "test-hash",
+		EngineVersion:   "test",
+		IRVersion:       ir.IRVersion,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, st.WriteInvocation(ctx, inv1))
 
 	inv2 := ir.Invocation{
-		ID:            "inv-2",
-		FlowToken:     "test-flow-1",
-		ActionURI:     "Inventory.check",
-		Args:          ir.IRObject{},
-		Seq:           2,
-		SpecHash:      "test-hash",
-		EngineVersion: "test",
-		IRVersion:     ir.IRVersion,
+		ID:              "inv-2",
+		FlowToken:       "test-flow-1",
+		ActionURI:       "Inventory.check",
+		Args:            ir.IRObject{},
+		Seq:             2,
+		SpecHash:        "test-hash",
+		EngineVersion:   "test",
+		IRVersion:       ir.IRVersion,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, st.WriteInvocation(ctx, inv2))
 	st.Close()
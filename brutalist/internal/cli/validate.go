@@ -11,12 +11,14 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/roach88/nysm/internal/compiler"
+	"github.com/roach88/nysm/internal/ir"
 )
 
 // ValidationResult holds validation results.
 type ValidationResult struct {
-	Valid  bool                        `json:"valid"`
-	Errors []compiler.ValidationError `json:"errors,omitempty"`
+	Valid    bool                       `json:"valid"`
+	Errors   []compiler.ValidationError `json:"errors,omitempty"`
+	Warnings []compiler.ValidationError `json:"warnings,omitempty"`
 }
 
 // NewValidateCommand creates the validate command.
@@ -77,18 +79,35 @@ func runValidate(opts *RootOptions, specsDir string, cmd *cobra.Command) error {
 		}
 	}
 
-	if len(validationErrors) > 0 {
-		return outputValidationErrors(formatter, validationErrors)
+	// Warnings (e.g. W120) are reported but do not fail validation
+	errs, warnings := splitWarnings(validationErrors)
+	if len(errs) > 0 {
+		return outputValidationErrors(formatter, errs, warnings)
 	}
 
 	// Output success
-	return outputValidateSuccess(formatter)
+	return outputValidateSuccess(formatter, warnings)
+}
+
+// splitWarnings separates error-severity findings from warnings,
+// preserving order within each.
+func splitWarnings(findings []compiler.ValidationError) (errs, warnings []compiler.ValidationError) {
+	for _, f := range findings {
+		if f.IsWarning() {
+			warnings = append(warnings, f)
+		} else {
+			errs = append(errs, f)
+		}
+	}
+	return errs, warnings
 }
 
 // validateAll validates all concepts and syncs in the CUE value.
 // Uses fast validation path - parses and validates without generating IR.
 func validateAll(value cue.Value, formatter *OutputFormatter) []compiler.ValidationError {
 	var allErrors []compiler.ValidationError
+	var specs []ir.ConceptSpec // Compiled concepts, for cross-referencing syncs
+	var rules []ir.SyncRule    // Compiled syncs, validated together below
 
 	// Validate concepts
 	conceptsVal := value.LookupPath(cue.ParsePath("concept"))
@@ -123,6 +142,7 @@ func validateAll(value cue.Value, formatter *OutputFormatter) []compiler.Validat
 				// Run schema validation on compiled spec
 				validationErrs := compiler.Validate(spec)
 				allErrors = append(allErrors, validationErrs...)
+				specs = append(specs, *spec)
 			}
 		}
 	}
@@ -157,13 +177,17 @@ func validateAll(value cue.Value, formatter *OutputFormatter) []compiler.Validat
 					continue
 				}
 
-				// Run schema validation on compiled rule
-				validationErrs := compiler.Validate(rule)
-				allErrors = append(allErrors, validationErrs...)
+				rules = append(rules, *rule)
 			}
 		}
 	}
 
+	// Run schema validation on the compiled rules as a set, so set-level
+	// warnings (W110) are reported along with per-rule findings
+	if len(rules) > 0 {
+		allErrors = append(allErrors, compiler.Validate(rules, specs...)...)
+	}
+
 	// Check if we found anything
 	conceptsVal = value.LookupPath(cue.ParsePath("concept"))
 	syncsVal = value.LookupPath(cue.ParsePath("sync"))
@@ -235,16 +259,35 @@ func getLineFromTokenPos(pos interface{ IsValid() bool; Line() int }) int {
 }
 
 // outputValidateSuccess outputs successful validation results.
-func outputValidateSuccess(formatter *OutputFormatter) error {
+func outputValidateSuccess(formatter *OutputFormatter, warnings []compiler.ValidationError) error {
 	if formatter.Format == "json" {
-		result := ValidationResult{Valid: true}
+		result := ValidationResult{Valid: true, Warnings: warnings}
 		return formatter.Success(result)
 	}
 
 	fmt.Fprintln(formatter.Writer, "✓ All specs valid")
+	outputValidationWarnings(formatter, warnings)
 	return nil
 }
 
+// outputValidationWarnings prints warnings in text format.
+func outputValidationWarnings(formatter *OutputFormatter, warnings []compiler.ValidationError) {
+	if len(warnings) == 0 {
+		return
+	}
+
+	fmt.Fprintln(formatter.Writer)
+	fmt.Fprintf(formatter.Writer, "⚠ %d warning(s)\n", len(warnings))
+	fmt.Fprintln(formatter.Writer)
+
+	for _, w := range warnings {
+		if w.Line > 0 {
+			fmt.Fprintf(formatter.Writer, "line %d\n", w.Line)
+		}
+		fmt.Fprintf(formatter.Writer, "  %s: %s\n\n", w.Code, w.Message)
+	}
+}
+
 // outputValidateError outputs a single validation error.
 func outputValidateError(formatter *OutputFormatter, code, message string, details interface{}) error {
 	_ = formatter.Error(code, message, details)
@@ -252,12 +295,14 @@ func outputValidateError(formatter *OutputFormatter, code, message string, detai
 	return NewExitError(ExitCommandError, fmt.Sprintf("%s: %s", code, message))
 }
 
-// outputValidationErrors outputs multiple validation errors.
-func outputValidationErrors(formatter *OutputFormatter, errs []compiler.ValidationError) error {
+// outputValidationErrors outputs multiple validation errors, followed by
+// any warnings.
+func outputValidationErrors(formatter *OutputFormatter, errs, warnings []compiler.ValidationError) error {
 	if formatter.Format == "json" {
 		result := ValidationResult{
-			Valid:  false,
-			Errors: errs,
+			Valid:    false,
+			Errors:   errs,
+			Warnings: warnings,
 		}
 
 		response := CLIResponse{
@@ -290,12 +335,16 @@ func outputValidationErrors(formatter *OutputFormatter, errs []compiler.Validati
 		fmt.Fprintf(formatter.Writer, "  %s: %s\n\n", err.Code, err.Message)
 	}
 
+	outputValidationWarnings(formatter, warnings)
+
 	// Validation failures = exit code 1 (test/validation failure)
 	return NewExitError(ExitFailure, fmt.Sprintf("validation failed with %d error(s)", len(errs)))
 }
 
 // ValidateSpecsDir validates all specs in a directory.
-// This is a helper function for external callers.
+// This is a helper function for external callers. The findings include
+// warnings; use ValidationError.IsWarning or compiler.HasErrors to tell
+// them apart.
 func ValidateSpecsDir(specsDir string) ([]compiler.ValidationError, error) {
 	// Use shared loader
 	loadResult, loadErrors := LoadSpecs(specsDir, LoadModeFailFast)
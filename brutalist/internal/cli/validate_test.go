@@ -208,6 +208,152 @@ sync: "test-sync": {
 	assert.Contains(t, output, "✓ All specs valid")
 }
 
+// warningOnlySpec has no errors, but its second sync has the same when
+// clause as the first, so it fires on exactly the same events (W110).
+const warningOnlySpec = `
+package test
+
+concept: Order: {
+	purpose: "Manages orders"
+
+	action: place: {
+		args: order_id: string
+		outputs: [{ case: "Success", fields: order_id: string }]
+	}
+
+	action: notify: {
+		args: order_id: string
+		outputs: [{ case: "Success", fields: {} }]
+	}
+}
+
+sync: "notify-placed": {
+	scope: "flow"
+	when: {
+		action: "Order.place"
+		event:  "completed"
+		bind: order_id: "result.order_id"
+	}
+	then: {
+		action: "Order.notify"
+		args: order_id: "bound.order_id"
+	}
+}
+
+sync: "notify-placed-again": {
+	scope: "flow"
+	when: {
+		action: "Order.place"
+		event:  "completed"
+		bind: order_id: "result.order_id"
+	}
+	then: {
+		action: "Order.notify"
+		args: order_id: "bound.order_id"
+	}
+}
+`
+
+func TestValidateWarningsOnly(t *testing.T) {
+	tmpDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(tmpDir, "order.cue"), []byte(warningOnlySpec), 0644)
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	rootOpts := &RootOptions{Format: "text"}
+	cmd := NewValidateCommand(rootOpts)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{tmpDir})
+
+	err = cmd.Execute()
+	require.NoError(t, err, "warnings must not fail validation")
+
+	output := buf.String()
+	assert.Contains(t, output, "✓ All specs valid")
+	assert.NotContains(t, output, "Validation failed")
+	assert.Contains(t, output, "1 warning(s)")
+	assert.Contains(t, output, "W110")
+}
+
+func TestValidateWarningsOnlyJSON(t *testing.T) {
+	tmpDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(tmpDir, "order.cue"), []byte(warningOnlySpec), 0644)
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	rootOpts := &RootOptions{Format: "json"}
+	cmd := NewValidateCommand(rootOpts)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{tmpDir})
+
+	err = cmd.Execute()
+	require.NoError(t, err)
+
+	var resp struct {
+		Status string           `json:"status"`
+		Data   ValidationResult `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &resp))
+	assert.Equal(t, "ok", resp.Status)
+	assert.True(t, resp.Data.Valid)
+	assert.Empty(t, resp.Data.Errors)
+	require.Len(t, resp.Data.Warnings, 1)
+	assert.Equal(t, "W110", resp.Data.Warnings[0].Code)
+}
+
+func TestValidateErrorsWithWarnings(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// E103 on the concept, plus W120 on the sync waiting for its completion
+	spec := `
+package test
+
+concept: Order: {
+	purpose: "Manages orders"
+
+	action: archive: {
+		args: order_id: string
+		outputs: []
+	}
+
+	action: notify: {
+		args: order_id: string
+		outputs: [{ case: "Success", fields: {} }]
+	}
+}
+
+sync: "notify-archived": {
+	scope: "flow"
+	when: {
+		action: "Order.archive"
+		event:  "completed"
+		bind: order_id: "args.order_id"
+	}
+	then: {
+		action: "Order.notify"
+		args: order_id: "bound.order_id"
+	}
+}
+`
+	err := os.WriteFile(filepath.Join(tmpDir, "order.cue"), []byte(spec), 0644)
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	rootOpts := &RootOptions{Format: "text"}
+	cmd := NewValidateCommand(rootOpts)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{tmpDir})
+
+	err = cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "validation failed with 1 error(s)", "warnings are not counted as errors")
+
+	output := buf.String()
+	assert.Contains(t, output, "E103")
+	assert.Contains(t, output, "1 warning(s)")
+	assert.Contains(t, output, "W120")
+}
+
 func TestValidateVerboseOutput(t *testing.T) {
 	tmpDir := t.TempDir()
 
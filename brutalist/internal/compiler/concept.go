@@ -2,11 +2,13 @@ package compiler
 
 import (
 	"fmt"
+	"slices"
 
 	"cuelang.org/go/cue"
 	"cuelang.org/go/cue/errors"
 	"cuelang.org/go/cue/token"
 
+	"github.com/roach88/nysm/internal/harness"
 	"github.com/roach88/nysm/internal/ir"
 )
 
@@ -18,7 +20,14 @@ import (
 //	ctx := cuecontext.New()
 //	v := ctx.CompileString(`concept Cart { ... }`)
 //	spec, err := CompileConcept(v.LookupPath(cue.ParsePath("concept.Cart")))
-func CompileConcept(v cue.Value) (*ir.ConceptSpec, error) {
+//
+// Options can be passed to enable additional checks (e.g., WithScenarioResolver).
+func CompileConcept(v cue.Value, opts ...CompileOption) (*ir.ConceptSpec, error) {
+	var o compileOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	if err := v.Err(); err != nil {
 		return nil, formatCUEError(err)
 	}
@@ -85,9 +94,58 @@ func CompileConcept(v cue.Value) (*ir.ConceptSpec, error) {
 		spec.OperationalPrinciples = append(spec.OperationalPrinciples, principles...)
 	}
 
+	if o.resolveScenarios {
+		if err := resolveScenarios(spec, v.Pos(), o.scenarioBasePath); err != nil {
+			return nil, err
+		}
+	}
+
 	return spec, nil
 }
 
+// CompileOption configures optional compile-time checks.
+type CompileOption func(*compileOptions)
+
+type compileOptions struct {
+	resolveScenarios bool
+	scenarioBasePath string
+}
+
+// WithScenarioResolver verifies that every operational principle scenario
+// exists and loads via harness.LoadScenarioWithBasePath. Relative scenario
+// paths, and the spec paths inside each scenario, are resolved against
+// basePath.
+func WithScenarioResolver(basePath string) CompileOption {
+	return func(o *compileOptions) {
+		o.resolveScenarios = true
+		o.scenarioBasePath = basePath
+	}
+}
+
+// resolveScenarios loads each scenario referenced by the concept's
+// operational principles, reporting the first failure with the concept and
+// principle index.
+func resolveScenarios(spec *ir.ConceptSpec, pos token.Pos, basePath string) error {
+	for i, principle := range spec.OperationalPrinciples {
+		field := fmt.Sprintf("concept.%s.operational_principles[%d].scenario", spec.Name, i)
+
+		paths, err := harness.ExtractScenarios(principle, basePath)
+		if err != nil {
+			return &CompileError{Field: field, Message: err.Error(), Pos: pos}
+		}
+		for _, path := range paths {
+			if _, err := harness.LoadScenarioWithBasePath(path, basePath); err != nil {
+				return &CompileError{
+					Field:   field,
+					Message: fmt.Sprintf("scenario %q: %v", principle.Scenario, err),
+					Pos:     pos,
+				}
+			}
+		}
+	}
+	return nil
+}
+
 // parseOperationalPrinciples parses operational principles from a CUE value.
 // Supports:
 // - Single string: "description text"
@@ -205,6 +263,13 @@ func parseStates(v cue.Value) ([]ir.StateSchema, error) {
 				return nil, err
 			}
 			state.Fields[fieldName] = fieldType
+
+			if values, ok := extractEnum(fieldIter.Value()); ok {
+				if state.Enums == nil {
+					state.Enums = make(map[string][]string)
+				}
+				state.Enums[fieldName] = values
+			}
 		}
 
 		states = append(states, state)
@@ -254,6 +319,13 @@ func parseActions(v cue.Value) ([]ir.ActionSig, error) {
 					Name: argName,
 					Type: argType,
 				})
+
+				if values, ok := extractEnum(argsIter.Value()); ok {
+					if action.Enums == nil {
+						action.Enums = make(map[string][]string)
+					}
+					action.Enums[argName] = values
+				}
 			}
 		}
 
@@ -356,6 +428,52 @@ func extractTypeName(v cue.Value) (string, error) {
 	}
 }
 
+// extractEnum returns the allowed values of a field declared as a
+// disjunction of string literals, e.g. status: "pending" | "shipped".
+// Values are in declaration order without duplicates; a default marker
+// (*"pending") is ignored. ok is false for any other field, including a
+// disjunction with a non-literal branch such as "pending" | string.
+func extractEnum(v cue.Value) (values []string, ok bool) {
+	if v.IncompleteKind() != cue.StringKind {
+		return nil, false
+	}
+	v = cue.Dereference(v) // status: #Status
+	op, _ := v.Expr()
+	if op != cue.OrOp {
+		return nil, false
+	}
+	if !collectEnumValues(v, &values) {
+		return nil, false
+	}
+	return values, true
+}
+
+// collectEnumValues appends the string literals of a (possibly nested or
+// referenced) disjunction to values, reporting false on any other branch.
+func collectEnumValues(v cue.Value, values *[]string) bool {
+	op, args := cue.Dereference(v).Expr()
+	switch op {
+	case cue.OrOp:
+		for _, arg := range args {
+			if !collectEnumValues(arg, values) {
+				return false
+			}
+		}
+		return true
+	case cue.NoOp:
+		s, err := v.String()
+		if err != nil {
+			return false
+		}
+		if !slices.Contains(*values, s) {
+			*values = append(*values, s)
+		}
+		return true
+	default:
+		return false
+	}
+}
+
 // CompileError represents a compilation error with source position.
 type CompileError struct {
 	Field   string
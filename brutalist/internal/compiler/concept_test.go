@@ -7,6 +7,8 @@ import (
 	"cuelang.org/go/cue/cuecontext"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/roach88/nysm/internal/ir"
 )
 
 func TestCompileConceptBasic(t *testing.T) {
@@ -388,6 +390,75 @@ func TestCompileConceptAllTypes(t *testing.T) {
 	assert.Equal(t, "object", state.Fields["object_field"])
 }
 
+func TestCompileConceptEnumFields(t *testing.T) {
+	ctx := cuecontext.New()
+	v := ctx.CompileString(`
+		#Carrier: "ups" | "fedex"
+
+		concept: Order: {
+			purpose: "Tracks orders"
+
+			state: Order: {
+				id: string
+				status: "pending" | "shipped" | "pending"
+				note: string
+				code: "x"
+				ref: "a" | string
+			}
+
+			action: ship: {
+				args: {
+					order_id: string
+					status: *"shipped" | "pending"
+					carrier: #Carrier
+					speed: ("standard" | "express") | "overnight"
+				}
+				outputs: [{ case: "Success", fields: {} }]
+			}
+		}
+	`)
+
+	require.NoError(t, v.Err())
+	spec, err := CompileConcept(v.LookupPath(cue.ParsePath("concept.Order")))
+	require.NoError(t, err)
+
+	// Enum fields keep the string type; values are in declaration order, deduplicated
+	state := spec.StateSchema[0]
+	assert.Equal(t, "string", state.Fields["status"])
+	assert.Equal(t, map[string][]string{"status": {"pending", "shipped"}}, state.Enums,
+		"single literals and disjunctions with a non-literal branch are not enums")
+
+	action := spec.Actions[0]
+	assert.Equal(t, map[string][]string{
+		"status":  {"shipped", "pending"},
+		"carrier": {"ups", "fedex"},
+		"speed":   {"standard", "express", "overnight"},
+	}, action.Enums)
+
+	assert.Empty(t, Validate(spec))
+}
+
+func TestCompileConceptNoEnums(t *testing.T) {
+	ctx := cuecontext.New()
+	v := ctx.CompileString(`
+		concept: Cart: {
+			purpose: "Manages shopping cart"
+			state: CartItem: { item_id: string }
+			action: addItem: {
+				args: { item_id: string }
+				outputs: [{ case: "Success", fields: {} }]
+			}
+		}
+	`)
+
+	require.NoError(t, v.Err())
+	spec, err := CompileConcept(v.LookupPath(cue.ParsePath("concept.Cart")))
+	require.NoError(t, err)
+
+	assert.Nil(t, spec.StateSchema[0].Enums)
+	assert.Nil(t, spec.Actions[0].Enums)
+}
+
 func TestCompileConceptMultipleOperationalPrinciples(t *testing.T) {
 	ctx := cuecontext.New()
 	v := ctx.CompileString(`
@@ -725,3 +796,56 @@ func TestCompileConceptStructuredPrincipleNoScenario(t *testing.T) {
 	assert.Equal(t, "Description without scenario", spec.OperationalPrinciples[0].Description)
 	assert.Equal(t, "", spec.OperationalPrinciples[0].Scenario)
 }
+
+func compileConceptWithScenario(t *testing.T, scenario string, opts ...CompileOption) (*ir.ConceptSpec, error) {
+	t.Helper()
+	ctx := cuecontext.New()
+	v := ctx.CompileString(`
+		concept: Cart: {
+			purpose: "Manages shopping cart"
+			action: checkout: outputs: [{case: "Success"}]
+			operational_principles: [
+				"Checkout clears the cart",
+				{description: "Checkout reserves inventory", scenario: "` + scenario + `"},
+			]
+		}
+	`)
+	require.NoError(t, v.Err())
+	return CompileConcept(v.LookupPath(cue.ParsePath("concept.Cart")), opts...)
+}
+
+func TestCompileConceptScenarioResolverPresent(t *testing.T) {
+	// Scenario spec paths are relative to the module root
+	spec, err := compileConceptWithScenario(t, "testdata/scenarios/cart_checkout_success.yaml",
+		WithScenarioResolver("../.."))
+	require.NoError(t, err)
+	require.Len(t, spec.OperationalPrinciples, 2)
+}
+
+func TestCompileConceptScenarioResolverDangling(t *testing.T) {
+	_, err := compileConceptWithScenario(t, "testdata/scenarios/missing.yaml",
+		WithScenarioResolver("../.."))
+	require.Error(t, err)
+
+	var compileErr *CompileError
+	require.ErrorAs(t, err, &compileErr)
+	assert.Equal(t, "concept.Cart.operational_principles[1].scenario", compileErr.Field)
+	assert.Contains(t, compileErr.Message, "missing.yaml")
+}
+
+func TestCompileConceptScenarioResolverUnparseable(t *testing.T) {
+	dir := t.TempDir()
+	writeSpecFile(t, dir, "broken.yaml", "name: broken\n")
+
+	_, err := compileConceptWithScenario(t, "broken.yaml", WithScenarioResolver(dir))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "operational_principles[1]")
+	assert.Contains(t, err.Error(), "invalid scenario")
+}
+
+func TestCompileConceptWithoutScenarioResolver(t *testing.T) {
+	// Scenario paths are not checked unless requested
+	spec, err := compileConceptWithScenario(t, "testdata/scenarios/missing.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "testdata/scenarios/missing.yaml", spec.OperationalPrinciples[1].Scenario)
+}
@@ -0,0 +1,310 @@
+package compiler
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/cuecontext"
+	"cuelang.org/go/cue/parser"
+	"cuelang.org/go/cue/token"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+// CompileFile compiles every concept.* and sync.* definition in a single CUE
+// file and validates the results.
+//
+// Sync rules are cross-referenced only against concepts in the same file; use
+// CompileDir when syncs reference concepts declared elsewhere.
+//
+// All compile and validation errors are collected and returned joined (see
+// errors.Join), each as a *CompileError positioned in the file. Definitions
+// that compiled and validated cleanly are returned even when err is non-nil.
+// Options are passed to CompileConcept.
+func CompileFile(path string, opts ...CompileOption) ([]ir.ConceptSpec, []ir.SyncRule, error) {
+	return compileFiles([]string{path}, opts)
+}
+
+// CompileDir compiles every .cue file under dir (recursively, in lexical
+// order) and validates the aggregated concepts and syncs.
+//
+// Each file is compiled independently together with the files it imports
+// (see specLoader). Sync rules are cross-referenced against the concepts of
+// all files. Errors are reported as for CompileFile.
+func CompileDir(dir string, opts ...CompileOption) ([]ir.ConceptSpec, []ir.SyncRule, error) {
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && filepath.Ext(path) == ".cue" {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("compile dir: %w", err)
+	}
+	if len(paths) == 0 {
+		return nil, nil, fmt.Errorf("compile dir: no CUE files found in %s", dir)
+	}
+
+	return compileFiles(paths, opts)
+}
+
+// compiledDef pairs a compiled definition with its CUE label and position so
+// validation errors can be reported against the source.
+type compiledDef struct {
+	label string
+	pos   token.Pos
+}
+
+// compileFiles compiles the given files in order, then validates the results.
+func compileFiles(paths []string, opts []CompileOption) ([]ir.ConceptSpec, []ir.SyncRule, error) {
+	ctx := cuecontext.New()
+
+	var (
+		errs     []error
+		specs    []ir.ConceptSpec
+		specDefs []compiledDef
+		rules    []ir.SyncRule
+		ruleDefs []compiledDef
+	)
+
+	loader := &specLoader{files: make(map[string]*ast.File)}
+
+	for _, path := range paths {
+		path = filepath.Clean(path)
+
+		file, err := loader.resolve(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		value := ctx.BuildFile(file)
+		if err := value.Err(); err != nil {
+			errs = append(errs, formatCUEError(err))
+			continue
+		}
+
+		// Imported files contribute shared definitions to this file's value,
+		// but their own concepts and syncs are compiled from their own entry
+		declaredHere := func(v cue.Value) bool {
+			return v.Pos().Filename() == path
+		}
+
+		forEachField(value, "concept", &errs, func(label string, v cue.Value) error {
+			if !declaredHere(v) {
+				return nil
+			}
+			spec, err := CompileConcept(v, opts...)
+			if err != nil {
+				return err
+			}
+			specs = append(specs, *spec)
+			specDefs = append(specDefs, compiledDef{label: "concept." + label, pos: v.Pos()})
+			return nil
+		})
+
+		forEachField(value, "sync", &errs, func(label string, v cue.Value) error {
+			if !declaredHere(v) {
+				return nil
+			}
+			rule, err := CompileSync(v)
+			if err != nil {
+				return err
+			}
+			rules = append(rules, *rule)
+			ruleDefs = append(ruleDefs, compiledDef{label: "sync." + label, pos: v.Pos()})
+			return nil
+		})
+	}
+
+	// Validate after compiling every file so syncs see all concepts
+	validSpecs := make([]ir.ConceptSpec, 0, len(specs))
+	for i := range specs {
+		verrs := validationErrors(specDefs[i], Validate(&specs[i]))
+		if len(verrs) > 0 {
+			errs = append(errs, verrs...)
+			continue
+		}
+		validSpecs = append(validSpecs, specs[i])
+	}
+
+	validRules := make([]ir.SyncRule, 0, len(rules))
+	for i := range rules {
+		verrs := validationErrors(ruleDefs[i], Validate(&rules[i], specs...))
+		if len(verrs) > 0 {
+			errs = append(errs, verrs...)
+			continue
+		}
+		validRules = append(validRules, rules[i])
+	}
+
+	return validSpecs, validRules, errors.Join(errs...)
+}
+
+// forEachField calls fn for each field under the given top-level path.
+// Errors are collected into errs so one bad definition does not hide the rest.
+func forEachField(value cue.Value, path string, errs *[]error, fn func(label string, v cue.Value) error) {
+	val := value.LookupPath(cue.ParsePath(path))
+	if !val.Exists() {
+		return
+	}
+
+	iter, err := val.Fields()
+	if err != nil {
+		*errs = append(*errs, formatCUEError(err))
+		return
+	}
+	for iter.Next() {
+		if err := fn(iter.Label(), iter.Value()); err != nil {
+			*errs = append(*errs, err)
+		}
+	}
+}
+
+// validationErrors converts validation errors for a definition into
+// positioned CompileErrors. Warnings are dropped.
+func validationErrors(def compiledDef, verrs []ValidationError) []error {
+	var errs []error
+	for _, ve := range verrs {
+		if ve.IsWarning() {
+			continue
+		}
+		errs = append(errs, &CompileError{
+			Field:   def.label + "." + ve.Field,
+			Message: fmt.Sprintf("[%s] %s", ve.Code, ve.Message),
+			Pos:     def.pos,
+		})
+	}
+	return errs
+}
+
+// importAttr is the file-level attribute a spec file uses to depend on
+// another spec file, e.g. @import("./shared.cue"). Paths are relative to the
+// importing file.
+const importAttr = "import"
+
+// specLoader parses spec files and resolves their @import dependencies.
+// Parsed files are cached so shared imports are read once per compilation.
+type specLoader struct {
+	files map[string]*ast.File
+}
+
+// resolve returns the file at path merged with all of its transitive imports
+// into a single file, so shared definitions resolve and their constraints
+// unify with the importing file's. Declarations keep their original
+// positions. Import cycles are reported with the full import chain.
+func (l *specLoader) resolve(path string) (*ast.File, error) {
+	var (
+		imports []ast.Decl // CUE package imports must precede other declarations
+		decls   []ast.Decl
+		visited = make(map[string]bool)
+	)
+
+	var visit func(path string, chain []string, from *ast.Attribute) error
+	visit = func(path string, chain []string, from *ast.Attribute) error {
+		if i := slices.Index(chain, path); i >= 0 {
+			cycle := slices.Concat(chain[i:], []string{path})
+			return &CompileError{
+				Field:   "import",
+				Message: fmt.Sprintf("import cycle: %s", strings.Join(cycle, " -> ")),
+				Pos:     from.Pos(),
+			}
+		}
+		if visited[path] {
+			return nil // Diamond import, already merged
+		}
+		visited[path] = true
+
+		file, err := l.parse(path)
+		if err != nil {
+			if from != nil {
+				return &CompileError{Field: "import", Message: err.Error(), Pos: from.Pos()}
+			}
+			return err
+		}
+
+		chain = append(chain, path)
+		for _, decl := range file.Decls {
+			attr, ok := decl.(*ast.Attribute)
+			if !ok {
+				continue
+			}
+			rel, ok, err := parseImportAttr(attr)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+			if err := visit(filepath.Join(filepath.Dir(path), rel), chain, attr); err != nil {
+				return err
+			}
+		}
+
+		for _, decl := range file.Decls {
+			switch decl.(type) {
+			case *ast.Package, *ast.Attribute:
+			case *ast.ImportDecl:
+				imports = append(imports, decl)
+			default:
+				decls = append(decls, decl)
+			}
+		}
+		return nil
+	}
+
+	if err := visit(path, nil, nil); err != nil {
+		return nil, err
+	}
+
+	return &ast.File{Filename: path, Decls: append(imports, decls...)}, nil
+}
+
+// parse reads and parses a spec file, caching the result.
+func (l *specLoader) parse(path string) (*ast.File, error) {
+	if file, ok := l.files[path]; ok {
+		return file, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	file, err := parser.ParseFile(path, data, parser.ParseComments)
+	if err != nil {
+		return nil, formatCUEError(err)
+	}
+
+	l.files[path] = file
+	return file, nil
+}
+
+// parseImportAttr extracts the relative path from an @import attribute.
+// ok is false for other attributes.
+func parseImportAttr(attr *ast.Attribute) (path string, ok bool, err error) {
+	key, body := attr.Split()
+	if key != importAttr {
+		return "", false, nil
+	}
+	path, err = strconv.Unquote(strings.TrimSpace(body))
+	if err != nil || path == "" {
+		return "", false, &CompileError{
+			Field:   "import",
+			Message: fmt.Sprintf("invalid import %s, expected @import(\"relative/path.cue\")", attr.Text),
+			Pos:     attr.Pos(),
+		}
+	}
+	return filepath.Clean(path), true, nil
+}
@@ -0,0 +1,290 @@
+package compiler
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const cartConceptFile = `package specs
+
+concept: Cart: {
+	purpose: "Manages shopping cart"
+
+	state: CartItem: {
+		cart_id: string
+		item_id: string
+	}
+
+	action: checkout: {
+		args: cart_id: string
+		outputs: [{case: "Success", fields: cart_id: string}]
+	}
+}
+`
+
+const inventoryConceptFile = `package specs
+
+concept: Inventory: {
+	purpose: "Tracks stock levels"
+
+	action: reserve: {
+		args: item_id: string
+		outputs: [{case: "Success"}]
+	}
+}
+`
+
+const reserveSyncFile = `package specs
+
+sync: "reserve-items": {
+	scope: "flow"
+	when: {
+		action: "Cart.checkout"
+		event:  "completed"
+		case:   "Success"
+		bind: cart_id: "result.cart_id"
+	}
+	where: {
+		from:   "CartItem"
+		filter: "cart_id == bound.cart_id"
+		bind: item_id: "item_id"
+	}
+	then: {
+		action: "Inventory.reserve"
+		args: item_id: "bound.item_id"
+	}
+}
+`
+
+func writeSpecFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestCompileDir_ConceptAndSync(t *testing.T) {
+	dir := t.TempDir()
+	writeSpecFile(t, dir, "cart.concept.cue", cartConceptFile)
+	writeSpecFile(t, dir, "inventory.concept.cue", inventoryConceptFile)
+	writeSpecFile(t, dir, "reserve.sync.cue", reserveSyncFile)
+
+	specs, rules, err := CompileDir(dir)
+	require.NoError(t, err)
+
+	require.Len(t, specs, 2)
+	assert.Equal(t, "Cart", specs[0].Name)
+	assert.Equal(t, "Inventory", specs[1].Name)
+	require.Len(t, rules, 1)
+	assert.Equal(t, "reserve-items", rules[0].ID)
+	require.NotNil(t, rules[0].Where)
+	assert.Equal(t, "CartItem", rules[0].Where.Source)
+}
+
+func TestCompileDir_CrossFileValidationError(t *testing.T) {
+	dir := t.TempDir()
+	writeSpecFile(t, dir, "cart.concept.cue", cartConceptFile)
+	syncPath := writeSpecFile(t, dir, "reserve.sync.cue",
+		`package specs
+
+sync: "reserve-items": {
+	scope: "flow"
+	when: {
+		action: "Cart.checkout"
+		event:  "completed"
+		bind: cart_id: "result.cart_id"
+	}
+	where: {
+		from: "CartItems"
+		bind: item_id: "item_id"
+	}
+	then: {
+		action: "Inventory.reserve"
+		args: item_id: "bound.item_id"
+	}
+}
+`)
+
+	specs, rules, err := CompileDir(dir)
+	require.Error(t, err)
+	assert.Len(t, specs, 1, "valid concepts are still returned")
+	assert.Empty(t, rules)
+
+	var compileErr *CompileError
+	require.True(t, errors.As(err, &compileErr))
+	assert.Equal(t, "sync.reserve-items.where.source", compileErr.Field)
+	assert.Contains(t, compileErr.Message, ErrUnknownWhereSource)
+	assert.Equal(t, syncPath, compileErr.Pos.Filename())
+	assert.Equal(t, 3, compileErr.Pos.Line())
+}
+
+func TestCompileDir_WhereFilterUnknownField(t *testing.T) {
+	dir := t.TempDir()
+	writeSpecFile(t, dir, "cart.concept.cue", cartConceptFile)
+	writeSpecFile(t, dir, "inventory.concept.cue", inventoryConceptFile)
+	writeSpecFile(t, dir, "reserve.sync.cue",
+		`package specs
+
+sync: "reserve-items": {
+	scope: "flow"
+	when: {
+		action: "Cart.checkout"
+		event:  "completed"
+		bind: cart_id: "result.cart_id"
+	}
+	where: {
+		from:   "CartItem"
+		filter: "cart == bound.cart_id"
+		bind: item_id: "item_id"
+	}
+	then: {
+		action: "Inventory.reserve"
+		args: item_id: "bound.item_id"
+	}
+}
+`)
+
+	_, rules, err := CompileDir(dir)
+	require.Error(t, err)
+	assert.Empty(t, rules)
+
+	var compileErr *CompileError
+	require.True(t, errors.As(err, &compileErr))
+	assert.Equal(t, "sync.reserve-items.where.filter", compileErr.Field)
+	assert.Contains(t, compileErr.Message, ErrUnknownWhereField)
+	assert.Contains(t, compileErr.Message, `field does not exist in state "CartItem"`)
+}
+
+func TestCompileDir_UnknownThenAction(t *testing.T) {
+	dir := t.TempDir()
+	writeSpecFile(t, dir, "cart.concept.cue", cartConceptFile)
+	writeSpecFile(t, dir, "reserve.sync.cue", reserveSyncFile) // Inventory is not declared
+
+	_, rules, err := CompileDir(dir)
+	require.Error(t, err)
+	assert.Empty(t, rules)
+
+	var compileErr *CompileError
+	require.True(t, errors.As(err, &compileErr))
+	assert.Equal(t, "sync.reserve-items.then.action_ref", compileErr.Field)
+	assert.Contains(t, compileErr.Message, ErrUnknownActionRef)
+	assert.Contains(t, compileErr.Message, `no concept "Inventory"`)
+}
+
+func TestCompileDir_AggregatesErrorsPerFile(t *testing.T) {
+	dir := t.TempDir()
+	badConcept := writeSpecFile(t, dir, "a.concept.cue", `concept: Bad: {
+	action: noop: outputs: [{case: "Success"}]
+}
+`)
+	badSyntax := writeSpecFile(t, dir, "b.concept.cue", "concept: {\n")
+
+	_, _, err := CompileDir(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), badConcept)
+	assert.Contains(t, err.Error(), badSyntax)
+}
+
+func TestCompileDir_NoFiles(t *testing.T) {
+	_, _, err := CompileDir(t.TempDir())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no CUE files")
+}
+
+func TestCompileFile_SingleFile(t *testing.T) {
+	path := writeSpecFile(t, t.TempDir(), "cart.concept.cue", cartConceptFile)
+
+	specs, rules, err := CompileFile(path)
+	require.NoError(t, err)
+	require.Len(t, specs, 1)
+	assert.Equal(t, "Cart", specs[0].Name)
+	assert.Empty(t, rules)
+}
+
+func TestCompileDir_ImportSharedDefinition(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "shared"), 0o755))
+	writeSpecFile(t, dir, "shared/item.cue", `package specs
+
+#Item: {
+	item_id:  string
+	quantity: int
+}
+`)
+	writeSpecFile(t, dir, "cart.concept.cue", `package specs
+
+@import("./shared/item.cue")
+
+concept: Cart: {
+	purpose: "Manages shopping cart"
+	state: CartItem: #Item
+	action: addItem: {
+		args: #Item
+		outputs: [{case: "Success"}]
+	}
+}
+`)
+
+	specs, _, err := CompileDir(dir)
+	require.NoError(t, err)
+	require.Len(t, specs, 1, "imported file is compiled once, not per importer")
+
+	cart := specs[0]
+	require.Len(t, cart.StateSchema, 1)
+	assert.Equal(t, map[string]string{"item_id": "string", "quantity": "int"}, cart.StateSchema[0].Fields)
+	require.Len(t, cart.Actions, 1)
+	assert.Len(t, cart.Actions[0].Args, 2)
+}
+
+func TestCompileFile_ImportedConstraintsUnify(t *testing.T) {
+	dir := t.TempDir()
+	writeSpecFile(t, dir, "rules.cue", `package specs
+
+concept: [_]: purpose: =~"^[A-Z]"
+`)
+	path := writeSpecFile(t, dir, "cart.concept.cue", `package specs
+
+@import("rules.cue")
+
+concept: Cart: {
+	purpose: "manages shopping cart"
+	action: checkout: outputs: [{case: "Success"}]
+}
+`)
+
+	_, _, err := CompileFile(path)
+	require.Error(t, err, "shared constraint rejects lowercase purpose")
+}
+
+func TestCompileDir_ImportCycle(t *testing.T) {
+	dir := t.TempDir()
+	a := writeSpecFile(t, dir, "a.cue", "package specs\n\n@import(\"b.cue\")\n\n#A: string\n")
+	b := writeSpecFile(t, dir, "b.cue", "package specs\n\n@import(\"a.cue\")\n\n#B: string\n")
+
+	_, _, err := CompileDir(dir)
+	require.Error(t, err)
+
+	var compileErr *CompileError
+	require.True(t, errors.As(err, &compileErr))
+	assert.Equal(t, "import", compileErr.Field)
+	assert.Contains(t, compileErr.Message, "import cycle: "+a+" -> "+b+" -> "+a)
+	assert.Equal(t, b, compileErr.Pos.Filename())
+}
+
+func TestCompileFile_MissingImport(t *testing.T) {
+	path := writeSpecFile(t, t.TempDir(), "cart.concept.cue", "package specs\n\n@import(\"missing.cue\")\n")
+
+	_, _, err := CompileFile(path)
+	require.Error(t, err)
+
+	var compileErr *CompileError
+	require.True(t, errors.As(err, &compileErr))
+	assert.Equal(t, path, compileErr.Pos.Filename())
+	assert.Equal(t, 3, compileErr.Pos.Line())
+	assert.Contains(t, compileErr.Message, "missing.cue")
+}
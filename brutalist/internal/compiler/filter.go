@@ -0,0 +1,74 @@
+package compiler
+
+import (
+	"errors"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/token"
+
+	"github.com/roach88/nysm/internal/queryir"
+)
+
+// ParseFilter parses a where-clause filter expression with
+// queryir.ParseFilter (see there for the grammar), reporting errors as
+// *CompileError.
+//
+// pos is the position of the filter string literal in the CUE source (see
+// ParseFilterValue), or token.NoPos for plain strings. Errors have Pos
+// pointing at the offending token when pos is valid; the message always
+// includes the column within the filter string.
+func ParseFilter(filter string, pos token.Pos) (queryir.Predicate, error) {
+	pred, err := queryir.ParseFilter(filter)
+	var ferr *queryir.FilterError
+	if errors.As(err, &ferr) {
+		return nil, &CompileError{
+			Field:   "where.filter",
+			Message: ferr.Error(),
+			Pos:     filterPos(pos, ferr.Offset),
+		}
+	}
+	return pred, err
+}
+
+// ParseFilterValue parses a CUE filter string value (e.g. a where.filter field).
+// Error positions point into the string literal in the CUE source.
+func ParseFilterValue(v cue.Value) (queryir.Predicate, error) {
+	filter, err := v.String()
+	if err != nil {
+		return nil, &CompileError{
+			Field:   "where.filter",
+			Message: "filter must be a string expression",
+			Pos:     v.Pos(),
+		}
+	}
+
+	// v.Pos() is the field label; the literal carries the string's position
+	pos := v.Pos()
+	if field, ok := v.Source().(*ast.Field); ok {
+		if lit, ok := field.Value.(*ast.BasicLit); ok {
+			pos = lit.ValuePos
+		}
+	}
+
+	return ParseFilter(filter, pos)
+}
+
+// filterPos maps a filter offset to a CUE source position.
+// The +1 skips the opening quote of the CUE string literal. Escape
+// sequences in the CUE source can shift the column; the message column
+// is always exact relative to the filter string.
+func filterPos(pos token.Pos, offset int) token.Pos {
+	if !pos.IsValid() {
+		return pos
+	}
+	file := pos.File()
+	if file == nil {
+		return pos
+	}
+	target := pos.Offset() + 1 + offset
+	if target > file.Size() {
+		return pos
+	}
+	return file.Pos(target, token.NoRelPos)
+}
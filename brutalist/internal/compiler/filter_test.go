@@ -0,0 +1,60 @@
+package compiler
+
+import (
+	"testing"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+	"cuelang.org/go/cue/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/roach88/nysm/internal/queryir"
+)
+
+func TestParseFilter_SingleEquality(t *testing.T) {
+	pred, err := ParseFilter("cart_id == bound.cart_id", token.NoPos)
+
+	require.NoError(t, err)
+	assert.Equal(t, queryir.BoundEquals{Field: "cart_id", BoundVar: "bound.cart_id"}, pred)
+}
+
+func TestParseFilter_RejectsOr(t *testing.T) {
+	_, err := ParseFilter(`status == "a" || status == "b"`, token.NoPos)
+
+	require.Error(t, err)
+	var compileErr *CompileError
+	require.ErrorAs(t, err, &compileErr)
+	assert.Equal(t, "where.filter", compileErr.Field)
+	assert.Contains(t, compileErr.Message, "OR")
+	assert.Contains(t, compileErr.Message, "Union")
+	assert.Contains(t, compileErr.Message, "column 15")
+}
+
+func TestParseFilterValue_Position(t *testing.T) {
+	ctx := cuecontext.New()
+	v := ctx.CompileString("where: {\n\tfilter: \"a == 1 || b == 2\"\n}", cue.Filename("rule.cue"))
+	require.NoError(t, v.Err())
+
+	_, err := ParseFilterValue(v.LookupPath(cue.ParsePath("where.filter")))
+	require.Error(t, err)
+
+	var compileErr *CompileError
+	require.ErrorAs(t, err, &compileErr)
+	require.True(t, compileErr.Pos.IsValid())
+	assert.Equal(t, "rule.cue", compileErr.Pos.Filename())
+	assert.Equal(t, 2, compileErr.Pos.Line())
+	// Literal opens at column 10; "||" is at offset 7 inside the string
+	assert.Equal(t, 18, compileErr.Pos.Column())
+}
+
+func TestParseFilterValue_Success(t *testing.T) {
+	ctx := cuecontext.New()
+	v := ctx.CompileString(`filter: "cart_id == bound.cart_id"`)
+	require.NoError(t, v.Err())
+
+	pred, err := ParseFilterValue(v.LookupPath(cue.ParsePath("filter")))
+
+	require.NoError(t, err)
+	assert.Equal(t, queryir.BoundEquals{Field: "cart_id", BoundVar: "bound.cart_id"}, pred)
+}
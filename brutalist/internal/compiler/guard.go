@@ -0,0 +1,82 @@
+package compiler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"cuelang.org/go/cue/token"
+
+	"github.com/roach88/nysm/internal/queryir"
+)
+
+// Guard field prefixes: a guard reads the completion's result or the
+// invocation's args.
+const (
+	guardResultPrefix = "result."
+	guardArgsPrefix   = "args."
+)
+
+// guardFields parses a when-clause guard and returns the fields it
+// references, in source order.
+//
+// Guards use the filter grammar (see ParseFilter) with two restrictions:
+// fields must be prefixed with "result." or "args.", and bound variables are
+// not allowed because guards are evaluated before bindings are extracted.
+func guardFields(guard string) ([]string, error) {
+	pred, err := ParseFilter(guard, token.NoPos)
+	if err != nil {
+		// The guard is validated as a plain string, so drop the
+		// where.filter label and report only the message
+		var ce *CompileError
+		if errors.As(err, &ce) {
+			return nil, errors.New(ce.Message)
+		}
+		return nil, err
+	}
+
+	var fields []string
+	var walk func(queryir.Predicate) error
+	walk = func(pred queryir.Predicate) error {
+		var field, boundVar string
+		switch p := pred.(type) {
+		case queryir.And:
+			for _, sub := range p.Predicates {
+				if err := walk(sub); err != nil {
+					return err
+				}
+			}
+			return nil
+		case queryir.Equals:
+			field = p.Field
+		case queryir.BoundEquals:
+			field, boundVar = p.Field, p.BoundVar
+		case queryir.GreaterThan:
+			field, boundVar = p.Field, p.BoundVar
+		case queryir.LessThan:
+			field, boundVar = p.Field, p.BoundVar
+		case queryir.GreaterOrEqual:
+			field, boundVar = p.Field, p.BoundVar
+		case queryir.LessOrEqual:
+			field, boundVar = p.Field, p.BoundVar
+		default:
+			return fmt.Errorf("unsupported guard predicate %T", pred)
+		}
+
+		if boundVar != "" {
+			return fmt.Errorf("bound variable %q is not available in guards (guards run before bindings are extracted)", boundVar)
+		}
+		if !strings.HasPrefix(field, guardResultPrefix) && !strings.HasPrefix(field, guardArgsPrefix) {
+			return fmt.Errorf("guard field %q must start with %q or %q", field, guardResultPrefix, guardArgsPrefix)
+		}
+		fields = append(fields, field)
+		return nil
+	}
+
+	if pred != nil {
+		if err := walk(pred); err != nil {
+			return nil, err
+		}
+	}
+	return fields, nil
+}
@@ -0,0 +1,36 @@
+package compiler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGuardFields(t *testing.T) {
+	fields, err := guardFields("result.status == 'approved' && args.amount >= 100 AND result.rush == true")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"result.status", "args.amount", "result.rush"}, fields)
+}
+
+func TestGuardFieldsEmpty(t *testing.T) {
+	fields, err := guardFields("")
+
+	require.NoError(t, err)
+	assert.Empty(t, fields)
+}
+
+func TestGuardFieldsRejectsBoundComparison(t *testing.T) {
+	_, err := guardFields("args.amount > bound.limit")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `bound variable "bound.limit"`)
+}
+
+func TestGuardFieldsReportsColumn(t *testing.T) {
+	_, err := guardFields("result.status = 'approved'")
+
+	require.Error(t, err)
+	assert.Equal(t, "use == for equality (column 15)", err.Error())
+}
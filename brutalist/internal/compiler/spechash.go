@@ -0,0 +1,145 @@
+package compiler
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+// specHashDomain separates spec hashes from the content-addressed IDs in ir
+// (same SHA256(domain + 0x00 + data) format).
+const specHashDomain = "nysm/spec/v1"
+
+// SpecHash returns a stable hex SHA-256 hash of a compiled concept set,
+// suitable for Invocation.SpecHash.
+//
+// The hashed document is the canonical JSON (RFC 8785, as for IDs in ir) of
+//
+//	{"concepts": [...], "syncs": [...]}
+//
+// Concepts are sorted by name, so file and declaration order of concepts do
+// not affect the hash. Syncs keep their declaration order, which determines
+// firing order (CRITICAL-3), so reordering syncs changes the hash. Other
+// order within a spec (actions, args, outputs) is kept as declared.
+//
+// Null, empty, and absent fields are treated alike, so a nil map and an
+// empty one hash the same.
+func SpecHash(specs []ir.ConceptSpec, syncs []ir.SyncRule) string {
+	type concept struct {
+		name      string
+		canonical []byte
+		value     ir.IRValue
+	}
+	sorted := make([]concept, len(specs))
+	for i, spec := range specs {
+		value := specValue(spec)
+		sorted[i] = concept{name: spec.Name, canonical: mustCanonical(value), value: value}
+	}
+	// Sort by name; duplicate names fall back to content so the order of
+	// the input never leaks into the hash.
+	slices.SortFunc(sorted, func(a, b concept) int {
+		if c := cmp.Compare(a.name, b.name); c != 0 {
+			return c
+		}
+		return bytes.Compare(a.canonical, b.canonical)
+	})
+
+	concepts := make(ir.IRArray, len(sorted))
+	for i, c := range sorted {
+		concepts[i] = c.value
+	}
+
+	rules := make(ir.IRArray, len(syncs))
+	for i, sync := range syncs {
+		rules[i] = specValue(sync)
+	}
+
+	doc := ir.IRObject{
+		"concepts": concepts,
+		"syncs":    rules,
+	}
+
+	h := sha256.New()
+	h.Write([]byte(specHashDomain))
+	h.Write([]byte{0x00})
+	h.Write(mustCanonical(doc))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// specValue converts a compiled spec or sync rule to an IRValue via its JSON
+// encoding, dropping null and empty fields.
+//
+// Spec types hold only strings, ints, bools, slices, and maps, so the
+// conversion cannot fail for them; a failure means a float or other
+// unsupported type was added to ir and panics.
+func specValue(v any) ir.IRValue {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("SpecHash: marshal %T: %v", v, err))
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var raw any
+	if err := dec.Decode(&raw); err != nil {
+		panic(fmt.Sprintf("SpecHash: decode %T: %v", v, err))
+	}
+	value, err := ir.FromGo(pruneEmpty(raw))
+	if err != nil {
+		panic(fmt.Sprintf("SpecHash: convert %T: %v", v, err))
+	}
+	return value
+}
+
+// pruneEmpty removes null, empty-array, and empty-object members from
+// decoded JSON objects, recursively.
+func pruneEmpty(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, elem := range val {
+			elem = pruneEmpty(elem)
+			if isEmptyJSON(elem) {
+				delete(val, k)
+			} else {
+				val[k] = elem
+			}
+		}
+		return val
+	case []any:
+		for i, elem := range val {
+			val[i] = pruneEmpty(elem)
+		}
+		return val
+	default:
+		return v
+	}
+}
+
+// isEmptyJSON reports whether a decoded JSON value is null or an empty
+// array or object.
+func isEmptyJSON(v any) bool {
+	switch val := v.(type) {
+	case nil:
+		return true
+	case map[string]any:
+		return len(val) == 0
+	case []any:
+		return len(val) == 0
+	default:
+		return false
+	}
+}
+
+// mustCanonical returns the canonical JSON of a value built by specValue.
+func mustCanonical(v ir.IRValue) []byte {
+	data, err := ir.CanonicalJSON(v)
+	if err != nil {
+		panic(fmt.Sprintf("SpecHash: canonical JSON: %v", err))
+	}
+	return data
+}
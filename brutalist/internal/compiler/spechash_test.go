@@ -0,0 +1,132 @@
+package compiler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+func specHashFixture() ([]ir.ConceptSpec, []ir.SyncRule) {
+	specs := []ir.ConceptSpec{
+		{
+			Name:    "Inventory",
+			Purpose: "Track stock",
+			Actions: []ir.ActionSig{{
+				Name:    "reserve",
+				Args:    []ir.NamedArg{{Name: "item_id", Type: "string"}},
+				Outputs: []ir.OutputCase{{Case: "Success", Fields: map[string]string{"reservation_id": "string"}}},
+			}},
+		},
+		{
+			Name:    "Cart",
+			Purpose: "Hold items",
+			StateSchema: []ir.StateSchema{{
+				Name:   "CartItem",
+				Fields: map[string]string{"item_id": "string", "quantity": "int"},
+			}},
+			Actions: []ir.ActionSig{{
+				Name:    "checkout",
+				Args:    []ir.NamedArg{{Name: "cart_id", Type: "string"}},
+				Outputs: []ir.OutputCase{{Case: "Success"}},
+			}},
+		},
+	}
+	syncs := []ir.SyncRule{
+		{
+			ID:    "reserve-on-checkout",
+			Scope: ir.ScopeSpec{Mode: "flow"},
+			When:  ir.WhenClause{ActionRef: "Cart.checkout", EventType: "completed", Bindings: map[string]string{"cart_id": "cart_id"}},
+			Then:  ir.ThenClause{ActionRef: "Inventory.reserve", Args: map[string]string{"item_id": "bound.cart_id"}},
+		},
+		{
+			ID:    "remind-after-checkout",
+			Scope: ir.ScopeSpec{Mode: "flow"},
+			When:  ir.WhenClause{ActionRef: "Cart.checkout", EventType: "completed"},
+			Then:  ir.ThenClause{ActionRef: "Cart.remind", AfterSteps: 3},
+		},
+	}
+	return specs, syncs
+}
+
+func TestSpecHash_Stable(t *testing.T) {
+	specs, syncs := specHashFixture()
+
+	hash := SpecHash(specs, syncs)
+
+	assert.Len(t, hash, 64, "hex SHA-256")
+	assert.Equal(t, hash, SpecHash(specs, syncs))
+
+	specs2, syncs2 := specHashFixture()
+	assert.Equal(t, hash, SpecHash(specs2, syncs2), "equal inputs must hash equal")
+}
+
+func TestSpecHash_ConceptOrderIndependent(t *testing.T) {
+	specs, syncs := specHashFixture()
+	reordered := []ir.ConceptSpec{specs[1], specs[0]}
+
+	assert.Equal(t, SpecHash(specs, syncs), SpecHash(reordered, syncs))
+}
+
+func TestSpecHash_SyncOrderMatters(t *testing.T) {
+	specs, syncs := specHashFixture()
+	reordered := []ir.SyncRule{syncs[1], syncs[0]}
+
+	assert.NotEqual(t, SpecHash(specs, syncs), SpecHash(specs, reordered))
+}
+
+func TestSpecHash_ContentChanges(t *testing.T) {
+	specs, syncs := specHashFixture()
+	base := SpecHash(specs, syncs)
+
+	tests := []struct {
+		name   string
+		mutate func(specs []ir.ConceptSpec, syncs []ir.SyncRule)
+	}{
+		{"concept purpose", func(specs []ir.ConceptSpec, _ []ir.SyncRule) { specs[0].Purpose = "Count stock" }},
+		{"action arg type", func(specs []ir.ConceptSpec, _ []ir.SyncRule) { specs[0].Actions[0].Args[0].Type = "int" }},
+		{"state field", func(specs []ir.ConceptSpec, _ []ir.SyncRule) { specs[1].StateSchema[0].Fields["price"] = "int" }},
+		{"sync then args", func(_ []ir.ConceptSpec, syncs []ir.SyncRule) { syncs[0].Then.Args["qty"] = "1" }},
+		{"sync after steps", func(_ []ir.ConceptSpec, syncs []ir.SyncRule) { syncs[1].Then.AfterSteps = 4 }},
+		{"sync where clause", func(_ []ir.ConceptSpec, syncs []ir.SyncRule) {
+			syncs[0].Where = &ir.WhereClause{Source: "CartItem", Filter: "item_id == bound.cart_id"}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			specs, syncs := specHashFixture()
+			tt.mutate(specs, syncs)
+			assert.NotEqual(t, base, SpecHash(specs, syncs))
+		})
+	}
+}
+
+func TestSpecHash_EmptyEqualsAbsent(t *testing.T) {
+	specs, syncs := specHashFixture()
+	base := SpecHash(specs, syncs)
+
+	syncs[1].When.Bindings = map[string]string{}
+	specs[1].Actions[0].Outputs[0].Fields = map[string]string{}
+
+	assert.Equal(t, base, SpecHash(specs, syncs))
+}
+
+func TestSpecHash_DuplicateConceptNames(t *testing.T) {
+	specs, syncs := specHashFixture()
+	dup := specs[1]
+	dup.Purpose = "Hold items (v2)"
+
+	a := SpecHash([]ir.ConceptSpec{specs[1], dup}, syncs)
+	b := SpecHash([]ir.ConceptSpec{dup, specs[1]}, syncs)
+	require.NotEmpty(t, a)
+	assert.Equal(t, a, b)
+}
+
+func TestSpecHash_Empty(t *testing.T) {
+	assert.Equal(t, SpecHash(nil, nil), SpecHash([]ir.ConceptSpec{}, []ir.SyncRule{}))
+	specs, syncs := specHashFixture()
+	assert.NotEqual(t, SpecHash(nil, nil), SpecHash(specs, syncs))
+}
@@ -2,6 +2,7 @@ package compiler
 
 import (
 	"fmt"
+	"math"
 	"regexp"
 	"strings"
 
@@ -65,9 +66,35 @@ func CompileSync(v cue.Value) (*ir.SyncRule, error) {
 		return nil, err
 	}
 
+	// Parse priority (optional, overrides declaration order)
+	rule.Priority, err = parsePriority(v)
+	if err != nil {
+		return nil, err
+	}
+
 	return rule, nil
 }
 
+// parsePriority extracts the optional evaluation priority of a sync rule.
+// Lower numbers are evaluated first; rules without one are evaluated after
+// all prioritized rules (see ir.SortSyncRules).
+func parsePriority(v cue.Value) (*int, error) {
+	priorityVal := v.LookupPath(cue.ParsePath("priority"))
+	if !priorityVal.Exists() {
+		return nil, nil
+	}
+	priority, err := priorityVal.Int64()
+	if err != nil || priority < math.MinInt32 || priority > math.MaxInt32 {
+		return nil, &CompileError{
+			Field:   "priority",
+			Message: "priority must be an int",
+			Pos:     priorityVal.Pos(),
+		}
+	}
+	p := int(priority)
+	return &p, nil
+}
+
 // parseScope extracts and validates the scope specification.
 func parseScope(v cue.Value) (ir.ScopeSpec, error) {
 	scopeVal := v.LookupPath(cue.ParsePath("scope"))
@@ -166,6 +193,20 @@ func parseWhenClause(v cue.Value) (ir.WhenClause, error) {
 		when.OutputCase = caseName
 	}
 
+	// Parse guard (optional predicate over result./args. fields)
+	guardVal := whenVal.LookupPath(cue.ParsePath("guard"))
+	if guardVal.Exists() {
+		guard, err := guardVal.String()
+		if err != nil {
+			return when, &CompileError{
+				Field:   "when.guard",
+				Message: "guard must be a string expression",
+				Pos:     guardVal.Pos(),
+			}
+		}
+		when.Guard = guard
+	}
+
 	// Parse bindings (string values only)
 	bindVal := whenVal.LookupPath(cue.ParsePath("bind"))
 	if bindVal.Exists() {
@@ -216,18 +257,14 @@ func parseWhereClause(v cue.Value) (*ir.WhereClause, error) {
 	}
 	where.Source = from
 
-	// Parse filter expression (optional string)
+	// Parse filter expression (optional string). It is stored as written and
+	// parsed again by the engine; parsing here reports errors at compile time.
 	filterVal := v.LookupPath(cue.ParsePath("filter"))
 	if filterVal.Exists() {
-		filter, err := filterVal.String()
-		if err != nil {
-			return nil, &CompileError{
-				Field:   "where.filter",
-				Message: "filter must be a string expression",
-				Pos:     filterVal.Pos(),
-			}
+		if _, err := ParseFilterValue(filterVal); err != nil {
+			return nil, err
 		}
-		where.Filter = filter
+		where.Filter, _ = filterVal.String() // ParseFilterValue checked it is a string
 	}
 
 	// Parse bindings (all string values)
@@ -311,5 +348,19 @@ func parseThenClause(v cue.Value) (ir.ThenClause, error) {
 		}
 	}
 
+	// Parse after_steps (optional non-negative int, deferral in seq ticks)
+	afterVal := thenVal.LookupPath(cue.ParsePath("after_steps"))
+	if afterVal.Exists() {
+		afterSteps, err := afterVal.Int64()
+		if err != nil || afterSteps < 0 || afterSteps > math.MaxInt32 {
+			return then, &CompileError{
+				Field:   "then.after_steps",
+				Message: "after_steps must be a non-negative int",
+				Pos:     afterVal.Pos(),
+			}
+		}
+		then.AfterSteps = int(afterSteps)
+	}
+
 	return then, nil
 }
@@ -258,6 +258,152 @@ func TestCompileSyncOutputCaseMatching(t *testing.T) {
 	assert.Equal(t, "InsufficientFunds", rule.When.OutputCase)
 }
 
+func TestCompileSyncGuard(t *testing.T) {
+	ctx := cuecontext.New()
+	v := ctx.CompileString(`
+		sync: "ship-approved": {
+			scope: "flow"
+
+			when: {
+				action: "Order.review"
+				event: "completed"
+				case: "Success"
+				guard: "result.status == 'approved' && args.amount >= 100"
+				bind: { order_id: "result.order_id" }
+			}
+
+			then: {
+				action: "Shipping.schedule"
+				args: { order_id: "bound.order_id" }
+			}
+		}
+	`)
+
+	require.NoError(t, v.Err())
+	syncVal := v.LookupPath(cue.ParsePath(`sync."ship-approved"`))
+	rule, err := CompileSync(syncVal)
+
+	require.NoError(t, err)
+	assert.Equal(t, "result.status == 'approved' && args.amount >= 100", rule.When.Guard)
+}
+
+func TestCompileSyncGuardNotString(t *testing.T) {
+	ctx := cuecontext.New()
+	v := ctx.CompileString(`
+		sync: "ship-approved": {
+			scope: "flow"
+			when: {
+				action: "Order.review"
+				event: "completed"
+				guard: true
+			}
+			then: { action: "Shipping.schedule" }
+		}
+	`)
+
+	require.NoError(t, v.Err())
+	syncVal := v.LookupPath(cue.ParsePath(`sync."ship-approved"`))
+	_, err := CompileSync(syncVal)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "when.guard")
+}
+
+func TestCompileSyncAfterSteps(t *testing.T) {
+	ctx := cuecontext.New()
+	v := ctx.CompileString(`
+		sync: "remind-unpaid": {
+			scope: "flow"
+			when: { action: "Order.place", event: "completed" }
+			then: {
+				action: "Order.remind"
+				after_steps: 5
+			}
+		}
+	`)
+
+	require.NoError(t, v.Err())
+	syncVal := v.LookupPath(cue.ParsePath(`sync."remind-unpaid"`))
+	rule, err := CompileSync(syncVal)
+
+	require.NoError(t, err)
+	assert.Equal(t, 5, rule.Then.AfterSteps)
+}
+
+func TestCompileSyncAfterStepsInvalid(t *testing.T) {
+	for _, value := range []string{"-1", `"5"`, "2.5"} {
+		t.Run(value, func(t *testing.T) {
+			ctx := cuecontext.New()
+			v := ctx.CompileString(`
+				sync: "remind-unpaid": {
+					scope: "flow"
+					when: { action: "Order.place", event: "completed" }
+					then: {
+						action: "Order.remind"
+						after_steps: ` + value + `
+					}
+				}
+			`)
+
+			require.NoError(t, v.Err())
+			syncVal := v.LookupPath(cue.ParsePath(`sync."remind-unpaid"`))
+			_, err := CompileSync(syncVal)
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "then.after_steps")
+		})
+	}
+}
+
+func TestCompileSyncPriority(t *testing.T) {
+	ctx := cuecontext.New()
+	v := ctx.CompileString(`
+		sync: "reserve-first": {
+			scope: "flow"
+			priority: -2
+			when: { action: "Cart.checkout", event: "completed" }
+			then: { action: "Inventory.reserve" }
+		}
+		sync: "unprioritized": {
+			scope: "flow"
+			when: { action: "Cart.checkout", event: "completed" }
+			then: { action: "Audit.record" }
+		}
+	`)
+
+	require.NoError(t, v.Err())
+	rule, err := CompileSync(v.LookupPath(cue.ParsePath(`sync."reserve-first"`)))
+	require.NoError(t, err)
+	require.NotNil(t, rule.Priority)
+	assert.Equal(t, -2, *rule.Priority)
+
+	rule, err = CompileSync(v.LookupPath(cue.ParsePath(`sync."unprioritized"`)))
+	require.NoError(t, err)
+	assert.Nil(t, rule.Priority)
+}
+
+func TestCompileSyncPriorityInvalid(t *testing.T) {
+	for _, value := range []string{`"1"`, "1.5", "4294967296"} {
+		t.Run(value, func(t *testing.T) {
+			ctx := cuecontext.New()
+			v := ctx.CompileString(`
+				sync: "test": {
+					scope: "flow"
+					priority: ` + value + `
+					when: { action: "A.b", event: "completed" }
+					then: { action: "C.d" }
+				}
+			`)
+
+			require.NoError(t, v.Err())
+			_, err := CompileSync(v.LookupPath(cue.ParsePath(`sync."test"`)))
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "priority must be an int")
+		})
+	}
+}
+
 func TestCompileSyncNoOutputCase(t *testing.T) {
 	ctx := cuecontext.New()
 	v := ctx.CompileString(`
@@ -501,6 +647,32 @@ func TestCompileSyncComplexFilter(t *testing.T) {
 	assert.Equal(t, `status == "active" && quantity > 0`, rule.Where.Filter)
 }
 
+func TestCompileSyncInvalidFilter(t *testing.T) {
+	ctx := cuecontext.New()
+	v := ctx.CompileString(`
+		sync: "bad": {
+			scope: "flow"
+			when: { action: "A.b", event: "completed" }
+			where: {
+				from: "Items"
+				filter: "quantity = 5"
+				bind: { id: "item_id" }
+			}
+			then: { action: "C.d" }
+		}
+	`)
+
+	require.NoError(t, v.Err())
+	syncVal := v.LookupPath(cue.ParsePath(`sync."bad"`))
+	_, err := CompileSync(syncVal)
+
+	require.Error(t, err)
+	var compileErr *CompileError
+	require.ErrorAs(t, err, &compileErr)
+	assert.Equal(t, "where.filter", compileErr.Field)
+	assert.Contains(t, compileErr.Message, "use == for equality")
+}
+
 func TestCompileSyncDashInID(t *testing.T) {
 	ctx := cuecontext.New()
 	v := ctx.CompileString(`
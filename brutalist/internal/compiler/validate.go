@@ -2,10 +2,14 @@ package compiler
 
 import (
 	"fmt"
-	"regexp"
+	"maps"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/roach88/nysm/internal/ir"
+	"github.com/roach88/nysm/internal/queryir"
 )
 
 // Validation error codes (E100-E199)
@@ -21,7 +25,7 @@ const (
 	ErrDuplicateName       = "E105" // duplicate action/state name
 	ErrFloatTypeForbidden  = "E106" // float types not allowed
 
-	// SyncRule errors (E110-E119)
+	// SyncRule errors (E110-E129)
 	ErrInvalidActionRef       = "E110" // invalid action reference format
 	ErrInvalidScopeMode       = "E111" // invalid scope mode or missing keyed key
 	ErrInvalidWhereClause     = "E112" // invalid where clause
@@ -29,14 +33,56 @@ const (
 	ErrUndefinedBoundVariable = "E114" // bound variable not defined
 	ErrMissingSyncClause      = "E115" // missing required clause
 	ErrInvalidEventType       = "E116" // invalid event type
+	ErrArgTypeMismatch        = "E117" // then arg literal does not match declared arg type
+	ErrUnknownActionArg       = "E118" // then arg not declared by target action
+	ErrUnknownWhereSource     = "E119" // where source is not a declared state
+	ErrUnknownWhereField      = "E120" // where clause references an undeclared state field
+	ErrInvalidGuard           = "E121" // when guard is not a valid predicate
+	ErrUnknownGuardField      = "E122" // when guard references an undeclared result field or arg
+	ErrUnknownOutputCase      = "E123" // when output case not declared by the triggering action
+	ErrUnknownActionRef       = "E124" // when/then action not declared by any concept
+	ErrInvalidLiteral         = "E125" // then arg typed literal is malformed or a forbidden float
+	ErrInvalidEnum            = "E126" // enum on an undeclared or non-string field, or with no values
 )
 
-// ValidationError represents a schema validation error.
+// Validation warning codes (W100-W199)
+const (
+	// SyncRule set warnings (W110-W119)
+	WarnShadowedSync = "W110" // sync has the same when clause as an earlier sync
+
+	// SyncRule warnings (W120-W129)
+	WarnWhenActionNoOutputs = "W120" // completed-event sync on an action with no output cases
+)
+
+// Validation severities. An empty Severity is treated as SeverityError so
+// that errors constructed before severities existed keep their meaning.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// ValidationError represents a schema validation error or warning.
 type ValidationError struct {
-	Field   string `json:"field"`
-	Message string `json:"message"`
-	Code    string `json:"code"`
-	Line    int    `json:"line,omitempty"`
+	Field    string `json:"field"`
+	Message  string `json:"message"`
+	Code     string `json:"code"`
+	Line     int    `json:"line,omitempty"`
+	Severity string `json:"severity,omitempty"`
+}
+
+// IsWarning reports whether the finding is a warning rather than an error.
+func (e ValidationError) IsWarning() bool {
+	return e.Severity == SeverityWarning
+}
+
+// HasErrors reports whether any finding has error severity.
+func HasErrors(errs []ValidationError) bool {
+	for _, e := range errs {
+		if !e.IsWarning() {
+			return true
+		}
+	}
+	return false
 }
 
 // Error implements the error interface.
@@ -50,16 +96,24 @@ func (e ValidationError) Error() string {
 // Validate validates compiled IR against schema rules.
 // Returns all errors found (does not fail-fast).
 // Supports ConceptSpec and SyncRule types.
-func Validate(v any) []ValidationError {
+//
+// When specs are given, sync rules are also cross-referenced against them
+// (see validateSyncRuleRefs). Without specs only self-contained checks run.
+//
+// A []ir.SyncRule is validated rule by rule (fields prefixed "syncs[i].")
+// and then as a set, which may produce warnings (see validateSyncRuleSet).
+func Validate(v any, specs ...ir.ConceptSpec) []ValidationError {
 	switch ir := v.(type) {
 	case *ir.ConceptSpec:
 		return validateConceptSpec(ir)
 	case ir.ConceptSpec:
 		return validateConceptSpec(&ir)
 	case *ir.SyncRule:
-		return validateSyncRule(ir)
+		return append(validateSyncRule(ir), validateSyncRuleRefs(ir, specs)...)
 	case ir.SyncRule:
-		return validateSyncRule(&ir)
+		return append(validateSyncRule(&ir), validateSyncRuleRefs(&ir, specs)...)
+	case []ir.SyncRule:
+		return validateSyncRules(ir, specs)
 	default:
 		return []ValidationError{{
 			Field:   "type",
@@ -117,10 +171,13 @@ func validateConceptSpec(spec *ir.ConceptSpec) []ValidationError {
 		}
 
 		// Validate arg types
+		argTypes := make(map[string]string, len(action.Args))
 		for j, arg := range action.Args {
 			typeErrs := validateFieldType(arg.Type, fmt.Sprintf("actions[%d].args[%d].type", i, j), arg.Name)
 			errs = append(errs, typeErrs...)
+			argTypes[arg.Name] = arg.Type
 		}
+		errs = append(errs, validateEnums(action.Enums, argTypes, fmt.Sprintf("actions[%d].enums", i))...)
 
 		// Validate output field types
 		for j, out := range action.Outputs {
@@ -148,11 +205,43 @@ func validateConceptSpec(spec *ir.ConceptSpec) []ValidationError {
 			typeErrs := validateFieldType(fieldType, fmt.Sprintf("state_schema[%d].fields.%s", i, fieldName), fieldName)
 			errs = append(errs, typeErrs...)
 		}
+		errs = append(errs, validateEnums(state.Enums, state.Fields, fmt.Sprintf("state_schema[%d].enums", i))...)
 	}
 
 	return errs
 }
 
+// validateEnums checks that each enum constrains a declared string field
+// and lists at least one value (E126).
+func validateEnums(enums map[string][]string, fieldTypes map[string]string, path string) []ValidationError {
+	var errs []ValidationError
+	for _, name := range slices.Sorted(maps.Keys(enums)) {
+		field := path + "." + name
+		fieldType, ok := fieldTypes[name]
+		switch {
+		case !ok:
+			errs = append(errs, ValidationError{
+				Field:   field,
+				Message: fmt.Sprintf("enum for undeclared field %q", name),
+				Code:    ErrInvalidEnum,
+			})
+		case fieldType != "string":
+			errs = append(errs, ValidationError{
+				Field:   field,
+				Message: fmt.Sprintf("enum on %s field %q, only string fields can be enums", fieldType, name),
+				Code:    ErrInvalidEnum,
+			})
+		case len(enums[name]) == 0:
+			errs = append(errs, ValidationError{
+				Field:   field,
+				Message: fmt.Sprintf("enum for field %q has no values", name),
+				Code:    ErrInvalidEnum,
+			})
+		}
+	}
+	return errs
+}
+
 // validateFieldType validates a type string, returning errors for invalid types and floats.
 func validateFieldType(fieldType, fieldPath, fieldName string) []ValidationError {
 	var errs []ValidationError
@@ -179,54 +268,40 @@ func validateFieldType(fieldType, fieldPath, fieldName string) []ValidationError
 }
 
 // validateSyncRule validates a sync rule specification.
+//
+// The structural checks shared with the engine come from SyncRule.Validate
+// and are given their compiler codes by syncRuleErrorCode; the checks below
+// it are compile-time only.
 func validateSyncRule(rule *ir.SyncRule) []ValidationError {
 	var errs []ValidationError
 
-	// E111: validate scope mode
-	if !isValidScopeMode(rule.Scope.Mode) {
+	// E110, E111, E114, E116, E125: structural checks
+	for _, err := range rule.Validate() {
+		verr := err.(ir.ValidationError)
 		errs = append(errs, ValidationError{
-			Field:   "scope.mode",
-			Message: fmt.Sprintf("invalid scope mode %q, must be \"flow\", \"global\", or \"keyed\"", rule.Scope.Mode),
-			Code:    ErrInvalidScopeMode,
+			Field:   verr.Field,
+			Message: verr.Message,
+			Code:    syncRuleErrorCode(rule, verr.Field),
 		})
 	}
 
-	// E111: keyed scope must have non-empty key
-	if rule.Scope.Mode == "keyed" && strings.TrimSpace(rule.Scope.Key) == "" {
+	// E111, E116: SyncRule.Validate treats an empty scope mode or event type
+	// as the engine default; compiled rules always spell them out
+	if rule.Scope.Mode == "" {
 		errs = append(errs, ValidationError{
-			Field:   "scope.key",
-			Message: "keyed scope requires a non-empty key field",
+			Field:   "scope.mode",
+			Message: `invalid scope mode "", must be "flow", "global", or "keyed"`,
 			Code:    ErrInvalidScopeMode,
 		})
 	}
-
-	// E110: validate when action reference
-	if !isValidActionRef(rule.When.ActionRef) {
-		errs = append(errs, ValidationError{
-			Field:   "when.action_ref",
-			Message: fmt.Sprintf("invalid action reference %q, expected format \"Concept.action\"", rule.When.ActionRef),
-			Code:    ErrInvalidActionRef,
-		})
-	}
-
-	// E116: validate event type
-	if rule.When.EventType != "completed" && rule.When.EventType != "invoked" {
+	if rule.When.EventType == "" {
 		errs = append(errs, ValidationError{
 			Field:   "when.event_type",
-			Message: fmt.Sprintf("invalid event type %q, must be \"completed\" or \"invoked\"", rule.When.EventType),
+			Message: `invalid event type "", must be "completed" or "invoked"`,
 			Code:    ErrInvalidEventType,
 		})
 	}
 
-	// E113: validate then action reference
-	if !isValidActionRef(rule.Then.ActionRef) {
-		errs = append(errs, ValidationError{
-			Field:   "then.action_ref",
-			Message: fmt.Sprintf("invalid action reference %q, expected format \"Concept.action\"", rule.Then.ActionRef),
-			Code:    ErrInvalidActionRef,
-		})
-	}
-
 	// E112: validate where clause if present
 	if rule.Where != nil {
 		if strings.TrimSpace(rule.Where.Source) == "" {
@@ -238,16 +313,162 @@ func validateSyncRule(rule *ir.SyncRule) []ValidationError {
 		}
 	}
 
-	// E114: validate bound variables in then.args are defined
-	definedVars := collectBoundVariables(rule)
-	for argName, argExpr := range rule.Then.Args {
-		usedVars := extractBoundVariableRefs(argExpr)
-		for _, usedVar := range usedVars {
-			if !definedVars[usedVar] {
+	// E121: guard must parse and reference only result./args. fields
+	if strings.TrimSpace(rule.When.Guard) != "" {
+		if _, err := guardFields(rule.When.Guard); err != nil {
+			errs = append(errs, ValidationError{
+				Field:   "when.guard",
+				Message: fmt.Sprintf("invalid guard %q: %s", rule.When.Guard, err),
+				Code:    ErrInvalidGuard,
+			})
+		}
+	}
+
+	return errs
+}
+
+// syncRuleErrorCode returns the code for a SyncRule.Validate error on field.
+func syncRuleErrorCode(rule *ir.SyncRule, field string) string {
+	switch field {
+	case "scope.mode", "scope.key":
+		return ErrInvalidScopeMode
+	case "when.action_ref", "then.action_ref":
+		return ErrInvalidActionRef
+	case "when.event_type":
+		return ErrInvalidEventType
+	}
+
+	// then.args.<name>: typed literals are only checked for well-formedness,
+	// every other expression only for undefined bound variables
+	name := strings.TrimPrefix(field, "then.args.")
+	if ir.IsTypedLiteral(rule.Then.Args[name]) {
+		return ErrInvalidLiteral
+	}
+	return ErrUndefinedBoundVariable
+}
+
+// validateSyncRuleRefs cross-references a sync rule against concept specs.
+// When and then actions that do not resolve are reported (E124); the
+// action-specific checks only run for actions that resolve.
+func validateSyncRuleRefs(rule *ir.SyncRule, specs []ir.ConceptSpec) []ValidationError {
+	if len(specs) == 0 {
+		return nil
+	}
+
+	var errs []ValidationError
+
+	// E119/E120: where source must be a declared state and the clause must
+	// only reference fields of that state
+	if rule.Where != nil && strings.TrimSpace(rule.Where.Source) != "" {
+		errs = append(errs, validateWhereSchema(rule.Where, specs)...)
+	}
+
+	// E124: when and then actions must be declared by a concept, otherwise
+	// the rule silently never fires (or fires an action nothing handles)
+	for _, ref := range []struct{ clause, ref string }{
+		{"when", rule.When.ActionRef},
+		{"then", rule.Then.ActionRef},
+	} {
+		concept, action, err := ir.ParseActionRef(ref.ref)
+		if err != nil {
+			continue // Malformed refs are reported as E110
+		}
+		if _, ok := findActionSig(specs, ref.ref); ok {
+			continue
+		}
+		message := fmt.Sprintf("unknown action %q: concept %q has no action %q", ref.ref, concept, action)
+		if !hasConcept(specs, concept) {
+			message = fmt.Sprintf("unknown action %q: no concept %q", ref.ref, concept)
+		}
+		errs = append(errs, ValidationError{
+			Field:   ref.clause + ".action_ref",
+			Message: message,
+			Code:    ErrUnknownActionRef,
+		})
+	}
+
+	if action, ok := findActionSig(specs, rule.When.ActionRef); ok {
+		// E123: a case-specific rule must name a case the action declares
+		caseDeclared := rule.When.OutputCase == "" || hasOutputCase(action, rule.When.OutputCase)
+		if !caseDeclared {
+			declared := "none"
+			if names := outputCaseNames(action); len(names) > 0 {
+				declared = strings.Join(names, ", ")
+			}
+			errs = append(errs, ValidationError{
+				Field:   "when.output_case",
+				Message: fmt.Sprintf("action %q has no output case %q (declared: %s)", rule.When.ActionRef, rule.When.OutputCase, declared),
+				Code:    ErrUnknownOutputCase,
+			})
+		}
+
+		// W120: an action without output cases never completes, so a rule
+		// on its completion can never fire (a named case is already E123)
+		if rule.When.EventType == "completed" && rule.When.OutputCase == "" && len(action.Outputs) == 0 {
+			errs = append(errs, ValidationError{
+				Field:    "when.action_ref",
+				Message:  fmt.Sprintf("action %q declares no output cases, so this sync can never match a completion", rule.When.ActionRef),
+				Code:     WarnWhenActionNoOutputs,
+				Severity: SeverityWarning,
+			})
+		}
+
+		// E122: guard fields must be declared by the when action, in the
+		// matched output case (or any case when the rule matches all cases)
+		if fields, err := guardFields(rule.When.Guard); err == nil && caseDeclared {
+			for _, field := range fields {
+				if !guardFieldDeclared(action, rule.When.OutputCase, field) {
+					errs = append(errs, ValidationError{
+						Field:   "when.guard",
+						Message: guardFieldMessage(rule.When, field),
+						Code:    ErrUnknownGuardField,
+					})
+				}
+			}
+		}
+	}
+
+	// E117/E118: then args must be declared by the target action and
+	// literals must match the declared type (and enum, if any)
+	if action, ok := findActionSig(specs, rule.Then.ActionRef); ok {
+		declared := make(map[string]string, len(action.Args))
+		for _, arg := range action.Args {
+			declared[arg.Name] = arg.Type
+		}
+
+		for _, argName := range sortedKeys(rule.Then.Args) {
+			argExpr := rule.Then.Args[argName]
+			field := fmt.Sprintf("then.args.%s", argName)
+
+			argType, ok := declared[argName]
+			if !ok {
+				errs = append(errs, ValidationError{
+					Field:   field,
+					Message: fmt.Sprintf("action %q has no arg %q", rule.Then.ActionRef, argName),
+					Code:    ErrUnknownActionArg,
+				})
+				continue
+			}
+
+			if !isLiteralExpr(argExpr) {
+				continue
+			}
+			if _, err := ir.ParseArgLiteral(argExpr); err != nil {
+				continue // Reported as E125
+			}
+			if !literalMatchesType(argExpr, argType) {
+				errs = append(errs, ValidationError{
+					Field:   field,
+					Message: fmt.Sprintf("literal %q is not a valid %s for arg %q of %q", argExpr, argType, argName, rule.Then.ActionRef),
+					Code:    ErrArgTypeMismatch,
+				})
+				continue
+			}
+			if allowed, ok := action.Enums[argName]; ok && !literalInEnum(argExpr, allowed) {
 				errs = append(errs, ValidationError{
-					Field:   fmt.Sprintf("then.args.%s", argName),
-					Message: fmt.Sprintf("undefined bound variable %q in expression %q", usedVar, argExpr),
-					Code:    ErrUndefinedBoundVariable,
+					Field:   field,
+					Message: fmt.Sprintf("literal %q is not one of the allowed values of arg %q of %q: %s", argExpr, argName, rule.Then.ActionRef, strings.Join(allowed, ", ")),
+					Code:    ErrArgTypeMismatch,
 				})
 			}
 		}
@@ -256,72 +477,271 @@ func validateSyncRule(rule *ir.SyncRule) []ValidationError {
 	return errs
 }
 
-// isValidType checks if a type string is valid for IR.
-func isValidType(t string) bool {
-	validTypes := map[string]bool{
-		"string": true,
-		"int":    true,
-		"bool":   true,
-		"array":  true,
-		"object": true,
+// validateSyncRules validates each rule in a set, then the set as a whole.
+func validateSyncRules(rules []ir.SyncRule, specs []ir.ConceptSpec) []ValidationError {
+	var errs []ValidationError
+
+	for i := range rules {
+		ruleErrs := append(validateSyncRule(&rules[i]), validateSyncRuleRefs(&rules[i], specs)...)
+		for _, e := range ruleErrs {
+			e.Field = fmt.Sprintf("syncs[%d].%s", i, e.Field)
+			errs = append(errs, e)
+		}
 	}
-	return validTypes[t]
+
+	return append(errs, validateSyncRuleSet(rules)...)
 }
 
-// isFloatType checks if a type string represents a float type.
-func isFloatType(t string) bool {
-	floatTypes := map[string]bool{
-		"float":   true,
-		"float32": true,
-		"float64": true,
-		"number":  true,
-		"double":  true,
+// validateSyncRuleSet checks relationships between rules in declaration order.
+func validateSyncRuleSet(rules []ir.SyncRule) []ValidationError {
+	var errs []ValidationError
+
+	// W110: a rule whose when clause is identical to an earlier rule's fires
+	// on exactly the same events, so it is redundant or an accidental duplicate
+	for j := range rules {
+		for i := 0; i < j; i++ {
+			if !sameWhenClause(rules[i].When, rules[j].When) {
+				continue
+			}
+			errs = append(errs, ValidationError{
+				Field:    fmt.Sprintf("syncs[%d].when", j),
+				Message:  fmt.Sprintf("sync %q has the same when clause as earlier sync %q", rules[j].ID, rules[i].ID),
+				Code:     WarnShadowedSync,
+				Severity: SeverityWarning,
+			})
+			break // Report against the first earlier match only
+		}
 	}
-	return floatTypes[t]
+
+	return errs
 }
 
-// isValidScopeMode checks if a scope mode is valid.
-func isValidScopeMode(mode string) bool {
-	return mode == "flow" || mode == "global" || mode == "keyed"
+// sameWhenClause reports whether two when clauses match the same events and
+// bind the same variables.
+func sameWhenClause(a, b ir.WhenClause) bool {
+	return a.ActionRef == b.ActionRef &&
+		a.EventType == b.EventType &&
+		a.OutputCase == b.OutputCase &&
+		a.Guard == b.Guard &&
+		maps.Equal(a.Bindings, b.Bindings)
 }
 
-// actionRefPattern matches "Concept.action" format.
-// Concept starts with uppercase letter, action starts with lowercase letter.
-var actionRefPattern = regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*\.[a-z][a-zA-Z0-9]*$`)
+// hasOutputCase reports whether the action declares the named output case.
+func hasOutputCase(action *ir.ActionSig, name string) bool {
+	for _, out := range action.Outputs {
+		if out.Case == name {
+			return true
+		}
+	}
+	return false
+}
 
-// isValidActionRef checks if an action reference has valid format.
-func isValidActionRef(ref string) bool {
-	return actionRefPattern.MatchString(ref)
+// outputCaseNames returns the action's output case names in declaration order.
+func outputCaseNames(action *ir.ActionSig) []string {
+	names := make([]string, 0, len(action.Outputs))
+	for _, out := range action.Outputs {
+		names = append(names, out.Case)
+	}
+	return names
 }
 
-// collectBoundVariables returns all variable names defined in when and where bindings.
-func collectBoundVariables(rule *ir.SyncRule) map[string]bool {
-	vars := make(map[string]bool)
+// guardFieldDeclared reports whether a "result.x" or "args.x" guard field is
+// declared by the action. Result fields are looked up in outputCase, or in
+// any output case if outputCase is empty.
+func guardFieldDeclared(action *ir.ActionSig, outputCase, field string) bool {
+	if name, ok := strings.CutPrefix(field, guardArgsPrefix); ok {
+		for _, arg := range action.Args {
+			if arg.Name == name {
+				return true
+			}
+		}
+		return false
+	}
 
-	for varName := range rule.When.Bindings {
-		vars[varName] = true
+	name := strings.TrimPrefix(field, guardResultPrefix)
+	for _, out := range action.Outputs {
+		if outputCase != "" && out.Case != outputCase {
+			continue
+		}
+		if _, ok := out.Fields[name]; ok {
+			return true
+		}
 	}
+	return false
+}
 
-	if rule.Where != nil {
-		for varName := range rule.Where.Bindings {
-			vars[varName] = true
+// guardFieldMessage describes an undeclared guard field.
+func guardFieldMessage(when ir.WhenClause, field string) string {
+	if name, ok := strings.CutPrefix(field, guardArgsPrefix); ok {
+		return fmt.Sprintf("action %q has no arg %q", when.ActionRef, name)
+	}
+	name := strings.TrimPrefix(field, guardResultPrefix)
+	if when.OutputCase != "" {
+		return fmt.Sprintf("output case %q of %q has no field %q", when.OutputCase, when.ActionRef, name)
+	}
+	return fmt.Sprintf("no output case of %q has field %q", when.ActionRef, name)
+}
+
+// hasConcept reports whether specs declares a concept with the given name.
+func hasConcept(specs []ir.ConceptSpec, name string) bool {
+	for i := range specs {
+		if specs[i].Name == name {
+			return true
 		}
 	}
+	return false
+}
 
-	return vars
+// findActionSig resolves a "Concept.action" reference to its signature.
+func findActionSig(specs []ir.ConceptSpec, ref string) (*ir.ActionSig, bool) {
+	concept, action, err := ir.ParseActionRef(ref)
+	if err != nil {
+		return nil, false
+	}
+	for i := range specs {
+		if specs[i].Name != concept {
+			continue
+		}
+		for j := range specs[i].Actions {
+			if specs[i].Actions[j].Name == action {
+				return &specs[i].Actions[j], true
+			}
+		}
+	}
+	return nil, false
 }
 
-// boundVarPattern matches "bound.variable_name" references in expressions.
-var boundVarPattern = regexp.MustCompile(`bound\.([a-zA-Z_][a-zA-Z0-9_]*)`)
+// validateWhereSchema checks a where clause against the declared state
+// schemas with queryir.ValidateSchema, the same check that applies to
+// compiled queries. Implicit columns (id, seq) are accepted.
+//
+// An unknown source is reported as E119; bindings and filter fields that
+// the state does not declare (or filter literals of the wrong type) are
+// reported as E120. Filters that do not parse are left to the compiler,
+// which reports them with a source position.
+func validateWhereSchema(where *ir.WhereClause, specs []ir.ConceptSpec) []ValidationError {
+	// Where bindings map variable -> field; Select bindings map field -> variable
+	bindings := queryir.Select{From: where.Source, Bindings: make(map[string]string, len(where.Bindings))}
+	for _, varName := range sortedKeys(where.Bindings) {
+		if _, dup := bindings.Bindings[where.Bindings[varName]]; !dup {
+			bindings.Bindings[where.Bindings[varName]] = varName
+		}
+	}
 
-// extractBoundVariableRefs extracts bound variable names from an expression string.
-func extractBoundVariableRefs(expr string) []string {
-	matches := boundVarPattern.FindAllStringSubmatch(expr, -1)
-	vars := make([]string, 0, len(matches))
-	for _, match := range matches {
-		if len(match) > 1 {
-			vars = append(vars, match[1])
+	var errs []ValidationError
+	for _, verr := range queryir.ValidateSchema(bindings, specs) {
+		if verr.Field == "from" {
+			return []ValidationError{{
+				Field:   "where.source",
+				Message: fmt.Sprintf("unknown state %q, not declared by any concept", where.Source),
+				Code:    ErrUnknownWhereSource,
+			}}
 		}
+		fieldName := strings.TrimPrefix(verr.Field, where.Source+".")
+		errs = append(errs, ValidationError{
+			Field:   "where.bindings." + bindings.Bindings[fieldName],
+			Message: fmt.Sprintf("field %q: %s", fieldName, verr.Message),
+			Code:    ErrUnknownWhereField,
+		})
+	}
+
+	pred, err := queryir.ParseFilter(where.Filter)
+	if err != nil || pred == nil {
+		return errs
+	}
+	for _, verr := range queryir.ValidateSchema(queryir.Select{From: where.Source, Filter: pred}, specs) {
+		errs = append(errs, ValidationError{
+			Field:   "where.filter",
+			Message: fmt.Sprintf("%s: %s", verr.Field, verr.Message),
+			Code:    ErrUnknownWhereField,
+		})
+	}
+	return errs
+}
+
+// isLiteralExpr reports whether a then-arg expression references no bound variables.
+func isLiteralExpr(expr string) bool {
+	return len(ir.BoundVariableRefs(expr)) == 0
+}
+
+// literalMatchesType reports whether a literal then-arg expression is valid
+// for a declared arg type. A typed literal ("int:5") must have exactly the
+// declared type.
+// Bare literals are passed to the action as strings, so a string arg accepts
+// any bare literal; int and bool args require bare literals that parse as
+// such. Arrays and objects cannot be written as literals.
+func literalMatchesType(literal, argType string) bool {
+	if ir.IsTypedLiteral(literal) {
+		value, err := ir.ParseArgLiteral(literal)
+		if err != nil {
+			return false
+		}
+		switch value.(type) {
+		case ir.IRString:
+			return argType == "string"
+		case ir.IRInt:
+			return argType == "int"
+		case ir.IRBool:
+			return argType == "bool"
+		default:
+			return false
+		}
+	}
+
+	switch argType {
+	case "string":
+		return true
+	case "int":
+		_, err := strconv.ParseInt(literal, 10, 64)
+		return err == nil
+	case "bool":
+		return literal == "true" || literal == "false"
+	default:
+		return false
+	}
+}
+
+// literalInEnum reports whether a literal then-arg expression, already known
+// to be a valid string, is one of the allowed enum values.
+func literalInEnum(literal string, allowed []string) bool {
+	value, err := ir.ParseArgLiteral(literal)
+	if err != nil {
+		return false
+	}
+	s, ok := value.(ir.IRString)
+	return ok && slices.Contains(allowed, string(s))
+}
+
+// sortedKeys returns map keys in sorted order for deterministic error output.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
 	}
-	return vars
+	sort.Strings(keys)
+	return keys
+}
+
+// isValidType checks if a type string is valid for IR.
+func isValidType(t string) bool {
+	validTypes := map[string]bool{
+		"string": true,
+		"int":    true,
+		"bool":   true,
+		"array":  true,
+		"object": true,
+	}
+	return validTypes[t]
+}
+
+// isFloatType checks if a type string represents a float type.
+func isFloatType(t string) bool {
+	floatTypes := map[string]bool{
+		"float":   true,
+		"float32": true,
+		"float64": true,
+		"number":  true,
+		"double":  true,
+	}
+	return floatTypes[t]
 }
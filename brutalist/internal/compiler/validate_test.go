@@ -53,6 +53,46 @@ func TestValidateConceptSpecValidWithStates(t *testing.T) {
 	assert.Empty(t, errs, "valid spec with states should have no errors")
 }
 
+func TestValidateConceptSpecEnums(t *testing.T) {
+	spec := &ir.ConceptSpec{
+		Name:    "Order",
+		Purpose: "Tracks orders",
+		StateSchema: []ir.StateSchema{{
+			Name:   "Order",
+			Fields: map[string]string{"status": "string", "count": "int"},
+			Enums: map[string][]string{
+				"status": {"pending", "shipped"},
+			},
+		}},
+		Actions: []ir.ActionSig{{
+			Name:    "ship",
+			Args:    []ir.NamedArg{{Name: "status", Type: "string"}},
+			Enums:   map[string][]string{"status": {"shipped"}},
+			Outputs: []ir.OutputCase{{Case: "Success"}},
+		}},
+	}
+	assert.Empty(t, Validate(spec))
+
+	spec.StateSchema[0].Enums = map[string][]string{
+		"count":   {"1"},
+		"missing": {"a"},
+		"status":  {},
+	}
+	spec.Actions[0].Enums = map[string][]string{"carrier": {"ups"}}
+
+	errs := Validate(spec)
+	require.Len(t, errs, 4)
+	for _, err := range errs {
+		assert.Equal(t, ErrInvalidEnum, err.Code)
+	}
+	assert.Equal(t, "actions[0].enums.carrier", errs[0].Field)
+	assert.Equal(t, "state_schema[0].enums.count", errs[1].Field)
+	assert.Contains(t, errs[1].Message, "only string fields can be enums")
+	assert.Equal(t, "state_schema[0].enums.missing", errs[2].Field)
+	assert.Equal(t, "state_schema[0].enums.status", errs[3].Field)
+	assert.Contains(t, errs[3].Message, "has no values")
+}
+
 func TestValidateConceptSpecMissingPurpose(t *testing.T) {
 	spec := &ir.ConceptSpec{
 		Name:    "Bad",
@@ -482,12 +522,24 @@ func TestValidateSyncRuleActionRefFormats(t *testing.T) {
 		"Cart.123action",    // action starts with number
 	}
 
+	ruleWithWhen := func(ref string) *ir.SyncRule {
+		return &ir.SyncRule{
+			ID:    "test",
+			Scope: ir.ScopeSpec{Mode: "flow"},
+			When:  ir.WhenClause{ActionRef: ref, EventType: "completed"},
+			Then:  ir.ThenClause{ActionRef: "Inventory.reserve"},
+		}
+	}
+
 	for _, ref := range validRefs {
-		assert.True(t, isValidActionRef(ref), "should be valid: %s", ref)
+		assert.Empty(t, Validate(ruleWithWhen(ref)), "should be valid: %s", ref)
 	}
 
 	for _, ref := range invalidRefs {
-		assert.False(t, isValidActionRef(ref), "should be invalid: %s", ref)
+		errs := Validate(ruleWithWhen(ref))
+		if assert.Len(t, errs, 1, "should be invalid: %s", ref) {
+			assert.Equal(t, ErrInvalidActionRef, errs[0].Code)
+		}
 	}
 }
 
@@ -667,54 +719,57 @@ func TestValidationErrorFormatWithLine(t *testing.T) {
 // Helper Function Tests
 // =============================================================================
 
-func TestExtractBoundVariableRefs(t *testing.T) {
-	tests := []struct {
-		expr     string
-		expected []string
-	}{
-		{"bound.cart_id", []string{"cart_id"}},
-		{"bound.x + bound.y", []string{"x", "y"}},
-		{"no refs here", []string{}},
-		{"bound.item_id + 1", []string{"item_id"}},
-		{"bound.a_b_c", []string{"a_b_c"}},
-		{"bound.x123", []string{"x123"}},
-	}
-
-	for _, tt := range tests {
-		result := extractBoundVariableRefs(tt.expr)
-		assert.Equal(t, tt.expected, result, "for expr: %s", tt.expr)
-	}
-}
-
-func TestCollectBoundVariables(t *testing.T) {
+func TestValidateSyncRuleBoundVariables(t *testing.T) {
 	rule := &ir.SyncRule{
+		ID:    "test",
+		Scope: ir.ScopeSpec{Mode: "flow"},
 		When: ir.WhenClause{
-			Bindings: map[string]string{"a": "result.a", "b": "result.b"},
+			ActionRef: "Cart.checkout",
+			EventType: "completed",
+			Bindings:  map[string]string{"a": "result.a", "b": "result.b"},
 		},
 		Where: &ir.WhereClause{
-			Bindings: map[string]string{"c": "c", "d": "d"},
+			Source:   "CartItem",
+			Bindings: map[string]string{"c": "item_id", "d": "quantity"},
+		},
+		Then: ir.ThenClause{
+			ActionRef: "Inventory.reserve",
+			Args: map[string]string{
+				"a": "bound.a", "b": "bound.b", "c": "bound.c", "d": "bound.d",
+				"e": "bound.e", "f": "bound.item_id", // Undefined; item_id is a field, not a variable
+			},
 		},
 	}
 
-	vars := collectBoundVariables(rule)
-	assert.True(t, vars["a"])
-	assert.True(t, vars["b"])
-	assert.True(t, vars["c"])
-	assert.True(t, vars["d"])
-	assert.False(t, vars["e"])
+	errs := Validate(rule)
+	require.Len(t, errs, 2)
+	for _, e := range errs {
+		assert.Equal(t, ErrUndefinedBoundVariable, e.Code)
+	}
+	assert.Equal(t, "then.args.e", errs[0].Field)
+	assert.Equal(t, "then.args.f", errs[1].Field)
 }
 
-func TestCollectBoundVariablesNoWhere(t *testing.T) {
+func TestValidateSyncRuleBoundVariablesNoWhere(t *testing.T) {
 	rule := &ir.SyncRule{
+		ID:    "test",
+		Scope: ir.ScopeSpec{Mode: "flow"},
 		When: ir.WhenClause{
-			Bindings: map[string]string{"a": "result.a"},
+			ActionRef: "Cart.checkout",
+			EventType: "completed",
+			Bindings:  map[string]string{"a": "result.a"},
 		},
 		Where: nil, // No where clause
+		Then: ir.ThenClause{
+			ActionRef: "Inventory.reserve",
+			Args:      map[string]string{"a": "bound.a", "c": "bound.c"},
+		},
 	}
 
-	vars := collectBoundVariables(rule)
-	assert.True(t, vars["a"])
-	assert.Len(t, vars, 1)
+	errs := Validate(rule)
+	require.Len(t, errs, 1)
+	assert.Equal(t, ErrUndefinedBoundVariable, errs[0].Code)
+	assert.Equal(t, "then.args.c", errs[0].Field)
 }
 
 func TestIsValidType(t *testing.T) {
@@ -743,15 +798,530 @@ func TestIsFloatType(t *testing.T) {
 	}
 }
 
-func TestIsValidScopeMode(t *testing.T) {
+func TestValidateSyncRuleScopeModes(t *testing.T) {
 	validModes := []string{"flow", "global", "keyed"}
 	invalidModes := []string{"local", "session", ""}
 
+	ruleWithMode := func(mode string) *ir.SyncRule {
+		return &ir.SyncRule{
+			ID:    "test",
+			Scope: ir.ScopeSpec{Mode: mode, Key: "user_id"},
+			When:  ir.WhenClause{ActionRef: "Cart.checkout", EventType: "completed"},
+			Then:  ir.ThenClause{ActionRef: "Inventory.reserve"},
+		}
+	}
+
 	for _, mode := range validModes {
-		assert.True(t, isValidScopeMode(mode), "should be valid: %s", mode)
+		assert.Empty(t, Validate(ruleWithMode(mode)), "should be valid: %s", mode)
 	}
 
 	for _, mode := range invalidModes {
-		assert.False(t, isValidScopeMode(mode), "should be invalid: %s", mode)
+		errs := Validate(ruleWithMode(mode))
+		if assert.Len(t, errs, 1, "should be invalid: %s", mode) {
+			assert.Equal(t, ErrInvalidScopeMode, errs[0].Code)
+		}
+	}
+}
+
+func TestValidateSyncRuleEmptyEventType(t *testing.T) {
+	rule := &ir.SyncRule{
+		ID:    "test",
+		Scope: ir.ScopeSpec{Mode: "flow"},
+		When:  ir.WhenClause{ActionRef: "Cart.checkout"},
+		Then:  ir.ThenClause{ActionRef: "Inventory.reserve"},
+	}
+
+	errs := Validate(rule)
+	require.Len(t, errs, 1)
+	assert.Equal(t, ErrInvalidEventType, errs[0].Code)
+}
+
+// =============================================================================
+// Cross-Reference Validation Tests
+// =============================================================================
+
+// stubConcept declares concept with a single action taking string args, so
+// cross-reference tests can make the other side of a sync resolve (E124).
+func stubConcept(concept, action string, args ...string) ir.ConceptSpec {
+	sig := ir.ActionSig{Name: action, Outputs: []ir.OutputCase{{Case: "Success"}}}
+	for _, arg := range args {
+		sig.Args = append(sig.Args, ir.NamedArg{Name: arg, Type: "string"})
+	}
+	return ir.ConceptSpec{Name: concept, Purpose: "Test stub", Actions: []ir.ActionSig{sig}}
+}
+
+func inventorySpecs() []ir.ConceptSpec {
+	return []ir.ConceptSpec{stubConcept("Cart", "checkout"), {
+		Name:    "Inventory",
+		Purpose: "Tracks stock levels",
+		Actions: []ir.ActionSig{{
+			Name: "reserve",
+			Args: []ir.NamedArg{
+				{Name: "item_id", Type: "string"},
+				{Name: "quantity", Type: "int"},
+				{Name: "priority", Type: "bool"},
+			},
+			Outputs: []ir.OutputCase{{Case: "Success"}},
+		}},
+	}}
+}
+
+func reserveRule(args map[string]string) *ir.SyncRule {
+	return &ir.SyncRule{
+		ID:    "reserve-stock",
+		Scope: ir.ScopeSpec{Mode: "flow"},
+		When: ir.WhenClause{
+			ActionRef: "Cart.checkout",
+			EventType: "completed",
+			Bindings:  map[string]string{"item_id": "result.item_id"},
+		},
+		Then: ir.ThenClause{
+			ActionRef: "Inventory.reserve",
+			Args:      args,
+		},
+	}
+}
+
+func TestValidateSyncRuleThenArgsValid(t *testing.T) {
+	rule := reserveRule(map[string]string{
+		"item_id":  "bound.item_id",
+		"quantity": "3",
+		"priority": "true",
+	})
+
+	errs := Validate(rule, inventorySpecs()...)
+	assert.Empty(t, errs)
+}
+
+func TestValidateSyncRuleThenArgUnknown(t *testing.T) {
+	rule := reserveRule(map[string]string{
+		"item_id": "bound.item_id",
+		"qty":     "3",
+	})
+
+	errs := Validate(rule, inventorySpecs()...)
+	require.Len(t, errs, 1)
+	assert.Equal(t, ErrUnknownActionArg, errs[0].Code)
+	assert.Equal(t, "then.args.qty", errs[0].Field)
+}
+
+func TestValidateSyncRuleThenArgTypeMismatch(t *testing.T) {
+	rule := reserveRule(map[string]string{
+		"item_id":  "bound.item_id",
+		"quantity": "three",
+		"priority": "yes",
+	})
+
+	errs := Validate(rule, inventorySpecs()...)
+	require.Len(t, errs, 2)
+	assert.Equal(t, ErrArgTypeMismatch, errs[0].Code)
+	assert.Equal(t, "then.args.priority", errs[0].Field)
+	assert.Equal(t, ErrArgTypeMismatch, errs[1].Code)
+	assert.Equal(t, "then.args.quantity", errs[1].Field)
+}
+
+func TestValidateSyncRuleThenArgTypedLiterals(t *testing.T) {
+	rule := reserveRule(map[string]string{
+		"item_id":  "str:bound.item_id", // A string value, not a reference
+		"quantity": "int:3",
+		"priority": "bool:true",
+	})
+	rule.When.Bindings = map[string]string{}
+
+	errs := Validate(rule, inventorySpecs()...)
+	assert.Empty(t, errs)
+}
+
+func TestValidateSyncRuleThenArgTypedLiteralMismatch(t *testing.T) {
+	rule := reserveRule(map[string]string{
+		"item_id":  "int:7",
+		"quantity": "str:3",
+		"priority": "bool:false",
+	})
+
+	errs := Validate(rule, inventorySpecs()...)
+	require.Len(t, errs, 2)
+	assert.Equal(t, ErrArgTypeMismatch, errs[0].Code)
+	assert.Equal(t, "then.args.item_id", errs[0].Field)
+	assert.Equal(t, ErrArgTypeMismatch, errs[1].Code)
+	assert.Equal(t, "then.args.quantity", errs[1].Field)
+}
+
+func TestValidateSyncRuleThenArgInvalidLiteral(t *testing.T) {
+	rule := reserveRule(map[string]string{
+		"item_id":  "bound.item_id",
+		"quantity": "float:1.5",
+		"priority": "bool:yes",
+	})
+
+	errs := Validate(rule, inventorySpecs()...)
+	require.Len(t, errs, 2)
+	assert.Equal(t, ErrInvalidLiteral, errs[0].Code)
+	assert.Equal(t, "then.args.priority", errs[0].Field)
+	assert.Equal(t, ErrInvalidLiteral, errs[1].Code)
+	assert.Equal(t, "then.args.quantity", errs[1].Field)
+	assert.Contains(t, errs[1].Message, "forbidden (CP-5)")
+}
+
+func TestValidateSyncRuleThenArgEnum(t *testing.T) {
+	specs := inventorySpecs()
+	reserve := &specs[1].Actions[0]
+	reserve.Args = append(reserve.Args, ir.NamedArg{Name: "status", Type: "string"})
+	reserve.Enums = map[string][]string{"status": {"held", "confirmed"}}
+
+	for _, literal := range []string{"held", "str:confirmed"} {
+		rule := reserveRule(map[string]string{"item_id": "bound.item_id", "status": literal})
+		assert.Empty(t, Validate(rule, specs...), "literal %q", literal)
+	}
+
+	// Bound values are only known at runtime
+	rule := reserveRule(map[string]string{"item_id": "bound.item_id", "status": "bound.item_id"})
+	assert.Empty(t, Validate(rule, specs...))
+
+	rule = reserveRule(map[string]string{"item_id": "bound.item_id", "status": "cancelled"})
+	errs := Validate(rule, specs...)
+	require.Len(t, errs, 1)
+	assert.Equal(t, ErrArgTypeMismatch, errs[0].Code)
+	assert.Equal(t, "then.args.status", errs[0].Field)
+	assert.Contains(t, errs[0].Message, `literal "cancelled" is not one of the allowed values of arg "status"`)
+	assert.Contains(t, errs[0].Message, "held, confirmed")
+}
+
+func TestValidateSyncRuleThenArgsWithoutSpecs(t *testing.T) {
+	rule := reserveRule(map[string]string{
+		"item_id": "bound.item_id",
+		"qty":     "three",
+	})
+
+	// Cross-reference checks only run when specs are supplied
+	assert.Empty(t, Validate(rule))
+}
+
+func TestValidateSyncRuleActionRefsResolve(t *testing.T) {
+	rule := reserveRule(map[string]string{"item_id": "bound.item_id"})
+
+	errs := Validate(rule, inventorySpecs()...)
+	assert.Empty(t, errs)
+}
+
+func TestValidateSyncRuleWhenActionUnknown(t *testing.T) {
+	rule := reserveRule(map[string]string{"item_id": "bound.item_id"})
+	rule.When.ActionRef = "Cart.chekout"
+
+	errs := Validate(rule, inventorySpecs()...)
+	require.Len(t, errs, 1)
+	assert.Equal(t, ErrUnknownActionRef, errs[0].Code)
+	assert.Equal(t, "when.action_ref", errs[0].Field)
+	assert.Equal(t, `unknown action "Cart.chekout": concept "Cart" has no action "chekout"`, errs[0].Message)
+}
+
+func TestValidateSyncRuleThenActionUnknown(t *testing.T) {
+	// Arg checks are skipped for an unresolved then action
+	rule := reserveRule(map[string]string{"anything": "x"})
+	rule.Then.ActionRef = "Shipping.schedule"
+
+	errs := Validate(rule, inventorySpecs()...)
+	require.Len(t, errs, 1)
+	assert.Equal(t, ErrUnknownActionRef, errs[0].Code)
+	assert.Equal(t, "then.action_ref", errs[0].Field)
+	assert.Equal(t, `unknown action "Shipping.schedule": no concept "Shipping"`, errs[0].Message)
+}
+
+func TestValidateSyncRuleMalformedActionRefNotUnknown(t *testing.T) {
+	rule := reserveRule(map[string]string{"item_id": "bound.item_id"})
+	rule.Then.ActionRef = "reserve"
+
+	errs := Validate(rule, inventorySpecs()...)
+	require.Len(t, errs, 1)
+	assert.Equal(t, ErrInvalidActionRef, errs[0].Code, "malformed refs are only reported as E110")
+}
+
+func reviewSpecs() []ir.ConceptSpec {
+	return []ir.ConceptSpec{stubConcept("Shipping", "schedule"), {
+		Name:    "Order",
+		Purpose: "Reviews orders",
+		Actions: []ir.ActionSig{{
+			Name: "review",
+			Args: []ir.NamedArg{{Name: "amount", Type: "int"}},
+			Outputs: []ir.OutputCase{
+				{Case: "Success", Fields: map[string]string{"status": "string"}},
+				{Case: "Rejected", Fields: map[string]string{"reason": "string"}},
+			},
+		}},
+	}}
+}
+
+func guardRule(outputCase, guard string) *ir.SyncRule {
+	return &ir.SyncRule{
+		ID:    "ship-approved",
+		Scope: ir.ScopeSpec{Mode: "flow"},
+		When: ir.WhenClause{
+			ActionRef:  "Order.review",
+			EventType:  "completed",
+			OutputCase: outputCase,
+			Guard:      guard,
+		},
+		Then: ir.ThenClause{ActionRef: "Shipping.schedule"},
+	}
+}
+
+func TestValidateSyncRuleGuardValid(t *testing.T) {
+	rule := guardRule("Success", "result.status == 'approved' && args.amount >= 100")
+
+	assert.Empty(t, Validate(rule, reviewSpecs()...))
+}
+
+func TestValidateSyncRuleGuardInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		guard string
+		want  string
+	}{
+		{"syntax", "result.status ==", "expected value"},
+		{"bound_variable", "result.status == bound.status", "not available in guards"},
+		{"unprefixed_field", "status == 'approved'", `must start with "result." or "args."`},
+		{"float", "args.amount > 1.5", "float literals are forbidden"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := Validate(guardRule("Success", tt.guard))
+			require.Len(t, errs, 1)
+			assert.Equal(t, ErrInvalidGuard, errs[0].Code)
+			assert.Equal(t, "when.guard", errs[0].Field)
+			assert.Contains(t, errs[0].Message, tt.want)
+		})
+	}
+}
+
+func TestValidateSyncRuleGuardUnknownField(t *testing.T) {
+	tests := []struct {
+		name       string
+		outputCase string
+		guard      string
+		want       string
+	}{
+		{"field_of_other_case", "Success", "result.reason == 'fraud'", `output case "Success" of "Order.review" has no field "reason"`},
+		{"field_of_no_case", "", "result.colour == 'red'", `no output case of "Order.review" has field "colour"`},
+		{"undeclared_arg", "Success", "args.total > 5", `action "Order.review" has no arg "total"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := Validate(guardRule(tt.outputCase, tt.guard), reviewSpecs()...)
+			require.Len(t, errs, 1)
+			assert.Equal(t, ErrUnknownGuardField, errs[0].Code)
+			assert.Equal(t, "when.guard", errs[0].Field)
+			assert.Equal(t, tt.want, errs[0].Message)
+		})
+	}
+}
+
+func TestValidateSyncRuleGuardAnyCase(t *testing.T) {
+	// Without an output case, a field declared by any case is accepted
+	rule := guardRule("", "result.reason == 'fraud'")
+
+	assert.Empty(t, Validate(rule, reviewSpecs()...))
+}
+
+func TestValidateSyncRuleOutputCaseDeclared(t *testing.T) {
+	assert.Empty(t, Validate(guardRule("Rejected", ""), reviewSpecs()...))
+	assert.Empty(t, Validate(guardRule("", ""), reviewSpecs()...), "empty case matches any")
+}
+
+func TestValidateSyncRuleOutputCaseUnknown(t *testing.T) {
+	// The guard is not checked against an undeclared case
+	rule := guardRule("InsufficientStock", "result.status == 'approved'")
+
+	errs := Validate(rule, reviewSpecs()...)
+	require.Len(t, errs, 1)
+	assert.Equal(t, ErrUnknownOutputCase, errs[0].Code)
+	assert.Equal(t, "when.output_case", errs[0].Field)
+	assert.Equal(t, `action "Order.review" has no output case "InsufficientStock" (declared: Success, Rejected)`, errs[0].Message)
+}
+
+func TestValidateSyncRuleWhenActionNoOutputs(t *testing.T) {
+	specs := reviewSpecs()
+	specs[1].Actions[0].Outputs = nil
+
+	errs := Validate(guardRule("", ""), specs...)
+	require.Len(t, errs, 1)
+	assert.Equal(t, WarnWhenActionNoOutputs, errs[0].Code)
+	assert.True(t, errs[0].IsWarning())
+	assert.Equal(t, "when.action_ref", errs[0].Field)
+	assert.False(t, HasErrors(errs))
+
+	// Invoked-event rules still fire on an action that never completes
+	invoked := guardRule("", "")
+	invoked.When.EventType = "invoked"
+	assert.Empty(t, Validate(invoked, specs...))
+
+	// A named case is reported as an unknown case instead
+	errs = Validate(guardRule("Success", ""), specs...)
+	require.Len(t, errs, 1)
+	assert.Equal(t, ErrUnknownOutputCase, errs[0].Code)
+	assert.Equal(t, `action "Order.review" has no output case "Success" (declared: none)`, errs[0].Message)
+}
+
+// =============================================================================
+// SyncRule Set Validation Tests
+// =============================================================================
+
+func checkoutRule(id, thenAction string) ir.SyncRule {
+	return ir.SyncRule{
+		ID:    id,
+		Scope: ir.ScopeSpec{Mode: "flow"},
+		When: ir.WhenClause{
+			ActionRef: "Cart.checkout",
+			EventType: "completed",
+			Bindings:  map[string]string{"cart_id": "result.cart_id"},
+		},
+		Then: ir.ThenClause{
+			ActionRef: thenAction,
+			Args:      map[string]string{"cart_id": "bound.cart_id"},
+		},
+	}
+}
+
+func TestValidateSyncRulesShadowed(t *testing.T) {
+	rules := []ir.SyncRule{
+		checkoutRule("reserve-stock", "Inventory.reserve"),
+		checkoutRule("reserve-stock-again", "Inventory.reserve"),
+	}
+
+	errs := Validate(rules)
+	require.Len(t, errs, 1)
+	assert.Equal(t, WarnShadowedSync, errs[0].Code)
+	assert.Equal(t, SeverityWarning, errs[0].Severity)
+	assert.True(t, errs[0].IsWarning())
+	assert.Equal(t, "syncs[1].when", errs[0].Field)
+	assert.Contains(t, errs[0].Message, `"reserve-stock-again"`)
+	assert.Contains(t, errs[0].Message, `"reserve-stock"`)
+	assert.False(t, HasErrors(errs))
+}
+
+func TestValidateSyncRulesDistinctWhens(t *testing.T) {
+	shipped := checkoutRule("notify-shipped", "Email.send")
+	shipped.When.ActionRef = "Order.ship"
+
+	failed := checkoutRule("release-cart", "Cart.release")
+	failed.When.OutputCase = "PaymentFailed"
+
+	guarded := checkoutRule("reserve-priority", "Inventory.reserve")
+	guarded.When.Guard = "result.priority == true"
+
+	rules := []ir.SyncRule{
+		checkoutRule("reserve-stock", "Inventory.reserve"),
+		shipped,
+		failed,
+		guarded,
+	}
+
+	assert.Empty(t, Validate(rules))
+}
+
+func TestValidateSyncRulesPrefixesRuleErrors(t *testing.T) {
+	bad := checkoutRule("bad-scope", "Inventory.reserve")
+	bad.Scope.Mode = "session"
+	bad.When.ActionRef = "Order.place"
+
+	errs := Validate([]ir.SyncRule{checkoutRule("reserve-stock", "Inventory.reserve"), bad})
+	require.Len(t, errs, 1)
+	assert.Equal(t, ErrInvalidScopeMode, errs[0].Code)
+	assert.Equal(t, "syncs[1].scope.mode", errs[0].Field)
+	assert.Empty(t, errs[0].Severity)
+	assert.True(t, HasErrors(errs))
+}
+
+func cartItemSpecs() []ir.ConceptSpec {
+	return []ir.ConceptSpec{stubConcept("Inventory", "reserve", "item_id"), {
+		Name:    "Cart",
+		Purpose: "Manages shopping cart",
+		StateSchema: []ir.StateSchema{{
+			Name:   "CartItem",
+			Fields: map[string]string{"cart_id": "string", "item_id": "string", "quantity": "int"},
+		}},
+		Actions: []ir.ActionSig{{
+			Name:    "checkout",
+			Outputs: []ir.OutputCase{{Case: "Success"}},
+		}},
+	}}
+}
+
+func whereRule(source string, bindings map[string]string) *ir.SyncRule {
+	return &ir.SyncRule{
+		ID:    "reserve-items",
+		Scope: ir.ScopeSpec{Mode: "flow"},
+		When: ir.WhenClause{
+			ActionRef: "Cart.checkout",
+			EventType: "completed",
+			Bindings:  map[string]string{"cart_id": "result.cart_id"},
+		},
+		Where: &ir.WhereClause{
+			Source:   source,
+			Filter:   "cart_id == bound.cart_id",
+			Bindings: bindings,
+		},
+		Then: ir.ThenClause{
+			ActionRef: "Inventory.reserve",
+			Args:      map[string]string{"item_id": "bound.item_id"},
+		},
+	}
+}
+
+func TestValidateSyncRuleWhereSourceValid(t *testing.T) {
+	rule := whereRule("CartItem", map[string]string{"item_id": "item_id", "qty": "quantity"})
+
+	assert.Empty(t, Validate(rule, cartItemSpecs()...))
+}
+
+func TestValidateSyncRuleWhereSourceUnknown(t *testing.T) {
+	rule := whereRule("CartItems", map[string]string{"item_id": "item_id"})
+
+	errs := Validate(rule, cartItemSpecs()...)
+	require.Len(t, errs, 1)
+	assert.Equal(t, ErrUnknownWhereSource, errs[0].Code)
+	assert.Equal(t, "where.source", errs[0].Field)
+}
+
+func TestValidateSyncRuleWhereFieldUnknown(t *testing.T) {
+	rule := whereRule("CartItem", map[string]string{"item_id": "item_id", "qty": "qty"})
+
+	errs := Validate(rule, cartItemSpecs()...)
+	require.Len(t, errs, 1)
+	assert.Equal(t, ErrUnknownWhereField, errs[0].Code)
+	assert.Equal(t, "where.bindings.qty", errs[0].Field)
+	assert.Contains(t, errs[0].Message, `"qty"`)
+}
+
+func TestValidateSyncRuleWhereImplicitColumns(t *testing.T) {
+	rule := whereRule("CartItem", map[string]string{"item_id": "item_id", "row_id": "id", "row_seq": "seq"})
+	rule.Where.Filter = "cart_id == bound.cart_id && seq > 0"
+
+	assert.Empty(t, Validate(rule, cartItemSpecs()...))
+}
+
+func TestValidateSyncRuleWhereFilterFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  string
+		message string
+	}{
+		{"unknown_field", "cart == bound.cart_id", "cart: field does not exist"},
+		{"literal_type_mismatch", "quantity == 'many'", "type mismatch: string predicate on int field"},
+		{"comparison_on_string", "item_id > 3", "type mismatch: int comparison on string field"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := whereRule("CartItem", map[string]string{"item_id": "item_id"})
+			rule.Where.Filter = tt.filter
+
+			errs := Validate(rule, cartItemSpecs()...)
+			require.Len(t, errs, 1)
+			assert.Equal(t, ErrUnknownWhereField, errs[0].Code)
+			assert.Equal(t, "where.filter", errs[0].Field)
+			assert.Contains(t, errs[0].Message, tt.message)
+		})
 	}
 }
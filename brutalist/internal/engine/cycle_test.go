@@ -2,6 +2,7 @@ package engine
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -181,6 +182,41 @@ func TestIsQuotaError(t *testing.T) {
 	assert.False(t, IsQuotaError(assert.AnError))
 }
 
+func TestNewDanglingCompletionError(t *testing.T) {
+	err := NewDanglingCompletionError("comp-1", "inv-missing")
+
+	assert.Equal(t, ErrCodeDanglingCompletion, err.Code)
+	assert.Equal(t, "comp-1", err.CompletionID)
+	assert.Equal(t, "inv-missing", err.InvocationID)
+	assert.Empty(t, err.FlowToken)
+	assert.Contains(t, err.Error(), "DANGLING_COMPLETION")
+	assert.Contains(t, err.Error(), "inv-missing")
+}
+
+func TestIsDanglingCompletionError(t *testing.T) {
+	danglingErr := NewDanglingCompletionError("comp-1", "inv-1")
+	cycleErr := NewCycleError("flow-1", "sync-1", "hash-1")
+
+	assert.True(t, IsDanglingCompletionError(danglingErr))
+	assert.True(t, IsDanglingCompletionError(fmt.Errorf("wrapped: %w", danglingErr)))
+	assert.False(t, IsDanglingCompletionError(cycleErr))
+	assert.False(t, IsDanglingCompletionError(nil))
+	assert.False(t, IsDanglingCompletionError(assert.AnError))
+	assert.False(t, IsCycleError(danglingErr))
+}
+
+func TestErrorCode(t *testing.T) {
+	cycleErr := NewCycleError("flow-1", "sync-1", "hash-1")
+	stepsErr := &StepsExceededError{FlowToken: "flow-1", Steps: 4, Limit: 3}
+
+	assert.Equal(t, ErrCodeCycleDetected, ErrorCode(cycleErr))
+	assert.Equal(t, ErrCodeCycleDetected, ErrorCode(fmt.Errorf("wrapped: %w", cycleErr)))
+	assert.Equal(t, ErrCodeQuotaExceeded, ErrorCode(NewQuotaError("flow-1", 100, 50)))
+	assert.Equal(t, ErrCodeStepsExceeded, ErrorCode(fmt.Errorf("quota: %w", stepsErr)))
+	assert.Equal(t, RuntimeErrorCode(""), ErrorCode(assert.AnError))
+	assert.Equal(t, RuntimeErrorCode(""), ErrorCode(nil))
+}
+
 // =============================================================================
 // Integration Tests with executeThen
 // =============================================================================
@@ -202,27 +238,29 @@ func TestCycle_ExecuteThen_FirstFiringSucceeds(t *testing.T) {
 
 	// Setup invocation and completion
 	inv := ir.Invocation{
-		ID:        "inv-1",
-		FlowToken: "flow-1",
-		ActionURI: "Order.Create",
-		Args:      ir.IRObject{},
-		Seq:       100,
+		ID:              "inv-1",
+		FlowToken:       "flow-1",
+		ActionURI:       "Order.create",
+		Args:            ir.IRObject{},
+		Seq:             100,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteInvocation(ctx, inv))
 
 	comp := ir.Completion{
-		ID:           "comp-1",
-		InvocationID: "inv-1",
-		OutputCase:   "Success",
-		Result:       ir.IRObject{},
-		Seq:          101,
+		ID:              "comp-1",
+		InvocationID:    "inv-1",
+		OutputCase:      "Success",
+		Result:          ir.IRObject{},
+		Seq:             101,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteCompletion(ctx, comp))
 
 	sync := ir.SyncRule{
 		ID: "sync-reserve",
 		Then: ir.ThenClause{
-			ActionRef: "Inventory.Reserve",
+			ActionRef: "Inventory.reserve",
 			Args:      map[string]string{"item": "bound.item_id"},
 		},
 	}
@@ -245,27 +283,29 @@ func TestCycle_ExecuteThen_CycleDetected(t *testing.T) {
 
 	// Setup invocation and completion
 	inv := ir.Invocation{
-		ID:        "inv-1",
-		FlowToken: "flow-1",
-		ActionURI: "Order.Create",
-		Args:      ir.IRObject{},
-		Seq:       100,
+		ID:              "inv-1",
+		FlowToken:       "flow-1",
+		ActionURI:       "Order.create",
+		Args:            ir.IRObject{},
+		Seq:             100,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteInvocation(ctx, inv))
 
 	comp := ir.Completion{
-		ID:           "comp-1",
-		InvocationID: "inv-1",
-		OutputCase:   "Success",
-		Result:       ir.IRObject{},
-		Seq:          101,
+		ID:              "comp-1",
+		InvocationID:    "inv-1",
+		OutputCase:      "Success",
+		Result:          ir.IRObject{},
+		Seq:             101,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteCompletion(ctx, comp))
 
 	sync := ir.SyncRule{
 		ID: "sync-reserve",
 		Then: ir.ThenClause{
-			ActionRef: "Inventory.Reserve",
+			ActionRef: "Inventory.reserve",
 			Args:      map[string]string{"item": "bound.item_id"},
 		},
 	}
@@ -297,46 +337,50 @@ func TestCycle_ExecuteThen_DifferentFlowsIndependent(t *testing.T) {
 
 	// Setup for flow-1
 	inv1 := ir.Invocation{
-		ID:        "inv-1",
-		FlowToken: "flow-1",
-		ActionURI: "Order.Create",
-		Args:      ir.IRObject{},
-		Seq:       100,
+		ID:              "inv-1",
+		FlowToken:       "flow-1",
+		ActionURI:       "Order.create",
+		Args:            ir.IRObject{},
+		Seq:             100,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteInvocation(ctx, inv1))
 
 	comp1 := ir.Completion{
-		ID:           "comp-1",
-		InvocationID: "inv-1",
-		OutputCase:   "Success",
-		Result:       ir.IRObject{},
-		Seq:          101,
+		ID:              "comp-1",
+		InvocationID:    "inv-1",
+		OutputCase:      "Success",
+		Result:          ir.IRObject{},
+		Seq:             101,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteCompletion(ctx, comp1))
 
 	// Setup for flow-2
 	inv2 := ir.Invocation{
-		ID:        "inv-2",
-		FlowToken: "flow-2",
-		ActionURI: "Order.Create",
-		Args:      ir.IRObject{},
-		Seq:       200,
+		ID:              "inv-2",
+		FlowToken:       "flow-2",
+		ActionURI:       "Order.create",
+		Args:            ir.IRObject{},
+		Seq:             200,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteInvocation(ctx, inv2))
 
 	comp2 := ir.Completion{
-		ID:           "comp-2",
-		InvocationID: "inv-2",
-		OutputCase:   "Success",
-		Result:       ir.IRObject{},
-		Seq:          201,
+		ID:              "comp-2",
+		InvocationID:    "inv-2",
+		OutputCase:      "Success",
+		Result:          ir.IRObject{},
+		Seq:             201,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteCompletion(ctx, comp2))
 
 	sync := ir.SyncRule{
 		ID: "sync-reserve",
 		Then: ir.ThenClause{
-			ActionRef: "Inventory.Reserve",
+			ActionRef: "Inventory.reserve",
 			Args:      map[string]string{"item": "bound.item_id"},
 		},
 	}
@@ -364,27 +408,29 @@ func TestCycle_ExecuteThen_DifferentBindingsSameFlowOK(t *testing.T) {
 
 	// Setup
 	inv := ir.Invocation{
-		ID:        "inv-1",
-		FlowToken: "flow-1",
-		ActionURI: "Order.Create",
-		Args:      ir.IRObject{},
-		Seq:       100,
+		ID:              "inv-1",
+		FlowToken:       "flow-1",
+		ActionURI:       "Order.create",
+		Args:            ir.IRObject{},
+		Seq:             100,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteInvocation(ctx, inv))
 
 	comp := ir.Completion{
-		ID:           "comp-1",
-		InvocationID: "inv-1",
-		OutputCase:   "Success",
-		Result:       ir.IRObject{},
-		Seq:          101,
+		ID:              "comp-1",
+		InvocationID:    "inv-1",
+		OutputCase:      "Success",
+		Result:          ir.IRObject{},
+		Seq:             101,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteCompletion(ctx, comp))
 
 	sync := ir.SyncRule{
 		ID: "sync-reserve",
 		Then: ir.ThenClause{
-			ActionRef: "Inventory.Reserve",
+			ActionRef: "Inventory.reserve",
 			Args:      map[string]string{"item": "bound.item_id"},
 		},
 	}
@@ -409,27 +455,29 @@ func TestCycle_ExecuteThen_ClearAfterFlowCompletes(t *testing.T) {
 
 	// Setup
 	inv := ir.Invocation{
-		ID:        "inv-1",
-		FlowToken: "flow-1",
-		ActionURI: "Order.Create",
-		Args:      ir.IRObject{},
-		Seq:       100,
+		ID:              "inv-1",
+		FlowToken:       "flow-1",
+		ActionURI:       "Order.create",
+		Args:            ir.IRObject{},
+		Seq:             100,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteInvocation(ctx, inv))
 
 	comp := ir.Completion{
-		ID:           "comp-1",
-		InvocationID: "inv-1",
-		OutputCase:   "Success",
-		Result:       ir.IRObject{},
-		Seq:          101,
+		ID:              "comp-1",
+		InvocationID:    "inv-1",
+		OutputCase:      "Success",
+		Result:          ir.IRObject{},
+		Seq:             101,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteCompletion(ctx, comp))
 
 	sync := ir.SyncRule{
 		ID: "sync-reserve",
 		Then: ir.ThenClause{
-			ActionRef: "Inventory.Reserve",
+			ActionRef: "Inventory.reserve",
 			Args:      map[string]string{"item": "bound.item_id"},
 		},
 	}
@@ -453,27 +501,29 @@ func TestCycle_ExecuteThen_MultipleBindingsCycleOnSecond(t *testing.T) {
 
 	// Setup
 	inv := ir.Invocation{
-		ID:        "inv-1",
-		FlowToken: "flow-1",
-		ActionURI: "Order.Create",
-		Args:      ir.IRObject{},
-		Seq:       100,
+		ID:              "inv-1",
+		FlowToken:       "flow-1",
+		ActionURI:       "Order.create",
+		Args:            ir.IRObject{},
+		Seq:             100,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteInvocation(ctx, inv))
 
 	comp := ir.Completion{
-		ID:           "comp-1",
-		InvocationID: "inv-1",
-		OutputCase:   "Success",
-		Result:       ir.IRObject{},
-		Seq:          101,
+		ID:              "comp-1",
+		InvocationID:    "inv-1",
+		OutputCase:      "Success",
+		Result:          ir.IRObject{},
+		Seq:             101,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteCompletion(ctx, comp))
 
 	sync := ir.SyncRule{
 		ID: "sync-reserve",
 		Then: ir.ThenClause{
-			ActionRef: "Inventory.Reserve",
+			ActionRef: "Inventory.reserve",
 			Args:      map[string]string{"item": "bound.item_id"},
 		},
 	}
@@ -502,35 +552,37 @@ func TestCycle_ExecuteThen_MultipleBindingsCycleOnSecond(t *testing.T) {
 
 func TestCycle_Scenario_SelfReferentialSync(t *testing.T) {
 	// Scenario: A sync rule that triggers itself
-	// Order.Create → sync-create-order → Order.Create (cycle!)
+	// Order.create → sync-create-order → Order.create (cycle!)
 
 	e, s := setupCycleTestEngine(t)
 	ctx := context.Background()
 
 	// Setup original invocation
 	inv := ir.Invocation{
-		ID:        "inv-1",
-		FlowToken: "flow-1",
-		ActionURI: "Order.Create",
-		Args:      ir.IRObject{"order_id": ir.IRString("order-123")},
-		Seq:       100,
+		ID:              "inv-1",
+		FlowToken:       "flow-1",
+		ActionURI:       "Order.create",
+		Args:            ir.IRObject{"order_id": ir.IRString("order-123")},
+		Seq:             100,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteInvocation(ctx, inv))
 
 	comp := ir.Completion{
-		ID:           "comp-1",
-		InvocationID: "inv-1",
-		OutputCase:   "Success",
-		Result:       ir.IRObject{"order_id": ir.IRString("order-123")},
-		Seq:          101,
+		ID:              "comp-1",
+		InvocationID:    "inv-1",
+		OutputCase:      "Success",
+		Result:          ir.IRObject{"order_id": ir.IRString("order-123")},
+		Seq:             101,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteCompletion(ctx, comp))
 
-	// Self-referential sync: Order.Create triggers Order.Create
+	// Self-referential sync: Order.create triggers Order.create
 	selfSync := ir.SyncRule{
 		ID: "sync-create-order",
 		Then: ir.ThenClause{
-			ActionRef: "Order.Create",
+			ActionRef: "Order.create",
 			Args:      map[string]string{"order_id": "bound.order_id"},
 		},
 	}
@@ -550,37 +602,88 @@ func TestCycle_Scenario_SelfReferentialSync(t *testing.T) {
 	assert.True(t, IsCycleError(err))
 }
 
+func TestCycle_ProcessCompletion_SelfReferentialSync(t *testing.T) {
+	// Two completions of Order.create in one flow bind the same order_id, so
+	// the self-referential sync would fire the same binding twice.
+	e, s := setupCycleTestEngine(t)
+	ctx := context.Background()
+
+	require.NoError(t, e.RegisterSyncs([]ir.SyncRule{{
+		ID: "sync-create-order",
+		When: ir.WhenClause{
+			ActionRef: "Order.create",
+			EventType: "completed",
+			Bindings:  map[string]string{"order_id": "order_id"},
+		},
+		Then: ir.ThenClause{
+			ActionRef: "Order.create",
+			Args:      map[string]string{"order_id": "${bound.order_id}"},
+		},
+	}}))
+
+	for i, id := range []string{"1", "2"} {
+		inv := ir.Invocation{
+			ID:              "inv-" + id,
+			FlowToken:       "flow-1",
+			ActionURI:       "Order.create",
+			Args:            ir.IRObject{"order_id": ir.IRString("order-123")},
+			Seq:             int64(100 + i*10),
+			SecurityContext: testSecurityContext,
+		}
+		require.NoError(t, s.WriteInvocation(ctx, inv))
+
+		comp := &ir.Completion{
+			ID:              "comp-" + id,
+			InvocationID:    inv.ID,
+			OutputCase:      "Success",
+			Result:          ir.IRObject{"order_id": ir.IRString("order-123")},
+			Seq:             int64(101 + i*10),
+			SecurityContext: testSecurityContext,
+		}
+		err := e.ProcessCompletion(ctx, comp)
+		if i == 0 {
+			require.NoError(t, err)
+			continue
+		}
+		require.Error(t, err)
+		assert.True(t, IsCycleError(err))
+		assert.Equal(t, ErrCodeCycleDetected, ErrorCode(err))
+	}
+}
+
 func TestCycle_Scenario_MutuallyRecursive(t *testing.T) {
 	// Scenario: Two syncs that trigger each other
-	// Order.Create → sync-A → Inventory.Reserve
-	// Inventory.Reserve → sync-B → Order.Create (same binding = cycle!)
+	// Order.create → sync-A → Inventory.reserve
+	// Inventory.reserve → sync-B → Order.create (same binding = cycle!)
 
 	e, s := setupCycleTestEngine(t)
 	ctx := context.Background()
 
 	// Setup for sync-A
 	inv1 := ir.Invocation{
-		ID:        "inv-1",
-		FlowToken: "flow-1",
-		ActionURI: "Order.Create",
-		Args:      ir.IRObject{"item_id": ir.IRString("widget")},
-		Seq:       100,
+		ID:              "inv-1",
+		FlowToken:       "flow-1",
+		ActionURI:       "Order.create",
+		Args:            ir.IRObject{"item_id": ir.IRString("widget")},
+		Seq:             100,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteInvocation(ctx, inv1))
 
 	comp1 := ir.Completion{
-		ID:           "comp-1",
-		InvocationID: "inv-1",
-		OutputCase:   "Success",
-		Result:       ir.IRObject{"item_id": ir.IRString("widget")},
-		Seq:          101,
+		ID:              "comp-1",
+		InvocationID:    "inv-1",
+		OutputCase:      "Success",
+		Result:          ir.IRObject{"item_id": ir.IRString("widget")},
+		Seq:             101,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteCompletion(ctx, comp1))
 
 	syncA := ir.SyncRule{
 		ID: "sync-A",
 		Then: ir.ThenClause{
-			ActionRef: "Inventory.Reserve",
+			ActionRef: "Inventory.reserve",
 			Args:      map[string]string{"item": "bound.item_id"},
 		},
 	}
@@ -588,7 +691,7 @@ func TestCycle_Scenario_MutuallyRecursive(t *testing.T) {
 	syncB := ir.SyncRule{
 		ID: "sync-B",
 		Then: ir.ThenClause{
-			ActionRef: "Order.Create",
+			ActionRef: "Order.create",
 			Args:      map[string]string{"item_id": "bound.item_id"},
 		},
 	}
@@ -599,22 +702,24 @@ func TestCycle_Scenario_MutuallyRecursive(t *testing.T) {
 	err := e.executeThen(ctx, syncA.Then, bindings, "flow-1", comp1, syncA)
 	require.NoError(t, err)
 
-	// Simulate Inventory.Reserve completion
+	// Simulate Inventory.reserve completion
 	inv2 := ir.Invocation{
-		ID:        "inv-2",
-		FlowToken: "flow-1",
-		ActionURI: "Inventory.Reserve",
-		Args:      ir.IRObject{"item": ir.IRString("widget")},
-		Seq:       102,
+		ID:              "inv-2",
+		FlowToken:       "flow-1",
+		ActionURI:       "Inventory.reserve",
+		Args:            ir.IRObject{"item": ir.IRString("widget")},
+		Seq:             102,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteInvocation(ctx, inv2))
 
 	comp2 := ir.Completion{
-		ID:           "comp-2",
-		InvocationID: "inv-2",
-		OutputCase:   "Success",
-		Result:       ir.IRObject{"item_id": ir.IRString("widget")},
-		Seq:          103,
+		ID:              "comp-2",
+		InvocationID:    "inv-2",
+		OutputCase:      "Success",
+		Result:          ir.IRObject{"item_id": ir.IRString("widget")},
+		Seq:             103,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteCompletion(ctx, comp2))
 
@@ -637,14 +742,14 @@ func TestCycle_Scenario_DistinctFlowsConcurrent(t *testing.T) {
 	ctx := context.Background()
 
 	// Setup flow-1
-	inv1 := ir.Invocation{ID: "inv-1", FlowToken: "flow-1", ActionURI: "A", Args: ir.IRObject{}, Seq: 100}
-	comp1 := ir.Completion{ID: "comp-1", InvocationID: "inv-1", OutputCase: "Success", Result: ir.IRObject{}, Seq: 101}
+	inv1 := ir.Invocation{ID: "inv-1", FlowToken: "flow-1", ActionURI: "A", Args: ir.IRObject{}, Seq: 100, SecurityContext: testSecurityContext}
+	comp1 := ir.Completion{ID: "comp-1", InvocationID: "inv-1", OutputCase: "Success", Result: ir.IRObject{}, Seq: 101, SecurityContext: testSecurityContext}
 	require.NoError(t, s.WriteInvocation(ctx, inv1))
 	require.NoError(t, s.WriteCompletion(ctx, comp1))
 
 	// Setup flow-2
-	inv2 := ir.Invocation{ID: "inv-2", FlowToken: "flow-2", ActionURI: "A", Args: ir.IRObject{}, Seq: 200}
-	comp2 := ir.Completion{ID: "comp-2", InvocationID: "inv-2", OutputCase: "Success", Result: ir.IRObject{}, Seq: 201}
+	inv2 := ir.Invocation{ID: "inv-2", FlowToken: "flow-2", ActionURI: "A", Args: ir.IRObject{}, Seq: 200, SecurityContext: testSecurityContext}
+	comp2 := ir.Completion{ID: "comp-2", InvocationID: "inv-2", OutputCase: "Success", Result: ir.IRObject{}, Seq: 201, SecurityContext: testSecurityContext}
 	require.NoError(t, s.WriteInvocation(ctx, inv2))
 	require.NoError(t, s.WriteCompletion(ctx, comp2))
 
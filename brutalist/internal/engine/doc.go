@@ -33,7 +33,8 @@
 // NEVER use wall-clock timestamps for ordering.
 //
 // CRITICAL-3: Deterministic Scheduling
-// Sync rules evaluated in declaration order.
+// Sync rules evaluated in declaration order, after any with an explicit
+// priority (lowest first, ties by sync ID).
 // Query results processed in ORDER BY seq, id order.
 // No randomness, no concurrency, no non-determinism.
 package engine
@@ -0,0 +1,131 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+// PlannedFiring is one sync firing that DryRun predicts a completion would
+// produce. It carries everything fireSyncRule would write except the
+// seq-dependent parts (invocation ID, seq), which are only assigned when the
+// firing actually happens.
+type PlannedFiring struct {
+	SyncID      string
+	Bindings    ir.IRObject // Merged when+where binding set
+	BindingHash string      // ir.BindingHash(Bindings), the CP-1 idempotency key
+	ActionURI   ir.ActionRef
+	Args        ir.IRObject // Then-clause args resolved against Bindings
+
+	// AlreadyFired is true if the store already records this
+	// (completion, sync, binding) firing; processing would skip it (CP-1).
+	AlreadyFired bool
+
+	// Vetoed is true if the invocation validator rejects the generated
+	// invocation (see WithInvocationValidator); processing would write
+	// nothing for this binding.
+	Vetoed bool
+}
+
+// DryRun previews the sync firings that processing comp would produce,
+// without writing anything.
+//
+// It runs the same pipeline as evaluateSyncs - when matching (including
+// guards), binding extraction, scoped where-clause execution, and then-arg
+// resolution - in declaration order (CRITICAL-3), with one PlannedFiring per
+// binding set. Syncs whose bindings, where-clause, or args fail are skipped,
+// as evaluateSyncs logs and skips them. Bindings the invocation validator
+// would veto are returned with Vetoed set; the validator sees the invocation
+// without its seq-dependent fields (ID and Seq are zero).
+//
+// The store is only read: the completion need not be written yet, but the
+// invocation it completes must exist (for action matching and the flow
+// token). The clock, cycle detector, queue, metrics, and listener are left
+// untouched. If processing would fail with a cycle or binding explosion
+// error, DryRun returns that error.
+//
+// Like ProcessCompletion, it must not be called concurrently with Run.
+func (e *Engine) DryRun(ctx context.Context, comp ir.Completion) ([]PlannedFiring, error) {
+	inv, err := e.store.ReadInvocation(ctx, comp.InvocationID)
+	if err != nil {
+		return nil, fmt.Errorf("dry run: read invocation %s: %w", comp.InvocationID, err)
+	}
+	flowToken := inv.FlowToken
+
+	planned := []PlannedFiring{}
+	for _, sync := range e.syncs {
+		if !e.matchSync(sync, &inv, &comp) {
+			continue
+		}
+
+		bindings, err := extractBindings(sync.When, &comp)
+		if err != nil {
+			slog.Debug("dry run: binding extraction failed", "sync_id", sync.ID, "error", err)
+			continue
+		}
+
+		bindingSets, err := e.executeWhereClause(ctx, sync, flowToken, bindings)
+		if err != nil {
+			if IsBindingExplosionError(err) {
+				return nil, err
+			}
+			slog.Debug("dry run: where-clause execution failed", "sync_id", sync.ID, "error", err)
+			continue
+		}
+
+		for _, bindingSet := range bindingSets {
+			p, err := e.planFiring(ctx, sync, &comp, flowToken, bindingSet)
+			if err != nil {
+				if IsCycleError(err) {
+					return nil, err
+				}
+				slog.Debug("dry run: sync rule would fail", "sync_id", sync.ID, "error", err)
+				continue
+			}
+			planned = append(planned, p)
+		}
+	}
+
+	return planned, nil
+}
+
+// planFiring is the read-only counterpart of fireSyncRule: the same
+// idempotency, cycle, and validator checks, then invocation generation,
+// without consuming a seq. A veto is not logged, counted, or reported.
+func (e *Engine) planFiring(ctx context.Context, sync ir.SyncRule, comp *ir.Completion, flowToken string, bindings ir.IRObject) (PlannedFiring, error) {
+	bindingHash, err := ir.BindingHash(bindings)
+	if err != nil {
+		return PlannedFiring{}, fmt.Errorf("compute binding hash: %w", err)
+	}
+
+	fired, err := e.store.HasFiring(ctx, comp.ID, sync.ID, bindingHash)
+	if err != nil {
+		return PlannedFiring{}, fmt.Errorf("check firing: %w", err)
+	}
+	if !fired && e.cycleDetector.WouldCycle(flowToken, sync.ID, bindingHash) {
+		return PlannedFiring{}, NewCycleError(flowToken, sync.ID, bindingHash)
+	}
+
+	inv, err := e.generateInvocationAt(flowToken, comp.SecurityContext, sync.Then, bindings, func() int64 {
+		return 0
+	})
+	if err != nil {
+		return PlannedFiring{}, fmt.Errorf("generate invocation: %w", err)
+	}
+	inv.ID = ""
+
+	// Bindings that already fired are not re-validated, as in fireSyncRule
+	vetoed := !fired && e.invocationValidator != nil && e.invocationValidator(inv) != nil
+
+	return PlannedFiring{
+		SyncID:       sync.ID,
+		Bindings:     bindings,
+		BindingHash:  bindingHash,
+		ActionURI:    inv.ActionURI,
+		Args:         inv.Args,
+		AlreadyFired: fired,
+		Vetoed:       vetoed,
+	}, nil
+}
@@ -0,0 +1,226 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/roach88/nysm/internal/ir"
+	"github.com/roach88/nysm/internal/store"
+)
+
+// dryRunSyncs fans Cart.checkout out over CartItems and also notifies once;
+// the third rule only matches a Failure completion.
+var dryRunSyncs = []ir.SyncRule{
+	{
+		ID: "reserve-each-item",
+		When: ir.WhenClause{
+			ActionRef: "Cart.checkout",
+			EventType: "completed",
+			Bindings:  map[string]string{"cartId": "cart_id"},
+		},
+		Where: &ir.WhereClause{
+			Source:   "CartItems",
+			Filter:   "cart_id == bound.cartId",
+			Bindings: map[string]string{"item_id": "itemId"},
+		},
+		Then: ir.ThenClause{
+			ActionRef: "Inventory.reserve",
+			Args:      map[string]string{"item": "${bound.itemId}", "cart": "${bound.cartId}"},
+		},
+	},
+	{
+		ID: "notify-checkout",
+		When: ir.WhenClause{
+			ActionRef: "Cart.checkout",
+			EventType: "completed",
+			Bindings:  map[string]string{"cartId": "cart_id"},
+		},
+		Then: ir.ThenClause{
+			ActionRef: "Notify.send",
+			Args:      map[string]string{"cart": "${bound.cartId}", "kind": "checkout"},
+		},
+	},
+	{
+		ID: "on-checkout-failure",
+		When: ir.WhenClause{
+			ActionRef:  "Cart.checkout",
+			EventType:  "completed",
+			OutputCase: "Failure",
+		},
+		Then: ir.ThenClause{ActionRef: "Notify.send"},
+	},
+}
+
+// setupDryRun seeds CartItems and a pending Cart.checkout invocation, and
+// returns the engine plus a (not yet written) Success completion for it.
+func setupDryRun(t *testing.T) (*Engine, *store.Store, ir.Completion) {
+	t.Helper()
+	s := setupTestStore(t)
+	ctx := context.Background()
+
+	_, err := s.DB().ExecContext(ctx, `
+		CREATE TABLE CartItems (id TEXT PRIMARY KEY, seq INTEGER, flow_token TEXT, cart_id TEXT, item_id TEXT);
+		INSERT INTO CartItems VALUES
+			('ci-1', 1, 'flow-1', 'cart-1', 'widget'),
+			('ci-2', 2, 'flow-1', 'cart-1', 'gadget'),
+			('ci-3', 3, 'flow-1', 'cart-2', 'gizmo');
+	`)
+	require.NoError(t, err)
+
+	args := ir.IRObject{}
+	invID := ir.MustInvocationID("flow-1", "Cart.checkout", args, 1)
+	require.NoError(t, s.WriteInvocation(ctx, ir.Invocation{
+		ID:              invID,
+		FlowToken:       "flow-1",
+		ActionURI:       "Cart.checkout",
+		Args:            args,
+		Seq:             1,
+		EngineVersion:   ir.EngineVersion,
+		IRVersion:       ir.IRVersion,
+		SecurityContext: testSecurityContext,
+	}))
+
+	result := ir.IRObject{"cart_id": ir.IRString("cart-1")}
+	comp := ir.Completion{
+		ID:              ir.MustCompletionID(invID, "Success", result, 2),
+		InvocationID:    invID,
+		OutputCase:      "Success",
+		Result:          result,
+		Seq:             2,
+		SecurityContext: testSecurityContext,
+	}
+
+	return NewWithClock(s, nil, dryRunSyncs, nil, NewClockAt(2)), s, comp
+}
+
+func TestDryRun_MatchesProcessedFirings(t *testing.T) {
+	e, s, comp := setupDryRun(t)
+	ctx := context.Background()
+
+	planned, err := e.DryRun(ctx, comp)
+	require.NoError(t, err)
+	require.Len(t, planned, 3)
+
+	assert.Equal(t, PlannedFiring{
+		SyncID:      "reserve-each-item",
+		Bindings:    ir.IRObject{"cartId": ir.IRString("cart-1"), "itemId": ir.IRString("gadget")},
+		BindingHash: ir.MustBindingHash(ir.IRObject{"cartId": ir.IRString("cart-1"), "itemId": ir.IRString("gadget")}),
+		ActionURI:   "Inventory.reserve",
+		Args:        ir.IRObject{"item": ir.IRString("gadget"), "cart": ir.IRString("cart-1")},
+	}, planned[0])
+
+	// Now process for real and compare
+	require.NoError(t, e.ProcessCompletion(ctx, &comp))
+
+	firings, err := s.ReadSyncFiringsForCompletion(ctx, comp.ID)
+	require.NoError(t, err)
+	pending, err := s.GetPendingInvocations(ctx, "flow-1")
+	require.NoError(t, err)
+	require.Len(t, firings, len(planned))
+	require.Len(t, pending, len(planned))
+
+	for i, p := range planned {
+		assert.Equal(t, firings[i].SyncID, p.SyncID, "firing %d", i)
+		assert.Equal(t, firings[i].BindingHash, p.BindingHash, "firing %d", i)
+		assert.Equal(t, pending[i].ActionURI, p.ActionURI, "invocation %d", i)
+		assert.Equal(t, pending[i].Args, p.Args, "invocation %d", i)
+		assert.False(t, p.AlreadyFired)
+	}
+}
+
+func TestDryRun_DoesNotWriteOrAdvanceState(t *testing.T) {
+	e, s, comp := setupDryRun(t)
+	ctx := context.Background()
+
+	before, err := s.SizeStats(ctx)
+	require.NoError(t, err)
+	clockBefore := e.clock.Current()
+
+	_, err = e.DryRun(ctx, comp)
+	require.NoError(t, err)
+
+	after, err := s.SizeStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, before.Invocations, after.Invocations)
+	assert.Equal(t, before.Completions, after.Completions)
+	assert.Equal(t, before.SyncFirings, after.SyncFirings)
+	assert.Equal(t, before.ProvenanceEdges, after.ProvenanceEdges)
+
+	assert.Equal(t, clockBefore, e.clock.Current(), "clock must not advance")
+	assert.Equal(t, 0, e.queue.Len(), "queue must stay empty")
+	assert.Zero(t, e.Metrics().SyncsFired())
+
+	for _, sync := range dryRunSyncs {
+		for _, h := range []ir.IRObject{
+			{"cartId": ir.IRString("cart-1"), "itemId": ir.IRString("widget")},
+			{"cartId": ir.IRString("cart-1")},
+		} {
+			assert.False(t, e.cycleDetector.WouldCycle("flow-1", sync.ID, ir.MustBindingHash(h)),
+				"cycle detector must not record %s", sync.ID)
+		}
+	}
+}
+
+func TestDryRun_AfterProcessingReportsAlreadyFired(t *testing.T) {
+	e, _, comp := setupDryRun(t)
+	ctx := context.Background()
+
+	require.NoError(t, e.ProcessCompletion(ctx, &comp))
+
+	planned, err := e.DryRun(ctx, comp)
+	require.NoError(t, err)
+	require.Len(t, planned, 3)
+	for _, p := range planned {
+		assert.True(t, p.AlreadyFired, "sync %s", p.SyncID)
+	}
+}
+
+func TestDryRun_MarksVetoedFirings(t *testing.T) {
+	e, s, comp := setupDryRun(t)
+	ctx := context.Background()
+	e.invocationValidator = func(inv ir.Invocation) error {
+		if inv.Args["item"] == ir.IRString("gadget") {
+			return errors.New("gadget is discontinued")
+		}
+		return nil
+	}
+
+	planned, err := e.DryRun(ctx, comp)
+	require.NoError(t, err)
+	require.Len(t, planned, 3)
+	assert.Equal(t, ir.IRString("gadget"), planned[0].Args["item"])
+	assert.True(t, planned[0].Vetoed)
+	assert.False(t, planned[1].Vetoed)
+	assert.False(t, planned[2].Vetoed)
+	assert.Zero(t, e.Metrics().InvocationsRejected(), "dry run must not count rejections")
+
+	// Processing writes exactly the firings that were not vetoed
+	require.NoError(t, e.ProcessCompletion(ctx, &comp))
+	firings, err := s.ReadSyncFiringsForCompletion(ctx, comp.ID)
+	require.NoError(t, err)
+	require.Len(t, firings, 2)
+	assert.Equal(t, planned[1].BindingHash, firings[0].BindingHash)
+	assert.Equal(t, planned[2].BindingHash, firings[1].BindingHash)
+}
+
+func TestDryRun_NoMatchingSyncs(t *testing.T) {
+	e, _, comp := setupDryRun(t)
+	require.NoError(t, e.RegisterSyncs(dryRunSyncs[2:])) // Failure-only rule
+
+	planned, err := e.DryRun(context.Background(), comp)
+	require.NoError(t, err)
+	assert.NotNil(t, planned)
+	assert.Empty(t, planned)
+}
+
+func TestDryRun_UnknownInvocation(t *testing.T) {
+	e, _, comp := setupDryRun(t)
+
+	comp.InvocationID = "missing"
+	_, err := e.DryRun(context.Background(), comp)
+	require.Error(t, err)
+}
@@ -2,10 +2,13 @@ package engine
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
 
 	"github.com/roach88/nysm/internal/ir"
+	"github.com/roach88/nysm/internal/queryir"
 	"github.com/roach88/nysm/internal/store"
 )
 
@@ -26,6 +29,7 @@ type FlowTokenGenerator interface {
 //
 // Thread-safety model:
 //   - Enqueue(): safe from any goroutine
+//   - Pause()/Resume(): safe from any goroutine
 //   - Run(): must be called from exactly one goroutine
 //   - NewFlow(): safe from any goroutine (delegates to thread-safe generator)
 //
@@ -38,22 +42,52 @@ type FlowTokenGenerator interface {
 // This prevents runaway flows from consuming unbounded resources.
 const DefaultMaxSteps = 1000
 
+// DefaultMaxBindingsPerFiring is the default cap on binding sets a single
+// where-clause may produce for one sync evaluation. Each binding set becomes
+// one invocation, so this bounds accidental fan-out.
+const DefaultMaxBindingsPerFiring = 1000
+
 // Engine is defined with fields for event processing, sync rules,
 // cycle detection, and quota enforcement.
 type Engine struct {
 	store         *store.Store
 	clock         *Clock
 	specs         []ir.ConceptSpec
-	syncs         []ir.SyncRule // Sync rules in declaration order (CRITICAL-3)
+	syncs         []ir.SyncRule                // Sync rules in evaluation order (CRITICAL-3)
+	guards        map[string]queryir.Predicate // Parsed when-guards by sync ID (see guard.go)
 	queue         *eventQueue
 	flowGen       FlowTokenGenerator
 	specHash      string // Hash of concept specs for versioning
 	cycleDetector *CycleDetector
 
+	// Skip the spec hash check before replay (see spechash.go)
+	allowSpecDrift bool
+
+	// Veto for generated invocations (nil = none, see validator.go)
+	invocationValidator InvocationValidator
+
 	// Quota enforcement (Story 5.4)
 	maxSteps int                        // Maximum steps per flow (default: 1000)
 	quotas   map[string]*QuotaEnforcer // Per-flow quota trackers
 
+	// Where-clause fan-out cap (default: DefaultMaxBindingsPerFiring)
+	maxBindingsPerFiring int
+
+	listener EventListener // Lifecycle observer (see listener.go)
+	metrics  *Metrics      // Aggregate counters (see metrics.go)
+
+	// Logical-clock flow timeouts (nil = disabled, see timeout.go)
+	timeouts *flowTimeoutTracker
+
+	// Per-tenant step budget (nil = disabled, see ratelimit.go)
+	tenantLimiter *tenantRateLimiter
+
+	// Operator pause/resume of the Run loop (see pause.go)
+	pause pauseGate
+
+	// Sync firings deferred by ThenClause.AfterSteps (see schedule.go)
+	schedule firingSchedule
+
 	// TODO (Story 4.1): Query IR compiler
 	// compiler queryir.Compiler
 }
@@ -72,15 +106,48 @@ func WithMaxSteps(maxSteps int) EngineOption {
 	}
 }
 
+// WithMaxBindingsPerFiring caps how many binding sets a where-clause may
+// return for one sync evaluation. A query exceeding the cap is rejected with
+// ErrCodeBindingExplosion instead of generating one invocation per row.
+//
+// Default: 1000 (DefaultMaxBindingsPerFiring). Values <= 0 keep the default;
+// the cap is always finite.
+func WithMaxBindingsPerFiring(n int) EngineOption {
+	return func(e *Engine) {
+		if n > 0 {
+			e.maxBindingsPerFiring = n
+		}
+	}
+}
+
+// WithQueueCapacity bounds the number of pending events the queue accepts
+// from Enqueue. Once n events are waiting, Enqueue returns ErrQueueFull until
+// the Run loop drains some, letting producers apply backpressure instead of
+// growing the queue without limit.
+//
+// Invocations generated by sync firings are always enqueued, so a full queue
+// never drops part of a flow; they may temporarily push the queue past n.
+// Processing stays single-threaded FIFO, so ordering is unaffected.
+//
+// Default: unbounded. Values <= 0 keep the queue unbounded.
+func WithQueueCapacity(n int) EngineOption {
+	return func(e *Engine) {
+		if n > 0 {
+			e.queue.capacity = n
+		}
+	}
+}
+
 // New creates an Engine with the given store, specs, syncs, and flow generator.
 //
-// The syncs slice must be in declaration order - this order is preserved for
-// deterministic sync rule evaluation (CRITICAL-3).
+// The syncs slice must be in declaration order. Rules are evaluated in that
+// order, except that rules with a Priority run first (see ir.SortSyncRules),
+// for deterministic sync rule evaluation (CRITICAL-3).
 //
 // The syncs slice is copied to prevent external mutation from breaking
-// the declaration order invariant.
+// the evaluation order invariant.
 //
-// Options can be passed to configure the engine (e.g., WithMaxSteps).
+// Options can be passed to configure the engine (e.g., WithMaxSteps, WithListener).
 func New(
 	s *store.Store,
 	specs []ir.ConceptSpec,
@@ -93,6 +160,14 @@ func New(
 	if syncs != nil {
 		syncsCopy = make([]ir.SyncRule, len(syncs))
 		copy(syncsCopy, syncs)
+		ir.SortSyncRules(syncsCopy)
+	}
+
+	// Constructors cannot fail: syncs with malformed guards are logged and
+	// never fire (RegisterSyncs rejects them instead)
+	guards, err := parseGuards(syncsCopy)
+	if err != nil {
+		slog.Warn("malformed sync guards, those syncs will not fire", "error", err)
 	}
 
 	e := &Engine{
@@ -100,11 +175,16 @@ func New(
 		clock:         NewClock(),
 		specs:         specs,
 		syncs:         syncsCopy,
+		guards:        guards,
 		queue:         newEventQueue(),
 		flowGen:       flowGen,
 		cycleDetector: NewCycleDetector(),
 		maxSteps:      DefaultMaxSteps,
 		quotas:        make(map[string]*QuotaEnforcer),
+		listener:      NopListener{},
+		metrics:       &Metrics{},
+
+		maxBindingsPerFiring: DefaultMaxBindingsPerFiring,
 	}
 
 	// Apply options
@@ -120,7 +200,7 @@ func New(
 //
 // The syncs slice is copied to prevent external mutation (CRITICAL-3 protection).
 //
-// Options can be passed to configure the engine (e.g., WithMaxSteps).
+// Options can be passed to configure the engine (e.g., WithMaxSteps, WithListener).
 func NewWithClock(
 	s *store.Store,
 	specs []ir.ConceptSpec,
@@ -134,6 +214,14 @@ func NewWithClock(
 	if syncs != nil {
 		syncsCopy = make([]ir.SyncRule, len(syncs))
 		copy(syncsCopy, syncs)
+		ir.SortSyncRules(syncsCopy)
+	}
+
+	// Constructors cannot fail: syncs with malformed guards are logged and
+	// never fire (RegisterSyncs rejects them instead)
+	guards, err := parseGuards(syncsCopy)
+	if err != nil {
+		slog.Warn("malformed sync guards, those syncs will not fire", "error", err)
 	}
 
 	e := &Engine{
@@ -141,11 +229,16 @@ func NewWithClock(
 		clock:         clock,
 		specs:         specs,
 		syncs:         syncsCopy,
+		guards:        guards,
 		queue:         newEventQueue(),
 		flowGen:       flowGen,
 		cycleDetector: NewCycleDetector(),
 		maxSteps:      DefaultMaxSteps,
 		quotas:        make(map[string]*QuotaEnforcer),
+		listener:      NopListener{},
+		metrics:       &Metrics{},
+
+		maxBindingsPerFiring: DefaultMaxBindingsPerFiring,
 	}
 
 	// Apply options
@@ -159,9 +252,11 @@ func NewWithClock(
 // Enqueue submits an event for processing by the Run loop.
 // Thread-safe: may be called from any goroutine.
 //
-// Returns false if the engine has been stopped.
-func (e *Engine) Enqueue(ev Event) bool {
-	return e.queue.Enqueue(ev)
+// Returns ErrStopped if the engine has been stopped, or ErrQueueFull if the
+// queue is bounded (see WithQueueCapacity) and at capacity. In both cases
+// the event was not accepted.
+func (e *Engine) Enqueue(ev Event) error {
+	return e.queue.TryEnqueue(ev)
 }
 
 // NewFlow generates a new flow token for an external request.
@@ -176,6 +271,10 @@ func (e *Engine) NewFlow() string {
 
 // Run starts the single-writer event loop.
 // Blocks until context is cancelled or Stop() is called.
+// While paused (see Pause), Run waits without dequeuing.
+//
+// On start, Run reloads firings scheduled by a previous run (see
+// schedule.go); failing to read them is returned as an error.
 //
 // CRITICAL: Must be called from exactly ONE goroutine.
 // All event processing, store writes, and sync rule evaluation
@@ -188,7 +287,31 @@ func (e *Engine) NewFlow() string {
 func (e *Engine) Run(ctx context.Context) error {
 	slog.Info("engine starting")
 
+	// Firings deferred by a previous run (see schedule.go)
+	if err := e.loadSchedule(ctx); err != nil {
+		return err
+	}
+
 	for {
+		// While paused, wait for Resume, Stop, or cancellation
+		if resumed := e.pause.wait(); resumed != nil {
+			select {
+			case <-ctx.Done():
+				slog.Info("engine stopping: context cancelled")
+				e.queue.Close()
+				return ctx.Err()
+			case <-resumed:
+			case <-e.queue.Wait():
+				// Enqueue signals are ignored while paused; a closed
+				// queue means Stop was called
+				if e.queue.Closed() {
+					slog.Info("engine stopping: queue closed while paused")
+					return nil
+				}
+			}
+			continue
+		}
+
 		// Try non-blocking dequeue first
 		event, ok := e.queue.TryDequeue()
 		if ok {
@@ -240,7 +363,7 @@ func (e *Engine) processEvent(ctx context.Context, event Event) error {
 		if event.Completion == nil {
 			return fmt.Errorf("completion event missing completion data")
 		}
-		return e.processCompletion(ctx, event.Completion)
+		return e.processCompletion(ctx, event.Completion, false)
 
 	default:
 		return fmt.Errorf("unknown event type: %d", event.Type)
@@ -251,6 +374,7 @@ func (e *Engine) processEvent(ctx context.Context, event Event) error {
 // Writes the invocation to the store.
 // CRITICAL: Called only from Run() goroutine - single-writer guarantee.
 func (e *Engine) processInvocation(ctx context.Context, inv *ir.Invocation) error {
+	e.metrics.eventsProcessed.Add(1)
 	slog.Debug("processing invocation",
 		"id", inv.ID,
 		"action", inv.ActionURI,
@@ -268,6 +392,11 @@ func (e *Engine) processInvocation(ctx context.Context, inv *ir.Invocation) erro
 		"action", inv.ActionURI,
 		"flow", inv.FlowToken,
 	)
+	e.listener.OnInvocation(*inv)
+
+	e.timeouts.invoked(inv.FlowToken, inv.ID, inv.Seq)
+	e.expireIdleFlows(inv.Seq)
+	e.releaseScheduledFirings(ctx, inv.Seq)
 
 	return nil
 }
@@ -278,8 +407,20 @@ func (e *Engine) processInvocation(ctx context.Context, inv *ir.Invocation) erro
 //
 // QUOTA ENFORCEMENT (Story 5.4): Each completion counts against the flow's
 // quota. If the quota is exceeded, sync rules are NOT evaluated and the
-// flow terminates with StepsExceededError.
-func (e *Engine) processCompletion(ctx context.Context, comp *ir.Completion) error {
+// flow terminates with StepsExceededError. With WithTenantRateLimit, the
+// completion also counts against its tenant's budget and is rejected with
+// ErrCodeTenantRateLimited once the budget is spent.
+//
+// If the originating invocation is not in the store, returns a RuntimeError
+// with ErrCodeDanglingCompletion and the completion is not written.
+//
+// DUPLICATE SUBMISSION: An external executor may submit the same completion
+// twice (network retry). Unless reevaluate is set, a completion the store
+// already holds is dropped at the event boundary: it does not count against
+// quotas and sync rules are not evaluated again. ProcessCompletion sets
+// reevaluate so replay can re-run sync evaluation for stored completions.
+func (e *Engine) processCompletion(ctx context.Context, comp *ir.Completion, reevaluate bool) error {
+	e.metrics.eventsProcessed.Add(1)
 	slog.Debug("processing completion",
 		"id", comp.ID,
 		"invocation_id", comp.InvocationID,
@@ -287,23 +428,56 @@ func (e *Engine) processCompletion(ctx context.Context, comp *ir.Completion) err
 		"seq", comp.Seq,
 	)
 
+	// Get the flow token from the originating invocation. This happens before
+	// the write so a dangling completion surfaces as a typed RuntimeError
+	// rather than an opaque foreign key failure.
+	inv, err := e.store.ReadInvocation(ctx, comp.InvocationID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return NewDanglingCompletionError(comp.ID, comp.InvocationID)
+	}
+	if err != nil {
+		return fmt.Errorf("read invocation for flow token: %w", err)
+	}
+	flowToken := inv.FlowToken
+
 	// Write completion to store (idempotent via ON CONFLICT)
-	if err := e.store.WriteCompletion(ctx, *comp); err != nil {
+	inserted, err := e.store.WriteCompletionIfNew(ctx, *comp)
+	if err != nil {
 		return fmt.Errorf("write completion %s: %w", comp.ID, err)
 	}
+	if !inserted && !reevaluate {
+		e.metrics.duplicateCompletions.Add(1)
+		slog.Info("duplicate completion, skipping sync evaluation",
+			"id", comp.ID,
+			"invocation_id", comp.InvocationID,
+			"flow_token", flowToken,
+		)
+		return nil
+	}
 
 	slog.Info("completion written",
 		"id", comp.ID,
 		"invocation_id", comp.InvocationID,
 		"output_case", comp.OutputCase,
 	)
+	e.listener.OnCompletion(*comp)
+
+	// TENANT RATE LIMIT: Counted once per recorded completion, when it is
+	// first written, so the budget matches the store (RestoreTenantSteps).
+	// Re-evaluating a stored completion on replay does not count again.
+	if inserted {
+		if err := e.checkTenantRateLimit(flowToken, comp.SecurityContext.TenantID, comp.ID); err != nil {
+			return err
+		}
+	}
 
-	// Get the flow token from the originating invocation
-	inv, err := e.store.ReadInvocation(ctx, comp.InvocationID)
-	if err != nil {
-		return fmt.Errorf("read invocation for flow token: %w", err)
+	// FLOW TIMEOUT: The completion is progress for its own flow; it may
+	// also age other flows past the step timeout.
+	e.timeouts.completed(flowToken, comp.InvocationID, comp.Seq)
+	e.expireIdleFlows(comp.Seq)
+	if err := e.checkFlowTimeout(flowToken); err != nil {
+		return err
 	}
-	flowToken := inv.FlowToken
 
 	// QUOTA ENFORCEMENT (Story 5.4): Check quota before evaluating sync rules
 	// Get or create quota enforcer for this flow
@@ -314,7 +488,10 @@ func (e *Engine) processCompletion(ctx context.Context, comp *ir.Completion) err
 	}
 
 	// Check quota - if exceeded, flow terminates
-	if err := quota.Check(flowToken); err != nil {
+	err = quota.Check(flowToken)
+	e.metrics.setFlowSteps(flowToken, quota.Current())
+	if err != nil {
+		e.metrics.quotaRejections.Add(1)
 		slog.Error("max steps quota exceeded",
 			"flow_token", flowToken,
 			"completion_id", comp.ID,
@@ -322,6 +499,10 @@ func (e *Engine) processCompletion(ctx context.Context, comp *ir.Completion) err
 			"limit", e.maxSteps,
 			"event", "quota_exceeded",
 		)
+		var quotaErr *StepsExceededError
+		if errors.As(err, &quotaErr) {
+			e.listener.OnQuotaExceeded(quotaErr)
+		}
 		return fmt.Errorf("quota enforcement failed: %w", err)
 	}
 
@@ -330,9 +511,27 @@ func (e *Engine) processCompletion(ctx context.Context, comp *ir.Completion) err
 		return fmt.Errorf("evaluate syncs for completion %s: %w", comp.ID, err)
 	}
 
+	// Deferred firings whose delay has now elapsed (see schedule.go)
+	e.releaseScheduledFirings(ctx, comp.Seq)
+
 	return nil
 }
 
+// ProcessCompletion writes a completion and evaluates sync rules against it
+// synchronously, returning any runtime error (cycle, quota, dangling
+// completion) instead of logging it.
+//
+// This is the entry point for callers that drive the engine step by step,
+// such as the conformance harness. It must not be called concurrently with
+// Run - both share the single-writer guarantee.
+//
+// Unlike completions submitted through Enqueue, a completion that is already
+// stored is evaluated again, so replay can re-drive sync evaluation; sync
+// firings stay idempotent (CP-1).
+func (e *Engine) ProcessCompletion(ctx context.Context, comp *ir.Completion) error {
+	return e.processCompletion(ctx, comp, true)
+}
+
 // evaluateSyncs evaluates all registered sync rules against a completion.
 //
 // Sync rules are checked in declaration order (CRITICAL-3). For each
@@ -344,7 +543,9 @@ func (e *Engine) processCompletion(ctx context.Context, comp *ir.Completion) err
 // Flow token propagation (Story 3.6): The flow token is inherited from the
 // triggering invocation, never generated mid-flow.
 //
-// TODO (Story 3.7): Implement flow-scoped where-clause execution
+// Where-clauses (Epic 4) expand a single when-match into zero or more
+// binding sets; the sync fires once per binding set. A where-clause that
+// exceeds the per-firing cap aborts evaluation with ErrCodeBindingExplosion.
 func (e *Engine) evaluateSyncs(ctx context.Context, comp *ir.Completion) error {
 	// Lookup the invocation (needed for action URI matching and flow token)
 	inv, err := e.store.ReadInvocation(ctx, comp.InvocationID)
@@ -358,7 +559,7 @@ func (e *Engine) evaluateSyncs(ctx context.Context, comp *ir.Completion) error {
 	// Iterate syncs in declaration order (deterministic)
 	for _, sync := range e.syncs {
 		// Check if this sync matches the completion
-		if matchWhen(sync.When, &inv, comp) {
+		if e.matchSync(sync, &inv, comp) {
 			slog.Debug("sync rule matched",
 				"sync_id", sync.ID,
 				"completion_id", comp.ID,
@@ -383,17 +584,49 @@ func (e *Engine) evaluateSyncs(ctx context.Context, comp *ir.Completion) error {
 				"binding_count", len(bindings),
 			)
 
-			// Fire the sync rule with inherited flow token (Story 3.6)
-			// TODO (Story 3.7): Execute where-clause for multi-binding scenarios
-			if err := e.fireSyncRule(ctx, sync, comp, flowToken, bindings); err != nil {
-				slog.Error("sync rule firing failed",
+			// Execute where-clause (one binding set per matching row)
+			bindingSets, err := e.executeWhereClause(ctx, sync, flowToken, bindings)
+			if err != nil {
+				// Runaway fan-out is a runtime error, like a cycle - surface it
+				if IsBindingExplosionError(err) {
+					return err
+				}
+				slog.Error("where-clause execution failed",
 					"sync_id", sync.ID,
 					"completion_id", comp.ID,
 					"error", err,
 				)
-				// Continue to next sync - individual sync failure shouldn't stop evaluation
+				// Continue to next sync - query failure shouldn't stop evaluation
 				continue
 			}
+
+			// Fire the sync rule once per binding set with inherited flow token (Story 3.6)
+			for _, bindingSet := range bindingSets {
+				// Deferred firings are released once the clock catches up
+				if sync.Then.AfterSteps > 0 {
+					if err := e.scheduleFiring(ctx, sync, comp, flowToken, bindingSet); err != nil {
+						slog.Error("sync rule scheduling failed",
+							"sync_id", sync.ID,
+							"completion_id", comp.ID,
+							"error", err,
+						)
+					}
+					continue
+				}
+				if err := e.fireSyncRule(ctx, sync, comp, flowToken, bindingSet); err != nil {
+					// Cycles terminate the flow (Story 5.3) - never skip past them
+					if IsCycleError(err) {
+						return err
+					}
+					slog.Error("sync rule firing failed",
+						"sync_id", sync.ID,
+						"completion_id", comp.ID,
+						"error", err,
+					)
+					// Continue - individual firing failure shouldn't stop evaluation
+					continue
+				}
+			}
 		}
 	}
 
@@ -409,6 +642,11 @@ func (e *Engine) evaluateSyncs(ctx context.Context, comp *ir.Completion) error {
 // CRASH ATOMICITY (CP-1): Uses WriteSyncFiringAtomic to write the firing,
 // invocation, and provenance edge in a single transaction. This prevents
 // orphaned invocations on crash recovery.
+//
+// CYCLE SAFETY (Story 5.3): If the same (sync_id, binding_hash) already fired
+// in this flow from a different completion, returns RuntimeError with
+// ErrCodeCycleDetected. As in executeThen, the firing is recorded only after
+// a successful insert so replays on a fresh engine do not report false cycles.
 func (e *Engine) fireSyncRule(ctx context.Context, sync ir.SyncRule, comp *ir.Completion, flowToken string, bindings ir.IRObject) error {
 	// Compute binding hash for idempotency check (CP-1)
 	bindingHash, err := ir.BindingHash(bindings)
@@ -416,13 +654,31 @@ func (e *Engine) fireSyncRule(ctx context.Context, sync ir.SyncRule, comp *ir.Co
 		return fmt.Errorf("compute binding hash: %w", err)
 	}
 
-	// Generate invocation with INHERITED flow token (Story 3.6)
+	// Re-evaluating the same completion is idempotent (CP-1), not a cycle.
+	// Only a different completion producing the same binding counts.
+	fired, err := e.store.HasFiring(ctx, comp.ID, sync.ID, bindingHash)
+	if err != nil {
+		return fmt.Errorf("check firing: %w", err)
+	}
+	if !fired && e.cycleDetector.WouldCycle(flowToken, sync.ID, bindingHash) {
+		cycleErr := NewCycleError(flowToken, sync.ID, bindingHash)
+		e.metrics.cyclesDetected.Add(1)
+		e.listener.OnCycleDetected(cycleErr)
+		return cycleErr
+	}
+
+	// Generate invocation with INHERITED flow token (Story 3.6) and security context
 	// We generate this before the atomic write so we have the full invocation ready
-	inv, err := e.generateInvocation(flowToken, sync.Then, bindings)
+	inv, err := e.generateInvocation(flowToken, comp.SecurityContext, sync.Then, bindings)
 	if err != nil {
 		return fmt.Errorf("generate invocation: %w", err)
 	}
 
+	// A vetoed binding is skipped without writing anything (see validator.go)
+	if !fired && e.rejectInvocation(sync, bindingHash, inv) {
+		return nil
+	}
+
 	// Prepare firing record
 	firing := ir.SyncFiring{
 		CompletionID: comp.ID,
@@ -433,10 +689,11 @@ func (e *Engine) fireSyncRule(ctx context.Context, sync ir.SyncRule, comp *ir.Co
 
 	// ATOMIC: Write firing + invocation + provenance in single transaction
 	// This ensures crash atomicity - either all three are written or none
-	_, inserted, err := e.store.WriteSyncFiringAtomic(ctx, firing, inv)
+	firingID, inserted, err := e.store.WriteSyncFiringAtomic(ctx, firing, inv)
 	if err != nil {
 		return fmt.Errorf("atomic sync firing: %w", err)
 	}
+	firing.ID = firingID
 
 	if !inserted {
 		slog.Debug("sync already fired, skipping (idempotent)",
@@ -447,6 +704,8 @@ func (e *Engine) fireSyncRule(ctx context.Context, sync ir.SyncRule, comp *ir.Co
 		return nil
 	}
 
+	e.cycleDetector.Record(flowToken, sync.ID, bindingHash)
+
 	slog.Info("sync fired",
 		"sync_id", sync.ID,
 		"completion_id", comp.ID,
@@ -455,6 +714,9 @@ func (e *Engine) fireSyncRule(ctx context.Context, sync ir.SyncRule, comp *ir.Co
 		"action_uri", inv.ActionURI,
 		"seq", inv.Seq,
 	)
+	e.metrics.syncsFired.Add(1)
+	e.timeouts.invoked(flowToken, inv.ID, inv.Seq)
+	e.listener.OnSyncFired(firing, inv)
 
 	return nil
 }
@@ -464,16 +726,26 @@ func (e *Engine) fireSyncRule(ctx context.Context, sync ir.SyncRule, comp *ir.Co
 //
 // Parameters:
 //   - flowToken: Flow token from the invocation that triggered this sync
+//   - secCtx: Security context of the triggering completion (CP-6)
 //   - then: Then-clause from sync rule (action + arg templates)
 //   - bindings: Variable bindings from when-clause (and where-clause in future)
 //
 // Returns:
-//   - Invocation with inherited flow token and computed content-addressed ID
+//   - Invocation with inherited flow token and security context, and computed
+//     content-addressed ID
 //   - Error if arg resolution fails
 //
 // CRITICAL: Flow token is a PARAMETER, not generated. This ensures flow
 // token chain remains unbroken from root to leaf (CP-7).
-func (e *Engine) generateInvocation(flowToken string, then ir.ThenClause, bindings ir.IRObject) (ir.Invocation, error) {
+func (e *Engine) generateInvocation(flowToken string, secCtx ir.SecurityContext, then ir.ThenClause, bindings ir.IRObject) (ir.Invocation, error) {
+	return e.generateInvocationAt(flowToken, secCtx, then, bindings, e.clock.Next)
+}
+
+// generateInvocationAt is generateInvocation with an explicit seq source.
+// nextSeq is only called once the args resolve, so a failed generation does
+// not consume a clock tick. Crash recovery passes the original seq to
+// recompute the same content-addressed invocation ID.
+func (e *Engine) generateInvocationAt(flowToken string, secCtx ir.SecurityContext, then ir.ThenClause, bindings ir.IRObject, nextSeq func() int64) (ir.Invocation, error) {
 	// Validate flow token - required for propagation chain integrity
 	if flowToken == "" {
 		return ir.Invocation{}, fmt.Errorf("flow token is required")
@@ -485,8 +757,14 @@ func (e *Engine) generateInvocation(flowToken string, then ir.ThenClause, bindin
 		return ir.Invocation{}, fmt.Errorf("resolve args for action %s: %w", then.ActionRef, err)
 	}
 
+	// Tenant and user are inherited from the triggering completion, so
+	// generated work stays attributed to whoever started the flow (CP-6)
+	if err := secCtx.Validate(); err != nil {
+		return ir.Invocation{}, fmt.Errorf("security context for action %s: %w", then.ActionRef, err)
+	}
+
 	// Get sequence number
-	seq := e.clock.Next()
+	seq := nextSeq()
 
 	// Compute content-addressed ID
 	id, err := ir.InvocationID(flowToken, then.ActionRef, args, seq)
@@ -501,7 +779,7 @@ func (e *Engine) generateInvocation(flowToken string, then ir.ThenClause, bindin
 		ActionURI:       ir.ActionRef(then.ActionRef),
 		Args:            args,
 		Seq:             seq,
-		SecurityContext: e.currentSecurityContext(),
+		SecurityContext: secCtx, // INHERITED from triggering completion
 		SpecHash:        e.specHash,
 		EngineVersion:   ir.EngineVersion,
 		IRVersion:       ir.IRVersion,
@@ -514,7 +792,7 @@ func (e *Engine) generateInvocation(flowToken string, then ir.ThenClause, bindin
 //
 // The then.Args map has string keys (arg names) and string values (expressions).
 // String values starting with "${bound." are binding references that get substituted.
-// Other values are treated as literal strings.
+// Other values are literals: typed ("int:5", "bool:true", "str:foo") or bare strings.
 //
 // Example:
 //
@@ -537,11 +815,12 @@ func (e *Engine) resolveArgs(argTemplates map[string]string, bindings ir.IRObjec
 
 // substituteBinding replaces a binding reference with its actual value.
 // Binding references use the format "${bound.varname}".
-// Non-reference strings are returned as IRString literals.
+// Non-reference strings are literals, parsed by ir.ParseArgLiteral.
 //
 // Examples:
 //
 //	"${bound.product}" with bindings{"product": IRString("widget")} → IRString("widget")
+//	"int:5" → IRInt(5)
 //	"literal value" → IRString("literal value")
 func (e *Engine) substituteBinding(template string, bindings ir.IRObject) (ir.IRValue, error) {
 	const prefix = "${bound."
@@ -562,20 +841,8 @@ func (e *Engine) substituteBinding(template string, bindings ir.IRObject) (ir.IR
 		return val, nil
 	}
 
-	// Not a binding reference - return as literal string
-	return ir.IRString(template), nil
-}
-
-// currentSecurityContext returns the security context for generated invocations.
-// Currently returns a placeholder - full implementation in Story 4.x (authz).
-//
-// TODO: Inherit from triggering invocation or use engine-level context
-func (e *Engine) currentSecurityContext() ir.SecurityContext {
-	return ir.SecurityContext{
-		TenantID:    "default",
-		UserID:      "engine",
-		Permissions: []string{},
-	}
+	// Not a binding reference - typed ("int:5") or bare string literal
+	return ir.ParseArgLiteral(template)
 }
 
 // executeWhereClause executes a where-clause query with scope filtering.
@@ -586,10 +853,17 @@ func (e *Engine) currentSecurityContext() ir.SecurityContext {
 //   - "global": No flow_token filter (match all flows)
 //   - "keyed": Filter by specified key field value
 //
-// STUB: Full implementation requires QueryIR from Epic 4. Currently returns
-// the when-bindings as a single binding set (no actual query execution).
+// The scope filter is ANDed with the where-clause filter, compiled to QueryIR,
+// and run through the SQL backend (see executeWhere). Each result row becomes
+// one binding set, merged with the when-bindings.
 //
-// TODO (Epic 4): Implement full query execution with scope filters
+// More than maxBindingsPerFiring binding sets is rejected with
+// ErrCodeBindingExplosion rather than fanning out (see WithMaxBindingsPerFiring).
+// The binding sets are returned sorted by ir.SortBindings on the where-clause
+// variables, which fixes the firing order (and seq assignment) across replays.
+// Bound values are scalar columns; anything that collects rows into an array
+// arg must order it with ir.SortArrayByCanonicalJSON so the invocation's
+// content ID does not depend on row order.
 func (e *Engine) executeWhereClause(
 	ctx context.Context,
 	sync ir.SyncRule,
@@ -610,48 +884,54 @@ func (e *Engine) executeWhereClause(
 	}
 
 	// Validate keyed scope has required key
-	if ScopeMode(scope.Mode) == ScopeModeKeyed {
-		if scope.Key == "" {
-			return nil, fmt.Errorf("keyed scope requires non-empty key field")
-		}
-		// Validate that key exists in when-bindings
-		_, err := extractKeyValue(whenBindings, scope.Key)
-		if err != nil {
-			return nil, fmt.Errorf("extract key value for keyed scope: %w", err)
-		}
+	if ScopeMode(scope.Mode) == ScopeModeKeyed && scope.Key == "" {
+		return nil, fmt.Errorf("keyed scope requires non-empty key field")
 	}
 
-	// TODO (Epic 4): Execute actual query with scope filter
-	// For now, return when-bindings as single binding set
-	// This allows sync rules without where-clauses to work correctly
-	slog.Debug("executeWhereClause stub: returning when-bindings only",
-		"sync_id", sync.ID,
-		"scope_mode", scope.Mode,
-		"flow_token", flowToken,
-		"has_where", sync.Where != nil,
-	)
+	scopeFilter, err := scopePredicate(scope, flowToken, whenBindings)
+	if err != nil {
+		return nil, err
+	}
+
+	bindingSets, err := e.executeWhere(ctx, sync.Where, whenBindings, flowToken, scopeFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fan-out guard: each binding set becomes an invocation
+	if len(bindingSets) > e.maxBindingsPerFiring {
+		return nil, NewBindingExplosionError(flowToken, sync.ID, len(bindingSets), e.maxBindingsPerFiring)
+	}
 
-	return []ir.IRObject{whenBindings}, nil
+	// Fire in canonical order, independent of DB row order, so replays
+	// assign identical seqs (CP-2)
+	ir.SortBindings(bindingSets, whereBindingVars(sync.Where))
+
+	return bindingSets, nil
 }
 
-// RegisterSyncs registers sync rules with the engine in declaration order.
+// RegisterSyncs registers sync rules with the engine in evaluation order.
 //
-// The syncs slice order determines evaluation priority. Sync rules are
-// evaluated in the exact order provided, which must match the declaration
-// order from the CUE compiler.
+// Rules with a Priority are evaluated first, in ascending priority with ties
+// broken by sync ID. The remaining rules follow in the exact order provided,
+// which must match the declaration order from the CUE compiler (see
+// ir.SortSyncRules).
 //
 // This function validates:
 //   - All sync IDs are unique
+//   - Each rule passes ir.SyncRule.Validate (structural invariants)
+//   - Each when-guard parses (it is parsed once here, see guard.go)
 //   - All when-clause event types are supported ("completed" only for now)
 //
 // Passing nil or an empty slice is valid and clears any previously
 // registered sync rules.
 //
 // CRITICAL: This order is deterministic and preserved across engine restarts.
-// The same input order guarantees the same evaluation order.
+// The same input rules in the same order guarantee the same evaluation order.
 func (e *Engine) RegisterSyncs(syncs []ir.SyncRule) error {
 	if syncs == nil {
 		e.syncs = nil
+		e.guards = nil
 		return nil
 	}
 
@@ -663,6 +943,10 @@ func (e *Engine) RegisterSyncs(syncs []ir.SyncRule) error {
 		}
 		seen[sync.ID] = true
 
+		if errs := sync.Validate(); len(errs) > 0 {
+			return fmt.Errorf("sync %s: %w", sync.ID, errors.Join(errs...))
+		}
+
 		// Validate event type is supported
 		// Currently only "completed" is implemented; "invoked" is planned for future
 		if sync.When.EventType != "" && sync.When.EventType != "completed" {
@@ -670,15 +954,22 @@ func (e *Engine) RegisterSyncs(syncs []ir.SyncRule) error {
 		}
 	}
 
-	// Store syncs in declaration order
+	guards, err := parseGuards(syncs)
+	if err != nil {
+		return err
+	}
+	e.guards = guards
+
+	// Store syncs in evaluation order
 	// Make a copy to prevent external mutation
 	e.syncs = make([]ir.SyncRule, len(syncs))
 	copy(e.syncs, syncs)
+	ir.SortSyncRules(e.syncs)
 
 	return nil
 }
 
-// Syncs returns the registered sync rules in declaration order.
+// Syncs returns the registered sync rules in evaluation order.
 // Used for testing and introspection.
 func (e *Engine) Syncs() []ir.SyncRule {
 	return e.syncs
@@ -696,6 +987,20 @@ func (e *Engine) QueueLen() int {
 	return e.queue.Len()
 }
 
+// PendingInvocations returns invocations in a flow that have no completion yet.
+// External executors poll this to find actions that still need to run.
+// Results ordered by seq ASC, id ASC per CP-4.
+func (e *Engine) PendingInvocations(ctx context.Context, flowToken string) ([]ir.Invocation, error) {
+	return e.store.GetPendingInvocations(ctx, flowToken)
+}
+
+// PendingInvocationsAll returns uncompleted invocations across all flows.
+// Results ordered by seq ASC, id ASC per CP-4, so executors process work
+// in the same order the engine generated it.
+func (e *Engine) PendingInvocationsAll(ctx context.Context) ([]ir.Invocation, error) {
+	return e.store.GetAllPendingInvocations(ctx)
+}
+
 // ClearFlowCycleHistory removes cycle detection history for a flow.
 // Should be called when a flow completes (success or error) to prevent memory leaks.
 //
@@ -716,9 +1021,25 @@ func (e *Engine) CycleDetectorForTesting() *CycleDetector {
 // This removes:
 //   - Quota enforcer from quotas map
 //   - Cycle detection history from cycleDetector
+//   - Step timeout tracking (a timed-out flow stays marked timed-out)
+//   - Scheduled firings not yet released, in memory and in the store
+//     (see schedule.go)
+//   - The flow's step count from Metrics.FlowSteps, reported to
+//     EventListener.OnFlowCleanup
 func (e *Engine) CleanupFlow(flowToken string) {
 	delete(e.quotas, flowToken)
 	e.cycleDetector.Clear(flowToken)
+	e.timeouts.forget(flowToken)
+	e.forgetScheduledFirings(flowToken)
+	if steps, ok := e.metrics.forgetFlow(flowToken); ok {
+		e.listener.OnFlowCleanup(flowToken, steps)
+	}
+}
+
+// Metrics returns the engine's aggregate counters.
+// Safe to read from any goroutine.
+func (e *Engine) Metrics() *Metrics {
+	return e.metrics
 }
 
 // MaxSteps returns the configured maximum steps per flow.
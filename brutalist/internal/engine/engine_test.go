@@ -2,6 +2,7 @@ package engine
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 	"time"
 
@@ -12,6 +13,9 @@ import (
 	"github.com/roach88/nysm/internal/store"
 )
 
+// testSecurityContext is a valid context (CP-6) for records written in tests.
+var testSecurityContext = ir.NewSecurityContext("test-tenant", "test-user")
+
 // stubFlowGen is a test-only flow generator that returns fixed tokens.
 type stubFlowGen struct {
 	tokens []string
@@ -40,12 +44,22 @@ func setupTestStore(t *testing.T) *store.Store {
 	return s
 }
 
+// namedSync returns a structurally valid sync rule with the given ID, for
+// tests that only care about registration and ordering.
+func namedSync(id string) ir.SyncRule {
+	return ir.SyncRule{
+		ID:   id,
+		When: ir.WhenClause{ActionRef: "Cart.checkout", EventType: "completed"},
+		Then: ir.ThenClause{ActionRef: "Inventory.reserve"},
+	}
+}
+
 func TestEngine_New(t *testing.T) {
 	s := setupTestStore(t)
 	flowGen := newStubFlowGen("test-flow")
 
 	specs := []ir.ConceptSpec{{Name: "Test"}}
-	syncs := []ir.SyncRule{{ID: "sync-1"}}
+	syncs := []ir.SyncRule{namedSync("sync-1")}
 
 	engine := New(s, specs, syncs, flowGen)
 
@@ -73,9 +87,9 @@ func TestEngine_Enqueue(t *testing.T) {
 	engine := New(s, nil, nil, flowGen)
 
 	inv := &ir.Invocation{ID: "inv-1", FlowToken: "flow-1"}
-	ok := engine.Enqueue(Event{Type: EventTypeInvocation, Invocation: inv})
+	err := engine.Enqueue(Event{Type: EventTypeInvocation, Invocation: inv})
 
-	assert.True(t, ok)
+	assert.NoError(t, err)
 	assert.Equal(t, 1, engine.QueueLen())
 }
 
@@ -87,9 +101,50 @@ func TestEngine_Enqueue_AfterStop(t *testing.T) {
 	engine.Stop()
 
 	inv := &ir.Invocation{ID: "inv-1", FlowToken: "flow-1"}
-	ok := engine.Enqueue(Event{Type: EventTypeInvocation, Invocation: inv})
+	err := engine.Enqueue(Event{Type: EventTypeInvocation, Invocation: inv})
 
-	assert.False(t, ok, "enqueue after stop should fail")
+	assert.ErrorIs(t, err, ErrStopped, "enqueue after stop should fail")
+}
+
+func TestEngine_Enqueue_QueueCapacity(t *testing.T) {
+	s := setupTestStore(t)
+	flowGen := newStubFlowGen("flow-1")
+	engine := New(s, nil, nil, flowGen, WithQueueCapacity(2))
+
+	enqueue := func(id string) error {
+		return engine.Enqueue(Event{Type: EventTypeInvocation, Invocation: &ir.Invocation{ID: id, FlowToken: "flow-1"}})
+	}
+
+	require.NoError(t, enqueue("inv-1"))
+	require.NoError(t, enqueue("inv-2"))
+	assert.ErrorIs(t, enqueue("inv-3"), ErrQueueFull, "enqueue beyond capacity should be rejected")
+	assert.Equal(t, 2, engine.QueueLen(), "rejected event is not queued")
+
+	// Draining one event frees one slot
+	ev, ok := engine.queue.TryDequeue()
+	require.True(t, ok)
+	assert.Equal(t, "inv-1", ev.Invocation.ID)
+	require.NoError(t, enqueue("inv-3"))
+	assert.ErrorIs(t, enqueue("inv-4"), ErrQueueFull)
+
+	// A stopped engine reports stopped, not full
+	engine.Stop()
+	err := enqueue("inv-5")
+	assert.ErrorIs(t, err, ErrStopped)
+	assert.NotErrorIs(t, err, ErrQueueFull)
+}
+
+func TestEngine_Enqueue_GeneratedInvocationsIgnoreCapacity(t *testing.T) {
+	s := setupTestStore(t)
+	flowGen := newStubFlowGen("flow-1")
+	engine := New(s, nil, nil, flowGen, WithQueueCapacity(1))
+
+	require.NoError(t, engine.Enqueue(Event{Type: EventTypeInvocation, Invocation: &ir.Invocation{ID: "inv-1"}}))
+
+	// Follow-on events go through the unbounded path, so a flow is never cut short
+	assert.True(t, engine.queue.Enqueue(Event{Type: EventTypeInvocation, Invocation: &ir.Invocation{ID: "inv-2"}}))
+	assert.Equal(t, 2, engine.QueueLen())
+	assert.ErrorIs(t, engine.Enqueue(Event{Type: EventTypeInvocation, Invocation: &ir.Invocation{ID: "inv-3"}}), ErrQueueFull)
 }
 
 func TestEngine_ProcessInvocation(t *testing.T) {
@@ -313,9 +368,9 @@ func TestEngine_SyncsPreservedInOrder(t *testing.T) {
 	flowGen := newStubFlowGen("flow-1")
 
 	syncs := []ir.SyncRule{
-		{ID: "sync-first"},
-		{ID: "sync-second"},
-		{ID: "sync-third"},
+		namedSync("sync-first"),
+		namedSync("sync-second"),
+		namedSync("sync-third"),
 	}
 
 	engine := New(s, nil, syncs, flowGen)
@@ -354,9 +409,9 @@ func TestRegisterSyncs_Order(t *testing.T) {
 	engine := New(s, nil, nil, flowGen)
 
 	syncs := []ir.SyncRule{
-		{ID: "sync-1"},
-		{ID: "sync-2"},
-		{ID: "sync-3"},
+		namedSync("sync-1"),
+		namedSync("sync-2"),
+		namedSync("sync-3"),
 	}
 
 	err := engine.RegisterSyncs(syncs)
@@ -369,15 +424,60 @@ func TestRegisterSyncs_Order(t *testing.T) {
 	assert.Equal(t, "sync-3", engine.Syncs()[2].ID)
 }
 
+func TestRegisterSyncs_PriorityOrder(t *testing.T) {
+	s := setupTestStore(t)
+	engine := New(s, nil, nil, newStubFlowGen("flow-1"))
+
+	priority := func(p int) *int { return &p }
+	late := namedSync("sync-late")
+	tieB := namedSync("sync-tie-b")
+	tieB.Priority = priority(1)
+	tieA := namedSync("sync-tie-a")
+	tieA.Priority = priority(1)
+	first := namedSync("sync-first")
+	first.Priority = priority(0)
+
+	require.NoError(t, engine.RegisterSyncs([]ir.SyncRule{late, tieB, tieA, first}))
+
+	var ids []string
+	for _, sync := range engine.Syncs() {
+		ids = append(ids, sync.ID)
+	}
+	// Lowest priority first, ties by ID, unprioritized last
+	assert.Equal(t, []string{"sync-first", "sync-tie-a", "sync-tie-b", "sync-late"}, ids)
+}
+
+func TestEvaluateSyncs_LowerPriorityFiresFirst(t *testing.T) {
+	ctx := context.Background()
+	s := setupTestStore(t)
+
+	priority := 1
+	audit := namedSync("audit")
+	audit.Then.ActionRef = "Audit.record"
+	reserve := namedSync("reserve")
+	reserve.Priority = &priority
+
+	// Declared after audit, but its priority makes it evaluate first
+	e := NewWithClock(s, nil, []ir.SyncRule{audit, reserve}, nil, NewClockAt(2))
+	processFanOutCheckout(t, e)
+
+	pending, err := s.GetPendingInvocations(ctx, "flow-1")
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, ir.ActionRef("Inventory.reserve"), pending[0].ActionURI)
+	assert.Equal(t, ir.ActionRef("Audit.record"), pending[1].ActionURI)
+	assert.Less(t, pending[0].Seq, pending[1].Seq)
+}
+
 func TestRegisterSyncs_DuplicateID(t *testing.T) {
 	s := setupTestStore(t)
 	flowGen := newStubFlowGen("flow-1")
 	engine := New(s, nil, nil, flowGen)
 
 	syncs := []ir.SyncRule{
-		{ID: "sync-1"},
-		{ID: "sync-2"},
-		{ID: "sync-1"}, // Duplicate
+		namedSync("sync-1"),
+		namedSync("sync-2"),
+		namedSync("sync-1"), // Duplicate
 	}
 
 	err := engine.RegisterSyncs(syncs)
@@ -385,14 +485,30 @@ func TestRegisterSyncs_DuplicateID(t *testing.T) {
 	assert.Contains(t, err.Error(), "duplicate sync ID: sync-1")
 }
 
+func TestRegisterSyncs_InvalidRule(t *testing.T) {
+	s := setupTestStore(t)
+	flowGen := newStubFlowGen("flow-1")
+	engine := New(s, nil, nil, flowGen)
+
+	invalid := namedSync("sync-bad")
+	invalid.Scope = ir.ScopeSpec{Mode: "keyed"}
+	invalid.Then.Args = map[string]string{"item_id": "${bound.item_id}"}
+
+	err := engine.RegisterSyncs([]ir.SyncRule{namedSync("sync-1"), invalid})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "sync sync-bad: scope.key: keyed scope requires a non-empty key field")
+	assert.Contains(t, err.Error(), `then.args.item_id: undefined bound variable "item_id"`)
+	assert.Empty(t, engine.Syncs(), "nothing is registered when a rule is invalid")
+}
+
 func TestRegisterSyncs_CopyPreventsExternalMutation(t *testing.T) {
 	s := setupTestStore(t)
 	flowGen := newStubFlowGen("flow-1")
 	engine := New(s, nil, nil, flowGen)
 
 	syncs := []ir.SyncRule{
-		{ID: "sync-1"},
-		{ID: "sync-2"},
+		namedSync("sync-1"),
+		namedSync("sync-2"),
 	}
 
 	err := engine.RegisterSyncs(syncs)
@@ -412,7 +528,7 @@ func TestRegisterSyncs_ReplacesPreviousRegistration(t *testing.T) {
 
 	// First registration
 	syncs1 := []ir.SyncRule{
-		{ID: "sync-1"},
+		namedSync("sync-1"),
 	}
 	err := engine.RegisterSyncs(syncs1)
 	require.NoError(t, err)
@@ -420,8 +536,8 @@ func TestRegisterSyncs_ReplacesPreviousRegistration(t *testing.T) {
 
 	// Second registration replaces
 	syncs2 := []ir.SyncRule{
-		{ID: "sync-2"},
-		{ID: "sync-3"},
+		namedSync("sync-2"),
+		namedSync("sync-3"),
 	}
 	err = engine.RegisterSyncs(syncs2)
 	require.NoError(t, err)
@@ -520,6 +636,65 @@ func TestEvaluateSyncs_WithMatchingSyncRule(t *testing.T) {
 	assert.Equal(t, ir.IRString("cart-123"), generatedInv.Args["cart_id"])
 }
 
+func TestEvaluateSyncs_OutputCaseSpecific(t *testing.T) {
+	s := setupTestStore(t)
+	ctx := context.Background()
+
+	// Error-handling sync: only InsufficientStock triggers a backorder
+	syncs := []ir.SyncRule{{
+		ID: "sync-backorder",
+		When: ir.WhenClause{
+			ActionRef:  "Inventory.reserve",
+			EventType:  "completed",
+			OutputCase: "InsufficientStock",
+			Bindings:   map[string]string{"item": "item"},
+		},
+		Then: ir.ThenClause{
+			ActionRef: "Inventory.backorder",
+			Args:      map[string]string{"item": "${bound.item}"},
+		},
+	}}
+	engine := New(s, nil, syncs, newStubFlowGen("flow-1"))
+
+	process := func(seq int64, outputCase string) string {
+		args := ir.IRObject{"item": ir.IRString("widget"), "attempt": ir.IRInt(seq)}
+		inv := ir.Invocation{
+			ID:              ir.MustInvocationID("flow-1", "Inventory.reserve", args, seq),
+			FlowToken:       "flow-1",
+			ActionURI:       "Inventory.reserve",
+			Args:            args,
+			Seq:             seq,
+			SecurityContext: testSecurityContext,
+		}
+		require.NoError(t, s.WriteInvocation(ctx, inv))
+
+		result := ir.IRObject{"item": ir.IRString("widget")}
+		comp := &ir.Completion{
+			ID:              ir.MustCompletionID(inv.ID, outputCase, result, seq+1),
+			InvocationID:    inv.ID,
+			OutputCase:      outputCase,
+			Result:          result,
+			Seq:             seq + 1,
+			SecurityContext: testSecurityContext,
+		}
+		require.NoError(t, engine.ProcessCompletion(ctx, comp))
+		return comp.ID
+	}
+
+	// Success is ignored
+	successID := process(1, "Success")
+	firings, err := s.ReadSyncFiringsForCompletion(ctx, successID)
+	require.NoError(t, err)
+	assert.Empty(t, firings, "Success must not trigger the InsufficientStock sync")
+
+	// The matching case fires
+	insufficientID := process(10, "InsufficientStock")
+	firings, err = s.ReadSyncFiringsForCompletion(ctx, insufficientID)
+	require.NoError(t, err)
+	require.Len(t, firings, 1)
+	assert.Equal(t, "sync-backorder", firings[0].SyncID)
+}
+
 func TestEvaluateSyncs_NoSyncs(t *testing.T) {
 	s := setupTestStore(t)
 	flowGen := newStubFlowGen("flow-1")
@@ -573,10 +748,11 @@ func TestMatchWhen_Integration(t *testing.T) {
 	}
 
 	inv := &ir.Invocation{
-		ID:        "inv-1",
-		FlowToken: "flow-1",
-		ActionURI: "Cart.checkout",
-		Args:      ir.IRObject{},
+		ID:              "inv-1",
+		FlowToken:       "flow-1",
+		ActionURI:       "Cart.checkout",
+		Args:            ir.IRObject{},
+		SecurityContext: testSecurityContext,
 	}
 
 	comp := &ir.Completion{
@@ -603,9 +779,9 @@ func TestDeclarationOrderDeterminism(t *testing.T) {
 	s2 := setupTestStore(t)
 
 	syncs := []ir.SyncRule{
-		{ID: "alpha"},
-		{ID: "beta"},
-		{ID: "gamma"},
+		namedSync("alpha"),
+		namedSync("beta"),
+		namedSync("gamma"),
 	}
 
 	// Create two engines with same syncs
@@ -625,3 +801,182 @@ func TestDeclarationOrderDeterminism(t *testing.T) {
 			"engines should have identical sync order at index %d", i)
 	}
 }
+
+func TestEngine_PendingInvocations(t *testing.T) {
+	s := setupTestStore(t)
+	flowGen := newStubFlowGen("flow-1")
+
+	syncs := []ir.SyncRule{
+		{
+			ID: "sync-cart-to-inventory",
+			When: ir.WhenClause{
+				ActionRef:  "Cart.addItem",
+				EventType:  "completed",
+				OutputCase: "Success",
+				Bindings:   map[string]string{"cart_id": "cart_id"},
+			},
+			Then: ir.ThenClause{
+				ActionRef: "Inventory.reserve",
+				Args:      map[string]string{"cart_id": "${bound.cart_id}"},
+			},
+		},
+	}
+
+	engine := NewWithClock(s, nil, syncs, flowGen, NewClockAt(2))
+	ctx := context.Background()
+
+	args := ir.IRObject{"item": ir.IRString("widget")}
+	invID := ir.MustInvocationID("flow-1", "Cart.addItem", args, 1)
+	require.NoError(t, s.WriteInvocation(ctx, ir.Invocation{
+		ID:              invID,
+		FlowToken:       "flow-1",
+		ActionURI:       "Cart.addItem",
+		Args:            args,
+		Seq:             1,
+		EngineVersion:   ir.EngineVersion,
+		IRVersion:       ir.IRVersion,
+		SecurityContext: testSecurityContext,
+	}))
+
+	// Root invocation has no completion yet, so it is pending
+	pending, err := engine.PendingInvocations(ctx, "flow-1")
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, invID, pending[0].ID)
+
+	result := ir.IRObject{"cart_id": ir.IRString("cart-123")}
+	compID := ir.MustCompletionID(invID, "Success", result, 2)
+	comp := &ir.Completion{
+		ID:              compID,
+		InvocationID:    invID,
+		OutputCase:      "Success",
+		Result:          result,
+		Seq:             2,
+		SecurityContext: testSecurityContext,
+	}
+	require.NoError(t, s.WriteCompletion(ctx, *comp))
+	require.NoError(t, engine.evaluateSyncs(ctx, comp))
+
+	// Generated-but-uncompleted invocation appears
+	pending, err = engine.PendingInvocations(ctx, "flow-1")
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	generated := pending[0]
+	assert.Equal(t, ir.ActionRef("Inventory.reserve"), generated.ActionURI)
+
+	all, err := engine.PendingInvocationsAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.Equal(t, generated.ID, all[0].ID)
+
+	// Writing its completion removes it from the pending set
+	genResult := ir.IRObject{}
+	require.NoError(t, s.WriteCompletion(ctx, ir.Completion{
+		ID:              ir.MustCompletionID(generated.ID, "Success", genResult, 4),
+		InvocationID:    generated.ID,
+		OutputCase:      "Success",
+		Result:          genResult,
+		Seq:             4,
+		SecurityContext: testSecurityContext,
+	}))
+
+	pending, err = engine.PendingInvocations(ctx, "flow-1")
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	all, err = engine.PendingInvocationsAll(ctx)
+	require.NoError(t, err)
+	assert.NotNil(t, all, "expected empty slice, not nil")
+	assert.Empty(t, all)
+}
+
+func TestEngine_ProcessCompletion_DanglingInvocation(t *testing.T) {
+	s := setupTestStore(t)
+	engine := New(s, nil, nil, newStubFlowGen("flow-1"))
+	ctx := context.Background()
+
+	result := ir.IRObject{}
+	comp := &ir.Completion{
+		ID:              ir.MustCompletionID("inv-missing", "Success", result, 2),
+		InvocationID:    "inv-missing",
+		OutputCase:      "Success",
+		Result:          result,
+		Seq:             2,
+		SecurityContext: testSecurityContext,
+	}
+
+	err := engine.ProcessCompletion(ctx, comp)
+	require.Error(t, err)
+	assert.True(t, IsDanglingCompletionError(err))
+
+	var re *RuntimeError
+	require.ErrorAs(t, err, &re)
+	assert.Equal(t, comp.ID, re.CompletionID)
+	assert.Equal(t, "inv-missing", re.InvocationID)
+
+	// Completion must not be persisted
+	_, err = s.ReadCompletion(ctx, comp.ID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestEngine_Run_DuplicateCompletionSkipsSyncs(t *testing.T) {
+	s := setupTestStore(t)
+	engine := New(s, nil, []ir.SyncRule{reserveOnCheckoutSync()}, newStubFlowGen("flow-1"))
+	ctx := context.Background()
+
+	comp := writeCheckout(t, s)
+
+	// An executor retry that reports a different result for the same
+	// invocation: the store keeps the first completion (UNIQUE invocation_id)
+	retry := ir.NewCompletion(comp.InvocationID, "Success").
+		WithResult(ir.IRObject{"item_id": ir.IRString("gadget")}).
+		WithSeq(102).
+		WithSecurityContext(testSecurityContext).
+		MustBuild()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- engine.Run(ctx)
+	}()
+
+	require.NoError(t, engine.Enqueue(Event{Type: EventTypeCompletion, Completion: comp}))
+	require.NoError(t, engine.Enqueue(Event{Type: EventTypeCompletion, Completion: comp}))
+	require.NoError(t, engine.Enqueue(Event{Type: EventTypeCompletion, Completion: &retry}))
+
+	assert.Eventually(t, func() bool {
+		return engine.Metrics().EventsProcessed() == 3
+	}, time.Second, 5*time.Millisecond)
+	engine.Stop()
+	require.NoError(t, <-errCh)
+
+	firings, err := s.ReadAllSyncFirings(ctx)
+	require.NoError(t, err)
+	require.Len(t, firings, 1)
+	assert.Equal(t, comp.ID, firings[0].CompletionID)
+
+	triggered, err := s.ReadTriggered(ctx, comp.ID)
+	require.NoError(t, err)
+	require.Len(t, triggered, 1)
+	assert.Equal(t, ir.IRObject{"item_id": ir.IRString("widget")}, triggered[0].Args)
+
+	// Duplicates do not count as flow steps
+	assert.Equal(t, int64(2), engine.Metrics().DuplicateCompletions())
+	assert.Equal(t, 1, engine.QuotaFor("flow-1").Current())
+}
+
+func TestEngine_ProcessCompletion_ReevaluatesStoredCompletion(t *testing.T) {
+	s := setupTestStore(t)
+	engine := New(s, nil, []ir.SyncRule{reserveOnCheckoutSync()}, newStubFlowGen("flow-1"))
+	ctx := context.Background()
+
+	comp := writeCheckout(t, s)
+	require.NoError(t, s.WriteCompletion(ctx, *comp))
+
+	// Replay drives stored completions through ProcessCompletion
+	require.NoError(t, engine.ProcessCompletion(ctx, comp))
+
+	triggered, err := s.ReadTriggered(ctx, comp.ID)
+	require.NoError(t, err)
+	assert.Len(t, triggered, 1)
+	assert.Equal(t, int64(0), engine.Metrics().DuplicateCompletions())
+}
@@ -3,6 +3,8 @@ package engine
 import (
 	"errors"
 	"fmt"
+
+	"github.com/roach88/nysm/internal/ir"
 )
 
 // RuntimeError represents an error detected during engine execution.
@@ -12,6 +14,10 @@ import (
 //   - Quota exceeded: Flow exceeds max steps limit
 //   - Missing action: Referenced action not found
 //   - Invalid binding: Binding doesn't satisfy schema
+//   - Dangling completion: Completion references an unknown invocation
+//   - Flow timeout: Flow made no progress within the step timeout
+//   - Tenant rate limited: Tenant exceeded its step budget
+//   - Invocation rejected: The invocation validator vetoed a generated invocation
 //
 // RuntimeError includes structured fields for diagnostics and recovery.
 type RuntimeError struct {
@@ -30,6 +36,13 @@ type RuntimeError struct {
 	// BindingHash identifies the specific binding (for cycle errors).
 	BindingHash string
 
+	// CompletionID identifies the affected completion (for dangling completion errors).
+	CompletionID string
+
+	// InvocationID identifies the referenced invocation (for dangling
+	// completion and invocation rejected errors).
+	InvocationID string
+
 	// Details contains additional context.
 	Details map[string]string
 }
@@ -49,6 +62,30 @@ const (
 
 	// ErrCodeInvalidBinding indicates a binding doesn't satisfy the schema.
 	ErrCodeInvalidBinding RuntimeErrorCode = "INVALID_BINDING"
+
+	// ErrCodeDanglingCompletion indicates a completion whose originating
+	// invocation cannot be found in the store.
+	ErrCodeDanglingCompletion RuntimeErrorCode = "DANGLING_COMPLETION"
+
+	// ErrCodeFlowTimeout indicates a flow made no progress for longer than
+	// the configured logical step timeout.
+	ErrCodeFlowTimeout RuntimeErrorCode = "FLOW_TIMEOUT"
+
+	// ErrCodeTenantRateLimited indicates a tenant exceeded its step budget.
+	ErrCodeTenantRateLimited RuntimeErrorCode = "TENANT_RATE_LIMITED"
+
+	// ErrCodeBindingExplosion indicates a where-clause returned more binding
+	// sets than the per-firing cap (see WithMaxBindingsPerFiring).
+	ErrCodeBindingExplosion RuntimeErrorCode = "BINDING_EXPLOSION"
+
+	// ErrCodeInvocationRejected indicates the invocation validator (see
+	// WithInvocationValidator) vetoed a generated invocation.
+	ErrCodeInvocationRejected RuntimeErrorCode = "INVOCATION_REJECTED"
+
+	// ErrCodeStepsExceeded identifies a StepsExceededError returned by the
+	// per-flow quota enforcer. StepsExceededError is not a RuntimeError, so
+	// this code is only reported by ErrorCode.
+	ErrCodeStepsExceeded RuntimeErrorCode = "STEPS_EXCEEDED"
 )
 
 // Error implements the error interface.
@@ -84,6 +121,71 @@ func IsQuotaError(err error) bool {
 	return errors.As(err, &se)
 }
 
+// IsDanglingCompletionError returns true if the error is a dangling completion error.
+// Uses errors.As to handle wrapped errors.
+func IsDanglingCompletionError(err error) bool {
+	var re *RuntimeError
+	if errors.As(err, &re) {
+		return re.Code == ErrCodeDanglingCompletion
+	}
+	return false
+}
+
+// IsFlowTimeoutError returns true if the error is a flow timeout error.
+// Uses errors.As to handle wrapped errors.
+func IsFlowTimeoutError(err error) bool {
+	var re *RuntimeError
+	if errors.As(err, &re) {
+		return re.Code == ErrCodeFlowTimeout
+	}
+	return false
+}
+
+// IsTenantRateLimitedError returns true if the error is a tenant rate limit error.
+// Uses errors.As to handle wrapped errors.
+func IsTenantRateLimitedError(err error) bool {
+	var re *RuntimeError
+	if errors.As(err, &re) {
+		return re.Code == ErrCodeTenantRateLimited
+	}
+	return false
+}
+
+// IsBindingExplosionError returns true if the error is a binding explosion error.
+// Uses errors.As to handle wrapped errors.
+func IsBindingExplosionError(err error) bool {
+	var re *RuntimeError
+	if errors.As(err, &re) {
+		return re.Code == ErrCodeBindingExplosion
+	}
+	return false
+}
+
+// IsInvocationRejectedError returns true if the error is an invocation rejected error.
+// Uses errors.As to handle wrapped errors.
+func IsInvocationRejectedError(err error) bool {
+	var re *RuntimeError
+	if errors.As(err, &re) {
+		return re.Code == ErrCodeInvocationRejected
+	}
+	return false
+}
+
+// ErrorCode returns the code of a typed engine error, or "" if err is not one.
+// RuntimeError reports its Code; StepsExceededError reports ErrCodeStepsExceeded.
+// Uses errors.As to handle wrapped errors.
+func ErrorCode(err error) RuntimeErrorCode {
+	var re *RuntimeError
+	if errors.As(err, &re) {
+		return re.Code
+	}
+	var se *StepsExceededError
+	if errors.As(err, &se) {
+		return ErrCodeStepsExceeded
+	}
+	return ""
+}
+
 // NewCycleError creates a RuntimeError for cycle detection.
 func NewCycleError(flowToken, syncID, bindingHash string) *RuntimeError {
 	return &RuntimeError{
@@ -107,3 +209,77 @@ func NewQuotaError(flowToken string, steps, maxSteps int) *RuntimeError {
 		},
 	}
 }
+
+// NewDanglingCompletionError creates a RuntimeError for a completion whose
+// originating invocation does not exist. The flow token is unknown in this
+// case because it is only recorded on the invocation.
+func NewDanglingCompletionError(completionID, invocationID string) *RuntimeError {
+	return &RuntimeError{
+		Code:         ErrCodeDanglingCompletion,
+		Message:      fmt.Sprintf("completion %s references unknown invocation %s", completionID, invocationID),
+		CompletionID: completionID,
+		InvocationID: invocationID,
+	}
+}
+
+// NewFlowTimeoutError creates a RuntimeError for a flow that made no progress
+// for more than timeout seq ticks, detected at seq.
+func NewFlowTimeoutError(flowToken string, timeout, seq int64) *RuntimeError {
+	return &RuntimeError{
+		Code:      ErrCodeFlowTimeout,
+		Message:   fmt.Sprintf("flow made no progress for more than %d seq ticks", timeout),
+		FlowToken: flowToken,
+		Details: map[string]string{
+			"timeout": fmt.Sprintf("%d", timeout),
+			"seq":     fmt.Sprintf("%d", seq),
+		},
+	}
+}
+
+// NewTenantRateLimitError creates a RuntimeError for a tenant that exceeded
+// its step budget while processing a completion in flowToken.
+func NewTenantRateLimitError(tenantID, flowToken string, steps, maxSteps int64) *RuntimeError {
+	return &RuntimeError{
+		Code:      ErrCodeTenantRateLimited,
+		Message:   fmt.Sprintf("tenant %s exceeded step budget (%d > %d)", tenantID, steps, maxSteps),
+		FlowToken: flowToken,
+		Details: map[string]string{
+			"tenant_id": tenantID,
+			"steps":     fmt.Sprintf("%d", steps),
+			"max_steps": fmt.Sprintf("%d", maxSteps),
+		},
+	}
+}
+
+// NewBindingExplosionError creates a RuntimeError for a where-clause in syncID
+// that produced count binding sets, more than maxBindings.
+func NewBindingExplosionError(flowToken, syncID string, count, maxBindings int) *RuntimeError {
+	return &RuntimeError{
+		Code:      ErrCodeBindingExplosion,
+		Message:   fmt.Sprintf("where-clause produced too many binding sets (%d > %d)", count, maxBindings),
+		FlowToken: flowToken,
+		SyncID:    syncID,
+		Details: map[string]string{
+			"count":        fmt.Sprintf("%d", count),
+			"max_bindings": fmt.Sprintf("%d", maxBindings),
+		},
+	}
+}
+
+// NewInvocationRejectedError creates a RuntimeError for an invocation that
+// syncID generated for bindingHash and the invocation validator vetoed with
+// reason.
+func NewInvocationRejectedError(inv ir.Invocation, syncID, bindingHash string, reason error) *RuntimeError {
+	return &RuntimeError{
+		Code:         ErrCodeInvocationRejected,
+		Message:      fmt.Sprintf("invocation of %s rejected by validator: %v", inv.ActionURI, reason),
+		FlowToken:    inv.FlowToken,
+		SyncID:       syncID,
+		BindingHash:  bindingHash,
+		InvocationID: inv.ID,
+		Details: map[string]string{
+			"action_uri": string(inv.ActionURI),
+			"reason":     reason.Error(),
+		},
+	}
+}
@@ -2,9 +2,9 @@ package engine
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
-	"strings"
+	"log/slog"
+	"sort"
 
 	"github.com/roach88/nysm/internal/ir"
 	"github.com/roach88/nysm/internal/queryir"
@@ -20,7 +20,8 @@ import (
 //   - ctx: Context for query execution
 //   - where: The where-clause from the sync rule
 //   - whenBindings: Bindings extracted from the when-clause
-//   - flowToken: Flow token for scoping (used in flow-scoped queries)
+//   - flowToken: Flow token of the triggering completion (for logging)
+//   - scopeFilter: Scope restriction ANDed with the filter (nil = unscoped)
 //
 // Returns:
 //   - []ir.IRObject: Zero or more binding sets, each containing merged when+where bindings
@@ -30,6 +31,7 @@ func (e *Engine) executeWhere(
 	where *ir.WhereClause,
 	whenBindings ir.IRObject,
 	flowToken string,
+	scopeFilter queryir.Predicate,
 ) ([]ir.IRObject, error) {
 	// If no where-clause, return single binding set (when-bindings only)
 	if where == nil {
@@ -37,50 +39,40 @@ func (e *Engine) executeWhere(
 	}
 
 	// Build QueryIR query from where-clause
-	query, err := e.buildQueryFromWhere(where, whenBindings)
+	query, err := e.buildQueryFromWhere(where, whenBindings, scopeFilter)
 	if err != nil {
 		return nil, fmt.Errorf("build query: %w", err)
 	}
 
-	// Create SQL compiler with bound values
-	compiler := querysql.NewSQLCompiler()
+	// Render the query plan only when debug logging is on (Explain walks the tree)
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("where-clause query plan",
+			"source", where.Source,
+			"flow_token", flowToken,
+			"plan", queryir.Explain(query),
+		)
+	}
+
+	// Create SQL backend with bound values
+	backend := querysql.NewSQLBackend()
 	for k, v := range whenBindings {
 		param, err := irValueToSQLParam(v)
 		if err != nil {
 			return nil, fmt.Errorf("convert bound value %s: %w", k, err)
 		}
-		compiler.BoundValues["bound."+k] = param
+		backend.BoundValues["bound."+k] = param
 	}
 
-	// Compile QueryIR to SQL
-	sqlStr, params, err := compiler.Compile(query)
+	// Compile and execute against the store
+	rows, err := backend.Execute(ctx, e.store, query)
 	if err != nil {
-		return nil, fmt.Errorf("compile query: %w", err)
+		return nil, err
 	}
 
-	// Execute query against store
-	rows, err := e.store.Query(ctx, sqlStr, params...)
-	if err != nil {
-		return nil, fmt.Errorf("execute query: %w", err)
-	}
-	defer rows.Close()
-
-	// Scan rows into binding sets
-	var bindings []ir.IRObject
-	for rows.Next() {
-		binding, err := scanBinding(rows, where.Bindings)
-		if err != nil {
-			return nil, fmt.Errorf("scan binding: %w", err)
-		}
-
-		// Merge when-bindings with where-bindings
-		mergedBinding := mergeBindings(whenBindings, binding)
-		bindings = append(bindings, mergedBinding)
-	}
-
-	// Check for row iteration errors
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("rows iteration: %w", err)
+	// Merge when-bindings with where-bindings (one binding set per row)
+	bindings := make([]ir.IRObject, 0, len(rows))
+	for _, row := range rows {
+		bindings = append(bindings, mergeBindings(whenBindings, row))
 	}
 
 	// Empty slice is valid (zero matches)
@@ -91,22 +83,35 @@ func (e *Engine) executeWhere(
 //
 // The WhereClause has:
 //   - Source: table name (e.g., "CartItems")
-//   - Filter: filter expression (e.g., "cart_id == bound.cart_id AND status == 'active'")
+//   - Filter: filter expression (e.g., "cart_id == bound.cart_id && status == 'active'"),
+//     parsed by queryir.ParseFilter
 //   - Bindings: field → variable name mapping (e.g., {"item_id": "itemId"})
+//
+// A non-nil scopeFilter (see scopePredicate) is ANDed after the parsed filter.
 func (e *Engine) buildQueryFromWhere(
 	where *ir.WhereClause,
 	whenBindings ir.IRObject,
+	scopeFilter queryir.Predicate,
 ) (queryir.Query, error) {
 	// Parse filter expression into predicate
 	var filter queryir.Predicate
 	if where.Filter != "" {
-		parsed, err := parseFilterExpression(where.Filter)
+		parsed, err := queryir.ParseFilter(where.Filter)
 		if err != nil {
 			return nil, fmt.Errorf("parse filter: %w", err)
 		}
 		filter = parsed
 	}
 
+	// Restrict to the sync's scope (flow, keyed); global adds nothing
+	if scopeFilter != nil {
+		if filter == nil {
+			filter = scopeFilter
+		} else {
+			filter = queryir.And{Predicates: []queryir.Predicate{filter, scopeFilter}}
+		}
+	}
+
 	// Build SELECT query
 	query := queryir.Select{
 		From:     where.Source,
@@ -117,236 +122,15 @@ func (e *Engine) buildQueryFromWhere(
 	return query, nil
 }
 
-// parseFilterExpression parses a filter expression string into a QueryIR Predicate.
-//
-// Supported expression formats:
-//   - "field == value" → Equals{Field: "field", Value: value}
-//   - "field == bound.var" → BoundEquals{Field: "field", BoundVar: "bound.var"}
-//   - "expr1 AND expr2" → And{Predicates: [expr1, expr2]}
-//
-// This is a simplified parser for MVP. Full expression parsing would use
-// a proper AST parser from the CUE compiler.
-func parseFilterExpression(filter string) (queryir.Predicate, error) {
-	filter = strings.TrimSpace(filter)
-	if filter == "" {
-		return nil, nil
-	}
-
-	// Check for AND expressions (split by " AND " or " and ")
-	andParts := splitByAnd(filter)
-	if len(andParts) > 1 {
-		predicates := make([]queryir.Predicate, 0, len(andParts))
-		for _, part := range andParts {
-			pred, err := parseFilterExpression(part)
-			if err != nil {
-				return nil, err
-			}
-			if pred != nil {
-				predicates = append(predicates, pred)
-			}
-		}
-		if len(predicates) == 0 {
-			return nil, nil
-		}
-		if len(predicates) == 1 {
-			return predicates[0], nil
-		}
-		return queryir.And{Predicates: predicates}, nil
-	}
-
-	// Parse single comparison: "field == value" or "field == bound.var"
-	return parseSingleComparison(filter)
-}
-
-// splitByAnd splits a filter expression by AND (case insensitive).
-func splitByAnd(filter string) []string {
-	// Simple split - look for " AND " or " and "
-	var parts []string
-	remaining := filter
-
-	for {
-		// Find " AND " (case insensitive)
-		lowerRemaining := strings.ToLower(remaining)
-		idx := strings.Index(lowerRemaining, " and ")
-		if idx == -1 {
-			parts = append(parts, strings.TrimSpace(remaining))
-			break
-		}
-
-		parts = append(parts, strings.TrimSpace(remaining[:idx]))
-		remaining = remaining[idx+5:] // len(" and ") == 5
-	}
-
-	return parts
-}
-
-// parseSingleComparison parses a single comparison expression.
-// Supports: "field == value", "field == bound.var", "field == 'literal'"
-func parseSingleComparison(expr string) (queryir.Predicate, error) {
-	expr = strings.TrimSpace(expr)
-
-	// Look for "==" operator
-	idx := strings.Index(expr, "==")
-	if idx == -1 {
-		// Try single "=" as well
-		idx = strings.Index(expr, "=")
-		if idx == -1 {
-			return nil, fmt.Errorf("unsupported expression (no == found): %s", expr)
-		}
-		// Make sure it's not part of !=
-		if idx > 0 && expr[idx-1] == '!' {
-			return nil, fmt.Errorf("unsupported operator != in: %s", expr)
-		}
-	}
-
-	// Split into field and value parts
-	var field, value string
-	if strings.Index(expr, "==") != -1 {
-		parts := strings.SplitN(expr, "==", 2)
-		field = strings.TrimSpace(parts[0])
-		value = strings.TrimSpace(parts[1])
-	} else {
-		parts := strings.SplitN(expr, "=", 2)
-		field = strings.TrimSpace(parts[0])
-		value = strings.TrimSpace(parts[1])
-	}
-
-	// Check if value is a bound variable reference
-	if strings.HasPrefix(value, "bound.") {
-		return queryir.BoundEquals{
-			Field:    field,
-			BoundVar: value,
-		}, nil
-	}
-
-	// Check if value is a string literal (quoted)
-	if (strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'")) ||
-		(strings.HasPrefix(value, "\"") && strings.HasSuffix(value, "\"")) {
-		// Remove quotes
-		literal := value[1 : len(value)-1]
-		return queryir.Equals{
-			Field: field,
-			Value: ir.IRString(literal),
-		}, nil
-	}
-
-	// Check if value is a number
-	if isNumeric(value) {
-		intVal := parseInt(value)
-		return queryir.Equals{
-			Field: field,
-			Value: ir.IRInt(intVal),
-		}, nil
-	}
-
-	// Check if value is a boolean
-	if value == "true" {
-		return queryir.Equals{
-			Field: field,
-			Value: ir.IRBool(true),
-		}, nil
-	}
-	if value == "false" {
-		return queryir.Equals{
-			Field: field,
-			Value: ir.IRBool(false),
-		}, nil
-	}
-
-	// Assume unquoted string literal
-	return queryir.Equals{
-		Field: field,
-		Value: ir.IRString(value),
-	}, nil
-}
-
-// isNumeric checks if a string is a valid integer.
-func isNumeric(s string) bool {
-	if s == "" {
-		return false
-	}
-	start := 0
-	if s[0] == '-' || s[0] == '+' {
-		start = 1
-	}
-	if start >= len(s) {
-		return false
-	}
-	for i := start; i < len(s); i++ {
-		if s[i] < '0' || s[i] > '9' {
-			return false
-		}
-	}
-	return true
-}
-
-// parseInt parses a string to int64.
-func parseInt(s string) int64 {
-	var result int64
-	negative := false
-	start := 0
-
-	if s[0] == '-' {
-		negative = true
-		start = 1
-	} else if s[0] == '+' {
-		start = 1
-	}
-
-	for i := start; i < len(s); i++ {
-		result = result*10 + int64(s[i]-'0')
-	}
-
-	if negative {
-		return -result
-	}
-	return result
-}
-
-// scanBinding scans a SQL row into an ir.IRObject.
-// Maps SQL columns to binding variable names per bindingSpec.
-func scanBinding(
-	rows *sql.Rows,
-	bindingSpec map[string]string,
-) (ir.IRObject, error) {
-	// Get column names from result set
-	columns, err := rows.Columns()
-	if err != nil {
-		return nil, fmt.Errorf("get columns: %w", err)
-	}
-
-	// Create scan targets (interface{} for each column)
-	values := make([]interface{}, len(columns))
-	valuePtrs := make([]interface{}, len(columns))
-	for i := range values {
-		valuePtrs[i] = &values[i]
+// whereBindingVars returns the variable names a where-clause binds, sorted.
+// These are the fields that distinguish one binding set from another.
+func whereBindingVars(where *ir.WhereClause) []string {
+	vars := make([]string, 0, len(where.Bindings))
+	for _, v := range where.Bindings {
+		vars = append(vars, v)
 	}
-
-	// Scan row into values
-	if err := rows.Scan(valuePtrs...); err != nil {
-		return nil, fmt.Errorf("scan row: %w", err)
-	}
-
-	// Build binding object
-	binding := make(ir.IRObject)
-	for i, colName := range columns {
-		// Look up variable name for this column
-		varName, exists := bindingSpec[colName]
-		if !exists {
-			// Column not in binding spec - skip
-			continue
-		}
-
-		// Convert SQL value to IRValue
-		irValue, err := sqlToIRValue(values[i])
-		if err != nil {
-			return nil, fmt.Errorf("convert column %s: %w", colName, err)
-		}
-
-		binding[varName] = irValue
-	}
-
-	return binding, nil
+	sort.Strings(vars)
+	return vars
 }
 
 // mergeBindings is defined in scope.go with better nil handling.
@@ -354,32 +138,9 @@ func scanBinding(
 // taking precedence if there are conflicts.
 
 // sqlToIRValue converts a SQL value (from database/sql) to an ir.IRValue.
+// Delegates to the SQL backend so the engine and backend agree on CP-5.
 func sqlToIRValue(v interface{}) (ir.IRValue, error) {
-	if v == nil {
-		return ir.IRNull{}, nil
-	}
-
-	switch val := v.(type) {
-	case int64:
-		return ir.IRInt(val), nil
-	case int:
-		return ir.IRInt(int64(val)), nil
-	case float64:
-		// CP-5: Floats are FORBIDDEN in IR - they break determinism.
-		// SQL REAL/FLOAT columns must be avoided in schema design.
-		// If you hit this, either:
-		// 1. Change the column type to INTEGER (store cents not dollars)
-		// 2. Store as TEXT with explicit precision
-		return nil, fmt.Errorf("float64 values are forbidden in IR (CP-5): %v - use INTEGER or TEXT instead", val)
-	case string:
-		return ir.IRString(val), nil
-	case []byte:
-		return ir.IRString(string(val)), nil
-	case bool:
-		return ir.IRBool(val), nil
-	default:
-		return nil, fmt.Errorf("unsupported SQL type: %T", v)
-	}
+	return querysql.FromSQLValue(v)
 }
 
 // irValueToSQLParam converts an ir.IRValue to a Go native type for SQL parameter.
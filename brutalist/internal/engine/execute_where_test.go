@@ -3,6 +3,7 @@ package engine
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -11,7 +12,16 @@ import (
 	"github.com/roach88/nysm/internal/queryir"
 )
 
-// TestParseFilterExpression tests filter expression parsing.
+// whereFilter parses filter the way the engine does for a where-clause.
+func whereFilter(filter string) (queryir.Predicate, error) {
+	query, err := (&Engine{}).buildQueryFromWhere(&ir.WhereClause{Source: "cart_items", Filter: filter}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return query.(queryir.Select).Filter, nil
+}
+
+// TestParseFilterExpression tests where-clause filter parsing.
 func TestParseFilterExpression(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -123,14 +133,33 @@ func TestParseFilterExpression(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name:   "single equals operator",
-			filter: "status = 'active'",
-			expected: queryir.Equals{
-				Field: "status",
-				Value: ir.IRString("active"),
+			name:   "&& expression",
+			filter: "cart_id == bound.cartId && qty >= 5",
+			expected: queryir.And{
+				Predicates: []queryir.Predicate{
+					queryir.BoundEquals{Field: "cart_id", BoundVar: "bound.cartId"},
+					queryir.GreaterOrEqual{Field: "qty", Value: ir.IRInt(5)},
+				},
 			},
 			wantErr: false,
 		},
+		{
+			name:     "comparison operator",
+			filter:   "qty >= 5",
+			expected: queryir.GreaterOrEqual{Field: "qty", Value: ir.IRInt(5)},
+			wantErr:  false,
+		},
+		{
+			name:     "&& inside a string literal",
+			filter:   "note == 'a && b'",
+			expected: queryir.Equals{Field: "note", Value: ir.IRString("a && b")},
+			wantErr:  false,
+		},
+		{
+			name:    "single equals operator",
+			filter:  "status = 'active'",
+			wantErr: true,
+		},
 		{
 			name:    "unsupported != operator",
 			filter:  "status != 'deleted'",
@@ -142,19 +171,15 @@ func TestParseFilterExpression(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name:   "unquoted string literal",
-			filter: "status == active",
-			expected: queryir.Equals{
-				Field: "status",
-				Value: ir.IRString("active"),
-			},
-			wantErr: false,
+			name:    "unquoted string literal",
+			filter:  "status == active",
+			wantErr: true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := parseFilterExpression(tt.filter)
+			result, err := whereFilter(tt.filter)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -167,96 +192,6 @@ func TestParseFilterExpression(t *testing.T) {
 	}
 }
 
-// TestSplitByAnd tests AND splitting.
-func TestSplitByAnd(t *testing.T) {
-	tests := []struct {
-		name     string
-		filter   string
-		expected []string
-	}{
-		{
-			name:     "no AND",
-			filter:   "status == 'active'",
-			expected: []string{"status == 'active'"},
-		},
-		{
-			name:     "single AND",
-			filter:   "a == 1 AND b == 2",
-			expected: []string{"a == 1", "b == 2"},
-		},
-		{
-			name:     "multiple ANDs",
-			filter:   "a == 1 AND b == 2 AND c == 3",
-			expected: []string{"a == 1", "b == 2", "c == 3"},
-		},
-		{
-			name:     "lowercase and",
-			filter:   "a == 1 and b == 2",
-			expected: []string{"a == 1", "b == 2"},
-		},
-		{
-			name:     "mixed case",
-			filter:   "a == 1 AND b == 2 and c == 3",
-			expected: []string{"a == 1", "b == 2", "c == 3"},
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := splitByAnd(tt.filter)
-			assert.Equal(t, tt.expected, result)
-		})
-	}
-}
-
-// TestIsNumeric tests numeric string detection.
-func TestIsNumeric(t *testing.T) {
-	tests := []struct {
-		input    string
-		expected bool
-	}{
-		{"42", true},
-		{"-42", true},
-		{"+42", true},
-		{"0", true},
-		{"123456789", true},
-		{"", false},
-		{"abc", false},
-		{"12abc", false},
-		{"12.34", false}, // Floats not supported
-		{"-", false},
-		{"+", false},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.input, func(t *testing.T) {
-			result := isNumeric(tt.input)
-			assert.Equal(t, tt.expected, result)
-		})
-	}
-}
-
-// TestParseInt tests integer parsing.
-func TestParseInt(t *testing.T) {
-	tests := []struct {
-		input    string
-		expected int64
-	}{
-		{"42", 42},
-		{"-42", -42},
-		{"+42", 42},
-		{"0", 0},
-		{"123456789", 123456789},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.input, func(t *testing.T) {
-			result := parseInt(tt.input)
-			assert.Equal(t, tt.expected, result)
-		})
-	}
-}
-
 // TestSqlToIRValue tests SQL to IR value conversion.
 func TestSqlToIRValue(t *testing.T) {
 	tests := []struct {
@@ -469,7 +404,7 @@ func TestParseSingleComparison(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := parseSingleComparison(tt.expr)
+			result, err := whereFilter(tt.expr)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -564,7 +499,7 @@ func TestBuildQueryFromWhere(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			query, err := e.buildQueryFromWhere(tt.where, tt.whenBindings)
+			query, err := e.buildQueryFromWhere(tt.where, tt.whenBindings, nil)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -593,13 +528,115 @@ func TestExecuteWhereNilClause(t *testing.T) {
 	}
 
 	// When where-clause is nil, should return single binding set with when-bindings
-	result, err := e.executeWhere(ctx, nil, whenBindings, "")
+	result, err := e.executeWhere(ctx, nil, whenBindings, "", nil)
 
 	require.NoError(t, err)
 	require.Len(t, result, 1, "nil where-clause should return single binding set")
 	assert.Equal(t, whenBindings, result[0])
 }
 
+// TestExecuteWhere_QueriesStore tests that a where-clause runs against the store
+// and yields one merged binding set per row in CP-4 order.
+func TestExecuteWhere_QueriesStore(t *testing.T) {
+	s := setupTestStore(t)
+	e := New(s, nil, nil, nil)
+	ctx := context.Background()
+
+	_, err := s.DB().ExecContext(ctx, `
+		CREATE TABLE CartItems (id TEXT PRIMARY KEY, seq INTEGER, cart_id TEXT, item_id TEXT);
+		INSERT INTO CartItems VALUES
+			('ci-2', 1, 'cart-1', 'gadget'),
+			('ci-1', 1, 'cart-1', 'widget'),
+			('ci-3', 2, 'cart-2', 'gizmo');
+	`)
+	require.NoError(t, err)
+
+	where := &ir.WhereClause{
+		Source:   "CartItems",
+		Filter:   "cart_id == bound.cartId",
+		Bindings: map[string]string{"item_id": "itemId"},
+	}
+	whenBindings := ir.IRObject{"cartId": ir.IRString("cart-1")}
+
+	result, err := e.executeWhere(ctx, where, whenBindings, "flow-1", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []ir.IRObject{
+		{"cartId": ir.IRString("cart-1"), "itemId": ir.IRString("widget")},
+		{"cartId": ir.IRString("cart-1"), "itemId": ir.IRString("gadget")},
+	}, result)
+}
+
+// TestEvaluateSyncs_WhereClauseFiresPerRow tests that evaluateSyncs fires
+// the sync once per where-clause binding set.
+func TestEvaluateSyncs_WhereClauseFiresPerRow(t *testing.T) {
+	s := setupTestStore(t)
+	ctx := context.Background()
+
+	_, err := s.DB().ExecContext(ctx, `
+		CREATE TABLE CartItems (id TEXT PRIMARY KEY, seq INTEGER, flow_token TEXT, cart_id TEXT, item_id TEXT);
+		INSERT INTO CartItems VALUES
+			('ci-1', 1, 'flow-1', 'cart-1', 'widget'),
+			('ci-2', 2, 'flow-1', 'cart-1', 'gadget');
+	`)
+	require.NoError(t, err)
+
+	syncs := []ir.SyncRule{{
+		ID: "reserve-each-item",
+		When: ir.WhenClause{
+			ActionRef: "Cart.checkout",
+			EventType: "completed",
+			Bindings:  map[string]string{"cartId": "cart_id"},
+		},
+		Where: &ir.WhereClause{
+			Source:   "CartItems",
+			Filter:   "cart_id == bound.cartId",
+			Bindings: map[string]string{"item_id": "itemId"},
+		},
+		Then: ir.ThenClause{
+			ActionRef: "Inventory.reserve",
+			Args:      map[string]string{"item": "${bound.itemId}"},
+		},
+	}}
+	e := NewWithClock(s, nil, syncs, nil, NewClockAt(2))
+
+	args := ir.IRObject{}
+	invID := ir.MustInvocationID("flow-1", "Cart.checkout", args, 1)
+	require.NoError(t, s.WriteInvocation(ctx, ir.Invocation{
+		ID:              invID,
+		FlowToken:       "flow-1",
+		ActionURI:       "Cart.checkout",
+		Args:            args,
+		Seq:             1,
+		EngineVersion:   ir.EngineVersion,
+		IRVersion:       ir.IRVersion,
+		SecurityContext: testSecurityContext,
+	}))
+	result := ir.IRObject{"cart_id": ir.IRString("cart-1")}
+	comp := &ir.Completion{
+		ID:              ir.MustCompletionID(invID, "Success", result, 2),
+		InvocationID:    invID,
+		OutputCase:      "Success",
+		Result:          result,
+		Seq:             2,
+		SecurityContext: testSecurityContext,
+	}
+	require.NoError(t, s.WriteCompletion(ctx, *comp))
+
+	require.NoError(t, e.evaluateSyncs(ctx, comp))
+
+	firings, err := s.ReadSyncFiringsForCompletion(ctx, comp.ID)
+	require.NoError(t, err)
+	require.Len(t, firings, 2, "expected one firing per where-clause row")
+
+	pending, err := s.GetPendingInvocations(ctx, "flow-1")
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	// Fired in canonical binding order (ir.SortBindings), not row order
+	assert.Equal(t, ir.IRString("gadget"), pending[0].Args["item"])
+	assert.Equal(t, ir.IRString("widget"), pending[1].Args["item"])
+}
+
 // TestMergeBindings_FromScope tests the mergeBindings function from scope.go.
 func TestMergeBindings_FromScope(t *testing.T) {
 	tests := []struct {
@@ -680,7 +717,7 @@ func TestParseFilterExpressionWhitespace(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := parseFilterExpression(tt.filter)
+			result, err := whereFilter(tt.filter)
 			require.NoError(t, err)
 			require.NotNil(t, result)
 
@@ -715,7 +752,7 @@ func TestIRValueConversionRoundTrip(t *testing.T) {
 			sqlVal, err := irValueToSQLParam(tt.value)
 			require.NoError(t, err)
 
-			// SQL -> IR (simulating what scanBinding does)
+			// SQL -> IR (simulating what the SQL backend does on scan)
 			irVal, err := sqlToIRValue(sqlVal)
 			require.NoError(t, err)
 
@@ -723,3 +760,294 @@ func TestIRValueConversionRoundTrip(t *testing.T) {
 		})
 	}
 }
+
+// seedScopedCartItems creates a CartItems state table spanning two flows and
+// two users, for scope filtering tests.
+func seedScopedCartItems(t *testing.T, e *Engine) {
+	t.Helper()
+	_, err := e.store.DB().ExecContext(context.Background(), `
+		CREATE TABLE CartItems (id TEXT PRIMARY KEY, seq INTEGER, flow_token TEXT, user_id TEXT, item_id TEXT);
+		INSERT INTO CartItems VALUES
+			('ci-1', 1, 'flow-1', 'alice', 'widget'),
+			('ci-2', 2, 'flow-2', 'alice', 'gadget'),
+			('ci-3', 3, 'flow-1', 'bob', 'gizmo'),
+			('ci-4', 4, 'flow-3', 'carol', 'doohickey');
+	`)
+	require.NoError(t, err)
+}
+
+// scopedItemIDs runs a where-clause over CartItems under scope and returns
+// the bound item IDs in result (canonical binding) order.
+func scopedItemIDs(t *testing.T, e *Engine, scope ir.ScopeSpec, flowToken string, whenBindings ir.IRObject) []string {
+	t.Helper()
+	sync := ir.SyncRule{
+		ID:    "scoped",
+		Scope: scope,
+		Where: &ir.WhereClause{
+			Source:   "CartItems",
+			Bindings: map[string]string{"item_id": "itemId"},
+		},
+	}
+
+	bindings, err := e.executeWhereClause(context.Background(), sync, flowToken, whenBindings)
+	require.NoError(t, err)
+
+	ids := make([]string, 0, len(bindings))
+	for _, b := range bindings {
+		ids = append(ids, string(b["itemId"].(ir.IRString)))
+	}
+	return ids
+}
+
+func TestExecuteWhereClause_FlowScopeReturnsSameFlowRows(t *testing.T) {
+	e := New(setupTestStore(t), nil, nil, nil)
+	seedScopedCartItems(t, e)
+
+	ids := scopedItemIDs(t, e, ir.ScopeSpec{Mode: "flow"}, "flow-1", ir.IRObject{})
+	assert.Equal(t, []string{"gizmo", "widget"}, ids)
+
+	// Empty mode defaults to flow scope
+	ids = scopedItemIDs(t, e, ir.ScopeSpec{}, "flow-2", ir.IRObject{})
+	assert.Equal(t, []string{"gadget"}, ids)
+}
+
+func TestExecuteWhereClause_KeyedScopeReturnsMatchingKeyRows(t *testing.T) {
+	e := New(setupTestStore(t), nil, nil, nil)
+	seedScopedCartItems(t, e)
+
+	// Keyed scope crosses flows: alice's items from flow-1 and flow-2
+	ids := scopedItemIDs(t, e,
+		ir.ScopeSpec{Mode: "keyed", Key: "user_id"},
+		"flow-1",
+		ir.IRObject{"user_id": ir.IRString("alice")},
+	)
+	assert.Equal(t, []string{"gadget", "widget"}, ids)
+}
+
+func TestExecuteWhereClause_KeyedScopeMissingKeyBinding(t *testing.T) {
+	e := New(setupTestStore(t), nil, nil, nil)
+	seedScopedCartItems(t, e)
+
+	sync := ir.SyncRule{
+		ID:    "scoped",
+		Scope: ir.ScopeSpec{Mode: "keyed", Key: "user_id"},
+		Where: &ir.WhereClause{Source: "CartItems", Bindings: map[string]string{"item_id": "itemId"}},
+	}
+	_, err := e.executeWhereClause(context.Background(), sync, "flow-1", ir.IRObject{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `key field "user_id" not found`)
+}
+
+func TestExecuteWhereClause_GlobalScopeReturnsAllRows(t *testing.T) {
+	e := New(setupTestStore(t), nil, nil, nil)
+	seedScopedCartItems(t, e)
+
+	ids := scopedItemIDs(t, e, ir.ScopeSpec{Mode: "global"}, "flow-1", ir.IRObject{})
+	assert.Equal(t, []string{"doohickey", "gadget", "gizmo", "widget"}, ids)
+}
+
+func TestExecuteWhereClause_ScopeCombinesWithFilter(t *testing.T) {
+	e := New(setupTestStore(t), nil, nil, nil)
+	seedScopedCartItems(t, e)
+
+	sync := ir.SyncRule{
+		ID:    "scoped",
+		Scope: ir.ScopeSpec{Mode: "flow"},
+		Where: &ir.WhereClause{
+			Source:   "CartItems",
+			Filter:   "user_id == bound.user",
+			Bindings: map[string]string{"item_id": "itemId"},
+		},
+	}
+	bindings, err := e.executeWhereClause(context.Background(), sync, "flow-1",
+		ir.IRObject{"user": ir.IRString("bob")})
+	require.NoError(t, err)
+	require.Len(t, bindings, 1)
+	assert.Equal(t, ir.IRString("gizmo"), bindings[0]["itemId"])
+}
+
+// seedManyCartItems creates a CartItems table with n rows in flow-1, cart-1.
+func seedManyCartItems(t *testing.T, e *Engine, n int) {
+	t.Helper()
+	ctx := context.Background()
+	_, err := e.store.DB().ExecContext(ctx,
+		`CREATE TABLE CartItems (id TEXT PRIMARY KEY, seq INTEGER, flow_token TEXT, cart_id TEXT, item_id TEXT)`)
+	require.NoError(t, err)
+	for i := 0; i < n; i++ {
+		require.NoError(t, e.store.ExecState(ctx, "CartItems", ir.IRObject{
+			"id":         ir.IRString(fmt.Sprintf("ci-%d", i)),
+			"seq":        ir.IRInt(i),
+			"flow_token": ir.IRString("flow-1"),
+			"cart_id":    ir.IRString("cart-1"),
+			"item_id":    ir.IRString(fmt.Sprintf("item-%d", i)),
+		}))
+	}
+}
+
+var fanOutSync = ir.SyncRule{
+	ID: "reserve-each-item",
+	Where: &ir.WhereClause{
+		Source:   "CartItems",
+		Filter:   "cart_id == bound.cartId",
+		Bindings: map[string]string{"item_id": "itemId"},
+	},
+}
+
+func TestExecuteWhereClause_BindingExplosionRejected(t *testing.T) {
+	e := New(setupTestStore(t), nil, nil, nil, WithMaxBindingsPerFiring(3))
+	seedManyCartItems(t, e, 4)
+
+	_, err := e.executeWhereClause(context.Background(), fanOutSync, "flow-1",
+		ir.IRObject{"cartId": ir.IRString("cart-1")})
+	require.Error(t, err)
+	assert.True(t, IsBindingExplosionError(err))
+	assert.Equal(t, ErrCodeBindingExplosion, ErrorCode(err))
+
+	var re *RuntimeError
+	require.ErrorAs(t, err, &re)
+	assert.Equal(t, "reserve-each-item", re.SyncID)
+	assert.Equal(t, "flow-1", re.FlowToken)
+	assert.Equal(t, "4", re.Details["count"])
+	assert.Equal(t, "3", re.Details["max_bindings"])
+}
+
+func TestExecuteWhereClause_BindingsAtCapAllowed(t *testing.T) {
+	e := New(setupTestStore(t), nil, nil, nil, WithMaxBindingsPerFiring(3))
+	seedManyCartItems(t, e, 3)
+
+	bindings, err := e.executeWhereClause(context.Background(), fanOutSync, "flow-1",
+		ir.IRObject{"cartId": ir.IRString("cart-1")})
+	require.NoError(t, err)
+	assert.Len(t, bindings, 3)
+}
+
+func TestWithMaxBindingsPerFiring_DefaultIsFinite(t *testing.T) {
+	e := New(setupTestStore(t), nil, nil, nil)
+	assert.Equal(t, DefaultMaxBindingsPerFiring, e.maxBindingsPerFiring)
+
+	e = New(setupTestStore(t), nil, nil, nil, WithMaxBindingsPerFiring(0))
+	assert.Equal(t, DefaultMaxBindingsPerFiring, e.maxBindingsPerFiring, "non-positive cap keeps the default")
+}
+
+func TestEvaluateSyncs_BindingExplosionWritesNothing(t *testing.T) {
+	s := setupTestStore(t)
+	ctx := context.Background()
+
+	sync := fanOutSync
+	sync.When = ir.WhenClause{
+		ActionRef: "Cart.checkout",
+		EventType: "completed",
+		Bindings:  map[string]string{"cartId": "cart_id"},
+	}
+	sync.Then = ir.ThenClause{
+		ActionRef: "Inventory.reserve",
+		Args:      map[string]string{"item": "${bound.itemId}"},
+	}
+	e := NewWithClock(s, nil, []ir.SyncRule{sync}, nil, NewClockAt(2), WithMaxBindingsPerFiring(2))
+	seedManyCartItems(t, e, 5)
+
+	args := ir.IRObject{}
+	invID := ir.MustInvocationID("flow-1", "Cart.checkout", args, 1)
+	require.NoError(t, s.WriteInvocation(ctx, ir.Invocation{
+		ID:              invID,
+		FlowToken:       "flow-1",
+		ActionURI:       "Cart.checkout",
+		Args:            args,
+		Seq:             1,
+		EngineVersion:   ir.EngineVersion,
+		IRVersion:       ir.IRVersion,
+		SecurityContext: testSecurityContext,
+	}))
+	result := ir.IRObject{"cart_id": ir.IRString("cart-1")}
+	comp := &ir.Completion{
+		ID:              ir.MustCompletionID(invID, "Success", result, 2),
+		InvocationID:    invID,
+		OutputCase:      "Success",
+		Result:          result,
+		Seq:             2,
+		SecurityContext: testSecurityContext,
+	}
+
+	err := e.ProcessCompletion(ctx, comp)
+	require.Error(t, err)
+	assert.True(t, IsBindingExplosionError(err))
+
+	firings, err := s.ReadSyncFiringsForCompletion(ctx, comp.ID)
+	require.NoError(t, err)
+	assert.Empty(t, firings, "no invocations may be generated past the cap")
+}
+
+// TestEvaluateSyncs_FiringOrderIndependentOfRowOrder tests that the same
+// state rows stored in different orders fire identically, down to the
+// content-addressed invocation IDs (which include seq).
+func TestEvaluateSyncs_FiringOrderIndependentOfRowOrder(t *testing.T) {
+	rowOrders := [][]string{
+		{"widget", "gadget", "gizmo"},
+		{"gizmo", "widget", "gadget"},
+	}
+
+	var runs [][]string
+	for _, items := range rowOrders {
+		s := setupTestStore(t)
+		ctx := context.Background()
+
+		_, err := s.DB().ExecContext(ctx,
+			`CREATE TABLE CartItems (id TEXT PRIMARY KEY, seq INTEGER, flow_token TEXT, cart_id TEXT, item_id TEXT)`)
+		require.NoError(t, err)
+		for i, item := range items {
+			require.NoError(t, s.ExecState(ctx, "CartItems", ir.IRObject{
+				"id":         ir.IRString(fmt.Sprintf("ci-%d", i)),
+				"seq":        ir.IRInt(i),
+				"flow_token": ir.IRString("flow-1"),
+				"cart_id":    ir.IRString("cart-1"),
+				"item_id":    ir.IRString(item),
+			}))
+		}
+
+		sync := fanOutSync
+		sync.When = ir.WhenClause{
+			ActionRef: "Cart.checkout",
+			EventType: "completed",
+			Bindings:  map[string]string{"cartId": "cart_id"},
+		}
+		sync.Then = ir.ThenClause{
+			ActionRef: "Inventory.reserve",
+			Args:      map[string]string{"item": "${bound.itemId}"},
+		}
+		e := NewWithClock(s, nil, []ir.SyncRule{sync}, nil, NewClockAt(2))
+
+		args := ir.IRObject{}
+		invID := ir.MustInvocationID("flow-1", "Cart.checkout", args, 1)
+		require.NoError(t, s.WriteInvocation(ctx, ir.Invocation{
+			ID:              invID,
+			FlowToken:       "flow-1",
+			ActionURI:       "Cart.checkout",
+			Args:            args,
+			Seq:             1,
+			EngineVersion:   ir.EngineVersion,
+			IRVersion:       ir.IRVersion,
+			SecurityContext: testSecurityContext,
+		}))
+		result := ir.IRObject{"cart_id": ir.IRString("cart-1")}
+		comp := &ir.Completion{
+			ID:              ir.MustCompletionID(invID, "Success", result, 2),
+			InvocationID:    invID,
+			OutputCase:      "Success",
+			Result:          result,
+			Seq:             2,
+			SecurityContext: testSecurityContext,
+		}
+		require.NoError(t, e.ProcessCompletion(ctx, comp))
+
+		pending, err := s.GetPendingInvocations(ctx, "flow-1")
+		require.NoError(t, err)
+		ids := make([]string, 0, len(pending))
+		for _, inv := range pending {
+			ids = append(ids, inv.ID)
+		}
+		runs = append(runs, ids)
+	}
+
+	require.Len(t, runs[0], 3)
+	assert.Equal(t, runs[0], runs[1], "row order must not change firing order or seqs")
+}
@@ -64,7 +64,10 @@ func (e *Engine) executeThen(
 		// This is checked BEFORE firing to prevent infinite loops.
 		// Distinct from idempotency: cycles are per-flow, idempotency is per-completion.
 		if e.cycleDetector.WouldCycle(flowToken, sync.ID, bindingHash) {
-			return NewCycleError(flowToken, sync.ID, bindingHash)
+			cycleErr := NewCycleError(flowToken, sync.ID, bindingHash)
+			e.metrics.cyclesDetected.Add(1)
+			e.listener.OnCycleDetected(cycleErr)
+			return cycleErr
 		}
 
 		// NOTE: Record() happens AFTER WriteSyncFiringAtomic, not here.
@@ -99,6 +102,11 @@ func (e *Engine) executeThen(
 			IRVersion:       ir.IRVersion,
 		}
 
+		// A vetoed binding is skipped without writing anything (see validator.go)
+		if e.rejectInvocation(sync, bindingHash, inv) {
+			continue
+		}
+
 		// Create sync firing record
 		firing := ir.SyncFiring{
 			CompletionID: completion.ID,
@@ -109,7 +117,7 @@ func (e *Engine) executeThen(
 
 		// CRASH ATOMICITY (CP-1): Atomically write firing, invocation, provenance edge.
 		// If inserted=false, this binding already fired (replay scenario) - skip enqueue.
-		_, inserted, err := e.store.WriteSyncFiringAtomic(ctx, firing, inv)
+		firingID, inserted, err := e.store.WriteSyncFiringAtomic(ctx, firing, inv)
 		if err != nil {
 			return fmt.Errorf("atomic write firing: %w", err)
 		}
@@ -120,7 +128,10 @@ func (e *Engine) executeThen(
 		// - Fresh engine + replay: WouldCycle=false, Write inserted=false, no Record
 		// - Same engine + cycle: WouldCycle=true (already recorded), error returned
 		if inserted {
+			firing.ID = firingID
 			e.cycleDetector.Record(flowToken, sync.ID, bindingHash)
+			e.metrics.syncsFired.Add(1)
+			e.listener.OnSyncFired(firing, inv)
 			e.queue.Enqueue(Event{
 				Type:       EventTypeInvocation,
 				Invocation: &inv,
@@ -135,9 +146,11 @@ func (e *Engine) executeThen(
 //
 // Arg templates support "bound.varName" syntax for binding substitution:
 //   - "bound.item_id" -> looks up "item_id" in bindings
-//   - Literal strings (not starting with "bound.") passed through unchanged
+//   - Typed literals "int:5", "bool:true", "str:foo" -> IRInt, IRBool, IRString
+//   - Other strings passed through unchanged as IRString
 //
-// Returns error if binding variable not found.
+// Returns error if binding variable not found or a typed literal is invalid
+// (including the forbidden "float:" prefix, CP-5).
 // All-or-nothing: partial substitution not allowed.
 //
 // Example:
@@ -163,8 +176,12 @@ func resolveArgs(argTemplates map[string]string, bindings ir.IRObject) (ir.IRObj
 			// Substitute binding value
 			resolvedArgs[key] = value
 		} else {
-			// Literal value - pass through as IRString
-			resolvedArgs[key] = ir.IRString(template)
+			// Literal value - typed ("int:5") or a bare IRString
+			value, err := ir.ParseArgLiteral(template)
+			if err != nil {
+				return nil, fmt.Errorf("arg %q: %w", key, err)
+			}
+			resolvedArgs[key] = value
 		}
 	}
 
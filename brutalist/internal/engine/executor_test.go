@@ -65,6 +65,57 @@ func TestResolveArgs_LiteralPassthrough(t *testing.T) {
 	assert.Equal(t, ir.IRString("true"), resolvedArgs["enabled"])
 }
 
+// TestResolveArgs_TypedLiterals tests int:, bool:, and str: literal prefixes.
+func TestResolveArgs_TypedLiterals(t *testing.T) {
+	argTemplates := map[string]string{
+		"quantity": "int:5",
+		"negative": "int:-2",
+		"enabled":  "bool:true",
+		"disabled": "bool:false",
+		"label":    "str:bound.item_id", // Escapes what would be a reference
+		"note":     "plain",
+	}
+
+	resolvedArgs, err := resolveArgs(argTemplates, ir.IRObject{})
+	require.NoError(t, err)
+
+	assert.Equal(t, ir.IRObject{
+		"quantity": ir.IRInt(5),
+		"negative": ir.IRInt(-2),
+		"enabled":  ir.IRBool(true),
+		"disabled": ir.IRBool(false),
+		"label":    ir.IRString("bound.item_id"),
+		"note":     ir.IRString("plain"),
+	}, resolvedArgs)
+}
+
+// TestResolveArgs_InvalidTypedLiterals tests that float: (CP-5) and
+// malformed typed literals are rejected.
+func TestResolveArgs_InvalidTypedLiterals(t *testing.T) {
+	tests := []struct {
+		template string
+		wantErr  string
+	}{
+		{"float:1.5", "forbidden (CP-5)"},
+		{"int:five", "invalid int literal"},
+		{"bool:yes", "invalid bool literal"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			_, err := resolveArgs(map[string]string{"value": tt.template}, ir.IRObject{})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+
+			// The ${bound.x} resolution path applies the same rules
+			e := &Engine{}
+			_, err = e.resolveArgs(map[string]string{"value": tt.template}, ir.IRObject{})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
 // TestResolveArgs_MissingVariable tests error on missing binding variable.
 func TestResolveArgs_MissingVariable(t *testing.T) {
 	argTemplates := map[string]string{
@@ -166,20 +217,22 @@ func TestExecuteThen_EmptyBindings(t *testing.T) {
 
 	// Setup completion
 	inv := ir.Invocation{
-		ID:        "inv-1",
-		FlowToken: "flow-1",
-		ActionURI: "Cart.checkout",
-		Args:      ir.IRObject{},
-		Seq:       1,
+		ID:              "inv-1",
+		FlowToken:       "flow-1",
+		ActionURI:       "Cart.checkout",
+		Args:            ir.IRObject{},
+		Seq:             1,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteInvocation(ctx, inv))
 
 	comp := ir.Completion{
-		ID:           "comp-1",
-		InvocationID: "inv-1",
-		OutputCase:   "Success",
-		Result:       ir.IRObject{},
-		Seq:          2,
+		ID:              "comp-1",
+		InvocationID:    "inv-1",
+		OutputCase:      "Success",
+		Result:          ir.IRObject{},
+		Seq:             2,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteCompletion(ctx, comp))
 
@@ -212,20 +265,22 @@ func TestExecuteThen_SingleBinding(t *testing.T) {
 
 	// Setup completion - use high seq so generated invocations are distinguishable
 	inv := ir.Invocation{
-		ID:        "inv-1",
-		FlowToken: "flow-1",
-		ActionURI: "Cart.checkout",
-		Args:      ir.IRObject{},
-		Seq:       100, // High seq so generated invocations (seq=1,2,...) come first
+		ID:              "inv-1",
+		FlowToken:       "flow-1",
+		ActionURI:       "Cart.checkout",
+		Args:            ir.IRObject{},
+		Seq:             100, // High seq so generated invocations (seq=1,2,...) come first
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteInvocation(ctx, inv))
 
 	comp := ir.Completion{
-		ID:           "comp-1",
-		InvocationID: "inv-1",
-		OutputCase:   "Success",
-		Result:       ir.IRObject{"cart_id": ir.IRString("cart-123")},
-		Seq:          101,
+		ID:              "comp-1",
+		InvocationID:    "inv-1",
+		OutputCase:      "Success",
+		Result:          ir.IRObject{"cart_id": ir.IRString("cart-123")},
+		Seq:             101,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteCompletion(ctx, comp))
 
@@ -274,20 +329,22 @@ func TestExecuteThen_MultipleBindings(t *testing.T) {
 
 	// Setup completion - use high seq so generated invocations are distinguishable
 	inv := ir.Invocation{
-		ID:        "inv-1",
-		FlowToken: "flow-1",
-		ActionURI: "Cart.checkout",
-		Args:      ir.IRObject{},
-		Seq:       100,
+		ID:              "inv-1",
+		FlowToken:       "flow-1",
+		ActionURI:       "Cart.checkout",
+		Args:            ir.IRObject{},
+		Seq:             100,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteInvocation(ctx, inv))
 
 	comp := ir.Completion{
-		ID:           "comp-1",
-		InvocationID: "inv-1",
-		OutputCase:   "Success",
-		Result:       ir.IRObject{},
-		Seq:          101,
+		ID:              "comp-1",
+		InvocationID:    "inv-1",
+		OutputCase:      "Success",
+		Result:          ir.IRObject{},
+		Seq:             101,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteCompletion(ctx, comp))
 
@@ -347,20 +404,22 @@ func TestExecuteThen_IdempotencySkip(t *testing.T) {
 
 	// Setup completion
 	inv := ir.Invocation{
-		ID:        "inv-1",
-		FlowToken: "flow-1",
-		ActionURI: "Cart.checkout",
-		Args:      ir.IRObject{},
-		Seq:       1,
+		ID:              "inv-1",
+		FlowToken:       "flow-1",
+		ActionURI:       "Cart.checkout",
+		Args:            ir.IRObject{},
+		Seq:             1,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteInvocation(ctx, inv))
 
 	comp := ir.Completion{
-		ID:           "comp-1",
-		InvocationID: "inv-1",
-		OutputCase:   "Success",
-		Result:       ir.IRObject{},
-		Seq:          2,
+		ID:              "comp-1",
+		InvocationID:    "inv-1",
+		OutputCase:      "Success",
+		Result:          ir.IRObject{},
+		Seq:             2,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteCompletion(ctx, comp))
 
@@ -414,20 +473,22 @@ func TestExecuteThen_PartialIdempotency(t *testing.T) {
 
 	// Setup completion
 	inv := ir.Invocation{
-		ID:        "inv-1",
-		FlowToken: "flow-1",
-		ActionURI: "Cart.checkout",
-		Args:      ir.IRObject{},
-		Seq:       1,
+		ID:              "inv-1",
+		FlowToken:       "flow-1",
+		ActionURI:       "Cart.checkout",
+		Args:            ir.IRObject{},
+		Seq:             1,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteInvocation(ctx, inv))
 
 	comp := ir.Completion{
-		ID:           "comp-1",
-		InvocationID: "inv-1",
-		OutputCase:   "Success",
-		Result:       ir.IRObject{},
-		Seq:          2,
+		ID:              "comp-1",
+		InvocationID:    "inv-1",
+		OutputCase:      "Success",
+		Result:          ir.IRObject{},
+		Seq:             2,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteCompletion(ctx, comp))
 
@@ -476,20 +537,22 @@ func TestExecuteThen_SyncFiringCreated(t *testing.T) {
 
 	// Setup completion
 	inv := ir.Invocation{
-		ID:        "inv-1",
-		FlowToken: "flow-1",
-		ActionURI: "Cart.checkout",
-		Args:      ir.IRObject{},
-		Seq:       1,
+		ID:              "inv-1",
+		FlowToken:       "flow-1",
+		ActionURI:       "Cart.checkout",
+		Args:            ir.IRObject{},
+		Seq:             1,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteInvocation(ctx, inv))
 
 	comp := ir.Completion{
-		ID:           "comp-1",
-		InvocationID: "inv-1",
-		OutputCase:   "Success",
-		Result:       ir.IRObject{},
-		Seq:          2,
+		ID:              "comp-1",
+		InvocationID:    "inv-1",
+		OutputCase:      "Success",
+		Result:          ir.IRObject{},
+		Seq:             2,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteCompletion(ctx, comp))
 
@@ -527,20 +590,22 @@ func TestExecuteThen_ProvenanceCreated(t *testing.T) {
 
 	// Setup completion - use high seq so generated invocations are distinguishable
 	inv := ir.Invocation{
-		ID:        "inv-1",
-		FlowToken: "flow-1",
-		ActionURI: "Cart.checkout",
-		Args:      ir.IRObject{},
-		Seq:       100,
+		ID:              "inv-1",
+		FlowToken:       "flow-1",
+		ActionURI:       "Cart.checkout",
+		Args:            ir.IRObject{},
+		Seq:             100,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteInvocation(ctx, inv))
 
 	comp := ir.Completion{
-		ID:           "comp-1",
-		InvocationID: "inv-1",
-		OutputCase:   "Success",
-		Result:       ir.IRObject{},
-		Seq:          101,
+		ID:              "comp-1",
+		InvocationID:    "inv-1",
+		OutputCase:      "Success",
+		Result:          ir.IRObject{},
+		Seq:             101,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteCompletion(ctx, comp))
 
@@ -591,20 +656,22 @@ func TestExecuteThen_InvocationEnqueued(t *testing.T) {
 
 	// Setup completion
 	inv := ir.Invocation{
-		ID:        "inv-1",
-		FlowToken: "flow-1",
-		ActionURI: "Cart.checkout",
-		Args:      ir.IRObject{},
-		Seq:       1,
+		ID:              "inv-1",
+		FlowToken:       "flow-1",
+		ActionURI:       "Cart.checkout",
+		Args:            ir.IRObject{},
+		Seq:             1,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteInvocation(ctx, inv))
 
 	comp := ir.Completion{
-		ID:           "comp-1",
-		InvocationID: "inv-1",
-		OutputCase:   "Success",
-		Result:       ir.IRObject{},
-		Seq:          2,
+		ID:              "comp-1",
+		InvocationID:    "inv-1",
+		OutputCase:      "Success",
+		Result:          ir.IRObject{},
+		Seq:             2,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteCompletion(ctx, comp))
 
@@ -651,20 +718,22 @@ func TestExecuteThen_ContextCancellation(t *testing.T) {
 
 	// Setup completion
 	inv := ir.Invocation{
-		ID:        "inv-1",
-		FlowToken: "flow-1",
-		ActionURI: "Cart.checkout",
-		Args:      ir.IRObject{},
-		Seq:       1,
+		ID:              "inv-1",
+		FlowToken:       "flow-1",
+		ActionURI:       "Cart.checkout",
+		Args:            ir.IRObject{},
+		Seq:             1,
+		SecurityContext: testSecurityContext,
 	}
 	s.WriteInvocation(context.Background(), inv)
 
 	comp := ir.Completion{
-		ID:           "comp-1",
-		InvocationID: "inv-1",
-		OutputCase:   "Success",
-		Result:       ir.IRObject{},
-		Seq:          2,
+		ID:              "comp-1",
+		InvocationID:    "inv-1",
+		OutputCase:      "Success",
+		Result:          ir.IRObject{},
+		Seq:             2,
+		SecurityContext: testSecurityContext,
 	}
 	s.WriteCompletion(context.Background(), comp)
 
@@ -695,20 +764,22 @@ func TestExecuteThen_ResolveArgsError(t *testing.T) {
 
 	// Setup completion
 	inv := ir.Invocation{
-		ID:        "inv-1",
-		FlowToken: "flow-1",
-		ActionURI: "Cart.checkout",
-		Args:      ir.IRObject{},
-		Seq:       1,
+		ID:              "inv-1",
+		FlowToken:       "flow-1",
+		ActionURI:       "Cart.checkout",
+		Args:            ir.IRObject{},
+		Seq:             1,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteInvocation(ctx, inv))
 
 	comp := ir.Completion{
-		ID:           "comp-1",
-		InvocationID: "inv-1",
-		OutputCase:   "Success",
-		Result:       ir.IRObject{},
-		Seq:          2,
+		ID:              "comp-1",
+		InvocationID:    "inv-1",
+		OutputCase:      "Success",
+		Result:          ir.IRObject{},
+		Seq:             2,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteCompletion(ctx, comp))
 
@@ -742,11 +813,12 @@ func TestExecuteThen_SecurityContextPropagated(t *testing.T) {
 
 	// Setup completion with security context - use high seq
 	inv := ir.Invocation{
-		ID:        "inv-1",
-		FlowToken: "flow-1",
-		ActionURI: "Cart.checkout",
-		Args:      ir.IRObject{},
-		Seq:       100,
+		ID:              "inv-1",
+		FlowToken:       "flow-1",
+		ActionURI:       "Cart.checkout",
+		Args:            ir.IRObject{},
+		Seq:             100,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteInvocation(ctx, inv))
 
@@ -805,20 +877,22 @@ func TestExecuteThen_ContentAddressedID(t *testing.T) {
 
 	// Setup completion - use high seq
 	inv := ir.Invocation{
-		ID:        "inv-1",
-		FlowToken: "flow-1",
-		ActionURI: "Cart.checkout",
-		Args:      ir.IRObject{},
-		Seq:       100,
+		ID:              "inv-1",
+		FlowToken:       "flow-1",
+		ActionURI:       "Cart.checkout",
+		Args:            ir.IRObject{},
+		Seq:             100,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteInvocation(ctx, inv))
 
 	comp := ir.Completion{
-		ID:           "comp-1",
-		InvocationID: "inv-1",
-		OutputCase:   "Success",
-		Result:       ir.IRObject{},
-		Seq:          101,
+		ID:              "comp-1",
+		InvocationID:    "inv-1",
+		OutputCase:      "Success",
+		Result:          ir.IRObject{},
+		Seq:             101,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteCompletion(ctx, comp))
 
@@ -866,20 +940,22 @@ func TestExecuteThen_UniqueBindingHashes(t *testing.T) {
 
 	// Setup completion
 	inv := ir.Invocation{
-		ID:        "inv-1",
-		FlowToken: "flow-1",
-		ActionURI: "Cart.checkout",
-		Args:      ir.IRObject{},
-		Seq:       1,
+		ID:              "inv-1",
+		FlowToken:       "flow-1",
+		ActionURI:       "Cart.checkout",
+		Args:            ir.IRObject{},
+		Seq:             1,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteInvocation(ctx, inv))
 
 	comp := ir.Completion{
-		ID:           "comp-1",
-		InvocationID: "inv-1",
-		OutputCase:   "Success",
-		Result:       ir.IRObject{},
-		Seq:          2,
+		ID:              "comp-1",
+		InvocationID:    "inv-1",
+		OutputCase:      "Success",
+		Result:          ir.IRObject{},
+		Seq:             2,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteCompletion(ctx, comp))
 
@@ -1,6 +1,8 @@
 package engine
 
 import (
+	"encoding/binary"
+	"math/rand/v2"
 	"sync"
 
 	"github.com/google/uuid"
@@ -70,3 +72,46 @@ func (g *FixedGenerator) Generate() string {
 	g.idx++
 	return token
 }
+
+// SeededGenerator produces a reproducible sequence of distinct flow tokens.
+//
+// Each token is a well-formed UUIDv4 whose bits come from a PCG stream
+// seeded with the given seed, so two generators with the same seed yield
+// identical sequences. Use it for multi-flow golden tests, where
+// FixedGenerator would require listing every token up front.
+//
+// Thread-safety: SeededGenerator is safe for concurrent use via internal mutex.
+type SeededGenerator struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// NewSeededGenerator creates a generator whose token sequence is fully
+// determined by seed.
+//
+// Example:
+//
+//	gen := NewSeededGenerator(42)
+//	gen.Generate() // same first token for every generator seeded with 42
+//	gen.Generate() // a different, equally stable second token
+func NewSeededGenerator(seed int64) *SeededGenerator {
+	return &SeededGenerator{
+		rng: rand.New(rand.NewPCG(uint64(seed), 0)),
+	}
+}
+
+// Generate returns the next token in the seeded sequence.
+//
+// Format: "xxxxxxxx-xxxx-4xxx-yxxx-xxxxxxxxxxxx" (36 characters, version 4,
+// RFC 4122 variant).
+func (g *SeededGenerator) Generate() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var id uuid.UUID
+	binary.BigEndian.PutUint64(id[:8], g.rng.Uint64())
+	binary.BigEndian.PutUint64(id[8:], g.rng.Uint64())
+	id[6] = (id[6] & 0x0f) | 0x40 // Version 4
+	id[8] = (id[8] & 0x3f) | 0x80 // RFC 4122 variant
+	return id.String()
+}
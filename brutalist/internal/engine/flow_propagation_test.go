@@ -19,7 +19,7 @@ func TestGenerateInvocation_FlowTokenPropagation(t *testing.T) {
 
 	flowToken := "flow-test-123"
 	then := ir.ThenClause{
-		ActionRef: "Inventory.ReserveStock",
+		ActionRef: "Inventory.reserveStock",
 		Args: map[string]string{
 			"product_id": "${bound.product}",
 			"quantity":   "5",
@@ -30,12 +30,12 @@ func TestGenerateInvocation_FlowTokenPropagation(t *testing.T) {
 	}
 
 	// Generate invocation
-	inv, err := engine.generateInvocation(flowToken, then, bindings)
+	inv, err := engine.generateInvocation(flowToken, testSecurityContext, then, bindings)
 	require.NoError(t, err)
 
 	// Verify flow token inherited
 	assert.Equal(t, flowToken, inv.FlowToken, "flow token must be inherited from completion")
-	assert.Equal(t, ir.ActionRef("Inventory.ReserveStock"), inv.ActionURI)
+	assert.Equal(t, ir.ActionRef("Inventory.reserveStock"), inv.ActionURI)
 	assert.Equal(t, ir.IRString("widget"), inv.Args["product_id"])
 	assert.Equal(t, ir.IRString("5"), inv.Args["quantity"])
 }
@@ -50,7 +50,7 @@ func TestGenerateInvocation_MissingFlowToken(t *testing.T) {
 	bindings := ir.IRObject{}
 
 	// Attempt to generate invocation with empty flow token
-	_, err := engine.generateInvocation("", then, bindings)
+	_, err := engine.generateInvocation("", testSecurityContext, then, bindings)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "flow token is required")
 }
@@ -73,7 +73,7 @@ func TestGenerateInvocation_ArgResolution(t *testing.T) {
 		"product_name": ir.IRString("widget"),
 	}
 
-	inv, err := engine.generateInvocation(flowToken, then, bindings)
+	inv, err := engine.generateInvocation(flowToken, testSecurityContext, then, bindings)
 	require.NoError(t, err)
 
 	// Verify args resolved correctly
@@ -101,7 +101,7 @@ func TestGenerateInvocation_MissingBinding(t *testing.T) {
 		// "nonexistent" binding not provided
 	}
 
-	_, err := engine.generateInvocation(flowToken, then, bindings)
+	_, err := engine.generateInvocation(flowToken, testSecurityContext, then, bindings)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "binding \"nonexistent\" not found")
 }
@@ -120,7 +120,7 @@ func TestFlowToken_NeverGeneratedMidFlow(t *testing.T) {
 	bindings := ir.IRObject{}
 
 	// Generate invocation - must use provided flow token
-	inv, err := engine.generateInvocation(originalFlow, then, bindings)
+	inv, err := engine.generateInvocation(originalFlow, testSecurityContext, then, bindings)
 	require.NoError(t, err)
 
 	// CRITICAL: Flow token MUST match the provided parameter
@@ -214,6 +214,9 @@ func TestFireSyncRule_FlowTokenPropagation(t *testing.T) {
 	assert.Equal(t, flowToken, generatedInv.FlowToken,
 		"generated invocation must have same flow token as triggering completion")
 	assert.Equal(t, ir.ActionRef("Order.Process"), generatedInv.ActionURI)
+	assert.Equal(t, "tenant-1", generatedInv.SecurityContext.TenantID,
+		"generated invocation must inherit the triggering completion's tenant")
+	assert.Equal(t, "user-1", generatedInv.SecurityContext.UserID)
 }
 
 func TestFireSyncRule_Idempotency(t *testing.T) {
@@ -294,13 +297,13 @@ func TestFlowTokenChain_TwoLevelsDeep(t *testing.T) {
 	ctx := context.Background()
 	flowToken := "flow-chain-xyz"
 
-	// Level 1: User initiates Order.Create
+	// Level 1: User initiates Order.create
 	args1 := ir.IRObject{"product": ir.IRString("widget"), "qty": ir.IRInt(5)}
-	invID1 := ir.MustInvocationID(flowToken, "Order.Create", args1, 1)
+	invID1 := ir.MustInvocationID(flowToken, "Order.create", args1, 1)
 	inv1 := ir.Invocation{
 		ID:        invID1,
 		FlowToken: flowToken,
-		ActionURI: "Order.Create",
+		ActionURI: "Order.create",
 		Args:      args1,
 		Seq:       1,
 		SecurityContext: ir.SecurityContext{
@@ -329,11 +332,11 @@ func TestFlowTokenChain_TwoLevelsDeep(t *testing.T) {
 	}
 	require.NoError(t, s.WriteCompletion(ctx, *comp1))
 
-	// Level 2: Sync generates Inventory.ReserveStock
+	// Level 2: Sync generates Inventory.reserveStock
 	sync1 := ir.SyncRule{
 		ID: "sync-reserve",
 		When: ir.WhenClause{
-			ActionRef:  "Order.Create",
+			ActionRef:  "Order.create",
 			EventType:  "completed",
 			OutputCase: "Success",
 			Bindings: map[string]string{
@@ -341,7 +344,7 @@ func TestFlowTokenChain_TwoLevelsDeep(t *testing.T) {
 			},
 		},
 		Then: ir.ThenClause{
-			ActionRef: "Inventory.ReserveStock",
+			ActionRef: "Inventory.reserveStock",
 			Args: map[string]string{
 				"order_id": "${bound.order_id}",
 			},
@@ -384,7 +387,7 @@ func TestFlowTokenChain_TwoLevelsDeep(t *testing.T) {
 	sync2 := ir.SyncRule{
 		ID: "sync-notify",
 		When: ir.WhenClause{
-			ActionRef:  "Inventory.ReserveStock",
+			ActionRef:  "Inventory.reserveStock",
 			EventType:  "completed",
 			OutputCase: "Success",
 			Bindings: map[string]string{
@@ -534,7 +537,7 @@ func TestGenerateInvocation_ContentAddressedID(t *testing.T) {
 	}
 	bindings := ir.IRObject{}
 
-	inv, err := engine.generateInvocation(flowToken, then, bindings)
+	inv, err := engine.generateInvocation(flowToken, testSecurityContext, then, bindings)
 	require.NoError(t, err)
 
 	// ID should be content-addressed (64 hex chars = SHA256)
@@ -556,7 +559,7 @@ func TestGenerateInvocation_SetsVersionFields(t *testing.T) {
 	}
 	bindings := ir.IRObject{}
 
-	inv, err := engine.generateInvocation(flowToken, then, bindings)
+	inv, err := engine.generateInvocation(flowToken, testSecurityContext, then, bindings)
 	require.NoError(t, err)
 
 	assert.Equal(t, ir.EngineVersion, inv.EngineVersion)
@@ -575,11 +578,11 @@ func TestGenerateInvocation_SequenceNumber(t *testing.T) {
 	bindings := ir.IRObject{}
 
 	// Generate first invocation
-	inv1, err := engine.generateInvocation(flowToken, then, bindings)
+	inv1, err := engine.generateInvocation(flowToken, testSecurityContext, then, bindings)
 	require.NoError(t, err)
 
 	// Generate second invocation
-	inv2, err := engine.generateInvocation(flowToken, then, bindings)
+	inv2, err := engine.generateInvocation(flowToken, testSecurityContext, then, bindings)
 	require.NoError(t, err)
 
 	// Sequence numbers should be increasing
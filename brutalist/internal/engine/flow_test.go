@@ -117,6 +117,48 @@ func TestFixedGenerator_SingleToken(t *testing.T) {
 	})
 }
 
+func TestSeededGenerator_SameSeedSameSequence(t *testing.T) {
+	a := NewSeededGenerator(42)
+	b := NewSeededGenerator(42)
+
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, a.Generate(), b.Generate(), "token %d should match", i)
+	}
+}
+
+func TestSeededGenerator_DifferentSeedsDiverge(t *testing.T) {
+	a := NewSeededGenerator(1)
+	b := NewSeededGenerator(2)
+
+	assert.NotEqual(t, a.Generate(), b.Generate())
+}
+
+func TestSeededGenerator_DistinctValidTokens(t *testing.T) {
+	gen := NewSeededGenerator(7)
+	const iterations = 1000
+
+	tokens := make(map[string]bool, iterations)
+	for i := 0; i < iterations; i++ {
+		token := gen.Generate()
+		require.False(t, tokens[token], "token %s generated twice", token)
+		tokens[token] = true
+
+		parsed, err := uuid.Parse(token)
+		require.NoError(t, err, "token should be valid UUID")
+		assert.Equal(t, uuid.Version(4), parsed.Version())
+		assert.Equal(t, uuid.RFC4122, parsed.Variant())
+	}
+}
+
+func TestEngine_NewFlow_WithSeededGenerator(t *testing.T) {
+	s := setupTestStore(t)
+	engine := New(s, nil, nil, NewSeededGenerator(99))
+
+	expected := NewSeededGenerator(99)
+	assert.Equal(t, expected.Generate(), engine.NewFlow())
+	assert.Equal(t, expected.Generate(), engine.NewFlow())
+}
+
 func TestEngine_NewFlow_WithFixedGenerator(t *testing.T) {
 	s := setupTestStore(t)
 	flowGen := NewFixedGenerator("test-flow-1", "test-flow-2")
@@ -0,0 +1,153 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/roach88/nysm/internal/ir"
+	"github.com/roach88/nysm/internal/queryir"
+)
+
+// matchGuard reports whether sync's when-guard holds for a completion.
+//
+// A guard is a filter-style predicate over the completion's result and the
+// invocation's args, e.g.:
+//
+//	result.status == 'approved' && args.amount >= 100
+//
+// It uses the where-clause filter grammar (queryir.ParseFilter). Fields
+// must be prefixed with "result." or "args."; bound variables are not
+// available, since guards run before bindings are extracted.
+//
+// Guards are parsed once, when the syncs are registered (see parseGuards).
+// An empty guard always holds. A missing field fails the guard. A malformed
+// guard fails closed (the sync does not fire); RegisterSyncs and the
+// compiler reject malformed guards up front.
+func (e *Engine) matchGuard(sync ir.SyncRule, inv *ir.Invocation, comp *ir.Completion) bool {
+	if strings.TrimSpace(sync.When.Guard) == "" {
+		return true
+	}
+
+	pred, ok := e.guards[sync.ID]
+	if !ok {
+		return false // Malformed; logged when the syncs were registered
+	}
+	matched, err := evalGuard(pred, inv, comp)
+	if err != nil {
+		slog.Warn("guard evaluation failed",
+			"sync_id", sync.ID,
+			"guard", sync.When.Guard,
+			"action", inv.ActionURI,
+			"completion_id", comp.ID,
+			"error", err,
+		)
+		return false
+	}
+	return matched
+}
+
+// parseGuards parses the when-guard of each sync, keyed by sync ID. Syncs
+// without a guard, or with a malformed one, have no entry; err joins the
+// parse errors of the malformed guards.
+func parseGuards(syncs []ir.SyncRule) (map[string]queryir.Predicate, error) {
+	guards := make(map[string]queryir.Predicate)
+	var errs []error
+	for _, sync := range syncs {
+		if strings.TrimSpace(sync.When.Guard) == "" {
+			continue
+		}
+		pred, err := queryir.ParseFilter(sync.When.Guard)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("sync %s: invalid guard %q: %w", sync.ID, sync.When.Guard, err))
+			continue
+		}
+		guards[sync.ID] = pred
+	}
+	return guards, errors.Join(errs...)
+}
+
+// evalGuard evaluates a guard predicate in memory.
+func evalGuard(pred queryir.Predicate, inv *ir.Invocation, comp *ir.Completion) (bool, error) {
+	switch p := pred.(type) {
+	case queryir.And:
+		for _, sub := range p.Predicates {
+			ok, err := evalGuard(sub, inv, comp)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+
+	case queryir.Equals:
+		val, found, err := guardField(p.Field, inv, comp)
+		if err != nil || !found {
+			return false, err
+		}
+		return ir.Equal(val, p.Value), nil
+
+	case queryir.In:
+		val, found, err := guardField(p.Field, inv, comp)
+		if err != nil || !found {
+			return false, err
+		}
+		for _, candidate := range p.Values {
+			if ir.Equal(val, candidate) {
+				return true, nil
+			}
+		}
+		return false, nil
+
+	case queryir.GreaterThan:
+		return compareGuardInt(p.Field, p.BoundVar, inv, comp, func(v int64) bool { return v > int64(p.Value) })
+	case queryir.LessThan:
+		return compareGuardInt(p.Field, p.BoundVar, inv, comp, func(v int64) bool { return v < int64(p.Value) })
+	case queryir.GreaterOrEqual:
+		return compareGuardInt(p.Field, p.BoundVar, inv, comp, func(v int64) bool { return v >= int64(p.Value) })
+	case queryir.LessOrEqual:
+		return compareGuardInt(p.Field, p.BoundVar, inv, comp, func(v int64) bool { return v <= int64(p.Value) })
+
+	case queryir.BoundEquals:
+		return false, fmt.Errorf("bound variable %q is not available in guards", p.BoundVar)
+
+	default:
+		return false, fmt.Errorf("unsupported guard predicate %T", pred)
+	}
+}
+
+// compareGuardInt applies an integer comparison to a guard field.
+// Non-integer values fail the comparison (no implicit conversion, CP-5).
+func compareGuardInt(field, boundVar string, inv *ir.Invocation, comp *ir.Completion, cmp func(int64) bool) (bool, error) {
+	if boundVar != "" {
+		return false, fmt.Errorf("bound variable %q is not available in guards", boundVar)
+	}
+	val, found, err := guardField(field, inv, comp)
+	if err != nil || !found {
+		return false, err
+	}
+	n, ok := val.(ir.IRInt)
+	if !ok {
+		return false, nil
+	}
+	return cmp(int64(n)), nil
+}
+
+// guardField resolves a "result.<name>" or "args.<name>" guard field.
+func guardField(field string, inv *ir.Invocation, comp *ir.Completion) (ir.IRValue, bool, error) {
+	source, name, ok := strings.Cut(field, ".")
+	if !ok || name == "" {
+		return nil, false, fmt.Errorf("guard field %q must start with \"result.\" or \"args.\"", field)
+	}
+
+	var val ir.IRValue
+	switch source {
+	case "result":
+		val, ok = comp.Result[name]
+	case "args":
+		val, ok = inv.Args[name]
+	default:
+		return nil, false, fmt.Errorf("guard field %q must start with \"result.\" or \"args.\"", field)
+	}
+	return val, ok, nil
+}
@@ -0,0 +1,166 @@
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/roach88/nysm/internal/ir"
+	"github.com/roach88/nysm/internal/queryir"
+)
+
+func TestMatchGuard(t *testing.T) {
+	inv := makeTestInvocation("Order.review")
+	inv.Args = ir.IRObject{"amount": ir.IRInt(150), "region": ir.IRString("eu")}
+	comp := makeTestCompletion("Success", ir.IRObject{
+		"status": ir.IRString("approved"),
+		"rush":   ir.IRBool(true),
+		"note":   ir.IRString("a && b"),
+	})
+
+	tests := []struct {
+		name  string
+		guard string
+		want  bool
+	}{
+		{"empty", "", true},
+		{"result_equals", "result.status == 'approved'", true},
+		{"result_not_equal", "result.status == 'rejected'", false},
+		{"args_range", "args.amount >= 100", true},
+		{"args_range_fails", "args.amount > 150", false},
+		{"bool", "result.rush == true", true},
+		{"conjunction", "result.status == 'approved' && args.amount < 200 AND args.region == 'eu'", true},
+		{"conjunction_fails", "result.status == 'approved' && args.amount < 100", false},
+		{"operator_inside_string", "result.note == 'a && b'", true},
+		{"missing_field", "result.reason == 'fraud'", false},
+		{"range_on_string", "args.region > 1", false},
+		{"unprefixed_field_fails_closed", "status == 'approved'", false},
+		{"bound_variable_fails_closed", "result.status == bound.status", false},
+		{"unsupported_operator_fails_closed", "result.status != 'rejected'", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sync := ir.SyncRule{ID: "guarded", When: ir.WhenClause{Guard: tt.guard}}
+			e := New(nil, nil, []ir.SyncRule{sync}, nil)
+			assert.Equal(t, tt.want, e.matchGuard(sync, inv, comp))
+		})
+	}
+}
+
+func TestMatchSync_GuardChecked(t *testing.T) {
+	sync := ir.SyncRule{
+		ID: "guarded",
+		When: ir.WhenClause{
+			ActionRef:  "Order.review",
+			EventType:  "completed",
+			OutputCase: "Success",
+			Guard:      "result.status == 'approved'",
+		},
+	}
+	e := New(nil, nil, []ir.SyncRule{sync}, nil)
+	inv := makeTestInvocation("Order.review")
+
+	assert.True(t, e.matchSync(sync, inv, makeTestCompletion("Success", ir.IRObject{"status": ir.IRString("approved")})))
+	assert.False(t, e.matchSync(sync, inv, makeTestCompletion("Success", ir.IRObject{"status": ir.IRString("pending")})))
+}
+
+func TestRegisterSyncs_ParsesGuards(t *testing.T) {
+	e := New(nil, nil, nil, nil)
+
+	require.NoError(t, e.RegisterSyncs([]ir.SyncRule{guardedShipSync}))
+	assert.Equal(t, queryir.And{Predicates: []queryir.Predicate{
+		queryir.Equals{Field: "result.status", Value: ir.IRString("approved")},
+		queryir.GreaterOrEqual{Field: "args.amount", Value: ir.IRInt(100)},
+	}}, e.guards["ship-approved"])
+
+	malformed := guardedShipSync
+	malformed.When.Guard = "result.status = 'approved'"
+	err := e.RegisterSyncs([]ir.SyncRule{malformed})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid guard")
+	assert.Equal(t, []ir.SyncRule{guardedShipSync}, e.Syncs(), "rejected syncs must not replace registered ones")
+}
+
+// guardedShipSync ships approved orders only.
+var guardedShipSync = ir.SyncRule{
+	ID: "ship-approved",
+	When: ir.WhenClause{
+		ActionRef:  "Order.review",
+		EventType:  "completed",
+		OutputCase: "Success",
+		Guard:      "result.status == 'approved' && args.amount >= 100",
+		Bindings:   map[string]string{"order_id": "order_id"},
+	},
+	Then: ir.ThenClause{
+		ActionRef: "Shipping.schedule",
+		Args:      map[string]string{"order_id": "${bound.order_id}"},
+	},
+}
+
+// reviewOrder writes an Order.review invocation and processes its completion.
+func reviewOrder(t *testing.T, e *Engine, amount int64, status string) string {
+	t.Helper()
+	ctx := context.Background()
+
+	args := ir.IRObject{"amount": ir.IRInt(amount)}
+	inv := ir.Invocation{
+		ID:              ir.MustInvocationID("flow-1", "Order.review", args, 1),
+		FlowToken:       "flow-1",
+		ActionURI:       "Order.review",
+		Args:            args,
+		Seq:             1,
+		SecurityContext: testSecurityContext,
+	}
+	require.NoError(t, e.store.WriteInvocation(ctx, inv))
+
+	result := ir.IRObject{"order_id": ir.IRString("order-1"), "status": ir.IRString(status)}
+	comp := &ir.Completion{
+		ID:              ir.MustCompletionID(inv.ID, "Success", result, 2),
+		InvocationID:    inv.ID,
+		OutputCase:      "Success",
+		Result:          result,
+		Seq:             2,
+		SecurityContext: testSecurityContext,
+	}
+	require.NoError(t, e.ProcessCompletion(ctx, comp))
+	return comp.ID
+}
+
+func TestEvaluateSyncs_GuardPasses(t *testing.T) {
+	s := setupTestStore(t)
+	e := New(s, nil, []ir.SyncRule{guardedShipSync}, newStubFlowGen("flow-1"))
+
+	compID := reviewOrder(t, e, 250, "approved")
+
+	firings, err := s.ReadSyncFiringsForCompletion(context.Background(), compID)
+	require.NoError(t, err)
+	require.Len(t, firings, 1)
+	assert.Equal(t, "ship-approved", firings[0].SyncID)
+}
+
+func TestEvaluateSyncs_GuardBlocksFiring(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount int64
+		status string
+	}{
+		{"result_guard_fails", 250, "rejected"},
+		{"args_guard_fails", 50, "approved"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := setupTestStore(t)
+			e := New(s, nil, []ir.SyncRule{guardedShipSync}, newStubFlowGen("flow-1"))
+
+			compID := reviewOrder(t, e, tt.amount, tt.status)
+
+			firings, err := s.ReadSyncFiringsForCompletion(context.Background(), compID)
+			require.NoError(t, err)
+			assert.Empty(t, firings)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package engine
+
+import "github.com/roach88/nysm/internal/ir"
+
+// EventListener observes engine lifecycle events.
+//
+// Listeners let callers build metrics, tracing, or audit logs without
+// parsing slog output. Callbacks fire at the same points the engine logs
+// the corresponding event, after the event has been durably written.
+//
+// CRITICAL: Callbacks are invoked synchronously from the Run goroutine
+// (single-writer). They MUST NOT block and MUST NOT call back into the
+// engine; a slow listener stalls all event processing. Hand work off to
+// another goroutine if it can take time.
+//
+// Embed NopListener to implement only the callbacks you need.
+type EventListener interface {
+	// OnInvocation is called after an invocation is written to the store.
+	OnInvocation(inv ir.Invocation)
+
+	// OnCompletion is called after a completion is written to the store,
+	// before sync rules are evaluated against it.
+	OnCompletion(comp ir.Completion)
+
+	// OnSyncFired is called after a sync firing and the invocation it
+	// generated are atomically written. Idempotent re-firings (CP-1) are
+	// not reported.
+	OnSyncFired(firing ir.SyncFiring, inv ir.Invocation)
+
+	// OnCycleDetected is called when a firing is rejected because the same
+	// (sync, binding) already fired in the flow (Story 5.3).
+	OnCycleDetected(err *RuntimeError)
+
+	// OnQuotaExceeded is called when a flow exceeds its max steps quota
+	// (Story 5.4).
+	OnQuotaExceeded(err *StepsExceededError)
+
+	// OnFlowTimeout is called when a flow is marked timed-out because it
+	// made no progress within the step timeout (see WithFlowStepTimeout).
+	OnFlowTimeout(err *RuntimeError)
+
+	// OnInvocationRejected is called when the invocation validator vetoes a
+	// generated invocation (see WithInvocationValidator). Nothing was
+	// written for that binding.
+	OnInvocationRejected(err *RuntimeError)
+
+	// OnFlowCleanup is called when CleanupFlow releases a flow's state,
+	// with the flow's final step count (see Metrics.FlowSteps). It is not
+	// called for flows the engine never counted a step for, and it runs on
+	// the goroutine that called CleanupFlow.
+	OnFlowCleanup(flowToken string, steps int64)
+}
+
+// NopListener is an EventListener that ignores every event.
+// It is the engine's default listener.
+type NopListener struct{}
+
+func (NopListener) OnInvocation(ir.Invocation)               {}
+func (NopListener) OnCompletion(ir.Completion)               {}
+func (NopListener) OnSyncFired(ir.SyncFiring, ir.Invocation) {}
+func (NopListener) OnCycleDetected(*RuntimeError)            {}
+func (NopListener) OnQuotaExceeded(*StepsExceededError)      {}
+func (NopListener) OnFlowTimeout(*RuntimeError)              {}
+func (NopListener) OnInvocationRejected(*RuntimeError)       {}
+func (NopListener) OnFlowCleanup(string, int64)              {}
+
+// WithListener registers an EventListener for engine lifecycle events.
+//
+// Passing nil restores the default NopListener.
+func WithListener(l EventListener) EngineOption {
+	return func(e *Engine) {
+		if l == nil {
+			l = NopListener{}
+		}
+		e.listener = l
+	}
+}
@@ -0,0 +1,188 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+// recordingListener records each callback as a short description.
+type recordingListener struct {
+	events []string
+	firing ir.SyncFiring
+}
+
+func (l *recordingListener) OnInvocation(inv ir.Invocation) {
+	l.events = append(l.events, "invocation "+string(inv.ActionURI))
+}
+
+func (l *recordingListener) OnCompletion(comp ir.Completion) {
+	l.events = append(l.events, "completion "+comp.OutputCase)
+}
+
+func (l *recordingListener) OnSyncFired(firing ir.SyncFiring, inv ir.Invocation) {
+	l.firing = firing
+	l.events = append(l.events, "sync_fired "+firing.SyncID+" -> "+string(inv.ActionURI))
+}
+
+func (l *recordingListener) OnCycleDetected(err *RuntimeError) {
+	l.events = append(l.events, "cycle "+err.SyncID)
+}
+
+func (l *recordingListener) OnQuotaExceeded(err *StepsExceededError) {
+	l.events = append(l.events, "quota_exceeded "+err.FlowToken)
+}
+
+func (l *recordingListener) OnFlowTimeout(err *RuntimeError) {
+	l.events = append(l.events, "flow_timeout "+err.FlowToken)
+}
+
+func (l *recordingListener) OnInvocationRejected(err *RuntimeError) {
+	l.events = append(l.events, "invocation_rejected "+err.SyncID)
+}
+
+func (l *recordingListener) OnFlowCleanup(flowToken string, steps int64) {
+	l.events = append(l.events, fmt.Sprintf("flow_cleanup %s steps=%d", flowToken, steps))
+}
+
+// listenerTestSync fires Inventory.reserve whenever Cart.addItem succeeds.
+var listenerTestSync = ir.SyncRule{
+	ID: "sync-cart-to-inventory",
+	When: ir.WhenClause{
+		ActionRef:  "Cart.addItem",
+		EventType:  "completed",
+		OutputCase: "Success",
+		Bindings:   map[string]string{"cart_id": "cart_id"},
+	},
+	Then: ir.ThenClause{
+		ActionRef: "Inventory.reserve",
+		Args:      map[string]string{"cart_id": "${bound.cart_id}"},
+	},
+}
+
+// runCartAddItem writes a Cart.addItem invocation through the engine and
+// completes it with Success.
+func runCartAddItem(t *testing.T, e *Engine) error {
+	t.Helper()
+	ctx := context.Background()
+
+	args := ir.IRObject{"item": ir.IRString("widget")}
+	inv := &ir.Invocation{
+		ID:              ir.MustInvocationID("flow-1", "Cart.addItem", args, 1),
+		FlowToken:       "flow-1",
+		ActionURI:       "Cart.addItem",
+		Args:            args,
+		Seq:             1,
+		SecurityContext: testSecurityContext,
+		EngineVersion:   ir.EngineVersion,
+		IRVersion:       ir.IRVersion,
+	}
+	require.NoError(t, e.processInvocation(ctx, inv))
+
+	result := ir.IRObject{"cart_id": ir.IRString("cart-123")}
+	return e.ProcessCompletion(ctx, &ir.Completion{
+		ID:              ir.MustCompletionID(inv.ID, "Success", result, 2),
+		InvocationID:    inv.ID,
+		OutputCase:      "Success",
+		Result:          result,
+		Seq:             2,
+		SecurityContext: testSecurityContext,
+	})
+}
+
+func TestListener_SyncFiringSequence(t *testing.T) {
+	s := setupTestStore(t)
+	listener := &recordingListener{}
+	e := New(s, nil, []ir.SyncRule{listenerTestSync}, newStubFlowGen("flow-1"), WithListener(listener))
+
+	require.NoError(t, runCartAddItem(t, e))
+
+	assert.Equal(t, []string{
+		"invocation Cart.addItem",
+		"completion Success",
+		"sync_fired sync-cart-to-inventory -> Inventory.reserve",
+	}, listener.events)
+
+	// The reported firing carries its store-assigned ID
+	firings, err := s.ReadSyncFiringsForCompletion(context.Background(), listener.firing.CompletionID)
+	require.NoError(t, err)
+	require.Len(t, firings, 1)
+	assert.Equal(t, firings[0], listener.firing)
+}
+
+func TestListener_QuotaExceeded(t *testing.T) {
+	s := setupTestStore(t)
+	listener := &recordingListener{}
+	e := New(s, nil, []ir.SyncRule{listenerTestSync}, newStubFlowGen("flow-1"),
+		WithListener(listener), WithMaxSteps(0))
+
+	err := runCartAddItem(t, e)
+	require.Error(t, err)
+	assert.True(t, IsStepsExceededError(err))
+
+	assert.Equal(t, []string{
+		"invocation Cart.addItem",
+		"completion Success",
+		"quota_exceeded flow-1",
+	}, listener.events)
+}
+
+func TestListener_CycleDetected(t *testing.T) {
+	e, s := setupCycleTestEngine(t)
+	listener := &recordingListener{}
+	WithListener(listener)(e)
+	ctx := context.Background()
+
+	require.NoError(t, e.RegisterSyncs([]ir.SyncRule{{
+		ID: "sync-create-order",
+		When: ir.WhenClause{
+			ActionRef: "Order.create",
+			EventType: "completed",
+			Bindings:  map[string]string{"order_id": "order_id"},
+		},
+		Then: ir.ThenClause{
+			ActionRef: "Order.create",
+			Args:      map[string]string{"order_id": "${bound.order_id}"},
+		},
+	}}))
+
+	for i, id := range []string{"1", "2"} {
+		inv := ir.Invocation{
+			ID:              "inv-" + id,
+			FlowToken:       "flow-1",
+			ActionURI:       "Order.create",
+			Args:            ir.IRObject{"order_id": ir.IRString("order-123")},
+			Seq:             int64(100 + i*10),
+			SecurityContext: testSecurityContext,
+		}
+		require.NoError(t, s.WriteInvocation(ctx, inv))
+
+		_ = e.ProcessCompletion(ctx, &ir.Completion{
+			ID:              "comp-" + id,
+			InvocationID:    inv.ID,
+			OutputCase:      "Success",
+			Result:          ir.IRObject{"order_id": ir.IRString("order-123")},
+			Seq:             int64(101 + i*10),
+			SecurityContext: testSecurityContext,
+		})
+	}
+
+	assert.Equal(t, []string{
+		"completion Success",
+		"sync_fired sync-create-order -> Order.create",
+		"completion Success",
+		"cycle sync-create-order",
+	}, listener.events)
+}
+
+func TestWithListener_NilRestoresNop(t *testing.T) {
+	s := setupTestStore(t)
+	e := New(s, nil, nil, newStubFlowGen("flow-1"), WithListener(nil))
+
+	assert.Equal(t, NopListener{}, e.listener)
+}
@@ -13,6 +13,8 @@ import (
 // 2. Event type: when.EventType must be "completed" (for completions)
 // 3. Output case: when.OutputCase matches if empty (any) or exact match
 //
+// The when-guard is checked separately by Engine.matchSync (see guard.go).
+//
 // Returns true only if ALL conditions are satisfied.
 //
 // NOTE: This function requires both the invocation (for action URI) and
@@ -39,6 +41,13 @@ func matchWhen(when ir.WhenClause, inv *ir.Invocation, comp *ir.Completion) bool
 	return true
 }
 
+// matchSync reports whether a completion triggers sync: its when-clause
+// matches (matchWhen) and its when-guard holds (matchGuard).
+func (e *Engine) matchSync(sync ir.SyncRule, inv *ir.Invocation, comp *ir.Completion) bool {
+	// Check guard last - it is the only condition that inspects values
+	return matchWhen(sync.When, inv, comp) && e.matchGuard(sync, inv, comp)
+}
+
 // extractBindings extracts bound variables from completion result.
 //
 // The when.Bindings map defines how to extract values:
@@ -342,6 +342,7 @@ func TestExtractInvocationBindings_Success(t *testing.T) {
 			"product_id": ir.IRString("prod-123"),
 			"quantity":   ir.IRInt(3),
 		},
+		SecurityContext: testSecurityContext,
 	}
 
 	bindings, err := extractInvocationBindings(when, inv)
@@ -372,6 +373,7 @@ func TestExtractInvocationBindings_MissingField(t *testing.T) {
 		Args: ir.IRObject{
 			"product_id": ir.IRString("prod-123"),
 		},
+		SecurityContext: testSecurityContext,
 	}
 
 	bindings, err := extractInvocationBindings(when, inv)
@@ -395,6 +397,7 @@ func TestExtractInvocationBindings_EmptyBindings(t *testing.T) {
 		Args: ir.IRObject{
 			"product_id": ir.IRString("prod-123"),
 		},
+		SecurityContext: testSecurityContext,
 	}
 
 	bindings, err := extractInvocationBindings(when, inv)
@@ -0,0 +1,97 @@
+package engine
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Metrics holds aggregate counters for engine activity.
+//
+// Counters are written only from the single-writer Run goroutine, so they
+// need no mutex; they are stored in atomics so other goroutines (e.g. a
+// Prometheus exporter) can read them at any time without racing.
+//
+// Obtain the engine's instance via Engine.Metrics().
+type Metrics struct {
+	eventsProcessed atomic.Int64
+	syncsFired      atomic.Int64
+	cyclesDetected  atomic.Int64
+	quotaRejections atomic.Int64
+
+	duplicateCompletions atomic.Int64
+	invocationsRejected  atomic.Int64
+
+	// flowSteps maps flow token -> *atomic.Int64 step count for flows not
+	// yet cleaned up. sync.Map keeps lookups for known flows lock-free on the
+	// hot path.
+	flowSteps sync.Map
+}
+
+// EventsProcessed returns the number of invocations and completions the
+// engine has processed, including those that failed.
+func (m *Metrics) EventsProcessed() int64 {
+	return m.eventsProcessed.Load()
+}
+
+// SyncsFired returns the number of new sync firings. Idempotent re-firings
+// (CP-1) are not counted.
+func (m *Metrics) SyncsFired() int64 {
+	return m.syncsFired.Load()
+}
+
+// CyclesDetected returns the number of firings rejected by cycle detection.
+func (m *Metrics) CyclesDetected() int64 {
+	return m.cyclesDetected.Load()
+}
+
+// QuotaRejections returns the number of completions rejected because their
+// flow exceeded the max steps quota.
+func (m *Metrics) QuotaRejections() int64 {
+	return m.quotaRejections.Load()
+}
+
+// DuplicateCompletions returns the number of enqueued completions dropped
+// because the store already held them (e.g. an executor retry).
+func (m *Metrics) DuplicateCompletions() int64 {
+	return m.duplicateCompletions.Load()
+}
+
+// InvocationsRejected returns the number of generated invocations vetoed by
+// the invocation validator (see WithInvocationValidator).
+func (m *Metrics) InvocationsRejected() int64 {
+	return m.invocationsRejected.Load()
+}
+
+// FlowSteps returns a snapshot of the step count for every active flow.
+// Step counts are suitable for feeding a histogram.
+//
+// CleanupFlow removes a flow's entry, so the map does not grow with every
+// flow the engine has ever seen; the final count is reported to
+// EventListener.OnFlowCleanup.
+func (m *Metrics) FlowSteps() map[string]int64 {
+	steps := make(map[string]int64)
+	m.flowSteps.Range(func(key, value any) bool {
+		steps[key.(string)] = value.(*atomic.Int64).Load()
+		return true
+	})
+	return steps
+}
+
+// setFlowSteps records the current step count for a flow.
+func (m *Metrics) setFlowSteps(flowToken string, steps int) {
+	counter, ok := m.flowSteps.Load(flowToken)
+	if !ok {
+		counter, _ = m.flowSteps.LoadOrStore(flowToken, new(atomic.Int64))
+	}
+	counter.(*atomic.Int64).Store(int64(steps))
+}
+
+// forgetFlow removes a flow's step count and returns its final value.
+// ok is false if the flow has no entry.
+func (m *Metrics) forgetFlow(flowToken string) (steps int64, ok bool) {
+	counter, ok := m.flowSteps.LoadAndDelete(flowToken)
+	if !ok {
+		return 0, false
+	}
+	return counter.(*atomic.Int64).Load(), true
+}
@@ -0,0 +1,108 @@
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+func TestMetrics_ZeroOnNewEngine(t *testing.T) {
+	e := New(setupTestStore(t), nil, nil, newStubFlowGen("flow-1"))
+
+	m := e.Metrics()
+	assert.Equal(t, int64(0), m.EventsProcessed())
+	assert.Equal(t, int64(0), m.SyncsFired())
+	assert.Equal(t, int64(0), m.CyclesDetected())
+	assert.Equal(t, int64(0), m.QuotaRejections())
+	assert.Equal(t, int64(0), m.DuplicateCompletions())
+	assert.Empty(t, m.FlowSteps())
+}
+
+func TestMetrics_SyncFiringScenario(t *testing.T) {
+	listener := &recordingListener{}
+	e := New(setupTestStore(t), nil, []ir.SyncRule{listenerTestSync}, newStubFlowGen("flow-1"),
+		WithListener(listener))
+
+	require.NoError(t, runCartAddItem(t, e))
+
+	m := e.Metrics()
+	assert.Equal(t, int64(2), m.EventsProcessed(), "one invocation + one completion")
+	assert.Equal(t, int64(1), m.SyncsFired())
+	assert.Equal(t, int64(0), m.CyclesDetected())
+	assert.Equal(t, int64(0), m.QuotaRejections())
+	assert.Equal(t, int64(0), m.DuplicateCompletions())
+	assert.Equal(t, map[string]int64{"flow-1": 1}, m.FlowSteps())
+
+	// Cleanup drops the entry and reports the final count
+	e.CleanupFlow("flow-1")
+	assert.Empty(t, m.FlowSteps())
+	assert.Equal(t, "flow_cleanup flow-1 steps=1", listener.events[len(listener.events)-1])
+
+	// Cleaning up an unknown or already cleaned-up flow reports nothing
+	events := len(listener.events)
+	e.CleanupFlow("flow-1")
+	e.CleanupFlow("flow-9")
+	assert.Len(t, listener.events, events)
+}
+
+func TestMetrics_QuotaRejection(t *testing.T) {
+	e := New(setupTestStore(t), nil, []ir.SyncRule{listenerTestSync}, newStubFlowGen("flow-1"),
+		WithMaxSteps(0))
+
+	require.Error(t, runCartAddItem(t, e))
+
+	m := e.Metrics()
+	assert.Equal(t, int64(2), m.EventsProcessed())
+	assert.Equal(t, int64(0), m.SyncsFired(), "syncs are not evaluated past the quota")
+	assert.Equal(t, int64(1), m.QuotaRejections())
+	assert.Equal(t, map[string]int64{"flow-1": 1}, m.FlowSteps())
+}
+
+func TestMetrics_CycleDetected(t *testing.T) {
+	e, s := setupCycleTestEngine(t)
+	ctx := context.Background()
+
+	require.NoError(t, e.RegisterSyncs([]ir.SyncRule{{
+		ID: "sync-create-order",
+		When: ir.WhenClause{
+			ActionRef: "Order.create",
+			EventType: "completed",
+			Bindings:  map[string]string{"order_id": "order_id"},
+		},
+		Then: ir.ThenClause{
+			ActionRef: "Order.create",
+			Args:      map[string]string{"order_id": "${bound.order_id}"},
+		},
+	}}))
+
+	for i, id := range []string{"1", "2"} {
+		inv := ir.Invocation{
+			ID:              "inv-" + id,
+			FlowToken:       "flow-1",
+			ActionURI:       "Order.create",
+			Args:            ir.IRObject{"order_id": ir.IRString("order-123")},
+			Seq:             int64(100 + i*10),
+			SecurityContext: testSecurityContext,
+		}
+		require.NoError(t, s.WriteInvocation(ctx, inv))
+
+		_ = e.ProcessCompletion(ctx, &ir.Completion{
+			ID:              "comp-" + id,
+			InvocationID:    inv.ID,
+			OutputCase:      "Success",
+			Result:          ir.IRObject{"order_id": ir.IRString("order-123")},
+			Seq:             int64(101 + i*10),
+			SecurityContext: testSecurityContext,
+		})
+	}
+
+	m := e.Metrics()
+	assert.Equal(t, int64(2), m.EventsProcessed())
+	assert.Equal(t, int64(1), m.SyncsFired())
+	assert.Equal(t, int64(1), m.CyclesDetected())
+	assert.Equal(t, map[string]int64{"flow-1": 2}, m.FlowSteps())
+}
@@ -0,0 +1,149 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+// orderListener records invocation IDs in processing order. Safe for use
+// while Run executes on another goroutine.
+type orderListener struct {
+	NopListener
+	mu  sync.Mutex
+	ids []string
+}
+
+func (l *orderListener) OnInvocation(inv ir.Invocation) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.ids = append(l.ids, inv.ID)
+}
+
+func (l *orderListener) seen() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.ids...)
+}
+
+// pauseTestInvocation builds a valid invocation with a distinct ID.
+func pauseTestInvocation(n int) *ir.Invocation {
+	inv := ir.NewInvocation("flow-1", "Cart.addItem").
+		WithArgs(ir.IRObject{"item": ir.IRString(fmt.Sprintf("item-%d", n))}).
+		WithSeq(int64(n)).
+		WithSecurityContext(ir.SecurityContext{TenantID: "tenant-1", UserID: "user-1"}).
+		WithSpecHash("spec-hash-1").
+		MustBuild()
+	return &inv
+}
+
+func TestEngine_PauseResume_PreservesOrder(t *testing.T) {
+	s := setupTestStore(t)
+	listener := &orderListener{}
+	engine := New(s, nil, nil, newStubFlowGen("flow-1"), WithListener(listener))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- engine.Run(ctx)
+	}()
+
+	engine.Pause()
+	assert.True(t, engine.Paused())
+
+	var want []string
+	for i := 1; i <= 5; i++ {
+		inv := pauseTestInvocation(i)
+		want = append(want, inv.ID)
+		require.NoError(t, engine.Enqueue(Event{Type: EventTypeInvocation, Invocation: inv}))
+	}
+
+	// Nothing is processed while paused
+	time.Sleep(50 * time.Millisecond)
+	assert.Empty(t, listener.seen())
+	assert.Equal(t, 5, engine.QueueLen())
+
+	engine.Resume()
+	assert.False(t, engine.Paused())
+
+	assert.Eventually(t, func() bool {
+		return len(listener.seen()) == len(want)
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, want, listener.seen())
+
+	engine.Stop()
+	require.NoError(t, <-errCh)
+}
+
+func TestEngine_PauseResume_Idempotent(t *testing.T) {
+	s := setupTestStore(t)
+	engine := New(s, nil, nil, newStubFlowGen("flow-1"))
+
+	engine.Resume() // Running engine: no-op
+	assert.False(t, engine.Paused())
+
+	engine.Pause()
+	engine.Pause()
+	assert.True(t, engine.Paused())
+
+	engine.Resume()
+	engine.Resume()
+	assert.False(t, engine.Paused())
+}
+
+func TestEngine_Pause_ContextCancelled(t *testing.T) {
+	s := setupTestStore(t)
+	listener := &orderListener{}
+	engine := New(s, nil, nil, newStubFlowGen("flow-1"), WithListener(listener))
+	engine.Pause()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- engine.Run(ctx)
+	}()
+
+	require.NoError(t, engine.Enqueue(Event{Type: EventTypeInvocation, Invocation: pauseTestInvocation(1)}))
+	cancel()
+
+	select {
+	case err := <-errCh:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after context cancellation while paused")
+	}
+	assert.Empty(t, listener.seen())
+}
+
+func TestEngine_Pause_Stop(t *testing.T) {
+	s := setupTestStore(t)
+	listener := &orderListener{}
+	engine := New(s, nil, nil, newStubFlowGen("flow-1"), WithListener(listener))
+	engine.Pause()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- engine.Run(context.Background())
+	}()
+
+	require.NoError(t, engine.Enqueue(Event{Type: EventTypeInvocation, Invocation: pauseTestInvocation(1)}))
+	time.Sleep(20 * time.Millisecond)
+	engine.Stop()
+
+	select {
+	case err := <-errCh:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after Stop while paused")
+	}
+	assert.Empty(t, listener.seen())
+}
@@ -1,6 +1,7 @@
 package engine
 
 import (
+	"errors"
 	"sync"
 
 	"github.com/roach88/nysm/internal/ir"
@@ -23,10 +24,20 @@ type Event struct {
 	Completion *ir.Completion
 }
 
+// ErrQueueFull is returned by Engine.Enqueue when the bounded event queue
+// (see WithQueueCapacity) is at capacity. The event was not accepted; the
+// producer should back off and retry once the Run loop has drained events.
+var ErrQueueFull = errors.New("engine: event queue is full")
+
+// ErrStopped is returned by Engine.Enqueue once the engine has been stopped.
+// Unlike ErrQueueFull, retrying will never succeed.
+var ErrStopped = errors.New("engine: stopped")
+
 // eventQueue is a thread-safe FIFO queue for events.
 //
-// The queue is unbounded to allow cascading sync rule firings to enqueue
-// arbitrarily many generated invocations without blocking.
+// Generated invocations (Enqueue) are never rejected, so cascading sync rule
+// firings can enqueue arbitrarily many follow-on events without blocking.
+// External submissions (TryEnqueue) are subject to the optional capacity.
 //
 // Thread-safety is provided for external enqueuing (e.g., HTTP handlers)
 // while the Engine's Run loop dequeues. In practice, most usage is single-threaded.
@@ -38,6 +49,9 @@ type eventQueue struct {
 	events []Event
 	closed bool
 	signal chan struct{} // Signals event availability (buffered, size 1)
+
+	// capacity bounds TryEnqueue (0 = unbounded, see WithQueueCapacity)
+	capacity int
 }
 
 // newEventQueue creates an empty event queue.
@@ -48,7 +62,8 @@ func newEventQueue() *eventQueue {
 	}
 }
 
-// Enqueue adds an event to the back of the queue.
+// Enqueue adds an event to the back of the queue, ignoring capacity.
+// Used for events the engine generates itself.
 // Thread-safe: may be called from any goroutine.
 // Returns false if the queue is closed.
 func (q *eventQueue) Enqueue(e Event) bool {
@@ -59,6 +74,30 @@ func (q *eventQueue) Enqueue(e Event) bool {
 		return false
 	}
 
+	q.pushLocked(e)
+	return true
+}
+
+// TryEnqueue adds an event to the back of the queue unless the queue is
+// closed (ErrStopped) or holds capacity or more events (ErrQueueFull).
+// Thread-safe: may be called from any goroutine.
+func (q *eventQueue) TryEnqueue(e Event) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return ErrStopped
+	}
+	if q.capacity > 0 && len(q.events) >= q.capacity {
+		return ErrQueueFull
+	}
+
+	q.pushLocked(e)
+	return nil
+}
+
+// pushLocked appends e and signals availability. Caller must hold q.mu.
+func (q *eventQueue) pushLocked(e Event) {
 	q.events = append(q.events, e)
 
 	// Signal availability (non-blocking - buffer of 1 coalesces multiple signals)
@@ -66,8 +105,6 @@ func (q *eventQueue) Enqueue(e Event) bool {
 	case q.signal <- struct{}{}:
 	default:
 	}
-
-	return true
 }
 
 // Dequeue removes and returns the front event.
@@ -145,6 +182,13 @@ func (q *eventQueue) Len() int {
 	return len(q.events)
 }
 
+// Closed reports whether Close has been called.
+func (q *eventQueue) Closed() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.closed
+}
+
 // Close signals that no more events will be enqueued.
 // Wakes any blocked waiters by closing the signal channel.
 func (q *eventQueue) Close() {
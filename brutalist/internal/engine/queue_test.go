@@ -193,3 +193,18 @@ func TestEventQueue_ThreadSafe(t *testing.T) {
 
 	assert.Len(t, received, producers*eventsPerProducer)
 }
+
+func TestEventQueue_TryEnqueue_Capacity(t *testing.T) {
+	q := newEventQueue()
+	q.capacity = 1
+
+	assert.NoError(t, q.TryEnqueue(Event{Type: EventTypeInvocation, Invocation: &ir.Invocation{ID: "1"}}))
+	assert.ErrorIs(t, q.TryEnqueue(Event{Type: EventTypeInvocation, Invocation: &ir.Invocation{ID: "2"}}), ErrQueueFull)
+
+	_, ok := q.TryDequeue()
+	require.True(t, ok)
+	assert.NoError(t, q.TryEnqueue(Event{Type: EventTypeInvocation, Invocation: &ir.Invocation{ID: "3"}}))
+
+	q.Close()
+	assert.ErrorIs(t, q.TryEnqueue(Event{Type: EventTypeInvocation, Invocation: &ir.Invocation{ID: "4"}}), ErrStopped)
+}
@@ -206,11 +206,12 @@ func TestQuota_EnforcedInProcessCompletion(t *testing.T) {
 
 	// Create invocation
 	inv := ir.Invocation{
-		ID:        "inv-1",
-		FlowToken: "flow-1",
-		ActionURI: "Test.action",
-		Args:      ir.IRObject{},
-		Seq:       100,
+		ID:              "inv-1",
+		FlowToken:       "flow-1",
+		ActionURI:       "Test.action",
+		Args:            ir.IRObject{},
+		Seq:             100,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteInvocation(ctx, inv))
 
@@ -218,29 +219,31 @@ func TestQuota_EnforcedInProcessCompletion(t *testing.T) {
 	for i := 0; i < 3; i++ {
 		compID := fmt.Sprintf("comp-%d", i)
 		comp := ir.Completion{
-			ID:           compID,
-			InvocationID: "inv-1",
-			OutputCase:   "Success",
-			Result:       ir.IRObject{},
-			Seq:          int64(101 + i),
+			ID:              compID,
+			InvocationID:    "inv-1",
+			OutputCase:      "Success",
+			Result:          ir.IRObject{},
+			Seq:             int64(101 + i),
+			SecurityContext: testSecurityContext,
 		}
 		require.NoError(t, s.WriteCompletion(ctx, comp))
 
-		err := e.processCompletion(ctx, &comp)
+		err := e.ProcessCompletion(ctx, &comp)
 		require.NoError(t, err, "completion %d should succeed", i)
 	}
 
 	// 4th completion should fail due to quota
 	comp4 := ir.Completion{
-		ID:           "comp-4",
-		InvocationID: "inv-1",
-		OutputCase:   "Success",
-		Result:       ir.IRObject{},
-		Seq:          104,
+		ID:              "comp-4",
+		InvocationID:    "inv-1",
+		OutputCase:      "Success",
+		Result:          ir.IRObject{},
+		Seq:             104,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteCompletion(ctx, comp4))
 
-	err := e.processCompletion(ctx, &comp4)
+	err := e.ProcessCompletion(ctx, &comp4)
 	require.Error(t, err)
 	assert.True(t, IsStepsExceededError(err))
 
@@ -258,18 +261,20 @@ func TestQuota_IndependentFlows(t *testing.T) {
 
 	// Create invocations for two flows
 	inv1 := ir.Invocation{
-		ID:        "inv-1",
-		FlowToken: "flow-1",
-		ActionURI: "Test.action",
-		Args:      ir.IRObject{},
-		Seq:       100,
+		ID:              "inv-1",
+		FlowToken:       "flow-1",
+		ActionURI:       "Test.action",
+		Args:            ir.IRObject{},
+		Seq:             100,
+		SecurityContext: testSecurityContext,
 	}
 	inv2 := ir.Invocation{
-		ID:        "inv-2",
-		FlowToken: "flow-2",
-		ActionURI: "Test.action",
-		Args:      ir.IRObject{},
-		Seq:       200,
+		ID:              "inv-2",
+		FlowToken:       "flow-2",
+		ActionURI:       "Test.action",
+		Args:            ir.IRObject{},
+		Seq:             200,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteInvocation(ctx, inv1))
 	require.NoError(t, s.WriteInvocation(ctx, inv2))
@@ -278,14 +283,15 @@ func TestQuota_IndependentFlows(t *testing.T) {
 	for i := 0; i < 2; i++ {
 		compID := fmt.Sprintf("comp-flow1-%d", i)
 		comp := ir.Completion{
-			ID:           compID,
-			InvocationID: "inv-1",
-			OutputCase:   "Success",
-			Result:       ir.IRObject{},
-			Seq:          int64(101 + i),
+			ID:              compID,
+			InvocationID:    "inv-1",
+			OutputCase:      "Success",
+			Result:          ir.IRObject{},
+			Seq:             int64(101 + i),
+			SecurityContext: testSecurityContext,
 		}
 		require.NoError(t, s.WriteCompletion(ctx, comp))
-		err := e.processCompletion(ctx, &comp)
+		err := e.ProcessCompletion(ctx, &comp)
 		require.NoError(t, err)
 	}
 
@@ -294,14 +300,15 @@ func TestQuota_IndependentFlows(t *testing.T) {
 
 	// flow-2 should still work
 	comp := ir.Completion{
-		ID:           "comp-flow2-1",
-		InvocationID: "inv-2",
-		OutputCase:   "Success",
-		Result:       ir.IRObject{},
-		Seq:          201,
+		ID:              "comp-flow2-1",
+		InvocationID:    "inv-2",
+		OutputCase:      "Success",
+		Result:          ir.IRObject{},
+		Seq:             201,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteCompletion(ctx, comp))
-	err := e.processCompletion(ctx, &comp)
+	err := e.ProcessCompletion(ctx, &comp)
 	require.NoError(t, err)
 	assert.Equal(t, 1, e.QuotaFor("flow-2").Current())
 }
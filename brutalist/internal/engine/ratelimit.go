@@ -0,0 +1,107 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// tenantRateLimiter tracks steps per tenant and enforces a budget.
+//
+// A step is one completion whose security context carries the tenant
+// (CP-6). This keeps one tenant from monopolizing the single-writer loop
+// in multi-tenant deployments.
+//
+// The budget counts every completion written to the store, including those
+// rejected for being over budget, so it can be rebuilt on replay from the
+// recorded completions (see Engine.RestoreTenantSteps). A completion counts
+// once, when it is first written; re-processing a stored completion (e.g.
+// replay through ProcessCompletion) does not count it again.
+//
+// Not thread-safe: owned by the single-writer Run goroutine, like quotas.
+type tenantRateLimiter struct {
+	maxSteps int64            // Maximum steps per tenant
+	steps    map[string]int64 // map[tenant_id]steps taken
+}
+
+// check counts a step for the tenant and reports the new total and
+// whether it is within budget.
+func (l *tenantRateLimiter) check(tenantID string) (int64, bool) {
+	l.steps[tenantID]++
+	steps := l.steps[tenantID]
+	return steps, steps <= l.maxSteps
+}
+
+// WithTenantRateLimit enables per-tenant rate limiting.
+//
+// Each newly recorded completion counts as one step against its
+// SecurityContext.TenantID.
+// Once a tenant has taken more than maxStepsPerTenant steps, sync rules are
+// not evaluated for its completions and processing returns a RuntimeError
+// with ErrCodeTenantRateLimited. The completion itself is still recorded.
+//
+// Default: disabled. Non-positive values disable the limit.
+func WithTenantRateLimit(maxStepsPerTenant int) EngineOption {
+	return func(e *Engine) {
+		if maxStepsPerTenant <= 0 {
+			e.tenantLimiter = nil
+			return
+		}
+		e.tenantLimiter = &tenantRateLimiter{
+			maxSteps: int64(maxStepsPerTenant),
+			steps:    make(map[string]int64),
+		}
+	}
+}
+
+// RestoreTenantSteps rebuilds per-tenant step counts from the completions
+// recorded in the store, replacing any in-memory counts.
+//
+// Call it before Run when resuming from an existing store so rate limiting
+// continues exactly where it left off. No-op if WithTenantRateLimit is not
+// configured.
+func (e *Engine) RestoreTenantSteps(ctx context.Context) error {
+	if e.tenantLimiter == nil {
+		return nil
+	}
+
+	counts, err := e.store.CountCompletionsByTenant(ctx)
+	if err != nil {
+		return fmt.Errorf("restore tenant steps: %w", err)
+	}
+	e.tenantLimiter.steps = counts
+	return nil
+}
+
+// TenantSteps returns the number of steps counted for a tenant.
+// Used for testing and diagnostics. Always 0 if rate limiting is disabled.
+func (e *Engine) TenantSteps(tenantID string) int64 {
+	if e.tenantLimiter == nil {
+		return 0
+	}
+	return e.tenantLimiter.steps[tenantID]
+}
+
+// checkTenantRateLimit counts a step for the completion's tenant and
+// returns a RuntimeError if the tenant is over budget.
+// CRITICAL: Called only from Run() goroutine - single-writer guarantee.
+func (e *Engine) checkTenantRateLimit(flowToken, tenantID, completionID string) error {
+	if e.tenantLimiter == nil {
+		return nil
+	}
+
+	steps, ok := e.tenantLimiter.check(tenantID)
+	if ok {
+		return nil
+	}
+
+	slog.Warn("tenant rate limit exceeded",
+		"tenant_id", tenantID,
+		"flow_token", flowToken,
+		"completion_id", completionID,
+		"steps", steps,
+		"limit", e.tenantLimiter.maxSteps,
+		"event", "tenant_rate_limited",
+	)
+	return NewTenantRateLimitError(tenantID, flowToken, steps, e.tenantLimiter.maxSteps)
+}
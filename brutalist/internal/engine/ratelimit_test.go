@@ -0,0 +1,123 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+// processTenantCompletion writes an invocation in its own flow and processes
+// a completion for it under the given tenant.
+func processTenantCompletion(t *testing.T, e *Engine, tenantID string, n int) error {
+	t.Helper()
+	ctx := context.Background()
+	secCtx := ir.NewSecurityContext(tenantID, "user-1")
+
+	inv := ir.Invocation{
+		ID:              fmt.Sprintf("inv-%s-%d", tenantID, n),
+		FlowToken:       fmt.Sprintf("flow-%s-%d", tenantID, n),
+		ActionURI:       "Cart.addItem",
+		Args:            ir.IRObject{},
+		Seq:             e.Clock().Next(),
+		SecurityContext: secCtx,
+	}
+	require.NoError(t, e.store.WriteInvocation(ctx, inv))
+
+	return e.ProcessCompletion(ctx, &ir.Completion{
+		ID:              "comp-" + inv.ID,
+		InvocationID:    inv.ID,
+		OutputCase:      "Success",
+		Result:          ir.IRObject{"cart_id": ir.IRString("cart-1")},
+		Seq:             e.Clock().Next(),
+		SecurityContext: secCtx,
+	})
+}
+
+func TestTenantRateLimit_OneTenantLimitedOtherUnder(t *testing.T) {
+	s := setupTestStore(t)
+	e := New(s, nil, []ir.SyncRule{listenerTestSync}, newStubFlowGen(), WithTenantRateLimit(2))
+
+	// tenant-noisy spends its budget, then is rejected
+	require.NoError(t, processTenantCompletion(t, e, "tenant-noisy", 1))
+	require.NoError(t, processTenantCompletion(t, e, "tenant-noisy", 2))
+	err := processTenantCompletion(t, e, "tenant-noisy", 3)
+	require.Error(t, err)
+	assert.True(t, IsTenantRateLimitedError(err))
+	assert.Equal(t, ErrCodeTenantRateLimited, ErrorCode(err))
+	assert.Equal(t, "flow-tenant-noisy-3", err.(*RuntimeError).FlowToken)
+
+	// tenant-quiet is unaffected
+	require.NoError(t, processTenantCompletion(t, e, "tenant-quiet", 1))
+
+	assert.Equal(t, int64(3), e.TenantSteps("tenant-noisy"))
+	assert.Equal(t, int64(1), e.TenantSteps("tenant-quiet"))
+
+	// The rejected completion is recorded but did not fire its sync
+	comp, err := s.ReadCompletion(context.Background(), "comp-inv-tenant-noisy-3")
+	require.NoError(t, err)
+	firings, err := s.ReadSyncFiringsForCompletion(context.Background(), comp.ID)
+	require.NoError(t, err)
+	assert.Empty(t, firings)
+	assert.Equal(t, int64(3), e.Metrics().SyncsFired(), "two noisy + one quiet firing")
+}
+
+func TestTenantRateLimit_RestoredFromStore(t *testing.T) {
+	s := setupTestStore(t)
+	first := New(s, nil, nil, newStubFlowGen(), WithTenantRateLimit(2))
+	require.NoError(t, processTenantCompletion(t, first, "tenant-a", 1))
+	require.NoError(t, processTenantCompletion(t, first, "tenant-a", 2))
+
+	// A fresh engine over the same store picks up the spent budget
+	lastSeq, err := s.GetLastSeq(context.Background())
+	require.NoError(t, err)
+	resumed := NewWithClock(s, nil, nil, newStubFlowGen(), NewClockAt(lastSeq), WithTenantRateLimit(2))
+	require.NoError(t, resumed.RestoreTenantSteps(context.Background()))
+	assert.Equal(t, int64(2), resumed.TenantSteps("tenant-a"))
+
+	err = processTenantCompletion(t, resumed, "tenant-a", 3)
+	assert.True(t, IsTenantRateLimitedError(err))
+}
+
+func TestTenantRateLimit_RestoreThenReplayCountsOnce(t *testing.T) {
+	ctx := context.Background()
+	s := setupTestStore(t)
+	first := New(s, nil, []ir.SyncRule{listenerTestSync}, newStubFlowGen(), WithTenantRateLimit(2))
+	require.NoError(t, processTenantCompletion(t, first, "tenant-a", 1))
+	require.NoError(t, processTenantCompletion(t, first, "tenant-a", 2))
+
+	// Restore from the store, then re-drive sync evaluation for the same
+	// stored completions, as replay does
+	lastSeq, err := s.GetLastSeq(ctx)
+	require.NoError(t, err)
+	resumed := NewWithClock(s, nil, []ir.SyncRule{listenerTestSync}, newStubFlowGen(),
+		NewClockAt(lastSeq), WithTenantRateLimit(2))
+	require.NoError(t, resumed.RestoreTenantSteps(ctx))
+
+	for _, id := range []string{"comp-inv-tenant-a-1", "comp-inv-tenant-a-2"} {
+		comp, err := s.ReadCompletion(ctx, id)
+		require.NoError(t, err)
+		require.NoError(t, resumed.ProcessCompletion(ctx, &comp), "replayed completions are already counted")
+	}
+	assert.Equal(t, int64(2), resumed.TenantSteps("tenant-a"))
+
+	// The next new completion is the one over budget
+	err = processTenantCompletion(t, resumed, "tenant-a", 3)
+	require.Error(t, err)
+	assert.True(t, IsTenantRateLimitedError(err))
+	assert.Equal(t, int64(3), resumed.TenantSteps("tenant-a"))
+}
+
+func TestTenantRateLimit_DisabledByDefault(t *testing.T) {
+	e := New(setupTestStore(t), nil, nil, newStubFlowGen())
+
+	for n := 1; n <= 5; n++ {
+		require.NoError(t, processTenantCompletion(t, e, "tenant-a", n))
+	}
+	assert.Equal(t, int64(0), e.TenantSteps("tenant-a"))
+	require.NoError(t, e.RestoreTenantSteps(context.Background()))
+}
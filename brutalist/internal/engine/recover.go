@@ -0,0 +1,148 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/roach88/nysm/internal/ir"
+	"github.com/roach88/nysm/internal/store"
+)
+
+// RecoveredFiring reports how Recover resolved one orphaned sync firing.
+type RecoveredFiring struct {
+	FiringID int64
+	SyncID   string
+	Outcome  store.RepairOutcome
+
+	// InvocationID is set when the invocation was regenerated.
+	InvocationID string
+
+	// Reason explains why regeneration was not possible (linked or abandoned).
+	Reason string
+}
+
+// Recover resolves every orphaned sync firing in the store: firings written
+// without a provenance edge because of a crash mid-write.
+//
+// For each orphan, the sync rule is re-evaluated against the firing's
+// completion. If it reproduces a binding with the firing's binding hash, the
+// intended invocation is regenerated at its original seq (firing seq - 1), so
+// its content-addressed ID is identical to what the crashed run would have
+// written. Otherwise recovery falls back to store.RepairOrphanedFiring, which
+// links an existing invocation or records the firing as abandoned with the
+// reason regeneration failed.
+//
+// Recover must run before Run starts; it shares the single-writer guarantee.
+// Results are in orphan order (seq ASC, id ASC).
+//
+// Re-evaluating sync rules is only faithful if the specs are unchanged, so
+// Recover first runs CheckSpecHash and returns its *SpecHashMismatchError
+// (nothing is repaired) unless WithAllowSpecDrift is set.
+func (e *Engine) Recover(ctx context.Context) ([]RecoveredFiring, error) {
+	if err := e.CheckSpecHash(ctx); err != nil {
+		return nil, fmt.Errorf("recover: %w", err)
+	}
+
+	orphans, err := e.store.FindOrphanedSyncFirings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("recover: %w", err)
+	}
+
+	results := make([]RecoveredFiring, 0, len(orphans))
+	for _, firing := range orphans {
+		result := RecoveredFiring{FiringID: firing.ID, SyncID: firing.SyncID}
+
+		inv, reason, err := e.regenerateInvocation(ctx, firing)
+		if err != nil {
+			return results, fmt.Errorf("recover firing %d: %w", firing.ID, err)
+		}
+
+		if reason == "" {
+			result.Outcome, err = e.store.CompleteOrphanedFiring(ctx, firing.ID, inv)
+			if err != nil {
+				return results, fmt.Errorf("recover firing %d: %w", firing.ID, err)
+			}
+			result.InvocationID = inv.ID
+			e.cycleDetector.Record(inv.FlowToken, firing.SyncID, firing.BindingHash)
+		} else {
+			result.Reason = reason
+			result.Outcome, err = e.store.RepairOrphanedFiring(ctx, firing.ID, reason)
+			if err != nil {
+				return results, fmt.Errorf("recover firing %d: %w", firing.ID, err)
+			}
+		}
+
+		slog.Info("orphaned sync firing recovered",
+			"firing_id", firing.ID,
+			"sync_id", firing.SyncID,
+			"outcome", result.Outcome,
+			"invocation_id", result.InvocationID,
+			"reason", result.Reason,
+		)
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// regenerateInvocation reconstructs the invocation an orphaned firing should
+// have produced. A non-empty reason means the firing cannot be reconstructed
+// from the current sync rules and state; err is reserved for store failures.
+func (e *Engine) regenerateInvocation(ctx context.Context, firing ir.SyncFiring) (ir.Invocation, string, error) {
+	var sync *ir.SyncRule
+	for i := range e.syncs {
+		if e.syncs[i].ID == firing.SyncID {
+			sync = &e.syncs[i]
+			break
+		}
+	}
+	if sync == nil {
+		return ir.Invocation{}, fmt.Sprintf("sync rule %q is not registered", firing.SyncID), nil
+	}
+
+	comp, err := e.store.ReadCompletion(ctx, firing.CompletionID)
+	if err != nil {
+		return ir.Invocation{}, "", fmt.Errorf("read completion %s: %w", firing.CompletionID, err)
+	}
+	trigger, err := e.store.ReadInvocation(ctx, comp.InvocationID)
+	if err != nil {
+		return ir.Invocation{}, "", fmt.Errorf("read invocation %s: %w", comp.InvocationID, err)
+	}
+
+	if !e.matchSync(*sync, &trigger, &comp) {
+		return ir.Invocation{}, "sync rule no longer matches the completion", nil
+	}
+
+	bindings, err := extractBindings(sync.When, &comp)
+	if err != nil {
+		return ir.Invocation{}, fmt.Sprintf("extract bindings: %v", err), nil
+	}
+
+	// Flow token is inherited from the triggering invocation (CP-7)
+	bindingSets, err := e.executeWhereClause(ctx, *sync, trigger.FlowToken, bindings)
+	if err != nil {
+		return ir.Invocation{}, fmt.Sprintf("execute where-clause: %v", err), nil
+	}
+
+	for _, bindingSet := range bindingSets {
+		hash, err := ir.BindingHash(bindingSet)
+		if err != nil {
+			return ir.Invocation{}, fmt.Sprintf("compute binding hash: %v", err), nil
+		}
+		if hash != firing.BindingHash {
+			continue
+		}
+
+		// fireSyncRule takes the invocation seq immediately before the firing seq
+		inv, err := e.generateInvocationAt(trigger.FlowToken, comp.SecurityContext, sync.Then, bindingSet, func() int64 {
+			return firing.Seq - 1
+		})
+		if err != nil {
+			return ir.Invocation{}, fmt.Sprintf("generate invocation: %v", err), nil
+		}
+		return inv, "", nil
+	}
+
+	return ir.Invocation{}, "where-clause no longer produces the fired binding", nil
+}
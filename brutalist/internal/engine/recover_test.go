@@ -0,0 +1,160 @@
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/roach88/nysm/internal/ir"
+	"github.com/roach88/nysm/internal/store"
+)
+
+// reserveOnCheckoutSync fires Inventory.reserve for the checked-out item.
+func reserveOnCheckoutSync() ir.SyncRule {
+	return ir.SyncRule{
+		ID: "sync-reserve",
+		When: ir.WhenClause{
+			ActionRef: "Cart.checkout",
+			EventType: "completed",
+			Bindings:  map[string]string{"item_id": "item_id"},
+		},
+		Then: ir.ThenClause{
+			ActionRef: "Inventory.reserve",
+			Args:      map[string]string{"item_id": "${bound.item_id}"},
+		},
+	}
+}
+
+// writeCheckout writes a Cart.checkout invocation and returns its completion
+// (not yet written).
+func writeCheckout(t *testing.T, s *store.Store) *ir.Completion {
+	t.Helper()
+	inv := ir.Invocation{
+		ID:              "inv-checkout",
+		FlowToken:       "flow-1",
+		ActionURI:       "Cart.checkout",
+		Args:            ir.IRObject{"item_id": ir.IRString("widget")},
+		Seq:             100,
+		SecurityContext: testSecurityContext,
+	}
+	require.NoError(t, s.WriteInvocation(context.Background(), inv))
+
+	return &ir.Completion{
+		ID:              "comp-checkout",
+		InvocationID:    inv.ID,
+		OutputCase:      "Success",
+		Result:          ir.IRObject{"item_id": ir.IRString("widget")},
+		Seq:             101,
+		SecurityContext: testSecurityContext,
+	}
+}
+
+// simulateCrashedFiring writes the completion and a sync firing whose
+// invocation and provenance edge were lost in a crash.
+func simulateCrashedFiring(t *testing.T, s *store.Store, comp *ir.Completion, syncID string, seq int64) {
+	t.Helper()
+	ctx := context.Background()
+	require.NoError(t, s.WriteCompletion(ctx, *comp))
+
+	hash, err := ir.BindingHash(ir.IRObject{"item_id": ir.IRString("widget")})
+	require.NoError(t, err)
+
+	_, inserted, err := s.WriteSyncFiring(ctx, ir.SyncFiring{
+		CompletionID: comp.ID,
+		SyncID:       syncID,
+		BindingHash:  hash,
+		Seq:          seq,
+	})
+	require.NoError(t, err)
+	require.True(t, inserted)
+}
+
+func TestRecover_RegeneratesIdenticalInvocation(t *testing.T) {
+	ctx := context.Background()
+
+	// Reference run: no crash
+	refStore := setupTestStore(t)
+	refEngine := New(refStore, nil, []ir.SyncRule{reserveOnCheckoutSync()}, nil)
+	comp := writeCheckout(t, refStore)
+	require.NoError(t, refEngine.ProcessCompletion(ctx, comp))
+
+	want, err := refStore.ReadTriggered(ctx, comp.ID)
+	require.NoError(t, err)
+	require.Len(t, want, 1)
+
+	// Crashed run: firing written, invocation and edge lost
+	s := setupTestStore(t)
+	comp = writeCheckout(t, s)
+	simulateCrashedFiring(t, s, comp, "sync-reserve", want[0].Seq+1)
+
+	e := New(s, nil, []ir.SyncRule{reserveOnCheckoutSync()}, nil)
+	results, err := e.Recover(ctx)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, store.RepairRegenerated, results[0].Outcome)
+	assert.Equal(t, want[0].ID, results[0].InvocationID)
+
+	got, err := s.ReadTriggered(ctx, comp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, want, got, "recovery must reproduce the invocation the crashed run would have written")
+
+	orphans, err := s.FindOrphanedSyncFirings(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, orphans)
+
+	// Recovery is idempotent
+	results, err = e.Recover(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestRecover_AbandonsUnknownSync(t *testing.T) {
+	ctx := context.Background()
+	s := setupTestStore(t)
+
+	comp := writeCheckout(t, s)
+	simulateCrashedFiring(t, s, comp, "sync-removed", 103)
+
+	e := New(s, nil, []ir.SyncRule{reserveOnCheckoutSync()}, nil)
+	results, err := e.Recover(ctx)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, store.RepairAbandoned, results[0].Outcome)
+	assert.Contains(t, results[0].Reason, "not registered")
+	assert.Empty(t, results[0].InvocationID)
+
+	// The persisted record explains why the firing was abandoned
+	bundle, err := s.ArchiveFlow(ctx, "flow-1")
+	require.NoError(t, err)
+	require.Len(t, bundle.AbandonedFirings, 1)
+	assert.Equal(t, results[0].Reason, bundle.AbandonedFirings[0].Reason)
+
+	state, err := s.GetFlowState(ctx, "flow-1")
+	require.NoError(t, err)
+	assert.Equal(t, 0, state.OrphanedFirings)
+	assert.True(t, state.IsComplete)
+}
+
+func TestRecover_AbandonsWhenBindingNotReproduced(t *testing.T) {
+	ctx := context.Background()
+	s := setupTestStore(t)
+
+	comp := writeCheckout(t, s)
+	require.NoError(t, s.WriteCompletion(ctx, *comp))
+	_, _, err := s.WriteSyncFiring(ctx, ir.SyncFiring{
+		CompletionID: comp.ID,
+		SyncID:       "sync-reserve",
+		BindingHash:  "hash-from-a-different-binding",
+		Seq:          103,
+	})
+	require.NoError(t, err)
+
+	e := New(s, nil, []ir.SyncRule{reserveOnCheckoutSync()}, nil)
+	results, err := e.Recover(ctx)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, store.RepairAbandoned, results[0].Outcome)
+	assert.Contains(t, results[0].Reason, "no longer produces")
+}
@@ -89,12 +89,21 @@
 //
 // With atomic writes, either all 3 records exist or none do.
 //
+// ## Spec Versioning
+//
+// Replay re-evaluates sync rules, so it only reproduces the original run if
+// the specs are unchanged. Invocations record the engine's spec hash
+// (WithSpecHash, computed by compiler.SpecHash); Recover refuses to run with
+// ErrSpecHashMismatch when the store holds a different one, unless
+// WithAllowSpecDrift is set.
+//
 // ## Key Functions
 //
 //   - executeThen: Uses WriteSyncFiringAtomic for crash-safe idempotent writes
 //   - store.WriteSyncFiringAtomic: Atomic write with duplicate detection
 //   - ir.BindingHash: Deterministic hash via canonical JSON
 //   - store.FindIncompleteFlows: Identifies flows needing recovery
+//   - Engine.Recover: Regenerates or abandons orphaned sync firings
 //   - store.ReplayFlow: Returns events for explicit replay
 //
 // ## References
@@ -30,20 +30,22 @@ func TestReplay_PartialReplaySkipsExistingFirings(t *testing.T) {
 
 	// Setup: invocation and completion
 	inv := ir.Invocation{
-		ID:        "inv-1",
-		FlowToken: "flow-1",
-		ActionURI: "Cart.checkout",
-		Args:      ir.IRObject{"cart_id": ir.IRString("cart-123")},
-		Seq:       100,
+		ID:              "inv-1",
+		FlowToken:       "flow-1",
+		ActionURI:       "Cart.checkout",
+		Args:            ir.IRObject{"cart_id": ir.IRString("cart-123")},
+		Seq:             100,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteInvocation(ctx, inv))
 
 	comp := ir.Completion{
-		ID:           "comp-1",
-		InvocationID: "inv-1",
-		OutputCase:   "Success",
-		Result:       ir.IRObject{"cart_id": ir.IRString("cart-123")},
-		Seq:          101,
+		ID:              "comp-1",
+		InvocationID:    "inv-1",
+		OutputCase:      "Success",
+		Result:          ir.IRObject{"cart_id": ir.IRString("cart-123")},
+		Seq:             101,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteCompletion(ctx, comp))
 
@@ -103,20 +105,22 @@ func TestReplay_FullReplayProducesIdenticalResults(t *testing.T) {
 		s *store.Store
 	}{{e1, s1}, {e2, s2}} {
 		inv := ir.Invocation{
-			ID:        "inv-1",
-			FlowToken: "flow-1",
-			ActionURI: "Cart.checkout",
-			Args:      ir.IRObject{},
-			Seq:       100,
+			ID:              "inv-1",
+			FlowToken:       "flow-1",
+			ActionURI:       "Cart.checkout",
+			Args:            ir.IRObject{},
+			Seq:             100,
+			SecurityContext: testSecurityContext,
 		}
 		require.NoError(t, pair.s.WriteInvocation(ctx, inv))
 
 		comp := ir.Completion{
-			ID:           "comp-1",
-			InvocationID: "inv-1",
-			OutputCase:   "Success",
-			Result:       ir.IRObject{},
-			Seq:          101,
+			ID:              "comp-1",
+			InvocationID:    "inv-1",
+			OutputCase:      "Success",
+			Result:          ir.IRObject{},
+			Seq:             101,
+			SecurityContext: testSecurityContext,
 		}
 		require.NoError(t, pair.s.WriteCompletion(ctx, comp))
 	}
@@ -137,11 +141,12 @@ func TestReplay_FullReplayProducesIdenticalResults(t *testing.T) {
 	}
 
 	comp := ir.Completion{
-		ID:           "comp-1",
-		InvocationID: "inv-1",
-		OutputCase:   "Success",
-		Result:       ir.IRObject{},
-		Seq:          101,
+		ID:              "comp-1",
+		InvocationID:    "inv-1",
+		OutputCase:      "Success",
+		Result:          ir.IRObject{},
+		Seq:             101,
+		SecurityContext: testSecurityContext,
 	}
 
 	// Execute on both engines
@@ -171,20 +176,22 @@ func TestReplay_MultiCrashRecovery(t *testing.T) {
 
 	// Setup
 	inv := ir.Invocation{
-		ID:        "inv-1",
-		FlowToken: "flow-1",
-		ActionURI: "Cart.checkout",
-		Args:      ir.IRObject{},
-		Seq:       100,
+		ID:              "inv-1",
+		FlowToken:       "flow-1",
+		ActionURI:       "Cart.checkout",
+		Args:            ir.IRObject{},
+		Seq:             100,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteInvocation(ctx, inv))
 
 	comp := ir.Completion{
-		ID:           "comp-1",
-		InvocationID: "inv-1",
-		OutputCase:   "Success",
-		Result:       ir.IRObject{},
-		Seq:          101,
+		ID:              "comp-1",
+		InvocationID:    "inv-1",
+		OutputCase:      "Success",
+		Result:          ir.IRObject{},
+		Seq:             101,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteCompletion(ctx, comp))
 
@@ -265,20 +272,22 @@ func TestReplay_QueueNotDuplicatedOnReplay(t *testing.T) {
 
 	// Setup
 	inv := ir.Invocation{
-		ID:        "inv-1",
-		FlowToken: "flow-1",
-		ActionURI: "Cart.checkout",
-		Args:      ir.IRObject{},
-		Seq:       100,
+		ID:              "inv-1",
+		FlowToken:       "flow-1",
+		ActionURI:       "Cart.checkout",
+		Args:            ir.IRObject{},
+		Seq:             100,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteInvocation(ctx, inv))
 
 	comp := ir.Completion{
-		ID:           "comp-1",
-		InvocationID: "inv-1",
-		OutputCase:   "Success",
-		Result:       ir.IRObject{},
-		Seq:          101,
+		ID:              "comp-1",
+		InvocationID:    "inv-1",
+		OutputCase:      "Success",
+		Result:          ir.IRObject{},
+		Seq:             101,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteCompletion(ctx, comp))
 
@@ -320,20 +329,22 @@ func TestReplay_NewBindingsDuringReplayFire(t *testing.T) {
 
 	// Setup
 	inv := ir.Invocation{
-		ID:        "inv-1",
-		FlowToken: "flow-1",
-		ActionURI: "Cart.checkout",
-		Args:      ir.IRObject{},
-		Seq:       100,
+		ID:              "inv-1",
+		FlowToken:       "flow-1",
+		ActionURI:       "Cart.checkout",
+		Args:            ir.IRObject{},
+		Seq:             100,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteInvocation(ctx, inv))
 
 	comp := ir.Completion{
-		ID:           "comp-1",
-		InvocationID: "inv-1",
-		OutputCase:   "Success",
-		Result:       ir.IRObject{},
-		Seq:          101,
+		ID:              "comp-1",
+		InvocationID:    "inv-1",
+		OutputCase:      "Success",
+		Result:          ir.IRObject{},
+		Seq:             101,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteCompletion(ctx, comp))
 
@@ -392,20 +403,22 @@ func TestReplay_ZeroExistingFiringsBehavesNormally(t *testing.T) {
 
 	// Setup
 	inv := ir.Invocation{
-		ID:        "inv-1",
-		FlowToken: "flow-1",
-		ActionURI: "Cart.checkout",
-		Args:      ir.IRObject{},
-		Seq:       100,
+		ID:              "inv-1",
+		FlowToken:       "flow-1",
+		ActionURI:       "Cart.checkout",
+		Args:            ir.IRObject{},
+		Seq:             100,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteInvocation(ctx, inv))
 
 	comp := ir.Completion{
-		ID:           "comp-1",
-		InvocationID: "inv-1",
-		OutputCase:   "Success",
-		Result:       ir.IRObject{},
-		Seq:          101,
+		ID:              "comp-1",
+		InvocationID:    "inv-1",
+		OutputCase:      "Success",
+		Result:          ir.IRObject{},
+		Seq:             101,
+		SecurityContext: testSecurityContext,
 	}
 	require.NoError(t, s.WriteCompletion(ctx, comp))
 
@@ -0,0 +1,245 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/roach88/nysm/internal/ir"
+	"github.com/roach88/nysm/internal/store"
+)
+
+// scheduledFiring is a sync firing deferred by ThenClause.AfterSteps.
+type scheduledFiring struct {
+	id         int64 // store.ScheduledFiring row, deleted on release
+	targetSeq  int64 // Released once the clock reaches this seq
+	sync       ir.SyncRule
+	completion ir.Completion
+	flowToken  string
+	bindings   ir.IRObject
+}
+
+// firingSchedule holds deferred sync firings keyed by target seq.
+//
+// Deferral is measured on the logical clock (CP-2), not wall-clock time, so
+// the same event log releases the same firings at the same seqs on replay.
+// The firing, its invocation, and the provenance edge are written atomically
+// on release (CP-1), exactly as for an immediate firing. Until then the
+// schedule is mirrored in the store (store.ScheduledFiring), so the flow is
+// reported incomplete and a restarted engine reloads it (see loadSchedule).
+//
+// Not thread-safe: owned by the single-writer Run goroutine, like quotas.
+type firingSchedule struct {
+	pending []scheduledFiring // Sorted by targetSeq, then scheduling order
+}
+
+// add schedules f, keeping pending ordered by target seq. Firings with the
+// same target keep the order they were scheduled in.
+func (s *firingSchedule) add(f scheduledFiring) {
+	i := len(s.pending)
+	for i > 0 && s.pending[i-1].targetSeq > f.targetSeq {
+		i--
+	}
+	s.pending = append(s.pending, scheduledFiring{})
+	copy(s.pending[i+1:], s.pending[i:])
+	s.pending[i] = f
+}
+
+// popDue removes and returns the earliest firing whose target seq is at or
+// before now.
+func (s *firingSchedule) popDue(now int64) (scheduledFiring, bool) {
+	if len(s.pending) == 0 || s.pending[0].targetSeq > now {
+		return scheduledFiring{}, false
+	}
+	f := s.pending[0]
+	s.pending = s.pending[1:]
+	return f, true
+}
+
+// contains reports whether the firing with the given store ID is scheduled.
+func (s *firingSchedule) contains(id int64) bool {
+	for _, f := range s.pending {
+		if f.id == id {
+			return true
+		}
+	}
+	return false
+}
+
+// forget drops all scheduled firings of a flow.
+func (s *firingSchedule) forget(flowToken string) {
+	kept := s.pending[:0]
+	for _, f := range s.pending {
+		if f.flowToken != flowToken {
+			kept = append(kept, f)
+		}
+	}
+	s.pending = kept
+}
+
+// ScheduledCount returns the number of sync firings waiting for their
+// AfterSteps delay to elapse. Used for testing and diagnostics.
+func (e *Engine) ScheduledCount() int {
+	return len(e.schedule.pending)
+}
+
+// scheduleFiring defers a sync firing until sync.Then.AfterSteps seq ticks
+// have elapsed after the triggering completion. As in expireIdleFlows, "now"
+// is the later of the completion's seq and the engine clock.
+//
+// The firing is written to the store before it is scheduled in memory.
+// Scheduling is idempotent per binding (CP-1): a firing that is already in
+// the store (e.g. the completion is processed again) is not scheduled twice.
+// CRITICAL: Called only from Run() goroutine - single-writer guarantee.
+func (e *Engine) scheduleFiring(ctx context.Context, sync ir.SyncRule, comp *ir.Completion, flowToken string, bindings ir.IRObject) error {
+	bindingHash, err := ir.BindingHash(bindings)
+	if err != nil {
+		return fmt.Errorf("schedule firing: binding hash: %w", err)
+	}
+
+	target := max(e.clock.Current(), comp.Seq) + int64(sync.Then.AfterSteps)
+	id, inserted, err := e.store.WriteScheduledFiring(ctx, store.ScheduledFiring{
+		CompletionID: comp.ID,
+		SyncID:       sync.ID,
+		BindingHash:  bindingHash,
+		FlowToken:    flowToken,
+		Bindings:     bindings,
+		TargetSeq:    target,
+	})
+	if err != nil {
+		return fmt.Errorf("schedule firing: %w", err)
+	}
+	if !inserted {
+		slog.Debug("sync firing already scheduled",
+			"sync_id", sync.ID,
+			"completion_id", comp.ID,
+			"binding_hash", bindingHash,
+		)
+		return nil
+	}
+
+	e.schedule.add(scheduledFiring{
+		id:         id,
+		targetSeq:  target,
+		sync:       sync,
+		completion: *comp,
+		flowToken:  flowToken,
+		bindings:   ir.CloneObject(bindings),
+	})
+
+	slog.Debug("sync firing scheduled",
+		"sync_id", sync.ID,
+		"completion_id", comp.ID,
+		"flow_token", flowToken,
+		"target_seq", target,
+	)
+	return nil
+}
+
+// loadSchedule reloads firings scheduled by a previous run from the store.
+// Called when Run starts; firings already scheduled in memory are skipped.
+//
+// A firing whose sync rule is no longer registered can never be released,
+// so it is logged and deleted rather than keeping its flow incomplete.
+// CRITICAL: Called only from Run() goroutine - single-writer guarantee.
+func (e *Engine) loadSchedule(ctx context.Context) error {
+	stored, err := e.store.ReadScheduledFirings(ctx)
+	if err != nil {
+		return fmt.Errorf("load schedule: %w", err)
+	}
+
+	for _, f := range stored {
+		if e.schedule.contains(f.ID) {
+			continue
+		}
+
+		var sync *ir.SyncRule
+		for i := range e.syncs {
+			if e.syncs[i].ID == f.SyncID {
+				sync = &e.syncs[i]
+				break
+			}
+		}
+		if sync == nil {
+			slog.Warn("dropping scheduled firing of unregistered sync rule",
+				"sync_id", f.SyncID,
+				"completion_id", f.CompletionID,
+				"flow_token", f.FlowToken,
+				"target_seq", f.TargetSeq,
+			)
+			if err := e.store.DeleteScheduledFiring(ctx, f.ID); err != nil {
+				return fmt.Errorf("load schedule: %w", err)
+			}
+			continue
+		}
+
+		comp, err := e.store.ReadCompletion(ctx, f.CompletionID)
+		if err != nil {
+			return fmt.Errorf("load schedule: read completion %s: %w", f.CompletionID, err)
+		}
+
+		e.schedule.add(scheduledFiring{
+			id:         f.ID,
+			targetSeq:  f.TargetSeq,
+			sync:       *sync,
+			completion: comp,
+			flowToken:  f.FlowToken,
+			bindings:   f.Bindings,
+		})
+	}
+
+	if len(stored) > 0 {
+		slog.Info("scheduled firings reloaded", "count", len(e.schedule.pending))
+	}
+	return nil
+}
+
+// releaseScheduledFirings fires every scheduled firing whose target seq has
+// been reached. Called after each processed event with the seq of that
+// event; "now" is the later of that seq and the engine clock. Released
+// firings advance the clock themselves, which may release further firings.
+//
+// A released firing is subject to the same idempotency and cycle checks as
+// an immediate one. Failures are logged and the remaining firings are still
+// released, as in evaluateSyncs. The store row is deleted after the firing
+// is written; if the engine stops in between, the reloaded firing is an
+// idempotent re-firing (CP-1).
+// CRITICAL: Called only from Run() goroutine - single-writer guarantee.
+func (e *Engine) releaseScheduledFirings(ctx context.Context, eventSeq int64) {
+	for {
+		f, ok := e.schedule.popDue(max(e.clock.Current(), eventSeq))
+		if !ok {
+			return
+		}
+
+		if err := e.fireSyncRule(ctx, f.sync, &f.completion, f.flowToken, f.bindings); err != nil {
+			slog.Error("scheduled sync firing failed",
+				"sync_id", f.sync.ID,
+				"completion_id", f.completion.ID,
+				"flow_token", f.flowToken,
+				"target_seq", f.targetSeq,
+				"error", err,
+			)
+		}
+		if err := e.store.DeleteScheduledFiring(ctx, f.id); err != nil {
+			slog.Error("failed to delete released scheduled firing",
+				"sync_id", f.sync.ID,
+				"completion_id", f.completion.ID,
+				"target_seq", f.targetSeq,
+				"error", err,
+			)
+		}
+	}
+}
+
+// forgetScheduledFirings drops a flow's scheduled firings from memory and
+// from the store.
+func (e *Engine) forgetScheduledFirings(flowToken string) {
+	e.schedule.forget(flowToken)
+	if _, err := e.store.DeleteScheduledFiringsForFlow(context.Background(), flowToken); err != nil {
+		slog.Error("failed to delete scheduled firings",
+			"flow_token", flowToken,
+			"error", err,
+		)
+	}
+}
@@ -0,0 +1,229 @@
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/roach88/nysm/internal/ir"
+	"github.com/roach88/nysm/internal/store"
+)
+
+// remindAfterPlaceSync invokes Order.remind three seq ticks after
+// Order.place completes.
+func remindAfterPlaceSync() ir.SyncRule {
+	return ir.SyncRule{
+		ID: "sync-remind",
+		When: ir.WhenClause{
+			ActionRef: "Order.place",
+			EventType: "completed",
+			Bindings:  map[string]string{"order_id": "order_id"},
+		},
+		Then: ir.ThenClause{
+			ActionRef:  "Order.remind",
+			Args:       map[string]string{"order_id": "${bound.order_id}"},
+			AfterSteps: 3,
+		},
+	}
+}
+
+// completeAction writes an invocation of action at seq and processes its
+// completion at seq+1.
+func completeAction(t *testing.T, e *Engine, s *store.Store, flowToken string, action ir.ActionRef, result ir.IRObject, seq int64) ir.Completion {
+	t.Helper()
+	ctx := context.Background()
+
+	inv := ir.NewInvocation(flowToken, action).
+		WithSeq(seq).
+		WithSecurityContext(testSecurityContext).
+		MustBuild()
+	require.NoError(t, s.WriteInvocation(ctx, inv))
+
+	comp := ir.NewCompletion(inv.ID, "Success").
+		WithResult(result).
+		WithSeq(seq + 1).
+		WithSecurityContext(testSecurityContext).
+		MustBuild()
+	require.NoError(t, e.ProcessCompletion(ctx, &comp))
+	return comp
+}
+
+func TestEngine_AfterSteps_DefersInvocation(t *testing.T) {
+	ctx := context.Background()
+	s := setupTestStore(t)
+	e := New(s, nil, []ir.SyncRule{remindAfterPlaceSync()}, nil)
+
+	placed := completeAction(t, e, s, "flow-1", "Order.place",
+		ir.IRObject{"order_id": ir.IRString("o-1")}, 1)
+
+	// Target seq is 2 + 3 = 5: nothing is written yet
+	triggered, err := s.ReadTriggered(ctx, placed.ID)
+	require.NoError(t, err)
+	assert.Empty(t, triggered)
+	assert.Equal(t, 1, e.ScheduledCount())
+
+	// Seq 4 is still before the target
+	completeAction(t, e, s, "flow-2", "Cart.view", nil, 3)
+	triggered, err = s.ReadTriggered(ctx, placed.ID)
+	require.NoError(t, err)
+	assert.Empty(t, triggered)
+
+	// Seq 6 passes it
+	completeAction(t, e, s, "flow-2", "Cart.view", nil, 5)
+	triggered, err = s.ReadTriggered(ctx, placed.ID)
+	require.NoError(t, err)
+	require.Len(t, triggered, 1)
+	assert.Equal(t, 0, e.ScheduledCount())
+
+	remind := triggered[0]
+	assert.Equal(t, ir.ActionRef("Order.remind"), remind.ActionURI)
+	assert.Equal(t, "flow-1", remind.FlowToken)
+	assert.Equal(t, ir.IRObject{"order_id": ir.IRString("o-1")}, remind.Args)
+
+	// The firing was written atomically with its invocation (CP-1)
+	state, err := s.GetFlowState(ctx, "flow-1")
+	require.NoError(t, err)
+	assert.Equal(t, 0, state.OrphanedFirings)
+}
+
+func TestEngine_AfterSteps_Deterministic(t *testing.T) {
+	run := func() []ir.Invocation {
+		ctx := context.Background()
+		s := setupTestStore(t)
+		e := New(s, nil, []ir.SyncRule{remindAfterPlaceSync()}, nil)
+
+		placed := completeAction(t, e, s, "flow-1", "Order.place",
+			ir.IRObject{"order_id": ir.IRString("o-1")}, 1)
+		for seq := int64(3); seq < 9; seq += 2 {
+			completeAction(t, e, s, "flow-2", "Cart.view", nil, seq)
+		}
+
+		triggered, err := s.ReadTriggered(ctx, placed.ID)
+		require.NoError(t, err)
+		return triggered
+	}
+
+	first := run()
+	require.Len(t, first, 1)
+	assert.Equal(t, first, run())
+}
+
+func TestEngine_AfterSteps_ReleasedInTargetOrder(t *testing.T) {
+	ctx := context.Background()
+	s := setupTestStore(t)
+
+	soon := remindAfterPlaceSync()
+	soon.ID = "sync-soon"
+	soon.Then.ActionRef = "Order.nudge"
+	soon.Then.AfterSteps = 1
+	later := remindAfterPlaceSync()
+
+	// Declared later-first; release follows target seq, not declaration order
+	e := New(s, nil, []ir.SyncRule{later, soon}, nil)
+
+	placed := completeAction(t, e, s, "flow-1", "Order.place",
+		ir.IRObject{"order_id": ir.IRString("o-1")}, 1)
+	assert.Equal(t, 2, e.ScheduledCount())
+
+	completeAction(t, e, s, "flow-2", "Cart.view", nil, 10)
+
+	triggered, err := s.ReadTriggered(ctx, placed.ID)
+	require.NoError(t, err)
+	require.Len(t, triggered, 2)
+	assert.Equal(t, ir.ActionRef("Order.nudge"), triggered[0].ActionURI)
+	assert.Equal(t, ir.ActionRef("Order.remind"), triggered[1].ActionURI)
+}
+
+func TestEngine_AfterSteps_CleanupFlowDropsScheduled(t *testing.T) {
+	ctx := context.Background()
+	s := setupTestStore(t)
+	e := New(s, nil, []ir.SyncRule{remindAfterPlaceSync()}, nil)
+
+	placed := completeAction(t, e, s, "flow-1", "Order.place",
+		ir.IRObject{"order_id": ir.IRString("o-1")}, 1)
+	require.Equal(t, 1, e.ScheduledCount())
+
+	e.CleanupFlow("flow-1")
+	assert.Equal(t, 0, e.ScheduledCount())
+
+	stored, err := s.ReadScheduledFirings(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, stored, "cleanup also drops the persisted schedule")
+
+	completeAction(t, e, s, "flow-2", "Cart.view", nil, 10)
+	triggered, err := s.ReadTriggered(ctx, placed.ID)
+	require.NoError(t, err)
+	assert.Empty(t, triggered)
+}
+
+func TestEngine_AfterSteps_FlowIncompleteWhileScheduled(t *testing.T) {
+	ctx := context.Background()
+	s := setupTestStore(t)
+	e := New(s, nil, []ir.SyncRule{remindAfterPlaceSync()}, nil)
+
+	completeAction(t, e, s, "flow-1", "Order.place",
+		ir.IRObject{"order_id": ir.IRString("o-1")}, 1)
+
+	// Every invocation has completed, but the reminder is still due
+	state, err := s.GetFlowState(ctx, "flow-1")
+	require.NoError(t, err)
+	assert.Equal(t, 0, state.PendingCount)
+	assert.Equal(t, 1, state.ScheduledCount)
+	assert.False(t, state.IsComplete)
+
+	completeAction(t, e, s, "flow-2", "Cart.view", nil, 5)
+
+	state, err = s.GetFlowState(ctx, "flow-1")
+	require.NoError(t, err)
+	assert.Equal(t, 0, state.ScheduledCount, "released firings leave the store schedule")
+	assert.Equal(t, 1, state.PendingCount, "the released reminder awaits its completion")
+}
+
+func TestEngine_AfterSteps_ReloadedAfterRestart(t *testing.T) {
+	ctx := context.Background()
+	s := setupTestStore(t)
+	syncs := []ir.SyncRule{remindAfterPlaceSync()}
+
+	first := New(s, nil, syncs, nil)
+	placed := completeAction(t, first, s, "flow-1", "Order.place",
+		ir.IRObject{"order_id": ir.IRString("o-1")}, 1)
+	require.Equal(t, 1, first.ScheduledCount())
+
+	// A new engine over the same store picks the schedule up
+	restarted := New(s, nil, syncs, nil)
+	require.NoError(t, restarted.loadSchedule(ctx))
+	assert.Equal(t, 1, restarted.ScheduledCount())
+
+	// Loading again does not schedule the firing twice
+	require.NoError(t, restarted.loadSchedule(ctx))
+	assert.Equal(t, 1, restarted.ScheduledCount())
+
+	completeAction(t, restarted, s, "flow-2", "Cart.view", nil, 5)
+
+	triggered, err := s.ReadTriggered(ctx, placed.ID)
+	require.NoError(t, err)
+	require.Len(t, triggered, 1)
+	assert.Equal(t, ir.ActionRef("Order.remind"), triggered[0].ActionURI)
+	assert.Equal(t, ir.IRObject{"order_id": ir.IRString("o-1")}, triggered[0].Args)
+	assert.Equal(t, 0, restarted.ScheduledCount())
+}
+
+func TestEngine_AfterSteps_ReloadDropsUnregisteredSync(t *testing.T) {
+	ctx := context.Background()
+	s := setupTestStore(t)
+
+	first := New(s, nil, []ir.SyncRule{remindAfterPlaceSync()}, nil)
+	completeAction(t, first, s, "flow-1", "Order.place",
+		ir.IRObject{"order_id": ir.IRString("o-1")}, 1)
+
+	restarted := New(s, nil, nil, nil)
+	require.NoError(t, restarted.loadSchedule(ctx))
+	assert.Equal(t, 0, restarted.ScheduledCount())
+
+	state, err := s.GetFlowState(ctx, "flow-1")
+	require.NoError(t, err)
+	assert.Equal(t, 0, state.ScheduledCount)
+	assert.True(t, state.IsComplete)
+}
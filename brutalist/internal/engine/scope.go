@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/roach88/nysm/internal/ir"
+	"github.com/roach88/nysm/internal/queryir"
 )
 
 // ScopeMode defines how sync rules match records across flows.
@@ -60,6 +61,30 @@ func NormalizeScope(scope ir.ScopeSpec) ir.ScopeSpec {
 	return scope
 }
 
+// scopePredicate returns the filter that restricts a where-clause query to
+// the records visible under scope (FR-3.3):
+//   - flow: flow_token == flowToken
+//   - keyed: <key> == the key's value in whenBindings
+//   - global: nil (no filter)
+//
+// The scope must already be normalized and validated.
+func scopePredicate(scope ir.ScopeSpec, flowToken string, whenBindings ir.IRObject) (queryir.Predicate, error) {
+	switch ScopeMode(scope.Mode) {
+	case ScopeModeFlow:
+		return queryir.Equals{Field: "flow_token", Value: ir.IRString(flowToken)}, nil
+	case ScopeModeKeyed:
+		keyValue, err := extractKeyValue(whenBindings, scope.Key)
+		if err != nil {
+			return nil, fmt.Errorf("extract key value for keyed scope: %w", err)
+		}
+		return queryir.Equals{Field: scope.Key, Value: keyValue}, nil
+	case ScopeModeGlobal:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("invalid scope mode %q: must be flow, global, or keyed", scope.Mode)
+	}
+}
+
 // extractKeyValue extracts the key field value from bindings.
 // Returns error if key not found (required for keyed scope).
 //
@@ -0,0 +1,71 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrSpecHashMismatch is matched (via errors.Is) by every
+// SpecHashMismatchError.
+var ErrSpecHashMismatch = errors.New("engine: spec hash mismatch")
+
+// SpecHashMismatchError reports that stored invocations were produced by a
+// different concept set than the engine was started with, so replaying
+// them may silently diverge.
+type SpecHashMismatchError struct {
+	Current string // The engine's spec hash (see WithSpecHash)
+	Stored  string // First differing spec hash recorded on stored invocations
+}
+
+func (e *SpecHashMismatchError) Error() string {
+	return fmt.Sprintf("%s: engine has %s, store has %s", ErrSpecHashMismatch, e.Current, e.Stored)
+}
+
+// Unwrap lets errors.Is match ErrSpecHashMismatch.
+func (e *SpecHashMismatchError) Unwrap() error {
+	return ErrSpecHashMismatch
+}
+
+// WithSpecHash sets the hash of the concept set the engine runs, recorded on
+// every invocation it generates and checked against stored invocations
+// before replay (see CheckSpecHash). Compute it with compiler.SpecHash.
+//
+// Default: "" (unversioned; the replay check is skipped).
+func WithSpecHash(hash string) EngineOption {
+	return func(e *Engine) {
+		e.specHash = hash
+	}
+}
+
+// WithAllowSpecDrift disables the spec hash check, letting Recover replay a
+// store written under different specs. Use it for deliberate migrations.
+func WithAllowSpecDrift() EngineOption {
+	return func(e *Engine) {
+		e.allowSpecDrift = true
+	}
+}
+
+// CheckSpecHash verifies that every spec hash recorded on stored invocations
+// equals the engine's own. Returns a *SpecHashMismatchError naming both
+// hashes for the first (earliest by seq) difference.
+//
+// The check passes without reading the store when the engine has no spec
+// hash or WithAllowSpecDrift is set. Invocations with an empty spec hash
+// (written before versioning) are ignored.
+func (e *Engine) CheckSpecHash(ctx context.Context) error {
+	if e.specHash == "" || e.allowSpecDrift {
+		return nil
+	}
+
+	stored, err := e.store.ReadSpecHashes(ctx)
+	if err != nil {
+		return fmt.Errorf("check spec hash: %w", err)
+	}
+	for _, hash := range stored {
+		if hash != e.specHash {
+			return &SpecHashMismatchError{Current: e.specHash, Stored: hash}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/roach88/nysm/internal/ir"
+	"github.com/roach88/nysm/internal/store"
+)
+
+// seedCrashedRun runs a checkout under specHash and then simulates a crash
+// that lost a second firing's invocation.
+func seedCrashedRun(t *testing.T, specHash string) *store.Store {
+	t.Helper()
+	ctx := context.Background()
+	s := setupTestStore(t)
+
+	e := NewWithClock(s, nil, []ir.SyncRule{reserveOnCheckoutSync()}, nil, NewClockAt(101), WithSpecHash(specHash))
+	comp := writeCheckout(t, s)
+	require.NoError(t, s.WriteCompletion(ctx, *comp))
+	require.NoError(t, e.ProcessCompletion(ctx, comp))
+
+	triggered, err := s.ReadTriggered(ctx, comp.ID)
+	require.NoError(t, err)
+	require.Len(t, triggered, 1)
+	require.Equal(t, specHash, triggered[0].SpecHash, "generated invocations record the spec hash")
+
+	simulateCrashedFiring(t, s, comp, "sync-lost", 200)
+	return s
+}
+
+func TestRecover_SpecHashMatches(t *testing.T) {
+	s := seedCrashedRun(t, "spec-v1")
+
+	e := New(s, nil, []ir.SyncRule{reserveOnCheckoutSync()}, nil, WithSpecHash("spec-v1"))
+	require.NoError(t, e.CheckSpecHash(context.Background()))
+
+	results, err := e.Recover(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+}
+
+func TestRecover_SpecHashMismatch(t *testing.T) {
+	ctx := context.Background()
+	s := seedCrashedRun(t, "spec-v1")
+
+	e := New(s, nil, []ir.SyncRule{reserveOnCheckoutSync()}, nil, WithSpecHash("spec-v2"))
+	results, err := e.Recover(ctx)
+	require.Error(t, err)
+	assert.Empty(t, results)
+	assert.True(t, errors.Is(err, ErrSpecHashMismatch))
+	assert.Contains(t, err.Error(), "engine has spec-v2, store has spec-v1")
+
+	var mismatch *SpecHashMismatchError
+	require.True(t, errors.As(err, &mismatch))
+	assert.Equal(t, "spec-v2", mismatch.Current)
+	assert.Equal(t, "spec-v1", mismatch.Stored)
+
+	// Nothing was repaired
+	orphans, err := s.FindOrphanedSyncFirings(ctx)
+	require.NoError(t, err)
+	assert.Len(t, orphans, 1)
+}
+
+func TestRecover_AllowSpecDrift(t *testing.T) {
+	s := seedCrashedRun(t, "spec-v1")
+
+	e := New(s, nil, []ir.SyncRule{reserveOnCheckoutSync()}, nil,
+		WithSpecHash("spec-v2"), WithAllowSpecDrift())
+	results, err := e.Recover(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+}
+
+func TestCheckSpecHash_Unversioned(t *testing.T) {
+	ctx := context.Background()
+
+	// An engine without a spec hash skips the check
+	s := seedCrashedRun(t, "spec-v1")
+	assert.NoError(t, New(s, nil, nil, nil).CheckSpecHash(ctx))
+
+	// Invocations without a spec hash are ignored (writeCheckout records none)
+	s = setupTestStore(t)
+	writeCheckout(t, s)
+	assert.NoError(t, New(s, nil, nil, nil, WithSpecHash("spec-v1")).CheckSpecHash(ctx))
+}
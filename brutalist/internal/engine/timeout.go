@@ -0,0 +1,156 @@
+package engine
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+)
+
+// flowTimeoutTracker detects flows that stop making progress.
+//
+// Progress is measured on the logical clock (CP-2), not wall-clock time:
+// a flow times out when more than `timeout` global seq ticks elapse since
+// its last event while it still has invocations awaiting completion. Other
+// flows advancing the clock is what makes an idle flow age. Because the
+// measure is seq-based, the same event log times out the same flows on
+// replay.
+//
+// Flows with no pending invocations are finished (or not yet started) and
+// never time out.
+//
+// A nil tracker (timeouts disabled) ignores all updates.
+//
+// Not thread-safe: owned by the single-writer Run goroutine, like quotas.
+type flowTimeoutTracker struct {
+	timeout  int64                          // Max seq ticks without progress (0 = disabled)
+	lastSeq  map[string]int64               // map[flow_token]seq of last event
+	pending  map[string]map[string]struct{} // map[flow_token]set of uncompleted invocation IDs
+	timedOut map[string]bool                // Flows already marked timed-out
+}
+
+// newFlowTimeoutTracker creates a tracker with the given timeout in seq ticks.
+func newFlowTimeoutTracker(timeout int64) *flowTimeoutTracker {
+	return &flowTimeoutTracker{
+		timeout:  timeout,
+		lastSeq:  make(map[string]int64),
+		pending:  make(map[string]map[string]struct{}),
+		timedOut: make(map[string]bool),
+	}
+}
+
+// touch records progress for a flow at seq. Timed-out flows stay timed-out.
+func (t *flowTimeoutTracker) touch(flowToken string, seq int64) {
+	if t == nil || t.timedOut[flowToken] {
+		return
+	}
+	if seq > t.lastSeq[flowToken] {
+		t.lastSeq[flowToken] = seq
+	}
+}
+
+// invoked records an invocation that is awaiting completion.
+func (t *flowTimeoutTracker) invoked(flowToken, invocationID string, seq int64) {
+	if t == nil || t.timedOut[flowToken] {
+		return
+	}
+	if t.pending[flowToken] == nil {
+		t.pending[flowToken] = make(map[string]struct{})
+	}
+	t.pending[flowToken][invocationID] = struct{}{}
+	t.touch(flowToken, seq)
+}
+
+// completed records the completion of a pending invocation.
+func (t *flowTimeoutTracker) completed(flowToken, invocationID string, seq int64) {
+	if t == nil {
+		return
+	}
+	delete(t.pending[flowToken], invocationID)
+	t.touch(flowToken, seq)
+}
+
+// expired returns the flows that have pending invocations and have made no
+// progress for more than timeout ticks as of now, in sorted order for
+// deterministic reporting. Returned flows are marked timed-out and no
+// longer tracked.
+func (t *flowTimeoutTracker) expired(now int64) []string {
+	var flows []string
+	for flowToken, invs := range t.pending {
+		if len(invs) > 0 && now-t.lastSeq[flowToken] > t.timeout {
+			flows = append(flows, flowToken)
+		}
+	}
+	slices.Sort(flows)
+
+	for _, flowToken := range flows {
+		t.timedOut[flowToken] = true
+		t.forget(flowToken)
+	}
+	return flows
+}
+
+// forget drops progress tracking for a flow without marking it timed-out.
+func (t *flowTimeoutTracker) forget(flowToken string) {
+	if t == nil {
+		return
+	}
+	delete(t.lastSeq, flowToken)
+	delete(t.pending, flowToken)
+}
+
+// WithFlowStepTimeout enables logical-clock flow timeouts.
+//
+// A flow with invocations awaiting completion is marked timed-out once more
+// than n global seq ticks elapse since its last event. The engine then
+// reports a RuntimeError with ErrCodeFlowTimeout to the listener
+// (OnFlowTimeout), cleans up the flow's quota and cycle history, and rejects
+// later completions in that flow without evaluating sync rules.
+//
+// Default: 0 (disabled). Non-positive values disable the timeout.
+func WithFlowStepTimeout(n int64) EngineOption {
+	return func(e *Engine) {
+		if n <= 0 {
+			e.timeouts = nil
+			return
+		}
+		e.timeouts = newFlowTimeoutTracker(n)
+	}
+}
+
+// IsFlowTimeout reports whether a flow has been marked timed-out.
+// Always false when WithFlowStepTimeout is not configured.
+func (e *Engine) IsFlowTimeout(flowToken string) bool {
+	return e.timeouts != nil && e.timeouts.timedOut[flowToken]
+}
+
+// expireIdleFlows times out flows that have been idle for longer than the
+// configured step timeout. Called after each processed event with the seq
+// of that event; "now" is the later of that seq and the engine clock.
+// CRITICAL: Called only from Run() goroutine - single-writer guarantee.
+func (e *Engine) expireIdleFlows(eventSeq int64) {
+	if e.timeouts == nil {
+		return
+	}
+
+	now := max(e.clock.Current(), eventSeq)
+	for _, flowToken := range e.timeouts.expired(now) {
+		err := NewFlowTimeoutError(flowToken, e.timeouts.timeout, now)
+		slog.Warn("flow timed out",
+			"flow_token", flowToken,
+			"timeout", e.timeouts.timeout,
+			"seq", now,
+			"event", "flow_timeout",
+		)
+		e.CleanupFlow(flowToken)
+		e.listener.OnFlowTimeout(err)
+	}
+}
+
+// checkFlowTimeout returns the flow's timeout error if it has timed out.
+func (e *Engine) checkFlowTimeout(flowToken string) error {
+	if !e.IsFlowTimeout(flowToken) {
+		return nil
+	}
+	return fmt.Errorf("flow already timed out: %w",
+		NewFlowTimeoutError(flowToken, e.timeouts.timeout, e.clock.Current()))
+}
@@ -0,0 +1,113 @@
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+// invokeAt processes an invocation stamped with the engine clock.
+func invokeAt(t *testing.T, e *Engine, id, flowToken string) *ir.Invocation {
+	t.Helper()
+	inv := &ir.Invocation{
+		ID:              id,
+		FlowToken:       flowToken,
+		ActionURI:       "Payment.charge",
+		Args:            ir.IRObject{},
+		Seq:             e.Clock().Next(),
+		SecurityContext: testSecurityContext,
+	}
+	require.NoError(t, e.processInvocation(context.Background(), inv))
+	return inv
+}
+
+// completeAt processes a Success completion stamped with the engine clock.
+func completeAt(t *testing.T, e *Engine, inv *ir.Invocation) error {
+	t.Helper()
+	return e.ProcessCompletion(context.Background(), &ir.Completion{
+		ID:              "comp-" + inv.ID,
+		InvocationID:    inv.ID,
+		OutputCase:      "Success",
+		Result:          ir.IRObject{},
+		Seq:             e.Clock().Next(),
+		SecurityContext: testSecurityContext,
+	})
+}
+
+func TestFlowTimeout_IdleFlowCrossesThreshold(t *testing.T) {
+	listener := &recordingListener{}
+	e := New(setupTestStore(t), nil, nil, newStubFlowGen(),
+		WithFlowStepTimeout(4), WithListener(listener))
+
+	// flow-idle invokes at seq 1 and never completes
+	idle := invokeAt(t, e, "inv-idle", "flow-idle")
+	require.Equal(t, int64(1), idle.Seq)
+
+	// flow-busy advances the clock: seq 2..5 keeps flow-idle within the timeout
+	busy1 := invokeAt(t, e, "inv-busy-1", "flow-busy")
+	require.NoError(t, completeAt(t, e, busy1))
+	busy2 := invokeAt(t, e, "inv-busy-2", "flow-busy")
+	require.NoError(t, completeAt(t, e, busy2))
+	assert.False(t, e.IsFlowTimeout("flow-idle"), "4 ticks idle is within the timeout")
+
+	// seq 6: 5 ticks since flow-idle's last event
+	invokeAt(t, e, "inv-busy-3", "flow-busy")
+	assert.True(t, e.IsFlowTimeout("flow-idle"))
+	assert.False(t, e.IsFlowTimeout("flow-busy"), "busy flow is making progress")
+
+	var timeouts []string
+	for _, ev := range listener.events {
+		if ev == "flow_timeout flow-idle" || ev == "flow_timeout flow-busy" {
+			timeouts = append(timeouts, ev)
+		}
+	}
+	assert.Equal(t, []string{"flow_timeout flow-idle"}, timeouts, "timeout is reported once")
+
+	// A late completion is rejected without evaluating syncs
+	err := completeAt(t, e, idle)
+	require.Error(t, err)
+	assert.True(t, IsFlowTimeoutError(err))
+	assert.Equal(t, ErrCodeFlowTimeout, ErrorCode(err))
+}
+
+func TestFlowTimeout_CompletedFlowNeverTimesOut(t *testing.T) {
+	e := New(setupTestStore(t), nil, nil, newStubFlowGen(), WithFlowStepTimeout(2))
+
+	done := invokeAt(t, e, "inv-done", "flow-done")
+	require.NoError(t, completeAt(t, e, done))
+
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		invokeAt(t, e, "inv-other-"+id, "flow-other")
+	}
+
+	assert.False(t, e.IsFlowTimeout("flow-done"), "no pending invocations")
+	assert.False(t, e.IsFlowTimeout("flow-other"), "each invocation is progress")
+}
+
+func TestFlowTimeout_DisabledByDefault(t *testing.T) {
+	e := New(setupTestStore(t), nil, nil, newStubFlowGen())
+
+	idle := invokeAt(t, e, "inv-idle", "flow-idle")
+	for range 100 {
+		e.Clock().Next()
+	}
+	invokeAt(t, e, "inv-busy", "flow-busy")
+
+	assert.False(t, e.IsFlowTimeout("flow-idle"))
+	require.NoError(t, completeAt(t, e, idle))
+}
+
+func TestNewFlowTimeoutError(t *testing.T) {
+	err := NewFlowTimeoutError("flow-1", 10, 42)
+
+	assert.Equal(t, ErrCodeFlowTimeout, err.Code)
+	assert.Equal(t, "flow-1", err.FlowToken)
+	assert.Equal(t, "10", err.Details["timeout"])
+	assert.Equal(t, "42", err.Details["seq"])
+	assert.True(t, IsFlowTimeoutError(err))
+	assert.False(t, IsCycleError(err))
+}
@@ -0,0 +1,60 @@
+package engine
+
+import (
+	"log/slog"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+// InvocationValidator enforces business invariants on a generated invocation
+// (e.g. quantity > 0) before it is persisted. Returning an error vetoes the
+// firing of that binding.
+//
+// CRITICAL: Validators MUST be pure - their result may depend only on the
+// invocation, never on wall clocks, randomness, or external state. Replay
+// regenerates the same invocations and must reach the same verdicts to
+// produce identical results (CP-2, FR-4.3).
+type InvocationValidator func(ir.Invocation) error
+
+// WithInvocationValidator registers a validator for invocations generated by
+// sync rules.
+//
+// The validator runs after the invocation is generated and before the
+// atomic firing write. A vetoed binding writes nothing (no firing,
+// invocation, or provenance edge); the rejection is logged, counted in
+// Metrics.InvocationsRejected, and reported to EventListener.
+// OnInvocationRejected, and the remaining bindings and syncs are still
+// evaluated. Bindings that already fired (CP-1) are not re-validated.
+// DryRun applies the same veto, marking the binding PlannedFiring.Vetoed.
+//
+// Default: none. Passing nil disables validation.
+func WithInvocationValidator(v InvocationValidator) EngineOption {
+	return func(e *Engine) {
+		e.invocationValidator = v
+	}
+}
+
+// rejectInvocation runs the invocation validator, if any, on an invocation
+// generated by sync for bindingHash. It reports whether the validator vetoed
+// it, after logging and reporting the rejection.
+func (e *Engine) rejectInvocation(sync ir.SyncRule, bindingHash string, inv ir.Invocation) bool {
+	if e.invocationValidator == nil {
+		return false
+	}
+	err := e.invocationValidator(inv)
+	if err == nil {
+		return false
+	}
+
+	rejectErr := NewInvocationRejectedError(inv, sync.ID, bindingHash, err)
+	slog.Warn("generated invocation rejected by validator",
+		"sync_id", sync.ID,
+		"flow_token", inv.FlowToken,
+		"action_uri", inv.ActionURI,
+		"binding_hash", bindingHash,
+		"error", err,
+	)
+	e.metrics.invocationsRejected.Add(1)
+	e.listener.OnInvocationRejected(rejectErr)
+	return true
+}
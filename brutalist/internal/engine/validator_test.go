@@ -0,0 +1,150 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+// rejectionListener records invocation rejections.
+type rejectionListener struct {
+	NopListener
+	rejected []*RuntimeError
+}
+
+func (l *rejectionListener) OnInvocationRejected(err *RuntimeError) {
+	l.rejected = append(l.rejected, err)
+}
+
+// rejectItem1 vetoes reservations of item-1.
+func rejectItem1(inv ir.Invocation) error {
+	if inv.Args["item"] == ir.IRString("item-1") {
+		return errors.New("item-1 is discontinued")
+	}
+	return nil
+}
+
+// processFanOutCheckout completes a Cart.checkout for cart-1 in flow-1,
+// firing reserve-each-item once per seeded CartItems row.
+func processFanOutCheckout(t *testing.T, e *Engine) *ir.Completion {
+	t.Helper()
+	ctx := context.Background()
+
+	args := ir.IRObject{}
+	invID := ir.MustInvocationID("flow-1", "Cart.checkout", args, 1)
+	require.NoError(t, e.store.WriteInvocation(ctx, ir.Invocation{
+		ID:              invID,
+		FlowToken:       "flow-1",
+		ActionURI:       "Cart.checkout",
+		Args:            args,
+		Seq:             1,
+		EngineVersion:   ir.EngineVersion,
+		IRVersion:       ir.IRVersion,
+		SecurityContext: testSecurityContext,
+	}))
+	result := ir.IRObject{"cart_id": ir.IRString("cart-1")}
+	comp := &ir.Completion{
+		ID:              ir.MustCompletionID(invID, "Success", result, 2),
+		InvocationID:    invID,
+		OutputCase:      "Success",
+		Result:          result,
+		Seq:             2,
+		SecurityContext: testSecurityContext,
+	}
+	require.NoError(t, e.ProcessCompletion(ctx, comp))
+	return comp
+}
+
+func fanOutReserveSync() ir.SyncRule {
+	sync := fanOutSync
+	sync.When = ir.WhenClause{
+		ActionRef: "Cart.checkout",
+		EventType: "completed",
+		Bindings:  map[string]string{"cartId": "cart_id"},
+	}
+	sync.Then = ir.ThenClause{
+		ActionRef: "Inventory.reserve",
+		Args:      map[string]string{"item": "${bound.itemId}"},
+	}
+	return sync
+}
+
+func TestInvocationValidator_VetoesOneBinding(t *testing.T) {
+	ctx := context.Background()
+	s := setupTestStore(t)
+	listener := &rejectionListener{}
+	e := NewWithClock(s, nil, []ir.SyncRule{fanOutReserveSync()}, nil, NewClockAt(2),
+		WithInvocationValidator(rejectItem1), WithListener(listener))
+	seedManyCartItems(t, e, 3)
+
+	comp := processFanOutCheckout(t, e)
+
+	// The vetoed binding wrote nothing; the others fired
+	pending, err := s.GetPendingInvocations(ctx, "flow-1")
+	require.NoError(t, err)
+	var items []ir.IRValue
+	for _, inv := range pending {
+		items = append(items, inv.Args["item"])
+	}
+	assert.Equal(t, []ir.IRValue{ir.IRString("item-0"), ir.IRString("item-2")}, items)
+
+	firings, err := s.ReadSyncFiringsForCompletion(ctx, comp.ID)
+	require.NoError(t, err)
+	assert.Len(t, firings, 2)
+
+	// The rejection is reported and counted
+	require.Len(t, listener.rejected, 1)
+	rejected := listener.rejected[0]
+	assert.True(t, IsInvocationRejectedError(rejected))
+	assert.Equal(t, "reserve-each-item", rejected.SyncID)
+	assert.Equal(t, "flow-1", rejected.FlowToken)
+	assert.Equal(t, "Inventory.reserve", rejected.Details["action_uri"])
+	assert.Equal(t, "item-1 is discontinued", rejected.Details["reason"])
+	assert.Contains(t, rejected.Error(), "INVOCATION_REJECTED: invocation of Inventory.reserve rejected by validator")
+	assert.Equal(t, int64(1), e.Metrics().InvocationsRejected())
+	assert.Equal(t, int64(2), e.Metrics().SyncsFired())
+}
+
+func TestInvocationValidator_AllowsAll(t *testing.T) {
+	ctx := context.Background()
+	s := setupTestStore(t)
+	calls := 0
+	e := NewWithClock(s, nil, []ir.SyncRule{fanOutReserveSync()}, nil, NewClockAt(2),
+		WithInvocationValidator(func(inv ir.Invocation) error {
+			calls++
+			return nil
+		}))
+	seedManyCartItems(t, e, 3)
+
+	processFanOutCheckout(t, e)
+
+	pending, err := s.GetPendingInvocations(ctx, "flow-1")
+	require.NoError(t, err)
+	assert.Len(t, pending, 3)
+	assert.Equal(t, 3, calls)
+	assert.Zero(t, e.Metrics().InvocationsRejected())
+}
+
+func TestInvocationValidator_ExecuteThen(t *testing.T) {
+	ctx := context.Background()
+	s := setupTestStore(t)
+	e := New(s, nil, nil, nil, WithInvocationValidator(rejectItem1))
+
+	// executeThen resolves the bare "bound." template syntax
+	sync := fanOutReserveSync()
+	sync.Then.Args = map[string]string{"item": "bound.itemId"}
+	comp := processFanOutCheckout(t, e) // No syncs registered, so nothing fires yet
+	bindings := []ir.IRObject{
+		{"itemId": ir.IRString("item-0")},
+		{"itemId": ir.IRString("item-1")},
+	}
+	require.NoError(t, e.executeThen(ctx, sync.Then, bindings, "flow-1", *comp, sync))
+
+	require.Equal(t, 1, e.QueueLen())
+	assert.Equal(t, int64(1), e.Metrics().InvocationsRejected())
+}
@@ -0,0 +1,221 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/roach88/nysm/internal/ir"
+	"github.com/roach88/nysm/internal/store"
+)
+
+// DivergenceKind classifies a replay divergence.
+type DivergenceKind string
+
+const (
+	// DivergenceMissingFiring: replay produces a sync firing (sync, binding
+	// hash) that the store does not record for the completion.
+	DivergenceMissingFiring DivergenceKind = "missing_firing"
+
+	// DivergenceUnexpectedFiring: the store records a firing for the
+	// completion that replay does not produce.
+	DivergenceUnexpectedFiring DivergenceKind = "unexpected_firing"
+
+	// DivergenceMissingInvocation: the firing exists but has no provenance
+	// edge to any invocation.
+	DivergenceMissingInvocation DivergenceKind = "missing_invocation"
+
+	// DivergenceInvocationMismatch: the firing's invocation has a different
+	// content-addressed ID than the one replay generates.
+	DivergenceInvocationMismatch DivergenceKind = "invocation_mismatch"
+)
+
+// ReplayDivergence describes the first point where replay disagrees with
+// the stored log.
+type ReplayDivergence struct {
+	Kind         DivergenceKind
+	CompletionID string
+	SyncID       string
+	BindingHash  string
+
+	// Set for invocation divergences.
+	ExpectedInvocationID string
+	ActualInvocationID   string // Empty for DivergenceMissingInvocation
+
+	// ExpectedArgs are the args replay generates; ActualArgs are the stored
+	// invocation's args (nil when there is no stored invocation).
+	ExpectedArgs ir.IRObject
+	ActualArgs   ir.IRObject
+}
+
+func (d ReplayDivergence) String() string {
+	switch d.Kind {
+	case DivergenceInvocationMismatch:
+		return fmt.Sprintf("%s: completion %s sync %s: expected invocation %s, stored %s",
+			d.Kind, d.CompletionID, d.SyncID, d.ExpectedInvocationID, d.ActualInvocationID)
+	case DivergenceMissingInvocation:
+		return fmt.Sprintf("%s: completion %s sync %s: expected invocation %s, firing has none",
+			d.Kind, d.CompletionID, d.SyncID, d.ExpectedInvocationID)
+	default:
+		return fmt.Sprintf("%s: completion %s sync %s binding %s",
+			d.Kind, d.CompletionID, d.SyncID, d.BindingHash)
+	}
+}
+
+// ReplayReport is the result of VerifyReplay.
+type ReplayReport struct {
+	Completions int // Completions replayed
+	Firings     int // Sync firings compared
+
+	// Divergence is the first divergence found (nil = consistent).
+	Divergence *ReplayDivergence
+}
+
+// Consistent reports whether replay reproduced the stored log.
+func (r ReplayReport) Consistent() bool {
+	return r.Divergence == nil
+}
+
+// VerifyReplay replays every stored completion through a fresh engine and
+// checks that the sync firings and invocations it generates already exist in
+// the store with identical content-addressed IDs. A divergence indicates
+// non-determinism in the run that wrote the log (e.g. map iteration order
+// leaking into args) or sync rules that changed since.
+//
+// The fresh engine is built with NewWithClock at seq 0 and evaluates each
+// completion with DryRun, in stored order (seq ASC, id ASC per CP-4), so the
+// store is only read. Each generated invocation is given its original seq
+// (firing seq - 1, as in fireSyncRule and Recover) before its ID is compared.
+// Firings the invocation validator vetoes are expected to be absent and are
+// not compared, so pass the same WithInvocationValidator as the original run.
+//
+// Where-clauses query current concept state, so rules with where-clauses are
+// only verified exactly if that state is unchanged since the original run.
+//
+// Verification stops at the first divergence. Store failures and runtime
+// errors (cycles, binding explosions) are returned as errors.
+func VerifyReplay(ctx context.Context, s *store.Store, specs []ir.ConceptSpec, syncs []ir.SyncRule, opts ...EngineOption) (ReplayReport, error) {
+	var report ReplayReport
+	e := NewWithClock(s, specs, syncs, nil, NewClockAt(0), opts...)
+
+	completions, err := s.ReadAllCompletions(ctx)
+	if err != nil {
+		return report, fmt.Errorf("verify replay: %w", err)
+	}
+
+	for _, comp := range completions {
+		report.Completions++
+
+		divergence, firings, err := e.verifyCompletion(ctx, comp)
+		report.Firings += firings
+		if err != nil {
+			return report, fmt.Errorf("verify replay: completion %s: %w", comp.ID, err)
+		}
+		if divergence != nil {
+			report.Divergence = divergence
+			return report, nil
+		}
+	}
+
+	return report, nil
+}
+
+// verifyCompletion compares the firings replay plans for comp with the
+// stored ones. Returns the first divergence and the number of planned
+// firings compared.
+func (e *Engine) verifyCompletion(ctx context.Context, comp ir.Completion) (*ReplayDivergence, int, error) {
+	planned, err := e.DryRun(ctx, comp)
+	if err != nil {
+		return nil, 0, err
+	}
+	trigger, err := e.store.ReadInvocation(ctx, comp.InvocationID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("read invocation %s: %w", comp.InvocationID, err)
+	}
+	stored, err := e.store.ReadSyncFiringsForCompletion(ctx, comp.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	type firingKey struct{ syncID, bindingHash string }
+	byKey := make(map[firingKey]ir.SyncFiring, len(stored))
+	for _, f := range stored {
+		byKey[firingKey{f.SyncID, f.BindingHash}] = f
+	}
+
+	compared := 0
+	for _, p := range planned {
+		if p.Vetoed {
+			continue
+		}
+		compared++
+		key := firingKey{p.SyncID, p.BindingHash}
+		firing, ok := byKey[key]
+		if !ok {
+			return &ReplayDivergence{
+				Kind:         DivergenceMissingFiring,
+				CompletionID: comp.ID,
+				SyncID:       p.SyncID,
+				BindingHash:  p.BindingHash,
+				ExpectedArgs: p.Args,
+			}, compared, nil
+		}
+		delete(byKey, key)
+
+		divergence, err := e.verifyFiringInvocation(ctx, comp, trigger.FlowToken, p, firing)
+		if err != nil || divergence != nil {
+			return divergence, compared, err
+		}
+	}
+
+	// Stored firings replay did not produce, in stored order
+	for _, f := range stored {
+		if _, left := byKey[firingKey{f.SyncID, f.BindingHash}]; left {
+			return &ReplayDivergence{
+				Kind:         DivergenceUnexpectedFiring,
+				CompletionID: comp.ID,
+				SyncID:       f.SyncID,
+				BindingHash:  f.BindingHash,
+			}, compared, nil
+		}
+	}
+
+	return nil, compared, nil
+}
+
+// verifyFiringInvocation checks that the stored firing links the invocation
+// replay generates for it.
+func (e *Engine) verifyFiringInvocation(ctx context.Context, comp ir.Completion, flowToken string, p PlannedFiring, firing ir.SyncFiring) (*ReplayDivergence, error) {
+	expectedID, err := ir.InvocationID(flowToken, string(p.ActionURI), p.Args, firing.Seq-1)
+	if err != nil {
+		return nil, fmt.Errorf("compute invocation ID: %w", err)
+	}
+
+	edges, err := e.store.ReadProvenanceEdgesForFiring(ctx, firing.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, edge := range edges {
+		if edge.InvocationID == expectedID {
+			return nil, nil
+		}
+	}
+
+	divergence := &ReplayDivergence{
+		Kind:                 DivergenceMissingInvocation,
+		CompletionID:         comp.ID,
+		SyncID:               p.SyncID,
+		BindingHash:          p.BindingHash,
+		ExpectedInvocationID: expectedID,
+		ExpectedArgs:         p.Args,
+	}
+	if len(edges) > 0 {
+		actual, err := e.store.ReadInvocation(ctx, edges[0].InvocationID)
+		if err != nil {
+			return nil, fmt.Errorf("read invocation %s: %w", edges[0].InvocationID, err)
+		}
+		divergence.Kind = DivergenceInvocationMismatch
+		divergence.ActualInvocationID = actual.ID
+		divergence.ActualArgs = actual.Args
+	}
+	return divergence, nil
+}
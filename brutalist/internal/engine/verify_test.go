@@ -0,0 +1,165 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/roach88/nysm/internal/ir"
+	"github.com/roach88/nysm/internal/store"
+)
+
+// scheduleOnReserveSync chains Shipping.schedule after Inventory.reserve.
+func scheduleOnReserveSync() ir.SyncRule {
+	return ir.SyncRule{
+		ID: "sync-schedule",
+		When: ir.WhenClause{
+			ActionRef: "Inventory.reserve",
+			EventType: "completed",
+			Bindings:  map[string]string{"reservation_id": "reservation_id"},
+		},
+		Then: ir.ThenClause{
+			ActionRef: "Shipping.schedule",
+			Args: map[string]string{
+				"reservation_id": "${bound.reservation_id}",
+				"priority":       "int:1",
+			},
+		},
+	}
+}
+
+// runCheckoutFlow runs checkout → reserve → schedule through a live engine.
+func runCheckoutFlow(t *testing.T, s *store.Store, syncs []ir.SyncRule, opts ...EngineOption) {
+	t.Helper()
+	ctx := context.Background()
+	// Continue the clock after the checkout completion (seq 101)
+	e := NewWithClock(s, nil, syncs, nil, NewClockAt(101), opts...)
+
+	comp := writeCheckout(t, s)
+	require.NoError(t, s.WriteCompletion(ctx, *comp))
+	require.NoError(t, e.ProcessCompletion(ctx, comp))
+
+	triggered, err := s.ReadTriggered(ctx, comp.ID)
+	require.NoError(t, err)
+	require.Len(t, triggered, 1)
+
+	reserved := ir.NewCompletion(triggered[0].ID, "Success").
+		WithResult(ir.IRObject{"reservation_id": ir.IRString("r-1")}).
+		WithSeq(e.Clock().Next()).
+		WithSecurityContext(testSecurityContext).
+		MustBuild()
+	require.NoError(t, s.WriteCompletion(ctx, reserved))
+	require.NoError(t, e.ProcessCompletion(ctx, &reserved))
+}
+
+func TestVerifyReplay_Consistent(t *testing.T) {
+	ctx := context.Background()
+	s := setupTestStore(t)
+	syncs := []ir.SyncRule{reserveOnCheckoutSync(), scheduleOnReserveSync()}
+	runCheckoutFlow(t, s, syncs)
+
+	report, err := VerifyReplay(ctx, s, nil, syncs)
+	require.NoError(t, err)
+
+	assert.True(t, report.Consistent(), "unexpected divergence: %v", report.Divergence)
+	assert.Equal(t, 2, report.Completions)
+	assert.Equal(t, 2, report.Firings)
+}
+
+func TestVerifyReplay_WithInvocationValidator(t *testing.T) {
+	ctx := context.Background()
+	s := setupTestStore(t)
+	syncs := []ir.SyncRule{reserveOnCheckoutSync(), scheduleOnReserveSync()}
+	noShipping := WithInvocationValidator(func(inv ir.Invocation) error {
+		if inv.ActionURI == "Shipping.schedule" {
+			return errors.New("shipping is paused")
+		}
+		return nil
+	})
+	runCheckoutFlow(t, s, syncs, noShipping)
+
+	// Replaying with the same validator reproduces the log
+	report, err := VerifyReplay(ctx, s, nil, syncs, noShipping)
+	require.NoError(t, err)
+	assert.True(t, report.Consistent(), "unexpected divergence: %v", report.Divergence)
+	assert.Equal(t, 2, report.Completions)
+	assert.Equal(t, 1, report.Firings)
+
+	// Without it, replay expects the vetoed firing
+	report, err = VerifyReplay(ctx, s, nil, syncs)
+	require.NoError(t, err)
+	require.False(t, report.Consistent())
+	assert.Equal(t, DivergenceMissingFiring, report.Divergence.Kind)
+	assert.Equal(t, "sync-schedule", report.Divergence.SyncID)
+}
+
+func TestVerifyReplay_NonDeterministicArgs(t *testing.T) {
+	ctx := context.Background()
+	s := setupTestStore(t)
+
+	comp := writeCheckout(t, s)
+	require.NoError(t, s.WriteCompletion(ctx, *comp))
+
+	// Simulate a live run whose arg resolution depended on iteration order:
+	// it fired the right binding but generated the invocation for a
+	// different item than the binding names.
+	bindings := ir.IRObject{"item_id": ir.IRString("widget")}
+	buggy := ir.NewInvocation("flow-1", "Inventory.reserve").
+		WithArgs(ir.IRObject{"item_id": ir.IRString("gadget")}).
+		WithSeq(102).
+		WithSecurityContext(testSecurityContext).
+		MustBuild()
+	_, inserted, err := s.WriteSyncFiringAtomic(ctx, ir.SyncFiring{
+		CompletionID: comp.ID,
+		SyncID:       "sync-reserve",
+		BindingHash:  ir.MustBindingHash(bindings),
+		Seq:          103,
+	}, buggy)
+	require.NoError(t, err)
+	require.True(t, inserted)
+
+	report, err := VerifyReplay(ctx, s, nil, []ir.SyncRule{reserveOnCheckoutSync()})
+	require.NoError(t, err)
+
+	require.False(t, report.Consistent())
+	d := report.Divergence
+	assert.Equal(t, DivergenceInvocationMismatch, d.Kind)
+	assert.Equal(t, comp.ID, d.CompletionID)
+	assert.Equal(t, "sync-reserve", d.SyncID)
+	assert.Equal(t, ir.MustInvocationID("flow-1", "Inventory.reserve", bindings, 102), d.ExpectedInvocationID)
+	assert.Equal(t, buggy.ID, d.ActualInvocationID)
+	assert.Equal(t, bindings, d.ExpectedArgs)
+	assert.Equal(t, buggy.Args, d.ActualArgs)
+}
+
+func TestVerifyReplay_FiringSetDiverges(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("replay fires a sync the log lacks", func(t *testing.T) {
+		s := setupTestStore(t)
+		runCheckoutFlow(t, s, []ir.SyncRule{reserveOnCheckoutSync()})
+
+		report, err := VerifyReplay(ctx, s, nil, []ir.SyncRule{reserveOnCheckoutSync(), scheduleOnReserveSync()})
+		require.NoError(t, err)
+
+		require.False(t, report.Consistent())
+		assert.Equal(t, DivergenceMissingFiring, report.Divergence.Kind)
+		assert.Equal(t, "sync-schedule", report.Divergence.SyncID)
+		assert.Equal(t, 2, report.Completions)
+	})
+
+	t.Run("log records a firing replay does not produce", func(t *testing.T) {
+		s := setupTestStore(t)
+		runCheckoutFlow(t, s, []ir.SyncRule{reserveOnCheckoutSync(), scheduleOnReserveSync()})
+
+		report, err := VerifyReplay(ctx, s, nil, []ir.SyncRule{reserveOnCheckoutSync()})
+		require.NoError(t, err)
+
+		require.False(t, report.Consistent())
+		assert.Equal(t, DivergenceUnexpectedFiring, report.Divergence.Kind)
+		assert.Equal(t, "sync-schedule", report.Divergence.SyncID)
+	})
+}
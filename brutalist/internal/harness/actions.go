@@ -0,0 +1,71 @@
+package harness
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/roach88/nysm/internal/ir"
+	"github.com/roach88/nysm/internal/store"
+)
+
+// ActionHandler executes an action for the harness, typically by mutating
+// state tables so final_state and state_count assertions observe real
+// effects. The run's store is available through StoreFromContext(ctx).
+//
+// A nil error means the action succeeded; the step's completion is then
+// written as usual. A setup handler error aborts the run; a flow handler
+// error completes the step with output case "Error".
+type ActionHandler func(ctx context.Context, args ir.IRObject) error
+
+// handlerErrorCase is the output case of a flow step whose handler failed.
+// Its result is {"error": <message>}.
+const handlerErrorCase = "Error"
+
+// ActionRegistry maps action URIs to handlers. Steps whose action has no
+// handler keep the default behavior (a manufactured completion).
+//
+// A registry may be shared across concurrent runs (see RunAllOptions) as long
+// as its handlers only touch state reached through StoreFromContext.
+type ActionRegistry struct {
+	handlers map[ir.ActionRef]ActionHandler
+}
+
+// NewActionRegistry creates an empty action registry.
+func NewActionRegistry() *ActionRegistry {
+	return &ActionRegistry{handlers: make(map[ir.ActionRef]ActionHandler)}
+}
+
+// Register adds the handler for action. Returns an error if the action is
+// empty, the handler is nil, or the action already has a handler.
+func (r *ActionRegistry) Register(action ir.ActionRef, handler ActionHandler) error {
+	if action == "" {
+		return fmt.Errorf("action is required")
+	}
+	if handler == nil {
+		return fmt.Errorf("handler for %s is nil", action)
+	}
+	if _, exists := r.handlers[action]; exists {
+		return fmt.Errorf("handler for %s is already registered", action)
+	}
+	r.handlers[action] = handler
+	return nil
+}
+
+// Lookup returns the handler for action, if any. Safe on a nil registry.
+func (r *ActionRegistry) Lookup(action ir.ActionRef) (ActionHandler, bool) {
+	if r == nil {
+		return nil, false
+	}
+	handler, ok := r.handlers[action]
+	return handler, ok
+}
+
+// storeKey is the context key under which a run exposes its store.
+type storeKey struct{}
+
+// StoreFromContext returns the store of the scenario run that called the
+// handler, or nil outside a run. Each run has its own in-memory store.
+func StoreFromContext(ctx context.Context) *store.Store {
+	st, _ := ctx.Value(storeKey{}).(*store.Store)
+	return st
+}
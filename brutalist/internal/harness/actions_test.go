@@ -0,0 +1,172 @@
+package harness
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+// inventoryActions registers Inventory.setStock, which upserts a stock row,
+// and Inventory.reserve, which decrements it and fails on insufficient stock.
+func inventoryActions(t *testing.T) *ActionRegistry {
+	t.Helper()
+	actions := NewActionRegistry()
+
+	require.NoError(t, actions.Register("Inventory.setStock", func(ctx context.Context, args ir.IRObject) error {
+		db := StoreFromContext(ctx).DB()
+		if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS inventory (item_id TEXT PRIMARY KEY, quantity INTEGER)`); err != nil {
+			return err
+		}
+		_, err := db.ExecContext(ctx,
+			`INSERT INTO inventory (item_id, quantity) VALUES (?, ?)
+			 ON CONFLICT(item_id) DO UPDATE SET quantity = excluded.quantity`,
+			string(args["item_id"].(ir.IRString)), int64(args["quantity"].(ir.IRInt)))
+		return err
+	}))
+
+	require.NoError(t, actions.Register("Inventory.reserve", func(ctx context.Context, args ir.IRObject) error {
+		res, err := StoreFromContext(ctx).DB().ExecContext(ctx,
+			`UPDATE inventory SET quantity = quantity - ? WHERE item_id = ? AND quantity >= ?`,
+			int64(args["quantity"].(ir.IRInt)), string(args["item_id"].(ir.IRString)), int64(args["quantity"].(ir.IRInt)))
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return fmt.Errorf("insufficient stock for %s", args["item_id"])
+		}
+		return nil
+	}))
+
+	return actions
+}
+
+func inventoryScenario(reserve int, expect *ExpectClause, stockAfter int64) *Scenario {
+	return &Scenario{
+		Name:        "inventory_handlers",
+		Description: "Setup and flow actions mutate state through handlers",
+		Specs:       []string{},
+		FlowToken:   "test-flow-actions",
+		Setup: []ActionStep{
+			{Action: "Inventory.setStock", Args: map[string]interface{}{"item_id": "widget", "quantity": 10}},
+		},
+		Flow: []FlowStep{
+			{
+				Invoke: "Inventory.reserve",
+				Args:   map[string]interface{}{"item_id": "widget", "quantity": reserve},
+				Expect: expect,
+			},
+		},
+		Assertions: []Assertion{
+			{
+				Type:   AssertFinalState,
+				Table:  "inventory",
+				Where:  map[string]interface{}{"item_id": "widget"},
+				Expect: map[string]interface{}{"quantity": stockAfter},
+			},
+		},
+	}
+}
+
+func TestRunWithActions_FinalStateReflectsHandlers(t *testing.T) {
+	scenario := inventoryScenario(3, nil, 7)
+
+	result, err := RunWithActions(scenario, inventoryActions(t))
+	require.NoError(t, err)
+	assert.True(t, result.Pass, "errors: %v", result.Errors)
+
+	require.Len(t, result.Trace, 4)
+	assert.Equal(t, "Inventory.setStock", result.Trace[0].ActionURI)
+	assert.Equal(t, "Inventory.reserve", result.Trace[2].ActionURI)
+	assert.Equal(t, "Success", result.Trace[3].OutputCase)
+}
+
+func TestRunWithActions_WrongExpectedStateFails(t *testing.T) {
+	scenario := inventoryScenario(3, nil, 10)
+
+	result, err := RunWithActions(scenario, inventoryActions(t))
+	require.NoError(t, err)
+	assert.False(t, result.Pass)
+	require.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0], "final_state")
+}
+
+func TestRunWithActions_FlowHandlerError(t *testing.T) {
+	scenario := inventoryScenario(50, nil, 10)
+
+	result, err := RunWithActions(scenario, inventoryActions(t))
+	require.NoError(t, err)
+	assert.False(t, result.Pass)
+	require.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0], "flow step 0 (Inventory.reserve): action handler failed: insufficient stock")
+
+	completion := result.Trace[3]
+	assert.Equal(t, "Error", completion.OutputCase)
+	assert.Equal(t, map[string]interface{}{"error": "insufficient stock for widget"}, completion.Result)
+}
+
+func TestRunWithActions_ExpectedHandlerError(t *testing.T) {
+	scenario := inventoryScenario(50, &ExpectClause{Case: "Error"}, 10)
+
+	result, err := RunWithActions(scenario, inventoryActions(t))
+	require.NoError(t, err)
+	assert.True(t, result.Pass, "errors: %v", result.Errors)
+}
+
+func TestRunWithActions_SetupHandlerErrorAborts(t *testing.T) {
+	actions := NewActionRegistry()
+	require.NoError(t, actions.Register("Inventory.setStock", func(ctx context.Context, args ir.IRObject) error {
+		return fmt.Errorf("warehouse offline")
+	}))
+
+	_, err := RunWithActions(inventoryScenario(3, nil, 7), actions)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "setup step 0 (Inventory.setStock): action handler: warehouse offline")
+}
+
+func TestRunWithActions_UnhandledActionsManufactured(t *testing.T) {
+	scenario := &Scenario{
+		Name:        "unhandled",
+		Description: "Actions without a handler keep the manufactured completion",
+		Specs:       []string{},
+		Flow: []FlowStep{
+			{Invoke: "Cart.addItem", Args: map[string]interface{}{}, Expect: &ExpectClause{Case: "Success"}},
+		},
+		Assertions: []Assertion{{Type: AssertTraceContains, Action: "Cart.addItem"}},
+	}
+
+	result, err := RunWithActions(scenario, NewActionRegistry())
+	require.NoError(t, err)
+	assert.True(t, result.Pass, "errors: %v", result.Errors)
+}
+
+func TestActionRegistry_Register(t *testing.T) {
+	actions := NewActionRegistry()
+	noop := func(ctx context.Context, args ir.IRObject) error { return nil }
+
+	require.NoError(t, actions.Register("Inventory.setStock", noop))
+
+	err := actions.Register("Inventory.setStock", noop)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already registered")
+
+	assert.Error(t, actions.Register("", noop))
+	assert.Error(t, actions.Register("Inventory.reserve", nil))
+
+	_, ok := actions.Lookup("Inventory.setStock")
+	assert.True(t, ok)
+	_, ok = actions.Lookup("Inventory.reserve")
+	assert.False(t, ok)
+
+	var nilRegistry *ActionRegistry
+	_, ok = nilRegistry.Lookup("Inventory.setStock")
+	assert.False(t, ok)
+}
+
+func TestStoreFromContext_OutsideRun(t *testing.T) {
+	assert.Nil(t, StoreFromContext(context.Background()))
+}
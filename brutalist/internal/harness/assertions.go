@@ -51,6 +51,10 @@ func (e *AssertionError) Error() string {
 // assertTraceContains checks if the trace contains an invocation matching
 // the specified action and args (subset match).
 func assertTraceContains(trace []TraceEvent, assertion Assertion) error {
+	if err := validateArgPatterns("trace_contains", assertion.Args); err != nil {
+		return err
+	}
+
 	for _, event := range trace {
 		if event.Type == "invocation" && event.ActionURI == assertion.Action {
 			// Check args match (subset semantics)
@@ -68,6 +72,61 @@ func assertTraceContains(trace []TraceEvent, assertion Assertion) error {
 	}
 }
 
+// assertTraceNotContains checks that no invocation in the trace matches the
+// specified action and args. Matching uses the same subset semantics as
+// trace_contains, so omitting args forbids every invocation of the action.
+func assertTraceNotContains(trace []TraceEvent, assertion Assertion) error {
+	if err := validateArgPatterns("trace_not_contains", assertion.Args); err != nil {
+		return err
+	}
+
+	var matches []string
+	for _, event := range trace {
+		if event.Type == "invocation" && event.ActionURI == assertion.Action {
+			if matchArgs(event.Args, assertion.Args) {
+				matches = append(matches, fmt.Sprintf("[seq %d] %s %v", event.Seq, event.ActionURI, event.Args))
+			}
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil
+	}
+
+	return &AssertionError{
+		Type:     "trace_not_contains",
+		Expected: fmt.Sprintf("no action %s with args %v", assertion.Action, assertion.Args),
+		Actual:   fmt.Sprintf("found %d matching event(s): %s", len(matches), strings.Join(matches, ", ")),
+		Trace:    trace,
+	}
+}
+
+// assertTraceArgsAll checks that every invocation of the specified action
+// matches args (subset semantics, as in trace_contains). Fails on the first
+// invocation that does not match, reporting its seq. An action that never
+// appears passes vacuously; combine with trace_count to require occurrences.
+func assertTraceArgsAll(trace []TraceEvent, assertion Assertion) error {
+	if err := validateArgPatterns("trace_args_all", assertion.Args); err != nil {
+		return err
+	}
+
+	for _, event := range trace {
+		if event.Type != "invocation" || event.ActionURI != assertion.Action {
+			continue
+		}
+		if !matchArgs(event.Args, assertion.Args) {
+			return &AssertionError{
+				Type:     "trace_args_all",
+				Expected: fmt.Sprintf("every action %s with args %v", assertion.Action, assertion.Args),
+				Actual:   fmt.Sprintf("[seq %d] %s %v does not match", event.Seq, event.ActionURI, event.Args),
+				Trace:    trace,
+			}
+		}
+	}
+
+	return nil
+}
+
 // assertTraceOrder checks if actions appear in the specified order.
 // Actions don't need to be consecutive (intervening actions are allowed).
 func assertTraceOrder(trace []TraceEvent, assertion Assertion) error {
@@ -115,6 +174,64 @@ func assertTraceOrder(trace []TraceEvent, assertion Assertion) error {
 	return nil
 }
 
+// assertSeqBefore checks that the invocations of assertion.EarlierAction all
+// happened strictly before those of assertion.LaterAction by logical clock:
+// the max seq of the earlier action must be below the min seq of the later
+// one. Unlike trace_order, which only compares first occurrences, any
+// interleaving fails. Both actions must be invoked at least once.
+func assertSeqBefore(trace []TraceEvent, assertion Assertion) error {
+	var earlierMax, laterMin int64
+	earlierFound, laterFound := false, false
+
+	for _, event := range trace {
+		if event.Type != "invocation" {
+			continue
+		}
+		switch event.ActionURI {
+		case assertion.EarlierAction:
+			if !earlierFound || event.Seq > earlierMax {
+				earlierMax = event.Seq
+			}
+			earlierFound = true
+		case assertion.LaterAction:
+			if !laterFound || event.Seq < laterMin {
+				laterMin = event.Seq
+			}
+			laterFound = true
+		}
+	}
+
+	expected := fmt.Sprintf("all %s seqs before all %s seqs", assertion.EarlierAction, assertion.LaterAction)
+	for _, missing := range []struct {
+		action string
+		found  bool
+	}{
+		{assertion.EarlierAction, earlierFound},
+		{assertion.LaterAction, laterFound},
+	} {
+		if !missing.found {
+			return &AssertionError{
+				Type:     "seq_before",
+				Expected: expected,
+				Actual:   fmt.Sprintf("no invocation of %s", missing.action),
+				Trace:    trace,
+			}
+		}
+	}
+
+	if earlierMax >= laterMin {
+		return &AssertionError{
+			Type:     "seq_before",
+			Expected: expected,
+			Actual: fmt.Sprintf("%s max seq %d is not before %s min seq %d",
+				assertion.EarlierAction, earlierMax, assertion.LaterAction, laterMin),
+			Trace: trace,
+		}
+	}
+
+	return nil
+}
+
 // assertTraceCount checks if the action appears exactly the specified number of times.
 func assertTraceCount(trace []TraceEvent, assertion Assertion) error {
 	count := 0
@@ -250,6 +367,308 @@ func assertFinalState(ctx context.Context, st *store.Store, assertion Assertion)
 	return nil
 }
 
+// assertStateCount checks that exactly assertion.Count rows in the state table
+// match assertion.Where. Uses the same identifier validation and parameterized
+// WHERE clause (HIGH-3) as assertFinalState.
+func assertStateCount(ctx context.Context, st *store.Store, assertion Assertion) error {
+	if assertion.Table == "" {
+		return fmt.Errorf("state_count assertion requires table name")
+	}
+
+	// Validate table name to prevent SQL injection (identifiers can't be parameterized)
+	if !validIdentifier.MatchString(assertion.Table) {
+		return fmt.Errorf("invalid table name %q: must match pattern %s", assertion.Table, validIdentifier.String())
+	}
+
+	whereSQL, whereArgs, err := buildWhereClause(assertion.Where)
+	if err != nil {
+		return err // Identifier validation failed
+	}
+
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s", assertion.Table)
+	if whereSQL != "" {
+		query += " WHERE " + whereSQL
+	}
+
+	rows, err := st.Query(ctx, query, whereArgs...)
+	if err != nil {
+		return &AssertionError{
+			Type:     "state_count",
+			Expected: fmt.Sprintf("query table %s", assertion.Table),
+			Actual:   fmt.Sprintf("query error: %v", err),
+		}
+	}
+	defer rows.Close()
+
+	var count int
+	if rows.Next() {
+		if err := rows.Scan(&count); err != nil {
+			return fmt.Errorf("scan count: %w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate count: %w", err)
+	}
+
+	if count != assertion.Count {
+		return &AssertionError{
+			Type: "state_count",
+			Expected: fmt.Sprintf("%d rows in %s where %s",
+				assertion.Count, assertion.Table, formatWhereClause(assertion.Where)),
+			Actual: fmt.Sprintf("%d rows", count),
+		}
+	}
+
+	return nil
+}
+
+// assertSyncCount checks that the sync rule assertion.SyncID fired exactly
+// assertion.Count times. Firings are read from the store rather than the
+// trace: a completion that matches a sync but is skipped (idempotency, cycle
+// detection) records no firing, which is what this assertion distinguishes
+// from trace_count.
+func assertSyncCount(ctx context.Context, st *store.Store, assertion Assertion) error {
+	firings, err := st.ReadAllSyncFirings(ctx)
+	if err != nil {
+		return fmt.Errorf("sync_count assertion: read sync firings: %w", err)
+	}
+
+	count := 0
+	for _, f := range firings {
+		if f.SyncID == assertion.SyncID {
+			count++
+		}
+	}
+
+	if count != assertion.Count {
+		return &AssertionError{
+			Type:     "sync_count",
+			Expected: fmt.Sprintf("%d firings of sync %s", assertion.Count, assertion.SyncID),
+			Actual:   fmt.Sprintf("%d firings", count),
+		}
+	}
+
+	return nil
+}
+
+// assertInvocationSecurity checks that every persisted invocation of
+// assertion.Action in the flow carries the expected tenant and user (CP-6).
+// Invocations generated by sync rules inherit the security context of the
+// completion that triggered them, so this verifies propagation end to end.
+// At least one invocation of the action must exist.
+func assertInvocationSecurity(ctx context.Context, st *store.Store, flowToken string, assertion Assertion) error {
+	invocations, _, err := st.ReadFlow(ctx, flowToken)
+	if err != nil {
+		return fmt.Errorf("invocation_security assertion: read flow: %w", err)
+	}
+
+	expected := fmt.Sprintf("invocations of %s with tenant %q, user %q",
+		assertion.Action, assertion.TenantID, assertion.UserID)
+
+	found := 0
+	for _, inv := range invocations {
+		if string(inv.ActionURI) != assertion.Action {
+			continue
+		}
+		found++
+
+		sc := inv.SecurityContext
+		if sc.TenantID != assertion.TenantID || sc.UserID != assertion.UserID {
+			return &AssertionError{
+				Type:     "invocation_security",
+				Expected: expected,
+				Actual: fmt.Sprintf("invocation %s (seq %d) has tenant %q, user %q",
+					inv.ID, inv.Seq, sc.TenantID, sc.UserID),
+			}
+		}
+	}
+
+	if found == 0 {
+		return &AssertionError{
+			Type:     "invocation_security",
+			Expected: expected,
+			Actual:   fmt.Sprintf("no invocation of %s in flow %s", assertion.Action, flowToken),
+		}
+	}
+
+	return nil
+}
+
+// assertFlowComplete checks that the flow ended cleanly: every invocation
+// has a completion and every sync firing has a provenance edge to the
+// invocation it generated (store.FlowState.IsComplete). An orphaned firing
+// means the engine crashed between recording the firing and its invocation;
+// a scheduled firing (ThenClause.AfterSteps) has not been released yet.
+func assertFlowComplete(ctx context.Context, st *store.Store, flowToken string) error {
+	state, err := st.GetFlowState(ctx, flowToken)
+	if err != nil {
+		return fmt.Errorf("flow_complete assertion: %w", err)
+	}
+
+	if state.IsComplete {
+		return nil
+	}
+
+	actual := fmt.Sprintf("%d pending invocations, %d orphaned firings",
+		state.PendingCount, state.OrphanedFirings)
+	if state.ScheduledCount > 0 {
+		actual += fmt.Sprintf(", %d scheduled firings", state.ScheduledCount)
+	}
+	if len(state.Invocations) == 0 {
+		actual = fmt.Sprintf("no invocations in flow %s", flowToken)
+	}
+	return &AssertionError{
+		Type:     "flow_complete",
+		Expected: fmt.Sprintf("flow %s with no pending invocations or orphaned firings", flowToken),
+		Actual:   actual,
+	}
+}
+
+// assertCompletionResult checks that some invocation of assertion.Action in
+// the flow whose args match assertion.Where completed with a result
+// containing every field in assertion.Expect (subset match, compared as IR
+// values). Completions are checked in seq order; the first one that matches
+// passes the assertion.
+func assertCompletionResult(ctx context.Context, st *store.Store, flowToken string, assertion Assertion) error {
+	where, err := convertArgsToIRObject(assertion.Where)
+	if err != nil {
+		return fmt.Errorf("completion_result assertion: where: %w", err)
+	}
+	expect, err := convertArgsToIRObject(assertion.Expect)
+	if err != nil {
+		return fmt.Errorf("completion_result assertion: expect: %w", err)
+	}
+
+	invocations, err := st.ReadInvocationsByAction(ctx, flowToken, ir.ActionRef(assertion.Action))
+	if err != nil {
+		return fmt.Errorf("completion_result assertion: read invocations: %w", err)
+	}
+	_, completions, err := st.ReadFlow(ctx, flowToken)
+	if err != nil {
+		return fmt.Errorf("completion_result assertion: read flow: %w", err)
+	}
+
+	selected := make(map[string]bool, len(invocations))
+	for _, inv := range invocations {
+		if containsIRFields(inv.Args, where) {
+			selected[inv.ID] = true
+		}
+	}
+
+	expected := fmt.Sprintf("completion of %s", assertion.Action)
+	if len(assertion.Where) > 0 {
+		expected += fmt.Sprintf(" where %s", formatWhereClause(assertion.Where))
+	}
+	expected += fmt.Sprintf(" with result %v", expect)
+
+	var mismatches []string
+	for _, comp := range completions {
+		if !selected[comp.InvocationID] {
+			continue
+		}
+		if containsIRFields(comp.Result, expect) {
+			return nil
+		}
+		mismatches = append(mismatches, fmt.Sprintf("[seq %d] %s %v", comp.Seq, comp.OutputCase, comp.Result))
+	}
+
+	actual := fmt.Sprintf("results: %s", strings.Join(mismatches, ", "))
+	switch {
+	case len(selected) == 0:
+		actual = fmt.Sprintf("no matching invocation of %s in flow %s", assertion.Action, flowToken)
+	case len(mismatches) == 0:
+		actual = fmt.Sprintf("no completion of %s in flow %s", assertion.Action, flowToken)
+	}
+	return &AssertionError{
+		Type:     "completion_result",
+		Expected: expected,
+		Actual:   actual,
+	}
+}
+
+// containsIRFields reports whether obj has every field of expected with an
+// equal value (ir.Equal). Extra fields in obj are ignored.
+func containsIRFields(obj, expected ir.IRObject) bool {
+	for key, want := range expected {
+		got, ok := obj[key]
+		if !ok || !ir.Equal(got, want) {
+			return false
+		}
+	}
+	return true
+}
+
+// assertProvenance checks that an invocation of assertion.Effect was caused by
+// a completion of assertion.Cause through one or more sync firings.
+//
+// Starting from every completion of the cause action in the flow, the chain is
+// followed forward with ReadTriggered: each triggered invocation's completion
+// is in turn expanded until an invocation of the effect action is reached.
+// Trace order alone is not enough - an effect that merely happens after the
+// cause, without a provenance edge chain linking them, fails this assertion.
+func assertProvenance(ctx context.Context, st *store.Store, flowToken string, assertion Assertion) error {
+	invocations, completions, err := st.ReadFlow(ctx, flowToken)
+	if err != nil {
+		return fmt.Errorf("provenance assertion: read flow: %w", err)
+	}
+
+	// Index completions by invocation so triggered invocations can be expanded
+	completionsByInvocation := make(map[string][]ir.Completion)
+	for _, comp := range completions {
+		completionsByInvocation[comp.InvocationID] = append(completionsByInvocation[comp.InvocationID], comp)
+	}
+
+	var frontier []ir.Completion
+	effectCount := 0
+	for _, inv := range invocations {
+		switch string(inv.ActionURI) {
+		case assertion.Cause:
+			frontier = append(frontier, completionsByInvocation[inv.ID]...)
+		case assertion.Effect:
+			effectCount++
+		}
+	}
+
+	expected := fmt.Sprintf("%s caused by %s via provenance edges", assertion.Effect, assertion.Cause)
+	if len(frontier) == 0 {
+		return &AssertionError{
+			Type:     "provenance",
+			Expected: expected,
+			Actual:   fmt.Sprintf("no completion of %s in flow", assertion.Cause),
+		}
+	}
+
+	// Breadth-first walk; visited guards against cycles in the edge graph
+	visited := make(map[string]bool)
+	for len(frontier) > 0 {
+		comp := frontier[0]
+		frontier = frontier[1:]
+
+		triggered, err := st.ReadTriggered(ctx, comp.ID)
+		if err != nil {
+			return fmt.Errorf("provenance assertion: read triggered: %w", err)
+		}
+
+		for _, inv := range triggered {
+			if string(inv.ActionURI) == assertion.Effect {
+				return nil
+			}
+			if visited[inv.ID] {
+				continue
+			}
+			visited[inv.ID] = true
+			frontier = append(frontier, completionsByInvocation[inv.ID]...)
+		}
+	}
+
+	return &AssertionError{
+		Type:     "provenance",
+		Expected: expected,
+		Actual: fmt.Sprintf("no provenance chain from %s to %s (%d unrelated invocation(s) of %s)",
+			assertion.Cause, assertion.Effect, effectCount, assertion.Effect),
+	}
+}
+
 // buildWhereClause constructs parameterized WHERE clause from assertion.Where.
 // Returns SQL fragment, arguments slice, and error. Keys are sorted for determinism.
 //
@@ -394,7 +813,9 @@ func stateValuesEqual(expected, actual interface{}) bool {
 }
 
 // matchArgs checks if actual args contain all expected args (subset match).
-// Extra keys in actual are ignored.
+// Extra keys in actual are ignored. An expected value of the form
+// {regex: "<pattern>"} matches string values against an RE2 pattern; callers
+// should check patterns with validateArgPatterns first.
 func matchArgs(actual interface{}, expected map[string]interface{}) bool {
 	if expected == nil || len(expected) == 0 {
 		return true // No args to match
@@ -420,8 +841,12 @@ func matchArgs(actual interface{}, expected map[string]interface{}) bool {
 }
 
 // valuesEqual compares two values for equality.
-// Handles nested maps and slices.
+// Handles nested maps and slices, and {regex: "..."} patterns at the top level.
 func valuesEqual(actual, expected interface{}) bool {
+	if pattern, ok := regexPattern(expected); ok {
+		return matchRegex(actual, pattern)
+	}
+
 	// Handle nil cases
 	if actual == nil && expected == nil {
 		return true
@@ -435,42 +860,186 @@ func valuesEqual(actual, expected interface{}) bool {
 	return reflect.DeepEqual(actual, expected)
 }
 
+// regexPattern reports whether an expected arg value is a regex matcher,
+// written as a single-key object {regex: "<pattern>"}, and returns the pattern.
+func regexPattern(expected interface{}) (string, bool) {
+	m, ok := expected.(map[string]interface{})
+	if !ok || len(m) != 1 {
+		return "", false
+	}
+	pattern, ok := m["regex"].(string)
+	return pattern, ok
+}
+
+// matchRegex matches a string actual value against pattern.
+// Non-string values and invalid patterns never match.
+func matchRegex(actual interface{}, pattern string) bool {
+	var str string
+	switch v := actual.(type) {
+	case string:
+		str = v
+	case ir.IRString:
+		str = string(v)
+	default:
+		return false
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(str)
+}
+
+// validateArgPatterns compiles every regex matcher in expected args so an
+// invalid pattern is reported as an assertion failure instead of silently
+// never matching. Keys are checked in sorted order for deterministic errors.
+func validateArgPatterns(assertionType string, expected map[string]interface{}) error {
+	keys := make([]string, 0, len(expected))
+	for k := range expected {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		pattern, ok := regexPattern(expected[key])
+		if !ok {
+			continue
+		}
+		if _, err := regexp.Compile(pattern); err != nil {
+			return &AssertionError{
+				Type:     assertionType,
+				Expected: fmt.Sprintf("valid regex for arg %q", key),
+				Actual:   fmt.Sprintf("invalid regex %q: %v", pattern, err),
+			}
+		}
+	}
+	return nil
+}
+
 // AssertionContext provides context for evaluating assertions.
 type AssertionContext struct {
 	Store *store.Store
 	Ctx   context.Context
+
+	// FlowToken scopes store-backed trace assertions (provenance) to the
+	// scenario's flow.
+	FlowToken string
 }
 
 // EvaluateAssertions evaluates all assertions against the result.
 // Returns a slice of error messages for failed assertions.
-// The actx parameter provides database access for final_state assertions.
+// The actx parameter provides database access for final_state, state_count,
+// provenance, sync_count, invocation_security, flow_complete, and
+// completion_result assertions.
 func EvaluateAssertions(result *Result, assertions []Assertion, actx *AssertionContext) []string {
 	var errors []string
 
+	for _, err := range evaluateAssertions(result, assertions, actx) {
+		if err != nil {
+			errors = append(errors, err.Error())
+		}
+	}
+
+	return errors
+}
+
+// evaluateAssertions evaluates each assertion and returns one entry per
+// assertion, index-aligned with assertions; nil means the assertion passed.
+func evaluateAssertions(result *Result, assertions []Assertion, actx *AssertionContext) []error {
+	errs := make([]error, len(assertions))
+
 	for i, assertion := range assertions {
 		var err error
 
+		// A flow_token scopes the trace and the store-backed flow reads
+		trace := result.Trace
+		var flowToken string
+		if actx != nil {
+			flowToken = actx.FlowToken
+		}
+		if assertion.FlowToken != "" {
+			trace = filterTraceByFlow(result.Trace, assertion.FlowToken)
+			if len(trace) == 0 {
+				errs[i] = fmt.Errorf("assertion[%d]: flow_token %q does not appear in the trace", i, assertion.FlowToken)
+				continue
+			}
+			flowToken = assertion.FlowToken
+		}
+
 		switch assertion.Type {
 		case AssertTraceContains:
-			err = assertTraceContains(result.Trace, assertion)
+			err = assertTraceContains(trace, assertion)
+		case AssertTraceNotContains:
+			err = assertTraceNotContains(trace, assertion)
+		case AssertTraceArgsAll:
+			err = assertTraceArgsAll(trace, assertion)
 		case AssertTraceOrder:
-			err = assertTraceOrder(result.Trace, assertion)
+			err = assertTraceOrder(trace, assertion)
 		case AssertTraceCount:
-			err = assertTraceCount(result.Trace, assertion)
+			err = assertTraceCount(trace, assertion)
+		case AssertSeqBefore:
+			err = assertSeqBefore(trace, assertion)
 		case AssertFinalState:
 			if actx == nil || actx.Store == nil {
 				err = fmt.Errorf("assertion[%d]: final_state requires database context", i)
 			} else {
 				err = assertFinalState(actx.Ctx, actx.Store, assertion)
 			}
+		case AssertStateCount:
+			if actx == nil || actx.Store == nil {
+				err = fmt.Errorf("assertion[%d]: state_count requires database context", i)
+			} else {
+				err = assertStateCount(actx.Ctx, actx.Store, assertion)
+			}
+		case AssertProvenance:
+			if actx == nil || actx.Store == nil {
+				err = fmt.Errorf("assertion[%d]: provenance requires database context", i)
+			} else {
+				err = assertProvenance(actx.Ctx, actx.Store, flowToken, assertion)
+			}
+		case AssertSyncCount:
+			if actx == nil || actx.Store == nil {
+				err = fmt.Errorf("assertion[%d]: sync_count requires database context", i)
+			} else {
+				err = assertSyncCount(actx.Ctx, actx.Store, assertion)
+			}
+		case AssertInvocationSecurity:
+			if actx == nil || actx.Store == nil {
+				err = fmt.Errorf("assertion[%d]: invocation_security requires database context", i)
+			} else {
+				err = assertInvocationSecurity(actx.Ctx, actx.Store, flowToken, assertion)
+			}
+		case AssertFlowComplete:
+			if actx == nil || actx.Store == nil {
+				err = fmt.Errorf("assertion[%d]: flow_complete requires database context", i)
+			} else {
+				err = assertFlowComplete(actx.Ctx, actx.Store, flowToken)
+			}
+		case AssertCompletionResult:
+			if actx == nil || actx.Store == nil {
+				err = fmt.Errorf("assertion[%d]: completion_result requires database context", i)
+			} else {
+				err = assertCompletionResult(actx.Ctx, actx.Store, flowToken, assertion)
+			}
 		default:
 			err = fmt.Errorf("assertion[%d]: unknown assertion type %q", i, assertion.Type)
 		}
 
-		if err != nil {
-			errors = append(errors, err.Error())
-		}
+		errs[i] = err
 	}
 
-	return errors
+	return errs
+}
+
+// filterTraceByFlow returns the events of trace that belong to flowToken,
+// in their original order.
+func filterTraceByFlow(trace []TraceEvent, flowToken string) []TraceEvent {
+	var filtered []TraceEvent
+	for _, event := range trace {
+		if event.FlowToken == flowToken {
+			filtered = append(filtered, event)
+		}
+	}
+	return filtered
 }
@@ -4,11 +4,16 @@ import (
 	"context"
 	"testing"
 
+	"github.com/roach88/nysm/internal/engine"
+	"github.com/roach88/nysm/internal/ir"
 	"github.com/roach88/nysm/internal/store"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// testSecurityContext is a valid context (CP-6) for records written in tests.
+var testSecurityContext = ir.NewSecurityContext("test-tenant", "test-user")
+
 func TestAssertTraceContains_Found(t *testing.T) {
 	trace := []TraceEvent{
 		{Type: "invocation", ActionURI: "Cart.addItem", Args: map[string]interface{}{"item_id": "widget", "quantity": 3}, Seq: 1},
@@ -97,6 +102,200 @@ func TestAssertTraceContains_NoArgsRequired(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestAssertTraceNotContains_Absent(t *testing.T) {
+	trace := []TraceEvent{
+		{Type: "invocation", ActionURI: "Cart.addItem", Args: map[string]interface{}{"item_id": "widget"}, Seq: 1},
+		{Type: "completion", OutputCase: "Success", Seq: 2},
+	}
+
+	assertion := Assertion{
+		Type:   AssertTraceNotContains,
+		Action: "Cart.checkout",
+	}
+
+	err := assertTraceNotContains(trace, assertion)
+	assert.NoError(t, err)
+}
+
+func TestAssertTraceNotContains_Present(t *testing.T) {
+	trace := []TraceEvent{
+		{Type: "invocation", ActionURI: "Cart.addItem", Args: map[string]interface{}{"item_id": "widget"}, Seq: 1},
+		{Type: "completion", OutputCase: "Success", Seq: 2},
+		{Type: "invocation", ActionURI: "Cart.addItem", Args: map[string]interface{}{"item_id": "widget"}, Seq: 3},
+	}
+
+	assertion := Assertion{
+		Type:   AssertTraceNotContains,
+		Action: "Cart.addItem",
+	}
+
+	err := assertTraceNotContains(trace, assertion)
+	require.Error(t, err)
+
+	assertErr, ok := err.(*AssertionError)
+	require.True(t, ok)
+	assert.Equal(t, "trace_not_contains", assertErr.Type)
+	assert.Contains(t, assertErr.Expected, "Cart.addItem")
+	assert.Contains(t, assertErr.Actual, "found 2 matching event(s)")
+	assert.Contains(t, assertErr.Actual, "[seq 1]")
+	assert.Contains(t, assertErr.Actual, "[seq 3]")
+}
+
+func TestAssertTraceNotContains_SubsetArgs(t *testing.T) {
+	// Subset semantics mirror trace_contains: only specified args are compared
+	trace := []TraceEvent{
+		{Type: "invocation", ActionURI: "Cart.addItem", Args: map[string]interface{}{
+			"item_id":  "widget",
+			"quantity": 3,
+		}, Seq: 1},
+	}
+
+	matching := Assertion{
+		Type:   AssertTraceNotContains,
+		Action: "Cart.addItem",
+		Args:   map[string]interface{}{"item_id": "widget"},
+	}
+	require.Error(t, assertTraceNotContains(trace, matching))
+
+	different := Assertion{
+		Type:   AssertTraceNotContains,
+		Action: "Cart.addItem",
+		Args:   map[string]interface{}{"item_id": "gadget"},
+	}
+	assert.NoError(t, assertTraceNotContains(trace, different))
+}
+
+func TestAssertTraceContains_RegexMatch(t *testing.T) {
+	trace := []TraceEvent{
+		{Type: "invocation", ActionURI: "Order.place", Args: map[string]interface{}{"order_id": "ord-123", "quantity": 2}, Seq: 1},
+	}
+
+	assertion := Assertion{
+		Type:   AssertTraceContains,
+		Action: "Order.place",
+		Args: map[string]interface{}{
+			"order_id": map[string]interface{}{"regex": "^ord-[0-9]+$"},
+			"quantity": 2, // Non-regex values still use equality
+		},
+	}
+
+	err := assertTraceContains(trace, assertion)
+	assert.NoError(t, err)
+}
+
+func TestAssertTraceContains_RegexNoMatch(t *testing.T) {
+	trace := []TraceEvent{
+		{Type: "invocation", ActionURI: "Order.place", Args: map[string]interface{}{"order_id": "inv-123"}, Seq: 1},
+		{Type: "invocation", ActionURI: "Order.place", Args: map[string]interface{}{"order_id": 42}, Seq: 2},
+	}
+
+	assertion := Assertion{
+		Type:   AssertTraceContains,
+		Action: "Order.place",
+		Args:   map[string]interface{}{"order_id": map[string]interface{}{"regex": "^ord-"}},
+	}
+
+	err := assertTraceContains(trace, assertion)
+	require.Error(t, err)
+	assertErr, ok := err.(*AssertionError)
+	require.True(t, ok)
+	assert.Equal(t, "not found in trace", assertErr.Actual)
+}
+
+func TestAssertTraceContains_InvalidRegex(t *testing.T) {
+	trace := []TraceEvent{
+		{Type: "invocation", ActionURI: "Order.place", Args: map[string]interface{}{"order_id": "ord-123"}, Seq: 1},
+	}
+
+	assertion := Assertion{
+		Type:   AssertTraceContains,
+		Action: "Order.place",
+		Args:   map[string]interface{}{"order_id": map[string]interface{}{"regex": "ord-(["}},
+	}
+
+	var err error
+	require.NotPanics(t, func() { err = assertTraceContains(trace, assertion) })
+	require.Error(t, err)
+
+	assertErr, ok := err.(*AssertionError)
+	require.True(t, ok)
+	assert.Equal(t, "trace_contains", assertErr.Type)
+	assert.Equal(t, `valid regex for arg "order_id"`, assertErr.Expected)
+	assert.Contains(t, assertErr.Actual, `invalid regex "ord-(["`)
+}
+
+func TestAssertTraceNotContains_Regex(t *testing.T) {
+	trace := []TraceEvent{
+		{Type: "invocation", ActionURI: "Order.place", Args: map[string]interface{}{"order_id": "ord-123"}, Seq: 1},
+	}
+
+	forbidden := Assertion{
+		Type:   AssertTraceNotContains,
+		Action: "Order.place",
+		Args:   map[string]interface{}{"order_id": map[string]interface{}{"regex": "^test-"}},
+	}
+	assert.NoError(t, assertTraceNotContains(trace, forbidden))
+
+	forbidden.Args = map[string]interface{}{"order_id": map[string]interface{}{"regex": "^ord-"}}
+	assert.Error(t, assertTraceNotContains(trace, forbidden))
+}
+
+func TestAssertTraceArgsAll_Uniform(t *testing.T) {
+	trace := []TraceEvent{
+		{Type: "invocation", ActionURI: "Notification.send", Args: map[string]interface{}{"channel": "email", "to": "a"}, Seq: 1},
+		{Type: "completion", OutputCase: "Success", Seq: 2},
+		{Type: "invocation", ActionURI: "Cart.checkout", Args: map[string]interface{}{"channel": "web"}, Seq: 3},
+		{Type: "invocation", ActionURI: "Notification.send", Args: map[string]interface{}{"channel": "email", "to": "b"}, Seq: 4},
+	}
+
+	assertion := Assertion{
+		Type:   AssertTraceArgsAll,
+		Action: "Notification.send",
+		Args:   map[string]interface{}{"channel": "email"},
+	}
+
+	err := assertTraceArgsAll(trace, assertion)
+	assert.NoError(t, err)
+}
+
+func TestAssertTraceArgsAll_Divergent(t *testing.T) {
+	trace := []TraceEvent{
+		{Type: "invocation", ActionURI: "Notification.send", Args: map[string]interface{}{"channel": "email"}, Seq: 1},
+		{Type: "invocation", ActionURI: "Notification.send", Args: map[string]interface{}{"channel": "sms"}, Seq: 3},
+		{Type: "invocation", ActionURI: "Notification.send", Args: map[string]interface{}{"channel": "push"}, Seq: 5},
+	}
+
+	assertion := Assertion{
+		Type:   AssertTraceArgsAll,
+		Action: "Notification.send",
+		Args:   map[string]interface{}{"channel": "email"},
+	}
+
+	err := assertTraceArgsAll(trace, assertion)
+	require.Error(t, err)
+
+	assertErr, ok := err.(*AssertionError)
+	require.True(t, ok)
+	assert.Equal(t, "trace_args_all", assertErr.Type)
+	assert.Contains(t, assertErr.Actual, "[seq 3]")
+	assert.NotContains(t, assertErr.Actual, "[seq 5]")
+}
+
+func TestAssertTraceArgsAll_NoOccurrences(t *testing.T) {
+	trace := []TraceEvent{
+		{Type: "invocation", ActionURI: "Cart.checkout", Args: map[string]interface{}{}, Seq: 1},
+	}
+
+	assertion := Assertion{
+		Type:   AssertTraceArgsAll,
+		Action: "Notification.send",
+		Args:   map[string]interface{}{"channel": "email"},
+	}
+
+	err := assertTraceArgsAll(trace, assertion)
+	assert.NoError(t, err)
+}
+
 func TestAssertTraceOrder_Correct(t *testing.T) {
 	trace := []TraceEvent{
 		{Type: "invocation", ActionURI: "Inventory.reserve", Seq: 1},
@@ -178,6 +377,76 @@ func TestAssertTraceOrder_InterveningActionsAllowed(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestAssertSeqBefore_ClearOrdering(t *testing.T) {
+	trace := []TraceEvent{
+		{Type: "invocation", ActionURI: "Cart.addItem", Seq: 1},
+		{Type: "completion", OutputCase: "Success", Seq: 2},
+		{Type: "invocation", ActionURI: "Cart.addItem", Seq: 3},
+		{Type: "completion", OutputCase: "Success", Seq: 4},
+		{Type: "invocation", ActionURI: "Cart.checkout", Seq: 5},
+		{Type: "completion", OutputCase: "Success", Seq: 6},
+	}
+
+	assertion := Assertion{
+		Type:          AssertSeqBefore,
+		EarlierAction: "Cart.addItem",
+		LaterAction:   "Cart.checkout",
+	}
+
+	err := assertSeqBefore(trace, assertion)
+	assert.NoError(t, err)
+}
+
+func TestAssertSeqBefore_Interleaved(t *testing.T) {
+	// trace_order would pass (first addItem precedes first checkout),
+	// but the second addItem comes after checkout.
+	trace := []TraceEvent{
+		{Type: "invocation", ActionURI: "Cart.addItem", Seq: 1},
+		{Type: "completion", OutputCase: "Success", Seq: 2},
+		{Type: "invocation", ActionURI: "Cart.checkout", Seq: 3},
+		{Type: "completion", OutputCase: "Success", Seq: 4},
+		{Type: "invocation", ActionURI: "Cart.addItem", Seq: 5},
+		{Type: "completion", OutputCase: "Success", Seq: 6},
+	}
+
+	assert.NoError(t, assertTraceOrder(trace, Assertion{
+		Type:    AssertTraceOrder,
+		Actions: []string{"Cart.addItem", "Cart.checkout"},
+	}))
+
+	err := assertSeqBefore(trace, Assertion{
+		Type:          AssertSeqBefore,
+		EarlierAction: "Cart.addItem",
+		LaterAction:   "Cart.checkout",
+	})
+	require.Error(t, err)
+
+	assertErr, ok := err.(*AssertionError)
+	require.True(t, ok)
+	assert.Equal(t, "seq_before", assertErr.Type)
+	assert.Contains(t, assertErr.Actual, "Cart.addItem max seq 5")
+	assert.Contains(t, assertErr.Actual, "Cart.checkout min seq 3")
+	assert.Equal(t, trace, assertErr.Trace)
+}
+
+func TestAssertSeqBefore_MissingAction(t *testing.T) {
+	trace := []TraceEvent{
+		{Type: "invocation", ActionURI: "Cart.addItem", Seq: 1},
+		{Type: "completion", OutputCase: "Success", Seq: 2},
+	}
+
+	err := assertSeqBefore(trace, Assertion{
+		Type:          AssertSeqBefore,
+		EarlierAction: "Cart.addItem",
+		LaterAction:   "Cart.checkout",
+	})
+	require.Error(t, err)
+
+	assertErr, ok := err.(*AssertionError)
+	require.True(t, ok)
+	assert.Contains(t, assertErr.Actual, "no invocation of Cart.checkout")
+}
+
 func TestAssertTraceCount_Exact(t *testing.T) {
 	trace := []TraceEvent{
 		{Type: "invocation", ActionURI: "Notification.send", Seq: 1},
@@ -858,3 +1127,625 @@ func TestEvaluateAssertions_FinalStateWithContext_Pass(t *testing.T) {
 	errors := EvaluateAssertions(result, assertions, actx)
 	assert.Empty(t, errors)
 }
+
+// State count assertion tests
+
+func seedStateCountRows(t *testing.T, st *store.Store) {
+	t.Helper()
+	createTestTable(t, st)
+	rows := []struct {
+		itemID string
+		status string
+	}{
+		{"widget", "active"},
+		{"gadget", "active"},
+		{"gizmo", "active"},
+		{"doohickey", "removed"},
+	}
+	for _, r := range rows {
+		_, err := st.DB().Exec(`INSERT INTO test_items (item_id, quantity, status) VALUES (?, ?, ?)`,
+			r.itemID, 1, r.status)
+		require.NoError(t, err)
+	}
+}
+
+func TestAssertStateCount_Match(t *testing.T) {
+	st := setupTestStore(t)
+	seedStateCountRows(t, st)
+
+	assertion := Assertion{
+		Type:  AssertStateCount,
+		Table: "test_items",
+		Where: map[string]interface{}{"status": "active"},
+		Count: 3,
+	}
+
+	err := assertStateCount(context.Background(), st, assertion)
+	assert.NoError(t, err)
+}
+
+func TestAssertStateCount_NoWhereCountsAllRows(t *testing.T) {
+	st := setupTestStore(t)
+	seedStateCountRows(t, st)
+
+	assertion := Assertion{Type: AssertStateCount, Table: "test_items", Count: 4}
+
+	err := assertStateCount(context.Background(), st, assertion)
+	assert.NoError(t, err)
+}
+
+func TestAssertStateCount_ZeroMatches(t *testing.T) {
+	st := setupTestStore(t)
+	seedStateCountRows(t, st)
+
+	assertion := Assertion{
+		Type:  AssertStateCount,
+		Table: "test_items",
+		Where: map[string]interface{}{"status": "archived"},
+		Count: 0,
+	}
+
+	err := assertStateCount(context.Background(), st, assertion)
+	assert.NoError(t, err)
+}
+
+func TestAssertStateCount_Mismatch(t *testing.T) {
+	st := setupTestStore(t)
+	seedStateCountRows(t, st)
+
+	assertion := Assertion{
+		Type:  AssertStateCount,
+		Table: "test_items",
+		Where: map[string]interface{}{"status": "active"},
+		Count: 2,
+	}
+
+	err := assertStateCount(context.Background(), st, assertion)
+	require.Error(t, err)
+
+	assertErr, ok := err.(*AssertionError)
+	require.True(t, ok)
+	assert.Equal(t, "state_count", assertErr.Type)
+	assert.Equal(t, "2 rows in test_items where status=active", assertErr.Expected)
+	assert.Equal(t, "3 rows", assertErr.Actual)
+}
+
+func TestAssertStateCount_InvalidTableName(t *testing.T) {
+	assertion := Assertion{
+		Type:  AssertStateCount,
+		Table: "users; DROP TABLE users; --",
+	}
+
+	err := assertStateCount(context.Background(), nil, assertion)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid table name")
+}
+
+func TestAssertStateCount_InvalidColumnName(t *testing.T) {
+	st := setupTestStore(t)
+	seedStateCountRows(t, st)
+
+	assertion := Assertion{
+		Type:  AssertStateCount,
+		Table: "test_items",
+		Where: map[string]interface{}{"status; DROP TABLE test_items; --": "active"},
+	}
+
+	err := assertStateCount(context.Background(), st, assertion)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid column name")
+}
+
+func TestEvaluateAssertions_StateCountWithContext(t *testing.T) {
+	st := setupTestStore(t)
+	seedStateCountRows(t, st)
+
+	result := &Result{Trace: []TraceEvent{}}
+	assertions := []Assertion{
+		{Type: AssertStateCount, Table: "test_items", Where: map[string]interface{}{"status": "active"}, Count: 3},
+		{Type: AssertStateCount, Table: "test_items", Where: map[string]interface{}{"status": "removed"}, Count: 2},
+	}
+
+	errors := EvaluateAssertions(result, assertions, &AssertionContext{Store: st, Ctx: context.Background()})
+	require.Len(t, errors, 1)
+	assert.Contains(t, errors[0], "state_count")
+	assert.Contains(t, errors[0], "1 rows")
+}
+
+// Provenance assertion tests seed a real invocation/completion/firing chain
+
+const provenanceFlow = "flow-provenance"
+
+func writeProvenanceInvocation(t *testing.T, st *store.Store, action string, seq int64) ir.Invocation {
+	t.Helper()
+	args := ir.IRObject{}
+	inv := ir.Invocation{
+		ID:              ir.MustInvocationID(provenanceFlow, action, args, seq),
+		FlowToken:       provenanceFlow,
+		ActionURI:       ir.ActionRef(action),
+		Args:            args,
+		Seq:             seq,
+		SecurityContext: testSecurityContext,
+		SpecHash:        "test-spec-hash",
+		EngineVersion:   "test",
+		IRVersion:       ir.IRVersion,
+	}
+	require.NoError(t, st.WriteInvocation(context.Background(), inv))
+	return inv
+}
+
+func writeProvenanceCompletion(t *testing.T, st *store.Store, inv ir.Invocation, seq int64) ir.Completion {
+	t.Helper()
+	result := ir.IRObject{}
+	compID, err := ir.CompletionID(inv.ID, "Success", result, seq)
+	require.NoError(t, err)
+	comp := ir.Completion{
+		ID:              compID,
+		InvocationID:    inv.ID,
+		OutputCase:      "Success",
+		Result:          result,
+		Seq:             seq,
+		SecurityContext: testSecurityContext,
+	}
+	require.NoError(t, st.WriteCompletion(context.Background(), comp))
+	return comp
+}
+
+// fireProvenanceSync records a sync firing on comp that generates action.
+func fireProvenanceSync(t *testing.T, st *store.Store, comp ir.Completion, syncID, action string, seq int64) ir.Invocation {
+	t.Helper()
+	args := ir.IRObject{}
+	inv := ir.Invocation{
+		ID:              ir.MustInvocationID(provenanceFlow, action, args, seq),
+		FlowToken:       provenanceFlow,
+		ActionURI:       ir.ActionRef(action),
+		Args:            args,
+		Seq:             seq,
+		SecurityContext: testSecurityContext,
+		SpecHash:        "test-spec-hash",
+		EngineVersion:   "test",
+		IRVersion:       ir.IRVersion,
+	}
+	firing := ir.SyncFiring{
+		CompletionID: comp.ID,
+		SyncID:       syncID,
+		BindingHash:  "binding-" + syncID,
+		Seq:          seq,
+	}
+	_, inserted, err := st.WriteSyncFiringAtomic(context.Background(), firing, inv)
+	require.NoError(t, err)
+	require.True(t, inserted)
+	return inv
+}
+
+func TestAssertProvenance_DirectEdge(t *testing.T) {
+	st := setupTestStore(t)
+
+	checkout := writeProvenanceInvocation(t, st, "Cart.checkout", 1)
+	checkoutComp := writeProvenanceCompletion(t, st, checkout, 2)
+	fireProvenanceSync(t, st, checkoutComp, "reserve-on-checkout", "Inventory.reserve", 3)
+
+	assertion := Assertion{Type: AssertProvenance, Cause: "Cart.checkout", Effect: "Inventory.reserve"}
+	err := assertProvenance(context.Background(), st, provenanceFlow, assertion)
+	assert.NoError(t, err)
+}
+
+func TestAssertProvenance_MultiHopChain(t *testing.T) {
+	st := setupTestStore(t)
+
+	checkout := writeProvenanceInvocation(t, st, "Cart.checkout", 1)
+	checkoutComp := writeProvenanceCompletion(t, st, checkout, 2)
+	charge := fireProvenanceSync(t, st, checkoutComp, "charge-on-checkout", "Payment.charge", 3)
+	chargeComp := writeProvenanceCompletion(t, st, charge, 4)
+	fireProvenanceSync(t, st, chargeComp, "reserve-on-charge", "Inventory.reserve", 5)
+
+	assertion := Assertion{Type: AssertProvenance, Cause: "Cart.checkout", Effect: "Inventory.reserve"}
+	err := assertProvenance(context.Background(), st, provenanceFlow, assertion)
+	assert.NoError(t, err)
+}
+
+func TestAssertProvenance_UnrelatedEffect(t *testing.T) {
+	st := setupTestStore(t)
+
+	// Inventory.reserve runs after checkout, but no sync firing links them
+	checkout := writeProvenanceInvocation(t, st, "Cart.checkout", 1)
+	writeProvenanceCompletion(t, st, checkout, 2)
+	reserve := writeProvenanceInvocation(t, st, "Inventory.reserve", 3)
+	writeProvenanceCompletion(t, st, reserve, 4)
+
+	assertion := Assertion{Type: AssertProvenance, Cause: "Cart.checkout", Effect: "Inventory.reserve"}
+	err := assertProvenance(context.Background(), st, provenanceFlow, assertion)
+	require.Error(t, err)
+
+	assertErr, ok := err.(*AssertionError)
+	require.True(t, ok)
+	assert.Equal(t, "provenance", assertErr.Type)
+	assert.Contains(t, assertErr.Actual, "no provenance chain from Cart.checkout to Inventory.reserve")
+	assert.Contains(t, assertErr.Actual, "1 unrelated invocation(s)")
+}
+
+func TestAssertProvenance_CauseNotCompleted(t *testing.T) {
+	st := setupTestStore(t)
+
+	writeProvenanceInvocation(t, st, "Cart.checkout", 1)
+
+	assertion := Assertion{Type: AssertProvenance, Cause: "Cart.checkout", Effect: "Inventory.reserve"}
+	err := assertProvenance(context.Background(), st, provenanceFlow, assertion)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no completion of Cart.checkout in flow")
+}
+
+func TestEvaluateAssertions_ProvenanceRequiresContext(t *testing.T) {
+	result := &Result{Trace: []TraceEvent{}}
+	assertions := []Assertion{
+		{Type: AssertProvenance, Cause: "Cart.checkout", Effect: "Inventory.reserve"},
+	}
+
+	errors := EvaluateAssertions(result, assertions, nil)
+	require.Len(t, errors, 1)
+	assert.Contains(t, errors[0], "provenance requires database context")
+}
+
+func TestAssertSyncCount_IdempotentRefireCountsOnce(t *testing.T) {
+	st := setupTestStore(t)
+	ctx := context.Background()
+
+	checkout := writeProvenanceInvocation(t, st, "Cart.checkout", 1)
+	checkoutComp := writeProvenanceCompletion(t, st, checkout, 2)
+	fireProvenanceSync(t, st, checkoutComp, "sync-reserve", "Inventory.reserve", 3)
+
+	// Re-processing the same completion hits the (completion, sync, binding)
+	// unique key and records nothing (CP-1)
+	_, inserted, err := st.WriteSyncFiring(ctx, ir.SyncFiring{
+		CompletionID: checkoutComp.ID,
+		SyncID:       "sync-reserve",
+		BindingHash:  "binding-sync-reserve",
+		Seq:          4,
+	})
+	require.NoError(t, err)
+	require.False(t, inserted)
+
+	err = assertSyncCount(ctx, st, Assertion{Type: AssertSyncCount, SyncID: "sync-reserve", Count: 1})
+	assert.NoError(t, err)
+}
+
+func TestAssertSyncCount_Mismatch(t *testing.T) {
+	st := setupTestStore(t)
+
+	checkout := writeProvenanceInvocation(t, st, "Cart.checkout", 1)
+	checkoutComp := writeProvenanceCompletion(t, st, checkout, 2)
+	fireProvenanceSync(t, st, checkoutComp, "sync-reserve", "Inventory.reserve", 3)
+
+	err := assertSyncCount(context.Background(), st, Assertion{Type: AssertSyncCount, SyncID: "sync-reserve", Count: 2})
+	require.Error(t, err)
+
+	assertErr, ok := err.(*AssertionError)
+	require.True(t, ok)
+	assert.Equal(t, "sync_count", assertErr.Type)
+	assert.Contains(t, assertErr.Expected, "2 firings of sync sync-reserve")
+	assert.Equal(t, "1 firings", assertErr.Actual)
+}
+
+func TestAssertSyncCount_OtherSyncsIgnored(t *testing.T) {
+	st := setupTestStore(t)
+
+	checkout := writeProvenanceInvocation(t, st, "Cart.checkout", 1)
+	checkoutComp := writeProvenanceCompletion(t, st, checkout, 2)
+	fireProvenanceSync(t, st, checkoutComp, "sync-charge", "Payment.charge", 3)
+
+	err := assertSyncCount(context.Background(), st, Assertion{Type: AssertSyncCount, SyncID: "sync-reserve", Count: 0})
+	assert.NoError(t, err)
+}
+
+func TestEvaluateAssertions_SyncCountRequiresContext(t *testing.T) {
+	result := &Result{Trace: []TraceEvent{}}
+	assertions := []Assertion{
+		{Type: AssertSyncCount, SyncID: "sync-reserve", Count: 1},
+	}
+
+	errors := EvaluateAssertions(result, assertions, nil)
+	require.Len(t, errors, 1)
+	assert.Contains(t, errors[0], "sync_count requires database context")
+}
+
+// processTenantCheckout writes a Cart.checkout invocation and completion for
+// tenant acme / user alice, and processes the completion through an engine
+// whose sync rule reserves inventory, so Inventory.reserve is generated.
+func processTenantCheckout(t *testing.T, st *store.Store) {
+	t.Helper()
+	ctx := context.Background()
+	tenant := ir.NewSecurityContext("acme", "alice")
+
+	args := ir.IRObject{}
+	invID := ir.MustInvocationID(provenanceFlow, "Cart.checkout", args, 1)
+	require.NoError(t, st.WriteInvocation(ctx, ir.Invocation{
+		ID:              invID,
+		FlowToken:       provenanceFlow,
+		ActionURI:       "Cart.checkout",
+		Args:            args,
+		Seq:             1,
+		SecurityContext: tenant,
+		EngineVersion:   ir.EngineVersion,
+		IRVersion:       ir.IRVersion,
+	}))
+
+	result := ir.IRObject{}
+	comp := ir.Completion{
+		ID:              ir.MustCompletionID(invID, "Success", result, 2),
+		InvocationID:    invID,
+		OutputCase:      "Success",
+		Result:          result,
+		Seq:             2,
+		SecurityContext: tenant,
+	}
+	require.NoError(t, st.WriteCompletion(ctx, comp))
+
+	eng := engine.NewWithClock(st, nil, []ir.SyncRule{{
+		ID:   "reserve-on-checkout",
+		When: ir.WhenClause{ActionRef: "Cart.checkout", EventType: "completed", OutputCase: "Success"},
+		Then: ir.ThenClause{ActionRef: "Inventory.reserve", Args: map[string]string{}},
+	}}, nil, engine.NewClockAt(2))
+	require.NoError(t, eng.ProcessCompletion(ctx, &comp))
+}
+
+func TestAssertInvocationSecurity_GeneratedInvocationInheritsTenant(t *testing.T) {
+	st := setupTestStore(t)
+	processTenantCheckout(t, st)
+
+	assertion := Assertion{Type: AssertInvocationSecurity, Action: "Inventory.reserve", TenantID: "acme", UserID: "alice"}
+	err := assertInvocationSecurity(context.Background(), st, provenanceFlow, assertion)
+	assert.NoError(t, err)
+}
+
+func TestAssertInvocationSecurity_Mismatch(t *testing.T) {
+	st := setupTestStore(t)
+	processTenantCheckout(t, st)
+
+	assertion := Assertion{Type: AssertInvocationSecurity, Action: "Inventory.reserve", TenantID: "globex", UserID: "alice"}
+	err := assertInvocationSecurity(context.Background(), st, provenanceFlow, assertion)
+	require.Error(t, err)
+
+	assertErr, ok := err.(*AssertionError)
+	require.True(t, ok)
+	assert.Equal(t, "invocation_security", assertErr.Type)
+	assert.Contains(t, assertErr.Expected, `tenant "globex", user "alice"`)
+	assert.Contains(t, assertErr.Actual, `has tenant "acme", user "alice"`)
+}
+
+func TestAssertInvocationSecurity_NoInvocations(t *testing.T) {
+	st := setupTestStore(t)
+	processTenantCheckout(t, st)
+
+	assertion := Assertion{Type: AssertInvocationSecurity, Action: "Payment.charge", TenantID: "acme", UserID: "alice"}
+	err := assertInvocationSecurity(context.Background(), st, provenanceFlow, assertion)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no invocation of Payment.charge")
+}
+
+func TestEvaluateAssertions_InvocationSecurityRequiresContext(t *testing.T) {
+	result := &Result{Trace: []TraceEvent{}}
+	assertions := []Assertion{
+		{Type: AssertInvocationSecurity, Action: "Inventory.reserve", TenantID: "acme", UserID: "alice"},
+	}
+
+	errors := EvaluateAssertions(result, assertions, nil)
+	require.Len(t, errors, 1)
+	assert.Contains(t, errors[0], "invocation_security requires database context")
+}
+
+func TestAssertFlowComplete_Complete(t *testing.T) {
+	st := setupTestStore(t)
+	checkout := writeProvenanceInvocation(t, st, "Cart.checkout", 1)
+	comp := writeProvenanceCompletion(t, st, checkout, 2)
+	reserve := fireProvenanceSync(t, st, comp, "reserve-on-checkout", "Inventory.reserve", 3)
+	writeProvenanceCompletion(t, st, reserve, 4)
+
+	err := assertFlowComplete(context.Background(), st, provenanceFlow)
+	assert.NoError(t, err)
+}
+
+func TestAssertFlowComplete_PendingInvocation(t *testing.T) {
+	st := setupTestStore(t)
+	processTenantCheckout(t, st) // Inventory.reserve is generated but never completes
+
+	err := assertFlowComplete(context.Background(), st, provenanceFlow)
+	require.Error(t, err)
+
+	assertErr, ok := err.(*AssertionError)
+	require.True(t, ok)
+	assert.Equal(t, "flow_complete", assertErr.Type)
+	assert.Equal(t, "1 pending invocations, 0 orphaned firings", assertErr.Actual)
+}
+
+func TestAssertFlowComplete_OrphanedFiring(t *testing.T) {
+	st := setupTestStore(t)
+	checkout := writeProvenanceInvocation(t, st, "Cart.checkout", 1)
+	comp := writeProvenanceCompletion(t, st, checkout, 2)
+
+	// Firing recorded, but the engine stopped before writing its invocation
+	_, _, err := st.WriteSyncFiring(context.Background(), ir.SyncFiring{
+		CompletionID: comp.ID,
+		SyncID:       "reserve-on-checkout",
+		BindingHash:  "binding-reserve-on-checkout",
+		Seq:          3,
+	})
+	require.NoError(t, err)
+
+	err = assertFlowComplete(context.Background(), st, provenanceFlow)
+	require.Error(t, err)
+
+	assertErr, ok := err.(*AssertionError)
+	require.True(t, ok)
+	assert.Equal(t, "0 pending invocations, 1 orphaned firings", assertErr.Actual)
+}
+
+func TestAssertFlowComplete_ScheduledFiring(t *testing.T) {
+	st := setupTestStore(t)
+	checkout := writeProvenanceInvocation(t, st, "Cart.checkout", 1)
+	comp := writeProvenanceCompletion(t, st, checkout, 2)
+
+	// A deferred firing the engine has not released yet
+	_, _, err := st.WriteScheduledFiring(context.Background(), store.ScheduledFiring{
+		CompletionID: comp.ID,
+		SyncID:       "remind-after-checkout",
+		BindingHash:  "binding-remind-after-checkout",
+		FlowToken:    provenanceFlow,
+		TargetSeq:    5,
+	})
+	require.NoError(t, err)
+
+	err = assertFlowComplete(context.Background(), st, provenanceFlow)
+	require.Error(t, err)
+
+	assertErr, ok := err.(*AssertionError)
+	require.True(t, ok)
+	assert.Equal(t, "0 pending invocations, 0 orphaned firings, 1 scheduled firings", assertErr.Actual)
+}
+
+func TestAssertFlowComplete_EmptyFlow(t *testing.T) {
+	st := setupTestStore(t)
+
+	err := assertFlowComplete(context.Background(), st, provenanceFlow)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no invocations in flow "+provenanceFlow)
+}
+
+func TestEvaluateAssertions_FlowCompleteRequiresContext(t *testing.T) {
+	result := &Result{Trace: []TraceEvent{}}
+	assertions := []Assertion{{Type: AssertFlowComplete}}
+
+	errors := EvaluateAssertions(result, assertions, nil)
+	require.Len(t, errors, 1)
+	assert.Contains(t, errors[0], "flow_complete requires database context")
+}
+
+// writeResultCompletion writes an invocation of action with args and its
+// Success completion with result, returning the completion.
+func writeResultCompletion(t *testing.T, st *store.Store, action string, args, result ir.IRObject, seq int64) ir.Completion {
+	t.Helper()
+	inv := ir.Invocation{
+		ID:              ir.MustInvocationID(provenanceFlow, action, args, seq),
+		FlowToken:       provenanceFlow,
+		ActionURI:       ir.ActionRef(action),
+		Args:            args,
+		Seq:             seq,
+		SecurityContext: testSecurityContext,
+		SpecHash:        "test-spec-hash",
+		EngineVersion:   "test",
+		IRVersion:       ir.IRVersion,
+	}
+	require.NoError(t, st.WriteInvocation(context.Background(), inv))
+
+	comp := ir.Completion{
+		ID:              ir.MustCompletionID(inv.ID, "Success", result, seq+1),
+		InvocationID:    inv.ID,
+		OutputCase:      "Success",
+		Result:          result,
+		Seq:             seq + 1,
+		SecurityContext: testSecurityContext,
+	}
+	require.NoError(t, st.WriteCompletion(context.Background(), comp))
+	return comp
+}
+
+func seedReservations(t *testing.T, st *store.Store) {
+	t.Helper()
+	writeResultCompletion(t, st, "Inventory.reserve",
+		ir.IRObject{"item_id": ir.IRString("widget")},
+		ir.IRObject{"reservation_id": ir.IRString("r-1"), "quantity": ir.IRInt(2)}, 1)
+	writeResultCompletion(t, st, "Inventory.reserve",
+		ir.IRObject{"item_id": ir.IRString("gadget")},
+		ir.IRObject{"reservation_id": ir.IRString("r-2"), "quantity": ir.IRInt(5)}, 3)
+}
+
+func TestAssertCompletionResult_Match(t *testing.T) {
+	st := setupTestStore(t)
+	seedReservations(t, st)
+
+	// Subset match: quantity is not checked
+	assertion := Assertion{
+		Type:   AssertCompletionResult,
+		Action: "Inventory.reserve",
+		Expect: map[string]interface{}{"reservation_id": "r-2"},
+	}
+	assert.NoError(t, assertCompletionResult(context.Background(), st, provenanceFlow, assertion))
+
+	// where selects the invocation by its args
+	assertion.Where = map[string]interface{}{"item_id": "gadget"}
+	assertion.Expect = map[string]interface{}{"reservation_id": "r-2", "quantity": 5}
+	assert.NoError(t, assertCompletionResult(context.Background(), st, provenanceFlow, assertion))
+}
+
+func TestAssertCompletionResult_Mismatch(t *testing.T) {
+	st := setupTestStore(t)
+	seedReservations(t, st)
+
+	assertion := Assertion{
+		Type:   AssertCompletionResult,
+		Action: "Inventory.reserve",
+		Where:  map[string]interface{}{"item_id": "widget"},
+		Expect: map[string]interface{}{"reservation_id": "r-2"},
+	}
+	err := assertCompletionResult(context.Background(), st, provenanceFlow, assertion)
+	require.Error(t, err)
+
+	assertErr, ok := err.(*AssertionError)
+	require.True(t, ok)
+	assert.Equal(t, "completion_result", assertErr.Type)
+	assert.Contains(t, assertErr.Expected, "completion of Inventory.reserve where item_id=widget")
+	assert.Contains(t, assertErr.Actual, "[seq 2] Success")
+	assert.Contains(t, assertErr.Actual, "r-1")
+	assert.NotContains(t, assertErr.Actual, "r-2", "only the selected invocation's completion is reported")
+}
+
+func TestAssertCompletionResult_NotFound(t *testing.T) {
+	st := setupTestStore(t)
+	seedReservations(t, st)
+	writeProvenanceInvocation(t, st, "Payment.charge", 5) // Never completes
+
+	tests := []struct {
+		name       string
+		assertion  Assertion
+		wantActual string
+	}{
+		{
+			name:       "no_invocation",
+			assertion:  Assertion{Action: "Cart.checkout", Expect: map[string]interface{}{"ok": true}},
+			wantActual: "no matching invocation of Cart.checkout",
+		},
+		{
+			name: "where_matches_nothing",
+			assertion: Assertion{
+				Action: "Inventory.reserve",
+				Where:  map[string]interface{}{"item_id": "gizmo"},
+				Expect: map[string]interface{}{"quantity": 2},
+			},
+			wantActual: "no matching invocation of Inventory.reserve",
+		},
+		{
+			name:       "no_completion",
+			assertion:  Assertion{Action: "Payment.charge", Expect: map[string]interface{}{"ok": true}},
+			wantActual: "no completion of Payment.charge",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.assertion.Type = AssertCompletionResult
+			err := assertCompletionResult(context.Background(), st, provenanceFlow, tt.assertion)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantActual)
+		})
+	}
+}
+
+func TestEvaluateAssertions_CompletionResultRequiresContext(t *testing.T) {
+	result := &Result{Trace: []TraceEvent{}}
+	assertions := []Assertion{
+		{Type: AssertCompletionResult, Action: "Inventory.reserve", Expect: map[string]interface{}{"ok": true}},
+	}
+
+	errors := EvaluateAssertions(result, assertions, nil)
+	require.Len(t, errors, 1)
+	assert.Contains(t, errors[0], "completion_result requires database context")
+}
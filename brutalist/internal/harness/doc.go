@@ -30,6 +30,20 @@
 //	    where: { id: "123" }
 //	    expect: { status: "completed" }
 //
+// # Matrix Scenarios
+//
+// An optional matrix section turns a scenario into a template. Each
+// combination of values becomes a concrete scenario (LoadScenarios,
+// ExpandMatrix), with ${param.name} references substituted:
+//
+//	matrix:
+//	  quantity: [1, 5, 10]
+//	flow:
+//	  - invoke: Cart.addItem
+//	    args: { item_id: "widget", quantity: "${param.quantity}" }
+//
+// Every ${param.*} reference must have a matrix entry.
+//
 // # Assertion Types
 //
 // The following assertion types are supported:
@@ -37,7 +51,20 @@
 //   - trace_contains: Verifies an action appears in the trace with matching args
 //   - trace_order: Verifies actions appear in specified order
 //   - trace_count: Verifies an action appears exactly N times
+//   - seq_before: Verifies every invocation of one action has a lower seq
+//     than every invocation of another (stricter than trace_order)
 //   - final_state: Queries a state table and verifies expected values
+//   - sync_count: Verifies a sync rule fired exactly N times (idempotent
+//     and cycle-rejected matches record no firing)
+//   - invocation_security: Verifies persisted invocations of an action carry
+//     the expected tenant and user
+//   - flow_complete: Verifies the flow has no pending invocations and no
+//     orphaned sync firings
+//   - completion_result: Verifies a completion of an action (optionally
+//     selected by invocation args in where) has the expected result fields
+//
+// A flow step may set flow_token to run in its own flow, and an assertion
+// may set flow_token to evaluate against that flow's events only.
 //
 // # Deterministic Testing
 //
@@ -51,6 +78,10 @@
 //
 // This ensures identical traces across runs for golden file comparison.
 //
+// Traces record seq values. The first event has seq 1 unless the scenario
+// sets clock_start (the seq of the first event), so golden files depend on
+// that setting.
+//
 // # Usage
 //
 // Load a scenario:
@@ -68,4 +99,19 @@
 //	        log.Println(err)
 //	    }
 //	}
+//
+// Register handlers so setup and flow actions mutate state tables, making
+// final_state assertions meaningful end to end:
+//
+//	actions := harness.NewActionRegistry()
+//	actions.Register("Inventory.setStock", func(ctx context.Context, args ir.IRObject) error {
+//	    db := harness.StoreFromContext(ctx).DB()
+//	    // ... write the stock row
+//	    return nil
+//	})
+//	result, err := harness.RunWithActions(scenario, actions)
+//
+// Run a batch of scenario files concurrently, each in its own store:
+//
+//	reports := harness.RunAll(ctx, paths, harness.RunAllOptions{Concurrency: 4})
 package harness
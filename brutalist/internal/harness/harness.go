@@ -57,6 +57,10 @@ import (
 	"github.com/roach88/nysm/internal/testutil"
 )
 
+// harnessSecurityContext is attached to every record the harness writes.
+// Scenarios don't model authz yet, but CP-6 requires an identified actor.
+var harnessSecurityContext = ir.NewSecurityContext("test", "harness")
+
 // Harness is the test execution engine.
 // It runs scenarios with deterministic clock and flow tokens.
 //
@@ -64,11 +68,12 @@ import (
 // documentation for the "Tautology Risk" limitation and Epic 7 integration plans.
 type Harness struct {
 	store   *store.Store
-	engine  *engine.Engine // TODO(Epic-7): Currently unused; will be used for engine.Enqueue() integration
+	engine  *engine.Engine // Processes flow completions; TODO(Epic-7): engine.Enqueue() integration
 	clock   *testutil.DeterministicClock
 	flowGen *testutil.FixedFlowGenerator
 	logger  *slog.Logger
 	specHash string // Hash of concept specs (for invocations)
+	actions *ActionRegistry // Optional handlers for setup and flow actions
 }
 
 // Run executes a test scenario and returns the result.
@@ -79,10 +84,34 @@ type Harness struct {
 // Execution flow:
 // 1. Create fresh in-memory database
 // 2. Load and compile concept specs and sync rules
-// 3. Execute setup steps
+// 3. Execute fixture setup steps, then scenario setup steps
 // 4. Execute flow steps with expect validation
 // 5. Return result with pass/fail, trace, and errors
 func Run(scenario *Scenario) (*Result, error) {
+	return RunWithActions(scenario, nil)
+}
+
+// RunWithActions executes a scenario like Run, invoking the registered
+// handler for each setup and flow step whose action has one. Handlers run
+// after the step's invocation is written and before its completion, so
+// final_state assertions see the state they produced. A nil registry
+// behaves like Run.
+func RunWithActions(scenario *Scenario, actions *ActionRegistry) (*Result, error) {
+	// TODO: Epic 7 - Load and compile specs from scenario.Specs
+	// Currently using empty syncs; real integration requires spec parsing
+	return run(scenario, []ir.SyncRule{}, actions)
+}
+
+// runWithSyncs executes a scenario with the given sync rules registered on
+// the engine. Flow step completions are processed by the engine, so sync
+// firings and runtime errors (cycles, quota) are real even though the
+// completions themselves are still manufactured from expect clauses.
+func runWithSyncs(scenario *Scenario, syncs []ir.SyncRule) (*Result, error) {
+	return run(scenario, syncs, nil)
+}
+
+// run executes a scenario with the given sync rules and action handlers.
+func run(scenario *Scenario, syncs []ir.SyncRule, actions *ActionRegistry) (*Result, error) {
 	// Create fresh in-memory SQLite database
 	st, err := store.Open(":memory:")
 	if err != nil {
@@ -90,18 +119,25 @@ func Run(scenario *Scenario) (*Result, error) {
 	}
 	defer st.Close()
 
-	// Initialize deterministic helpers
+	// Initialize deterministic helpers. With a clock_start, both clocks are
+	// positioned one before it so the first event gets that seq.
 	clock := testutil.NewDeterministicClock()
+	engineClock := engine.NewClock()
+	if scenario.ClockStart != nil {
+		clock = testutil.NewDeterministicClockAt(*scenario.ClockStart - 1)
+		engineClock = engine.NewClockAt(*scenario.ClockStart - 1)
+	}
 	flowGen := testutil.NewFixedFlowGenerator(scenario.FlowToken)
 
-	// TODO: Epic 7 - Load and compile specs from scenario.Specs
-	// Currently using empty specs; real integration requires spec parsing
 	specs := []ir.ConceptSpec{}
-	syncs := []ir.SyncRule{}
 	specHash := "test-spec-hash"
 
-	// Create engine with test flow generator
-	eng := engine.New(st, specs, syncs, flowGen)
+	// Create engine with test flow generator and the scenario's quota override
+	var opts []engine.EngineOption
+	if scenario.MaxSteps != nil {
+		opts = append(opts, engine.WithMaxSteps(*scenario.MaxSteps))
+	}
+	eng := engine.NewWithClock(st, specs, syncs, flowGen, engineClock, opts...)
 
 	// Initialize harness
 	h := &Harness{
@@ -111,13 +147,18 @@ func Run(scenario *Scenario) (*Result, error) {
 		flowGen:  flowGen,
 		logger:   slog.New(slog.NewTextHandler(io.Discard, nil)), // Suppress logs in tests
 		specHash: specHash,
+		actions:  actions,
 	}
 
-	ctx := context.Background()
+	// Action handlers reach this run's store through the context
+	ctx := context.WithValue(context.Background(), storeKey{}, st)
 
 	// Execute setup steps
 	result := NewResult()
-	if err := h.executeSetup(ctx, scenario.Setup, result); err != nil {
+	result.Scenario = scenario.Name
+	// Fixture setup runs first, in declared order, under the same clock
+	setup := append(append([]ActionStep{}, scenario.FixtureSetup...), scenario.Setup...)
+	if err := h.executeSetup(ctx, setup, result); err != nil {
 		return nil, fmt.Errorf("failed to execute setup: %w", err)
 	}
 
@@ -128,12 +169,16 @@ func Run(scenario *Scenario) (*Result, error) {
 
 	// Evaluate assertions against the result
 	actx := &AssertionContext{
-		Store: st,
-		Ctx:   ctx,
+		Store:     st,
+		Ctx:       ctx,
+		FlowToken: flowGen.Generate(),
 	}
-	assertionErrors := EvaluateAssertions(result, scenario.Assertions, actx)
-	for _, errMsg := range assertionErrors {
-		result.AddError(errMsg)
+	result.assertionErrs = evaluateAssertions(result, scenario.Assertions, actx)
+	for _, err := range result.assertionErrs {
+		if err == nil {
+			continue
+		}
+		result.AddError(err.Error())
 	}
 
 	return result, nil
@@ -170,7 +215,7 @@ func (h *Harness) executeSetup(ctx context.Context, setup []ActionStep, result *
 			ActionURI:       ir.ActionRef(step.Action),
 			Args:            args,
 			Seq:             invSeq,
-			SecurityContext: ir.SecurityContext{},
+			SecurityContext: harnessSecurityContext,
 			SpecHash:        h.specHash,
 			EngineVersion:   "test",
 			IRVersion:       ir.IRVersion,
@@ -182,10 +227,15 @@ func (h *Harness) executeSetup(ctx context.Context, setup []ActionStep, result *
 		}
 
 		// Add to trace
-		result.AddInvocationTrace(step.Action, step.Args, invSeq)
+		result.AddInvocationTrace(flowToken, step.Action, step.Args, invSeq)
 
-		// Execute action (stub for MVP - Epic 7 will implement actual execution)
-		// Setup steps always succeed; real handlers needed for failure scenarios
+		// Execute the registered handler, if any. Setup steps are assumed to
+		// succeed, so a handler failure aborts the run.
+		if handler, ok := h.actions.Lookup(inv.ActionURI); ok {
+			if err := handler(ctx, args); err != nil {
+				return fmt.Errorf("setup step %d (%s): action handler: %w", i, step.Action, err)
+			}
+		}
 
 		// Get completion seq ONCE
 		compSeq := h.clock.Next()
@@ -202,7 +252,7 @@ func (h *Harness) executeSetup(ctx context.Context, setup []ActionStep, result *
 			OutputCase:      "Success",
 			Result:          compResult,
 			Seq:             compSeq,
-			SecurityContext: ir.SecurityContext{},
+			SecurityContext: harnessSecurityContext,
 		}
 
 		if err := h.store.WriteCompletion(ctx, comp); err != nil {
@@ -210,7 +260,7 @@ func (h *Harness) executeSetup(ctx context.Context, setup []ActionStep, result *
 		}
 
 		// Add to trace
-		result.AddCompletionTrace("Success", nil, compSeq)
+		result.AddCompletionTrace(flowToken, "Success", nil, compSeq)
 
 		h.logger.Info("setup step completed",
 			"step", i,
@@ -232,10 +282,13 @@ func (h *Harness) executeSetup(ctx context.Context, setup []ActionStep, result *
 // Each step:
 // 1. Generates invocation with deterministic ID (content-addressed)
 // 2. Writes invocation to store (bypasses engine.Enqueue)
-// 3. Manufactures completion from expect clause (NOT from engine execution)
-// 4. Writes completion to store
-// 5. Validates expect clause (always passes since completion = expect)
-// 6. Builds trace for golden file comparison
+// 3. Runs the registered action handler, if any (see ActionRegistry)
+// 4. Manufactures completion from expect clause (NOT from engine execution),
+//    or an "Error" completion if the handler failed
+// 5. Processes completion through the engine (writes it, evaluates syncs)
+// 6. Validates expect clause (always passes since completion = expect),
+//    or matches expect_error against the engine's typed runtime error
+// 7. Builds trace for golden file comparison
 func (h *Harness) executeFlow(ctx context.Context, flow []FlowStep, result *Result) error {
 	for i, step := range flow {
 		// Convert args to IRObject
@@ -245,7 +298,11 @@ func (h *Harness) executeFlow(ctx context.Context, flow []FlowStep, result *Resu
 		}
 
 		// Generate flow token and seq ONCE (CRITICAL: avoid double clock.Next())
-		flowToken := h.flowGen.Generate()
+		// A step-level flow token places the step in its own flow
+		flowToken := step.FlowToken
+		if flowToken == "" {
+			flowToken = h.flowGen.Generate()
+		}
 		invSeq := h.clock.Next()
 
 		// Compute ID using Story 1.5 signature (SecurityContext excluded per CP-6)
@@ -260,7 +317,7 @@ func (h *Harness) executeFlow(ctx context.Context, flow []FlowStep, result *Resu
 			ActionURI:       ir.ActionRef(step.Invoke),
 			Args:            args,
 			Seq:             invSeq,
-			SecurityContext: ir.SecurityContext{},
+			SecurityContext: harnessSecurityContext,
 			SpecHash:        h.specHash,
 			EngineVersion:   "test",
 			IRVersion:       ir.IRVersion,
@@ -272,7 +329,7 @@ func (h *Harness) executeFlow(ctx context.Context, flow []FlowStep, result *Resu
 		}
 
 		// Add to trace
-		result.AddInvocationTrace(step.Invoke, step.Args, invSeq)
+		result.AddInvocationTrace(flowToken, step.Invoke, step.Args, invSeq)
 
 		// TODO: Epic 7 - Replace this stub with actual engine integration:
 		//   1. eng.Enqueue(inv) to submit to engine
@@ -281,6 +338,13 @@ func (h *Harness) executeFlow(ctx context.Context, flow []FlowStep, result *Resu
 		//   4. Compare actual vs expected (currently they're identical by construction)
 		// For now, manufacture completion from expect clause (TAUTOLOGY - see package docs)
 
+		// Run the registered handler, if any. A failing handler completes
+		// the step as "Error", which is only expected if the step says so.
+		var handlerErr error
+		if handler, ok := h.actions.Lookup(inv.ActionURI); ok {
+			handlerErr = handler(ctx, args)
+		}
+
 		// Determine expected output case (default: "Success")
 		expectedCase := "Success"
 		if step.Expect != nil {
@@ -299,6 +363,21 @@ func (h *Harness) executeFlow(ctx context.Context, flow []FlowStep, result *Resu
 			}
 		}
 
+		var traceResult interface{}
+		if step.Expect != nil {
+			traceResult = step.Expect.Result
+		}
+
+		if handlerErr != nil {
+			if expectedCase != handlerErrorCase {
+				result.AddError(fmt.Sprintf("flow step %d (%s): action handler failed: %v",
+					i, step.Invoke, handlerErr))
+			}
+			expectedCase = handlerErrorCase
+			compResult = ir.IRObject{"error": ir.IRString(handlerErr.Error())}
+			traceResult = map[string]interface{}{"error": handlerErr.Error()}
+		}
+
 		compID, err := ir.CompletionID(inv.ID, expectedCase, compResult, compSeq)
 		if err != nil {
 			return fmt.Errorf("flow step %d: failed to compute completion ID: %w", i, err)
@@ -310,19 +389,33 @@ func (h *Harness) executeFlow(ctx context.Context, flow []FlowStep, result *Resu
 			OutputCase:      expectedCase,
 			Result:          compResult,
 			Seq:             compSeq,
-			SecurityContext: ir.SecurityContext{},
+			SecurityContext: harnessSecurityContext,
 		}
 
-		if err := h.store.WriteCompletion(ctx, comp); err != nil {
-			return fmt.Errorf("flow step %d: failed to write completion: %w", i, err)
+		// The engine writes the completion and evaluates sync rules, surfacing
+		// typed runtime errors for expect_error steps
+		procErr := h.engine.ProcessCompletion(ctx, &comp)
+		errCode := engine.ErrorCode(procErr)
+		if procErr != nil && errCode == "" {
+			return fmt.Errorf("flow step %d: failed to process completion: %w", i, procErr)
 		}
 
-		// Add to trace
-		var traceResult interface{}
-		if step.Expect != nil {
-			traceResult = step.Expect.Result
+		if step.ExpectError != nil {
+			if string(errCode) != step.ExpectError.Code {
+				actual := "no error"
+				if procErr != nil {
+					actual = procErr.Error()
+				}
+				result.AddError(fmt.Sprintf("flow step %d (%s): expected error %s, got %s",
+					i, step.Invoke, step.ExpectError.Code, actual))
+			}
+		} else if procErr != nil {
+			result.AddError(fmt.Sprintf("flow step %d (%s): unexpected engine error: %v",
+				i, step.Invoke, procErr))
 		}
-		result.AddCompletionTrace(comp.OutputCase, traceResult, compSeq)
+
+		// Add to trace
+		result.AddCompletionTrace(flowToken, comp.OutputCase, traceResult, compSeq)
 
 		// Validate against expect clause
 		if step.Expect != nil {
@@ -351,68 +444,44 @@ func (h *Harness) executeFlow(ctx context.Context, flow []FlowStep, result *Resu
 }
 
 // convertArgsToIRObject converts a map[string]interface{} to ir.IRObject.
-// This handles YAML-parsed values and converts them to proper IRValue types.
+// This handles YAML/JSON-parsed values via ir.FromGo, after converting
+// whole-number float64s (how JSON decodes integers) to int64. Other floats
+// and nulls are rejected by ir.FromGo.
 func convertArgsToIRObject(args map[string]interface{}) (ir.IRObject, error) {
 	if args == nil {
 		return ir.IRObject{}, nil
 	}
 
-	result := make(ir.IRObject)
-	for key, val := range args {
-		irVal, err := convertToIRValue(val)
-		if err != nil {
-			return nil, fmt.Errorf("field %q: %w", key, err)
-		}
-		result[key] = irVal
+	val, err := ir.FromGo(wholeFloatsToInts(args))
+	if err != nil {
+		return nil, err
 	}
-	return result, nil
+	return val.(ir.IRObject), nil
 }
 
-// convertToIRValue converts a YAML-parsed value to an IRValue.
-// Returns an error for null values since they are forbidden in canonical JSON
-// and would fail later during ID computation (ir.MarshalCanonical rejects nulls).
-func convertToIRValue(val interface{}) (ir.IRValue, error) {
-	if val == nil {
-		// Reject nulls early with a clear error message.
-		// YAML `null` or `~` would pass through here but fail during
-		// canonical JSON serialization for content-addressed IDs.
-		return nil, fmt.Errorf("null values are forbidden in IR (canonical JSON does not support null)")
-	}
-
+// wholeFloatsToInts returns a copy of a decoded value with every
+// integer-valued float64 replaced by int64. Fractional floats are kept so
+// ir.FromGo reports them (CP-5).
+func wholeFloatsToInts(val interface{}) interface{} {
 	switch v := val.(type) {
-	case string:
-		return ir.IRString(v), nil
-	case int:
-		return ir.IRInt(int64(v)), nil
-	case int64:
-		return ir.IRInt(v), nil
 	case float64:
-		// YAML parses all numbers as float64
-		// Check if it's actually an integer (floats forbidden in IR per CP-5)
 		if v == float64(int64(v)) {
-			return ir.IRInt(int64(v)), nil
+			return int64(v)
 		}
-		// Floats are forbidden in IR (CP-5)
-		return nil, fmt.Errorf("floats are forbidden in IR (CP-5): %v", v)
-	case bool:
-		return ir.IRBool(v), nil
+		return v
 	case []interface{}:
-		arr := make(ir.IRArray, len(v))
+		out := make([]interface{}, len(v))
 		for i, elem := range v {
-			irElem, err := convertToIRValue(elem)
-			if err != nil {
-				return nil, fmt.Errorf("array[%d]: %w", i, err)
-			}
-			arr[i] = irElem
+			out[i] = wholeFloatsToInts(elem)
 		}
-		return arr, nil
+		return out
 	case map[string]interface{}:
-		obj, err := convertArgsToIRObject(v)
-		if err != nil {
-			return nil, err
+		out := make(map[string]interface{}, len(v))
+		for k, elem := range v {
+			out[k] = wholeFloatsToInts(elem)
 		}
-		return obj, nil
+		return out
 	default:
-		return nil, fmt.Errorf("unsupported type %T", val)
+		return val
 	}
 }
@@ -1,8 +1,12 @@
 package harness
 
 import (
+	"context"
+	"fmt"
 	"testing"
 
+	"github.com/roach88/nysm/internal/engine"
+	"github.com/roach88/nysm/internal/ir"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -160,6 +164,432 @@ func TestRun_WithErrorExpect(t *testing.T) {
 	assert.Equal(t, "InsufficientStock", result.Trace[1].OutputCase)
 }
 
+// selfReferentialSync fires Counter.tick whenever Counter.tick completes.
+// With no bindings every firing has the same binding hash, so the second
+// completion in a flow is a cycle.
+func selfReferentialSync() ir.SyncRule {
+	return ir.SyncRule{
+		ID: "tick-again",
+		When: ir.WhenClause{
+			ActionRef: "Counter.tick",
+			EventType: "completed",
+		},
+		Then: ir.ThenClause{
+			ActionRef: "Counter.tick",
+			Args:      map[string]string{},
+		},
+	}
+}
+
+func TestRun_ExpectErrorCycleDetected(t *testing.T) {
+	scenario := &Scenario{
+		Name:        "self_referential_cycle",
+		Description: "Self-referential sync is rejected with CYCLE_DETECTED",
+		Specs:       []string{},
+		FlowToken:   "test-flow-cycle",
+		Flow: []FlowStep{
+			{Invoke: "Counter.tick", Args: map[string]interface{}{}},
+			{
+				Invoke:      "Counter.tick",
+				Args:        map[string]interface{}{},
+				ExpectError: &ExpectErrorClause{Code: "CYCLE_DETECTED"},
+			},
+		},
+		Assertions: []Assertion{
+			{Type: AssertTraceCount, Action: "Counter.tick", Count: 2},
+		},
+	}
+
+	result, err := runWithSyncs(scenario, []ir.SyncRule{selfReferentialSync()})
+	require.NoError(t, err)
+	assert.True(t, result.Pass, "errors: %v", result.Errors)
+}
+
+func TestRun_SyncCountDuplicateCompletionsFireOnce(t *testing.T) {
+	scenario := &Scenario{
+		Name:        "sync_count_once",
+		Description: "Two matching completions, but the sync fires only once",
+		Specs:       []string{},
+		FlowToken:   "test-flow-sync-count",
+		Flow: []FlowStep{
+			{Invoke: "Counter.tick", Args: map[string]interface{}{}},
+			{
+				Invoke:      "Counter.tick",
+				Args:        map[string]interface{}{},
+				ExpectError: &ExpectErrorClause{Code: "CYCLE_DETECTED"},
+			},
+		},
+		Assertions: []Assertion{
+			{Type: AssertTraceCount, Action: "Counter.tick", Count: 2},
+			{Type: AssertSyncCount, SyncID: "tick-again", Count: 1},
+		},
+	}
+
+	result, err := runWithSyncs(scenario, []ir.SyncRule{selfReferentialSync()})
+	require.NoError(t, err)
+	assert.True(t, result.Pass, "errors: %v", result.Errors)
+}
+
+func TestRun_InvocationSecurityPropagated(t *testing.T) {
+	scenario := &Scenario{
+		Name:        "invocation_security",
+		Description: "Sync-generated invocations carry the harness security context",
+		Specs:       []string{},
+		FlowToken:   "test-flow-invocation-security",
+		Flow: []FlowStep{
+			{Invoke: "Counter.tick", Args: map[string]interface{}{}},
+		},
+		Assertions: []Assertion{
+			{Type: AssertSyncCount, SyncID: "tick-again", Count: 1},
+			{
+				Type:     AssertInvocationSecurity,
+				Action:   "Counter.tick",
+				TenantID: harnessSecurityContext.TenantID,
+				UserID:   harnessSecurityContext.UserID,
+			},
+		},
+	}
+
+	result, err := runWithSyncs(scenario, []ir.SyncRule{selfReferentialSync()})
+	require.NoError(t, err)
+	assert.True(t, result.Pass, "errors: %v", result.Errors)
+}
+
+// interleavedFlowsScenario adds items to two carts in interleaved flows:
+// flow-a gets two items, flow-b one, in the order a, b, a.
+func interleavedFlowsScenario(assertions ...Assertion) *Scenario {
+	return &Scenario{
+		Name:        "interleaved_flows",
+		Description: "Assertions scoped to one of two interleaved flows",
+		Specs:       []string{},
+		FlowToken:   "flow-a",
+		Flow: []FlowStep{
+			{Invoke: "Cart.addItem", Args: map[string]interface{}{"item_id": "widget"}},
+			{Invoke: "Cart.addItem", Args: map[string]interface{}{"item_id": "gadget"}, FlowToken: "flow-b"},
+			{Invoke: "Cart.checkout", Args: map[string]interface{}{}},
+		},
+		Assertions: assertions,
+	}
+}
+
+func TestRun_FlowScopedAssertions(t *testing.T) {
+	scenario := interleavedFlowsScenario(
+		Assertion{Type: AssertTraceCount, Action: "Cart.addItem", Count: 2},
+		Assertion{Type: AssertTraceCount, Action: "Cart.addItem", Count: 1, FlowToken: "flow-a"},
+		Assertion{Type: AssertTraceCount, Action: "Cart.addItem", Count: 1, FlowToken: "flow-b"},
+		Assertion{Type: AssertTraceCount, Action: "Cart.checkout", Count: 0, FlowToken: "flow-b"},
+		Assertion{Type: AssertTraceContains, Action: "Cart.addItem", Args: map[string]interface{}{"item_id": "gadget"}, FlowToken: "flow-b"},
+		Assertion{Type: AssertTraceNotContains, Action: "Cart.addItem", Args: map[string]interface{}{"item_id": "gadget"}, FlowToken: "flow-a"},
+		Assertion{Type: AssertTraceOrder, Actions: []string{"Cart.addItem", "Cart.checkout"}, FlowToken: "flow-a"},
+	)
+
+	result, err := Run(scenario)
+	require.NoError(t, err)
+	assert.True(t, result.Pass, "errors: %v", result.Errors)
+
+	require.Len(t, result.Trace, 6)
+	flows := make([]string, len(result.Trace))
+	for i, event := range result.Trace {
+		flows[i] = event.FlowToken
+	}
+	assert.Equal(t, []string{"flow-a", "flow-a", "flow-b", "flow-b", "flow-a", "flow-a"}, flows)
+}
+
+func TestRun_FlowScopedAssertion_CountMismatch(t *testing.T) {
+	scenario := interleavedFlowsScenario(
+		Assertion{Type: AssertTraceCount, Action: "Cart.addItem", Count: 2, FlowToken: "flow-b"},
+	)
+
+	result, err := Run(scenario)
+	require.NoError(t, err)
+	assert.False(t, result.Pass)
+	require.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0], "trace_count")
+}
+
+func TestRun_FlowScopedAssertion_UnknownFlow(t *testing.T) {
+	scenario := interleavedFlowsScenario(
+		Assertion{Type: AssertTraceCount, Action: "Cart.addItem", Count: 0, FlowToken: "flow-c"},
+	)
+
+	result, err := Run(scenario)
+	require.NoError(t, err)
+	assert.False(t, result.Pass)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, `assertion[0]: flow_token "flow-c" does not appear in the trace`, result.Errors[0])
+}
+
+func TestRun_FlowCompleteAssertion(t *testing.T) {
+	scenario := interleavedFlowsScenario(
+		Assertion{Type: AssertFlowComplete},
+		Assertion{Type: AssertFlowComplete, FlowToken: "flow-b"},
+	)
+
+	result, err := Run(scenario)
+	require.NoError(t, err)
+	assert.True(t, result.Pass, "errors: %v", result.Errors)
+}
+
+func TestRun_FlowCompleteAssertion_PendingInvocation(t *testing.T) {
+	scenario := &Scenario{
+		Name:        "flow_complete_pending",
+		Description: "The sync-generated reserve invocation never completes",
+		Specs:       []string{},
+		FlowToken:   "test-flow-complete-pending",
+		Flow: []FlowStep{
+			{Invoke: "Cart.checkout", Args: map[string]interface{}{}},
+		},
+		Assertions: []Assertion{
+			{Type: AssertFlowComplete},
+		},
+	}
+
+	reserveOnCheckout := ir.SyncRule{
+		ID:   "reserve-on-checkout",
+		When: ir.WhenClause{ActionRef: "Cart.checkout", EventType: "completed"},
+		Then: ir.ThenClause{ActionRef: "Inventory.reserve", Args: map[string]string{}},
+	}
+
+	result, err := runWithSyncs(scenario, []ir.SyncRule{reserveOnCheckout})
+	require.NoError(t, err)
+	assert.False(t, result.Pass)
+	require.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0], "flow_complete")
+	assert.Contains(t, result.Errors[0], "1 pending invocations, 0 orphaned firings")
+}
+
+func TestRun_SyncCountAssertion_Fail(t *testing.T) {
+	scenario := &Scenario{
+		Name:        "sync_count_fail",
+		Description: "sync_count reports the actual number of firings",
+		Specs:       []string{},
+		FlowToken:   "test-flow-sync-count-fail",
+		Flow: []FlowStep{
+			{Invoke: "Counter.tick", Args: map[string]interface{}{}},
+			{
+				Invoke:      "Counter.tick",
+				Args:        map[string]interface{}{},
+				ExpectError: &ExpectErrorClause{Code: "CYCLE_DETECTED"},
+			},
+		},
+		Assertions: []Assertion{
+			{Type: AssertSyncCount, SyncID: "tick-again", Count: 2},
+		},
+	}
+
+	result, err := runWithSyncs(scenario, []ir.SyncRule{selfReferentialSync()})
+	require.NoError(t, err)
+	assert.False(t, result.Pass)
+	require.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0], "2 firings of sync tick-again")
+}
+
+func TestRun_ExpectErrorNotRaised(t *testing.T) {
+	scenario := &Scenario{
+		Name:        "no_cycle",
+		Description: "Expected error that the engine never raises",
+		Specs:       []string{},
+		FlowToken:   "test-flow-no-cycle",
+		Flow: []FlowStep{
+			{
+				Invoke:      "Counter.tick",
+				Args:        map[string]interface{}{},
+				ExpectError: &ExpectErrorClause{Code: "CYCLE_DETECTED"},
+			},
+		},
+		Assertions: []Assertion{
+			{Type: AssertTraceContains, Action: "Counter.tick"},
+		},
+	}
+
+	result, err := runWithSyncs(scenario, []ir.SyncRule{selfReferentialSync()})
+	require.NoError(t, err)
+	assert.False(t, result.Pass)
+	require.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0], "expected error CYCLE_DETECTED, got no error")
+}
+
+func TestRun_UnexpectedEngineError(t *testing.T) {
+	scenario := &Scenario{
+		Name:        "unexpected_cycle",
+		Description: "Cycle without expect_error fails the scenario",
+		Specs:       []string{},
+		FlowToken:   "test-flow-unexpected",
+		Flow: []FlowStep{
+			{Invoke: "Counter.tick", Args: map[string]interface{}{}},
+			{Invoke: "Counter.tick", Args: map[string]interface{}{}},
+		},
+		Assertions: []Assertion{
+			{Type: AssertTraceContains, Action: "Counter.tick"},
+		},
+	}
+
+	result, err := runWithSyncs(scenario, []ir.SyncRule{selfReferentialSync()})
+	require.NoError(t, err)
+	assert.False(t, result.Pass)
+	require.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0], "flow step 1 (Counter.tick): unexpected engine error")
+	assert.Contains(t, result.Errors[0], "CYCLE_DETECTED")
+}
+
+func TestRun_FixtureSetupRunsFirst(t *testing.T) {
+	scenario := &Scenario{
+		Name:        "fixtures",
+		Description: "Fixture setup runs before scenario setup",
+		Specs:       []string{},
+		FlowToken:   "test-flow-fixtures",
+		FixtureSetup: []ActionStep{
+			{Action: "Inventory.setStock", Args: map[string]interface{}{"quantity": 10}},
+		},
+		Setup: []ActionStep{
+			{Action: "Cart.create", Args: map[string]interface{}{}},
+		},
+		Flow: []FlowStep{
+			{Invoke: "Cart.checkout", Args: map[string]interface{}{}},
+		},
+		Assertions: []Assertion{
+			{Type: AssertTraceOrder, Actions: []string{"Inventory.setStock", "Cart.create", "Cart.checkout"}},
+		},
+	}
+
+	result, err := Run(scenario)
+	require.NoError(t, err)
+	assert.True(t, result.Pass, "errors: %v", result.Errors)
+	assert.Equal(t, int64(1), result.Trace[0].Seq)
+	assert.Equal(t, "Inventory.setStock", result.Trace[0].ActionURI)
+}
+
+// fanOutScenario returns a scenario with n flow steps in one flow.
+// Each flow step completion counts as one step against the engine quota.
+func fanOutScenario(n int) *Scenario {
+	flow := make([]FlowStep, n)
+	for i := range flow {
+		flow[i] = FlowStep{
+			Invoke: "Notification.send",
+			Args:   map[string]interface{}{"index": i},
+		}
+	}
+	return &Scenario{
+		Name:        "fan_out",
+		Description: "Many completions in a single flow",
+		Specs:       []string{},
+		FlowToken:   "test-flow-fan-out",
+		Flow:        flow,
+		Assertions: []Assertion{
+			{Type: AssertTraceCount, Action: "Notification.send", Count: n},
+		},
+	}
+}
+
+func TestRun_MaxStepsDefaultExceeded(t *testing.T) {
+	result, err := Run(fanOutScenario(engine.DefaultMaxSteps + 1))
+	require.NoError(t, err)
+	assert.False(t, result.Pass)
+	require.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0], "unexpected engine error")
+	assert.Contains(t, result.Errors[0], "exceeded max steps quota")
+}
+
+func TestRun_MaxStepsRaised(t *testing.T) {
+	scenario := fanOutScenario(engine.DefaultMaxSteps + 1)
+	maxSteps := engine.DefaultMaxSteps + 10
+	scenario.MaxSteps = &maxSteps
+
+	result, err := Run(scenario)
+	require.NoError(t, err)
+	assert.True(t, result.Pass, "errors: %v", result.Errors)
+}
+
+func TestRun_MaxStepsLoweredExpectError(t *testing.T) {
+	scenario := fanOutScenario(3)
+	maxSteps := 2
+	scenario.MaxSteps = &maxSteps
+	scenario.Flow[2].ExpectError = &ExpectErrorClause{Code: "STEPS_EXCEEDED"}
+
+	result, err := Run(scenario)
+	require.NoError(t, err)
+	assert.True(t, result.Pass, "errors: %v", result.Errors)
+}
+
+func TestRun_BindingExplosionExpectError(t *testing.T) {
+	// Cart.seedItems fills CartItems with one row more than the engine's
+	// default cap, so the where clause below fans out past it.
+	actions := NewActionRegistry()
+	require.NoError(t, actions.Register("Cart.seedItems", func(ctx context.Context, args ir.IRObject) error {
+		st := StoreFromContext(ctx)
+		if _, err := st.DB().ExecContext(ctx,
+			`CREATE TABLE CartItems (id TEXT PRIMARY KEY, seq INTEGER, flow_token TEXT, cart_id TEXT, item_id TEXT)`); err != nil {
+			return err
+		}
+		for i := 0; i <= engine.DefaultMaxBindingsPerFiring; i++ {
+			if err := st.ExecState(ctx, "CartItems", ir.IRObject{
+				"id":         ir.IRString(fmt.Sprintf("ci-%d", i)),
+				"seq":        ir.IRInt(i),
+				"flow_token": ir.IRString("test-flow-fan-out"),
+				"cart_id":    ir.IRString("cart-1"),
+				"item_id":    ir.IRString(fmt.Sprintf("item-%d", i)),
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+
+	sync := ir.SyncRule{
+		ID: "reserve-each-item",
+		When: ir.WhenClause{
+			ActionRef: "Cart.checkout",
+			EventType: "completed",
+		},
+		Where: &ir.WhereClause{
+			Source:   "CartItems",
+			Bindings: map[string]string{"item_id": "itemId"},
+		},
+		Then: ir.ThenClause{
+			ActionRef: "Inventory.reserve",
+			Args:      map[string]string{"item": "${bound.itemId}"},
+		},
+	}
+
+	scenario := &Scenario{
+		Name:        "binding_explosion",
+		Description: "Where clause fan-out past the cap is rejected with BINDING_EXPLOSION",
+		Specs:       []string{},
+		FlowToken:   "test-flow-fan-out",
+		Setup:       []ActionStep{{Action: "Cart.seedItems", Args: map[string]interface{}{}}},
+		Flow: []FlowStep{
+			{
+				Invoke:      "Cart.checkout",
+				Args:        map[string]interface{}{},
+				ExpectError: &ExpectErrorClause{Code: "BINDING_EXPLOSION"},
+			},
+		},
+		Assertions: []Assertion{
+			{Type: AssertTraceCount, Action: "Inventory.reserve", Count: 0},
+		},
+	}
+
+	result, err := run(scenario, []ir.SyncRule{sync}, actions)
+	require.NoError(t, err)
+	assert.True(t, result.Pass, "errors: %v", result.Errors)
+}
+
+func TestRun_ClockStart(t *testing.T) {
+	scenario := fanOutScenario(2)
+	clockStart := int64(100)
+	scenario.ClockStart = &clockStart
+
+	result, err := Run(scenario)
+	require.NoError(t, err)
+	assert.True(t, result.Pass, "errors: %v", result.Errors)
+
+	// The first event gets the configured seq; the rest follow it
+	require.Len(t, result.Trace, 4)
+	for i, event := range result.Trace {
+		assert.Equal(t, clockStart+int64(i), event.Seq, "seq at trace index %d", i)
+	}
+}
+
 func TestRun_Deterministic(t *testing.T) {
 	scenario := &Scenario{
 		Name:        "determinism",
@@ -475,15 +905,17 @@ func TestResult_AddTrace(t *testing.T) {
 	result := NewResult()
 	assert.Empty(t, result.Trace)
 
-	result.AddInvocationTrace("Test.action", map[string]interface{}{"key": "val"}, 1)
+	result.AddInvocationTrace("flow-1", "Test.action", map[string]interface{}{"key": "val"}, 1)
 	assert.Len(t, result.Trace, 1)
 	assert.Equal(t, "invocation", result.Trace[0].Type)
+	assert.Equal(t, "flow-1", result.Trace[0].FlowToken)
 	assert.Equal(t, "Test.action", result.Trace[0].ActionURI)
 	assert.Equal(t, int64(1), result.Trace[0].Seq)
 
-	result.AddCompletionTrace("Success", nil, 2)
+	result.AddCompletionTrace("flow-1", "Success", nil, 2)
 	assert.Len(t, result.Trace, 2)
 	assert.Equal(t, "completion", result.Trace[1].Type)
+	assert.Equal(t, "flow-1", result.Trace[1].FlowToken)
 	assert.Equal(t, "Success", result.Trace[1].OutputCase)
 	assert.Equal(t, int64(2), result.Trace[1].Seq)
 }
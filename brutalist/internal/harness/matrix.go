@@ -0,0 +1,256 @@
+package harness
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// paramRef matches a ${param.name} reference to a matrix variable.
+var paramRef = regexp.MustCompile(`\$\{param\.([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// LoadScenarios reads a scenario file and expands its matrix section, if any,
+// into concrete scenarios (see ExpandMatrix). A scenario without a matrix
+// yields a single-element slice.
+func LoadScenarios(path string) ([]*Scenario, error) {
+	scenario, err := LoadScenario(path)
+	if err != nil {
+		return nil, err
+	}
+	return ExpandMatrix(scenario)
+}
+
+// ExpandMatrix returns one concrete scenario per combination of matrix
+// values, with every ${param.name} reference in setup args, flow args, flow
+// expectations, and assertion args/where/expect substituted.
+//
+// Expansion order is deterministic: matrix keys are taken in lexicographic
+// order, the first key varying slowest, and each key's values in their
+// listed order. Each expanded scenario is named "name[k1=v1,k2=v2]" and has
+// no matrix of its own.
+//
+// A reference that is the whole string value is replaced by the matrix value
+// itself, keeping its type (quantity: "${param.qty}" becomes an integer);
+// a reference embedded in a longer string is replaced by its text.
+//
+// A scenario without a matrix is returned as is.
+func ExpandMatrix(s *Scenario) ([]*Scenario, error) {
+	if len(s.Matrix) == 0 {
+		return []*Scenario{s}, nil
+	}
+	if err := validateMatrix(s); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(s.Matrix))
+	for key := range s.Matrix {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	combos := []map[string]interface{}{{}}
+	for _, key := range keys {
+		next := make([]map[string]interface{}, 0, len(combos)*len(s.Matrix[key]))
+		for _, combo := range combos {
+			for _, value := range s.Matrix[key] {
+				params := make(map[string]interface{}, len(combo)+1)
+				for k, v := range combo {
+					params[k] = v
+				}
+				params[key] = value
+				next = append(next, params)
+			}
+		}
+		combos = next
+	}
+
+	expanded := make([]*Scenario, len(combos))
+	for i, params := range combos {
+		expanded[i] = instantiate(s, keys, params)
+	}
+	return expanded, nil
+}
+
+// instantiate builds the concrete scenario for one matrix combination.
+func instantiate(s *Scenario, keys []string, params map[string]interface{}) *Scenario {
+	c := *s
+	c.Matrix = nil
+
+	labels := make([]string, len(keys))
+	for i, key := range keys {
+		labels[i] = fmt.Sprintf("%s=%v", key, params[key])
+	}
+	c.Name = fmt.Sprintf("%s[%s]", s.Name, strings.Join(labels, ","))
+
+	c.Specs = slices.Clone(s.Specs)
+	c.Fixtures = slices.Clone(s.Fixtures)
+	c.FixtureSetup = slices.Clone(s.FixtureSetup)
+	if s.MaxSteps != nil {
+		maxSteps := *s.MaxSteps
+		c.MaxSteps = &maxSteps
+	}
+	if s.ClockStart != nil {
+		clockStart := *s.ClockStart
+		c.ClockStart = &clockStart
+	}
+
+	c.Setup = make([]ActionStep, len(s.Setup))
+	for i, step := range s.Setup {
+		step.Args = substituteMap(step.Args, params)
+		c.Setup[i] = step
+	}
+
+	c.Flow = make([]FlowStep, len(s.Flow))
+	for i, step := range s.Flow {
+		step.Args = substituteMap(step.Args, params)
+		if step.Expect != nil {
+			expect := *step.Expect
+			expect.Case = fmt.Sprint(substituteParams(expect.Case, params))
+			expect.Result = substituteMap(expect.Result, params)
+			step.Expect = &expect
+		}
+		c.Flow[i] = step
+	}
+
+	c.Assertions = make([]Assertion, len(s.Assertions))
+	for i, assertion := range s.Assertions {
+		assertion.Args = substituteMap(assertion.Args, params)
+		assertion.Where = substituteMap(assertion.Where, params)
+		assertion.Expect = substituteMap(assertion.Expect, params)
+		assertion.Actions = slices.Clone(assertion.Actions)
+		c.Assertions[i] = assertion
+	}
+
+	return &c
+}
+
+// substituteMap applies substituteParams to every value of m, returning a
+// new map (nil stays nil).
+func substituteMap(m map[string]interface{}, params map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+	return substituteParams(m, params).(map[string]interface{})
+}
+
+// substituteParams returns a copy of v with ${param.name} references replaced
+// by their values from params. Maps and lists are copied recursively.
+func substituteParams(v interface{}, params map[string]interface{}) interface{} {
+	switch val := v.(type) {
+	case string:
+		if m := paramRef.FindStringSubmatch(val); m != nil && m[0] == val {
+			return params[m[1]]
+		}
+		return paramRef.ReplaceAllStringFunc(val, func(ref string) string {
+			return fmt.Sprint(params[paramRef.FindStringSubmatch(ref)[1]])
+		})
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			out[k] = substituteParams(item, params)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = substituteParams(item, params)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// validateMatrix checks that matrix variables are identifiers with at least
+// one value, and that every ${param.name} reference names a matrix variable.
+// It applies to scenarios without a matrix too, so a stray reference is
+// reported instead of being run as a literal string.
+func validateMatrix(s *Scenario) error {
+	keys := make([]string, 0, len(s.Matrix))
+	for key := range s.Matrix {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !validIdentifier.MatchString(key) {
+			return fmt.Errorf("matrix: invalid variable name %q", key)
+		}
+		if len(s.Matrix[key]) == 0 {
+			return fmt.Errorf("matrix.%s: at least one value is required", key)
+		}
+	}
+
+	check := func(location string, v interface{}) error {
+		for _, name := range paramRefs(v) {
+			if _, ok := s.Matrix[name]; !ok {
+				return fmt.Errorf("%s: ${param.%s} has no matrix entry", location, name)
+			}
+		}
+		return nil
+	}
+
+	for i, step := range s.Setup {
+		if err := check(fmt.Sprintf("setup[%d].args", i), step.Args); err != nil {
+			return err
+		}
+	}
+	for i, step := range s.Flow {
+		if err := check(fmt.Sprintf("flow[%d].args", i), step.Args); err != nil {
+			return err
+		}
+		if step.Expect != nil {
+			if err := check(fmt.Sprintf("flow[%d].expect.case", i), step.Expect.Case); err != nil {
+				return err
+			}
+			if err := check(fmt.Sprintf("flow[%d].expect.result", i), step.Expect.Result); err != nil {
+				return err
+			}
+		}
+	}
+	for i, assertion := range s.Assertions {
+		if err := check(fmt.Sprintf("assertions[%d].args", i), assertion.Args); err != nil {
+			return err
+		}
+		if err := check(fmt.Sprintf("assertions[%d].where", i), assertion.Where); err != nil {
+			return err
+		}
+		if err := check(fmt.Sprintf("assertions[%d].expect", i), assertion.Expect); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// paramRefs returns the sorted, de-duplicated variable names referenced by
+// ${param.name} anywhere in v.
+func paramRefs(v interface{}) []string {
+	seen := map[string]bool{}
+	var walk func(interface{})
+	walk = func(v interface{}) {
+		switch val := v.(type) {
+		case string:
+			for _, m := range paramRef.FindAllStringSubmatch(val, -1) {
+				seen[m[1]] = true
+			}
+		case map[string]interface{}:
+			for _, item := range val {
+				walk(item)
+			}
+		case []interface{}:
+			for _, item := range val {
+				walk(item)
+			}
+		}
+	}
+	walk(v)
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,165 @@
+package harness
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeMatrixScenario writes a 2x2 matrix scenario over item and quantity.
+func writeMatrixScenario(t *testing.T, dir string) string {
+	t.Helper()
+	specPath := createTestSpec(t, dir, "cart.concept.cue")
+
+	path := filepath.Join(dir, "matrix.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+name: add_item
+description: "Adds one item per matrix combination"
+specs:
+  - `+specPath+`
+matrix:
+  quantity: [1, 5]
+  item: ["widget", "gadget"]
+flow:
+  - invoke: Cart.addItem
+    args:
+      item_id: "${param.item}"
+      quantity: "${param.quantity}"
+      note: "add ${param.quantity}x ${param.item}"
+    expect:
+      case: Success
+      result:
+        quantity: "${param.quantity}"
+assertions:
+  - type: trace_contains
+    action: Cart.addItem
+    args:
+      item_id: "${param.item}"
+      quantity: "${param.quantity}"
+`), 0644))
+	return path
+}
+
+func TestLoadScenarios_Matrix2x2(t *testing.T) {
+	scenarios, err := LoadScenarios(writeMatrixScenario(t, t.TempDir()))
+	require.NoError(t, err)
+	require.Len(t, scenarios, 4)
+
+	// Keys in lexicographic order (item, quantity); values in listed order
+	want := []struct {
+		name     string
+		item     string
+		quantity int
+	}{
+		{"add_item[item=widget,quantity=1]", "widget", 1},
+		{"add_item[item=widget,quantity=5]", "widget", 5},
+		{"add_item[item=gadget,quantity=1]", "gadget", 1},
+		{"add_item[item=gadget,quantity=5]", "gadget", 5},
+	}
+
+	for i, w := range want {
+		s := scenarios[i]
+		assert.Equal(t, w.name, s.Name)
+		assert.Nil(t, s.Matrix)
+
+		args := s.Flow[0].Args
+		assert.Equal(t, w.item, args["item_id"])
+		assert.Equal(t, w.quantity, args["quantity"], "whole-value reference keeps the value's type")
+		assert.Equal(t, fmt.Sprintf("add %dx %s", w.quantity, w.item), args["note"])
+		assert.Equal(t, w.quantity, s.Flow[0].Expect.Result["quantity"])
+		assert.Equal(t, w.item, s.Assertions[0].Args["item_id"])
+	}
+}
+
+func TestExpandMatrix_DoesNotShareState(t *testing.T) {
+	scenarios, err := LoadScenarios(writeMatrixScenario(t, t.TempDir()))
+	require.NoError(t, err)
+
+	scenarios[0].Flow[0].Args["item_id"] = "changed"
+	scenarios[0].Specs[0] = "changed"
+	assert.Equal(t, "widget", scenarios[1].Flow[0].Args["item_id"])
+	assert.NotEqual(t, "changed", scenarios[1].Specs[0])
+}
+
+func TestExpandMatrix_NoMatrix(t *testing.T) {
+	scenario := &Scenario{Name: "plain"}
+
+	scenarios, err := ExpandMatrix(scenario)
+	require.NoError(t, err)
+	require.Len(t, scenarios, 1)
+	assert.Same(t, scenario, scenarios[0])
+}
+
+func TestLoadScenario_MatrixValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		matrix  string
+		itemID  string
+		wantErr string
+	}{
+		{
+			name:    "unbound_param",
+			matrix:  "matrix:\n  quantity: [1, 5]\n",
+			itemID:  "${param.item}",
+			wantErr: "flow[0].args: ${param.item} has no matrix entry",
+		},
+		{
+			name:    "param_without_matrix",
+			itemID:  "${param.item}",
+			wantErr: "flow[0].args: ${param.item} has no matrix entry",
+		},
+		{
+			name:    "empty_values",
+			matrix:  "matrix:\n  item: []\n",
+			itemID:  "${param.item}",
+			wantErr: "matrix.item: at least one value is required",
+		},
+		{
+			name:    "invalid_name",
+			matrix:  "matrix:\n  bad-name: [1]\n",
+			itemID:  "widget",
+			wantErr: `matrix: invalid variable name "bad-name"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			specPath := createTestSpec(t, dir, "cart.concept.cue")
+			path := filepath.Join(dir, "scenario.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(`
+name: matrix_validation
+description: "Matrix validation"
+specs:
+  - `+specPath+`
+`+tt.matrix+`flow:
+  - invoke: Cart.addItem
+    args:
+      item_id: "`+tt.itemID+`"
+assertions:
+  - type: trace_contains
+    action: Cart.addItem
+`), 0644))
+
+			_, err := LoadScenario(path)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestRunAll_ExpandsMatrix(t *testing.T) {
+	reports := RunAll(context.Background(), []string{writeMatrixScenario(t, t.TempDir())}, RunAllOptions{})
+	require.Len(t, reports, 4)
+
+	assert.Equal(t, "add_item[item=widget,quantity=1]", reports[0].Scenario)
+	assert.Equal(t, "add_item[item=gadget,quantity=5]", reports[3].Scenario)
+	for _, r := range reports {
+		assert.True(t, r.Pass, "%s: %v", r.Scenario, r.Errors)
+	}
+}
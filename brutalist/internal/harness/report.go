@@ -0,0 +1,110 @@
+package harness
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// RunReport is a machine-readable summary of a scenario run for CI systems.
+// Unlike Result.Errors, each assertion keeps its index and type so a failure
+// maps back to its position in the scenario file.
+type RunReport struct {
+	// Scenario is the scenario name.
+	Scenario string `json:"scenario"`
+
+	// Pass is true if every flow step and assertion passed.
+	Pass bool `json:"pass"`
+
+	// Assertions has one entry per scenario assertion, in declared order.
+	Assertions []AssertionStatus `json:"assertions"`
+
+	// Errors contains every error message recorded during the run, including
+	// flow step failures and assertion failures, in the order they occurred.
+	Errors []string `json:"errors"`
+
+	// Trace is the full invocation/completion trace.
+	Trace []TraceEvent `json:"trace"`
+}
+
+// AssertionStatus is the outcome of a single assertion.
+type AssertionStatus struct {
+	// Index is the assertion's position in the scenario's assertions list.
+	Index int `json:"index"`
+
+	// Type is the assertion type (e.g., "trace_contains").
+	Type string `json:"type"`
+
+	// Pass is true if the assertion held.
+	Pass bool `json:"pass"`
+
+	// Expected and Actual describe a failed assertion (from AssertionError).
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+
+	// Error is set for failures that are not an AssertionError
+	// (e.g., invalid table name, missing database context).
+	Error string `json:"error,omitempty"`
+}
+
+// Report builds a structured run report from a result and the scenario's
+// assertions.
+//
+// Assertion outcomes recorded by Run are used when available. Otherwise
+// (e.g., a Result assembled by hand) the assertions are re-evaluated against
+// the trace; store-backed assertions then report a missing database context.
+func Report(result *Result, assertions []Assertion) RunReport {
+	errs := result.assertionErrs
+	if len(errs) != len(assertions) {
+		errs = evaluateAssertions(result, assertions, nil)
+	}
+
+	report := RunReport{
+		Scenario:   result.Scenario,
+		Pass:       result.Pass,
+		Assertions: make([]AssertionStatus, len(assertions)),
+		Errors:     result.Errors,
+		Trace:      result.Trace,
+	}
+
+	for i, assertion := range assertions {
+		status := AssertionStatus{
+			Index: i,
+			Type:  assertion.Type,
+			Pass:  errs[i] == nil,
+		}
+
+		var assertErr *AssertionError
+		switch {
+		case errs[i] == nil:
+		case errors.As(errs[i], &assertErr):
+			status.Expected = assertErr.Expected
+			status.Actual = assertErr.Actual
+		default:
+			status.Error = errs[i].Error()
+		}
+
+		if !status.Pass {
+			report.Pass = false
+		}
+		report.Assertions[i] = status
+	}
+
+	return report
+}
+
+// MarshalJSON encodes the report with empty lists as [] rather than null,
+// so consumers can iterate every field without nil checks.
+func (r RunReport) MarshalJSON() ([]byte, error) {
+	type plain RunReport // Avoid recursion into this method
+	out := plain(r)
+	if out.Assertions == nil {
+		out.Assertions = []AssertionStatus{}
+	}
+	if out.Errors == nil {
+		out.Errors = []string{}
+	}
+	if out.Trace == nil {
+		out.Trace = []TraceEvent{}
+	}
+	return json.Marshal(out)
+}
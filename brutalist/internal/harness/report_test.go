@@ -0,0 +1,85 @@
+package harness
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReport_MixedPassFail(t *testing.T) {
+	scenario := &Scenario{
+		Name:        "mixed_report",
+		Description: "One passing and two failing assertions",
+		Specs:       []string{},
+		FlowToken:   "test-flow-report",
+		Flow: []FlowStep{
+			{Invoke: "Cart.addItem", Args: map[string]interface{}{"item_id": "widget"}},
+		},
+		Assertions: []Assertion{
+			{Type: AssertTraceContains, Action: "Cart.addItem"},
+			{Type: AssertTraceCount, Action: "Cart.addItem", Count: 2},
+			{Type: AssertFinalState, Table: "bad name", Expect: map[string]interface{}{"x": 1}},
+		},
+	}
+
+	result, err := Run(scenario)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(Report(result, scenario.Assertions))
+	require.NoError(t, err)
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	assert.Equal(t, "mixed_report", decoded["scenario"])
+	assert.Equal(t, false, decoded["pass"])
+	assert.Len(t, decoded["errors"], 2)
+	assert.Len(t, decoded["trace"], 2)
+
+	assertions, ok := decoded["assertions"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, assertions, 3)
+
+	assert.Equal(t, map[string]interface{}{
+		"index": float64(0),
+		"type":  "trace_contains",
+		"pass":  true,
+	}, assertions[0])
+	assert.Equal(t, map[string]interface{}{
+		"index":    float64(1),
+		"type":     "trace_count",
+		"pass":     false,
+		"expected": "2 occurrences of Cart.addItem",
+		"actual":   "1 occurrences",
+	}, assertions[1])
+
+	third := assertions[2].(map[string]interface{})
+	assert.Equal(t, float64(2), third["index"])
+	assert.Equal(t, "final_state", third["type"])
+	assert.Equal(t, false, third["pass"])
+	assert.Contains(t, third["error"], "invalid table name")
+}
+
+func TestReport_WithoutRunReevaluatesTrace(t *testing.T) {
+	result := NewResult()
+	result.AddInvocationTrace("flow-1", "Cart.addItem", map[string]interface{}{}, 1)
+
+	assertions := []Assertion{
+		{Type: AssertTraceContains, Action: "Cart.addItem"},
+		{Type: AssertStateCount, Table: "cart_items", Count: 1},
+	}
+
+	report := Report(result, assertions)
+	assert.False(t, report.Pass)
+	assert.True(t, report.Assertions[0].Pass)
+	assert.False(t, report.Assertions[1].Pass)
+	assert.Contains(t, report.Assertions[1].Error, "requires database context")
+}
+
+func TestRunReport_MarshalJSONEmptyLists(t *testing.T) {
+	data, err := json.Marshal(RunReport{Scenario: "empty", Pass: true})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"scenario":"empty","pass":true,"assertions":[],"errors":[],"trace":[]}`, string(data))
+}
@@ -0,0 +1,102 @@
+package harness
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// RunAllOptions configures a batch run.
+type RunAllOptions struct {
+	// Concurrency is the maximum number of scenarios run at once.
+	// Zero or negative uses runtime.GOMAXPROCS(0).
+	Concurrency int
+
+	// Actions, if set, supplies action handlers to every scenario
+	// (see RunWithActions). Handlers may run concurrently.
+	Actions *ActionRegistry
+}
+
+// RunAll loads and runs every scenario file in paths concurrently and
+// returns one report per scenario, in the order of paths. A file with a
+// matrix section contributes one report per expansion, in ExpandMatrix order.
+//
+// Each scenario runs through RunWithActions, so it gets its own in-memory
+// store, clock, and engine; nothing is shared between scenarios. Because
+// every run is deterministic and reports are placed by index, the result
+// does not depend on which scenario finishes first.
+//
+// A scenario that fails to load or run gets a failing report whose Scenario
+// is its path and whose Errors hold the failure. Once ctx is cancelled,
+// scenarios that have not started yet are reported as failed with ctx.Err().
+func RunAll(ctx context.Context, paths []string, opts RunAllOptions) []RunReport {
+	limit := opts.Concurrency
+	if limit <= 0 {
+		limit = runtime.GOMAXPROCS(0)
+	}
+
+	perPath := make([][]RunReport, len(paths))
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+
+	for i, path := range paths {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				perPath[i] = []RunReport{failedReport(path, ctx.Err())}
+				return
+			}
+			if err := ctx.Err(); err != nil {
+				perPath[i] = []RunReport{failedReport(path, err)}
+				return
+			}
+
+			perPath[i] = runPath(path, opts.Actions)
+		}()
+	}
+
+	wg.Wait()
+
+	reports := []RunReport{}
+	for _, r := range perPath {
+		reports = append(reports, r...)
+	}
+	return reports
+}
+
+// runPath loads and runs a single scenario file, and each of its matrix
+// expansions, for RunAll.
+func runPath(path string, actions *ActionRegistry) []RunReport {
+	scenarios, err := LoadScenarios(path)
+	if err != nil {
+		return []RunReport{failedReport(path, err)}
+	}
+
+	reports := make([]RunReport, len(scenarios))
+	for i, scenario := range scenarios {
+		result, err := RunWithActions(scenario, actions)
+		if err != nil {
+			reports[i] = failedReport(path, fmt.Errorf("run scenario %s: %w", scenario.Name, err))
+			continue
+		}
+		reports[i] = Report(result, scenario.Assertions)
+	}
+	return reports
+}
+
+// failedReport is the report for a scenario that could not produce a Result.
+func failedReport(path string, err error) RunReport {
+	return RunReport{
+		Scenario:   path,
+		Pass:       false,
+		Assertions: []AssertionStatus{},
+		Errors:     []string{err.Error()},
+		Trace:      []TraceEvent{},
+	}
+}
@@ -0,0 +1,123 @@
+package harness
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeRunnerScenarios writes a passing three-step scenario and a failing
+// one-step scenario, and returns their paths.
+func writeRunnerScenarios(t *testing.T, dir string) (string, string) {
+	t.Helper()
+	specPath := createTestSpec(t, dir, "cart.concept.cue")
+
+	passing := filepath.Join(dir, "passing.yaml")
+	require.NoError(t, os.WriteFile(passing, []byte(`
+name: three_items
+description: "Adds three items"
+specs:
+  - `+specPath+`
+flow:
+  - invoke: Cart.addItem
+    args: {item_id: "a"}
+  - invoke: Cart.addItem
+    args: {item_id: "b"}
+  - invoke: Cart.addItem
+    args: {item_id: "c"}
+assertions:
+  - type: trace_count
+    action: Cart.addItem
+    count: 3
+`), 0644))
+
+	failing := filepath.Join(dir, "failing.yaml")
+	require.NoError(t, os.WriteFile(failing, []byte(`
+name: one_item
+description: "Adds one item but expects a checkout"
+specs:
+  - `+specPath+`
+flow:
+  - invoke: Cart.addItem
+    args: {item_id: "z"}
+assertions:
+  - type: trace_contains
+    action: Cart.checkout
+`), 0644))
+
+	return passing, failing
+}
+
+func TestRunAll_ParallelIsolatedReports(t *testing.T) {
+	passing, failing := writeRunnerScenarios(t, t.TempDir())
+
+	reports := RunAll(context.Background(), []string{passing, failing}, RunAllOptions{Concurrency: 2})
+	require.Len(t, reports, 2)
+
+	// Each scenario sees only its own store and clock: seqs start fresh
+	assert.Equal(t, "three_items", reports[0].Scenario)
+	assert.True(t, reports[0].Pass)
+	require.Len(t, reports[0].Trace, 6)
+	for i, event := range reports[0].Trace {
+		assert.Equal(t, int64(i+1), event.Seq)
+	}
+
+	assert.Equal(t, "one_item", reports[1].Scenario)
+	assert.False(t, reports[1].Pass)
+	require.Len(t, reports[1].Trace, 2)
+	assert.Equal(t, int64(1), reports[1].Trace[0].Seq)
+	require.Len(t, reports[1].Assertions, 1)
+	assert.False(t, reports[1].Assertions[0].Pass)
+}
+
+func TestRunAll_DeterministicRegardlessOfCompletionOrder(t *testing.T) {
+	passing, failing := writeRunnerScenarios(t, t.TempDir())
+	paths := []string{passing, failing, passing, failing}
+
+	sequential := RunAll(context.Background(), paths, RunAllOptions{Concurrency: 1})
+	want, err := json.Marshal(sequential)
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		got, err := json.Marshal(RunAll(context.Background(), paths, RunAllOptions{Concurrency: len(paths)}))
+		require.NoError(t, err)
+		assert.JSONEq(t, string(want), string(got), "run %d", i)
+	}
+}
+
+func TestRunAll_LoadErrorReportsPath(t *testing.T) {
+	dir := t.TempDir()
+	passing, _ := writeRunnerScenarios(t, dir)
+	missing := filepath.Join(dir, "missing.yaml")
+
+	reports := RunAll(context.Background(), []string{missing, passing}, RunAllOptions{})
+	require.Len(t, reports, 2)
+
+	assert.Equal(t, missing, reports[0].Scenario)
+	assert.False(t, reports[0].Pass)
+	require.Len(t, reports[0].Errors, 1)
+	assert.True(t, reports[1].Pass, "a load failure must not affect other scenarios")
+}
+
+func TestRunAll_CancelledContext(t *testing.T) {
+	passing, _ := writeRunnerScenarios(t, t.TempDir())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	reports := RunAll(ctx, []string{passing}, RunAllOptions{Concurrency: 1})
+	require.Len(t, reports, 1)
+	assert.False(t, reports[0].Pass)
+	assert.Equal(t, []string{context.Canceled.Error()}, reports[0].Errors)
+}
+
+func TestRunAll_Empty(t *testing.T) {
+	reports := RunAll(context.Background(), nil, RunAllOptions{})
+	assert.NotNil(t, reports)
+	assert.Empty(t, reports)
+}
@@ -2,10 +2,14 @@ package harness
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
+	"github.com/roach88/nysm/internal/engine"
 	"gopkg.in/yaml.v3"
 )
 
@@ -14,141 +18,235 @@ import (
 // and asserting on the resulting trace and final state.
 type Scenario struct {
 	// Name uniquely identifies this scenario.
-	Name string `yaml:"name"`
+	Name string `yaml:"name" json:"name"`
 
 	// Description explains what this scenario validates.
-	Description string `yaml:"description"`
+	Description string `yaml:"description" json:"description"`
 
 	// Specs lists paths to CUE spec files to compile and load.
 	// Paths are relative to the scenario file location.
-	Specs []string `yaml:"specs"`
+	Specs []string `yaml:"specs" json:"specs"`
+
+	// Fixtures lists paths to scenario files whose setup steps run before this
+	// scenario's own Setup, in declared order, so later fixtures override
+	// state established by earlier ones. Fixture files only need setup (and
+	// may list their own fixtures); other fields are ignored.
+	// Paths are resolved relative to the base path, like Specs.
+	Fixtures []string `yaml:"fixtures,omitempty" json:"fixtures,omitempty"`
+
+	// FixtureSetup holds the flattened setup steps of all Fixtures.
+	// Populated by LoadScenarioWithBasePath; not part of the file format.
+	FixtureSetup []ActionStep `yaml:"-" json:"-"`
 
 	// Setup contains actions to invoke before the main flow.
 	// These establish initial state (e.g., setting inventory stock).
 	// Setup actions are assumed to succeed.
-	Setup []ActionStep `yaml:"setup,omitempty"`
+	Setup []ActionStep `yaml:"setup,omitempty" json:"setup,omitempty"`
 
 	// Flow contains the main test flow - invocations with expected results.
 	// Each step can specify expected output case and result values.
-	Flow []FlowStep `yaml:"flow"`
+	Flow []FlowStep `yaml:"flow" json:"flow"`
 
 	// Assertions validate the final trace and state.
-	// Supported types: trace_contains, trace_order, trace_count, final_state
-	Assertions []Assertion `yaml:"assertions"`
+	// Supported types: trace_contains, trace_not_contains, trace_args_all, trace_order,
+	// trace_count, final_state, state_count, provenance
+	Assertions []Assertion `yaml:"assertions" json:"assertions"`
 
 	// FlowToken is an optional fixed flow token for deterministic tests.
 	// If empty, defaults to "test-flow-default" for deterministic golden file comparison.
 	// Production scenarios should specify an explicit token for traceability.
-	FlowToken string `yaml:"flow_token,omitempty"`
+	FlowToken string `yaml:"flow_token,omitempty" json:"flow_token,omitempty"`
+
+	// Matrix maps variable names to value lists. A scenario with a matrix is
+	// a template: ExpandMatrix (or LoadScenarios) turns it into one concrete
+	// scenario per combination of values, substituting ${param.name}
+	// references in setup args, flow args and expectations, and assertions.
+	Matrix map[string][]interface{} `yaml:"matrix,omitempty" json:"matrix,omitempty"`
+
+	// MaxSteps overrides the engine's per-flow steps quota for this scenario.
+	// If omitted, the engine default (engine.DefaultMaxSteps) applies.
+	// Must be positive when set.
+	MaxSteps *int `yaml:"max_steps,omitempty" json:"max_steps,omitempty"`
+
+	// ClockStart is the seq of the first recorded event. The harness and
+	// engine clocks both start there, so fixtures that assume a seq offset
+	// compose reproducibly. Every seq in the trace (and so in golden files)
+	// shifts with it. If omitted, the first event has seq 1.
+	// Must be non-negative when set.
+	ClockStart *int64 `yaml:"clock_start,omitempty" json:"clock_start,omitempty"`
 }
 
 // ActionStep represents a single action invocation.
 // Used in Setup sections to establish initial state.
 type ActionStep struct {
 	// Action is the action URI (e.g., "Inventory.setStock").
-	Action string `yaml:"action"`
+	Action string `yaml:"action" json:"action"`
 
 	// Args contains the action arguments as a map.
 	// Values are converted to ir.IRValue types during execution.
-	Args map[string]interface{} `yaml:"args"`
+	Args map[string]interface{} `yaml:"args" json:"args"`
 }
 
 // FlowStep represents a step in the main test flow.
 // Each step invokes an action and optionally validates the completion.
 type FlowStep struct {
 	// Invoke is the action URI to invoke.
-	Invoke string `yaml:"invoke"`
+	Invoke string `yaml:"invoke" json:"invoke"`
 
 	// Args contains the action arguments.
-	Args map[string]interface{} `yaml:"args"`
+	Args map[string]interface{} `yaml:"args" json:"args"`
 
 	// Expect specifies the expected completion result.
 	// If nil, no validation is performed (action assumed to succeed).
-	Expect *ExpectClause `yaml:"expect,omitempty"`
+	Expect *ExpectClause `yaml:"expect,omitempty" json:"expect,omitempty"`
+
+	// ExpectError specifies that processing this step's completion must be
+	// rejected by the engine with a typed runtime error.
+	// Mutually exclusive with Expect.
+	ExpectError *ExpectErrorClause `yaml:"expect_error,omitempty" json:"expect_error,omitempty"`
+
+	// FlowToken, if set, runs this step in the given flow instead of the
+	// scenario's, so one scenario can drive several interleaved flows.
+	FlowToken string `yaml:"flow_token,omitempty" json:"flow_token,omitempty"`
 }
 
 // ExpectClause specifies expected completion behavior.
 type ExpectClause struct {
 	// Case is the expected OutputCase name (e.g., "Success", "InsufficientStock").
-	Case string `yaml:"case"`
+	Case string `yaml:"case" json:"case"`
 
 	// Result contains expected result field values.
 	// This is a subset match - only specified fields are validated.
 	// If nil, only the case is validated.
-	Result map[string]interface{} `yaml:"result,omitempty"`
+	Result map[string]interface{} `yaml:"result,omitempty" json:"result,omitempty"`
+}
+
+// ExpectErrorClause specifies an expected engine rejection for negative tests.
+type ExpectErrorClause struct {
+	// Code is the expected engine error code (e.g., "CYCLE_DETECTED",
+	// "STEPS_EXCEEDED"), matched against engine.ErrorCode.
+	Code string `yaml:"code" json:"code"`
+}
+
+// knownErrorCodes lists the engine error codes accepted by expect_error.
+var knownErrorCodes = map[string]bool{
+	string(engine.ErrCodeCycleDetected):      true,
+	string(engine.ErrCodeQuotaExceeded):      true,
+	string(engine.ErrCodeStepsExceeded):      true,
+	string(engine.ErrCodeMissingAction):      true,
+	string(engine.ErrCodeInvalidBinding):     true,
+	string(engine.ErrCodeDanglingCompletion): true,
+	string(engine.ErrCodeBindingExplosion):   true,
 }
 
 // Assertion validates trace or final state.
 type Assertion struct {
 	// Type specifies the assertion type:
 	// - "trace_contains": Check action appears in trace with args
+	// - "trace_not_contains": Check no invocation of action matches args
+	// - "trace_args_all": Check every invocation of action matches args
 	// - "trace_order": Check actions appear in order
 	// - "trace_count": Check action appears exactly N times
 	// - "final_state": Query table and verify expected values
-	Type string `yaml:"type"`
-
-	// Action is the action URI (used by trace_contains, trace_order, trace_count).
-	Action string `yaml:"action,omitempty"`
-
-	// Args are the expected action arguments (used by trace_contains).
+	// - "state_count": Check table has exactly N rows matching where
+	// - "provenance": Check effect was caused by cause via sync firings
+	// - "sync_count": Check a sync rule fired exactly N times
+	// - "seq_before": Check every earlier_action seq precedes every later_action seq
+	// - "invocation_security": Check invocations of action carry tenant_id/user_id
+	// - "flow_complete": Check the flow has no pending invocations or orphaned firings
+	// - "completion_result": Check a completion of action has the expected result fields
+	Type string `yaml:"type" json:"type"`
+
+	// Action is the action URI (used by trace_contains, trace_not_contains,
+	// trace_args_all, trace_count, invocation_security, completion_result).
+	Action string `yaml:"action,omitempty" json:"action,omitempty"`
+
+	// Args are the expected action arguments (used by trace_contains,
+	// trace_not_contains, trace_args_all).
 	// Subset match - only specified fields are validated.
-	Args map[string]interface{} `yaml:"args,omitempty"`
+	// A value of {regex: "<pattern>"} matches string args against an RE2 pattern.
+	Args map[string]interface{} `yaml:"args,omitempty" json:"args,omitempty"`
 
-	// Table is the state table name (used by final_state).
-	Table string `yaml:"table,omitempty"`
+	// Table is the state table name (used by final_state, state_count).
+	Table string `yaml:"table,omitempty" json:"table,omitempty"`
 
-	// Where specifies query filters (used by final_state).
+	// Where specifies query filters (used by final_state, state_count), or
+	// selects invocations by their args (used by completion_result).
 	// All fields must match exactly.
-	Where map[string]interface{} `yaml:"where,omitempty"`
+	Where map[string]interface{} `yaml:"where,omitempty" json:"where,omitempty"`
 
-	// Expect contains expected field values (used by final_state).
+	// Expect contains expected field values (used by final_state) or
+	// completion result fields (used by completion_result).
 	// Subset match - only specified fields are validated.
-	Expect map[string]interface{} `yaml:"expect,omitempty"`
+	Expect map[string]interface{} `yaml:"expect,omitempty" json:"expect,omitempty"`
+
+	// Count is the expected number of occurrences (used by trace_count),
+	// matching rows (used by state_count), or sync firings (used by sync_count).
+	Count int `yaml:"count,omitempty" json:"count,omitempty"`
+
+	// SyncID is the sync rule whose firings are counted (used by sync_count).
+	SyncID string `yaml:"sync_id,omitempty" json:"sync_id,omitempty"`
 
-	// Count is the expected number of occurrences (used by trace_count).
-	Count int `yaml:"count,omitempty"`
+	// EarlierAction and LaterAction are action URIs whose invocations must
+	// not interleave: every EarlierAction seq is below every LaterAction seq
+	// (used by seq_before).
+	EarlierAction string `yaml:"earlier_action,omitempty" json:"earlier_action,omitempty"`
+	LaterAction   string `yaml:"later_action,omitempty" json:"later_action,omitempty"`
+
+	// TenantID and UserID are the security context every persisted
+	// invocation of Action must carry (used by invocation_security).
+	TenantID string `yaml:"tenant_id,omitempty" json:"tenant_id,omitempty"`
+	UserID   string `yaml:"user_id,omitempty" json:"user_id,omitempty"`
 
 	// Actions is the expected action order (used by trace_order).
-	Actions []string `yaml:"actions,omitempty"`
+	Actions []string `yaml:"actions,omitempty" json:"actions,omitempty"`
+
+	// Cause is the action URI whose completion must lead to Effect (used by provenance).
+	Cause string `yaml:"cause,omitempty" json:"cause,omitempty"`
+
+	// Effect is the action URI that must be reachable from Cause through
+	// provenance edges (used by provenance).
+	Effect string `yaml:"effect,omitempty" json:"effect,omitempty"`
+
+	// FlowToken, if set, scopes the assertion to one flow: trace assertions
+	// see only that flow's events, and provenance, invocation_security,
+	// flow_complete and completion_result read that flow from the store. The flow must appear in
+	// the trace.
+	// Not supported by final_state, state_count, or sync_count.
+	FlowToken string `yaml:"flow_token,omitempty" json:"flow_token,omitempty"`
 }
 
 // Assertion type constants.
 const (
-	AssertTraceContains = "trace_contains"
-	AssertTraceOrder    = "trace_order"
-	AssertTraceCount    = "trace_count"
-	AssertFinalState    = "final_state"
+	AssertTraceContains      = "trace_contains"
+	AssertTraceNotContains   = "trace_not_contains"
+	AssertTraceArgsAll       = "trace_args_all"
+	AssertTraceOrder         = "trace_order"
+	AssertTraceCount         = "trace_count"
+	AssertFinalState         = "final_state"
+	AssertStateCount         = "state_count"
+	AssertProvenance         = "provenance"
+	AssertSyncCount          = "sync_count"
+	AssertSeqBefore          = "seq_before"
+	AssertInvocationSecurity = "invocation_security"
+	AssertFlowComplete       = "flow_complete"
+	AssertCompletionResult   = "completion_result"
 )
 
-// LoadScenario reads and parses a scenario YAML file.
+// LoadScenario reads and parses a scenario file.
+// The format is chosen by extension: .json files are decoded as JSON, all
+// others (.yaml, .yml) as YAML. Both formats produce the same Scenario and
+// go through the same validation.
 // Returns an error if the file doesn't exist, is malformed,
 // contains unknown fields (typos), or is missing required fields.
 func LoadScenario(path string) (*Scenario, error) {
-	// Read file
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read scenario file: %w", err)
-	}
-
-	// Parse YAML with strict field validation (catches typos like "assertion:" vs "assertions:")
-	var scenario Scenario
-	decoder := yaml.NewDecoder(bytes.NewReader(data))
-	decoder.KnownFields(true) // Reject unknown fields
-	if err := decoder.Decode(&scenario); err != nil {
-		return nil, fmt.Errorf("failed to parse YAML: %w", err)
-	}
-
-	// Validate required fields
-	if err := validateScenario(&scenario); err != nil {
-		return nil, fmt.Errorf("invalid scenario: %w", err)
-	}
-
-	return &scenario, nil
+	return LoadScenarioWithBasePath(path, "")
 }
 
-// LoadScenarioWithBasePath reads and parses a scenario YAML file,
+// LoadScenarioWithBasePath reads and parses a scenario file,
 // resolving spec paths relative to the provided base path.
 // This is useful when scenario files reference specs using relative paths.
+// An empty base path leaves spec paths unchanged.
 func LoadScenarioWithBasePath(path, basePath string) (*Scenario, error) {
 	// Read file
 	data, err := os.ReadFile(path)
@@ -156,12 +254,9 @@ func LoadScenarioWithBasePath(path, basePath string) (*Scenario, error) {
 		return nil, fmt.Errorf("failed to read scenario file: %w", err)
 	}
 
-	// Parse YAML with strict field validation (catches typos)
-	var scenario Scenario
-	decoder := yaml.NewDecoder(bytes.NewReader(data))
-	decoder.KnownFields(true) // Reject unknown fields
-	if err := decoder.Decode(&scenario); err != nil {
-		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	scenario, err := decodeScenario(path, data)
+	if err != nil {
+		return nil, err
 	}
 
 	// Resolve spec paths relative to base path BEFORE validation
@@ -172,13 +267,96 @@ func LoadScenarioWithBasePath(path, basePath string) (*Scenario, error) {
 	}
 
 	// Validate required fields (now with resolved paths)
-	if err := validateScenario(&scenario); err != nil {
+	if err := validateScenario(scenario); err != nil {
 		return nil, fmt.Errorf("invalid scenario: %w", err)
 	}
 
-	return &scenario, nil
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve scenario path: %w", err)
+	}
+	setup, err := loadFixtures(scenario.Fixtures, basePath, []string{absPath})
+	if err != nil {
+		return nil, fmt.Errorf("invalid scenario: %w", err)
+	}
+	scenario.FixtureSetup = setup
+
+	return scenario, nil
 }
 
+// loadFixtures reads fixture files in declared order and returns their setup
+// steps flattened depth-first: a fixture's own fixtures come before its setup.
+// stack holds the absolute paths of the files currently being included and
+// is used to reject include cycles.
+func loadFixtures(fixtures []string, basePath string, stack []string) ([]ActionStep, error) {
+	var setup []ActionStep
+
+	for _, fixturePath := range fixtures {
+		if !filepath.IsAbs(fixturePath) && basePath != "" {
+			fixturePath = filepath.Join(basePath, fixturePath)
+		}
+
+		absPath, err := filepath.Abs(fixturePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve fixture path %s: %w", fixturePath, err)
+		}
+		if slices.Contains(stack, absPath) {
+			return nil, fmt.Errorf("fixture cycle detected: %s",
+				strings.Join(append(stack, absPath), " -> "))
+		}
+
+		data, err := os.ReadFile(fixturePath)
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("fixture file not found: %s", fixturePath)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixture file: %w", err)
+		}
+
+		fixture, err := decodeScenario(fixturePath, data)
+		if err != nil {
+			return nil, fmt.Errorf("fixture %s: %w", fixturePath, err)
+		}
+		if err := validateSetup(fixture.Setup); err != nil {
+			return nil, fmt.Errorf("fixture %s: %w", fixturePath, err)
+		}
+
+		nested, err := loadFixtures(fixture.Fixtures, basePath, append(stack, absPath))
+		if err != nil {
+			return nil, err
+		}
+		setup = append(setup, nested...)
+		setup = append(setup, fixture.Setup...)
+	}
+
+	return setup, nil
+}
+
+// decodeScenario parses scenario data in the format implied by path's
+// extension. Both decoders reject unknown fields (catches typos like
+// "assertion:" vs "assertions:").
+func decodeScenario(path string, data []byte) (*Scenario, error) {
+	var scenario Scenario
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&scenario); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON: %w", err)
+		}
+		if decoder.More() {
+			return nil, fmt.Errorf("failed to parse JSON: unexpected data after scenario object")
+		}
+		return &scenario, nil
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true) // Reject unknown fields
+	if err := decoder.Decode(&scenario); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	return &scenario, nil
+}
 
 // validateScenario checks that required fields are present and valid.
 func validateScenario(s *Scenario) error {
@@ -202,6 +380,14 @@ func validateScenario(s *Scenario) error {
 		return fmt.Errorf("assertions list is required and must be non-empty")
 	}
 
+	if s.MaxSteps != nil && *s.MaxSteps <= 0 {
+		return fmt.Errorf("max_steps must be a positive integer, got %d", *s.MaxSteps)
+	}
+
+	if s.ClockStart != nil && *s.ClockStart < 0 {
+		return fmt.Errorf("clock_start must be a non-negative integer, got %d", *s.ClockStart)
+	}
+
 	// Validate spec paths exist
 	for _, specPath := range s.Specs {
 		if _, err := os.Stat(specPath); os.IsNotExist(err) {
@@ -210,13 +396,8 @@ func validateScenario(s *Scenario) error {
 	}
 
 	// Validate setup steps (if present)
-	for i, step := range s.Setup {
-		if step.Action == "" {
-			return fmt.Errorf("setup[%d]: action is required", i)
-		}
-		if step.Args == nil {
-			return fmt.Errorf("setup[%d]: args is required (use empty map if no args)", i)
-		}
+	if err := validateSetup(s.Setup); err != nil {
+		return err
 	}
 
 	// Validate flow steps
@@ -231,6 +412,17 @@ func validateScenario(s *Scenario) error {
 		if step.Expect != nil && step.Expect.Case == "" {
 			return fmt.Errorf("flow[%d].expect: case is required", i)
 		}
+		if step.ExpectError != nil {
+			if step.Expect != nil {
+				return fmt.Errorf("flow[%d]: expect and expect_error are mutually exclusive", i)
+			}
+			if step.ExpectError.Code == "" {
+				return fmt.Errorf("flow[%d].expect_error: code is required", i)
+			}
+			if !knownErrorCodes[step.ExpectError.Code] {
+				return fmt.Errorf("flow[%d].expect_error: unknown error code %q", i, step.ExpectError.Code)
+			}
+		}
 	}
 
 	// Validate assertions
@@ -240,6 +432,20 @@ func validateScenario(s *Scenario) error {
 		}
 	}
 
+	// Validate matrix variables and ${param.*} references
+	return validateMatrix(s)
+}
+
+// validateSetup checks that each setup step names an action and has args.
+func validateSetup(setup []ActionStep) error {
+	for i, step := range setup {
+		if step.Action == "" {
+			return fmt.Errorf("setup[%d]: action is required", i)
+		}
+		if step.Args == nil {
+			return fmt.Errorf("setup[%d]: args is required (use empty map if no args)", i)
+		}
+	}
 	return nil
 }
 
@@ -254,6 +460,14 @@ func validateAssertion(index int, a *Assertion) error {
 		if a.Action == "" {
 			return fmt.Errorf("assertions[%d]: action is required for trace_contains", index)
 		}
+	case AssertTraceNotContains:
+		if a.Action == "" {
+			return fmt.Errorf("assertions[%d]: action is required for trace_not_contains", index)
+		}
+	case AssertTraceArgsAll:
+		if a.Action == "" {
+			return fmt.Errorf("assertions[%d]: action is required for trace_args_all", index)
+		}
 	case AssertTraceOrder:
 		if len(a.Actions) == 0 {
 			return fmt.Errorf("assertions[%d]: actions list is required for trace_order", index)
@@ -272,9 +486,63 @@ func validateAssertion(index int, a *Assertion) error {
 		if a.Expect == nil || len(a.Expect) == 0 {
 			return fmt.Errorf("assertions[%d]: expect is required for final_state", index)
 		}
+	case AssertStateCount:
+		if a.Table == "" {
+			return fmt.Errorf("assertions[%d]: table is required for state_count", index)
+		}
+		if a.Count < 0 {
+			return fmt.Errorf("assertions[%d]: count must be non-negative for state_count", index)
+		}
+	case AssertProvenance:
+		if a.Cause == "" {
+			return fmt.Errorf("assertions[%d]: cause is required for provenance", index)
+		}
+		if a.Effect == "" {
+			return fmt.Errorf("assertions[%d]: effect is required for provenance", index)
+		}
+	case AssertSyncCount:
+		if a.SyncID == "" {
+			return fmt.Errorf("assertions[%d]: sync_id is required for sync_count", index)
+		}
+		if a.Count < 0 {
+			return fmt.Errorf("assertions[%d]: count must be non-negative for sync_count", index)
+		}
+	case AssertSeqBefore:
+		if a.EarlierAction == "" {
+			return fmt.Errorf("assertions[%d]: earlier_action is required for seq_before", index)
+		}
+		if a.LaterAction == "" {
+			return fmt.Errorf("assertions[%d]: later_action is required for seq_before", index)
+		}
+	case AssertInvocationSecurity:
+		if a.Action == "" {
+			return fmt.Errorf("assertions[%d]: action is required for invocation_security", index)
+		}
+		if a.TenantID == "" {
+			return fmt.Errorf("assertions[%d]: tenant_id is required for invocation_security", index)
+		}
+		if a.UserID == "" {
+			return fmt.Errorf("assertions[%d]: user_id is required for invocation_security", index)
+		}
+	case AssertFlowComplete:
+		// No required fields; flow_token is optional
+	case AssertCompletionResult:
+		if a.Action == "" {
+			return fmt.Errorf("assertions[%d]: action is required for completion_result", index)
+		}
+		if len(a.Expect) == 0 {
+			return fmt.Errorf("assertions[%d]: expect is required for completion_result", index)
+		}
 	default:
 		return fmt.Errorf("assertions[%d]: unknown assertion type %q", index, a.Type)
 	}
 
+	if a.FlowToken != "" {
+		switch a.Type {
+		case AssertFinalState, AssertStateCount, AssertSyncCount:
+			return fmt.Errorf("assertions[%d]: flow_token is not supported for %s", index, a.Type)
+		}
+	}
+
 	return nil
 }
@@ -410,6 +410,89 @@ assertions:
 	assert.Contains(t, err.Error(), "flow[0].expect: case is required")
 }
 
+func TestLoadScenario_ExpectError(t *testing.T) {
+	tests := []struct {
+		name     string
+		stepYAML string
+		wantCode string
+		wantErr  string
+	}{
+		{
+			name: "valid",
+			stepYAML: `
+    expect_error:
+      code: CYCLE_DETECTED
+`,
+			wantCode: "CYCLE_DETECTED",
+		},
+		{
+			name: "binding_explosion",
+			stepYAML: `
+    expect_error:
+      code: BINDING_EXPLOSION
+`,
+			wantCode: "BINDING_EXPLOSION",
+		},
+		{
+			name: "with_expect",
+			stepYAML: `
+    expect:
+      case: Success
+    expect_error:
+      code: CYCLE_DETECTED
+`,
+			wantErr: "flow[0]: expect and expect_error are mutually exclusive",
+		},
+		{
+			name: "missing_code",
+			stepYAML: `
+    expect_error: {}
+`,
+			wantErr: "flow[0].expect_error: code is required",
+		},
+		{
+			name: "unknown_code",
+			stepYAML: `
+    expect_error:
+      code: NOT_A_CODE
+`,
+			wantErr: `flow[0].expect_error: unknown error code "NOT_A_CODE"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			specPath := createTestSpec(t, dir, "cart.concept.cue")
+			scenarioPath := filepath.Join(dir, "test.yaml")
+
+			content := `
+name: test
+description: "Test"
+specs:
+  - ` + specPath + `
+flow:
+  - invoke: Cart.addItem
+    args: {}` + tt.stepYAML + `
+assertions:
+  - type: trace_contains
+    action: Cart.addItem
+`
+			require.NoError(t, os.WriteFile(scenarioPath, []byte(content), 0644))
+
+			scenario, err := LoadScenario(scenarioPath)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				require.NotNil(t, scenario.Flow[0].ExpectError)
+				assert.Equal(t, tt.wantCode, scenario.Flow[0].ExpectError.Code)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestLoadScenario_FixedFlowToken(t *testing.T) {
 	dir := t.TempDir()
 	specPath := createTestSpec(t, dir, "cart.concept.cue")
@@ -460,6 +543,52 @@ func TestLoadScenario_AssertionTypes(t *testing.T) {
 `,
 			wantErr: "action is required for trace_contains",
 		},
+		{
+			name: "trace_not_contains_valid",
+			assertionYAML: `
+  - type: trace_not_contains
+    action: Cart.checkout
+    args:
+      cart_id: "abc"
+`,
+			wantErr: "",
+		},
+		{
+			name: "trace_not_contains_without_args",
+			assertionYAML: `
+  - type: trace_not_contains
+    action: Cart.checkout
+`,
+			wantErr: "",
+		},
+		{
+			name: "trace_not_contains_missing_action",
+			assertionYAML: `
+  - type: trace_not_contains
+    args:
+      cart_id: "abc"
+`,
+			wantErr: "action is required for trace_not_contains",
+		},
+		{
+			name: "trace_args_all_valid",
+			assertionYAML: `
+  - type: trace_args_all
+    action: Notification.send
+    args:
+      channel: email
+`,
+			wantErr: "",
+		},
+		{
+			name: "trace_args_all_missing_action",
+			assertionYAML: `
+  - type: trace_args_all
+    args:
+      channel: email
+`,
+			wantErr: "action is required for trace_args_all",
+		},
 		{
 			name: "trace_order_valid",
 			assertionYAML: `
@@ -562,6 +691,59 @@ func TestLoadScenario_AssertionTypes(t *testing.T) {
 `,
 			wantErr: "expect is required for final_state",
 		},
+		{
+			name: "state_count_valid",
+			assertionYAML: `
+  - type: state_count
+    table: cart_items
+    where:
+      cart_id: "abc"
+    count: 3
+`,
+			wantErr: "",
+		},
+		{
+			name: "state_count_missing_table",
+			assertionYAML: `
+  - type: state_count
+    count: 3
+`,
+			wantErr: "table is required for state_count",
+		},
+		{
+			name: "state_count_negative_count",
+			assertionYAML: `
+  - type: state_count
+    table: cart_items
+    count: -1
+`,
+			wantErr: "count must be non-negative for state_count",
+		},
+		{
+			name: "provenance_valid",
+			assertionYAML: `
+  - type: provenance
+    cause: Cart.checkout
+    effect: Inventory.reserve
+`,
+			wantErr: "",
+		},
+		{
+			name: "provenance_missing_cause",
+			assertionYAML: `
+  - type: provenance
+    effect: Inventory.reserve
+`,
+			wantErr: "cause is required for provenance",
+		},
+		{
+			name: "provenance_missing_effect",
+			assertionYAML: `
+  - type: provenance
+    cause: Cart.checkout
+`,
+			wantErr: "effect is required for provenance",
+		},
 		{
 			name: "unknown_type",
 			assertionYAML: `
@@ -978,11 +1160,399 @@ assertions:
 	assert.Contains(t, err.Error(), "count must be non-negative")
 }
 
+func TestLoadScenario_JSONMatchesYAML(t *testing.T) {
+	dir := t.TempDir()
+	specPath := createTestSpec(t, dir, "cart.concept.cue")
+
+	yamlContent := fmt.Sprintf(`
+name: json_parity
+description: "Same scenario in both formats"
+specs: [%s]
+flow_token: flow-parity
+flow:
+  - invoke: Cart.addItem
+    args:
+      item_id: widget
+      quantity: 3
+    expect:
+      case: Success
+      result:
+        new_quantity: 3
+assertions:
+  - type: trace_count
+    action: Cart.addItem
+    count: 1
+`, specPath)
+
+	jsonContent := fmt.Sprintf(`{
+  "name": "json_parity",
+  "description": "Same scenario in both formats",
+  "specs": [%q],
+  "flow_token": "flow-parity",
+  "flow": [
+    {
+      "invoke": "Cart.addItem",
+      "args": {"item_id": "widget", "quantity": 3},
+      "expect": {"case": "Success", "result": {"new_quantity": 3}}
+    }
+  ],
+  "assertions": [
+    {"type": "trace_count", "action": "Cart.addItem", "count": 1}
+  ]
+}`, specPath)
+
+	yamlPath := filepath.Join(dir, "parity.yaml")
+	jsonPath := filepath.Join(dir, "parity.json")
+	require.NoError(t, os.WriteFile(yamlPath, []byte(yamlContent), 0644))
+	require.NoError(t, os.WriteFile(jsonPath, []byte(jsonContent), 0644))
+
+	fromYAML, err := LoadScenario(yamlPath)
+	require.NoError(t, err)
+	fromJSON, err := LoadScenario(jsonPath)
+	require.NoError(t, err)
+
+	// JSON numbers decode as float64 and YAML integers as int; both convert
+	// to the same IRInt when the scenario runs.
+	assert.Equal(t, fromYAML.Name, fromJSON.Name)
+	assert.Equal(t, fromYAML.FlowToken, fromJSON.FlowToken)
+	assert.Equal(t, fromYAML.Assertions, fromJSON.Assertions)
+	require.Len(t, fromJSON.Flow, 1)
+	assert.Equal(t, "Success", fromJSON.Flow[0].Expect.Case)
+
+	yamlArgs, err := convertArgsToIRObject(fromYAML.Flow[0].Args)
+	require.NoError(t, err)
+	jsonArgs, err := convertArgsToIRObject(fromJSON.Flow[0].Args)
+	require.NoError(t, err)
+	assert.Equal(t, yamlArgs, jsonArgs)
+}
+
+func TestLoadScenario_JSONValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name: "missing_name",
+			content: `{
+  "description": "Missing name",
+  "specs": [%q],
+  "flow": [{"invoke": "Cart.addItem", "args": {}}],
+  "assertions": [{"type": "trace_contains", "action": "Cart.addItem"}]
+}`,
+			wantErr: "name is required",
+		},
+		{
+			name: "unknown_field",
+			content: `{
+  "name": "test",
+  "description": "Typo in assertions key",
+  "specs": [%q],
+  "flow": [{"invoke": "Cart.addItem", "args": {}}],
+  "assertion": [{"type": "trace_contains", "action": "Cart.addItem"}]
+}`,
+			wantErr: `unknown field "assertion"`,
+		},
+		{
+			name: "trace_count_negative",
+			content: `{
+  "name": "test",
+  "description": "Negative count",
+  "specs": [%q],
+  "flow": [{"invoke": "Cart.addItem", "args": {}}],
+  "assertions": [{"type": "trace_count", "action": "Cart.checkout", "count": -1}]
+}`,
+			wantErr: "count must be non-negative",
+		},
+		{
+			name: "malformed",
+			content: `{
+  "name": "test",
+  "specs": [%q],
+}`,
+			wantErr: "failed to parse JSON",
+		},
+		{
+			name: "trailing_data",
+			content: `{
+  "name": "test",
+  "description": "Two objects",
+  "specs": [%q],
+  "flow": [{"invoke": "Cart.addItem", "args": {}}],
+  "assertions": [{"type": "trace_contains", "action": "Cart.addItem"}]
+} {}`,
+			wantErr: "unexpected data after scenario object",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			specPath := createTestSpec(t, dir, "cart.concept.cue")
+			scenarioPath := filepath.Join(dir, "test.json")
+			require.NoError(t, os.WriteFile(scenarioPath, []byte(fmt.Sprintf(tt.content, specPath)), 0644))
+
+			_, err := LoadScenario(scenarioPath)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestLoadScenario_Fixtures(t *testing.T) {
+	dir := t.TempDir()
+	specPath := createTestSpec(t, dir, "cart.concept.cue")
+
+	// base.yaml includes stock.yaml; the scenario includes base.yaml then promo.yaml
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "stock.yaml"), []byte(`
+setup:
+  - action: Inventory.setStock
+    args: {item_id: widget, quantity: 10}
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "base.yaml"), []byte(`
+fixtures: [stock.yaml]
+setup:
+  - action: Cart.create
+    args: {cart_id: c1}
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "promo.json"), []byte(`{
+  "setup": [{"action": "Inventory.setStock", "args": {"item_id": "widget", "quantity": 20}}]
+}`), 0644))
+
+	scenarioPath := filepath.Join(dir, "test.yaml")
+	content := fmt.Sprintf(`
+name: with_fixtures
+description: "Shared fixtures"
+specs: [%s]
+fixtures: [base.yaml, promo.json]
+setup:
+  - action: Cart.addItem
+    args: {item_id: widget}
+flow:
+  - invoke: Cart.checkout
+    args: {}
+assertions:
+  - type: trace_contains
+    action: Cart.checkout
+`, specPath)
+	require.NoError(t, os.WriteFile(scenarioPath, []byte(content), 0644))
+
+	scenario, err := LoadScenarioWithBasePath(scenarioPath, dir)
+	require.NoError(t, err)
+
+	actions := make([]string, len(scenario.FixtureSetup))
+	for i, step := range scenario.FixtureSetup {
+		actions[i] = step.Action
+	}
+	assert.Equal(t, []string{"Inventory.setStock", "Cart.create", "Inventory.setStock"}, actions)
+	assert.Equal(t, 20, int(scenario.FixtureSetup[2].Args["quantity"].(float64)))
+	require.Len(t, scenario.Setup, 1)
+}
+
+func TestLoadScenario_FixtureNotFound(t *testing.T) {
+	dir := t.TempDir()
+	specPath := createTestSpec(t, dir, "cart.concept.cue")
+	scenarioPath := filepath.Join(dir, "test.yaml")
+
+	content := fmt.Sprintf(`
+name: test
+description: "Missing fixture"
+specs: [%s]
+fixtures: [missing.yaml]
+flow:
+  - invoke: Cart.addItem
+    args: {}
+assertions:
+  - type: trace_contains
+    action: Cart.addItem
+`, specPath)
+	require.NoError(t, os.WriteFile(scenarioPath, []byte(content), 0644))
+
+	_, err := LoadScenarioWithBasePath(scenarioPath, dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "fixture file not found")
+	assert.Contains(t, err.Error(), "missing.yaml")
+}
+
+func TestLoadScenario_FixtureCycle(t *testing.T) {
+	dir := t.TempDir()
+	specPath := createTestSpec(t, dir, "cart.concept.cue")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.yaml"), []byte("fixtures: [b.yaml]\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.yaml"), []byte("fixtures: [a.yaml]\n"), 0644))
+
+	scenarioPath := filepath.Join(dir, "test.yaml")
+	content := fmt.Sprintf(`
+name: test
+description: "Fixture cycle"
+specs: [%s]
+fixtures: [a.yaml]
+flow:
+  - invoke: Cart.addItem
+    args: {}
+assertions:
+  - type: trace_contains
+    action: Cart.addItem
+`, specPath)
+	require.NoError(t, os.WriteFile(scenarioPath, []byte(content), 0644))
+
+	_, err := LoadScenarioWithBasePath(scenarioPath, dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "fixture cycle detected")
+	assert.Contains(t, err.Error(), filepath.Join(dir, "a.yaml")+" -> "+filepath.Join(dir, "b.yaml")+" -> "+filepath.Join(dir, "a.yaml"))
+}
+
+func TestLoadScenario_FixtureIncludesScenario(t *testing.T) {
+	// A fixture that includes the scenario itself is a cycle too
+	dir := t.TempDir()
+	specPath := createTestSpec(t, dir, "cart.concept.cue")
+	scenarioPath := filepath.Join(dir, "test.yaml")
+
+	content := fmt.Sprintf(`
+name: test
+description: "Self include"
+specs: [%s]
+fixtures: [test.yaml]
+flow:
+  - invoke: Cart.addItem
+    args: {}
+assertions:
+  - type: trace_contains
+    action: Cart.addItem
+`, specPath)
+	require.NoError(t, os.WriteFile(scenarioPath, []byte(content), 0644))
+
+	_, err := LoadScenarioWithBasePath(scenarioPath, dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "fixture cycle detected")
+}
+
+func TestLoadScenario_FixtureInvalidSetup(t *testing.T) {
+	dir := t.TempDir()
+	specPath := createTestSpec(t, dir, "cart.concept.cue")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.yaml"), []byte(`
+setup:
+  - args: {}
+`), 0644))
+
+	scenarioPath := filepath.Join(dir, "test.yaml")
+	content := fmt.Sprintf(`
+name: test
+description: "Bad fixture"
+specs: [%s]
+fixtures: [bad.yaml]
+flow:
+  - invoke: Cart.addItem
+    args: {}
+assertions:
+  - type: trace_contains
+    action: Cart.addItem
+`, specPath)
+	require.NoError(t, os.WriteFile(scenarioPath, []byte(content), 0644))
+
+	_, err := LoadScenarioWithBasePath(scenarioPath, dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "setup[0]: action is required")
+}
+
+func TestLoadScenario_MaxSteps(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxSteps string
+		want     *int
+		wantErr  string
+	}{
+		{name: "omitted", maxSteps: "", want: nil},
+		{name: "positive", maxSteps: "max_steps: 5000\n", want: func() *int { v := 5000; return &v }()},
+		{name: "zero", maxSteps: "max_steps: 0\n", wantErr: "max_steps must be a positive integer, got 0"},
+		{name: "negative", maxSteps: "max_steps: -5\n", wantErr: "max_steps must be a positive integer, got -5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			specPath := createTestSpec(t, dir, "cart.concept.cue")
+			scenarioPath := filepath.Join(dir, "test.yaml")
+
+			content := fmt.Sprintf(`
+name: test
+description: "Max steps override"
+specs: [%s]
+%sflow:
+  - invoke: Cart.addItem
+    args: {}
+assertions:
+  - type: trace_contains
+    action: Cart.addItem
+`, specPath, tt.maxSteps)
+			require.NoError(t, os.WriteFile(scenarioPath, []byte(content), 0644))
+
+			scenario, err := LoadScenario(scenarioPath)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, scenario.MaxSteps)
+		})
+	}
+}
+
+func TestLoadScenario_ClockStart(t *testing.T) {
+	tests := []struct {
+		name       string
+		clockStart string
+		want       *int64
+		wantErr    string
+	}{
+		{name: "omitted", clockStart: "", want: nil},
+		{name: "zero", clockStart: "clock_start: 0\n", want: func() *int64 { v := int64(0); return &v }()},
+		{name: "positive", clockStart: "clock_start: 100\n", want: func() *int64 { v := int64(100); return &v }()},
+		{name: "negative", clockStart: "clock_start: -1\n", wantErr: "clock_start must be a non-negative integer, got -1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			specPath := createTestSpec(t, dir, "cart.concept.cue")
+			scenarioPath := filepath.Join(dir, "test.yaml")
+
+			content := fmt.Sprintf(`
+name: test
+description: "Clock start override"
+specs: [%s]
+%sflow:
+  - invoke: Cart.addItem
+    args: {}
+assertions:
+  - type: trace_contains
+    action: Cart.addItem
+`, specPath, tt.clockStart)
+			require.NoError(t, os.WriteFile(scenarioPath, []byte(content), 0644))
+
+			scenario, err := LoadScenario(scenarioPath)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, scenario.ClockStart)
+		})
+	}
+}
+
 func TestAssertionConstants(t *testing.T) {
 	assert.Equal(t, "trace_contains", AssertTraceContains)
+	assert.Equal(t, "trace_not_contains", AssertTraceNotContains)
+	assert.Equal(t, "trace_args_all", AssertTraceArgsAll)
 	assert.Equal(t, "trace_order", AssertTraceOrder)
 	assert.Equal(t, "trace_count", AssertTraceCount)
 	assert.Equal(t, "final_state", AssertFinalState)
+	assert.Equal(t, "state_count", AssertStateCount)
+	assert.Equal(t, "provenance", AssertProvenance)
 }
 
 // TestLoadExampleScenarios validates the example scenario files in testdata/scenarios.
@@ -1030,3 +1600,290 @@ func TestLoadExampleScenarios(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadScenario_SyncCountValidation(t *testing.T) {
+	dir := t.TempDir()
+	specPath := createTestSpec(t, dir, "cart.cue")
+
+	tests := []struct {
+		name      string
+		assertion string
+		wantErr   string
+	}{
+		{
+			name:      "valid",
+			assertion: "sync_id: sync-reserve\n    count: 1",
+		},
+		{
+			name:      "zero_allowed",
+			assertion: "sync_id: sync-reserve\n    count: 0",
+		},
+		{
+			name:      "missing_sync_id",
+			assertion: "count: 1",
+			wantErr:   "sync_id is required for sync_count",
+		},
+		{
+			name:      "negative_count",
+			assertion: "sync_id: sync-reserve\n    count: -1",
+			wantErr:   "count must be non-negative for sync_count",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := fmt.Sprintf(`
+name: sync_count
+description: Test sync_count validation
+specs: [%s]
+flow:
+  - invoke: Cart.addItem
+    args: {}
+assertions:
+  - type: sync_count
+    %s
+`, specPath, tt.assertion)
+
+			scenarioPath := filepath.Join(dir, tt.name+".yaml")
+			require.NoError(t, os.WriteFile(scenarioPath, []byte(content), 0644))
+
+			scenario, err := LoadScenario(scenarioPath)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "sync-reserve", scenario.Assertions[0].SyncID)
+		})
+	}
+}
+
+func TestLoadScenario_SeqBeforeValidation(t *testing.T) {
+	dir := t.TempDir()
+	specPath := createTestSpec(t, dir, "cart.cue")
+
+	tests := []struct {
+		name      string
+		assertion string
+		wantErr   string
+	}{
+		{
+			name:      "valid",
+			assertion: "earlier_action: Cart.addItem\n    later_action: Cart.checkout",
+		},
+		{
+			name:      "missing_earlier_action",
+			assertion: "later_action: Cart.checkout",
+			wantErr:   "earlier_action is required for seq_before",
+		},
+		{
+			name:      "missing_later_action",
+			assertion: "earlier_action: Cart.addItem",
+			wantErr:   "later_action is required for seq_before",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := fmt.Sprintf(`
+name: seq_before
+description: Test seq_before validation
+specs: [%s]
+flow:
+  - invoke: Cart.addItem
+    args: {}
+assertions:
+  - type: seq_before
+    %s
+`, specPath, tt.assertion)
+
+			scenarioPath := filepath.Join(dir, tt.name+".yaml")
+			require.NoError(t, os.WriteFile(scenarioPath, []byte(content), 0644))
+
+			scenario, err := LoadScenario(scenarioPath)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "Cart.addItem", scenario.Assertions[0].EarlierAction)
+			assert.Equal(t, "Cart.checkout", scenario.Assertions[0].LaterAction)
+		})
+	}
+}
+
+func TestLoadScenario_InvocationSecurityValidation(t *testing.T) {
+	dir := t.TempDir()
+	specPath := createTestSpec(t, dir, "cart.cue")
+
+	tests := []struct {
+		name      string
+		assertion string
+		wantErr   string
+	}{
+		{
+			name:      "valid",
+			assertion: "action: Inventory.reserve\n    tenant_id: acme\n    user_id: alice",
+		},
+		{
+			name:      "missing_action",
+			assertion: "tenant_id: acme\n    user_id: alice",
+			wantErr:   "action is required for invocation_security",
+		},
+		{
+			name:      "missing_tenant_id",
+			assertion: "action: Inventory.reserve\n    user_id: alice",
+			wantErr:   "tenant_id is required for invocation_security",
+		},
+		{
+			name:      "missing_user_id",
+			assertion: "action: Inventory.reserve\n    tenant_id: acme",
+			wantErr:   "user_id is required for invocation_security",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := fmt.Sprintf(`
+name: invocation_security
+description: Test invocation_security validation
+specs: [%s]
+flow:
+  - invoke: Cart.checkout
+    args: {}
+assertions:
+  - type: invocation_security
+    %s
+`, specPath, tt.assertion)
+
+			scenarioPath := filepath.Join(dir, tt.name+".yaml")
+			require.NoError(t, os.WriteFile(scenarioPath, []byte(content), 0644))
+
+			scenario, err := LoadScenario(scenarioPath)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "acme", scenario.Assertions[0].TenantID)
+			assert.Equal(t, "alice", scenario.Assertions[0].UserID)
+		})
+	}
+}
+
+func TestLoadScenario_CompletionResultValidation(t *testing.T) {
+	dir := t.TempDir()
+	specPath := createTestSpec(t, dir, "cart.cue")
+
+	tests := []struct {
+		name      string
+		assertion string
+		wantErr   string
+	}{
+		{
+			name:      "valid",
+			assertion: "action: Inventory.reserve\n    where: { item_id: widget }\n    expect: { quantity: 2 }",
+		},
+		{
+			name:      "missing_action",
+			assertion: "expect: { quantity: 2 }",
+			wantErr:   "action is required for completion_result",
+		},
+		{
+			name:      "missing_expect",
+			assertion: "action: Inventory.reserve",
+			wantErr:   "expect is required for completion_result",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := fmt.Sprintf(`
+name: completion_result
+description: Test completion_result validation
+specs: [%s]
+flow:
+  - invoke: Cart.checkout
+    args: {}
+assertions:
+  - type: completion_result
+    %s
+`, specPath, tt.assertion)
+
+			scenarioPath := filepath.Join(dir, tt.name+".yaml")
+			require.NoError(t, os.WriteFile(scenarioPath, []byte(content), 0644))
+
+			scenario, err := LoadScenario(scenarioPath)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "widget", scenario.Assertions[0].Where["item_id"])
+			assert.Equal(t, 2, scenario.Assertions[0].Expect["quantity"])
+		})
+	}
+}
+
+func TestLoadScenario_FlowTokenScoping(t *testing.T) {
+	dir := t.TempDir()
+	specPath := createTestSpec(t, dir, "cart.cue")
+
+	tests := []struct {
+		name      string
+		assertion string
+		wantErr   string
+	}{
+		{
+			name:      "trace_count",
+			assertion: "type: trace_count\n    action: Cart.checkout\n    count: 1\n    flow_token: flow-b",
+		},
+		{
+			name:      "flow_complete",
+			assertion: "type: flow_complete\n    flow_token: flow-b",
+		},
+		{
+			name:      "final_state",
+			assertion: "type: final_state\n    table: carts\n    expect: {id: c1}\n    flow_token: flow-b",
+			wantErr:   "assertions[0]: flow_token is not supported for final_state",
+		},
+		{
+			name:      "sync_count",
+			assertion: "type: sync_count\n    sync_id: s1\n    count: 1\n    flow_token: flow-b",
+			wantErr:   "assertions[0]: flow_token is not supported for sync_count",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := fmt.Sprintf(`
+name: flow_token_scoping
+description: Test flow_token on steps and assertions
+specs: [%s]
+flow:
+  - invoke: Cart.checkout
+    args: {}
+    flow_token: flow-b
+assertions:
+  - %s
+`, specPath, tt.assertion)
+
+			scenarioPath := filepath.Join(dir, tt.name+".yaml")
+			require.NoError(t, os.WriteFile(scenarioPath, []byte(content), 0644))
+
+			scenario, err := LoadScenario(scenarioPath)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "flow-b", scenario.Flow[0].FlowToken)
+			assert.Equal(t, "flow-b", scenario.Assertions[0].FlowToken)
+		})
+	}
+}
@@ -11,6 +11,7 @@ type Event interface {
 // This provides a concrete type for the trace slice.
 type TraceEvent struct {
 	Type       string      `json:"type"` // "invocation" or "completion"
+	FlowToken  string      `json:"flow_token,omitempty"`
 	ActionURI  string      `json:"action_uri,omitempty"`
 	Args       interface{} `json:"args,omitempty"`
 	OutputCase string      `json:"output_case,omitempty"`
@@ -20,6 +21,9 @@ type TraceEvent struct {
 
 // Result is the outcome of a test scenario execution.
 type Result struct {
+	// Scenario is the name of the scenario that produced this result.
+	Scenario string `json:"scenario,omitempty"`
+
 	// Pass indicates overall test success.
 	// True if all expect clauses match.
 	Pass bool `json:"pass"`
@@ -35,6 +39,10 @@ type Result struct {
 	// State contains final state tables for state assertions.
 	// Keys are table names, values are query results.
 	State map[string]interface{} `json:"state,omitempty"`
+
+	// assertionErrs holds one entry per scenario assertion (nil = passed),
+	// recorded by Run for structured reporting.
+	assertionErrs []error
 }
 
 // NewResult creates a new passing result.
@@ -54,20 +62,22 @@ func (r *Result) AddError(err string) {
 	r.Pass = false
 }
 
-// AddInvocationTrace adds an invocation to the trace.
-func (r *Result) AddInvocationTrace(actionURI string, args interface{}, seq int64) {
+// AddInvocationTrace adds an invocation in flowToken to the trace.
+func (r *Result) AddInvocationTrace(flowToken, actionURI string, args interface{}, seq int64) {
 	r.Trace = append(r.Trace, TraceEvent{
 		Type:      "invocation",
+		FlowToken: flowToken,
 		ActionURI: actionURI,
 		Args:      args,
 		Seq:       seq,
 	})
 }
 
-// AddCompletionTrace adds a completion to the trace.
-func (r *Result) AddCompletionTrace(outputCase string, result interface{}, seq int64) {
+// AddCompletionTrace adds a completion in flowToken to the trace.
+func (r *Result) AddCompletionTrace(flowToken, outputCase string, result interface{}, seq int64) {
 	r.Trace = append(r.Trace, TraceEvent{
 		Type:       "completion",
+		FlowToken:  flowToken,
 		OutputCase: outputCase,
 		Result:     result,
 		Seq:        seq,
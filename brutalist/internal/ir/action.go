@@ -3,8 +3,10 @@ package ir
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"slices"
+	"strings"
 )
 
 // ValidTypes defines the allowed type strings for action args and output fields.
@@ -63,7 +65,9 @@ func (a *ActionSig) Validate() []ValidationError {
 	}
 
 	// Validate args types
+	argTypes := make(map[string]string, len(a.Args))
 	for i, arg := range a.Args {
+		argTypes[arg.Name] = arg.Type
 		if !ValidTypes[arg.Type] {
 			errs = append(errs, ValidationError{
 				Field:   fmt.Sprintf("args[%d].type", i),
@@ -72,17 +76,150 @@ func (a *ActionSig) Validate() []ValidationError {
 		}
 	}
 
+	// Rule: Enums constrain declared string args and list at least one value
+	for _, name := range sortedFieldNames(a.Enums) {
+		field := "enums." + name
+		argType, ok := argTypes[name]
+		switch {
+		case !ok:
+			errs = append(errs, ValidationError{Field: field, Message: fmt.Sprintf("enum for undeclared arg %q", name)})
+		case argType != "string":
+			errs = append(errs, ValidationError{Field: field, Message: fmt.Sprintf("enum on %s arg %q, only string args can be enums", argType, name)})
+		case len(a.Enums[name]) == 0:
+			errs = append(errs, ValidationError{Field: field, Message: "enum has no allowed values"})
+		}
+	}
+
 	return errs
 }
 
+// ValidateArgs checks invocation args against the enum constraints of the
+// signature: an enum-constrained arg, when present, must be a string among
+// its allowed values. Other args are not checked.
+// Returns all problems joined (not fail-fast), each a ValidationError.
+func (a *ActionSig) ValidateArgs(args IRObject) error {
+	var errs []error
+	for _, name := range sortedFieldNames(a.Enums) {
+		v, ok := args[name]
+		if !ok {
+			continue
+		}
+		if err := checkEnum("args."+name, a.Enums[name], v); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkEnum reports a ValidationError for field unless v is an IRString
+// among allowed.
+func checkEnum(field string, allowed []string, v IRValue) error {
+	s, ok := v.(IRString)
+	if !ok {
+		return ValidationError{
+			Field:   field,
+			Message: fmt.Sprintf("enum value must be a string, got %T", v),
+		}
+	}
+	if !slices.Contains(allowed, string(s)) {
+		return ValidationError{
+			Field:   field,
+			Message: fmt.Sprintf("value %q is not one of: %s", string(s), strings.Join(allowed, ", ")),
+		}
+	}
+	return nil
+}
+
+// ValidateResult checks a completion result against the declared fields of
+// the named output case: the case must exist, every declared field must be
+// present, no undeclared field may appear, and each value must match its
+// declared type. Values of any other Go type (such as a smuggled float) are
+// rejected per CP-5.
+// Returns all problems joined (not fail-fast), each a ValidationError.
+func (a *ActionSig) ValidateResult(outputCase string, result IRObject) error {
+	idx := slices.IndexFunc(a.Outputs, func(o OutputCase) bool { return o.Case == outputCase })
+	if idx < 0 {
+		return ValidationError{
+			Field:   "output_case",
+			Message: fmt.Sprintf("action %q has no output case %q", a.Name, outputCase),
+		}
+	}
+	declared := a.Outputs[idx].Fields
+
+	var errs []error
+	for _, name := range sortedFieldNames(declared) {
+		if _, ok := result[name]; !ok {
+			errs = append(errs, ValidationError{
+				Field:   "result." + name,
+				Message: fmt.Sprintf("missing field declared by output case %q", outputCase),
+			})
+		}
+	}
+	for _, name := range sortedFieldNames(result) {
+		wantType, ok := declared[name]
+		if !ok {
+			errs = append(errs, ValidationError{
+				Field:   "result." + name,
+				Message: fmt.Sprintf("field not declared by output case %q", outputCase),
+			})
+			continue
+		}
+		gotType := valueTypeName(result[name])
+		if gotType == "" {
+			errs = append(errs, ValidationError{
+				Field:   "result." + name,
+				Message: fmt.Sprintf("unsupported value type %T, only string, int, bool, array, object are allowed (no floats, CP-5)", result[name]),
+			})
+			continue
+		}
+		if gotType != wantType {
+			errs = append(errs, ValidationError{
+				Field:   "result." + name,
+				Message: fmt.Sprintf("type mismatch: got %s, declared %s", gotType, wantType),
+			})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// valueTypeName returns the declared-type name ("string", "int", ...) of an
+// IRValue, or "" for null and for any type outside the IR.
+func valueTypeName(v IRValue) string {
+	switch v.(type) {
+	case IRString:
+		return "string"
+	case IRInt:
+		return "int"
+	case IRBool:
+		return "bool"
+	case IRArray:
+		return "array"
+	case IRObject:
+		return "object"
+	default:
+		return ""
+	}
+}
+
+// sortedFieldNames returns map keys in sorted order for deterministic errors.
+func sortedFieldNames[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
 // MarshalJSON produces JSON with sorted keys for determinism.
-// Fields are in alphabetical order: args, name, outputs, requires (if non-empty).
+// Fields are in alphabetical order: args, enums (if non-empty), name,
+// outputs, requires (if non-empty).
 // NOTE: This is NOT canonical marshaling. Use MarshalCanonical (Story 1-4) for hashing.
 func (a ActionSig) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	buf.WriteByte('{')
 
-	// Fixed field order: args, name, outputs, requires (alphabetical)
+	// Fixed field order: args, enums, name, outputs, requires (alphabetical)
 	buf.WriteString(`"args":`)
 	argsBytes, err := json.Marshal(a.Args)
 	if err != nil {
@@ -90,6 +227,17 @@ func (a ActionSig) MarshalJSON() ([]byte, error) {
 	}
 	buf.Write(argsBytes)
 
+	// Only include enums if non-empty (omitempty behavior); map keys are
+	// sorted by encoding/json
+	if len(a.Enums) > 0 {
+		buf.WriteString(`,"enums":`)
+		enumsBytes, err := json.Marshal(a.Enums)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(enumsBytes)
+	}
+
 	buf.WriteString(`,"name":`)
 	nameBytes, err := json.Marshal(a.Name)
 	if err != nil {
@@ -102,6 +102,38 @@ func TestActionSigValidation(t *testing.T) {
 			},
 			wantErrs: 3,
 		},
+		{
+			name: "valid enum arg",
+			action: ActionSig{
+				Name:  "ship",
+				Args:  []NamedArg{{Name: "status", Type: "string"}},
+				Enums: map[string][]string{"status": {"pending", "shipped"}},
+				Outputs: []OutputCase{
+					{Case: "Success", Fields: map[string]string{}},
+				},
+			},
+			wantErrs: 0,
+		},
+		{
+			name: "invalid enums",
+			action: ActionSig{
+				Name: "ship",
+				Args: []NamedArg{
+					{Name: "count", Type: "int"},
+					{Name: "status", Type: "string"},
+				},
+				Enums: map[string][]string{
+					"count":   {"1"}, // error 1: not a string arg
+					"missing": {"a"}, // error 2: undeclared arg
+					"status":  {},    // error 3: no values
+				},
+				Outputs: []OutputCase{
+					{Case: "Success", Fields: map[string]string{}},
+				},
+			},
+			wantErrs: 3,
+			errField: "enums.count",
+		},
 		{
 			name: "all valid types",
 			action: ActionSig{
@@ -189,6 +221,49 @@ func TestActionSigJSONSortedKeys(t *testing.T) {
 	assert.Equal(t, expected, string(data))
 }
 
+func TestActionSigJSONWithEnums(t *testing.T) {
+	action := ActionSig{
+		Name:  "ship",
+		Args:  []NamedArg{{Name: "status", Type: "string"}},
+		Enums: map[string][]string{"status": {"pending", "shipped"}},
+		Outputs: []OutputCase{
+			{Case: "Success", Fields: map[string]string{}},
+		},
+	}
+
+	data, err := json.Marshal(action)
+	require.NoError(t, err)
+
+	expected := `{"args":[{"name":"status","type":"string"}],"enums":{"status":["pending","shipped"]},"name":"ship","outputs":[{"case":"Success","fields":{}}]}`
+	assert.Equal(t, expected, string(data))
+
+	var decoded ActionSig
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, action, decoded)
+}
+
+func TestActionSigValidateArgs(t *testing.T) {
+	sig := ActionSig{
+		Name:  "ship",
+		Args:  []NamedArg{{Name: "status", Type: "string"}, {Name: "note", Type: "string"}},
+		Enums: map[string][]string{"status": {"pending", "shipped"}},
+	}
+
+	assert.NoError(t, sig.ValidateArgs(IRObject{"status": IRString("shipped"), "note": IRString("anything")}))
+	assert.NoError(t, sig.ValidateArgs(IRObject{}), "absent enum args are not checked")
+
+	err := sig.ValidateArgs(IRObject{"status": IRString("lost")})
+	require.Error(t, err)
+	var verr ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, "args.status", verr.Field)
+	assert.Contains(t, err.Error(), `value "lost" is not one of: pending, shipped`)
+
+	err = sig.ValidateArgs(IRObject{"status": IRInt(1)})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "enum value must be a string")
+}
+
 func TestActionSigJSONWithRequires(t *testing.T) {
 	action := ActionSig{
 		Name: "checkout",
@@ -324,3 +399,87 @@ func TestActionSigWithNoArgs(t *testing.T) {
 	expected := `{"args":[],"name":"getStatus","outputs":[{"case":"Success","fields":{"status":"string"}}]}`
 	assert.Equal(t, expected, string(data))
 }
+
+// smuggledFloat satisfies IRValue by embedding a sanctioned type, which is
+// how a float can slip past the sealed interface.
+type smuggledFloat struct {
+	IRInt
+	f float64
+}
+
+func TestActionSigValidateResult(t *testing.T) {
+	sig := ActionSig{
+		Name: "reserve",
+		Outputs: []OutputCase{
+			{Case: "Success", Fields: map[string]string{"reservation_id": "string", "quantity": "int"}},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		outputCase string
+		result     IRObject
+		wantFields []string // Expected error fields, in order
+	}{
+		{
+			name:       "valid",
+			outputCase: "Success",
+			result:     IRObject{"reservation_id": IRString("r-1"), "quantity": IRInt(1)},
+		},
+		{
+			name:       "unknown output case",
+			outputCase: "Failure",
+			result:     IRObject{},
+			wantFields: []string{"output_case"},
+		},
+		{
+			name:       "missing and undeclared fields",
+			outputCase: "Success",
+			result:     IRObject{"reservation_id": IRString("r-1"), "note": IRString("x")},
+			wantFields: []string{"result.quantity", "result.note"},
+		},
+		{
+			name:       "type mismatch",
+			outputCase: "Success",
+			result:     IRObject{"reservation_id": IRInt(1), "quantity": IRInt(1)},
+			wantFields: []string{"result.reservation_id"},
+		},
+		{
+			name:       "null value",
+			outputCase: "Success",
+			result:     IRObject{"reservation_id": IRNull{}, "quantity": IRInt(1)},
+			wantFields: []string{"result.reservation_id"},
+		},
+		{
+			name:       "smuggled float",
+			outputCase: "Success",
+			result:     IRObject{"reservation_id": IRString("r-1"), "quantity": smuggledFloat{f: 1.5}},
+			wantFields: []string{"result.quantity"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := sig.ValidateResult(tt.outputCase, tt.result)
+			if len(tt.wantFields) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+
+			var errs []error
+			if joined, ok := err.(interface{ Unwrap() []error }); ok {
+				errs = joined.Unwrap()
+			} else {
+				errs = []error{err}
+			}
+			fields := make([]string, len(errs))
+			for i, e := range errs {
+				var verr ValidationError
+				require.ErrorAs(t, e, &verr)
+				fields[i] = verr.Field
+			}
+			assert.Equal(t, tt.wantFields, fields)
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package ir
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SortBindings sorts binding sets in place into a canonical order that does
+// not depend on how they were produced (e.g., database row order).
+//
+// Binding sets are compared field by field in keys order, using the
+// canonical JSON (CanonicalJSON) of each field's value; a binding set missing
+// a key sorts before one that has it. Ties are broken by the canonical JSON
+// of the whole binding set, so the result is fully determined by the binding
+// contents.
+//
+// The comparison is bytewise, not semantic: IRInt(10) sorts before IRInt(9).
+// That is sufficient for its purpose - the same inputs always yield the same
+// firing order and therefore the same seq assignment on replay (CP-2).
+func SortBindings(bindings []IRObject, keys []string) {
+	type sortable struct {
+		fields [][]byte // Canonical JSON per key (nil = key absent)
+		whole  []byte   // Canonical JSON of the full binding set
+		b      IRObject
+	}
+
+	items := make([]sortable, len(bindings))
+	for i, b := range bindings {
+		fields := make([][]byte, len(keys))
+		for j, k := range keys {
+			if v, ok := b[k]; ok {
+				fields[j] = bindingSortKey(v)
+			}
+		}
+		items[i] = sortable{fields: fields, whole: bindingSortKey(b), b: b}
+	}
+
+	slices.SortStableFunc(items, func(x, y sortable) int {
+		for j := range keys {
+			if c := bytes.Compare(x.fields[j], y.fields[j]); c != 0 {
+				return c
+			}
+		}
+		return bytes.Compare(x.whole, y.whole)
+	})
+
+	for i, item := range items {
+		bindings[i] = item.b
+	}
+}
+
+// bindingSortKey returns the bytes a binding value sorts by: its canonical
+// JSON. IRNull (a SQL NULL column) has no canonical form and sorts as "null";
+// values nesting one fall back to their Go formatting, which prints map keys
+// in sorted order and so stays deterministic.
+func bindingSortKey(v IRValue) []byte {
+	if v == nil {
+		return []byte("null")
+	}
+	if _, ok := v.(IRNull); ok {
+		return []byte("null")
+	}
+	data, err := CanonicalJSON(v)
+	if err != nil {
+		return []byte(fmt.Sprintf("%v", v))
+	}
+	return data
+}
+
+// SortArrayByCanonicalJSON returns a copy of arr sorted into a canonical
+// order, so an array built from unordered data (e.g., the objects returned by
+// a where-clause query) hashes to the same content ID however it was
+// produced (CP-2).
+//
+// Elements are ordered by type first: null, bool, int, string, array, then
+// object. Scalars of the same type compare by value (false before true,
+// integers numerically, strings bytewise); arrays and objects compare by
+// their canonical JSON bytes. The sort is stable, and arr is not modified.
+//
+// Any code that turns query results into an array arg must pass the array
+// through this function before generating the invocation. Arrays that carry
+// meaningful order (e.g., an action's ranked result) must not be sorted.
+func SortArrayByCanonicalJSON(arr IRArray) IRArray {
+	if arr == nil {
+		return nil
+	}
+	sorted := slices.Clone(arr)
+	slices.SortStableFunc(sorted, compareArrayElements)
+	return sorted
+}
+
+// compareArrayElements orders two array elements for SortArrayByCanonicalJSON.
+func compareArrayElements(a, b IRValue) int {
+	if c := cmp.Compare(arrayTypeRank(a), arrayTypeRank(b)); c != 0 {
+		return c
+	}
+	switch x := a.(type) {
+	case IRBool:
+		y := b.(IRBool)
+		if x == y {
+			return 0
+		}
+		if !x {
+			return -1
+		}
+		return 1
+	case IRInt:
+		return cmp.Compare(x, b.(IRInt))
+	case IRString:
+		return strings.Compare(string(x), string(b.(IRString)))
+	case IRArray, IRObject:
+		return bytes.Compare(bindingSortKey(a), bindingSortKey(b))
+	default:
+		return 0 // Both null
+	}
+}
+
+// arrayTypeRank returns the position of a value's type in the canonical
+// array order.
+func arrayTypeRank(v IRValue) int {
+	switch v.(type) {
+	case IRBool:
+		return 1
+	case IRInt:
+		return 2
+	case IRString:
+		return 3
+	case IRArray:
+		return 4
+	case IRObject:
+		return 5
+	default:
+		return 0 // IRNull or nil
+	}
+}
@@ -0,0 +1,154 @@
+package ir
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sortBindingsFixture() []IRObject {
+	return []IRObject{
+		{"cart": IRString("c1"), "item": IRString("apple"), "qty": IRInt(2)},
+		{"cart": IRString("c1"), "item": IRString("apple"), "qty": IRInt(1)},
+		{"cart": IRString("c1"), "item": IRString("banana"), "qty": IRInt(5)},
+		{"cart": IRString("c2"), "item": IRString("apple"), "qty": IRInt(3)},
+		{"cart": IRString("c2"), "item": IRNull{}, "qty": IRInt(4)},
+		{"cart": IRString("c2"), "qty": IRInt(6)},
+	}
+}
+
+func TestSortBindings_ShuffledInputsSortIdentically(t *testing.T) {
+	want := sortBindingsFixture()
+	SortBindings(want, []string{"item"})
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	for i := 0; i < 20; i++ {
+		got := sortBindingsFixture()
+		rng.Shuffle(len(got), func(a, b int) { got[a], got[b] = got[b], got[a] })
+
+		SortBindings(got, []string{"item"})
+		assert.Equal(t, want, got, "shuffle %d", i)
+	}
+}
+
+func TestSortBindings_OrdersByKeysThenWhole(t *testing.T) {
+	bindings := sortBindingsFixture()
+	SortBindings(bindings, []string{"item"})
+
+	assert.Equal(t, []IRObject{
+		// Missing key sorts first
+		{"cart": IRString("c2"), "qty": IRInt(6)},
+		// "apple" ties broken by the whole binding set
+		{"cart": IRString("c1"), "item": IRString("apple"), "qty": IRInt(1)},
+		{"cart": IRString("c1"), "item": IRString("apple"), "qty": IRInt(2)},
+		{"cart": IRString("c2"), "item": IRString("apple"), "qty": IRInt(3)},
+		{"cart": IRString("c1"), "item": IRString("banana"), "qty": IRInt(5)},
+		// IRNull sorts as the bytes "null", after quoted strings
+		{"cart": IRString("c2"), "item": IRNull{}, "qty": IRInt(4)},
+	}, bindings)
+}
+
+func TestSortBindings_KeyOrderMatters(t *testing.T) {
+	bindings := []IRObject{
+		{"a": IRString("x"), "b": IRString("2")},
+		{"a": IRString("y"), "b": IRString("1")},
+	}
+
+	SortBindings(bindings, []string{"b", "a"})
+	assert.Equal(t, IRString("y"), bindings[0]["a"])
+
+	SortBindings(bindings, []string{"a", "b"})
+	assert.Equal(t, IRString("x"), bindings[0]["a"])
+}
+
+func TestSortBindings_EmptyAndNoKeys(t *testing.T) {
+	SortBindings(nil, []string{"item"}) // must not panic
+
+	bindings := []IRObject{
+		{"item": IRString("b")},
+		{"item": IRString("a")},
+	}
+	SortBindings(bindings, nil)
+	assert.Equal(t, IRString("a"), bindings[0]["item"], "no keys still sorts by whole binding set")
+}
+
+func sortArrayFixture() IRArray {
+	return IRArray{
+		IRObject{"item_id": IRString("widget"), "quantity": IRInt(2)},
+		IRObject{"item_id": IRString("gadget"), "quantity": IRInt(1)},
+		IRObject{"item_id": IRString("gizmo"), "quantity": IRInt(5)},
+		IRArray{IRString("b"), IRString("a")},
+		IRArray{IRString("a")},
+		IRString("b"),
+		IRString("a"),
+		IRInt(10),
+		IRInt(9),
+		IRBool(true),
+		IRBool(false),
+		IRNull{},
+	}
+}
+
+func TestSortArrayByCanonicalJSON_ShuffledInputsSortIdentically(t *testing.T) {
+	want := SortArrayByCanonicalJSON(sortArrayFixture())
+
+	rng := rand.New(rand.NewPCG(3, 4))
+	for i := 0; i < 20; i++ {
+		shuffled := sortArrayFixture()
+		rng.Shuffle(len(shuffled), func(a, b int) { shuffled[a], shuffled[b] = shuffled[b], shuffled[a] })
+
+		assert.Equal(t, want, SortArrayByCanonicalJSON(shuffled), "shuffle %d", i)
+	}
+}
+
+func TestSortArrayByCanonicalJSON_OrdersByTypeThenValue(t *testing.T) {
+	assert.Equal(t, IRArray{
+		IRNull{},
+		IRBool(false),
+		IRBool(true),
+		// Integers compare numerically, not bytewise
+		IRInt(9),
+		IRInt(10),
+		IRString("a"),
+		IRString("b"),
+		// Arrays compare by canonical JSON; element order is kept
+		IRArray{IRString("a")},
+		IRArray{IRString("b"), IRString("a")},
+		// Objects compare by canonical JSON (keys sorted)
+		IRObject{"item_id": IRString("gadget"), "quantity": IRInt(1)},
+		IRObject{"item_id": IRString("gizmo"), "quantity": IRInt(5)},
+		IRObject{"item_id": IRString("widget"), "quantity": IRInt(2)},
+	}, SortArrayByCanonicalJSON(sortArrayFixture()))
+}
+
+func TestSortArrayByCanonicalJSON_DoesNotModifyInput(t *testing.T) {
+	arr := IRArray{IRString("b"), IRString("a")}
+
+	sorted := SortArrayByCanonicalJSON(arr)
+	assert.Equal(t, IRArray{IRString("a"), IRString("b")}, sorted)
+	assert.Equal(t, IRArray{IRString("b"), IRString("a")}, arr)
+
+	assert.Nil(t, SortArrayByCanonicalJSON(nil))
+	assert.Equal(t, IRArray{}, SortArrayByCanonicalJSON(IRArray{}))
+}
+
+func TestSortArrayByCanonicalJSON_StableInvocationID(t *testing.T) {
+	reserved := func(arr IRArray) IRObject {
+		return IRObject{"items": SortArrayByCanonicalJSON(arr)}
+	}
+	a := IRArray{
+		IRObject{"item_id": IRString("widget"), "quantity": IRInt(2)},
+		IRObject{"item_id": IRString("gadget"), "quantity": IRInt(1)},
+	}
+	b := IRArray{a[1], a[0]}
+
+	idA := MustInvocationID("flow-1", "Inventory.reserveAll", reserved(a), 3)
+	idB := MustInvocationID("flow-1", "Inventory.reserveAll", reserved(b), 3)
+	assert.Equal(t, idA, idB)
+
+	// Unsorted, the two orders hash differently
+	assert.NotEqual(t,
+		MustInvocationID("flow-1", "Inventory.reserveAll", IRObject{"items": a}, 3),
+		MustInvocationID("flow-1", "Inventory.reserveAll", IRObject{"items": b}, 3))
+}
@@ -0,0 +1,184 @@
+package ir
+
+import "fmt"
+
+// InvocationBuilder assembles an Invocation without repeating the
+// content-addressed ID and version fields by hand.
+//
+// Methods return a modified copy, so a partially configured builder can be
+// reused as a template:
+//
+//	inv, err := ir.NewInvocation("flow-1", "Cart.addItem").
+//		WithArgs(ir.IRObject{"item_id": ir.IRString("widget")}).
+//		WithSeq(1).
+//		WithSecurityContext(ir.NewSecurityContext("tenant-1", "user-1")).
+//		Build()
+type InvocationBuilder struct {
+	inv Invocation
+	sig *ActionSig // nil = no args validation
+}
+
+// NewInvocation starts an invocation of action within flowToken.
+// Args default to an empty object; EngineVersion and IRVersion are filled
+// from this package's constants.
+func NewInvocation(flowToken string, action ActionRef) InvocationBuilder {
+	return InvocationBuilder{inv: Invocation{
+		FlowToken:     flowToken,
+		ActionURI:     action,
+		Args:          IRObject{},
+		EngineVersion: EngineVersion,
+		IRVersion:     IRVersion,
+	}}
+}
+
+// WithArgs sets the invocation args. The args are deep-copied, so later
+// changes by the caller cannot drift from the computed ID. Nil is treated
+// as an empty object.
+func (b InvocationBuilder) WithArgs(args IRObject) InvocationBuilder {
+	if args == nil {
+		args = IRObject{}
+	}
+	b.inv.Args = CloneObject(args)
+	return b
+}
+
+// WithSeq sets the logical clock value (CP-2).
+func (b InvocationBuilder) WithSeq(seq int64) InvocationBuilder {
+	b.inv.Seq = seq
+	return b
+}
+
+// WithSecurityContext sets the security context (CP-6).
+func (b InvocationBuilder) WithSecurityContext(sc SecurityContext) InvocationBuilder {
+	b.inv.SecurityContext = sc
+	return b
+}
+
+// WithSpecHash sets the hash of the concept spec the invocation targets.
+func (b InvocationBuilder) WithSpecHash(specHash string) InvocationBuilder {
+	b.inv.SpecHash = specHash
+	return b
+}
+
+// WithActionSig enables args validation against sig's enum constraints (see
+// ActionSig.ValidateArgs). The signature is copied.
+func (b InvocationBuilder) WithActionSig(sig ActionSig) InvocationBuilder {
+	b.sig = &sig
+	return b
+}
+
+// Build validates the args (if an ActionSig was given), computes the
+// content-addressed ID via InvocationID, and returns the invocation.
+// Returns an error if the args cannot be canonically marshaled.
+func (b InvocationBuilder) Build() (Invocation, error) {
+	inv := b.inv
+	inv.Args = CloneObject(inv.Args)
+
+	if b.sig != nil {
+		if err := b.sig.ValidateArgs(inv.Args); err != nil {
+			return Invocation{}, fmt.Errorf("build invocation: %w", err)
+		}
+	}
+
+	id, err := InvocationID(inv.FlowToken, string(inv.ActionURI), inv.Args, inv.Seq)
+	if err != nil {
+		return Invocation{}, fmt.Errorf("build invocation: %w", err)
+	}
+	inv.ID = id
+	return inv, nil
+}
+
+// MustBuild is like Build but panics on error.
+// Use only in tests or when inputs are known to be valid.
+func (b InvocationBuilder) MustBuild() Invocation {
+	inv, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+	return inv
+}
+
+// CompletionBuilder assembles a Completion and computes its content-addressed
+// ID. When given the originating action's signature (WithActionSig), Build
+// also checks the result against the declared fields of the output case.
+//
+//	comp, err := ir.NewCompletion(inv.ID, "Success").
+//		WithResult(ir.IRObject{"reservation_id": ir.IRString("r-1")}).
+//		WithSeq(2).
+//		WithSecurityContext(inv.SecurityContext).
+//		WithActionSig(reserveSig).
+//		Build()
+type CompletionBuilder struct {
+	comp Completion
+	sig  *ActionSig // nil = no result validation
+}
+
+// NewCompletion starts a completion of invocationID with the given output
+// case. The result defaults to an empty object.
+func NewCompletion(invocationID, outputCase string) CompletionBuilder {
+	return CompletionBuilder{comp: Completion{
+		InvocationID: invocationID,
+		OutputCase:   outputCase,
+		Result:       IRObject{},
+	}}
+}
+
+// WithResult sets the completion result. The result is deep-copied, so
+// later changes by the caller cannot drift from the computed ID. Nil is
+// treated as an empty object.
+func (b CompletionBuilder) WithResult(result IRObject) CompletionBuilder {
+	if result == nil {
+		result = IRObject{}
+	}
+	b.comp.Result = CloneObject(result)
+	return b
+}
+
+// WithSeq sets the logical clock value (CP-2).
+func (b CompletionBuilder) WithSeq(seq int64) CompletionBuilder {
+	b.comp.Seq = seq
+	return b
+}
+
+// WithSecurityContext sets the security context (CP-6).
+func (b CompletionBuilder) WithSecurityContext(sc SecurityContext) CompletionBuilder {
+	b.comp.SecurityContext = sc
+	return b
+}
+
+// WithActionSig enables result validation against sig (see
+// ActionSig.ValidateResult). The signature is copied.
+func (b CompletionBuilder) WithActionSig(sig ActionSig) CompletionBuilder {
+	b.sig = &sig
+	return b
+}
+
+// Build validates the result (if an ActionSig was given), computes the
+// content-addressed ID via CompletionID, and returns the completion.
+func (b CompletionBuilder) Build() (Completion, error) {
+	comp := b.comp
+	comp.Result = CloneObject(comp.Result)
+
+	if b.sig != nil {
+		if err := b.sig.ValidateResult(comp.OutputCase, comp.Result); err != nil {
+			return Completion{}, fmt.Errorf("build completion: %w", err)
+		}
+	}
+
+	id, err := CompletionID(comp.InvocationID, comp.OutputCase, comp.Result, comp.Seq)
+	if err != nil {
+		return Completion{}, fmt.Errorf("build completion: %w", err)
+	}
+	comp.ID = id
+	return comp, nil
+}
+
+// MustBuild is like Build but panics on error.
+// Use only in tests or when inputs are known to be valid.
+func (b CompletionBuilder) MustBuild() Completion {
+	comp, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+	return comp
+}
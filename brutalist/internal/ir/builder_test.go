@@ -0,0 +1,156 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvocationBuilder_MatchesManualID(t *testing.T) {
+	args := IRObject{
+		"item_id":  IRString("widget"),
+		"quantity": IRInt(3),
+	}
+	sc := NewSecurityContext("tenant-1", "user-1", "cart:write")
+
+	inv, err := NewInvocation("flow-1", "Cart.addItem").
+		WithArgs(args).
+		WithSeq(7).
+		WithSecurityContext(sc).
+		WithSpecHash("spec-hash-1").
+		Build()
+	require.NoError(t, err)
+
+	wantID, err := InvocationID("flow-1", "Cart.addItem", args, 7)
+	require.NoError(t, err)
+
+	assert.Equal(t, Invocation{
+		ID:              wantID,
+		FlowToken:       "flow-1",
+		ActionURI:       "Cart.addItem",
+		Args:            args,
+		Seq:             7,
+		SecurityContext: sc,
+		SpecHash:        "spec-hash-1",
+		EngineVersion:   EngineVersion,
+		IRVersion:       IRVersion,
+	}, inv)
+}
+
+func TestInvocationBuilder_Defaults(t *testing.T) {
+	inv := NewInvocation("flow-1", "Cart.checkout").MustBuild()
+
+	assert.Equal(t, IRObject{}, inv.Args)
+	assert.Equal(t, MustInvocationID("flow-1", "Cart.checkout", IRObject{}, 0), inv.ID)
+	assert.Equal(t, EngineVersion, inv.EngineVersion)
+	assert.Equal(t, IRVersion, inv.IRVersion)
+
+	assert.Equal(t, IRObject{}, NewInvocation("flow-1", "Cart.checkout").WithArgs(nil).MustBuild().Args)
+}
+
+func TestInvocationBuilder_ArgsCopied(t *testing.T) {
+	args := IRObject{"item_id": IRString("widget")}
+	builder := NewInvocation("flow-1", "Cart.addItem").WithArgs(args).WithSeq(1)
+
+	args["item_id"] = IRString("gadget")
+	inv := builder.MustBuild()
+
+	assert.Equal(t, IRString("widget"), inv.Args["item_id"])
+	assert.Equal(t, MustInvocationID("flow-1", "Cart.addItem", IRObject{"item_id": IRString("widget")}, 1), inv.ID)
+}
+
+func TestInvocationBuilder_Template(t *testing.T) {
+	base := NewInvocation("flow-1", "Cart.addItem").
+		WithSecurityContext(NewSecurityContext("tenant-1", "user-1"))
+
+	first := base.WithSeq(1).MustBuild()
+	second := base.WithSeq(2).MustBuild()
+
+	assert.Equal(t, int64(1), first.Seq)
+	assert.Equal(t, int64(2), second.Seq)
+	assert.NotEqual(t, first.ID, second.ID)
+}
+
+// reserveSig declares Inventory.reserve with a success and an error case.
+func TestInvocationBuilder_EnumArg(t *testing.T) {
+	sig := ActionSig{
+		Name:  "ship",
+		Args:  []NamedArg{{Name: "status", Type: "string"}},
+		Enums: map[string][]string{"status": {"pending", "shipped"}},
+	}
+	builder := NewInvocation("flow-1", "Order.ship").
+		WithArgs(IRObject{"status": IRString("lost")})
+
+	// Without a signature, no validation
+	_, err := builder.Build()
+	require.NoError(t, err)
+
+	_, err = builder.WithActionSig(sig).Build()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `args.status: value "lost" is not one of: pending, shipped`)
+
+	inv, err := builder.WithArgs(IRObject{"status": IRString("shipped")}).WithActionSig(sig).Build()
+	require.NoError(t, err)
+	assert.Equal(t, IRString("shipped"), inv.Args["status"])
+}
+
+func reserveSig() ActionSig {
+	return ActionSig{
+		Name: "reserve",
+		Args: []NamedArg{{Name: "item_id", Type: "string"}},
+		Outputs: []OutputCase{
+			{Case: "Success", Fields: map[string]string{"reservation_id": "string", "quantity": "int"}},
+			{Case: "InsufficientStock", Fields: map[string]string{"available": "int"}},
+		},
+	}
+}
+
+func TestCompletionBuilder_MatchesManualID(t *testing.T) {
+	result := IRObject{
+		"reservation_id": IRString("r-1"),
+		"quantity":       IRInt(2),
+	}
+	sc := NewSecurityContext("tenant-1", "user-1")
+
+	comp, err := NewCompletion("inv-1", "Success").
+		WithResult(result).
+		WithSeq(4).
+		WithSecurityContext(sc).
+		WithActionSig(reserveSig()).
+		Build()
+	require.NoError(t, err)
+
+	assert.Equal(t, Completion{
+		ID:              MustCompletionID("inv-1", "Success", result, 4),
+		InvocationID:    "inv-1",
+		OutputCase:      "Success",
+		Result:          result,
+		Seq:             4,
+		SecurityContext: sc,
+	}, comp)
+}
+
+func TestCompletionBuilder_UndeclaredField(t *testing.T) {
+	builder := NewCompletion("inv-1", "InsufficientStock").
+		WithResult(IRObject{"available": IRInt(0), "reason": IRString("sold out")}).
+		WithSeq(4)
+
+	// Without a signature, no validation
+	_, err := builder.Build()
+	require.NoError(t, err)
+
+	_, err = builder.WithActionSig(reserveSig()).Build()
+	require.Error(t, err)
+	var verr ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, "result.reason", verr.Field)
+	assert.Contains(t, err.Error(), `field not declared by output case "InsufficientStock"`)
+}
+
+func TestCompletionBuilder_Defaults(t *testing.T) {
+	comp := NewCompletion("inv-1", "Success").WithResult(nil).MustBuild()
+
+	assert.Equal(t, IRObject{}, comp.Result)
+	assert.Equal(t, MustCompletionID("inv-1", "Success", IRObject{}, 0), comp.ID)
+}
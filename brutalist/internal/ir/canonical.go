@@ -22,6 +22,24 @@ func MarshalCanonical(v any) ([]byte, error) {
 	return marshalCanonical(v)
 }
 
+// CanonicalJSON returns the RFC 8785 canonical JSON encoding of an IRValue:
+// object keys sorted by UTF-16 code units, strings NFC-normalized without
+// HTML escaping, and integers in plain decimal (never exponent notation).
+// IRNull is rejected, as are floats (CP-5), which IRValue cannot hold.
+//
+// These are exactly the bytes hashed for content-addressed identity. Each ID
+// is the hex SHA-256 of its domain prefix (e.g., DomainInvocation), a 0x00
+// separator, then CanonicalJSON of the record's identity fields:
+//
+//	InvocationID: {"action_uri", "args", "flow_token", "seq"}
+//	CompletionID: {"invocation_id", "output_case", "result", "seq"}
+//	BindingHash:  the bindings object itself
+//
+// so external tools can log, sign, or re-derive IDs from the same bytes.
+func CanonicalJSON(v IRValue) ([]byte, error) {
+	return marshalCanonical(v)
+}
+
 func marshalCanonical(v any) ([]byte, error) {
 	switch val := v.(type) {
 	case nil:
@@ -1,6 +1,9 @@
 package ir
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -444,3 +447,66 @@ func FuzzMarshalCanonicalIdempotent(f *testing.F) {
 		assert.Equal(t, canonical1, canonical2, "canonical marshaling must be idempotent")
 	})
 }
+
+func TestCanonicalJSONSortedKeys(t *testing.T) {
+	data, err := CanonicalJSON(IRObject{
+		"zeta":  IRInt(1),
+		"alpha": IRObject{"b": IRBool(true), "a": IRString("x")},
+		"Mid":   IRArray{IRInt(2), IRInt(1)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `{"Mid":[2,1],"alpha":{"a":"x","b":true},"zeta":1}`, string(data))
+}
+
+func TestCanonicalJSONIntsWithoutExponent(t *testing.T) {
+	data, err := CanonicalJSON(IRArray{
+		IRInt(1000000000000000000),
+		IRInt(math.MaxInt64),
+		IRInt(math.MinInt64),
+		IRInt(0),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "[1000000000000000000,9223372036854775807,-9223372036854775808,0]", string(data))
+}
+
+func TestCanonicalJSONRejectsNull(t *testing.T) {
+	_, err := CanonicalJSON(IRObject{"a": IRNull{}})
+	require.Error(t, err)
+}
+
+// hashCanonical re-derives an ID from CanonicalJSON output, independently of
+// hashWithDomain: SHA-256 over domain, 0x00, then the canonical bytes.
+func hashCanonical(t *testing.T, domain string, v IRValue) string {
+	t.Helper()
+	data, err := CanonicalJSON(v)
+	require.NoError(t, err)
+	sum := sha256.Sum256(append(append([]byte(domain), 0x00), data...))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestCanonicalJSONMatchesHashInput(t *testing.T) {
+	args := IRObject{"item_id": IRString("widget"), "quantity": IRInt(2), "note": IRString("<é>")}
+
+	inv := IRObject{
+		"flow_token": IRString("flow-1"),
+		"action_uri": IRString("Cart.addItem"),
+		"args":       args,
+		"seq":        IRInt(7),
+	}
+	data, err := CanonicalJSON(inv)
+	require.NoError(t, err)
+	internal, err := MarshalCanonical(invocationHashInput("flow-1", "Cart.addItem", args, 7))
+	require.NoError(t, err)
+	assert.Equal(t, internal, data, "CanonicalJSON must produce the exact bytes InvocationID hashes")
+	assert.Equal(t, MustInvocationID("flow-1", "Cart.addItem", args, 7), hashCanonical(t, DomainInvocation, inv))
+
+	comp := IRObject{
+		"invocation_id": IRString("inv-1"),
+		"output_case":   IRString("Success"),
+		"result":        args,
+		"seq":           IRInt(8),
+	}
+	assert.Equal(t, MustCompletionID("inv-1", "Success", args, 8), hashCanonical(t, DomainCompletion, comp))
+
+	assert.Equal(t, MustBindingHash(args), hashCanonical(t, DomainBinding, args))
+}
@@ -6,6 +6,7 @@ type WhenClause struct {
 	EventType  string            `json:"event_type"`            // "completed" or "invoked"
 	OutputCase string            `json:"output_case,omitempty"` // "Success", etc. (empty = match any)
 	Bindings   map[string]string `json:"bindings"`              // var name → path expression
+	Guard      string            `json:"guard,omitempty"`       // Predicate over result./args. fields (empty = always fire)
 }
 
 // WhereClause specifies the query to produce bindings.
@@ -16,7 +17,12 @@ type WhereClause struct {
 }
 
 // ThenClause specifies the action to invoke.
+//
+// AfterSteps defers the generated invocation until the logical clock has
+// advanced that many seq ticks past the triggering completion (0 = invoke
+// immediately). Being seq-based, the delay is deterministic on replay.
 type ThenClause struct {
-	ActionRef string            `json:"action_ref"` // "Inventory.reserve"
-	Args      map[string]string `json:"args"`       // arg name → expression using bound vars
+	ActionRef  string            `json:"action_ref"`            // "Inventory.reserve"
+	Args       map[string]string `json:"args"`                  // arg name → expression using bound vars
+	AfterSteps int               `json:"after_steps,omitempty"` // Seq ticks to defer the invocation (0 = immediate)
 }
@@ -0,0 +1,42 @@
+package ir
+
+// CloneObject returns a deep copy of obj: nested objects and arrays are
+// copied recursively, so mutating the clone never affects obj (and vice
+// versa). Scalars are immutable values and are shared. A nil object clones
+// to nil; an empty object clones to a new empty object.
+func CloneObject(obj IRObject) IRObject {
+	if obj == nil {
+		return nil
+	}
+	out := make(IRObject, len(obj))
+	for k, v := range obj {
+		out[k] = cloneValue(v)
+	}
+	return out
+}
+
+// CloneArray returns a deep copy of arr, with the same guarantees as
+// CloneObject. A nil array clones to nil; an empty array clones to a new
+// empty array.
+func CloneArray(arr IRArray) IRArray {
+	if arr == nil {
+		return nil
+	}
+	out := make(IRArray, len(arr))
+	for i, v := range arr {
+		out[i] = cloneValue(v)
+	}
+	return out
+}
+
+// cloneValue deep-copies containers and returns scalars (and nil) as is.
+func cloneValue(v IRValue) IRValue {
+	switch val := v.(type) {
+	case IRObject:
+		return CloneObject(val)
+	case IRArray:
+		return CloneArray(val)
+	default:
+		return v
+	}
+}
@@ -0,0 +1,90 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func nestedObject() IRObject {
+	return IRObject{
+		"name": IRString("order"),
+		"customer": IRObject{
+			"id":   IRInt(7),
+			"tags": IRArray{IRString("vip"), IRObject{"since": IRInt(2020)}},
+		},
+		"items": IRArray{
+			IRObject{"sku": IRString("widget"), "qty": IRInt(2)},
+			IRArray{IRBool(true), IRNull{}},
+		},
+		"empty_object": IRObject{},
+		"empty_array":  IRArray{},
+	}
+}
+
+func TestCloneObject_DeepCopy(t *testing.T) {
+	original := nestedObject()
+	clone := CloneObject(original)
+	require.True(t, Equal(original, clone))
+
+	// Mutate every nesting level of the clone
+	clone["name"] = IRString("changed")
+	customer := clone["customer"].(IRObject)
+	customer["id"] = IRInt(8)
+	tags := customer["tags"].(IRArray)
+	tags[0] = IRString("regular")
+	tags[1].(IRObject)["since"] = IRInt(2024)
+	items := clone["items"].(IRArray)
+	items[0].(IRObject)["qty"] = IRInt(99)
+	items[1].(IRArray)[0] = IRBool(false)
+	clone["empty_object"].(IRObject)["added"] = IRInt(1)
+
+	assert.True(t, Equal(nestedObject(), original), "original must be unaffected")
+}
+
+func TestCloneObject_OriginalMutationDoesNotAffectClone(t *testing.T) {
+	original := nestedObject()
+	clone := CloneObject(original)
+
+	original["customer"].(IRObject)["tags"].(IRArray)[1].(IRObject)["since"] = IRInt(1999)
+	delete(original, "items")
+
+	assert.True(t, Equal(nestedObject(), clone))
+}
+
+func TestCloneObject_EmptyAndNil(t *testing.T) {
+	assert.Nil(t, CloneObject(nil))
+
+	empty := IRObject{}
+	clone := CloneObject(empty)
+	require.NotNil(t, clone)
+	assert.Empty(t, clone)
+
+	clone["key"] = IRString("value")
+	assert.Empty(t, empty)
+}
+
+func TestCloneArray_DeepCopy(t *testing.T) {
+	original := IRArray{
+		IRObject{"nested": IRArray{IRInt(1), IRObject{"deep": IRString("x")}}},
+		IRArray{},
+		IRString("scalar"),
+	}
+	clone := CloneArray(original)
+	require.True(t, Equal(original, clone))
+
+	clone[0].(IRObject)["nested"].(IRArray)[1].(IRObject)["deep"] = IRString("y")
+	clone[2] = IRString("changed")
+
+	assert.Equal(t, IRString("x"), original[0].(IRObject)["nested"].(IRArray)[1].(IRObject)["deep"])
+	assert.Equal(t, IRString("scalar"), original[2])
+}
+
+func TestCloneArray_EmptyAndNil(t *testing.T) {
+	assert.Nil(t, CloneArray(nil))
+
+	clone := CloneArray(IRArray{})
+	require.NotNil(t, clone)
+	assert.Empty(t, clone)
+}
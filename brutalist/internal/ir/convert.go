@@ -0,0 +1,116 @@
+package ir
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// FromGo converts an arbitrary Go value into an IRValue.
+//
+// Supported conversions:
+//   - string → IRString
+//   - signed and unsigned integers, json.Number (integral) → IRInt
+//   - bool → IRBool
+//   - slices and arrays → IRArray
+//   - maps with string keys → IRObject
+//   - IRValue → itself
+//
+// Floats are rejected to enforce CP-5, even when whole-valued; loaders that
+// decode integers as float64 must convert them first. nil is rejected because
+// canonical JSON does not support null. Errors name the offending path, e.g.
+// `items[1].price`.
+func FromGo(v any) (IRValue, error) {
+	return fromGo(v, "")
+}
+
+func fromGo(v any, path string) (IRValue, error) {
+	switch val := v.(type) {
+	case nil:
+		return nil, fromGoError(path, "null values are forbidden in IR (canonical JSON does not support null)")
+	case IRValue:
+		return val, nil
+	case string:
+		return IRString(val), nil
+	case bool:
+		return IRBool(val), nil
+	case float32, float64:
+		return nil, fromGoError(path, fmt.Sprintf("floats are forbidden in IR (CP-5): %v", val))
+	case json.Number:
+		n, err := val.Int64()
+		if err != nil || strings.ContainsAny(string(val), ".eE") {
+			return nil, fromGoError(path, fmt.Sprintf("floats are forbidden in IR (CP-5): %s", val))
+		}
+		return IRInt(n), nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String:
+		return IRString(rv.String()), nil
+	case reflect.Bool:
+		return IRBool(rv.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return IRInt(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := rv.Uint()
+		if u > math.MaxInt64 {
+			return nil, fromGoError(path, fmt.Sprintf("integer %d overflows int64", u))
+		}
+		return IRInt(int64(u)), nil
+	case reflect.Float32, reflect.Float64:
+		return nil, fromGoError(path, fmt.Sprintf("floats are forbidden in IR (CP-5): %v", rv.Float()))
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return IRArray{}, nil
+		}
+		arr := make(IRArray, rv.Len())
+		for i := range rv.Len() {
+			elem, err := fromGo(rv.Index(i).Interface(), fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			arr[i] = elem
+		}
+		return arr, nil
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, fromGoError(path, fmt.Sprintf("map keys must be strings, got %s", rv.Type().Key()))
+		}
+		obj := make(IRObject, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+			elem, err := fromGo(iter.Value().Interface(), joinFromGoPath(path, key))
+			if err != nil {
+				return nil, err
+			}
+			obj[key] = elem
+		}
+		return obj, nil
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return fromGo(nil, path)
+		}
+		return fromGo(rv.Elem().Interface(), path)
+	default:
+		return nil, fromGoError(path, fmt.Sprintf("unsupported type %T", v))
+	}
+}
+
+func joinFromGoPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func fromGoError(path, msg string) error {
+	if path == "" {
+		return errors.New(msg)
+	}
+	return fmt.Errorf("%s: %s", path, msg)
+}
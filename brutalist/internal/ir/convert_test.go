@@ -0,0 +1,111 @@
+package ir
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromGo_Scalars(t *testing.T) {
+	tests := []struct {
+		name string
+		in   any
+		want IRValue
+	}{
+		{"string", "widget", IRString("widget")},
+		{"named_string", ActionRef("Cart.addItem"), IRString("Cart.addItem")},
+		{"int", 42, IRInt(42)},
+		{"int64", int64(-7), IRInt(-7)},
+		{"int32", int32(3), IRInt(3)},
+		{"uint8", uint8(255), IRInt(255)},
+		{"bool", true, IRBool(true)},
+		{"json_number", json.Number("12"), IRInt(12)},
+		{"ir_value", IRString("as-is"), IRString("as-is")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromGo(tt.in)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFromGo_Nested(t *testing.T) {
+	in := map[string]any{
+		"order_id": "ord-1",
+		"items": []any{
+			map[string]any{"sku": "A", "qty": 2},
+			map[string]any{"sku": "B", "qty": int64(1), "tags": []string{"gift"}},
+		},
+		"paid": false,
+	}
+
+	got, err := FromGo(in)
+	require.NoError(t, err)
+	assert.Equal(t, IRObject{
+		"order_id": IRString("ord-1"),
+		"items": IRArray{
+			IRObject{"sku": IRString("A"), "qty": IRInt(2)},
+			IRObject{"sku": IRString("B"), "qty": IRInt(1), "tags": IRArray{IRString("gift")}},
+		},
+		"paid": IRBool(false),
+	}, got)
+}
+
+func TestFromGo_TypedMapsAndSlices(t *testing.T) {
+	got, err := FromGo(map[string]int{"a": 1})
+	require.NoError(t, err)
+	assert.Equal(t, IRObject{"a": IRInt(1)}, got)
+
+	got, err = FromGo([2]bool{true, false})
+	require.NoError(t, err)
+	assert.Equal(t, IRArray{IRBool(true), IRBool(false)}, got)
+
+	got, err = FromGo([]int(nil))
+	require.NoError(t, err)
+	assert.Equal(t, IRArray{}, got)
+}
+
+func TestFromGo_RejectsFloat(t *testing.T) {
+	in := map[string]any{
+		"items": []any{
+			map[string]any{"price": 9},
+			map[string]any{"price": 9.99},
+		},
+	}
+
+	_, err := FromGo(in)
+	require.Error(t, err)
+	assert.Equal(t, "items[1].price: floats are forbidden in IR (CP-5): 9.99", err.Error())
+
+	_, err = FromGo(float32(1))
+	require.Error(t, err, "whole-valued floats are still floats")
+	assert.Contains(t, err.Error(), "floats are forbidden in IR (CP-5)")
+
+	_, err = FromGo(json.Number("1.5"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "floats are forbidden in IR (CP-5)")
+}
+
+func TestFromGo_RejectsUnsupported(t *testing.T) {
+	_, err := FromGo(map[string]any{"x": nil})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "x: null values are forbidden")
+
+	_, err = FromGo(map[int]string{1: "a"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "map keys must be strings")
+
+	_, err = FromGo(uint64(math.MaxUint64))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "overflows int64")
+
+	_, err = FromGo(struct{}{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported type")
+}
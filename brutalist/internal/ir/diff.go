@@ -0,0 +1,113 @@
+package ir
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// FieldDiff is a single field-level difference between two records.
+// A and B hold the field's value on each side, or nil when the field
+// (e.g., an args key) is absent on that side.
+type FieldDiff struct {
+	Path string  `json:"path"` // e.g. "args.quantity", "security_context.user_id"
+	A    IRValue `json:"a"`
+	B    IRValue `json:"b"`
+}
+
+// String formats the difference as "path: a → b", with absent values shown as <absent>.
+func (d FieldDiff) String() string {
+	return fmt.Sprintf("%s: %s → %s", d.Path, formatDiffValue(d.A), formatDiffValue(d.B))
+}
+
+// Diff returns the field-level differences between two invocations, ordered
+// by path. Args are compared key by key using Equal. ID is omitted: it is
+// derived from the other fields, so a differing ID shows up as differences
+// in what it hashes.
+func Diff(a, b Invocation) []FieldDiff {
+	var d differ
+	d.scalar("flow_token", IRString(a.FlowToken), IRString(b.FlowToken))
+	d.scalar("action_uri", IRString(a.ActionURI), IRString(b.ActionURI))
+	d.object("args", a.Args, b.Args)
+	d.scalar("seq", IRInt(a.Seq), IRInt(b.Seq))
+	d.securityContext(a.SecurityContext, b.SecurityContext)
+	d.scalar("spec_hash", IRString(a.SpecHash), IRString(b.SpecHash))
+	d.scalar("engine_version", IRString(a.EngineVersion), IRString(b.EngineVersion))
+	d.scalar("ir_version", IRString(a.IRVersion), IRString(b.IRVersion))
+	return d.sorted()
+}
+
+// DiffCompletion returns the field-level differences between two
+// completions, ordered by path. Result keys are compared individually, and
+// ID is omitted as for Diff.
+func DiffCompletion(a, b Completion) []FieldDiff {
+	var d differ
+	d.scalar("invocation_id", IRString(a.InvocationID), IRString(b.InvocationID))
+	d.scalar("output_case", IRString(a.OutputCase), IRString(b.OutputCase))
+	d.object("result", a.Result, b.Result)
+	d.scalar("seq", IRInt(a.Seq), IRInt(b.Seq))
+	d.securityContext(a.SecurityContext, b.SecurityContext)
+	return d.sorted()
+}
+
+// differ accumulates FieldDiffs.
+type differ struct {
+	diffs []FieldDiff
+}
+
+func (d *differ) scalar(path string, a, b IRValue) {
+	if !Equal(a, b) {
+		d.diffs = append(d.diffs, FieldDiff{Path: path, A: a, B: b})
+	}
+}
+
+// object compares two objects key by key; a key missing on one side is
+// reported with a nil value for that side.
+func (d *differ) object(path string, a, b IRObject) {
+	keys := a.SortedKeys()
+	for _, k := range b.SortedKeys() {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	for _, k := range keys {
+		d.scalar(path+"."+k, a[k], b[k])
+	}
+}
+
+func (d *differ) securityContext(a, b SecurityContext) {
+	d.scalar("security_context.tenant_id", IRString(a.TenantID), IRString(b.TenantID))
+	d.scalar("security_context.user_id", IRString(a.UserID), IRString(b.UserID))
+	d.scalar("security_context.permissions", stringsToIRArray(a.Permissions), stringsToIRArray(b.Permissions))
+}
+
+// sorted returns the diffs ordered by path (RFC 8785 key order, matching
+// canonical JSON) so output is deterministic.
+func (d *differ) sorted() []FieldDiff {
+	if d.diffs == nil {
+		return []FieldDiff{}
+	}
+	slices.SortFunc(d.diffs, func(x, y FieldDiff) int {
+		return compareKeysRFC8785(x.Path, y.Path)
+	})
+	return d.diffs
+}
+
+func stringsToIRArray(ss []string) IRArray {
+	arr := make(IRArray, len(ss))
+	for i, s := range ss {
+		arr[i] = IRString(s)
+	}
+	return arr
+}
+
+func formatDiffValue(v IRValue) string {
+	if v == nil {
+		return "<absent>"
+	}
+	data, err := MarshalIRValue(v)
+	if err != nil {
+		return fmt.Sprintf("<%v>", err)
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,102 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func diffTestInvocation() Invocation {
+	return Invocation{
+		FlowToken: "flow-1",
+		ActionURI: "Cart.addItem",
+		Args: IRObject{
+			"item_id":  IRString("widget"),
+			"quantity": IRInt(2),
+			"options":  IRObject{"gift": IRBool(true)},
+		},
+		Seq:             3,
+		SecurityContext: SecurityContext{TenantID: "t1", UserID: "u1", Permissions: []string{"cart:write"}},
+		SpecHash:        "spec",
+		EngineVersion:   "0.1.0",
+		IRVersion:       "1",
+	}
+}
+
+func TestDiff_Identical(t *testing.T) {
+	a := diffTestInvocation()
+	b := diffTestInvocation()
+
+	diffs := Diff(a, b)
+	assert.NotNil(t, diffs)
+	assert.Empty(t, diffs)
+}
+
+func TestDiff_ChangedArg(t *testing.T) {
+	a := diffTestInvocation()
+	b := diffTestInvocation()
+	b.Args["quantity"] = IRInt(3)
+
+	diffs := Diff(a, b)
+	require.Len(t, diffs, 1)
+	assert.Equal(t, FieldDiff{Path: "args.quantity", A: IRInt(2), B: IRInt(3)}, diffs[0])
+	assert.Equal(t, "args.quantity: 2 → 3", diffs[0].String())
+}
+
+func TestDiff_NestedArgUsesDeepEquality(t *testing.T) {
+	a := diffTestInvocation()
+	b := diffTestInvocation()
+	b.Args["options"] = IRObject{"gift": IRBool(false)}
+
+	diffs := Diff(a, b)
+	require.Len(t, diffs, 1)
+	assert.Equal(t, "args.options", diffs[0].Path)
+}
+
+func TestDiff_AddedAndRemovedArgKeys(t *testing.T) {
+	a := diffTestInvocation()
+	b := diffTestInvocation()
+	delete(b.Args, "item_id")
+	b.Args["coupon"] = IRString("SAVE10")
+
+	diffs := Diff(a, b)
+	require.Len(t, diffs, 2)
+	assert.Equal(t, FieldDiff{Path: "args.coupon", A: nil, B: IRString("SAVE10")}, diffs[0])
+	assert.Equal(t, FieldDiff{Path: "args.item_id", A: IRString("widget"), B: nil}, diffs[1])
+	assert.Equal(t, `args.item_id: "widget" → <absent>`, diffs[1].String())
+}
+
+func TestDiff_SortedAcrossFields(t *testing.T) {
+	a := diffTestInvocation()
+	b := diffTestInvocation()
+	b.Seq = 4
+	b.ActionURI = "Cart.removeItem"
+	b.SecurityContext.UserID = "u2"
+	b.Args["quantity"] = IRInt(5)
+
+	var paths []string
+	for _, d := range Diff(a, b) {
+		paths = append(paths, d.Path)
+	}
+	assert.Equal(t, []string{"action_uri", "args.quantity", "security_context.user_id", "seq"}, paths)
+}
+
+func TestDiffCompletion(t *testing.T) {
+	a := Completion{
+		InvocationID: "inv-1",
+		OutputCase:   "Success",
+		Result:       IRObject{"total": IRInt(10)},
+		Seq:          4,
+	}
+	b := a
+	b.OutputCase = "Failed"
+	b.Result = IRObject{"total": IRInt(10), "reason": IRString("declined")}
+
+	diffs := DiffCompletion(a, b)
+	require.Len(t, diffs, 2)
+	assert.Equal(t, "output_case", diffs[0].Path)
+	assert.Equal(t, FieldDiff{Path: "result.reason", A: nil, B: IRString("declined")}, diffs[1])
+
+	assert.Empty(t, DiffCompletion(a, a))
+}
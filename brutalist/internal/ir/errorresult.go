@@ -0,0 +1,32 @@
+package ir
+
+// Canonical error result field names. Error-handling syncs bind them from
+// the completion result like any other field.
+const (
+	ErrorCodeField    = "error_code"
+	ErrorMessageField = "error_message"
+)
+
+// ErrorResult returns the canonical completion result for an error output
+// case: {"error_code": code, "error_message": message}.
+//
+// Callers may add case-specific fields to the returned object (e.g.
+// "available" for InsufficientStock); IsErrorResult still recognizes it.
+func ErrorResult(code, message string) IRObject {
+	return IRObject{
+		ErrorCodeField:    IRString(code),
+		ErrorMessageField: IRString(message),
+	}
+}
+
+// IsErrorResult reads a result produced by ErrorResult. ok is true only if
+// both error_code and error_message are present and are strings; other
+// fields are ignored.
+func IsErrorResult(result IRObject) (code, message string, ok bool) {
+	c, codeOK := result[ErrorCodeField].(IRString)
+	m, messageOK := result[ErrorMessageField].(IRString)
+	if !codeOK || !messageOK {
+		return "", "", false
+	}
+	return string(c), string(m), true
+}
@@ -0,0 +1,64 @@
+package ir
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorResultRoundTrip(t *testing.T) {
+	result := ErrorResult("INSUFFICIENT_STOCK", "only 2 left")
+	assert.Equal(t, IRObject{
+		"error_code":    IRString("INSUFFICIENT_STOCK"),
+		"error_message": IRString("only 2 left"),
+	}, result)
+
+	code, message, ok := IsErrorResult(result)
+	require.True(t, ok)
+	assert.Equal(t, "INSUFFICIENT_STOCK", code)
+	assert.Equal(t, "only 2 left", message)
+
+	// The shape survives canonical serialization, as in a stored completion
+	data, err := MarshalCanonical(result)
+	require.NoError(t, err)
+	assert.Equal(t, `{"error_code":"INSUFFICIENT_STOCK","error_message":"only 2 left"}`, string(data))
+
+	var decoded IRObject
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	code, message, ok = IsErrorResult(decoded)
+	require.True(t, ok)
+	assert.Equal(t, "INSUFFICIENT_STOCK", code)
+	assert.Equal(t, "only 2 left", message)
+}
+
+func TestIsErrorResultExtraFields(t *testing.T) {
+	result := ErrorResult("INSUFFICIENT_STOCK", "only 2 left")
+	result["available"] = IRInt(2)
+
+	code, _, ok := IsErrorResult(result)
+	assert.True(t, ok)
+	assert.Equal(t, "INSUFFICIENT_STOCK", code)
+}
+
+func TestIsErrorResultNotError(t *testing.T) {
+	tests := []struct {
+		name   string
+		result IRObject
+	}{
+		{"nil", nil},
+		{"success result", IRObject{"order_id": IRString("order-1")}},
+		{"code only", IRObject{"error_code": IRString("E1")}},
+		{"message only", IRObject{"error_message": IRString("boom")}},
+		{"non-string code", IRObject{"error_code": IRInt(1), "error_message": IRString("boom")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, message, ok := IsErrorResult(tt.result)
+			assert.False(t, ok)
+			assert.Empty(t, code)
+			assert.Empty(t, message)
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"slices"
 )
 
 // Domain prefixes for content-addressed identity (CP-4).
@@ -12,6 +13,7 @@ const (
 	DomainInvocation = "nysm/invocation/v1"
 	DomainCompletion = "nysm/completion/v1"
 	DomainBinding    = "nysm/binding/v1"
+	DomainBindingV2  = "nysm/binding/v2"
 )
 
 // hashWithDomain computes SHA-256 hash with domain separation.
@@ -41,16 +43,7 @@ func hashWithDomain(domain string, data []byte) string {
 // For cryptographic "who did it" binding, use a separate AttributionHash (future story).
 // SecurityContext is still stored on the Invocation record for audit purposes.
 func InvocationID(flowToken, actionURI string, args IRObject, seq int64) (string, error) {
-	// Build object for hashing using IRObject for type safety (CP-5)
-	// NOTE: SecurityContext excluded - see design decision above
-	obj := IRObject{
-		"flow_token": IRString(flowToken),
-		"action_uri": IRString(actionURI),
-		"args":       args,
-		"seq":        IRInt(seq),
-	}
-
-	canonical, err := MarshalCanonical(obj)
+	canonical, err := CanonicalJSON(invocationHashInput(flowToken, actionURI, args, seq))
 	if err != nil {
 		return "", fmt.Errorf("InvocationID: failed to marshal: %w", err)
 	}
@@ -62,14 +55,7 @@ func InvocationID(flowToken, actionURI string, args IRObject, seq int64) (string
 // Links to the invocation it completes via invocationID.
 // Returns error if result cannot be canonically marshaled.
 func CompletionID(invocationID, outputCase string, result IRObject, seq int64) (string, error) {
-	obj := IRObject{
-		"invocation_id": IRString(invocationID),
-		"output_case":   IRString(outputCase),
-		"result":        result,
-		"seq":           IRInt(seq),
-	}
-
-	canonical, err := MarshalCanonical(obj)
+	canonical, err := CanonicalJSON(completionHashInput(invocationID, outputCase, result, seq))
 	if err != nil {
 		return "", fmt.Errorf("CompletionID: failed to marshal: %w", err)
 	}
@@ -81,7 +67,7 @@ func CompletionID(invocationID, outputCase string, result IRObject, seq int64) (
 // Used in sync_firings table: UNIQUE(completion_id, sync_id, binding_hash)
 // Returns error if bindings cannot be canonically marshaled.
 func BindingHash(bindings IRObject) (string, error) {
-	canonical, err := MarshalCanonical(bindings)
+	canonical, err := CanonicalJSON(bindings)
 	if err != nil {
 		return "", fmt.Errorf("BindingHash: failed to marshal: %w", err)
 	}
@@ -89,6 +75,64 @@ func BindingHash(bindings IRObject) (string, error) {
 	return hashWithDomain(DomainBinding, canonical), nil
 }
 
+// BindingHashV2 computes the idempotency hash (CP-1) over only the declared
+// bound variables in keys, ignoring any other entries in bindings. Adding
+// engine-internal metadata to a binding object therefore leaves the hash
+// unchanged, while changing the value of a bound variable changes it.
+//
+// keys are the sync rule's bound variable names; order and duplicates do not
+// matter. Every key must be present in bindings. The hash is domain-separated
+// from BindingHash (DomainBindingV2), so the two never collide.
+//
+// MIGRATION: v1 and v2 hashes of the same binding differ. sync_firings rows
+// written with BindingHash will not match BindingHashV2 hashes, so switching
+// a deployment to v2 on an existing store makes already-fired bindings look
+// new and fire again on replay. Switch only on a fresh store, or after
+// re-hashing the binding_hash column of existing firings.
+func BindingHashV2(bindings IRObject, keys []string) (string, error) {
+	sorted := slices.Clone(keys)
+	slices.Sort(sorted)
+	sorted = slices.Compact(sorted)
+
+	subset := make(IRObject, len(sorted))
+	for _, key := range sorted {
+		value, ok := bindings[key]
+		if !ok {
+			return "", fmt.Errorf("BindingHashV2: bound variable %q missing from bindings", key)
+		}
+		subset[key] = value
+	}
+
+	canonical, err := CanonicalJSON(subset)
+	if err != nil {
+		return "", fmt.Errorf("BindingHashV2: failed to marshal: %w", err)
+	}
+
+	return hashWithDomain(DomainBindingV2, canonical), nil
+}
+
+// invocationHashInput builds the object hashed by InvocationID, using
+// IRObject for type safety (CP-5).
+// NOTE: SecurityContext excluded - see design decision on InvocationID.
+func invocationHashInput(flowToken, actionURI string, args IRObject, seq int64) IRObject {
+	return IRObject{
+		"flow_token": IRString(flowToken),
+		"action_uri": IRString(actionURI),
+		"args":       args,
+		"seq":        IRInt(seq),
+	}
+}
+
+// completionHashInput builds the object hashed by CompletionID.
+func completionHashInput(invocationID, outputCase string, result IRObject, seq int64) IRObject {
+	return IRObject{
+		"invocation_id": IRString(invocationID),
+		"output_case":   IRString(outputCase),
+		"result":        result,
+		"seq":           IRInt(seq),
+	}
+}
+
 // MustInvocationID is like InvocationID but panics on error.
 // Use only in tests or when inputs are known to be valid.
 func MustInvocationID(flowToken, actionURI string, args IRObject, seq int64) string {
@@ -118,3 +162,13 @@ func MustBindingHash(bindings IRObject) string {
 	}
 	return hash
 }
+
+// MustBindingHashV2 is like BindingHashV2 but panics on error.
+// Use only in tests or when inputs are known to be valid.
+func MustBindingHashV2(bindings IRObject, keys []string) string {
+	hash, err := BindingHashV2(bindings, keys)
+	if err != nil {
+		panic(err)
+	}
+	return hash
+}
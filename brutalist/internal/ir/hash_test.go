@@ -115,6 +115,57 @@ func TestBindingHashChangesWithContent(t *testing.T) {
 	assert.NotEqual(t, hash1, hash2, "Different bindings must produce different hash")
 }
 
+func TestBindingHashV2IgnoresMetadata(t *testing.T) {
+	keys := []string{"item_id", "cart_id"}
+	bindings := IRObject{
+		"cart_id": IRString("cart-123"),
+		"item_id": IRString("SKU-001"),
+	}
+	withMetadata := IRObject{
+		"cart_id":       IRString("cart-123"),
+		"item_id":       IRString("SKU-001"),
+		"_engine_seq":   IRInt(42),
+		"_matched_flow": IRString("flow-1"),
+	}
+
+	hash := MustBindingHashV2(bindings, keys)
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, MustBindingHashV2(withMetadata, keys), "non-binding metadata must not change the hash")
+	assert.Equal(t, hash, MustBindingHashV2(bindings, []string{"cart_id", "item_id", "cart_id"}), "key order and duplicates must not matter")
+
+	// v1 hashes the whole object, so the same metadata changes it
+	assert.NotEqual(t, MustBindingHash(bindings), MustBindingHash(withMetadata))
+}
+
+func TestBindingHashV2ChangesWithBoundValue(t *testing.T) {
+	keys := []string{"cart_id", "item_id"}
+	bindings1 := IRObject{
+		"cart_id": IRString("cart-123"),
+		"item_id": IRString("SKU-001"),
+		"_meta":   IRInt(1),
+	}
+	bindings2 := IRObject{
+		"cart_id": IRString("cart-123"),
+		"item_id": IRString("SKU-002"), // Different bound value
+		"_meta":   IRInt(1),
+	}
+
+	assert.NotEqual(t, MustBindingHashV2(bindings1, keys), MustBindingHashV2(bindings2, keys))
+}
+
+func TestBindingHashV2DomainSeparated(t *testing.T) {
+	bindings := IRObject{"cart_id": IRString("cart-123")}
+
+	assert.NotEqual(t, MustBindingHash(bindings), MustBindingHashV2(bindings, []string{"cart_id"}),
+		"v1 and v2 must not collide on the same binding")
+}
+
+func TestBindingHashV2MissingKey(t *testing.T) {
+	_, err := BindingHashV2(IRObject{"cart_id": IRString("cart-123")}, []string{"cart_id", "item_id"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `bound variable "item_id" missing`)
+}
+
 func TestDomainSeparationPreventsCrossTypeCollision(t *testing.T) {
 	// Same data hashed with different domains must produce different hashes
 	data := []byte(`{"id":"test","data":42}`)
@@ -174,6 +225,7 @@ func TestDomainConstants(t *testing.T) {
 	assert.Equal(t, "nysm/invocation/v1", DomainInvocation)
 	assert.Equal(t, "nysm/completion/v1", DomainCompletion)
 	assert.Equal(t, "nysm/binding/v1", DomainBinding)
+	assert.Equal(t, "nysm/binding/v2", DomainBindingV2)
 }
 
 func TestNestedArgsHash(t *testing.T) {
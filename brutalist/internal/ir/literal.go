@@ -0,0 +1,62 @@
+package ir
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Typed literal prefixes for then-clause arg expressions.
+const (
+	literalInt   = "int:"
+	literalBool  = "bool:"
+	literalStr   = "str:"
+	literalFloat = "float:"
+)
+
+// IsTypedLiteral reports whether a then-arg expression uses a typed literal
+// prefix ("int:", "bool:", "str:", or the forbidden "float:"). A typed
+// literal never references bound variables.
+func IsTypedLiteral(expr string) bool {
+	for _, prefix := range []string{literalInt, literalBool, literalStr, literalFloat} {
+		if strings.HasPrefix(expr, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseArgLiteral converts a literal then-arg expression to its IR value:
+//
+//	"int:5"      → IRInt(5)
+//	"bool:true"  → IRBool(true)
+//	"str:foo"    → IRString("foo")
+//	"foo"        → IRString("foo") (untyped, for compatibility)
+//
+// "float:" literals are rejected per CP-5, as are int and bool literals that
+// do not parse. The expression must not be a binding reference; callers
+// resolve those first.
+func ParseArgLiteral(expr string) (IRValue, error) {
+	switch {
+	case strings.HasPrefix(expr, literalInt):
+		n, err := strconv.ParseInt(strings.TrimPrefix(expr, literalInt), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid int literal %q", expr)
+		}
+		return IRInt(n), nil
+	case strings.HasPrefix(expr, literalBool):
+		switch strings.TrimPrefix(expr, literalBool) {
+		case "true":
+			return IRBool(true), nil
+		case "false":
+			return IRBool(false), nil
+		}
+		return nil, fmt.Errorf("invalid bool literal %q, must be bool:true or bool:false", expr)
+	case strings.HasPrefix(expr, literalStr):
+		return IRString(strings.TrimPrefix(expr, literalStr)), nil
+	case strings.HasPrefix(expr, literalFloat):
+		return nil, fmt.Errorf("float literal %q is forbidden (CP-5); use int", expr)
+	default:
+		return IRString(expr), nil
+	}
+}
@@ -0,0 +1,65 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseArgLiteral(t *testing.T) {
+	tests := []struct {
+		expr string
+		want IRValue
+	}{
+		{"int:5", IRInt(5)},
+		{"int:-42", IRInt(-42)},
+		{"int:9223372036854775807", IRInt(9223372036854775807)},
+		{"bool:true", IRBool(true)},
+		{"bool:false", IRBool(false)},
+		{"str:foo", IRString("foo")},
+		{"str:", IRString("")},
+		{"str:int:5", IRString("int:5")},
+		{"foo", IRString("foo")},
+		{"5", IRString("5")},
+		{"", IRString("")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := ParseArgLiteral(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseArgLiteral_Invalid(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr string
+	}{
+		{"float:1.5", `float literal "float:1.5" is forbidden (CP-5); use int`},
+		{"int:1.5", `invalid int literal "int:1.5"`},
+		{"int:", `invalid int literal "int:"`},
+		{"int:9223372036854775808", `invalid int literal "int:9223372036854775808"`},
+		{"bool:TRUE", `invalid bool literal "bool:TRUE", must be bool:true or bool:false`},
+		{"bool:1", `invalid bool literal "bool:1", must be bool:true or bool:false`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := ParseArgLiteral(tt.expr)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestIsTypedLiteral(t *testing.T) {
+	for _, expr := range []string{"int:5", "bool:true", "str:x", "float:1.5"} {
+		assert.True(t, IsTypedLiteral(expr), expr)
+	}
+	for _, expr := range []string{"5", "bound.x", "${bound.x}", "integer:5", ""} {
+		assert.False(t, IsTypedLiteral(expr), expr)
+	}
+}
@@ -1,9 +1,47 @@
 package ir
 
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
 // ActionRef is a typed reference to a concept action.
 // Format: "Concept.action" (will evolve to nysm://... URI in future).
 type ActionRef string
 
+// actionRefPattern matches "Concept.action" format.
+// Concept starts with uppercase letter, action starts with lowercase letter.
+var actionRefPattern = regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*\.[a-z][a-zA-Z0-9]*$`)
+
+// ParseActionRef splits a "Concept.action" reference into its parts.
+// The concept must start with an uppercase letter, the action with a
+// lowercase letter, both alphanumeric and separated by a single dot.
+func ParseActionRef(s string) (concept, action string, err error) {
+	if !actionRefPattern.MatchString(s) {
+		return "", "", fmt.Errorf("invalid action reference %q, expected format \"Concept.action\"", s)
+	}
+	concept, action, _ = strings.Cut(s, ".")
+	return concept, action, nil
+}
+
+// IsValid reports whether the reference has "Concept.action" format.
+func (r ActionRef) IsValid() bool {
+	return actionRefPattern.MatchString(string(r))
+}
+
+// Concept returns the concept name, or "" if the reference is invalid.
+func (r ActionRef) Concept() string {
+	concept, _, _ := ParseActionRef(string(r))
+	return concept
+}
+
+// Action returns the action name, or "" if the reference is invalid.
+func (r ActionRef) Action() string {
+	_, action, _ := ParseActionRef(string(r))
+	return action
+}
+
 // ConceptRef is a typed reference to a concept.
 type ConceptRef struct {
 	Name    string `json:"name"`
@@ -0,0 +1,63 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Cases mirror TestValidateSyncRuleActionRefFormats in the compiler.
+var (
+	validActionRefs = []struct {
+		ref             string
+		concept, action string
+	}{
+		{"Cart.addItem", "Cart", "addItem"},
+		{"Inventory.reserve", "Inventory", "reserve"},
+		{"Order123.process", "Order123", "process"},
+		{"A.b", "A", "b"},
+	}
+
+	invalidActionRefs = []string{
+		"cart.addItem",    // lowercase concept
+		"Cart.AddItem",    // uppercase action
+		"CartaddItem",     // missing dot
+		"Cart.",           // missing action
+		".addItem",        // missing concept
+		"Cart..addItem",   // double dot
+		"123Cart.addItem", // concept starts with number
+		"Cart.123action",  // action starts with number
+		"",                // empty
+	}
+)
+
+func TestParseActionRef(t *testing.T) {
+	for _, tt := range validActionRefs {
+		concept, action, err := ParseActionRef(tt.ref)
+		require.NoError(t, err, tt.ref)
+		assert.Equal(t, tt.concept, concept, tt.ref)
+		assert.Equal(t, tt.action, action, tt.ref)
+	}
+
+	for _, ref := range invalidActionRefs {
+		_, _, err := ParseActionRef(ref)
+		assert.Error(t, err, "should be invalid: %q", ref)
+	}
+}
+
+func TestActionRefAccessors(t *testing.T) {
+	for _, tt := range validActionRefs {
+		ref := ActionRef(tt.ref)
+		assert.True(t, ref.IsValid(), tt.ref)
+		assert.Equal(t, tt.concept, ref.Concept(), tt.ref)
+		assert.Equal(t, tt.action, ref.Action(), tt.ref)
+	}
+
+	for _, s := range invalidActionRefs {
+		ref := ActionRef(s)
+		assert.False(t, ref.IsValid(), "should be invalid: %q", s)
+		assert.Empty(t, ref.Concept(), s)
+		assert.Empty(t, ref.Action(), s)
+	}
+}
@@ -0,0 +1,160 @@
+package ir
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// jsonSchemaDialect is the JSON Schema draft produced by JSONSchema.
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// schemaRecords are the event-log record types described by JSONSchema, in
+// the order they are listed at the top level.
+var schemaRecords = []reflect.Type{
+	reflect.TypeFor[Invocation](),
+	reflect.TypeFor[Completion](),
+	reflect.TypeFor[SyncFiring](),
+	reflect.TypeFor[ProvenanceEdge](),
+}
+
+// JSONSchema returns a JSON Schema (draft 2020-12) describing the JSON
+// encoding of Invocation, Completion, SyncFiring, and ProvenanceEdge.
+//
+// The schema is generated by reflecting over the struct definitions and their
+// json tags, so it cannot drift from the types. Each record is available
+// under "$defs" by its Go type name; the root schema accepts any of them.
+// Numbers are always "integer" (CP-5), and security_context is a required,
+// non-nullable object (CP-6).
+//
+// Panics if a described type contains a float, which CP-5 forbids.
+func JSONSchema() []byte {
+	g := &schemaGenerator{defs: map[string]any{
+		"IRValue": map[string]any{
+			"anyOf": []any{
+				map[string]any{"type": "string"},
+				map[string]any{"type": "integer"},
+				map[string]any{"type": "boolean"},
+				map[string]any{"type": "null"},
+				schemaRef("IRArray"),
+				schemaRef("IRObject"),
+			},
+		},
+		"IRArray": map[string]any{
+			"type":  "array",
+			"items": schemaRef("IRValue"),
+		},
+		"IRObject": map[string]any{
+			"type":                 "object",
+			"additionalProperties": schemaRef("IRValue"),
+		},
+	}}
+
+	records := make([]any, 0, len(schemaRecords))
+	for _, t := range schemaRecords {
+		records = append(records, g.schemaFor(t))
+	}
+
+	schema := map[string]any{
+		"$schema": jsonSchemaDialect,
+		"title":   "NYSM event log records",
+		"anyOf":   records,
+		"$defs":   g.defs,
+	}
+
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		panic(fmt.Sprintf("ir: marshal JSON schema: %v", err))
+	}
+	return data
+}
+
+// schemaGenerator builds schemas for Go types, collecting named struct
+// schemas into defs so each is described once and referenced by name.
+type schemaGenerator struct {
+	defs map[string]any
+}
+
+var (
+	irValueType  = reflect.TypeFor[IRValue]()
+	irArrayType  = reflect.TypeFor[IRArray]()
+	irObjectType = reflect.TypeFor[IRObject]()
+)
+
+// schemaFor returns the schema for t, registering struct types in defs.
+func (g *schemaGenerator) schemaFor(t reflect.Type) any {
+	switch t {
+	case irValueType:
+		return schemaRef("IRValue")
+	case irArrayType:
+		return schemaRef("IRArray")
+	case irObjectType:
+		return schemaRef("IRObject")
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		panic(fmt.Sprintf("ir: %s is a float, which is forbidden in IR (CP-5)", t))
+	case reflect.Slice:
+		// encoding/json writes nil slices as null
+		return map[string]any{
+			"type":  []any{"array", "null"},
+			"items": g.schemaFor(t.Elem()),
+		}
+	case reflect.Struct:
+		if _, ok := g.defs[t.Name()]; !ok {
+			g.defs[t.Name()] = nil // Reserve the name before recursing
+			g.defs[t.Name()] = g.structSchema(t)
+		}
+		return schemaRef(t.Name())
+	default:
+		panic(fmt.Sprintf("ir: no JSON schema for %s (kind %s)", t, t.Kind()))
+	}
+}
+
+// structSchema describes a struct from its exported fields' json tags.
+// Fields without omitempty are required.
+func (g *schemaGenerator) structSchema(t reflect.Type) map[string]any {
+	properties := make(map[string]any)
+	required := []any{}
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		properties[name] = g.schemaFor(field.Type)
+		if !strings.Contains(opts, "omitempty") {
+			required = append(required, name)
+		}
+	}
+
+	return map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+}
+
+// schemaRef returns a reference to a schema in "$defs".
+func schemaRef(name string) map[string]any {
+	return map[string]any{"$ref": "#/$defs/" + name}
+}
@@ -0,0 +1,210 @@
+package ir
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// validateSchema checks a decoded JSON instance against the subset of JSON
+// Schema that JSONSchema emits: $ref, anyOf, type, properties, required,
+// additionalProperties, and items.
+func validateSchema(root, schema map[string]any, instance any, path string) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/$defs/")
+		def, ok := root["$defs"].(map[string]any)[name].(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: unresolved $ref %q", path, ref)
+		}
+		return validateSchema(root, def, instance, path)
+	}
+
+	if anyOf, ok := schema["anyOf"].([]any); ok {
+		for _, sub := range anyOf {
+			if validateSchema(root, sub.(map[string]any), instance, path) == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: matches no anyOf branch", path)
+	}
+
+	if typ, ok := schema["type"]; ok {
+		var types []any
+		if list, ok := typ.([]any); ok {
+			types = list
+		} else {
+			types = []any{typ}
+		}
+		if !slices.ContainsFunc(types, func(t any) bool { return instanceHasType(instance, t.(string)) }) {
+			return fmt.Errorf("%s: %v is not of type %v", path, instance, typ)
+		}
+	}
+
+	switch val := instance.(type) {
+	case map[string]any:
+		props, _ := schema["properties"].(map[string]any)
+		required, _ := schema["required"].([]any)
+		for _, name := range required {
+			if _, ok := val[name.(string)]; !ok {
+				return fmt.Errorf("%s: missing required property %q", path, name)
+			}
+		}
+		for key, elem := range val {
+			sub, ok := props[key].(map[string]any)
+			if !ok {
+				switch extra := schema["additionalProperties"].(type) {
+				case bool:
+					if !extra {
+						return fmt.Errorf("%s: unexpected property %q", path, key)
+					}
+					continue
+				case map[string]any:
+					sub = extra
+				default:
+					continue
+				}
+			}
+			if err := validateSchema(root, sub, elem, path+"."+key); err != nil {
+				return err
+			}
+		}
+	case []any:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, elem := range val {
+				if err := validateSchema(root, items, elem, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func instanceHasType(instance any, typ string) bool {
+	switch val := instance.(type) {
+	case nil:
+		return typ == "null"
+	case bool:
+		return typ == "boolean"
+	case string:
+		return typ == "string"
+	case json.Number:
+		if typ == "number" {
+			return true
+		}
+		_, err := val.Int64()
+		return typ == "integer" && err == nil
+	case []any:
+		return typ == "array"
+	case map[string]any:
+		return typ == "object"
+	}
+	return false
+}
+
+func decodeWithNumbers(t *testing.T, data []byte) any {
+	t.Helper()
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	require.NoError(t, dec.Decode(&v))
+	return v
+}
+
+func loadSchema(t *testing.T) map[string]any {
+	t.Helper()
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(JSONSchema(), &schema))
+	return schema
+}
+
+func recordSchema(name string) map[string]any {
+	return map[string]any{"$ref": "#/$defs/" + name}
+}
+
+func sampleInvocationJSON(t *testing.T) []byte {
+	t.Helper()
+	inv := Invocation{
+		ID:        "inv-1",
+		FlowToken: "flow-1",
+		ActionURI: "Cart.addItem",
+		Args: IRObject{
+			"item_id":  IRString("widget"),
+			"quantity": IRInt(3),
+			"tags":     IRArray{IRString("new"), IRBool(true)},
+			"meta":     IRObject{"note": IRNull{}},
+		},
+		Seq:             7,
+		SecurityContext: SecurityContext{TenantID: "t1", UserID: "u1"},
+		SpecHash:        "spec",
+		EngineVersion:   "0.1.0",
+		IRVersion:       "1",
+	}
+	data, err := json.Marshal(inv)
+	require.NoError(t, err)
+	return data
+}
+
+func TestJSONSchema_Metadata(t *testing.T) {
+	schema := loadSchema(t)
+	assert.Equal(t, "https://json-schema.org/draft/2020-12/schema", schema["$schema"])
+
+	defs := schema["$defs"].(map[string]any)
+	for _, name := range []string{"Invocation", "Completion", "SyncFiring", "ProvenanceEdge", "SecurityContext"} {
+		assert.Contains(t, defs, name)
+	}
+
+	assert.NotContains(t, string(JSONSchema()), `"number"`, "only integers are allowed (CP-5)")
+
+	inv := defs["Invocation"].(map[string]any)
+	assert.Contains(t, inv["required"], "security_context")
+	assert.Equal(t, recordSchema("SecurityContext"), inv["properties"].(map[string]any)["security_context"])
+}
+
+func TestJSONSchema_Deterministic(t *testing.T) {
+	assert.Equal(t, JSONSchema(), JSONSchema())
+}
+
+func TestJSONSchema_ValidatesInvocation(t *testing.T) {
+	schema := loadSchema(t)
+	instance := decodeWithNumbers(t, sampleInvocationJSON(t))
+
+	require.NoError(t, validateSchema(schema, recordSchema("Invocation"), instance, "$"))
+	require.NoError(t, validateSchema(schema, schema, instance, "$"))
+}
+
+func TestJSONSchema_RejectsFloat(t *testing.T) {
+	schema := loadSchema(t)
+	data := bytes.Replace(sampleInvocationJSON(t), []byte(`"quantity":3`), []byte(`"quantity":3.5`), 1)
+	instance := decodeWithNumbers(t, data)
+
+	err := validateSchema(schema, recordSchema("Invocation"), instance, "$")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "$.args.quantity")
+}
+
+func TestJSONSchema_RejectsMissingSecurityContext(t *testing.T) {
+	schema := loadSchema(t)
+	instance := decodeWithNumbers(t, sampleInvocationJSON(t)).(map[string]any)
+	delete(instance, "security_context")
+
+	err := validateSchema(schema, recordSchema("Invocation"), instance, "$")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "security_context")
+}
+
+func TestJSONSchema_PanicsOnFloatField(t *testing.T) {
+	type withFloat struct {
+		Price float64 `json:"price"`
+	}
+
+	g := &schemaGenerator{defs: map[string]any{}}
+	assert.Panics(t, func() { g.schemaFor(reflect.TypeFor[withFloat]()) })
+}
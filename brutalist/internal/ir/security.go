@@ -0,0 +1,35 @@
+package ir
+
+import (
+	"errors"
+	"slices"
+)
+
+// NewSecurityContext creates a SecurityContext with a normalized permission
+// list: sorted, de-duplicated, and never nil, so that equivalent contexts
+// serialize identically (stable hashing and golden traces).
+func NewSecurityContext(tenantID, userID string, permissions ...string) SecurityContext {
+	perms := slices.Clone(permissions)
+	if perms == nil {
+		perms = []string{}
+	}
+	slices.Sort(perms)
+	return SecurityContext{
+		TenantID:    tenantID,
+		UserID:      userID,
+		Permissions: slices.Compact(perms),
+	}
+}
+
+// Validate checks that the context identifies who acted (CP-6): both
+// TenantID and UserID must be non-empty. All problems are reported together.
+func (sc SecurityContext) Validate() error {
+	var errs []error
+	if sc.TenantID == "" {
+		errs = append(errs, ValidationError{Field: "security_context.tenant_id", Message: "tenant_id is required"})
+	}
+	if sc.UserID == "" {
+		errs = append(errs, ValidationError{Field: "security_context.user_id", Message: "user_id is required"})
+	}
+	return errors.Join(errs...)
+}
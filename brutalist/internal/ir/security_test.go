@@ -0,0 +1,46 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecurityContextValidate(t *testing.T) {
+	assert.NoError(t, NewSecurityContext("tenant-1", "user-1").Validate())
+
+	err := SecurityContext{UserID: "user-1"}.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "security_context.tenant_id")
+
+	err = SecurityContext{TenantID: "tenant-1"}.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "security_context.user_id")
+
+	err = SecurityContext{}.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tenant_id")
+	assert.Contains(t, err.Error(), "user_id")
+}
+
+func TestNewSecurityContextNormalizesPermissions(t *testing.T) {
+	sc := NewSecurityContext("tenant-1", "user-1", "write", "read", "write", "admin")
+	assert.Equal(t, []string{"admin", "read", "write"}, sc.Permissions)
+
+	// Equivalent permission sets serialize identically
+	other := NewSecurityContext("tenant-1", "user-1", "read", "admin", "write")
+	assert.Equal(t, sc, other)
+}
+
+func TestNewSecurityContextEmptyPermissions(t *testing.T) {
+	sc := NewSecurityContext("tenant-1", "user-1")
+	assert.NotNil(t, sc.Permissions, "permissions marshal as [] rather than null")
+	assert.Empty(t, sc.Permissions)
+}
+
+func TestNewSecurityContextDoesNotAliasInput(t *testing.T) {
+	perms := []string{"b", "a"}
+	NewSecurityContext("tenant-1", "user-1", perms...)
+	assert.Equal(t, []string{"b", "a"}, perms)
+}
@@ -0,0 +1,144 @@
+package ir
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// boundVarPattern matches "bound.variable_name" references in then-arg expressions.
+var boundVarPattern = regexp.MustCompile(`bound\.([a-zA-Z_][a-zA-Z0-9_]*)`)
+
+// Validate checks the structural invariants of a sync rule that need no
+// concept context: scope mode, keyed-scope key, event type, action reference
+// format, typed literals in then.args, and that every bound variable used in
+// then.args is defined.
+// Cross-concept checks (actions exist, output cases, arg types) belong to
+// the compiler.
+//
+// An empty scope mode (defaults to "flow") and an empty event type
+// (defaults to "completed") are accepted, matching the engine.
+// Returns all errors (not fail-fast), each a ValidationError.
+func (r SyncRule) Validate() []error {
+	var errs []error
+
+	if r.Scope.Mode != "" && !ValidScopeModes[r.Scope.Mode] {
+		errs = append(errs, ValidationError{
+			Field:   "scope.mode",
+			Message: fmt.Sprintf("invalid scope mode %q, must be \"flow\", \"global\", or \"keyed\"", r.Scope.Mode),
+		})
+	}
+	if r.Scope.Mode == "keyed" && strings.TrimSpace(r.Scope.Key) == "" {
+		errs = append(errs, ValidationError{
+			Field:   "scope.key",
+			Message: "keyed scope requires a non-empty key field",
+		})
+	}
+
+	if !ActionRef(r.When.ActionRef).IsValid() {
+		errs = append(errs, ValidationError{
+			Field:   "when.action_ref",
+			Message: fmt.Sprintf("invalid action reference %q, expected format \"Concept.action\"", r.When.ActionRef),
+		})
+	}
+	switch r.When.EventType {
+	case "", "completed", "invoked":
+	default:
+		errs = append(errs, ValidationError{
+			Field:   "when.event_type",
+			Message: fmt.Sprintf("invalid event type %q, must be \"completed\" or \"invoked\"", r.When.EventType),
+		})
+	}
+
+	if !ActionRef(r.Then.ActionRef).IsValid() {
+		errs = append(errs, ValidationError{
+			Field:   "then.action_ref",
+			Message: fmt.Sprintf("invalid action reference %q, expected format \"Concept.action\"", r.Then.ActionRef),
+		})
+	}
+
+	defined := r.boundVariables()
+	argNames := make([]string, 0, len(r.Then.Args))
+	for name := range r.Then.Args {
+		argNames = append(argNames, name)
+	}
+	sort.Strings(argNames)
+	for _, name := range argNames {
+		expr := r.Then.Args[name]
+		if IsTypedLiteral(expr) {
+			if _, err := ParseArgLiteral(expr); err != nil {
+				errs = append(errs, ValidationError{
+					Field:   "then.args." + name,
+					Message: err.Error(),
+				})
+			}
+			continue
+		}
+		for _, v := range BoundVariableRefs(expr) {
+			if !defined[v] {
+				errs = append(errs, ValidationError{
+					Field:   "then.args." + name,
+					Message: fmt.Sprintf("undefined bound variable %q in expression %q", v, expr),
+				})
+			}
+		}
+	}
+
+	return errs
+}
+
+// boundVariables returns the variable names defined by the when and where
+// bindings: the keys of each bindings map (var name → path expression).
+func (r SyncRule) boundVariables() map[string]bool {
+	vars := make(map[string]bool)
+	for name := range r.When.Bindings {
+		vars[name] = true
+	}
+	if r.Where != nil {
+		for name := range r.Where.Bindings {
+			vars[name] = true
+		}
+	}
+	return vars
+}
+
+// BoundVariableRefs returns the variable names an expression references as
+// "bound.name", in order of appearance. Typed literals ("str:bound.x") are
+// values, not references, and yield none.
+func BoundVariableRefs(expr string) []string {
+	if IsTypedLiteral(expr) {
+		return nil
+	}
+	matches := boundVarPattern.FindAllStringSubmatch(expr, -1)
+	vars := make([]string, 0, len(matches))
+	for _, m := range matches {
+		vars = append(vars, m[1])
+	}
+	return vars
+}
+
+// SortSyncRules sorts sync rules in place into evaluation order (CRITICAL-3).
+//
+// Rules with a Priority come first, in ascending priority; equal priorities
+// are ordered by rule ID, so the result does not depend on which file
+// declared them first. Rules without a Priority follow, keeping their
+// relative declaration order.
+func SortSyncRules(rules []SyncRule) {
+	slices.SortStableFunc(rules, func(a, b SyncRule) int {
+		switch {
+		case a.Priority == nil && b.Priority == nil:
+			return 0 // Declaration order
+		case a.Priority == nil:
+			return 1
+		case b.Priority == nil:
+			return -1
+		}
+		if c := cmp.Compare(*a.Priority, *b.Priority); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+}
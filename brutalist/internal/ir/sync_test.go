@@ -0,0 +1,231 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// validSyncRule returns a rule that passes Validate, for tests to break.
+func validSyncRule() SyncRule {
+	return SyncRule{
+		ID:    "cart-inventory",
+		Scope: ScopeSpec{Mode: "flow"},
+		When: WhenClause{
+			ActionRef: "Cart.checkout",
+			EventType: "completed",
+			Bindings:  map[string]string{"cart_id": "result.cart_id"},
+		},
+		Where: &WhereClause{
+			Source:   "CartItem",
+			Filter:   "cart_id == bound.cart_id",
+			Bindings: map[string]string{"item_id": "item_id"},
+		},
+		Then: ThenClause{
+			ActionRef: "Inventory.reserve",
+			Args: map[string]string{
+				"cart_id": "bound.cart_id",
+				"item_id": "bound.item_id",
+			},
+		},
+	}
+}
+
+func TestSyncRuleValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		mutate   func(r *SyncRule)
+		wantErrs []string // Expected fields, in order
+	}{
+		{
+			name:   "valid rule",
+			mutate: func(r *SyncRule) {},
+		},
+		{
+			name:   "empty scope mode defaults to flow",
+			mutate: func(r *SyncRule) { r.Scope.Mode = "" },
+		},
+		{
+			name:   "global scope",
+			mutate: func(r *SyncRule) { r.Scope.Mode = "global" },
+		},
+		{
+			name:   "keyed scope with key",
+			mutate: func(r *SyncRule) { r.Scope = ScopeSpec{Mode: "keyed", Key: "user_id"} },
+		},
+		{
+			name:     "invalid scope mode",
+			mutate:   func(r *SyncRule) { r.Scope.Mode = "session" },
+			wantErrs: []string{"scope.mode"},
+		},
+		{
+			name:     "keyed scope without key",
+			mutate:   func(r *SyncRule) { r.Scope = ScopeSpec{Mode: "keyed"} },
+			wantErrs: []string{"scope.key"},
+		},
+		{
+			name:     "keyed scope with blank key",
+			mutate:   func(r *SyncRule) { r.Scope = ScopeSpec{Mode: "keyed", Key: "  "} },
+			wantErrs: []string{"scope.key"},
+		},
+		{
+			name:   "invoked event type",
+			mutate: func(r *SyncRule) { r.When.EventType = "invoked" },
+		},
+		{
+			name:   "empty event type defaults to completed",
+			mutate: func(r *SyncRule) { r.When.EventType = "" },
+		},
+		{
+			name:     "invalid event type",
+			mutate:   func(r *SyncRule) { r.When.EventType = "started" },
+			wantErrs: []string{"when.event_type"},
+		},
+		{
+			name:     "invalid when action ref",
+			mutate:   func(r *SyncRule) { r.When.ActionRef = "cart.checkout" },
+			wantErrs: []string{"when.action_ref"},
+		},
+		{
+			name:     "invalid then action ref",
+			mutate:   func(r *SyncRule) { r.Then.ActionRef = "Inventory" },
+			wantErrs: []string{"then.action_ref"},
+		},
+		{
+			name:     "undefined bound variable",
+			mutate:   func(r *SyncRule) { r.Then.Args["quantity"] = "bound.quantity" },
+			wantErrs: []string{"then.args.quantity"},
+		},
+		{
+			name: "variable bound only by a removed where clause",
+			mutate: func(r *SyncRule) {
+				r.Where = nil
+			},
+			wantErrs: []string{"then.args.item_id"},
+		},
+		{
+			name: "where binding values are paths, not variables",
+			mutate: func(r *SyncRule) {
+				r.Where.Bindings = map[string]string{"itemId": "item_id"}
+			},
+			wantErrs: []string{"then.args.item_id"},
+		},
+		{
+			name:   "literal then arg",
+			mutate: func(r *SyncRule) { r.Then.Args["quantity"] = "1" },
+		},
+		{
+			name:   "typed literal is not a bound reference",
+			mutate: func(r *SyncRule) { r.Then.Args["label"] = "str:bound.missing" },
+		},
+		{
+			name:     "float literal rejected",
+			mutate:   func(r *SyncRule) { r.Then.Args["quantity"] = "float:1.5" },
+			wantErrs: []string{"then.args.quantity"},
+		},
+		{
+			name: "all errors collected",
+			mutate: func(r *SyncRule) {
+				r.Scope = ScopeSpec{Mode: "keyed"}
+				r.When.ActionRef = ""
+				r.When.EventType = "started"
+				r.Then.ActionRef = ""
+				r.Then.Args = map[string]string{"b": "bound.y", "a": "bound.x"}
+			},
+			wantErrs: []string{
+				"scope.key",
+				"when.action_ref",
+				"when.event_type",
+				"then.action_ref",
+				"then.args.a",
+				"then.args.b",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := validSyncRule()
+			tt.mutate(&rule)
+
+			errs := rule.Validate()
+			fields := make([]string, len(errs))
+			for i, err := range errs {
+				var verr ValidationError
+				require.ErrorAs(t, err, &verr)
+				fields[i] = verr.Field
+			}
+			if len(tt.wantErrs) == 0 {
+				assert.Empty(t, errs)
+				return
+			}
+			assert.Equal(t, tt.wantErrs, fields)
+		})
+	}
+}
+
+func TestSyncRuleValidate_Messages(t *testing.T) {
+	rule := validSyncRule()
+	rule.Scope.Mode = "session"
+	rule.Then.Args["quantity"] = "bound.quantity"
+
+	errs := rule.Validate()
+	require.Len(t, errs, 2)
+	assert.EqualError(t, errs[0], `scope.mode: invalid scope mode "session", must be "flow", "global", or "keyed"`)
+	assert.EqualError(t, errs[1], `then.args.quantity: undefined bound variable "quantity" in expression "bound.quantity"`)
+}
+
+func TestBoundVariableRefs(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected []string
+	}{
+		{"bound.cart_id", []string{"cart_id"}},
+		{"bound.x + bound.y", []string{"x", "y"}},
+		{"no refs here", []string{}},
+		{"bound.item_id + 1", []string{"item_id"}},
+		{"bound.a_b_c", []string{"a_b_c"}},
+		{"bound.x123", []string{"x123"}},
+		{"str:bound.x", nil},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, BoundVariableRefs(tt.expr), "for expr: %s", tt.expr)
+	}
+}
+
+func TestSortSyncRules(t *testing.T) {
+	priority := func(p int) *int { return &p }
+	rules := []SyncRule{
+		{ID: "unprioritized-b"},
+		{ID: "tie-b", Priority: priority(5)},
+		{ID: "late", Priority: priority(10)},
+		{ID: "unprioritized-a"},
+		{ID: "tie-a", Priority: priority(5)},
+		{ID: "first", Priority: priority(-1)},
+	}
+
+	SortSyncRules(rules)
+
+	var ids []string
+	for _, r := range rules {
+		ids = append(ids, r.ID)
+	}
+	assert.Equal(t, []string{
+		"first",
+		// Equal priorities break ties by ID, not declaration order
+		"tie-a",
+		"tie-b",
+		"late",
+		// Unprioritized rules follow in declaration order
+		"unprioritized-b",
+		"unprioritized-a",
+	}, ids)
+}
+
+func TestSortSyncRules_NoPrioritiesKeepsDeclarationOrder(t *testing.T) {
+	rules := []SyncRule{{ID: "c"}, {ID: "a"}, {ID: "b"}}
+	SortSyncRules(rules)
+	assert.Equal(t, []SyncRule{{ID: "c"}, {ID: "a"}, {ID: "b"}}, rules)
+}
@@ -10,11 +10,15 @@ type ConceptSpec struct {
 }
 
 // ActionSig represents an action signature with typed inputs/outputs.
+//
+// An enum-constrained arg keeps the type name "string"; its allowed values
+// are listed in Enums.
 type ActionSig struct {
-	Name     string       `json:"name"`
-	Args     []NamedArg   `json:"args"`
-	Outputs  []OutputCase `json:"outputs"`
-	Requires []string     `json:"requires,omitempty"` // Required permissions (authz)
+	Name     string              `json:"name"`
+	Args     []NamedArg          `json:"args"`
+	Enums    map[string][]string `json:"enums,omitempty"` // string arg name -> allowed values
+	Outputs  []OutputCase        `json:"outputs"`
+	Requires []string            `json:"requires,omitempty"` // Required permissions (authz)
 }
 
 // OutputCase represents a typed output variant (success or error).
@@ -24,9 +28,13 @@ type OutputCase struct {
 }
 
 // StateSchema represents a state table definition.
+//
+// An enum-constrained field keeps the type name "string"; its allowed values
+// are listed in Enums.
 type StateSchema struct {
-	Name   string            `json:"name"`
-	Fields map[string]string `json:"fields"` // field name -> type name
+	Name   string              `json:"name"`
+	Fields map[string]string   `json:"fields"`          // field name -> type name
+	Enums  map[string][]string `json:"enums,omitempty"` // string field name -> allowed values
 }
 
 // NamedArg represents a named argument with type.
@@ -42,12 +50,16 @@ type OperationalPrinciple struct {
 }
 
 // SyncRule represents a compiled sync rule (when/where/then).
+//
+// Priority optionally overrides declaration order for evaluation: rules with
+// a priority run first, lowest number first (see SortSyncRules).
 type SyncRule struct {
-	ID    string       `json:"id"`
-	Scope ScopeSpec    `json:"scope"`
-	When  WhenClause   `json:"when"`
-	Where *WhereClause `json:"where,omitempty"` // Optional
-	Then  ThenClause   `json:"then"`
+	ID       string       `json:"id"`
+	Scope    ScopeSpec    `json:"scope"`
+	When     WhenClause   `json:"when"`
+	Where    *WhereClause `json:"where,omitempty"` // Optional
+	Then     ThenClause   `json:"then"`
+	Priority *int         `json:"priority,omitempty"` // Optional; nil = after all prioritized rules
 }
 
 // ScopeSpec defines the scoping mode for a sync rule.
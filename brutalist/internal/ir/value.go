@@ -296,6 +296,45 @@ func MarshalIRValue(v IRValue) ([]byte, error) {
 	}
 }
 
+// Equal reports whether two IRValues are structurally equal.
+// Objects are compared by key set and values, arrays element by element.
+// A nil IRValue equals only another nil.
+func Equal(a, b IRValue) bool {
+	switch av := a.(type) {
+	case nil:
+		return b == nil
+	case IRNull:
+		_, ok := b.(IRNull)
+		return ok
+	case IRString:
+		bv, ok := b.(IRString)
+		return ok && av == bv
+	case IRInt:
+		bv, ok := b.(IRInt)
+		return ok && av == bv
+	case IRBool:
+		bv, ok := b.(IRBool)
+		return ok && av == bv
+	case IRArray:
+		bv, ok := b.(IRArray)
+		return ok && slices.EqualFunc(av, bv, Equal)
+	case IRObject:
+		bv, ok := b.(IRObject)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for k, aElem := range av {
+			bElem, ok := bv[k]
+			if !ok || !Equal(aElem, bElem) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
 // marshalIRArray marshals an IRArray to JSON bytes.
 func marshalIRArray(arr IRArray) ([]byte, error) {
 	var buf bytes.Buffer
@@ -465,3 +465,15 @@ func TestUnmarshalValidJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestEqual(t *testing.T) {
+	assert.True(t, Equal(IRInt(1), IRInt(1)))
+	assert.False(t, Equal(IRInt(1), IRString("1")))
+	assert.True(t, Equal(IRNull{}, IRNull{}))
+	assert.True(t, Equal(nil, nil))
+	assert.False(t, Equal(nil, IRNull{}))
+	assert.True(t, Equal(IRArray{IRInt(1), IRObject{"a": IRBool(true)}}, IRArray{IRInt(1), IRObject{"a": IRBool(true)}}))
+	assert.False(t, Equal(IRArray{IRInt(1)}, IRArray{IRInt(1), IRInt(2)}))
+	assert.False(t, Equal(IRObject{"a": IRInt(1)}, IRObject{"b": IRInt(1)}))
+	assert.True(t, Equal(IRObject{}, IRObject(nil)))
+}
@@ -0,0 +1,84 @@
+package queryir
+
+import "github.com/roach88/nysm/internal/ir"
+
+// GreaterThan represents a field-greater-than predicate over integers.
+//
+// Semantics:
+//
+//	<field> > <value>          (Value literal)
+//	<field> > <bound_variable> (when BoundVar is set)
+//
+// The comparison predicates (GreaterThan, LessThan, GreaterOrEqual,
+// LessOrEqual) share the same shape:
+//  1. Field references a field in the current query source
+//  2. The right-hand side is either an IRInt literal (Value) or a
+//     when-clause variable (BoundVar, e.g. "bound.min")
+//  3. When BoundVar is non-empty it takes precedence over Value
+//
+// Example:
+//
+//	GreaterOrEqual{Field: "quantity", BoundVar: "bound.min"}
+//
+// Translates to SQL:
+//
+//	quantity >= ?
+//
+// PORTABLE FRAGMENT RULES:
+//   - Operands are integers only (IRInt); floats remain forbidden per CP-5
+//   - Bound variables must resolve to integers at execution time
+//   - String ordering is NOT portable (collation differs across backends)
+//
+// SPARQL MAPPING:
+//
+//	GreaterThan{Field: "quantity", Value: ir.IRInt(5)}
+//
+// becomes:
+//
+//	FILTER(?quantity > 5)
+type GreaterThan struct {
+	Field    string   // Field name in current query source
+	Value    ir.IRInt // Literal operand (used when BoundVar is empty)
+	BoundVar string   // Optional bound variable operand (e.g., "bound.min")
+}
+
+func (GreaterThan) predicateNode() {}
+
+// LessThan represents a field-less-than predicate over integers.
+//
+//	<field> < <value | bound_variable>
+//
+// See GreaterThan for operand rules and SPARQL mapping (FILTER(?f < v)).
+type LessThan struct {
+	Field    string   // Field name in current query source
+	Value    ir.IRInt // Literal operand (used when BoundVar is empty)
+	BoundVar string   // Optional bound variable operand (e.g., "bound.max")
+}
+
+func (LessThan) predicateNode() {}
+
+// GreaterOrEqual represents a field-greater-or-equal predicate over integers.
+//
+//	<field> >= <value | bound_variable>
+//
+// See GreaterThan for operand rules and SPARQL mapping (FILTER(?f >= v)).
+type GreaterOrEqual struct {
+	Field    string   // Field name in current query source
+	Value    ir.IRInt // Literal operand (used when BoundVar is empty)
+	BoundVar string   // Optional bound variable operand (e.g., "bound.min")
+}
+
+func (GreaterOrEqual) predicateNode() {}
+
+// LessOrEqual represents a field-less-or-equal predicate over integers.
+//
+//	<field> <= <value | bound_variable>
+//
+// See GreaterThan for operand rules and SPARQL mapping (FILTER(?f <= v)).
+type LessOrEqual struct {
+	Field    string   // Field name in current query source
+	Value    ir.IRInt // Literal operand (used when BoundVar is empty)
+	BoundVar string   // Optional bound variable operand (e.g., "bound.max")
+}
+
+func (LessOrEqual) predicateNode() {}
@@ -0,0 +1,62 @@
+package queryir
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+func TestComparison_ImplementsPredicate(t *testing.T) {
+	predicates := []Predicate{
+		GreaterThan{Field: "quantity", Value: ir.IRInt(1)},
+		LessThan{Field: "quantity", Value: ir.IRInt(10)},
+		GreaterOrEqual{Field: "quantity", BoundVar: "bound.min"},
+		LessOrEqual{Field: "quantity", BoundVar: "bound.max"},
+	}
+
+	for _, p := range predicates {
+		// Type switch is exhaustive - compiler knows all types
+		switch p.(type) {
+		case GreaterThan, LessThan, GreaterOrEqual, LessOrEqual:
+			// OK
+		default:
+			t.Fatalf("unexpected predicate type: %T", p)
+		}
+	}
+}
+
+func TestComparison_Construction(t *testing.T) {
+	gt := GreaterThan{Field: "quantity", Value: ir.IRInt(5)}
+	assert.Equal(t, "quantity", gt.Field)
+	assert.Equal(t, ir.IRInt(5), gt.Value)
+	assert.Empty(t, gt.BoundVar)
+
+	le := LessOrEqual{Field: "quantity", BoundVar: "bound.max"}
+	assert.Equal(t, "bound.max", le.BoundVar)
+}
+
+func TestValidate_ComparisonsArePortable(t *testing.T) {
+	predicates := []Predicate{
+		GreaterThan{Field: "quantity", Value: ir.IRInt(1)},
+		&GreaterThan{Field: "quantity", Value: ir.IRInt(1)},
+		LessThan{Field: "quantity", Value: ir.IRInt(10)},
+		&LessThan{Field: "quantity", Value: ir.IRInt(10)},
+		GreaterOrEqual{Field: "quantity", BoundVar: "bound.min"},
+		&GreaterOrEqual{Field: "quantity", BoundVar: "bound.min"},
+		LessOrEqual{Field: "quantity", BoundVar: "bound.max"},
+		&LessOrEqual{Field: "quantity", BoundVar: "bound.max"},
+	}
+
+	for _, p := range predicates {
+		result := Validate(Select{
+			From:     "inventory",
+			Filter:   p,
+			Bindings: map[string]string{"item_id": "item"},
+		})
+
+		assert.True(t, result.IsPortable, "%T should be portable", p)
+		assert.Empty(t, result.Warnings)
+	}
+}
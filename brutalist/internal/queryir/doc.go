@@ -20,18 +20,25 @@
 // PORTABLE FRAGMENT:
 //
 // The portable fragment includes:
-//   - Select(from, filter, bindings) - Table/source access with filtering
+//   - Select(from, filter, bindings, limit) - Table/source access with filtering
 //   - Join(left, right, on) - Inner joins only
-//   - Predicates: Equals, BoundEquals, And
+//   - Union(left, right) - Set union with identical output bindings
+//   - Projection(query, outputs) - Renamed or constant output bindings
+//   - OrderBy(query, keys) - Explicit result ordering by bound fields
+//   - Predicates: Equals, BoundEquals, NotEquals, And
+//   - Comparisons: GreaterThan, LessThan, GreaterOrEqual, LessOrEqual
+//     (integer operands only)
+//   - In(field, values) - set membership (portable with translation)
 //   - Explicit field bindings (no SELECT *)
 //
 // The portable fragment EXCLUDES:
 //   - NULLs (use explicit Option types or IS NOT NULL filters)
 //   - Outer joins (LEFT/RIGHT/FULL not portable to SPARQL)
-//   - Aggregations (SUM/COUNT/GROUP BY not in MVP)
+//   - Aggregations (SUM/COUNT/GROUP BY not in MVP; the Count node is a
+//     SQL-backend-specific exception for guard conditions)
 //   - SELECT * (explicit bindings required)
 //   - Subqueries (not in MVP)
-//   - OR predicates (use separate rules or UNION)
+//   - OR predicates (use separate rules or Union)
 //
 // SEALED INTERFACES:
 //
@@ -54,6 +61,12 @@
 //	    // Impossible - compiler knows all Query types
 //	}
 //
+// VALIDATION:
+//
+// Validate reports portability warnings (features outside the portable
+// fragment). ValidateSchema checks a query against concept StateSchemas:
+// unknown sources, unknown fields, and literal/field type mismatches.
+//
 // SPARQL MIGRATION:
 //
 // The QueryIR portable fragment maps cleanly to SPARQL:
@@ -62,9 +75,32 @@
 //	-------              ------
 //	Select               SELECT with triple patterns
 //	Join                 Multiple triple patterns (implicit join)
+//	Union                { ... } UNION { ... }
+//	Projection           BIND(?source AS ?output), BIND(value AS ?output)
+//	OrderBy              ORDER BY ASC(?var) DESC(?var)
 //	Equals               ?var = "value"
 //	BoundEquals          Variable binding from outer scope
+//	NotEquals            FILTER(?var != value)
 //	And                  Multiple filters (implicit AND)
+//	GreaterThan          FILTER(?var > value)
+//	LessThan             FILTER(?var < value)
+//	GreaterOrEqual       FILTER(?var >= value)
+//	LessOrEqual          FILTER(?var <= value)
+//	In                   VALUES ?var { v1 v2 ... }
+//
+// Comparisons are SPARQL-portable via FILTER because operands are restricted
+// to integers, whose ordering is identical in both backends. String ordering
+// depends on collation and is deliberately not offered.
+//
+// NotEquals only matches rows where the field has a value, since neither
+// backend treats a comparison with NULL (or an unbound variable) as true.
+// Negate flips a predicate to its complement within the portable fragment.
+//
+// OrderBy keys are applied ahead of each backend's deterministic tiebreak
+// (seq, id in SQL per CP-4), so ties between equal keys are still stable.
+//
+// In is portable with translation: SPARQL expresses set membership with a
+// VALUES block (or a UNION of equality patterns) rather than a direct IN.
 //
 // Queries using portable fragment only are SPARQL-ready. Queries using
 // SQL-specific features require explicit migration.
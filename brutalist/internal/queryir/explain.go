@@ -0,0 +1,252 @@
+package queryir
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+// Explain renders a query tree as an indented, human-readable outline.
+//
+// Used to debug where-clauses that produce unexpected bindings. Output is
+// deterministic so it is suitable for golden tests:
+//   - Bindings are listed sorted by source field
+//   - Predicates inside And are sorted by their rendered text
+//
+// Example:
+//
+//	Select CartItems
+//	  bind: item_id -> itemId
+//	  filter:
+//	    And
+//	      BoundEquals cart_id = bound.cartId
+//	      Equals status = "active"
+//
+// Explain is a pure function with no side effects.
+func Explain(q Query) string {
+	var b strings.Builder
+	explainQuery(&b, q, 0)
+	return b.String()
+}
+
+// explainQuery writes a query node and its children at the given depth.
+func explainQuery(b *strings.Builder, q Query, depth int) {
+	switch query := q.(type) {
+	case Select:
+		explainSelect(b, query, depth)
+	case *Select:
+		explainSelect(b, *query, depth)
+	case Join:
+		explainJoin(b, query, depth)
+	case *Join:
+		explainJoin(b, *query, depth)
+	case Union:
+		explainUnion(b, query, depth)
+	case *Union:
+		explainUnion(b, *query, depth)
+	case Count:
+		writeLine(b, depth, "Count")
+		explainSelect(b, query.Select, depth+1)
+	case *Count:
+		explainQuery(b, *query, depth)
+	case Projection:
+		explainProjection(b, query, depth)
+	case *Projection:
+		explainProjection(b, *query, depth)
+	case OrderBy:
+		explainOrderBy(b, query, depth)
+	case *OrderBy:
+		explainOrderBy(b, *query, depth)
+	case nil:
+		writeLine(b, depth, "<nil query>")
+	default:
+		writeLine(b, depth, fmt.Sprintf("<unknown query %T>", q))
+	}
+}
+
+// explainSelect writes a Select node.
+func explainSelect(b *strings.Builder, sel Select, depth int) {
+	writeLine(b, depth, "Select "+sel.From)
+
+	if len(sel.Bindings) == 0 {
+		writeLine(b, depth+1, "bind: *")
+	} else {
+		keys := make([]string, 0, len(sel.Bindings))
+		for k := range sel.Bindings {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			writeLine(b, depth+1, fmt.Sprintf("bind: %s -> %s", k, sel.Bindings[k]))
+		}
+	}
+
+	if sel.Filter != nil {
+		writeLine(b, depth+1, "filter:")
+		b.WriteString(explainPredicate(sel.Filter, depth+2))
+	}
+
+	if sel.Limit != 0 {
+		writeLine(b, depth+1, fmt.Sprintf("limit: %d", sel.Limit))
+	}
+}
+
+// explainJoin writes a Join node.
+func explainJoin(b *strings.Builder, join Join, depth int) {
+	writeLine(b, depth, "Join")
+	writeLine(b, depth+1, "left:")
+	explainQuery(b, join.Left, depth+2)
+	writeLine(b, depth+1, "right:")
+	explainQuery(b, join.Right, depth+2)
+	if join.On != nil {
+		writeLine(b, depth+1, "on:")
+		b.WriteString(explainPredicate(join.On, depth+2))
+	}
+}
+
+// explainUnion writes a Union node.
+func explainUnion(b *strings.Builder, union Union, depth int) {
+	writeLine(b, depth, "Union")
+	writeLine(b, depth+1, "left:")
+	explainQuery(b, union.Left, depth+2)
+	writeLine(b, depth+1, "right:")
+	explainQuery(b, union.Right, depth+2)
+}
+
+// explainProjection writes a Projection node.
+// Outputs are listed sorted by name: renames as "name <- field",
+// constants as "name = value".
+func explainProjection(b *strings.Builder, proj Projection, depth int) {
+	writeLine(b, depth, "Projection")
+	for _, name := range sortedKeys(proj.Outputs) {
+		src := proj.Outputs[name]
+		if src.Field != "" {
+			writeLine(b, depth+1, fmt.Sprintf("out: %s <- %s", name, src.Field))
+		} else {
+			writeLine(b, depth+1, fmt.Sprintf("out: %s = %s", name, explainValue(src.Value)))
+		}
+	}
+	writeLine(b, depth+1, "of:")
+	explainQuery(b, proj.Query, depth+2)
+}
+
+// explainOrderBy writes an OrderBy node.
+// Keys are listed in order as "key: field direction".
+func explainOrderBy(b *strings.Builder, order OrderBy, depth int) {
+	writeLine(b, depth, "OrderBy")
+	for _, key := range order.Keys {
+		direction := key.Direction
+		if direction == "" {
+			direction = Ascending
+		}
+		writeLine(b, depth+1, fmt.Sprintf("key: %s %s", key.Field, direction))
+	}
+	writeLine(b, depth+1, "of:")
+	explainQuery(b, order.Query, depth+2)
+}
+
+// explainPredicate renders a predicate subtree at the given depth.
+// Returned as a string so And can sort its children before writing.
+func explainPredicate(p Predicate, depth int) string {
+	var b strings.Builder
+
+	switch pred := p.(type) {
+	case Equals:
+		writeLine(&b, depth, fmt.Sprintf("Equals %s = %s", pred.Field, explainValue(pred.Value)))
+	case *Equals:
+		return explainPredicate(*pred, depth)
+	case BoundEquals:
+		writeLine(&b, depth, fmt.Sprintf("BoundEquals %s = %s", pred.Field, pred.BoundVar))
+	case *BoundEquals:
+		return explainPredicate(*pred, depth)
+	case NotEquals:
+		operand := pred.BoundVar
+		if operand == "" {
+			operand = explainValue(pred.Value)
+		}
+		writeLine(&b, depth, fmt.Sprintf("NotEquals %s != %s", pred.Field, operand))
+	case *NotEquals:
+		return explainPredicate(*pred, depth)
+	case GreaterThan:
+		writeLine(&b, depth, explainComparison("GreaterThan", ">", pred.Field, pred.Value, pred.BoundVar))
+	case *GreaterThan:
+		return explainPredicate(*pred, depth)
+	case LessThan:
+		writeLine(&b, depth, explainComparison("LessThan", "<", pred.Field, pred.Value, pred.BoundVar))
+	case *LessThan:
+		return explainPredicate(*pred, depth)
+	case GreaterOrEqual:
+		writeLine(&b, depth, explainComparison("GreaterOrEqual", ">=", pred.Field, pred.Value, pred.BoundVar))
+	case *GreaterOrEqual:
+		return explainPredicate(*pred, depth)
+	case LessOrEqual:
+		writeLine(&b, depth, explainComparison("LessOrEqual", "<=", pred.Field, pred.Value, pred.BoundVar))
+	case *LessOrEqual:
+		return explainPredicate(*pred, depth)
+	case In:
+		values := make([]string, len(pred.Values))
+		for i, v := range pred.Values {
+			values[i] = explainValue(v)
+		}
+		writeLine(&b, depth, fmt.Sprintf("In %s IN (%s)", pred.Field, strings.Join(values, ", ")))
+	case *In:
+		return explainPredicate(*pred, depth)
+	case And:
+		writeLine(&b, depth, "And")
+		children := make([]string, len(pred.Predicates))
+		for i, sub := range pred.Predicates {
+			children[i] = explainPredicate(sub, depth+1)
+		}
+		sort.Strings(children)
+		for _, child := range children {
+			b.WriteString(child)
+		}
+	case *And:
+		return explainPredicate(*pred, depth)
+	case nil:
+		writeLine(&b, depth, "<nil predicate>")
+	default:
+		writeLine(&b, depth, fmt.Sprintf("<unknown predicate %T>", p))
+	}
+
+	return b.String()
+}
+
+// explainComparison renders an integer comparison with its operand.
+func explainComparison(name, op, field string, value ir.IRInt, boundVar string) string {
+	operand := fmt.Sprintf("%d", int64(value))
+	if boundVar != "" {
+		operand = boundVar
+	}
+	return fmt.Sprintf("%s %s %s %s", name, field, op, operand)
+}
+
+// explainValue renders a literal IRValue.
+// Strings are quoted so they are distinguishable from bound references.
+func explainValue(v ir.IRValue) string {
+	switch val := v.(type) {
+	case ir.IRString:
+		return fmt.Sprintf("%q", string(val))
+	case ir.IRInt:
+		return fmt.Sprintf("%d", int64(val))
+	case ir.IRBool:
+		return fmt.Sprintf("%t", bool(val))
+	case ir.IRNull, nil:
+		return "null"
+	default:
+		data, err := ir.MarshalCanonical(v)
+		if err != nil {
+			return fmt.Sprintf("<%T>", v)
+		}
+		return string(data)
+	}
+}
+
+// writeLine writes one indented line (two spaces per depth level).
+func writeLine(b *strings.Builder, depth int, text string) {
+	b.WriteString(strings.Repeat("  ", depth))
+	b.WriteString(text)
+	b.WriteString("\n")
+}
@@ -0,0 +1,116 @@
+package queryir
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+func TestExplain_NestedJoinAnd(t *testing.T) {
+	query := Join{
+		Left: Select{
+			From: "Carts",
+			Filter: &And{Predicates: []Predicate{
+				Equals{Field: "status", Value: ir.IRString("active")},
+				BoundEquals{Field: "cart_id", BoundVar: "bound.cartId"},
+				And{Predicates: []Predicate{
+					GreaterOrEqual{Field: "total", BoundVar: "bound.min"},
+					LessThan{Field: "total", Value: ir.IRInt(1000)},
+				}},
+			}},
+			Bindings: map[string]string{"user_id": "userId", "cart_id": "cartId"},
+		},
+		Right: &Select{
+			From:     "CartItems",
+			Filter:   In{Field: "state", Values: []ir.IRValue{ir.IRString("held"), ir.IRInt(2)}},
+			Bindings: map[string]string{"item_id": "itemId"},
+		},
+		On: Equals{Field: "CartItems.cart_id", Value: ir.IRString("cart-1")},
+	}
+
+	want := `Join
+  left:
+    Select Carts
+      bind: cart_id -> cartId
+      bind: user_id -> userId
+      filter:
+        And
+          And
+            GreaterOrEqual total >= bound.min
+            LessThan total < 1000
+          BoundEquals cart_id = bound.cartId
+          Equals status = "active"
+  right:
+    Select CartItems
+      bind: item_id -> itemId
+      filter:
+        In state IN ("held", 2)
+  on:
+    Equals CartItems.cart_id = "cart-1"
+`
+
+	assert.Equal(t, want, Explain(query))
+}
+
+func TestExplain_AndOrderIsDeterministic(t *testing.T) {
+	a := Equals{Field: "a", Value: ir.IRInt(1)}
+	b := BoundEquals{Field: "b", BoundVar: "bound.b"}
+
+	first := Explain(Select{From: "T", Filter: And{Predicates: []Predicate{a, b}}})
+	second := Explain(Select{From: "T", Filter: And{Predicates: []Predicate{b, a}}})
+
+	assert.Equal(t, first, second)
+}
+
+func TestExplain_SelectStarAndUnion(t *testing.T) {
+	query := Union{
+		Left:  Select{From: "Orders"},
+		Right: Select{From: "Archive", Filter: Equals{Field: "open", Value: ir.IRBool(false)}},
+	}
+
+	want := `Union
+  left:
+    Select Orders
+      bind: *
+  right:
+    Select Archive
+      bind: *
+      filter:
+        Equals open = false
+`
+
+	assert.Equal(t, want, Explain(query))
+}
+
+func TestExplain_Limit(t *testing.T) {
+	query := Select{
+		From:     "Events",
+		Bindings: map[string]string{"id": "eventId"},
+		Limit:    10,
+	}
+
+	want := `Select Events
+  bind: id -> eventId
+  limit: 10
+`
+
+	assert.Equal(t, want, Explain(query))
+}
+
+func TestExplain_Count(t *testing.T) {
+	query := Count{Select: Select{
+		From:   "Reservations",
+		Filter: BoundEquals{Field: "item_id", BoundVar: "bound.itemId"},
+	}}
+
+	want := `Count
+  Select Reservations
+    bind: *
+    filter:
+      BoundEquals item_id = bound.itemId
+`
+
+	assert.Equal(t, want, Explain(query))
+}
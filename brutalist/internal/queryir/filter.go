@@ -0,0 +1,308 @@
+package queryir
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+// ParseFilter parses a where-clause filter expression into a predicate.
+// It is the one filter parser for where-clause filters and when-guards:
+// the compiler validates them with it and the engine evaluates them with
+// it, so both accept the same rules.
+//
+// Grammar:
+//
+//	filter     := comparison (("&&" | "AND") comparison)*
+//	comparison := field op operand
+//	op         := "==" | ">" | "<" | ">=" | "<="
+//	operand    := "bound." name | 'string' | "string" | int | true | false
+//
+// Mapping:
+//   - field == bound.x   → BoundEquals
+//   - field == literal   → Equals
+//   - field > 5          → GreaterThan (likewise <, >=, <=)
+//   - field >= bound.min → GreaterOrEqual with BoundVar
+//   - a && b && c        → And{a, b, c} (flat, in source order)
+//
+// "&&" and "AND" inside quoted strings are part of the literal. A single
+// comparison is returned unwrapped. An empty filter returns nil.
+//
+// OR ("||") is rejected: it is outside the portable fragment. Use separate
+// sync rules or a Union query instead.
+//
+// Errors are *FilterError.
+func ParseFilter(filter string) (Predicate, error) {
+	p := &filterParser{src: filter}
+	if err := p.tokenize(); err != nil {
+		return nil, err
+	}
+	if len(p.tokens) == 0 {
+		return nil, nil
+	}
+	return p.parseFilter()
+}
+
+// FilterError reports a malformed filter expression.
+type FilterError struct {
+	Offset  int    // Byte offset of the offending token in the filter string
+	Message string // Description without position
+}
+
+func (e *FilterError) Error() string {
+	return fmt.Sprintf("%s (column %d)", e.Message, e.Offset+1)
+}
+
+// filterTokenKind categorizes filter tokens.
+type filterTokenKind int
+
+const (
+	tokIdent filterTokenKind = iota
+	tokString
+	tokInt
+	tokOp
+	tokAnd
+	tokOr
+)
+
+// filterToken is a lexed token with its byte offset in the filter string.
+type filterToken struct {
+	kind   filterTokenKind
+	text   string
+	offset int
+}
+
+// filterParser holds tokenizer and parser state for one filter expression.
+type filterParser struct {
+	src    string
+	tokens []filterToken
+	next   int
+}
+
+// errorAt builds a FilterError for a byte offset in the filter string.
+func (p *filterParser) errorAt(offset int, format string, args ...any) error {
+	return &FilterError{Offset: offset, Message: fmt.Sprintf(format, args...)}
+}
+
+// tokenize splits the filter string into tokens.
+func (p *filterParser) tokenize() error {
+	src := p.src
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(src[i+1:], c)
+			if end == -1 {
+				return p.errorAt(i, "unterminated string literal")
+			}
+			p.tokens = append(p.tokens, filterToken{tokString, src[i+1 : i+1+end], i})
+			i += end + 2
+
+		case c == '&' || c == '|':
+			if i+1 >= len(src) || src[i+1] != c {
+				return p.errorAt(i, "unexpected %q (did you mean %q?)", string(c), string([]byte{c, c}))
+			}
+			kind := tokAnd
+			if c == '|' {
+				kind = tokOr
+			}
+			p.tokens = append(p.tokens, filterToken{kind, src[i : i+2], i})
+			i += 2
+
+		case c == '=' || c == '!' || c == '<' || c == '>':
+			op := string(c)
+			if i+1 < len(src) && src[i+1] == '=' {
+				op += "="
+			}
+			p.tokens = append(p.tokens, filterToken{tokOp, op, i})
+			i += len(op)
+
+		case c == '-' || (c >= '0' && c <= '9'):
+			start := i
+			i++
+			for i < len(src) && src[i] >= '0' && src[i] <= '9' {
+				i++
+			}
+			if i < len(src) && src[i] == '.' {
+				return p.errorAt(start, "float literals are forbidden (CP-5); use integers")
+			}
+			p.tokens = append(p.tokens, filterToken{tokInt, src[start:i], start})
+
+		case isFilterIdentStart(c):
+			start := i
+			for i < len(src) && (isFilterIdentStart(src[i]) || (src[i] >= '0' && src[i] <= '9') || src[i] == '.') {
+				i++
+			}
+			word := src[start:i]
+			switch strings.ToUpper(word) {
+			case "AND":
+				p.tokens = append(p.tokens, filterToken{tokAnd, word, start})
+			case "OR":
+				p.tokens = append(p.tokens, filterToken{tokOr, word, start})
+			default:
+				p.tokens = append(p.tokens, filterToken{tokIdent, word, start})
+			}
+
+		default:
+			return p.errorAt(i, "unexpected character %q", string(c))
+		}
+	}
+	return nil
+}
+
+// isFilterIdentStart reports whether c can start an identifier.
+func isFilterIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// parseFilter parses a conjunction of comparisons.
+func (p *filterParser) parseFilter() (Predicate, error) {
+	var preds []Predicate
+	for {
+		pred, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		preds = append(preds, pred)
+
+		if p.next >= len(p.tokens) {
+			break
+		}
+		tok := p.tokens[p.next]
+		switch tok.kind {
+		case tokAnd:
+			p.next++
+			if p.next >= len(p.tokens) {
+				return nil, p.errorAt(len(p.src), "expected comparison after %q", tok.text)
+			}
+		case tokOr:
+			return nil, p.errorAt(tok.offset, "OR (%s) is not supported in filters; use separate sync rules or a Union query", tok.text)
+		default:
+			return nil, p.errorAt(tok.offset, "expected && between comparisons, got %q", tok.text)
+		}
+	}
+
+	if len(preds) == 1 {
+		return preds[0], nil
+	}
+	return And{Predicates: preds}, nil
+}
+
+// parseComparison parses "field op operand".
+func (p *filterParser) parseComparison() (Predicate, error) {
+	field, err := p.expect(tokIdent, "field name")
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(field.text, "bound.") {
+		return nil, p.errorAt(field.offset, "bound variable %q must be on the right-hand side", field.text)
+	}
+
+	op, err := p.expect(tokOp, "comparison operator")
+	if err != nil {
+		return nil, err
+	}
+	switch op.text {
+	case "==", ">", "<", ">=", "<=":
+	case "=":
+		return nil, p.errorAt(op.offset, "use == for equality")
+	default:
+		return nil, p.errorAt(op.offset, "unsupported operator %q", op.text)
+	}
+
+	if p.next >= len(p.tokens) {
+		return nil, p.errorAt(len(p.src), "expected value after %q", op.text)
+	}
+	operand := p.tokens[p.next]
+	p.next++
+
+	if op.text == "==" {
+		return p.equality(field.text, operand)
+	}
+	return p.comparison(field.text, op.text, operand)
+}
+
+// equality builds Equals or BoundEquals for "field == operand".
+func (p *filterParser) equality(field string, operand filterToken) (Predicate, error) {
+	switch operand.kind {
+	case tokIdent:
+		switch {
+		case strings.HasPrefix(operand.text, "bound."):
+			return BoundEquals{Field: field, BoundVar: operand.text}, nil
+		case operand.text == "true":
+			return Equals{Field: field, Value: ir.IRBool(true)}, nil
+		case operand.text == "false":
+			return Equals{Field: field, Value: ir.IRBool(false)}, nil
+		default:
+			return nil, p.errorAt(operand.offset, "unquoted value %q (quote string literals or use bound.%s)", operand.text, operand.text)
+		}
+	case tokString:
+		return Equals{Field: field, Value: ir.IRString(operand.text)}, nil
+	case tokInt:
+		n, err := p.parseInt(operand)
+		if err != nil {
+			return nil, err
+		}
+		return Equals{Field: field, Value: n}, nil
+	default:
+		return nil, p.errorAt(operand.offset, "expected value, got %q", operand.text)
+	}
+}
+
+// comparison builds an integer range predicate for "field op operand".
+func (p *filterParser) comparison(field, op string, operand filterToken) (Predicate, error) {
+	var value ir.IRInt
+	var boundVar string
+
+	switch {
+	case operand.kind == tokInt:
+		n, err := p.parseInt(operand)
+		if err != nil {
+			return nil, err
+		}
+		value = n
+	case operand.kind == tokIdent && strings.HasPrefix(operand.text, "bound."):
+		boundVar = operand.text
+	default:
+		return nil, p.errorAt(operand.offset, "operator %s requires an integer or bound variable, got %q", op, operand.text)
+	}
+
+	switch op {
+	case ">":
+		return GreaterThan{Field: field, Value: value, BoundVar: boundVar}, nil
+	case "<":
+		return LessThan{Field: field, Value: value, BoundVar: boundVar}, nil
+	case ">=":
+		return GreaterOrEqual{Field: field, Value: value, BoundVar: boundVar}, nil
+	default:
+		return LessOrEqual{Field: field, Value: value, BoundVar: boundVar}, nil
+	}
+}
+
+// expect consumes the next token if it has the given kind.
+func (p *filterParser) expect(kind filterTokenKind, what string) (filterToken, error) {
+	if p.next >= len(p.tokens) {
+		return filterToken{}, p.errorAt(len(p.src), "expected %s", what)
+	}
+	tok := p.tokens[p.next]
+	if tok.kind != kind {
+		return filterToken{}, p.errorAt(tok.offset, "expected %s, got %q", what, tok.text)
+	}
+	p.next++
+	return tok, nil
+}
+
+// parseInt converts an integer token to IRInt (CP-5: no floats).
+func (p *filterParser) parseInt(tok filterToken) (ir.IRInt, error) {
+	n, err := strconv.ParseInt(tok.text, 10, 64)
+	if err != nil {
+		return 0, p.errorAt(tok.offset, "invalid integer %q", tok.text)
+	}
+	return ir.IRInt(n), nil
+}
@@ -0,0 +1,104 @@
+package queryir
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+func TestParseFilter_Literals(t *testing.T) {
+	tests := []struct {
+		filter string
+		want   Predicate
+	}{
+		{`status == "active"`, Equals{Field: "status", Value: ir.IRString("active")}},
+		{`status == 'active'`, Equals{Field: "status", Value: ir.IRString("active")}},
+		{"quantity == -3", Equals{Field: "quantity", Value: ir.IRInt(-3)}},
+		{"archived == false", Equals{Field: "archived", Value: ir.IRBool(false)}},
+		{"quantity > 0", GreaterThan{Field: "quantity", Value: ir.IRInt(0)}},
+		{"quantity < 10", LessThan{Field: "quantity", Value: ir.IRInt(10)}},
+		{"quantity >= bound.min", GreaterOrEqual{Field: "quantity", BoundVar: "bound.min"}},
+		{"quantity <= 5", LessOrEqual{Field: "quantity", Value: ir.IRInt(5)}},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			pred, err := ParseFilter(tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, pred)
+		})
+	}
+}
+
+func TestParseFilter_CompoundAnd(t *testing.T) {
+	pred, err := ParseFilter(`cart_id == bound.cart_id && status == "active" AND quantity > 0`)
+
+	require.NoError(t, err)
+	assert.Equal(t, And{Predicates: []Predicate{
+		BoundEquals{Field: "cart_id", BoundVar: "bound.cart_id"},
+		Equals{Field: "status", Value: ir.IRString("active")},
+		GreaterThan{Field: "quantity", Value: ir.IRInt(0)},
+	}}, pred)
+}
+
+func TestParseFilter_OperatorsInsideStrings(t *testing.T) {
+	pred, err := ParseFilter(`note == 'a && b' && label == "x AND y"`)
+
+	require.NoError(t, err)
+	assert.Equal(t, And{Predicates: []Predicate{
+		Equals{Field: "note", Value: ir.IRString("a && b")},
+		Equals{Field: "label", Value: ir.IRString("x AND y")},
+	}}, pred)
+}
+
+func TestParseFilter_Deterministic(t *testing.T) {
+	filter := `a == 1 && b == bound.b && c >= 2`
+
+	first, err := ParseFilter(filter)
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		again, err := ParseFilter(filter)
+		require.NoError(t, err)
+		assert.Equal(t, first, again)
+	}
+}
+
+func TestParseFilter_Malformed(t *testing.T) {
+	tests := []struct {
+		filter  string
+		wantMsg string
+	}{
+		{"status = 'a'", "use == for equality"},
+		{"status != 'a'", "unsupported operator"},
+		{"status == active", "unquoted value"},
+		{"quantity > 'a'", "requires an integer"},
+		{"price == 1.5", "float literals are forbidden"},
+		{"status == 'a' &&", "expected comparison"},
+		{"status == 'open", "unterminated string"},
+		{"status 'a'", "expected comparison operator"},
+		{"a == 1 b == 2", "expected && between comparisons"},
+		{"bound.x == a", "right-hand side"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			_, err := ParseFilter(tt.filter)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantMsg)
+		})
+	}
+}
+
+func TestParseFilter_ErrorOffset(t *testing.T) {
+	_, err := ParseFilter(`status == "a" || status == "b"`)
+
+	var filterErr *FilterError
+	require.ErrorAs(t, err, &filterErr)
+	assert.Equal(t, 14, filterErr.Offset)
+	assert.Contains(t, filterErr.Message, "OR")
+	assert.EqualError(t, err, filterErr.Message+" (column 15)")
+}
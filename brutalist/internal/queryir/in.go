@@ -0,0 +1,46 @@
+package queryir
+
+import "github.com/roach88/nysm/internal/ir"
+
+// In represents a set-membership predicate.
+//
+// Semantics:
+//
+//	<field> IN (<value1>, <value2>, ..., <valueN>)
+//
+// The In predicate:
+//  1. References a field in the current query source
+//  2. Compares it against a fixed set of literal values
+//  3. Returns true if the field equals ANY value in the set
+//
+// Example:
+//
+//	In{Field: "status", Values: []ir.IRValue{
+//	  ir.IRString("pending"),
+//	  ir.IRString("held"),
+//	}}
+//
+// Translates to SQL:
+//
+//	status IN (?, ?)
+//
+// PORTABLE FRAGMENT RULES:
+//   - Values must be scalar IRValues (string, int, bool)
+//   - No NULLs and no floats (CP-5)
+//   - The set must be non-empty (an empty IN is rejected at compile time)
+//
+// SPARQL MAPPING (portable with translation):
+// SPARQL has no direct IN over literals in all engines. The predicate is
+// expressible via VALUES (or an equivalent UNION of equality patterns):
+//
+//	In{Field: "status", Values: ["pending", "held"]}
+//
+// becomes:
+//
+//	VALUES ?status { "pending" "held" }
+type In struct {
+	Field  string       // Field name in current query source
+	Values []ir.IRValue // Literal set (non-empty, no nulls)
+}
+
+func (In) predicateNode() {}
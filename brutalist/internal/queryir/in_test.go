@@ -0,0 +1,63 @@
+package queryir
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+func TestIn_ImplementsPredicate(t *testing.T) {
+	var p Predicate = In{Field: "status", Values: []ir.IRValue{ir.IRString("pending")}}
+
+	switch p.(type) {
+	case In:
+		// OK
+	default:
+		t.Fatalf("unexpected predicate type: %T", p)
+	}
+}
+
+func TestValidate_InPortable(t *testing.T) {
+	query := Select{
+		From: "orders",
+		Filter: In{Field: "status", Values: []ir.IRValue{
+			ir.IRString("pending"),
+			ir.IRString("held"),
+		}},
+		Bindings: map[string]string{"id": "orderId"},
+	}
+
+	result := Validate(query)
+
+	assert.True(t, result.IsPortable)
+	assert.Empty(t, result.Warnings)
+}
+
+func TestValidate_InEmptySet(t *testing.T) {
+	query := Select{
+		From:     "orders",
+		Filter:   &In{Field: "status"},
+		Bindings: map[string]string{"id": "orderId"},
+	}
+
+	result := Validate(query)
+
+	assert.False(t, result.IsPortable)
+	assert.Len(t, result.Warnings, 1)
+	assert.Contains(t, result.Warnings[0], "empty IN set")
+}
+
+func TestValidate_InWithNull(t *testing.T) {
+	query := Select{
+		From:     "orders",
+		Filter:   In{Field: "status", Values: []ir.IRValue{ir.IRString("held"), ir.IRNull{}}},
+		Bindings: map[string]string{"id": "orderId"},
+	}
+
+	result := Validate(query)
+
+	assert.False(t, result.IsPortable)
+	assert.Contains(t, result.Warnings[0], "NULL")
+}
@@ -0,0 +1,61 @@
+package queryir
+
+// Negate returns the predicate that matches exactly the rows with a value
+// for the field that p does not match:
+//
+//	Equals         ↔ NotEquals (literal operand)
+//	BoundEquals    ↔ NotEquals (bound operand)
+//	GreaterThan    ↔ LessOrEqual
+//	LessThan       ↔ GreaterOrEqual
+//
+// Operands are carried over unchanged. An And with a single predicate is
+// negated through to that predicate. Pointer predicates are accepted;
+// the result is always a value.
+//
+// Returns nil when the negation is outside the portable fragment: a
+// multi-predicate And (its negation is a disjunction), an empty And
+// (always true), In (NOT IN), or a nil predicate.
+func Negate(p Predicate) Predicate {
+	switch pred := p.(type) {
+	case Equals:
+		return NotEquals{Field: pred.Field, Value: pred.Value}
+	case *Equals:
+		return Negate(*pred)
+	case BoundEquals:
+		return NotEquals{Field: pred.Field, BoundVar: pred.BoundVar}
+	case *BoundEquals:
+		return Negate(*pred)
+	case NotEquals:
+		if pred.BoundVar != "" {
+			return BoundEquals{Field: pred.Field, BoundVar: pred.BoundVar}
+		}
+		return Equals{Field: pred.Field, Value: pred.Value}
+	case *NotEquals:
+		return Negate(*pred)
+	case GreaterThan:
+		return LessOrEqual(pred)
+	case *GreaterThan:
+		return Negate(*pred)
+	case LessOrEqual:
+		return GreaterThan(pred)
+	case *LessOrEqual:
+		return Negate(*pred)
+	case LessThan:
+		return GreaterOrEqual(pred)
+	case *LessThan:
+		return Negate(*pred)
+	case GreaterOrEqual:
+		return LessThan(pred)
+	case *GreaterOrEqual:
+		return Negate(*pred)
+	case And:
+		if len(pred.Predicates) == 1 {
+			return Negate(pred.Predicates[0])
+		}
+		return nil
+	case *And:
+		return Negate(*pred)
+	default:
+		return nil
+	}
+}
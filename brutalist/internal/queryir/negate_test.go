@@ -0,0 +1,111 @@
+package queryir
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+func TestNegate_Flips(t *testing.T) {
+	tests := []struct {
+		name string
+		in   Predicate
+		want Predicate
+	}{
+		{
+			"equals to not equals",
+			Equals{Field: "status", Value: ir.IRString("archived")},
+			NotEquals{Field: "status", Value: ir.IRString("archived")},
+		},
+		{
+			"not equals to equals",
+			NotEquals{Field: "status", Value: ir.IRString("archived")},
+			Equals{Field: "status", Value: ir.IRString("archived")},
+		},
+		{
+			"bound equals to bound not equals",
+			BoundEquals{Field: "cart_id", BoundVar: "bound.cartId"},
+			NotEquals{Field: "cart_id", BoundVar: "bound.cartId"},
+		},
+		{
+			"bound not equals to bound equals",
+			NotEquals{Field: "cart_id", BoundVar: "bound.cartId"},
+			BoundEquals{Field: "cart_id", BoundVar: "bound.cartId"},
+		},
+		{
+			"greater than to less or equal",
+			GreaterThan{Field: "quantity", Value: ir.IRInt(5)},
+			LessOrEqual{Field: "quantity", Value: ir.IRInt(5)},
+		},
+		{
+			"less or equal to greater than",
+			LessOrEqual{Field: "quantity", BoundVar: "bound.max"},
+			GreaterThan{Field: "quantity", BoundVar: "bound.max"},
+		},
+		{
+			"less than to greater or equal",
+			LessThan{Field: "quantity", Value: ir.IRInt(5)},
+			GreaterOrEqual{Field: "quantity", Value: ir.IRInt(5)},
+		},
+		{
+			"greater or equal to less than",
+			GreaterOrEqual{Field: "quantity", BoundVar: "bound.min"},
+			LessThan{Field: "quantity", BoundVar: "bound.min"},
+		},
+		{
+			"pointer yields value",
+			&GreaterThan{Field: "quantity", Value: ir.IRInt(5)},
+			LessOrEqual{Field: "quantity", Value: ir.IRInt(5)},
+		},
+		{
+			"single-predicate and",
+			And{Predicates: []Predicate{&Equals{Field: "status", Value: ir.IRString("open")}}},
+			NotEquals{Field: "status", Value: ir.IRString("open")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Negate(tt.in))
+		})
+	}
+}
+
+func TestNegate_OutsidePortableFragment(t *testing.T) {
+	tests := []struct {
+		name string
+		in   Predicate
+	}{
+		{"nil", nil},
+		{"empty and", And{}},
+		{"multi-predicate and", And{Predicates: []Predicate{
+			Equals{Field: "a", Value: ir.IRInt(1)},
+			Equals{Field: "b", Value: ir.IRInt(2)},
+		}}},
+		{"in", In{Field: "status", Values: []ir.IRValue{ir.IRString("open")}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Nil(t, Negate(tt.in))
+		})
+	}
+}
+
+func TestNegate_RoundTrip(t *testing.T) {
+	predicates := []Predicate{
+		Equals{Field: "status", Value: ir.IRString("archived")},
+		NotEquals{Field: "status", Value: ir.IRBool(true)},
+		BoundEquals{Field: "cart_id", BoundVar: "bound.cartId"},
+		GreaterThan{Field: "quantity", Value: ir.IRInt(5)},
+		LessThan{Field: "quantity", Value: ir.IRInt(5)},
+		GreaterOrEqual{Field: "quantity", BoundVar: "bound.min"},
+		LessOrEqual{Field: "quantity", BoundVar: "bound.max"},
+	}
+
+	for _, p := range predicates {
+		assert.Equal(t, p, Negate(Negate(p)), "%T", p)
+	}
+}
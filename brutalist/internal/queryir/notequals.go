@@ -0,0 +1,47 @@
+package queryir
+
+import "github.com/roach88/nysm/internal/ir"
+
+// NotEquals represents a field-not-equals predicate.
+//
+// Semantics:
+//
+//	<field> != <value>          (Value literal)
+//	<field> != <bound_variable> (when BoundVar is set)
+//
+// The NotEquals predicate:
+//  1. References a field in the current query source
+//  2. Compares it against an IRValue literal (Value) or a when-clause
+//     variable (BoundVar, e.g. "bound.status")
+//  3. When BoundVar is non-empty it takes precedence over Value
+//  4. Only matches rows where the field has a value: a missing (NULL)
+//     field is neither equal nor unequal to anything
+//
+// Example:
+//
+//	NotEquals{Field: "status", Value: ir.IRString("archived")}
+//
+// Translates to SQL:
+//
+//	status <> ?
+//
+// PORTABLE FRAGMENT RULES:
+//   - Value must be a non-null IRValue (no floats per CP-5)
+//   - Bound variables must resolve to a present value at execution time
+//   - Rows without the field never match (SQL and SPARQL agree: comparing
+//     with NULL or an unbound variable is not true)
+//
+// SPARQL MAPPING:
+//
+//	NotEquals{Field: "status", Value: ir.IRString("archived")}
+//
+// becomes:
+//
+//	FILTER(?status != "archived")
+type NotEquals struct {
+	Field    string     // Field name in current query source
+	Value    ir.IRValue // Literal operand (used when BoundVar is empty)
+	BoundVar string     // Optional bound variable operand (e.g., "bound.status")
+}
+
+func (NotEquals) predicateNode() {}
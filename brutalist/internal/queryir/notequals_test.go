@@ -0,0 +1,92 @@
+package queryir
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+func TestNotEquals_ImplementsPredicate(t *testing.T) {
+	var p Predicate = NotEquals{Field: "status", Value: ir.IRString("archived")}
+
+	switch p.(type) {
+	case NotEquals:
+		// OK
+	default:
+		t.Fatalf("unexpected predicate type: %T", p)
+	}
+}
+
+func TestValidate_NotEqualsPortable(t *testing.T) {
+	query := Select{
+		From: "orders",
+		Filter: And{Predicates: []Predicate{
+			NotEquals{Field: "status", Value: ir.IRString("archived")},
+			&NotEquals{Field: "owner", BoundVar: "bound.user"},
+		}},
+		Bindings: map[string]string{"id": "orderId"},
+	}
+
+	result := Validate(query)
+
+	assert.True(t, result.IsPortable)
+	assert.Empty(t, result.Warnings)
+}
+
+func TestValidate_NotEqualsNull(t *testing.T) {
+	query := Select{
+		From:     "orders",
+		Filter:   NotEquals{Field: "status", Value: ir.IRNull{}},
+		Bindings: map[string]string{"id": "orderId"},
+	}
+
+	result := Validate(query)
+
+	assert.False(t, result.IsPortable)
+	require.Len(t, result.Warnings, 1)
+	assert.Contains(t, result.Warnings[0], "NULL")
+}
+
+func TestValidateSchema_NotEquals(t *testing.T) {
+	query := Select{
+		From: "CartItem",
+		Filter: And{Predicates: []Predicate{
+			NotEquals{Field: "quantity", Value: ir.IRString("three")},
+			NotEquals{Field: "color", BoundVar: "bound.color"},
+			NotEquals{Field: "item_id", Value: ir.IRString("widget")},
+		}},
+		Bindings: map[string]string{"item_id": "itemId"},
+	}
+
+	errs := ValidateSchema(query, testSchemaSpecs())
+
+	require.Len(t, errs, 2)
+	assert.Equal(t, "CartItem.quantity", errs[0].Field)
+	assert.Contains(t, errs[0].Message, "string predicate on int field")
+	assert.Equal(t, "color", errs[1].Field)
+	assert.Contains(t, errs[1].Message, "does not exist")
+}
+
+func TestExplain_NotEquals(t *testing.T) {
+	query := Select{
+		From: "Orders",
+		Filter: And{Predicates: []Predicate{
+			NotEquals{Field: "status", Value: ir.IRString("archived")},
+			NotEquals{Field: "owner", BoundVar: "bound.user"},
+		}},
+		Bindings: map[string]string{"id": "orderId"},
+	}
+
+	want := `Select Orders
+  bind: id -> orderId
+  filter:
+    And
+      NotEquals owner != bound.user
+      NotEquals status != "archived"
+`
+
+	assert.Equal(t, want, Explain(query))
+}
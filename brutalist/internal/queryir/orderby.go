@@ -0,0 +1,88 @@
+package queryir
+
+// OrderBy orders the results of a child query by one or more of its bindings.
+//
+// Semantics:
+//
+//	SELECT ... FROM (<query>) ORDER BY <field> <direction>, ...
+//
+// The OrderBy query:
+//  1. Executes the child query to produce its bindings
+//  2. Orders rows by Keys in sequence (the first key is most significant)
+//  3. Breaks remaining ties with the mandatory seq, id order (CP-4), so the
+//     result is still fully deterministic
+//  4. Produces the same bindings as the child
+//
+// A Limit on a child Select applies to the ordered rows, so OrderBy over a
+// limited Select yields the top rows by key.
+//
+// Example:
+//
+//	OrderBy{
+//	  Query: Select{From: "CartItems", Bindings: map[string]string{"item_id": "itemId", "quantity": "qty"}},
+//	  Keys:  []OrderKey{{Field: "qty", Direction: Descending}},
+//	}
+//
+// Translates to SQL:
+//
+//	SELECT item_id AS itemId, quantity AS qty FROM CartItems ORDER BY qty DESC, seq ASC, id COLLATE BINARY ASC
+//
+// PORTABLE FRAGMENT RULES:
+//   - Keys must be non-empty
+//   - Every key Field must be a binding produced by the child query
+//   - Direction must be Ascending, Descending, or empty (ascending)
+//
+// SPARQL MAPPING:
+//
+//	OrderBy{Keys: [{Field: "qty", Direction: Descending}]}
+//
+// becomes:
+//
+//	ORDER BY DESC(?qty)
+type OrderBy struct {
+	Query Query      // Child query producing the bindings to order
+	Keys  []OrderKey // Ordering keys, most significant first
+}
+
+func (OrderBy) queryNode() {}
+
+// OrderKey is one ordering key of an OrderBy.
+type OrderKey struct {
+	Field     string        // Binding produced by the child query
+	Direction SortDirection // Empty means Ascending
+}
+
+// SortDirection is the direction of an OrderKey.
+type SortDirection string
+
+const (
+	Ascending  SortDirection = "asc"
+	Descending SortDirection = "desc"
+)
+
+// Valid reports whether d is Ascending, Descending, or empty.
+func (d SortDirection) Valid() bool {
+	return d == "" || d == Ascending || d == Descending
+}
+
+// unboundFields returns the key fields (in key order) that are not bindings
+// of the child query. Returns false when the child's bindings cannot be
+// determined statically.
+func (o OrderBy) unboundFields() ([]string, bool) {
+	vars, ok := outputVars(o.Query)
+	if !ok {
+		return nil, false
+	}
+	bound := make(map[string]bool, len(vars))
+	for _, v := range vars {
+		bound[v] = true
+	}
+
+	var missing []string
+	for _, key := range o.Keys {
+		if !bound[key.Field] {
+			missing = append(missing, key.Field)
+		}
+	}
+	return missing, true
+}
@@ -0,0 +1,100 @@
+package queryir
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testOrderBy() OrderBy {
+	return OrderBy{
+		Query: Select{
+			From:     "CartItem",
+			Filter:   BoundEquals{Field: "cart_id", BoundVar: "bound.cartId"},
+			Bindings: map[string]string{"item_id": "itemId", "quantity": "qty"},
+		},
+		Keys: []OrderKey{
+			{Field: "qty", Direction: Descending},
+			{Field: "itemId"},
+		},
+	}
+}
+
+func TestOrderBy_ImplementsQuery(t *testing.T) {
+	var q Query = testOrderBy()
+
+	switch q.(type) {
+	case OrderBy:
+		// OK
+	default:
+		t.Fatalf("unexpected query type: %T", q)
+	}
+}
+
+func TestSortDirection_Valid(t *testing.T) {
+	assert.True(t, SortDirection("").Valid())
+	assert.True(t, Ascending.Valid())
+	assert.True(t, Descending.Valid())
+	assert.False(t, SortDirection("DESC").Valid())
+}
+
+func TestValidate_OrderByPortable(t *testing.T) {
+	result := Validate(testOrderBy())
+
+	assert.True(t, result.IsPortable)
+	assert.Empty(t, result.Warnings)
+}
+
+func TestValidate_OrderByInvalidKeys(t *testing.T) {
+	order := testOrderBy()
+	order.Keys = []OrderKey{
+		{Field: "quantity"},
+		{Field: "qty", Direction: "sideways"},
+	}
+
+	result := Validate(&order)
+
+	assert.False(t, result.IsPortable)
+	require.Len(t, result.Warnings, 2)
+	assert.Contains(t, result.Warnings[0], "'qty' has unknown direction")
+	assert.Contains(t, result.Warnings[1], "'quantity' not bound by the child query")
+
+	result = Validate(OrderBy{Query: testOrderBy().Query})
+	require.Len(t, result.Warnings, 1)
+	assert.Contains(t, result.Warnings[0], "OrderBy without keys")
+}
+
+func TestValidateSchema_OrderBy(t *testing.T) {
+	assert.Empty(t, ValidateSchema(testOrderBy(), testSchemaSpecs()))
+
+	order := testOrderBy()
+	order.Keys = append(order.Keys, OrderKey{Field: "cart_id"})
+
+	errs := ValidateSchema(order, testSchemaSpecs())
+	require.Len(t, errs, 1)
+	assert.Equal(t, "order_by.cart_id", errs[0].Field)
+	assert.Contains(t, errs[0].Message, `key field "cart_id" is not bound by the child query`)
+}
+
+func TestOrderBy_OutputVars(t *testing.T) {
+	vars, ok := outputVars(&OrderBy{Query: testProjection()})
+	require.True(t, ok)
+	assert.Equal(t, []string{"sku", "source"}, vars)
+}
+
+func TestExplain_OrderBy(t *testing.T) {
+	got := Explain(testOrderBy())
+
+	want := `OrderBy
+  key: qty desc
+  key: itemId asc
+  of:
+    Select CartItem
+      bind: item_id -> itemId
+      bind: quantity -> qty
+      filter:
+        BoundEquals cart_id = bound.cartId
+`
+	assert.Equal(t, want, got)
+}
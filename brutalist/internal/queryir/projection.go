@@ -0,0 +1,142 @@
+package queryir
+
+import (
+	"sort"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+// Projection computes the output bindings of a child query.
+//
+// Semantics:
+//
+//	SELECT <source> AS <output>, ... FROM (<query>)
+//
+// The Projection query:
+//  1. Executes the child query to produce its bindings
+//  2. Emits exactly the bindings named in Outputs (child bindings not
+//     listed are dropped)
+//  3. Each output is either a child binding under a new name (rename) or
+//     a literal IRValue (constant)
+//
+// Projection lets a where-clause rename a field or bind a constant without
+// an extra join, while keeping bindings explicit (no SELECT *).
+//
+// Example:
+//
+//	Projection{
+//	  Query: Select{From: "CartItems", Bindings: map[string]string{"item_id": "itemId"}},
+//	  Outputs: map[string]ProjectionSource{
+//	    "sku":    {Field: "itemId"},
+//	    "source": {Value: ir.IRString("cart")},
+//	  },
+//	}
+//
+// Translates to SQL:
+//
+//	SELECT ? AS source, item_id AS sku FROM CartItems ORDER BY seq ASC, id COLLATE BINARY ASC
+//
+// Produces bindings: {"sku": <item_id>, "source": "cart"}
+//
+// PORTABLE FRAGMENT RULES:
+//   - Outputs must be non-empty (explicit bindings)
+//   - Every source Field must be a binding produced by the child query
+//   - Constants must be non-null IRValues (no floats per CP-5)
+//
+// SPARQL MAPPING:
+//
+//	Projection{Outputs: {"sku": {Field: "itemId"}, "source": {Value: "cart"}}}
+//
+// becomes:
+//
+//	BIND(?itemId AS ?sku)
+//	BIND("cart" AS ?source)
+type Projection struct {
+	Query   Query                       // Child query producing the source bindings
+	Outputs map[string]ProjectionSource // output binding name → source
+}
+
+func (Projection) queryNode() {}
+
+// ProjectionSource is the source of one Projection output.
+// When Field is non-empty it takes precedence over Value.
+type ProjectionSource struct {
+	Field string     // Binding produced by the child query (rename)
+	Value ir.IRValue // Literal value (used when Field is empty)
+}
+
+// unboundSources returns the output names (sorted) whose source Field is
+// not a binding of the child query. Returns false when the child's bindings
+// cannot be determined statically.
+func (p Projection) unboundSources() ([]string, bool) {
+	vars, ok := outputVars(p.Query)
+	if !ok {
+		return nil, false
+	}
+	bound := make(map[string]bool, len(vars))
+	for _, v := range vars {
+		bound[v] = true
+	}
+
+	var missing []string
+	for _, name := range sortedKeys(p.Outputs) {
+		if field := p.Outputs[name].Field; field != "" && !bound[field] {
+			missing = append(missing, name)
+		}
+	}
+	return missing, true
+}
+
+// outputVars returns the sorted variable names a query binds.
+// Returns false when the bindings cannot be determined statically
+// (nil or unknown query types, or a branch that cannot be determined).
+func outputVars(q Query) ([]string, bool) {
+	switch query := q.(type) {
+	case Select:
+		return boundVarsOf(query.Bindings), true
+	case *Select:
+		return boundVarsOf(query.Bindings), true
+	case Join:
+		return joinOutputVars(query)
+	case *Join:
+		return joinOutputVars(*query)
+	case Union:
+		return outputVars(query.Left)
+	case *Union:
+		return outputVars(query.Left)
+	case Count, *Count:
+		return []string{CountBinding}, true
+	case Projection:
+		return sortedKeys(query.Outputs), true
+	case *Projection:
+		return sortedKeys(query.Outputs), true
+	case OrderBy:
+		return outputVars(query.Query)
+	case *OrderBy:
+		return outputVars(query.Query)
+	default:
+		return nil, false
+	}
+}
+
+// joinOutputVars returns the combined bindings of both join sides.
+func joinOutputVars(join Join) ([]string, bool) {
+	left, leftOK := outputVars(join.Left)
+	right, rightOK := outputVars(join.Right)
+	if !leftOK || !rightOK {
+		return nil, false
+	}
+	vars := append(left, right...)
+	sort.Strings(vars)
+	return vars, true
+}
+
+// boundVarsOf returns the bound variable names of a bindings map, sorted.
+func boundVarsOf(bindings map[string]string) []string {
+	vars := make([]string, 0, len(bindings))
+	for _, boundVar := range bindings {
+		vars = append(vars, boundVar)
+	}
+	sort.Strings(vars)
+	return vars
+}
@@ -0,0 +1,116 @@
+package queryir
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+func testProjection() Projection {
+	return Projection{
+		Query: Select{
+			From:     "CartItem",
+			Filter:   BoundEquals{Field: "cart_id", BoundVar: "bound.cartId"},
+			Bindings: map[string]string{"item_id": "itemId"},
+		},
+		Outputs: map[string]ProjectionSource{
+			"sku":    {Field: "itemId"},
+			"source": {Value: ir.IRString("cart")},
+		},
+	}
+}
+
+func TestProjection_ImplementsQuery(t *testing.T) {
+	var q Query = testProjection()
+
+	switch q.(type) {
+	case Projection:
+		// OK
+	default:
+		t.Fatalf("unexpected query type: %T", q)
+	}
+}
+
+func TestValidate_ProjectionPortable(t *testing.T) {
+	result := Validate(testProjection())
+
+	assert.True(t, result.IsPortable)
+	assert.Empty(t, result.Warnings)
+}
+
+func TestValidate_ProjectionUnboundField(t *testing.T) {
+	proj := testProjection()
+	proj.Outputs["qty"] = ProjectionSource{Field: "quantity"}
+
+	result := Validate(&proj)
+
+	assert.False(t, result.IsPortable)
+	require.Len(t, result.Warnings, 1)
+	assert.Contains(t, result.Warnings[0], "projects field 'quantity' not bound by the child query")
+}
+
+func TestValidate_ProjectionInvalidOutputs(t *testing.T) {
+	proj := Projection{
+		Query: testProjection().Query,
+		Outputs: map[string]ProjectionSource{
+			"empty": {},
+			"null":  {Value: ir.IRNull{}},
+		},
+	}
+
+	result := Validate(proj)
+
+	require.Len(t, result.Warnings, 2)
+	assert.Contains(t, result.Warnings[0], "'empty' has neither a source field nor a value")
+	assert.Contains(t, result.Warnings[1], "'null' binds NULL")
+
+	result = Validate(Projection{Query: testProjection().Query})
+	require.Len(t, result.Warnings, 1)
+	assert.Contains(t, result.Warnings[0], "Empty projection outputs")
+}
+
+func TestValidateSchema_Projection(t *testing.T) {
+	assert.Empty(t, ValidateSchema(testProjection(), testSchemaSpecs()))
+
+	proj := testProjection()
+	proj.Outputs["qty"] = ProjectionSource{Field: "quantity"}
+
+	errs := ValidateSchema(proj, testSchemaSpecs())
+	require.Len(t, errs, 1)
+	assert.Equal(t, "projection.qty", errs[0].Field)
+	assert.Contains(t, errs[0].Message, `source field "quantity" is not bound by the child query`)
+}
+
+func TestValidateSchema_ProjectionOverJoin(t *testing.T) {
+	proj := Projection{
+		Query: Join{
+			Left:  Select{From: "CartItem", Bindings: map[string]string{"item_id": "itemId"}},
+			Right: Select{From: "Stock", Bindings: map[string]string{"available": "stock"}},
+			On:    Equals{Field: "Stock.available", Value: ir.IRInt(1)},
+		},
+		Outputs: map[string]ProjectionSource{
+			"sku":   {Field: "itemId"},
+			"count": {Field: "stock"},
+		},
+	}
+
+	assert.Empty(t, ValidateSchema(proj, testSchemaSpecs()))
+}
+
+func TestExplain_Projection(t *testing.T) {
+	got := Explain(testProjection())
+
+	want := `Projection
+  out: sku <- itemId
+  out: source = "cart"
+  of:
+    Select CartItem
+      bind: item_id -> itemId
+      filter:
+        BoundEquals cart_id = bound.cartId
+`
+	assert.Equal(t, want, got)
+}
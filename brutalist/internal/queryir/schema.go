@@ -0,0 +1,315 @@
+package queryir
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+// implicitColumns are engine-managed columns present on every state table.
+// They are not declared in StateSchema but may be filtered and bound.
+var implicitColumns = map[string]string{
+	"id":  "string",
+	"seq": "int",
+}
+
+// ValidateSchema checks a query against the state schemas declared by concepts.
+//
+// Where-clauses reference state tables by name. Without this check, a typo
+// in a source or field only surfaces as a SQL error at execution time.
+// ValidateSchema lets the compiler reject bad where-clauses at build time;
+// compiler.Validate reports its errors as E119 (unknown source) and E120
+// (unknown or mistyped field).
+//
+// Checks performed:
+//   - Every Select source matches a StateSchema name in some concept
+//   - Every bound field exists in that schema
+//   - Every predicate field exists in a schema in scope
+//   - Literal operands match the field type (IRInt on int, IRString on string)
+//   - Comparison predicates only apply to int fields
+//
+// Join ON predicates may reference either side; fields can be qualified as
+// "Source.field" to disambiguate. BoundEquals operands are only known at
+// runtime, so only the field is checked.
+//
+// This is distinct from Validate, which checks portability rather than
+// schema conformance. Returns nil if the query is valid.
+func ValidateSchema(q Query, specs []ir.ConceptSpec) []ir.ValidationError {
+	c := &schemaChecker{
+		states: make(map[string]ir.StateSchema),
+	}
+	for _, spec := range specs {
+		for _, state := range spec.StateSchema {
+			if _, exists := c.states[state.Name]; !exists {
+				c.states[state.Name] = state
+			}
+		}
+	}
+
+	c.checkQuery(q)
+	return c.errs
+}
+
+// schemaChecker accumulates errors during traversal.
+type schemaChecker struct {
+	states map[string]ir.StateSchema
+	errs   []ir.ValidationError
+}
+
+// addError appends a validation error.
+func (c *schemaChecker) addError(field, format string, args ...any) {
+	c.errs = append(c.errs, ir.ValidationError{
+		Field:   field,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// checkQuery validates a query node and returns the schemas it brings into
+// scope (used to resolve Join ON fields). Unknown sources contribute nothing.
+func (c *schemaChecker) checkQuery(q Query) []ir.StateSchema {
+	switch query := q.(type) {
+	case Select:
+		return c.checkSelect(query)
+	case *Select:
+		return c.checkSelect(*query)
+	case Join:
+		return c.checkJoin(query)
+	case *Join:
+		return c.checkJoin(*query)
+	case Union:
+		c.checkQuery(query.Left)
+		c.checkQuery(query.Right)
+		return nil
+	case *Union:
+		c.checkQuery(query.Left)
+		c.checkQuery(query.Right)
+		return nil
+	case Count:
+		c.checkSelect(query.Select)
+		return nil
+	case *Count:
+		c.checkSelect(query.Select)
+		return nil
+	case Projection:
+		return c.checkProjection(query)
+	case *Projection:
+		return c.checkProjection(*query)
+	case OrderBy:
+		return c.checkOrderBy(query)
+	case *OrderBy:
+		return c.checkOrderBy(*query)
+	default:
+		c.addError("query", "unsupported query type: %T", q)
+		return nil
+	}
+}
+
+// checkSelect validates a Select against its source schema.
+func (c *schemaChecker) checkSelect(sel Select) []ir.StateSchema {
+	state, ok := c.states[sel.From]
+	if !ok {
+		c.addError("from", "unknown state source %q", sel.From)
+		return nil
+	}
+
+	scope := []ir.StateSchema{state}
+	for _, sourceField := range sortedKeys(sel.Bindings) {
+		if _, _, ok := c.resolveField(sourceField, scope); !ok {
+			c.addError(state.Name+"."+sourceField, "bound field does not exist in state %q", state.Name)
+		}
+	}
+
+	if sel.Filter != nil {
+		c.checkPredicate(sel.Filter, scope)
+	}
+
+	return scope
+}
+
+// checkJoin validates both sides and the ON predicate over their union scope.
+func (c *schemaChecker) checkJoin(join Join) []ir.StateSchema {
+	scope := append(c.checkQuery(join.Left), c.checkQuery(join.Right)...)
+	if join.On != nil && len(scope) > 0 {
+		c.checkPredicate(join.On, scope)
+	}
+	return scope
+}
+
+// checkProjection validates the child query and that every renamed output
+// refers to a binding the child produces.
+func (c *schemaChecker) checkProjection(proj Projection) []ir.StateSchema {
+	scope := c.checkQuery(proj.Query)
+	missing, _ := proj.unboundSources()
+	for _, name := range missing {
+		c.addError("projection."+name, "source field %q is not bound by the child query", proj.Outputs[name].Field)
+	}
+	return scope
+}
+
+// checkOrderBy validates the child query and that every key refers to a
+// binding the child produces.
+func (c *schemaChecker) checkOrderBy(order OrderBy) []ir.StateSchema {
+	scope := c.checkQuery(order.Query)
+	missing, _ := order.unboundFields()
+	for _, field := range missing {
+		c.addError("order_by."+field, "key field %q is not bound by the child query", field)
+	}
+	return scope
+}
+
+// checkPredicate validates field references and operand types.
+func (c *schemaChecker) checkPredicate(p Predicate, scope []ir.StateSchema) {
+	switch pred := p.(type) {
+	case Equals:
+		c.checkLiteral(pred.Field, pred.Value, scope)
+	case *Equals:
+		c.checkLiteral(pred.Field, pred.Value, scope)
+	case BoundEquals:
+		c.checkField(pred.Field, scope)
+	case *BoundEquals:
+		c.checkField(pred.Field, scope)
+	case NotEquals:
+		if pred.BoundVar != "" {
+			c.checkField(pred.Field, scope)
+		} else {
+			c.checkLiteral(pred.Field, pred.Value, scope)
+		}
+	case *NotEquals:
+		c.checkPredicate(*pred, scope)
+	case In:
+		for _, v := range pred.Values {
+			c.checkLiteral(pred.Field, v, scope)
+		}
+	case *In:
+		c.checkPredicate(*pred, scope)
+	case GreaterThan:
+		c.checkIntField(pred.Field, scope)
+	case *GreaterThan:
+		c.checkIntField(pred.Field, scope)
+	case LessThan:
+		c.checkIntField(pred.Field, scope)
+	case *LessThan:
+		c.checkIntField(pred.Field, scope)
+	case GreaterOrEqual:
+		c.checkIntField(pred.Field, scope)
+	case *GreaterOrEqual:
+		c.checkIntField(pred.Field, scope)
+	case LessOrEqual:
+		c.checkIntField(pred.Field, scope)
+	case *LessOrEqual:
+		c.checkIntField(pred.Field, scope)
+	case And:
+		for _, sub := range pred.Predicates {
+			c.checkPredicate(sub, scope)
+		}
+	case *And:
+		c.checkPredicate(*pred, scope)
+	default:
+		c.addError("filter", "unsupported predicate type: %T", p)
+	}
+}
+
+// checkField reports an error if the field is not in scope.
+// Returns the owning schema name and field type when found.
+func (c *schemaChecker) checkField(field string, scope []ir.StateSchema) (string, string, bool) {
+	owner, fieldType, ok := c.resolveField(field, scope)
+	if !ok {
+		c.addError(field, "field does not exist in %s", scopeNames(scope))
+	}
+	return owner, fieldType, ok
+}
+
+// checkLiteral validates that a literal operand matches the field type.
+func (c *schemaChecker) checkLiteral(field string, value ir.IRValue, scope []ir.StateSchema) {
+	owner, fieldType, ok := c.checkField(field, scope)
+	if !ok {
+		return
+	}
+
+	valueType := irValueTypeName(value)
+	if valueType == "" {
+		return // NULL - portability concern, reported by Validate
+	}
+	if valueType != fieldType {
+		c.addError(owner+"."+unqualified(field), "type mismatch: %s predicate on %s field", valueType, fieldType)
+	}
+}
+
+// checkIntField validates that a comparison applies to an int field.
+func (c *schemaChecker) checkIntField(field string, scope []ir.StateSchema) {
+	owner, fieldType, ok := c.checkField(field, scope)
+	if ok && fieldType != "int" {
+		c.addError(owner+"."+unqualified(field), "type mismatch: int comparison on %s field", fieldType)
+	}
+}
+
+// resolveField finds a field in scope. Qualified names ("Source.field")
+// only match the named source; unqualified names match the first schema
+// in scope that declares the field.
+func (c *schemaChecker) resolveField(field string, scope []ir.StateSchema) (string, string, bool) {
+	source, name, qualified := strings.Cut(field, ".")
+	if !qualified {
+		source, name = "", field
+	}
+
+	for _, state := range scope {
+		if qualified && state.Name != source {
+			continue
+		}
+		if t, ok := state.Fields[name]; ok {
+			return state.Name, t, true
+		}
+		if t, ok := implicitColumns[name]; ok {
+			return state.Name, t, true
+		}
+	}
+	return "", "", false
+}
+
+// sortedKeys returns map keys in sorted order for deterministic errors.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// scopeNames formats the schema names in scope for error messages.
+func scopeNames(scope []ir.StateSchema) string {
+	names := make([]string, len(scope))
+	for i, state := range scope {
+		names[i] = fmt.Sprintf("%q", state.Name)
+	}
+	return "state " + strings.Join(names, ", ")
+}
+
+// unqualified strips a "Source." prefix from a field reference.
+func unqualified(field string) string {
+	if _, name, ok := strings.Cut(field, "."); ok {
+		return name
+	}
+	return field
+}
+
+// irValueTypeName returns the schema type name for an IRValue.
+// Returns "" for IRNull.
+func irValueTypeName(v ir.IRValue) string {
+	switch v.(type) {
+	case ir.IRString:
+		return "string"
+	case ir.IRInt:
+		return "int"
+	case ir.IRBool:
+		return "bool"
+	case ir.IRArray:
+		return "array"
+	case ir.IRObject:
+		return "object"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,120 @@
+package queryir
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+func testSchemaSpecs() []ir.ConceptSpec {
+	return []ir.ConceptSpec{
+		{
+			Name: "Cart",
+			StateSchema: []ir.StateSchema{{
+				Name: "CartItem",
+				Fields: map[string]string{
+					"cart_id":  "string",
+					"item_id":  "string",
+					"quantity": "int",
+				},
+			}},
+		},
+		{
+			Name: "Inventory",
+			StateSchema: []ir.StateSchema{{
+				Name: "Stock",
+				Fields: map[string]string{
+					"item_id":   "string",
+					"available": "int",
+				},
+			}},
+		},
+	}
+}
+
+func TestValidateSchema_Valid(t *testing.T) {
+	query := Join{
+		Left: Select{
+			From: "CartItem",
+			Filter: And{Predicates: []Predicate{
+				BoundEquals{Field: "cart_id", BoundVar: "bound.cartId"},
+				GreaterThan{Field: "quantity", Value: ir.IRInt(0)},
+			}},
+			Bindings: map[string]string{"item_id": "itemId", "seq": "seq"},
+		},
+		Right: Select{
+			From:     "Stock",
+			Filter:   In{Field: "item_id", Values: []ir.IRValue{ir.IRString("a"), ir.IRString("b")}},
+			Bindings: map[string]string{"available": "available"},
+		},
+		On: Equals{Field: "Stock.available", Value: ir.IRInt(5)},
+	}
+
+	errs := ValidateSchema(query, testSchemaSpecs())
+
+	assert.Empty(t, errs)
+}
+
+func TestValidateSchema_UnknownSource(t *testing.T) {
+	query := Select{
+		From:     "CartItems",
+		Bindings: map[string]string{"item_id": "itemId"},
+	}
+
+	errs := ValidateSchema(query, testSchemaSpecs())
+
+	require.Len(t, errs, 1)
+	assert.Equal(t, "from", errs[0].Field)
+	assert.Contains(t, errs[0].Message, `unknown state source "CartItems"`)
+}
+
+func TestValidateSchema_UnknownField(t *testing.T) {
+	query := &Select{
+		From:     "CartItem",
+		Filter:   Equals{Field: "status", Value: ir.IRString("active")},
+		Bindings: map[string]string{"sku": "sku"},
+	}
+
+	errs := ValidateSchema(query, testSchemaSpecs())
+
+	require.Len(t, errs, 2)
+	assert.Equal(t, "CartItem.sku", errs[0].Field)
+	assert.Contains(t, errs[0].Message, "bound field does not exist")
+	assert.Equal(t, "status", errs[1].Field)
+	assert.Contains(t, errs[1].Message, "field does not exist")
+}
+
+func TestValidateSchema_TypeMismatch(t *testing.T) {
+	query := Select{
+		From: "CartItem",
+		Filter: And{Predicates: []Predicate{
+			Equals{Field: "quantity", Value: ir.IRString("three")},
+			GreaterOrEqual{Field: "item_id", Value: ir.IRInt(1)},
+		}},
+		Bindings: map[string]string{"item_id": "itemId"},
+	}
+
+	errs := ValidateSchema(query, testSchemaSpecs())
+
+	require.Len(t, errs, 2)
+	assert.Equal(t, "CartItem.quantity", errs[0].Field)
+	assert.Contains(t, errs[0].Message, "string predicate on int field")
+	assert.Equal(t, "CartItem.item_id", errs[1].Field)
+	assert.Contains(t, errs[1].Message, "int comparison on string field")
+}
+
+func TestValidateSchema_QualifiedFieldWrongSource(t *testing.T) {
+	query := Join{
+		Left:  Select{From: "CartItem", Bindings: map[string]string{"item_id": "itemId"}},
+		Right: Select{From: "Stock", Bindings: map[string]string{"available": "available"}},
+		On:    Equals{Field: "Stock.quantity", Value: ir.IRInt(1)},
+	}
+
+	errs := ValidateSchema(query, testSchemaSpecs())
+
+	require.Len(t, errs, 1)
+	assert.Equal(t, "Stock.quantity", errs[0].Field)
+}
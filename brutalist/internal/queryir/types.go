@@ -11,6 +11,10 @@ import "github.com/roach88/nysm/internal/ir"
 // Query types:
 //   - Select: Basic table access with filtering and field bindings
 //   - Join: Combine two queries with inner join
+//   - Union: Combine two queries with identical output bindings (OR)
+//   - Count: Row count of a Select (backend-specific, not portable)
+//   - Projection: Renamed or constant output bindings over a child query
+//   - OrderBy: Explicit result ordering over a child query
 //
 // All queries produce a set of bindings (variable name → value mappings)
 // that can be used in sync rule then-clauses.
@@ -26,6 +30,10 @@ type Query interface {
 // Predicate types:
 //   - Equals: field = literal_value
 //   - BoundEquals: field = bound_variable (from when-clause)
+//   - NotEquals: field != literal_value or bound_variable (see notequals.go)
+//   - GreaterThan, LessThan, GreaterOrEqual, LessOrEqual: integer range
+//     comparisons against a literal or bound variable (see compare.go)
+//   - In: field IN (literal set)
 //   - And: all predicates must be true
 //
 // The portable fragment excludes OR predicates and subqueries.
@@ -71,10 +79,18 @@ type Predicate interface {
 //   - Filter must use portable predicates only (no SQL functions)
 //   - Bindings must be explicit (no SELECT *)
 //   - No NULLs in results (fields with NULL are excluded from bindings)
+//
+// LIMIT:
+// Limit bounds per-firing fan-out: a where-clause matching thousands of rows
+// would otherwise generate thousands of invocations. Zero means unlimited.
+// Limit is applied after the mandatory deterministic ORDER BY (CP-4), so it
+// always selects the same stable prefix and replay stays reproducible.
+// SPARQL supports LIMIT with ORDER BY, so Limit is portable.
 type Select struct {
 	From     string            // Table/source name (e.g., "CartItems")
 	Filter   Predicate         // WHERE conditions (nil = no filter)
 	Bindings map[string]string // source_field → bound_variable
+	Limit    int               // Max rows after ordering (0 = unlimited)
 }
 
 func (Select) queryNode() {}
@@ -122,6 +138,79 @@ type Join struct {
 
 func (Join) queryNode() {}
 
+// Union represents the set union of two queries.
+//
+// Semantics:
+//
+//	(<left>) UNION (<right>)
+//
+// The Union query:
+//  1. Executes left and right queries independently
+//  2. Returns every binding set produced by either side
+//  3. Requires both sides to bind exactly the same variable names
+//
+// Union is the portable way to express OR: instead of
+// "status = 'pending' OR status = 'held'", union two Selects that each
+// filter on one value.
+//
+// Example:
+//
+//	Union{
+//	  Left:  Select{From: "Orders", Filter: Equals{Field: "status", Value: ir.IRString("pending")},
+//	                Bindings: map[string]string{"id": "orderId"}},
+//	  Right: Select{From: "Orders", Filter: Equals{Field: "status", Value: ir.IRString("held")},
+//	                Bindings: map[string]string{"id": "orderId"}},
+//	}
+//
+// PORTABLE FRAGMENT RULES:
+//   - Left and Right must bind identical variable names (checked at compile time)
+//   - Bindings must be explicit on both sides (no SELECT *)
+//   - Results are ordered deterministically across both sides (CP-4)
+//
+// SPARQL MAPPING:
+//
+//	{ <left patterns> } UNION { <right patterns> }
+type Union struct {
+	Left  Query // Left query (must bind same variables as Right)
+	Right Query // Right query (must bind same variables as Left)
+}
+
+func (Union) queryNode() {}
+
+// Count represents a row-count aggregation over a Select.
+//
+// Semantics:
+//
+//	SELECT COUNT(*) AS count FROM <from> WHERE <filter>
+//
+// The Count query:
+//  1. Applies the wrapped Select's source and filter
+//  2. Ignores the wrapped Select's bindings
+//  3. Produces exactly one binding set: {"count": IRInt(n)}
+//
+// Count exists for guard conditions such as "fire only if there is no
+// existing reservation" (count = 0).
+//
+// Example:
+//
+//	Count{Select: Select{
+//	  From:   "Reservations",
+//	  Filter: BoundEquals{Field: "item_id", BoundVar: "bound.itemId"},
+//	}}
+//
+// NOT PORTABLE:
+// Aggregations are excluded from the portable fragment (MVP). Count is a
+// SQL-backend-specific feature; Validate reports it as a warning. A SPARQL
+// backend would need COUNT support before such queries can migrate.
+type Count struct {
+	Select Select // Source and filter to count (bindings ignored, no limit)
+}
+
+func (Count) queryNode() {}
+
+// CountBinding is the variable name Count binds its result to.
+const CountBinding = "count"
+
 // Equals represents a field-equals-literal predicate.
 //
 // Semantics:
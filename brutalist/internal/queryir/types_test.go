@@ -521,3 +521,17 @@ func TestDeepNesting(t *testing.T) {
 	assert.IsType(t, Select{}, outerJoin.Right)
 	assert.IsType(t, BoundEquals{}, outerJoin.On)
 }
+
+func TestUnion_ImplementsQuery(t *testing.T) {
+	var q Query = Union{
+		Left:  Select{From: "Orders"},
+		Right: Select{From: "Orders"},
+	}
+
+	switch q.(type) {
+	case Union:
+		// Expected
+	case Select, Join:
+		t.Fatal("unexpected type")
+	}
+}
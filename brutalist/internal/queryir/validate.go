@@ -2,6 +2,7 @@ package queryir
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/roach88/nysm/internal/ir"
 )
@@ -76,6 +77,22 @@ func (v *validator) validateQuery(q Query) {
 		v.validateJoin(query)
 	case *Join:
 		v.validateJoin(*query)
+	case Union:
+		v.validateUnion(query)
+	case *Union:
+		v.validateUnion(*query)
+	case Count:
+		v.validateCount(query)
+	case *Count:
+		v.validateCount(*query)
+	case Projection:
+		v.validateProjection(query)
+	case *Projection:
+		v.validateProjection(*query)
+	case OrderBy:
+		v.validateOrderBy(query)
+	case *OrderBy:
+		v.validateOrderBy(*query)
 	default:
 		// Unknown query type - add warning
 		v.addWarning("Unknown query type: %T - portability cannot be verified", q)
@@ -93,6 +110,10 @@ func (v *validator) validateSelect(sel Select) {
 	if sel.Filter != nil {
 		v.validatePredicate(sel.Filter)
 	}
+
+	if sel.Limit < 0 {
+		v.addWarning("Negative limit %d on %s - limit must be zero (unlimited) or positive", sel.Limit, sel.From)
+	}
 }
 
 // validateJoin validates a Join query node.
@@ -114,6 +135,93 @@ func (v *validator) validateJoin(join Join) {
 	}
 }
 
+// validateCount validates a Count query node.
+// Count is always non-portable (aggregation), but its filter is still checked.
+func (v *validator) validateCount(count Count) {
+	v.addWarning("Count aggregation on %s - aggregations are backend-specific (not in portable fragment)", count.Select.From)
+	if count.Select.Filter != nil {
+		v.validatePredicate(count.Select.Filter)
+	}
+}
+
+// validateProjection validates a Projection query node.
+func (v *validator) validateProjection(proj Projection) {
+	v.validateQuery(proj.Query)
+
+	// Rule 4: Explicit bindings - no SELECT *
+	if len(proj.Outputs) == 0 {
+		v.addWarning("Empty projection outputs (SELECT *) - portable fragment requires explicit field selection")
+	}
+
+	for _, name := range sortedKeys(proj.Outputs) {
+		src := proj.Outputs[name]
+		if src.Field != "" {
+			continue
+		}
+		switch src.Value.(type) {
+		case nil:
+			v.addWarning("Projection output '%s' has neither a source field nor a value", name)
+		case ir.IRNull:
+			// Rule 1: No NULLs
+			v.addWarning("Projection output '%s' binds NULL - portable fragment requires explicit values", name)
+		}
+	}
+
+	missing, _ := proj.unboundSources()
+	for _, name := range missing {
+		v.addWarning("Projection output '%s' projects field '%s' not bound by the child query", name, proj.Outputs[name].Field)
+	}
+}
+
+// validateOrderBy validates an OrderBy query node.
+func (v *validator) validateOrderBy(order OrderBy) {
+	v.validateQuery(order.Query)
+
+	if len(order.Keys) == 0 {
+		v.addWarning("OrderBy without keys - ordering requires at least one key")
+	}
+
+	for _, key := range order.Keys {
+		if !key.Direction.Valid() {
+			v.addWarning("OrderBy key '%s' has unknown direction %q - use asc or desc", key.Field, key.Direction)
+		}
+	}
+
+	missing, _ := order.unboundFields()
+	for _, field := range missing {
+		v.addWarning("OrderBy key '%s' not bound by the child query", field)
+	}
+}
+
+// validateUnion validates a Union query node.
+func (v *validator) validateUnion(union Union) {
+	v.validateQuery(union.Left)
+	v.validateQuery(union.Right)
+
+	// Both sides must produce the same variables
+	left, leftOK := selectBoundVars(union.Left)
+	right, rightOK := selectBoundVars(union.Right)
+	if leftOK && rightOK && !slices.Equal(left, right) {
+		v.addWarning("Union branches bind different variables (%v vs %v) - both sides must bind identical variables", left, right)
+	}
+}
+
+// selectBoundVars returns the sorted bound variable names of a Select.
+// Returns false for non-Select queries.
+func selectBoundVars(q Query) ([]string, bool) {
+	var bindings map[string]string
+	switch query := q.(type) {
+	case Select:
+		bindings = query.Bindings
+	case *Select:
+		bindings = query.Bindings
+	default:
+		return nil, false
+	}
+
+	return boundVarsOf(bindings), true
+}
+
 // validatePredicate recursively validates a predicate node.
 func (v *validator) validatePredicate(p Predicate) {
 	if p == nil {
@@ -130,10 +238,22 @@ func (v *validator) validatePredicate(p Predicate) {
 		// Binding existence is checked at runtime, not during validation
 	case *BoundEquals:
 		// Same as above
+	case NotEquals:
+		v.validateNotEquals(pred)
+	case *NotEquals:
+		v.validateNotEquals(*pred)
 	case And:
 		v.validateAnd(pred)
 	case *And:
 		v.validateAnd(*pred)
+	case In:
+		v.validateIn(pred)
+	case *In:
+		v.validateIn(*pred)
+	case GreaterThan, *GreaterThan, LessThan, *LessThan,
+		GreaterOrEqual, *GreaterOrEqual, LessOrEqual, *LessOrEqual:
+		// Integer comparisons are portable - SPARQL FILTER supports them
+		// and IRInt operands rule out floats (CP-5)
 	default:
 		// Unknown predicate type
 		v.addWarning("Unknown predicate type: %T - portability cannot be verified", p)
@@ -149,6 +269,32 @@ func (v *validator) validateEquals(eq Equals) {
 	}
 }
 
+// validateNotEquals validates a NotEquals predicate.
+func (v *validator) validateNotEquals(ne NotEquals) {
+	if ne.BoundVar != "" {
+		return // Bound operand is checked at runtime, like BoundEquals
+	}
+
+	// Rule 1: No NULLs (a NULL literal makes the predicate never true)
+	if _, isNull := ne.Value.(ir.IRNull); isNull || ne.Value == nil {
+		v.addWarning("Field '%s' compared to NULL with != - portable fragment requires explicit values", ne.Field)
+	}
+}
+
+// validateIn validates an In predicate.
+func (v *validator) validateIn(in In) {
+	if len(in.Values) == 0 {
+		v.addWarning("Field '%s' compared to empty IN set - set must be non-empty", in.Field)
+	}
+
+	// Rule 1: No NULLs
+	for _, val := range in.Values {
+		if _, isNull := val.(ir.IRNull); isNull {
+			v.addWarning("Field '%s' IN set contains NULL - portable fragment requires explicit values", in.Field)
+		}
+	}
+}
+
 // validateAnd validates an And predicate.
 func (v *validator) validateAnd(and And) {
 	// Recursively validate all sub-predicates
@@ -566,3 +566,66 @@ func TestValidate_Idempotent(t *testing.T) {
 	assert.Equal(t, result1.IsPortable, result2.IsPortable)
 	assert.Equal(t, result1.Warnings, result2.Warnings)
 }
+
+func TestValidate_Union(t *testing.T) {
+	query := Union{
+		Left: Select{
+			From:     "orders",
+			Filter:   Equals{Field: "status", Value: ir.IRString("pending")},
+			Bindings: map[string]string{"id": "orderId"},
+		},
+		Right: &Select{
+			From:     "orders",
+			Filter:   Equals{Field: "status", Value: ir.IRString("held")},
+			Bindings: map[string]string{"id": "orderId"},
+		},
+	}
+
+	result := Validate(query)
+
+	assert.True(t, result.IsPortable, "Union with matching bindings is portable")
+	assert.Empty(t, result.Warnings)
+}
+
+func TestValidate_UnionMismatchedBindings(t *testing.T) {
+	query := &Union{
+		Left:  Select{From: "orders", Bindings: map[string]string{"id": "orderId"}},
+		Right: Select{From: "orders", Bindings: map[string]string{"id": "id"}},
+	}
+
+	result := Validate(query)
+
+	assert.False(t, result.IsPortable)
+	require.Len(t, result.Warnings, 1)
+	assert.Contains(t, result.Warnings[0], "bind different variables")
+}
+
+func TestValidate_Limit(t *testing.T) {
+	valid := Validate(Select{
+		From:     "events",
+		Bindings: map[string]string{"id": "eventId"},
+		Limit:    5,
+	})
+	assert.True(t, valid.IsPortable, "LIMIT with deterministic ORDER BY is portable")
+
+	negative := Validate(Select{
+		From:     "events",
+		Bindings: map[string]string{"id": "eventId"},
+		Limit:    -1,
+	})
+	assert.False(t, negative.IsPortable)
+	require.Len(t, negative.Warnings, 1)
+	assert.Contains(t, negative.Warnings[0], "Negative limit")
+}
+
+func TestValidate_CountNotPortable(t *testing.T) {
+	result := Validate(Count{Select: Select{
+		From:   "reservations",
+		Filter: Equals{Field: "item_id", Value: ir.IRNull{}},
+	}})
+
+	assert.False(t, result.IsPortable)
+	require.Len(t, result.Warnings, 2)
+	assert.Contains(t, result.Warnings[0], "Count aggregation")
+	assert.Contains(t, result.Warnings[1], "NULL")
+}
@@ -0,0 +1,129 @@
+package querysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/roach88/nysm/internal/ir"
+	"github.com/roach88/nysm/internal/queryir"
+)
+
+// Queryer executes parameterized SQL and returns rows.
+// Implemented by *store.Store; kept as an interface so this package
+// does not depend on the store.
+type Queryer interface {
+	Query(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// SQLBackend is the SQL implementation of the QueryIR backend contract (HIGH-2).
+//
+// It compiles QueryIR to parameterized SQL via SQLCompiler and executes the
+// result, returning one binding set per row. The sync engine uses this to
+// run where-clauses; a SPARQL backend would expose the same shape.
+//
+// CRITICAL: ALL queries include ORDER BY per CP-4 for deterministic results.
+// CRITICAL: All values are parameterized (never interpolated) per HIGH-3.
+type SQLBackend struct {
+	// BoundValues holds the values for BoundEquals predicates.
+	// Must be set by the engine before compilation.
+	BoundValues map[string]any
+}
+
+// NewSQLBackend creates a new SQLBackend.
+func NewSQLBackend() *SQLBackend {
+	return &SQLBackend{
+		BoundValues: make(map[string]any),
+	}
+}
+
+// Compile converts a QueryIR query to parameterized SQL.
+// Returns (sql, args, error) tuple.
+func (b *SQLBackend) Compile(q queryir.Query) (string, []any, error) {
+	compiler := &SQLCompiler{BoundValues: b.BoundValues}
+	return compiler.Compile(q)
+}
+
+// Execute compiles and runs a query, returning one IRObject per row.
+//
+// Each row is keyed by result column name. Because compiled queries alias
+// every source field to its bound variable, keys are the variable names
+// from the query's explicit bindings.
+//
+// Returns an empty slice (not nil) when no rows match. Row order follows
+// the compiled ORDER BY (CP-4).
+func (b *SQLBackend) Execute(ctx context.Context, db Queryer, q queryir.Query) ([]ir.IRObject, error) {
+	sqlStr, args, err := b.Compile(q)
+	if err != nil {
+		return nil, fmt.Errorf("compile query: %w", err)
+	}
+
+	rows, err := db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, fmt.Errorf("execute query: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("get columns: %w", err)
+	}
+
+	results := []ir.IRObject{}
+	for rows.Next() {
+		values := make([]any, len(columns))
+		valuePtrs := make([]any, len(columns))
+		for i := range values {
+			valuePtrs[i] = &values[i]
+		}
+
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+
+		row := make(ir.IRObject, len(columns))
+		for i, col := range columns {
+			v, err := FromSQLValue(values[i])
+			if err != nil {
+				return nil, fmt.Errorf("convert column %s: %w", col, err)
+			}
+			row[col] = v
+		}
+		results = append(results, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return results, nil
+}
+
+// FromSQLValue converts a value scanned by database/sql to an ir.IRValue.
+// SQL NULL maps to IRNull.
+//
+// CP-5: Floats are FORBIDDEN in IR - they break determinism. SQL REAL/FLOAT
+// columns must be avoided in schema design (store cents not dollars, or
+// TEXT with explicit precision).
+func FromSQLValue(v any) (ir.IRValue, error) {
+	if v == nil {
+		return ir.IRNull{}, nil
+	}
+
+	switch val := v.(type) {
+	case int64:
+		return ir.IRInt(val), nil
+	case int:
+		return ir.IRInt(int64(val)), nil
+	case float64:
+		return nil, fmt.Errorf("float64 values are forbidden in IR (CP-5): %v - use INTEGER or TEXT instead", val)
+	case string:
+		return ir.IRString(val), nil
+	case []byte:
+		return ir.IRString(string(val)), nil
+	case bool:
+		return ir.IRBool(val), nil
+	default:
+		return nil, fmt.Errorf("unsupported SQL type: %T", v)
+	}
+}
@@ -0,0 +1,361 @@
+package querysql
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/roach88/nysm/internal/ir"
+	"github.com/roach88/nysm/internal/queryir"
+)
+
+// testDB adapts *sql.DB to the Queryer interface.
+type testDB struct {
+	db *sql.DB
+}
+
+func (d testDB) Query(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return d.db.QueryContext(ctx, query, args...)
+}
+
+func setupBackendDB(t *testing.T) testDB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	_, err = db.Exec(`
+		CREATE TABLE cart_items (id TEXT PRIMARY KEY, seq INTEGER, cart_id TEXT, item_id TEXT, quantity INTEGER);
+		CREATE TABLE inventory (id TEXT PRIMARY KEY, seq INTEGER, item_id TEXT, available INTEGER);
+		INSERT INTO cart_items VALUES
+			('ci-3', 1, 'cart-1', 'widget', 2),
+			('ci-1', 2, 'cart-1', 'gadget', 1),
+			('ci-2', 2, 'cart-1', 'gizmo', 5),
+			('ci-4', 3, 'cart-2', 'widget', 9);
+		INSERT INTO inventory VALUES
+			('inv-1', 1, 'widget', 10),
+			('inv-2', 2, 'gadget', 0),
+			('inv-3', 3, 'gizmo', 7);
+	`)
+	require.NoError(t, err)
+	return testDB{db: db}
+}
+
+func TestSQLBackend_Compile_OrdersBySeqThenID(t *testing.T) {
+	backend := NewSQLBackend()
+
+	sql, _, err := backend.Compile(queryir.Select{
+		From:     "cart_items",
+		Bindings: map[string]string{"item_id": "item"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "SELECT item_id AS item FROM cart_items ORDER BY seq ASC, id COLLATE BINARY ASC", sql)
+}
+
+func TestSQLBackend_Execute_SelectWithBoundEquals(t *testing.T) {
+	db := setupBackendDB(t)
+	backend := NewSQLBackend()
+	backend.BoundValues["bound.cart_id"] = "cart-1"
+
+	query := queryir.Select{
+		From: "cart_items",
+		Filter: queryir.BoundEquals{
+			Field:    "cart_id",
+			BoundVar: "bound.cart_id",
+		},
+		Bindings: map[string]string{
+			"item_id":  "item",
+			"quantity": "qty",
+		},
+	}
+
+	sqlStr, args, err := backend.Compile(query)
+	require.NoError(t, err)
+	assert.Contains(t, sqlStr, "cart_id = ?")
+	assert.Equal(t, []any{"cart-1"}, args, "bound value must be parameterized (HIGH-3)")
+
+	rows, err := backend.Execute(context.Background(), db, query)
+	require.NoError(t, err)
+
+	// Ordered by seq, then id as tiebreaker (CP-4)
+	assert.Equal(t, []ir.IRObject{
+		{"item": ir.IRString("widget"), "qty": ir.IRInt(2)},
+		{"item": ir.IRString("gadget"), "qty": ir.IRInt(1)},
+		{"item": ir.IRString("gizmo"), "qty": ir.IRInt(5)},
+	}, rows)
+}
+
+func TestSQLBackend_Execute_NotEqualsSkipsMissingValues(t *testing.T) {
+	db := setupBackendDB(t)
+	_, err := db.db.Exec(`INSERT INTO inventory VALUES ('inv-4', 4, NULL, 3)`)
+	require.NoError(t, err)
+
+	rows, err := NewSQLBackend().Execute(context.Background(), db, queryir.Select{
+		From:     "inventory",
+		Filter:   queryir.NotEquals{Field: "item_id", Value: ir.IRString("widget")},
+		Bindings: map[string]string{"id": "id"},
+	})
+	require.NoError(t, err)
+
+	// inv-4 has no item_id, so it is not "not widget" either
+	assert.Equal(t, []ir.IRObject{
+		{"id": ir.IRString("inv-2")},
+		{"id": ir.IRString("inv-3")},
+	}, rows)
+}
+
+func TestSQLBackend_Execute_NoRows(t *testing.T) {
+	db := setupBackendDB(t)
+	backend := NewSQLBackend()
+
+	rows, err := backend.Execute(context.Background(), db, queryir.Select{
+		From:     "cart_items",
+		Filter:   queryir.Equals{Field: "cart_id", Value: ir.IRString("missing")},
+		Bindings: map[string]string{"item_id": "item"},
+	})
+	require.NoError(t, err)
+	assert.NotNil(t, rows, "expected empty slice, not nil")
+	assert.Empty(t, rows)
+}
+
+func TestSQLBackend_Execute_Join(t *testing.T) {
+	db := setupBackendDB(t)
+	backend := NewSQLBackend()
+	backend.BoundValues["bound.cart_id"] = "cart-1"
+
+	query := queryir.Join{
+		Left: queryir.Select{
+			From:     "cart_items",
+			Filter:   queryir.BoundEquals{Field: "cart_id", BoundVar: "bound.cart_id"},
+			Bindings: map[string]string{"item_id": "item"},
+		},
+		Right: queryir.Select{
+			From:     "inventory",
+			Filter:   queryir.Equals{Field: "available", Value: ir.IRInt(7)},
+			Bindings: map[string]string{"available": "stock"},
+		},
+		On: queryir.Equals{Field: "cart_items.quantity", Value: ir.IRInt(5)},
+	}
+
+	sqlStr, args, err := backend.Compile(query)
+	require.NoError(t, err)
+	assert.Equal(t,
+		"SELECT cart_items.item_id AS item, inventory.available AS stock "+
+			"FROM cart_items INNER JOIN inventory ON cart_items.quantity = ? "+
+			"WHERE cart_items.cart_id = ? AND inventory.available = ? "+
+			"ORDER BY cart_items.seq ASC, cart_items.id COLLATE BINARY ASC",
+		sqlStr)
+	assert.Equal(t, []any{int64(5), "cart-1", int64(7)}, args,
+		"params must follow placeholder order: ON, left filter, right filter")
+
+	rows, err := backend.Execute(context.Background(), db, query)
+	require.NoError(t, err)
+	assert.Equal(t, []ir.IRObject{
+		{"item": ir.IRString("gizmo"), "stock": ir.IRInt(7)},
+	}, rows)
+}
+
+func TestSQLBackend_Join_DuplicateBindingRejected(t *testing.T) {
+	backend := NewSQLBackend()
+
+	_, _, err := backend.Compile(queryir.Join{
+		Left:  queryir.Select{From: "cart_items", Bindings: map[string]string{"item_id": "item"}},
+		Right: queryir.Select{From: "inventory", Bindings: map[string]string{"item_id": "item"}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"item"`)
+}
+
+func TestFromSQLValue_FloatForbidden(t *testing.T) {
+	_, err := FromSQLValue(float64(1.5))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "CP-5")
+
+	v, err := FromSQLValue(nil)
+	require.NoError(t, err)
+	assert.Equal(t, ir.IRNull{}, v)
+}
+
+func TestSQLBackend_Execute_Union(t *testing.T) {
+	db := setupBackendDB(t)
+	backend := NewSQLBackend()
+
+	query := queryir.Union{
+		Left: queryir.Select{
+			From:     "cart_items",
+			Filter:   queryir.Equals{Field: "cart_id", Value: ir.IRString("cart-2")},
+			Bindings: map[string]string{"item_id": "item"},
+		},
+		Right: queryir.Select{
+			From:     "inventory",
+			Filter:   queryir.Equals{Field: "available", Value: ir.IRInt(0)},
+			Bindings: map[string]string{"item_id": "item"},
+		},
+	}
+
+	rows, err := backend.Execute(context.Background(), db, query)
+	require.NoError(t, err)
+
+	// Ordered by seq across both branches: inv-2 (seq 2), ci-4 (seq 3)
+	assert.Equal(t, []ir.IRObject{
+		{"item": ir.IRString("gadget")},
+		{"item": ir.IRString("widget")},
+	}, rows)
+}
+
+func TestSQLBackend_Execute_Projection(t *testing.T) {
+	db := setupBackendDB(t)
+	backend := NewSQLBackend()
+
+	query := queryir.Projection{
+		Query: queryir.Select{
+			From:     "cart_items",
+			Filter:   queryir.Equals{Field: "cart_id", Value: ir.IRString("cart-1")},
+			Bindings: map[string]string{"item_id": "item", "quantity": "qty"},
+		},
+		Outputs: map[string]queryir.ProjectionSource{
+			"sku":    {Field: "item"},
+			"source": {Value: ir.IRString("cart")},
+		},
+	}
+
+	rows, err := backend.Execute(context.Background(), db, query)
+	require.NoError(t, err)
+
+	// Renamed and constant bindings only; qty is not projected
+	assert.Equal(t, []ir.IRObject{
+		{"sku": ir.IRString("widget"), "source": ir.IRString("cart")},
+		{"sku": ir.IRString("gadget"), "source": ir.IRString("cart")},
+		{"sku": ir.IRString("gizmo"), "source": ir.IRString("cart")},
+	}, rows)
+}
+
+func TestSQLBackend_Execute_OrderBy(t *testing.T) {
+	db := setupBackendDB(t)
+	backend := NewSQLBackend()
+
+	selectItems := queryir.Select{
+		From:     "cart_items",
+		Bindings: map[string]string{"cart_id": "cart", "item_id": "item", "quantity": "qty"},
+	}
+
+	t.Run("ascending", func(t *testing.T) {
+		rows, err := backend.Execute(context.Background(), db, queryir.OrderBy{
+			Query: selectItems,
+			Keys:  []queryir.OrderKey{{Field: "qty", Direction: queryir.Ascending}},
+		})
+		require.NoError(t, err)
+
+		var items []ir.IRValue
+		for _, row := range rows {
+			items = append(items, row["item"])
+		}
+		assert.Equal(t, []ir.IRValue{
+			ir.IRString("gadget"), ir.IRString("widget"), ir.IRString("gizmo"), ir.IRString("widget"),
+		}, items)
+	})
+
+	t.Run("descending", func(t *testing.T) {
+		rows, err := backend.Execute(context.Background(), db, queryir.OrderBy{
+			Query: selectItems,
+			Keys:  []queryir.OrderKey{{Field: "qty", Direction: queryir.Descending}},
+		})
+		require.NoError(t, err)
+
+		var qtys []ir.IRValue
+		for _, row := range rows {
+			qtys = append(qtys, row["qty"])
+		}
+		assert.Equal(t, []ir.IRValue{ir.IRInt(9), ir.IRInt(5), ir.IRInt(2), ir.IRInt(1)}, qtys)
+	})
+
+	t.Run("tiebreak", func(t *testing.T) {
+		// Two cart-3 rows with equal seq, inserted out of id order
+		_, err := db.db.Exec(`INSERT INTO cart_items VALUES
+			('ci-6', 4, 'cart-3', 'bolt', 3),
+			('ci-5', 4, 'cart-3', 'nut', 3)`)
+		require.NoError(t, err)
+
+		rows, err := backend.Execute(context.Background(), db, queryir.OrderBy{
+			Query: selectItems,
+			Keys:  []queryir.OrderKey{{Field: "cart", Direction: queryir.Descending}},
+		})
+		require.NoError(t, err)
+
+		// Rows with equal keys fall back to seq ASC, then id ASC
+		var items []ir.IRValue
+		for _, row := range rows {
+			items = append(items, row["item"])
+		}
+		assert.Equal(t, []ir.IRValue{
+			ir.IRString("nut"), ir.IRString("bolt"), // cart-3: seq 4, ci-5 < ci-6
+			ir.IRString("widget"),                                              // cart-2
+			ir.IRString("widget"), ir.IRString("gadget"), ir.IRString("gizmo"), // cart-1: seq 1, then seq 2 by id
+		}, items)
+	})
+}
+
+func TestSQLBackend_Execute_LimitStablePrefix(t *testing.T) {
+	db := setupBackendDB(t)
+	_, err := db.db.Exec(`
+		CREATE TABLE events (id TEXT PRIMARY KEY, seq INTEGER, kind TEXT);
+		INSERT INTO events VALUES
+			('e-5', 5, 'tick'),
+			('e-2', 2, 'tick'),
+			('e-4', 4, 'tick'),
+			('e-1', 1, 'tick'),
+			('e-3', 3, 'tick');
+	`)
+	require.NoError(t, err)
+
+	query := queryir.Select{
+		From:     "events",
+		Filter:   queryir.Equals{Field: "kind", Value: ir.IRString("tick")},
+		Bindings: map[string]string{"id": "eventId"},
+		Limit:    2,
+	}
+
+	want := []ir.IRObject{
+		{"eventId": ir.IRString("e-1")},
+		{"eventId": ir.IRString("e-2")},
+	}
+
+	// Deterministic ORDER BY makes the limited prefix identical on every run
+	for run := 0; run < 5; run++ {
+		rows, err := NewSQLBackend().Execute(context.Background(), db, query)
+		require.NoError(t, err)
+		assert.Equal(t, want, rows, "run %d", run)
+	}
+}
+
+func TestSQLBackend_Execute_Count(t *testing.T) {
+	db := setupBackendDB(t)
+	backend := NewSQLBackend()
+	backend.BoundValues["bound.cart_id"] = "cart-1"
+
+	tests := []struct {
+		name   string
+		filter queryir.Predicate
+		want   int64
+	}{
+		{"nonzero", queryir.BoundEquals{Field: "cart_id", BoundVar: "bound.cart_id"}, 3},
+		{"zero", queryir.Equals{Field: "cart_id", Value: ir.IRString("missing")}, 0},
+		{"no filter", nil, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := backend.Execute(context.Background(), db, queryir.Count{
+				Select: queryir.Select{From: "cart_items", Filter: tt.filter},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, []ir.IRObject{{"count": ir.IRInt(tt.want)}}, rows)
+		})
+	}
+}
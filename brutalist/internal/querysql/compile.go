@@ -2,6 +2,7 @@ package querysql
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 
@@ -45,6 +46,22 @@ func (c *SQLCompiler) Compile(q queryir.Query) (string, []any, error) {
 		return c.compileJoin(query)
 	case *queryir.Join:
 		return c.compileJoin(*query)
+	case queryir.Union:
+		return c.compileUnion(query)
+	case *queryir.Union:
+		return c.compileUnion(*query)
+	case queryir.Count:
+		return c.compileCount(query)
+	case *queryir.Count:
+		return c.compileCount(*query)
+	case queryir.Projection:
+		return c.compileProjection(query)
+	case *queryir.Projection:
+		return c.compileProjection(*query)
+	case queryir.OrderBy:
+		return c.compileOrderBy(query)
+	case *queryir.OrderBy:
+		return c.compileOrderBy(*query)
 	default:
 		return "", nil, fmt.Errorf("unsupported query type: %T", q)
 	}
@@ -53,33 +70,49 @@ func (c *SQLCompiler) Compile(q queryir.Query) (string, []any, error) {
 // compileSelect compiles a queryir.Select to SQL.
 // MANDATORY: Includes ORDER BY per CP-4.
 func (c *SQLCompiler) compileSelect(q queryir.Select) (string, []any, error) {
-	// Build SELECT clause from bindings
-	selectClause := c.compileBindings(q.Bindings)
+	return c.compileSelectColumns(q, c.compileBindings(q.Bindings), nil, nil)
+}
 
+// compileSelectColumns compiles a Select with the given SELECT column list.
+// selectParams bind placeholders in the column list and precede filter
+// parameters, matching placeholder order. orderTerms (from OrderBy) are
+// placed ahead of the mandatory tiebreak.
+func (c *SQLCompiler) compileSelectColumns(q queryir.Select, selectClause string, selectParams []any, orderTerms []string) (string, []any, error) {
 	// Build FROM clause
 	fromClause := q.From
 
 	// Build WHERE clause and collect parameters
 	var whereClause string
-	var params []any
+	params := selectParams
 	if q.Filter != nil {
 		filterSQL, filterParams, err := c.compilePredicate(q.Filter)
 		if err != nil {
 			return "", nil, fmt.Errorf("compile filter: %w", err)
 		}
 		whereClause = " WHERE " + filterSQL
-		params = filterParams
+		params = append(params, filterParams...)
 	}
 
 	// MANDATORY: Always add ORDER BY per CP-4
-	orderByClause := " ORDER BY " + c.stableOrderKey(q)
+	orderByClause := " ORDER BY " + orderByTerms(orderTerms, c.stableOrderKey(q))
+
+	// LIMIT follows ORDER BY so it selects a stable prefix
+	var limitClause string
+	if q.Limit < 0 {
+		return "", nil, fmt.Errorf("select from %s: limit must be non-negative, got %d", q.From, q.Limit)
+	}
+	if q.Limit > 0 {
+		limitClause = " LIMIT ?"
+		params = append(params, int64(q.Limit))
+	}
 
 	// Assemble SQL
-	sql := fmt.Sprintf("SELECT %s FROM %s%s%s",
+	sql := fmt.Sprintf("SELECT %s FROM %s%s%s%s",
 		selectClause,
 		fromClause,
 		whereClause,
-		orderByClause)
+		orderByClause,
+		limitClause)
 
 	return sql, params, nil
 }
@@ -116,34 +149,64 @@ func (c *SQLCompiler) compileBindings(bindings map[string]string) string {
 
 // stableOrderKey returns the ORDER BY clause for a query.
 // MANDATORY: Every query MUST call this function per CP-4.
-// Uses COLLATE BINARY for deterministic text ordering.
+// Orders by logical clock first, then id with COLLATE BINARY as the
+// deterministic tiebreaker across SQLite versions.
 func (c *SQLCompiler) stableOrderKey(q queryir.Select) string {
-	// Default to id as primary key
-	// COLLATE BINARY ensures deterministic text ordering across SQLite versions
-	return "id ASC COLLATE BINARY"
+	return "seq ASC, id COLLATE BINARY ASC"
 }
 
 // compilePredicate compiles a queryir.Predicate to SQL WHERE clause fragment.
 // Returns (sql, params, error).
 // CRITICAL: Values NEVER interpolated - always use ? placeholders.
 func (c *SQLCompiler) compilePredicate(p queryir.Predicate) (string, []any, error) {
+	return c.compileQualifiedPredicate(p, "")
+}
+
+// compileQualifiedPredicate compiles a predicate with field references
+// qualified by table. An empty table leaves fields unqualified; this is
+// used for joins, where both sides may share column names.
+func (c *SQLCompiler) compileQualifiedPredicate(p queryir.Predicate, table string) (string, []any, error) {
 	if p == nil {
 		return "1 = 1", nil, nil // Always true
 	}
 
 	switch pred := p.(type) {
 	case queryir.Equals:
-		return c.compileEquals(pred)
+		return c.compileEquals(pred, table)
 	case *queryir.Equals:
-		return c.compileEquals(*pred)
+		return c.compileEquals(*pred, table)
 	case queryir.And:
-		return c.compileAnd(pred)
+		return c.compileAnd(pred, table)
 	case *queryir.And:
-		return c.compileAnd(*pred)
+		return c.compileAnd(*pred, table)
 	case queryir.BoundEquals:
-		return c.compileBoundEquals(pred)
+		return c.compileBoundEquals(pred, table)
 	case *queryir.BoundEquals:
-		return c.compileBoundEquals(*pred)
+		return c.compileBoundEquals(*pred, table)
+	case queryir.NotEquals:
+		return c.compileNotEquals(pred, table)
+	case *queryir.NotEquals:
+		return c.compileNotEquals(*pred, table)
+	case queryir.In:
+		return c.compileIn(pred, table)
+	case *queryir.In:
+		return c.compileIn(*pred, table)
+	case queryir.GreaterThan:
+		return c.compileComparison(">", pred.Field, pred.Value, pred.BoundVar, table)
+	case *queryir.GreaterThan:
+		return c.compileComparison(">", pred.Field, pred.Value, pred.BoundVar, table)
+	case queryir.LessThan:
+		return c.compileComparison("<", pred.Field, pred.Value, pred.BoundVar, table)
+	case *queryir.LessThan:
+		return c.compileComparison("<", pred.Field, pred.Value, pred.BoundVar, table)
+	case queryir.GreaterOrEqual:
+		return c.compileComparison(">=", pred.Field, pred.Value, pred.BoundVar, table)
+	case *queryir.GreaterOrEqual:
+		return c.compileComparison(">=", pred.Field, pred.Value, pred.BoundVar, table)
+	case queryir.LessOrEqual:
+		return c.compileComparison("<=", pred.Field, pred.Value, pred.BoundVar, table)
+	case *queryir.LessOrEqual:
+		return c.compileComparison("<=", pred.Field, pred.Value, pred.BoundVar, table)
 	default:
 		return "", nil, fmt.Errorf("unsupported predicate type: %T", p)
 	}
@@ -151,21 +214,21 @@ func (c *SQLCompiler) compilePredicate(p queryir.Predicate) (string, []any, erro
 
 // compileEquals compiles an Equals predicate to "field = ?".
 // CRITICAL: Value is NEVER interpolated - always parameterized.
-func (c *SQLCompiler) compileEquals(eq queryir.Equals) (string, []any, error) {
+func (c *SQLCompiler) compileEquals(eq queryir.Equals, table string) (string, []any, error) {
 	// Convert IRValue to Go native type for SQL parameter
 	param, err := irValueToParam(eq.Value)
 	if err != nil {
 		return "", nil, fmt.Errorf("convert value: %w", err)
 	}
 
-	sql := fmt.Sprintf("%s = ?", eq.Field)
+	sql := fmt.Sprintf("%s = ?", qualifyColumn(table, eq.Field))
 	params := []any{param}
 
 	return sql, params, nil
 }
 
 // compileAnd compiles an And predicate to conjunction with AND.
-func (c *SQLCompiler) compileAnd(and queryir.And) (string, []any, error) {
+func (c *SQLCompiler) compileAnd(and queryir.And, table string) (string, []any, error) {
 	if len(and.Predicates) == 0 {
 		return "1 = 1", nil, nil // Always true (vacuous truth)
 	}
@@ -174,7 +237,7 @@ func (c *SQLCompiler) compileAnd(and queryir.And) (string, []any, error) {
 	var allParams []any
 
 	for _, pred := range and.Predicates {
-		sql, params, err := c.compilePredicate(pred)
+		sql, params, err := c.compileQualifiedPredicate(pred, table)
 		if err != nil {
 			return "", nil, err
 		}
@@ -192,8 +255,8 @@ func (c *SQLCompiler) compileAnd(and queryir.And) (string, []any, error) {
 // BoundEquals references a variable from when-clause bindings.
 // The bound value is looked up from BoundValues map.
 // CRITICAL: Value is NEVER interpolated - always parameterized.
-func (c *SQLCompiler) compileBoundEquals(beq queryir.BoundEquals) (string, []any, error) {
-	sql := fmt.Sprintf("%s = ?", beq.Field)
+func (c *SQLCompiler) compileBoundEquals(beq queryir.BoundEquals, table string) (string, []any, error) {
+	sql := fmt.Sprintf("%s = ?", qualifyColumn(table, beq.Field))
 
 	// Look up bound value from BoundValues map
 	var params []any
@@ -208,42 +271,134 @@ func (c *SQLCompiler) compileBoundEquals(beq queryir.BoundEquals) (string, []any
 	return sql, params, nil
 }
 
+// compileNotEquals compiles a NotEquals predicate to "field <> ?".
+// The operand is the bound variable if set (looked up like BoundEquals),
+// otherwise the literal. NULL literals are rejected: "<> NULL" is never true.
+// CRITICAL: Value is NEVER interpolated - always parameterized.
+func (c *SQLCompiler) compileNotEquals(ne queryir.NotEquals, table string) (string, []any, error) {
+	sql := fmt.Sprintf("%s <> ?", qualifyColumn(table, ne.Field))
+
+	if ne.BoundVar != "" {
+		var params []any
+		if c.BoundValues != nil {
+			if val, ok := c.BoundValues[ne.BoundVar]; ok {
+				params = []any{val}
+			}
+		}
+		return sql, params, nil
+	}
+
+	if _, isNull := ne.Value.(ir.IRNull); isNull || ne.Value == nil {
+		return "", nil, fmt.Errorf("not-equals on %s: value is null", ne.Field)
+	}
+	param, err := irValueToParam(ne.Value)
+	if err != nil {
+		return "", nil, fmt.Errorf("not-equals on %s: %w", ne.Field, err)
+	}
+	return sql, []any{param}, nil
+}
+
+// compileIn compiles an In predicate to "field IN (?, ?, ...)".
+// One parameter per element. Empty sets and NULL elements are rejected.
+// CRITICAL: Values are NEVER interpolated - always parameterized.
+func (c *SQLCompiler) compileIn(in queryir.In, table string) (string, []any, error) {
+	if len(in.Values) == 0 {
+		return "", nil, fmt.Errorf("IN on %s: value set must not be empty", in.Field)
+	}
+
+	placeholders := make([]string, len(in.Values))
+	params := make([]any, len(in.Values))
+	for i, v := range in.Values {
+		if _, isNull := v.(ir.IRNull); isNull {
+			return "", nil, fmt.Errorf("IN on %s: element %d is null", in.Field, i)
+		}
+		param, err := irValueToParam(v)
+		if err != nil {
+			return "", nil, fmt.Errorf("IN on %s: element %d: %w", in.Field, i, err)
+		}
+		placeholders[i] = "?"
+		params[i] = param
+	}
+
+	sql := fmt.Sprintf("%s IN (%s)", qualifyColumn(table, in.Field), strings.Join(placeholders, ", "))
+	return sql, params, nil
+}
+
+// compileComparison compiles an integer comparison to "field <op> ?".
+// The operand is the bound variable if set, otherwise the IRInt literal.
+// Bound values must be integers - floats and strings are rejected (CP-5).
+// CRITICAL: Value is NEVER interpolated - always parameterized.
+func (c *SQLCompiler) compileComparison(op, field string, value ir.IRInt, boundVar, table string) (string, []any, error) {
+	sql := fmt.Sprintf("%s %s ?", qualifyColumn(table, field), op)
+
+	if boundVar == "" {
+		return sql, []any{int64(value)}, nil
+	}
+
+	// Look up bound value; missing values behave like BoundEquals
+	var params []any
+	if c.BoundValues != nil {
+		if val, ok := c.BoundValues[boundVar]; ok {
+			n, isInt := val.(int64)
+			if !isInt {
+				return "", nil, fmt.Errorf("comparison on %s: bound variable %s must be an integer, got %T", field, boundVar, val)
+			}
+			params = []any{n}
+		}
+	}
+
+	return sql, params, nil
+}
+
 // compileJoin compiles a queryir.Join to SQL INNER JOIN.
+//
+// Both sides must be Select for MVP. Bindings and filters from each side
+// are qualified with their table name so shared column names (id, seq)
+// don't collide. Parameters are ordered to match placeholder positions:
+// ON predicate first, then left filter, then right filter.
+//
 // MANDATORY: Includes ORDER BY per CP-4.
 func (c *SQLCompiler) compileJoin(j queryir.Join) (string, []any, error) {
+	return c.compileJoinColumns(j, nil, nil)
+}
+
+// compileJoinColumns compiles a Join. With nil outputs the SELECT list is
+// the sides' bindings; otherwise it is the projection of those bindings.
+// orderTerms (from OrderBy) are placed ahead of the mandatory tiebreak.
+func (c *SQLCompiler) compileJoinColumns(j queryir.Join, outputs map[string]queryir.ProjectionSource, orderTerms []string) (string, []any, error) {
 	// Get left table (must be Select for MVP)
-	leftTable, leftOK := getSelectFrom(j.Left)
-	if !leftOK {
+	left := getSelect(j.Left)
+	if left == nil {
 		return "", nil, fmt.Errorf("join left must be Select for MVP")
 	}
 
 	// Get right table (must be Select for MVP)
-	rightTable, rightOK := getSelectFrom(j.Right)
-	if !rightOK {
+	right := getSelect(j.Right)
+	if right == nil {
 		return "", nil, fmt.Errorf("join right must be Select for MVP")
 	}
 
-	// Collect all parameters from left and right filters
-	var allParams []any
+	if left.Limit != 0 || right.Limit != 0 {
+		return "", nil, fmt.Errorf("limit is not supported on join sides")
+	}
 
-	// Compile left filter if present
-	leftSelect := getSelect(j.Left)
-	if leftSelect != nil && leftSelect.Filter != nil {
-		_, leftParams, err := c.compilePredicate(leftSelect.Filter)
+	var selectClause string
+	var allParams []any
+	if outputs == nil {
+		clause, err := c.compileJoinBindings(*left, *right)
 		if err != nil {
-			return "", nil, fmt.Errorf("compile left filter: %w", err)
+			return "", nil, err
 		}
-		allParams = append(allParams, leftParams...)
-	}
-
-	// Compile right filter if present
-	rightSelect := getSelect(j.Right)
-	if rightSelect != nil && rightSelect.Filter != nil {
-		_, rightParams, err := c.compilePredicate(rightSelect.Filter)
+		selectClause = clause
+	} else {
+		columns, err := joinColumnExprs(*left, *right)
 		if err != nil {
-			return "", nil, fmt.Errorf("compile right filter: %w", err)
+			return "", nil, err
+		}
+		selectClause, allParams, err = compileProjectionColumns(outputs, columns)
+		if err != nil {
+			return "", nil, err
 		}
-		allParams = append(allParams, rightParams...)
 	}
 
 	// Compile ON predicate
@@ -259,29 +414,411 @@ func (c *SQLCompiler) compileJoin(j queryir.Join) (string, []any, error) {
 		onSQL = "1 = 1" // Cross join (no condition)
 	}
 
-	// Build JOIN SQL
-	sql := fmt.Sprintf("%s INNER JOIN %s ON %s",
-		leftTable,
-		rightTable,
-		onSQL)
+	// Compile per-side filters into the WHERE clause
+	var whereParts []string
+	for _, side := range []*queryir.Select{left, right} {
+		if side.Filter == nil {
+			continue
+		}
+		sql, params, err := c.compileQualifiedPredicate(side.Filter, side.From)
+		if err != nil {
+			return "", nil, fmt.Errorf("compile %s filter: %w", side.From, err)
+		}
+		whereParts = append(whereParts, sql)
+		allParams = append(allParams, params...)
+	}
+
+	var whereClause string
+	if len(whereParts) > 0 {
+		whereClause = " WHERE " + strings.Join(whereParts, " AND ")
+	}
 
 	// MANDATORY: Add ORDER BY per CP-4
-	// For joins, order by first table's primary key
-	sql += " ORDER BY " + leftTable + ".id ASC COLLATE BINARY"
+	// For joins, order by first table's logical clock and primary key
+	stableKey := fmt.Sprintf("%s.seq ASC, %s.id COLLATE BINARY ASC", left.From, left.From)
+	sql := fmt.Sprintf("SELECT %s FROM %s INNER JOIN %s ON %s%s ORDER BY %s",
+		selectClause,
+		left.From,
+		right.From,
+		onSQL,
+		whereClause,
+		orderByTerms(orderTerms, stableKey))
 
 	return sql, allParams, nil
 }
 
-// getSelectFrom extracts the table name from a Query if it's a Select.
-func getSelectFrom(q queryir.Query) (string, bool) {
+// compileJoinBindings builds the SELECT column list for a join.
+// Each column is qualified by its table and aliased to its bound variable.
+// Returns an error if both sides bind the same variable name.
+func (c *SQLCompiler) compileJoinBindings(left, right queryir.Select) (string, error) {
+	if len(left.Bindings) == 0 && len(right.Bindings) == 0 {
+		return "*", nil
+	}
+
+	seen := make(map[string]string)
+	var parts []string
+	for _, side := range []queryir.Select{left, right} {
+		keys := make([]string, 0, len(side.Bindings))
+		for k := range side.Bindings {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, sourceField := range keys {
+			boundVar := side.Bindings[sourceField]
+			if table, dup := seen[boundVar]; dup {
+				return "", fmt.Errorf("join binds %q on both %s and %s", boundVar, table, side.From)
+			}
+			seen[boundVar] = side.From
+			parts = append(parts, fmt.Sprintf("%s AS %s", qualifyColumn(side.From, sourceField), boundVar))
+		}
+	}
+
+	return strings.Join(parts, ", "), nil
+}
+
+// compileProjection compiles a queryir.Projection by replacing the child's
+// SELECT list with aliased columns: renames become "<column> AS <output>"
+// and constants become "? AS <output>" (parameterized per HIGH-3). The
+// child's FROM, WHERE, ORDER BY (CP-4) and LIMIT are kept as-is.
+//
+// The child must be Select or Join for MVP. Every renamed output must
+// refer to a variable the child binds.
+func (c *SQLCompiler) compileProjection(p queryir.Projection) (string, []any, error) {
+	return c.compileOrderedProjection(p, nil)
+}
+
+// compileOrderedProjection compiles a Projection with orderTerms (from
+// OrderBy) placed ahead of the mandatory tiebreak.
+func (c *SQLCompiler) compileOrderedProjection(p queryir.Projection, orderTerms []string) (string, []any, error) {
+	if len(p.Outputs) == 0 {
+		return "", nil, fmt.Errorf("projection requires at least one output")
+	}
+
+	switch child := p.Query.(type) {
+	case queryir.Select, *queryir.Select:
+		sel := getSelect(child)
+		columns := make(map[string]string, len(sel.Bindings))
+		for sourceField, boundVar := range sel.Bindings {
+			columns[boundVar] = sourceField
+		}
+		selectClause, params, err := compileProjectionColumns(p.Outputs, columns)
+		if err != nil {
+			return "", nil, err
+		}
+		return c.compileSelectColumns(*sel, selectClause, params, orderTerms)
+	case queryir.Join:
+		return c.compileJoinColumns(child, p.Outputs, orderTerms)
+	case *queryir.Join:
+		return c.compileJoinColumns(*child, p.Outputs, orderTerms)
+	default:
+		return "", nil, fmt.Errorf("projection child must be Select or Join for MVP, got %T", p.Query)
+	}
+}
+
+// compileProjectionColumns builds a projection's SELECT list from the
+// child's columns (bound variable → column expression). Outputs are emitted
+// sorted by name for deterministic output. Returns the parameters for
+// constant outputs in placeholder order.
+func compileProjectionColumns(outputs map[string]queryir.ProjectionSource, columns map[string]string) (string, []any, error) {
+	names := make([]string, 0, len(outputs))
+	for name := range outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var parts []string
+	var params []any
+	for _, name := range names {
+		src := outputs[name]
+		if src.Field != "" {
+			column, ok := columns[src.Field]
+			if !ok {
+				return "", nil, fmt.Errorf("projection output %s: field %q is not bound by the child query", name, src.Field)
+			}
+			if column == name {
+				parts = append(parts, column)
+			} else {
+				parts = append(parts, fmt.Sprintf("%s AS %s", column, name))
+			}
+			continue
+		}
+
+		switch src.Value.(type) {
+		case nil:
+			return "", nil, fmt.Errorf("projection output %s: requires a source field or a value", name)
+		case ir.IRNull:
+			return "", nil, fmt.Errorf("projection output %s: value must not be null", name)
+		}
+		param, err := irValueToParam(src.Value)
+		if err != nil {
+			return "", nil, fmt.Errorf("projection output %s: %w", name, err)
+		}
+		parts = append(parts, "? AS "+name)
+		params = append(params, param)
+	}
+
+	return strings.Join(parts, ", "), params, nil
+}
+
+// joinColumnExprs maps each variable bound by a join to its table-qualified
+// column. Returns an error if both sides bind the same variable name.
+func joinColumnExprs(left, right queryir.Select) (map[string]string, error) {
+	columns := make(map[string]string, len(left.Bindings)+len(right.Bindings))
+	owner := make(map[string]string, len(columns))
+	for _, side := range []queryir.Select{left, right} {
+		for sourceField, boundVar := range side.Bindings {
+			if table, dup := owner[boundVar]; dup {
+				return nil, fmt.Errorf("join binds %q on both %s and %s", boundVar, table, side.From)
+			}
+			owner[boundVar] = side.From
+			columns[boundVar] = qualifyColumn(side.From, sourceField)
+		}
+	}
+	return columns, nil
+}
+
+// compileUnion compiles a queryir.Union to SQL UNION.
+//
+// Both sides must be Select for MVP and must bind identical variable names.
+// Columns in each branch are ordered by variable name because UNION is
+// positional. Each branch also carries its seq and id under internal
+// aliases so the outer query can impose a single deterministic order.
+//
+// MANDATORY: Includes ORDER BY per CP-4.
+func (c *SQLCompiler) compileUnion(u queryir.Union) (string, []any, error) {
+	return c.compileOrderedUnion(u, nil)
+}
+
+// compileOrderedUnion compiles a Union with orderTerms (from OrderBy) placed
+// ahead of the mandatory tiebreak in the outer ORDER BY.
+func (c *SQLCompiler) compileOrderedUnion(u queryir.Union, orderTerms []string) (string, []any, error) {
+	left := getSelect(u.Left)
+	if left == nil {
+		return "", nil, fmt.Errorf("union left must be Select for MVP")
+	}
+	right := getSelect(u.Right)
+	if right == nil {
+		return "", nil, fmt.Errorf("union right must be Select for MVP")
+	}
+
+	if left.Limit != 0 || right.Limit != 0 {
+		return "", nil, fmt.Errorf("limit is not supported on union branches")
+	}
+
+	leftVars := boundVarNames(left.Bindings)
+	rightVars := boundVarNames(right.Bindings)
+	if len(leftVars) == 0 || len(rightVars) == 0 {
+		return "", nil, fmt.Errorf("union branches require explicit bindings")
+	}
+	if !slices.Equal(leftVars, rightVars) {
+		return "", nil, fmt.Errorf("union branches bind different variables: %v vs %v", leftVars, rightVars)
+	}
+
+	leftSQL, leftParams, err := c.compileUnionBranch(*left)
+	if err != nil {
+		return "", nil, fmt.Errorf("compile union left: %w", err)
+	}
+	rightSQL, rightParams, err := c.compileUnionBranch(*right)
+	if err != nil {
+		return "", nil, fmt.Errorf("compile union right: %w", err)
+	}
+
+	// MANDATORY: Outer ORDER BY per CP-4 across both branches
+	stableKey := fmt.Sprintf("%s ASC, %s COLLATE BINARY ASC", unionSeqColumn, unionIDColumn)
+	sql := fmt.Sprintf("SELECT %s FROM (%s UNION %s) ORDER BY %s",
+		strings.Join(leftVars, ", "),
+		leftSQL,
+		rightSQL,
+		orderByTerms(orderTerms, stableKey))
+
+	params := append(leftParams, rightParams...)
+	return sql, params, nil
+}
+
+// Internal column aliases carried through union branches for ordering.
+// Prefixed to avoid colliding with user-bound variable names.
+const (
+	unionSeqColumn = "__seq"
+	unionIDColumn  = "__id"
+)
+
+// compileOrderBy compiles a queryir.OrderBy by prepending its keys to the
+// child's ORDER BY. The child's mandatory seq, id tiebreak (CP-4) is kept
+// after the keys, so rows with equal keys are still ordered deterministically.
+//
+// Keys reference the child's bound variables, which are the column aliases
+// of the child's SELECT list. The child must be Select, Join, Union or
+// Projection for MVP.
+func (c *SQLCompiler) compileOrderBy(o queryir.OrderBy) (string, []any, error) {
+	if len(o.Keys) == 0 {
+		return "", nil, fmt.Errorf("order by requires at least one key")
+	}
+
+	vars, err := orderableVars(o.Query)
+	if err != nil {
+		return "", nil, err
+	}
+	bound := make(map[string]bool, len(vars))
+	for _, v := range vars {
+		bound[v] = true
+	}
+
+	terms := make([]string, 0, len(o.Keys))
+	for _, key := range o.Keys {
+		if !bound[key.Field] {
+			return "", nil, fmt.Errorf("order by %s: field is not bound by the child query", key.Field)
+		}
+		switch key.Direction {
+		case "", queryir.Ascending:
+			terms = append(terms, key.Field+" ASC")
+		case queryir.Descending:
+			terms = append(terms, key.Field+" DESC")
+		default:
+			return "", nil, fmt.Errorf("order by %s: unknown direction %q", key.Field, key.Direction)
+		}
+	}
+
+	switch child := o.Query.(type) {
+	case queryir.Select, *queryir.Select:
+		sel := getSelect(child)
+		return c.compileSelectColumns(*sel, c.compileBindings(sel.Bindings), nil, terms)
+	case queryir.Join:
+		return c.compileJoinColumns(child, nil, terms)
+	case *queryir.Join:
+		return c.compileJoinColumns(*child, nil, terms)
+	case queryir.Union:
+		return c.compileOrderedUnion(child, terms)
+	case *queryir.Union:
+		return c.compileOrderedUnion(*child, terms)
+	case queryir.Projection:
+		return c.compileOrderedProjection(child, terms)
+	case *queryir.Projection:
+		return c.compileOrderedProjection(*child, terms)
+	default:
+		return "", nil, fmt.Errorf("order by child must be Select, Join, Union or Projection for MVP, got %T", o.Query)
+	}
+}
+
+// orderableVars returns the variables an OrderBy child binds, i.e. the
+// aliases its SELECT list exposes.
+func orderableVars(q queryir.Query) ([]string, error) {
 	switch query := q.(type) {
-	case queryir.Select:
-		return query.From, true
-	case *queryir.Select:
-		return query.From, true
+	case queryir.Select, *queryir.Select:
+		return boundVarNames(getSelect(query).Bindings), nil
+	case queryir.Join:
+		return joinVars(query)
+	case *queryir.Join:
+		return joinVars(*query)
+	case queryir.Union:
+		return orderableVars(query.Left)
+	case *queryir.Union:
+		return orderableVars(query.Left)
+	case queryir.Projection:
+		return projectionVars(query), nil
+	case *queryir.Projection:
+		return projectionVars(*query), nil
 	default:
-		return "", false
+		return nil, fmt.Errorf("order by child must be Select, Join, Union or Projection for MVP, got %T", q)
+	}
+}
+
+// joinVars returns the variables bound by either side of a join.
+func joinVars(j queryir.Join) ([]string, error) {
+	left, right := getSelect(j.Left), getSelect(j.Right)
+	if left == nil || right == nil {
+		return nil, fmt.Errorf("join sides must be Select for MVP")
+	}
+	return append(boundVarNames(left.Bindings), boundVarNames(right.Bindings)...), nil
+}
+
+// projectionVars returns a projection's output names.
+func projectionVars(p queryir.Projection) []string {
+	vars := make([]string, 0, len(p.Outputs))
+	for name := range p.Outputs {
+		vars = append(vars, name)
+	}
+	return vars
+}
+
+// orderByTerms joins OrderBy terms with the mandatory stable key (CP-4),
+// which always comes last.
+func orderByTerms(terms []string, stableKey string) string {
+	return strings.Join(append(slices.Clip(terms), stableKey), ", ")
+}
+
+// compileCount compiles a queryir.Count to SELECT COUNT(*).
+//
+// An aggregate without GROUP BY yields exactly one row, so no ORDER BY is
+// needed for determinism (CP-4 is trivially satisfied).
+func (c *SQLCompiler) compileCount(q queryir.Count) (string, []any, error) {
+	if q.Select.Limit != 0 {
+		return "", nil, fmt.Errorf("limit is not supported inside count")
+	}
+
+	var whereClause string
+	var params []any
+	if q.Select.Filter != nil {
+		filterSQL, filterParams, err := c.compilePredicate(q.Select.Filter)
+		if err != nil {
+			return "", nil, fmt.Errorf("compile filter: %w", err)
+		}
+		whereClause = " WHERE " + filterSQL
+		params = filterParams
+	}
+
+	sql := fmt.Sprintf("SELECT COUNT(*) AS %s FROM %s%s", queryir.CountBinding, q.Select.From, whereClause)
+	return sql, params, nil
+}
+
+// compileUnionBranch compiles one side of a union without ORDER BY.
+// Columns are emitted in bound-variable order, followed by seq and id.
+func (c *SQLCompiler) compileUnionBranch(q queryir.Select) (string, []any, error) {
+	bySourceVar := make(map[string]string, len(q.Bindings))
+	for sourceField, boundVar := range q.Bindings {
+		bySourceVar[boundVar] = sourceField
+	}
+
+	var cols []string
+	for _, boundVar := range boundVarNames(q.Bindings) {
+		cols = append(cols, fmt.Sprintf("%s AS %s", bySourceVar[boundVar], boundVar))
+	}
+	cols = append(cols,
+		"seq AS "+unionSeqColumn,
+		"id AS "+unionIDColumn)
+
+	var whereClause string
+	var params []any
+	if q.Filter != nil {
+		filterSQL, filterParams, err := c.compilePredicate(q.Filter)
+		if err != nil {
+			return "", nil, fmt.Errorf("compile filter: %w", err)
+		}
+		whereClause = " WHERE " + filterSQL
+		params = filterParams
+	}
+
+	sql := fmt.Sprintf("SELECT %s FROM %s%s", strings.Join(cols, ", "), q.From, whereClause)
+	return sql, params, nil
+}
+
+// boundVarNames returns the bound variable names of a bindings map, sorted.
+func boundVarNames(bindings map[string]string) []string {
+	vars := make([]string, 0, len(bindings))
+	for _, boundVar := range bindings {
+		vars = append(vars, boundVar)
+	}
+	sort.Strings(vars)
+	return vars
+}
+
+// qualifyColumn prefixes a field with its table name.
+// Fields that are already qualified, or an empty table, are returned as-is.
+func qualifyColumn(table, field string) string {
+	if table == "" || strings.Contains(field, ".") {
+		return field
 	}
+	return table + "." + field
 }
 
 // getSelect extracts the Select from a Query if it's a Select.
@@ -544,7 +544,7 @@ func TestCompile_GoldenSQL(t *testing.T) {
 				Bindings: map[string]string{"name": "item"},
 				Filter:   queryir.Equals{Field: "category", Value: ir.IRString("widgets")},
 			},
-			wantSQL:    "SELECT name AS item FROM inventory WHERE category = ? ORDER BY id ASC COLLATE BINARY",
+			wantSQL:    "SELECT name AS item FROM inventory WHERE category = ? ORDER BY seq ASC, id COLLATE BINARY ASC",
 			wantParams: []any{"widgets"},
 		},
 		{
@@ -558,7 +558,7 @@ func TestCompile_GoldenSQL(t *testing.T) {
 					},
 				},
 			},
-			wantSQL:    "SELECT * FROM inventory WHERE category = ? AND in_stock = ? ORDER BY id ASC COLLATE BINARY",
+			wantSQL:    "SELECT * FROM inventory WHERE category = ? AND in_stock = ? ORDER BY seq ASC, id COLLATE BINARY ASC",
 			wantParams: []any{"widgets", true},
 		},
 		{
@@ -567,7 +567,7 @@ func TestCompile_GoldenSQL(t *testing.T) {
 				From:     "inventory",
 				Bindings: map[string]string{"id": "id"},
 			},
-			wantSQL:    "SELECT id FROM inventory ORDER BY id ASC COLLATE BINARY",
+			wantSQL:    "SELECT id FROM inventory ORDER BY seq ASC, id COLLATE BINARY ASC",
 			wantParams: nil,
 		},
 	}
@@ -659,3 +659,528 @@ func TestIRValueToParam(t *testing.T) {
 		})
 	}
 }
+
+func TestCompile_Comparisons(t *testing.T) {
+	tests := []struct {
+		name      string
+		pred      queryir.Predicate
+		wantWhere string
+	}{
+		{"greater than", queryir.GreaterThan{Field: "quantity", Value: ir.IRInt(5)}, "quantity > ?"},
+		{"less than", queryir.LessThan{Field: "quantity", Value: ir.IRInt(5)}, "quantity < ?"},
+		{"greater or equal", queryir.GreaterOrEqual{Field: "quantity", Value: ir.IRInt(5)}, "quantity >= ?"},
+		{"less or equal", queryir.LessOrEqual{Field: "quantity", Value: ir.IRInt(5)}, "quantity <= ?"},
+		{"pointer", &queryir.GreaterThan{Field: "quantity", Value: ir.IRInt(5)}, "quantity > ?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compiler := NewSQLCompiler()
+			sql, params, err := compiler.Compile(queryir.Select{
+				From:     "inventory",
+				Filter:   tt.pred,
+				Bindings: map[string]string{"item_id": "item"},
+			})
+			require.NoError(t, err)
+
+			assert.Contains(t, sql, "WHERE "+tt.wantWhere+" ORDER BY")
+			assert.Equal(t, []any{int64(5)}, params, "value must be parameterized (HIGH-3)")
+		})
+	}
+}
+
+func TestCompile_ComparisonBoundVar(t *testing.T) {
+	compiler := NewSQLCompiler()
+	compiler.BoundValues["bound.min"] = int64(3)
+
+	sql, params, err := compiler.Compile(queryir.Select{
+		From:   "inventory",
+		Filter: queryir.GreaterOrEqual{Field: "quantity", BoundVar: "bound.min"},
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, sql, "quantity >= ?")
+	assert.Equal(t, []any{int64(3)}, params)
+}
+
+func TestCompile_ComparisonBoundVarMustBeInt(t *testing.T) {
+	compiler := NewSQLCompiler()
+	compiler.BoundValues["bound.min"] = "three"
+
+	_, _, err := compiler.Compile(queryir.Select{
+		From:   "inventory",
+		Filter: queryir.GreaterThan{Field: "quantity", BoundVar: "bound.min"},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must be an integer")
+}
+
+// TestCompile_PredicateExhaustive guards the backend type switch: every
+// sealed Predicate type (value and pointer form) must compile.
+func TestCompile_PredicateExhaustive(t *testing.T) {
+	predicates := []queryir.Predicate{
+		queryir.Equals{Field: "f", Value: ir.IRInt(1)},
+		&queryir.Equals{Field: "f", Value: ir.IRInt(1)},
+		queryir.BoundEquals{Field: "f", BoundVar: "bound.v"},
+		&queryir.BoundEquals{Field: "f", BoundVar: "bound.v"},
+		queryir.NotEquals{Field: "f", Value: ir.IRInt(1)},
+		&queryir.NotEquals{Field: "f", BoundVar: "bound.v"},
+		queryir.And{},
+		&queryir.And{},
+		queryir.GreaterThan{Field: "f", Value: ir.IRInt(1)},
+		&queryir.GreaterThan{Field: "f", Value: ir.IRInt(1)},
+		queryir.LessThan{Field: "f", Value: ir.IRInt(1)},
+		&queryir.LessThan{Field: "f", Value: ir.IRInt(1)},
+		queryir.GreaterOrEqual{Field: "f", Value: ir.IRInt(1)},
+		&queryir.GreaterOrEqual{Field: "f", Value: ir.IRInt(1)},
+		queryir.LessOrEqual{Field: "f", Value: ir.IRInt(1)},
+		&queryir.LessOrEqual{Field: "f", Value: ir.IRInt(1)},
+		queryir.In{Field: "f", Values: []ir.IRValue{ir.IRInt(1)}},
+		&queryir.In{Field: "f", Values: []ir.IRValue{ir.IRInt(1)}},
+	}
+
+	for _, p := range predicates {
+		compiler := NewSQLCompiler()
+		compiler.BoundValues["bound.v"] = int64(1)
+		_, _, err := compiler.Compile(queryir.Select{From: "t", Filter: p})
+		assert.NoError(t, err, "backend must handle %T", p)
+	}
+}
+
+func TestCompile_NotEquals(t *testing.T) {
+	compiler := NewSQLCompiler()
+
+	sql, params, err := compiler.Compile(queryir.Select{
+		From:     "orders",
+		Filter:   queryir.NotEquals{Field: "status", Value: ir.IRString("archived")},
+		Bindings: map[string]string{"id": "orderId"},
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, sql, "WHERE status <> ? ORDER BY")
+	assert.Equal(t, []any{"archived"}, params, "value must be parameterized (HIGH-3)")
+}
+
+func TestCompile_NotEqualsBoundVar(t *testing.T) {
+	compiler := NewSQLCompiler()
+	compiler.BoundValues["bound.status"] = "archived"
+
+	sql, params, err := compiler.Compile(queryir.Select{
+		From:   "orders",
+		Filter: queryir.NotEquals{Field: "status", Value: ir.IRString("ignored"), BoundVar: "bound.status"},
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, sql, "status <> ?")
+	assert.Equal(t, []any{"archived"}, params)
+}
+
+func TestCompile_NotEqualsNullError(t *testing.T) {
+	compiler := NewSQLCompiler()
+
+	_, _, err := compiler.Compile(queryir.Select{
+		From:   "orders",
+		Filter: queryir.NotEquals{Field: "status", Value: ir.IRNull{}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "null")
+}
+
+func TestCompile_InStrings(t *testing.T) {
+	compiler := NewSQLCompiler()
+
+	sql, params, err := compiler.Compile(queryir.Select{
+		From: "orders",
+		Filter: queryir.In{Field: "status", Values: []ir.IRValue{
+			ir.IRString("pending"),
+			ir.IRString("held"),
+		}},
+		Bindings: map[string]string{"id": "orderId"},
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, sql, "WHERE status IN (?, ?) ORDER BY")
+	assert.Equal(t, []any{"pending", "held"}, params)
+}
+
+func TestCompile_InInts(t *testing.T) {
+	compiler := NewSQLCompiler()
+
+	sql, params, err := compiler.Compile(queryir.Select{
+		From: "orders",
+		Filter: queryir.In{Field: "priority", Values: []ir.IRValue{
+			ir.IRInt(1),
+			ir.IRInt(2),
+			ir.IRInt(3),
+		}},
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, sql, "priority IN (?, ?, ?)")
+	assert.Equal(t, []any{int64(1), int64(2), int64(3)}, params)
+}
+
+func TestCompile_InEmptySetError(t *testing.T) {
+	compiler := NewSQLCompiler()
+
+	_, _, err := compiler.Compile(queryir.Select{
+		From:   "orders",
+		Filter: queryir.In{Field: "status", Values: nil},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must not be empty")
+}
+
+func TestCompile_InNullElementError(t *testing.T) {
+	compiler := NewSQLCompiler()
+
+	_, _, err := compiler.Compile(queryir.Select{
+		From:   "orders",
+		Filter: queryir.In{Field: "status", Values: []ir.IRValue{ir.IRNull{}}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "null")
+}
+
+func TestCompile_Union(t *testing.T) {
+	compiler := NewSQLCompiler()
+
+	query := queryir.Union{
+		Left: queryir.Select{
+			From:     "orders",
+			Filter:   queryir.Equals{Field: "status", Value: ir.IRString("pending")},
+			Bindings: map[string]string{"id": "orderId", "total": "amount"},
+		},
+		Right: queryir.Select{
+			From:     "archived_orders",
+			Filter:   queryir.Equals{Field: "status", Value: ir.IRString("held")},
+			Bindings: map[string]string{"order_total": "amount", "order_id": "orderId"},
+		},
+	}
+
+	sql, params, err := compiler.Compile(query)
+	require.NoError(t, err)
+
+	// Branch columns ordered by variable name (UNION is positional)
+	assert.Equal(t,
+		"SELECT amount, orderId FROM ("+
+			"SELECT total AS amount, id AS orderId, seq AS __seq, id AS __id FROM orders WHERE status = ? "+
+			"UNION "+
+			"SELECT order_total AS amount, order_id AS orderId, seq AS __seq, id AS __id FROM archived_orders WHERE status = ?"+
+			") ORDER BY __seq ASC, __id COLLATE BINARY ASC",
+		sql)
+	assert.Equal(t, []any{"pending", "held"}, params)
+}
+
+func TestCompile_UnionMismatchedBindings(t *testing.T) {
+	compiler := NewSQLCompiler()
+
+	query := &queryir.Union{
+		Left:  queryir.Select{From: "orders", Bindings: map[string]string{"id": "orderId"}},
+		Right: queryir.Select{From: "orders", Bindings: map[string]string{"id": "id"}},
+	}
+
+	_, _, err := compiler.Compile(query)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bind different variables")
+}
+
+func TestCompile_UnionRequiresExplicitBindings(t *testing.T) {
+	compiler := NewSQLCompiler()
+
+	_, _, err := compiler.Compile(queryir.Union{
+		Left:  queryir.Select{From: "orders"},
+		Right: queryir.Select{From: "orders"},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "explicit bindings")
+}
+
+func TestCompile_Limit(t *testing.T) {
+	compiler := NewSQLCompiler()
+
+	sql, params, err := compiler.Compile(queryir.Select{
+		From:     "inventory",
+		Filter:   queryir.Equals{Field: "category", Value: ir.IRString("widgets")},
+		Bindings: map[string]string{"id": "id"},
+		Limit:    2,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "SELECT id FROM inventory WHERE category = ? ORDER BY seq ASC, id COLLATE BINARY ASC LIMIT ?", sql)
+	assert.Equal(t, []any{"widgets", int64(2)}, params)
+}
+
+func TestCompile_NegativeLimit(t *testing.T) {
+	compiler := NewSQLCompiler()
+
+	_, _, err := compiler.Compile(queryir.Select{From: "inventory", Limit: -1})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "limit must be non-negative")
+}
+
+func TestCompile_LimitOnJoinSideRejected(t *testing.T) {
+	compiler := NewSQLCompiler()
+
+	_, _, err := compiler.Compile(queryir.Join{
+		Left:  queryir.Select{From: "orders", Limit: 1},
+		Right: queryir.Select{From: "customers"},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "limit is not supported")
+}
+
+func TestCompile_Count(t *testing.T) {
+	compiler := NewSQLCompiler()
+
+	sql, params, err := compiler.Compile(&queryir.Count{Select: queryir.Select{
+		From:     "reservations",
+		Filter:   queryir.Equals{Field: "item_id", Value: ir.IRString("widget")},
+		Bindings: map[string]string{"id": "ignored"},
+	}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "SELECT COUNT(*) AS count FROM reservations WHERE item_id = ?", sql)
+	assert.Equal(t, []any{"widget"}, params)
+}
+
+func TestCompile_ProjectionRename(t *testing.T) {
+	compiler := NewSQLCompiler()
+
+	sql, params, err := compiler.Compile(queryir.Projection{
+		Query: queryir.Select{
+			From:     "cart_items",
+			Filter:   queryir.Equals{Field: "cart_id", Value: ir.IRString("cart-1")},
+			Bindings: map[string]string{"item_id": "itemId", "quantity": "qty"},
+		},
+		Outputs: map[string]queryir.ProjectionSource{
+			"sku": {Field: "itemId"},
+			"qty": {Field: "qty"},
+		},
+	})
+	require.NoError(t, err)
+
+	// Unprojected bindings are dropped; an unchanged name needs no alias
+	assert.Equal(t, "SELECT quantity AS qty, item_id AS sku FROM cart_items WHERE cart_id = ? ORDER BY seq ASC, id COLLATE BINARY ASC", sql)
+	assert.Equal(t, []any{"cart-1"}, params)
+}
+
+func TestCompile_ProjectionConstant(t *testing.T) {
+	compiler := NewSQLCompiler()
+
+	sql, params, err := compiler.Compile(&queryir.Projection{
+		Query: queryir.Select{
+			From:     "cart_items",
+			Filter:   queryir.Equals{Field: "cart_id", Value: ir.IRString("cart-1")},
+			Bindings: map[string]string{"item_id": "itemId"},
+			Limit:    3,
+		},
+		Outputs: map[string]queryir.ProjectionSource{
+			"itemId":   {Field: "itemId"},
+			"priority": {Value: ir.IRInt(1)},
+			"source":   {Value: ir.IRString("cart")},
+		},
+	})
+	require.NoError(t, err)
+
+	// Constant parameters come first, matching placeholder order
+	assert.Equal(t, "SELECT item_id AS itemId, ? AS priority, ? AS source FROM cart_items WHERE cart_id = ? ORDER BY seq ASC, id COLLATE BINARY ASC LIMIT ?", sql)
+	assert.Equal(t, []any{int64(1), "cart", "cart-1", int64(3)}, params)
+}
+
+func TestCompile_ProjectionOverJoin(t *testing.T) {
+	compiler := NewSQLCompiler()
+
+	sql, params, err := compiler.Compile(queryir.Projection{
+		Query: queryir.Join{
+			Left:  queryir.Select{From: "cart_items", Bindings: map[string]string{"item_id": "itemId"}},
+			Right: queryir.Select{From: "inventory", Bindings: map[string]string{"available": "stock"}},
+			On:    queryir.Equals{Field: "cart_items.status", Value: ir.IRString("active")},
+		},
+		Outputs: map[string]queryir.ProjectionSource{
+			"sku":   {Field: "itemId"},
+			"label": {Value: ir.IRString("join")},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "SELECT ? AS label, cart_items.item_id AS sku FROM cart_items INNER JOIN inventory ON cart_items.status = ? "+
+		"ORDER BY cart_items.seq ASC, cart_items.id COLLATE BINARY ASC", sql)
+	assert.Equal(t, []any{"join", "active"}, params)
+}
+
+func TestCompile_ProjectionInvalidSourceField(t *testing.T) {
+	compiler := NewSQLCompiler()
+
+	_, _, err := compiler.Compile(queryir.Projection{
+		Query: queryir.Select{From: "cart_items", Bindings: map[string]string{"item_id": "itemId"}},
+		Outputs: map[string]queryir.ProjectionSource{
+			"qty": {Field: "quantity"},
+		},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `projection output qty: field "quantity" is not bound by the child query`)
+}
+
+func TestCompile_ProjectionRejected(t *testing.T) {
+	child := queryir.Select{From: "cart_items", Bindings: map[string]string{"item_id": "itemId"}}
+
+	tests := []struct {
+		name    string
+		query   queryir.Projection
+		wantErr string
+	}{
+		{
+			name:    "no outputs",
+			query:   queryir.Projection{Query: child},
+			wantErr: "at least one output",
+		},
+		{
+			name:    "null constant",
+			query:   queryir.Projection{Query: child, Outputs: map[string]queryir.ProjectionSource{"x": {Value: ir.IRNull{}}}},
+			wantErr: "must not be null",
+		},
+		{
+			name:    "empty source",
+			query:   queryir.Projection{Query: child, Outputs: map[string]queryir.ProjectionSource{"x": {}}},
+			wantErr: "requires a source field or a value",
+		},
+		{
+			name: "count child",
+			query: queryir.Projection{
+				Query:   queryir.Count{Select: child},
+				Outputs: map[string]queryir.ProjectionSource{"n": {Field: "count"}},
+			},
+			wantErr: "projection child must be Select or Join",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := NewSQLCompiler().Compile(tt.query)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestCompile_OrderBy(t *testing.T) {
+	compiler := NewSQLCompiler()
+
+	sql, params, err := compiler.Compile(queryir.OrderBy{
+		Query: queryir.Select{
+			From:     "cart_items",
+			Filter:   queryir.Equals{Field: "cart_id", Value: ir.IRString("cart-1")},
+			Bindings: map[string]string{"item_id": "itemId", "quantity": "qty"},
+			Limit:    2,
+		},
+		Keys: []queryir.OrderKey{
+			{Field: "qty", Direction: queryir.Descending},
+			{Field: "itemId"},
+		},
+	})
+	require.NoError(t, err)
+
+	// Keys come first; the CP-4 tiebreak is still last, before LIMIT
+	assert.Equal(t, "SELECT item_id AS itemId, quantity AS qty FROM cart_items WHERE cart_id = ? "+
+		"ORDER BY qty DESC, itemId ASC, seq ASC, id COLLATE BINARY ASC LIMIT ?", sql)
+	assert.Equal(t, []any{"cart-1", int64(2)}, params)
+}
+
+func TestCompile_OrderByChildren(t *testing.T) {
+	keys := []queryir.OrderKey{{Field: "sku", Direction: queryir.Ascending}}
+
+	tests := []struct {
+		name  string
+		query queryir.Query
+		want  string
+	}{
+		{
+			name: "join",
+			query: &queryir.OrderBy{
+				Query: queryir.Join{
+					Left:  queryir.Select{From: "cart_items", Bindings: map[string]string{"item_id": "sku"}},
+					Right: queryir.Select{From: "inventory", Bindings: map[string]string{"available": "stock"}},
+				},
+				Keys: keys,
+			},
+			want: "SELECT cart_items.item_id AS sku, inventory.available AS stock FROM cart_items INNER JOIN inventory ON 1 = 1 " +
+				"ORDER BY sku ASC, cart_items.seq ASC, cart_items.id COLLATE BINARY ASC",
+		},
+		{
+			name: "union",
+			query: queryir.OrderBy{
+				Query: queryir.Union{
+					Left:  queryir.Select{From: "cart_items", Bindings: map[string]string{"item_id": "sku"}},
+					Right: queryir.Select{From: "inventory", Bindings: map[string]string{"item_id": "sku"}},
+				},
+				Keys: keys,
+			},
+			want: "SELECT sku FROM (SELECT item_id AS sku, seq AS __seq, id AS __id FROM cart_items UNION " +
+				"SELECT item_id AS sku, seq AS __seq, id AS __id FROM inventory) ORDER BY sku ASC, __seq ASC, __id COLLATE BINARY ASC",
+		},
+		{
+			name: "projection",
+			query: queryir.OrderBy{
+				Query: queryir.Projection{
+					Query:   queryir.Select{From: "cart_items", Bindings: map[string]string{"item_id": "itemId"}},
+					Outputs: map[string]queryir.ProjectionSource{"sku": {Field: "itemId"}},
+				},
+				Keys: keys,
+			},
+			want: "SELECT item_id AS sku FROM cart_items ORDER BY sku ASC, seq ASC, id COLLATE BINARY ASC",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, _, err := NewSQLCompiler().Compile(tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, sql)
+		})
+	}
+}
+
+func TestCompile_OrderByRejected(t *testing.T) {
+	child := queryir.Select{From: "cart_items", Bindings: map[string]string{"item_id": "itemId"}}
+
+	tests := []struct {
+		name    string
+		query   queryir.OrderBy
+		wantErr string
+	}{
+		{
+			name:    "no keys",
+			query:   queryir.OrderBy{Query: child},
+			wantErr: "at least one key",
+		},
+		{
+			name:    "unbound field",
+			query:   queryir.OrderBy{Query: child, Keys: []queryir.OrderKey{{Field: "quantity"}}},
+			wantErr: "order by quantity: field is not bound by the child query",
+		},
+		{
+			name:    "source column instead of binding",
+			query:   queryir.OrderBy{Query: child, Keys: []queryir.OrderKey{{Field: "item_id"}}},
+			wantErr: "order by item_id: field is not bound by the child query",
+		},
+		{
+			name:    "unknown direction",
+			query:   queryir.OrderBy{Query: child, Keys: []queryir.OrderKey{{Field: "itemId", Direction: "sideways"}}},
+			wantErr: `unknown direction "sideways"`,
+		},
+		{
+			name:    "count child",
+			query:   queryir.OrderBy{Query: queryir.Count{Select: child}, Keys: []queryir.OrderKey{{Field: "count"}}},
+			wantErr: "order by child must be Select, Join, Union or Projection",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := NewSQLCompiler().Compile(tt.query)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
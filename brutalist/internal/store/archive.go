@@ -0,0 +1,343 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+// FlowBundle is a portable, self-contained export of a single flow.
+// It serializes to JSON and round-trips losslessly through ImportFlow:
+// content-addressed IDs, store IDs, and seq values are preserved.
+type FlowBundle struct {
+	FlowToken       string              `json:"flow_token"`
+	Invocations     []ir.Invocation     `json:"invocations"`
+	Completions     []ir.Completion     `json:"completions"`
+	SyncFirings     []ir.SyncFiring     `json:"sync_firings"`
+	ProvenanceEdges []ir.ProvenanceEdge `json:"provenance_edges"`
+
+	// AbandonedFirings records orphaned firings resolved by crash recovery,
+	// so an imported flow does not report them as orphaned again.
+	AbandonedFirings []AbandonedFiring `json:"abandoned_firings"`
+
+	// ScheduledFirings are deferred firings (ThenClause.AfterSteps) not yet
+	// released, so an imported flow stays incomplete and the engine still
+	// releases them after a restart.
+	ScheduledFirings []ScheduledFiring `json:"scheduled_firings"`
+}
+
+// AbandonedFiring is an orphaned sync firing that recovery could not complete.
+type AbandonedFiring struct {
+	SyncFiringID int64  `json:"sync_firing_id"`
+	Reason       string `json:"reason"`
+}
+
+// ArchiveFlow exports every record belonging to a flow as a FlowBundle.
+// All slices are ordered deterministically per CP-4 (seq ASC, then id ASC),
+// so archiving the same flow twice yields identical bundles.
+//
+// Returns a bundle with empty slices (not nil) if the flow does not exist.
+func (s *Store) ArchiveFlow(ctx context.Context, flowToken string) (FlowBundle, error) {
+	bundle := FlowBundle{FlowToken: flowToken}
+
+	invocations, completions, err := s.ReadFlow(ctx, flowToken)
+	if err != nil {
+		return FlowBundle{}, fmt.Errorf("archive flow: %w", err)
+	}
+	bundle.Invocations = invocations
+	bundle.Completions = completions
+
+	bundle.SyncFirings, err = s.readFlowSyncFirings(ctx, flowToken)
+	if err != nil {
+		return FlowBundle{}, fmt.Errorf("archive flow: %w", err)
+	}
+
+	bundle.ProvenanceEdges, err = s.readFlowProvenanceEdges(ctx, flowToken)
+	if err != nil {
+		return FlowBundle{}, fmt.Errorf("archive flow: %w", err)
+	}
+
+	bundle.AbandonedFirings, err = s.readFlowAbandonedFirings(ctx, flowToken)
+	if err != nil {
+		return FlowBundle{}, fmt.Errorf("archive flow: %w", err)
+	}
+
+	bundle.ScheduledFirings, err = s.readFlowScheduledFirings(ctx, flowToken)
+	if err != nil {
+		return FlowBundle{}, fmt.Errorf("archive flow: %w", err)
+	}
+
+	return bundle, nil
+}
+
+// readFlowSyncFirings returns all sync firings triggered by the flow's completions.
+func (s *Store) readFlowSyncFirings(ctx context.Context, flowToken string) ([]ir.SyncFiring, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT sf.id, sf.completion_id, sf.sync_id, sf.binding_hash, sf.seq
+		FROM sync_firings sf
+		JOIN completions c ON sf.completion_id = c.id
+		JOIN invocations i ON c.invocation_id = i.id
+		WHERE i.flow_token = ?
+		ORDER BY sf.seq ASC, sf.id ASC
+	`, flowToken)
+	if err != nil {
+		return nil, fmt.Errorf("query sync firings: %w", err)
+	}
+	defer rows.Close()
+
+	firings := []ir.SyncFiring{}
+	for rows.Next() {
+		var f ir.SyncFiring
+		if err := rows.Scan(&f.ID, &f.CompletionID, &f.SyncID, &f.BindingHash, &f.Seq); err != nil {
+			return nil, fmt.Errorf("scan sync firing: %w", err)
+		}
+		firings = append(firings, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate sync firings: %w", err)
+	}
+
+	return firings, nil
+}
+
+// readFlowProvenanceEdges returns all provenance edges pointing at the flow's invocations.
+// Ordered by sync_firing.seq ASC, then id ASC, matching ReadAllProvenanceEdges.
+func (s *Store) readFlowProvenanceEdges(ctx context.Context, flowToken string) ([]ir.ProvenanceEdge, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT pe.id, pe.sync_firing_id, pe.invocation_id
+		FROM provenance_edges pe
+		JOIN sync_firings sf ON pe.sync_firing_id = sf.id
+		JOIN invocations i ON pe.invocation_id = i.id
+		WHERE i.flow_token = ?
+		ORDER BY sf.seq ASC, pe.id ASC
+	`, flowToken)
+	if err != nil {
+		return nil, fmt.Errorf("query provenance edges: %w", err)
+	}
+	defer rows.Close()
+
+	edges := []ir.ProvenanceEdge{}
+	for rows.Next() {
+		var e ir.ProvenanceEdge
+		if err := rows.Scan(&e.ID, &e.SyncFiringID, &e.InvocationID); err != nil {
+			return nil, fmt.Errorf("scan provenance edge: %w", err)
+		}
+		edges = append(edges, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate provenance edges: %w", err)
+	}
+
+	return edges, nil
+}
+
+// readFlowAbandonedFirings returns the abandoned firings among the flow's sync firings.
+func (s *Store) readFlowAbandonedFirings(ctx context.Context, flowToken string) ([]AbandonedFiring, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT af.sync_firing_id, af.reason
+		FROM abandoned_firings af
+		JOIN sync_firings sf ON af.sync_firing_id = sf.id
+		JOIN completions c ON sf.completion_id = c.id
+		JOIN invocations i ON c.invocation_id = i.id
+		WHERE i.flow_token = ?
+		ORDER BY sf.seq ASC, sf.id ASC
+	`, flowToken)
+	if err != nil {
+		return nil, fmt.Errorf("query abandoned firings: %w", err)
+	}
+	defer rows.Close()
+
+	abandoned := []AbandonedFiring{}
+	for rows.Next() {
+		var a AbandonedFiring
+		if err := rows.Scan(&a.SyncFiringID, &a.Reason); err != nil {
+			return nil, fmt.Errorf("scan abandoned firing: %w", err)
+		}
+		abandoned = append(abandoned, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate abandoned firings: %w", err)
+	}
+
+	return abandoned, nil
+}
+
+// ImportFlow re-inserts a bundle produced by ArchiveFlow in a single transaction.
+// IDs and seq values are preserved exactly, so replaying the imported flow
+// yields the same events as before it was archived.
+//
+// Unlike the Write* methods, ImportFlow is not idempotent: any record whose ID
+// already exists in the store is a collision and aborts the whole import.
+// Every invocation and scheduled firing must carry the bundle's flow token.
+func (s *Store) ImportFlow(ctx context.Context, bundle FlowBundle) error {
+	for _, inv := range bundle.Invocations {
+		if inv.FlowToken != bundle.FlowToken {
+			return fmt.Errorf("import flow: invocation %s has flow token %q, want %q",
+				inv.ID, inv.FlowToken, bundle.FlowToken)
+		}
+	}
+	for _, f := range bundle.ScheduledFirings {
+		if f.FlowToken != bundle.FlowToken {
+			return fmt.Errorf("import flow: scheduled firing %d has flow token %q, want %q",
+				f.ID, f.FlowToken, bundle.FlowToken)
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("import flow: begin transaction: %w", err)
+	}
+	defer tx.Rollback() // No-op if committed
+
+	// Insert in foreign-key dependency order (reverse of PruneFlow).
+	for _, inv := range bundle.Invocations {
+		if err := checkCollision(ctx, tx, "invocations", inv.ID); err != nil {
+			return fmt.Errorf("import flow: %w", err)
+		}
+
+		argsJSON, err := marshalArgs(inv.Args)
+		if err != nil {
+			return fmt.Errorf("import flow: %w", err)
+		}
+		secCtxJSON, err := marshalSecurityContext(inv.SecurityContext)
+		if err != nil {
+			return fmt.Errorf("import flow: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO invocations
+			(id, flow_token, action_uri, args, seq, security_context, spec_hash, engine_version, ir_version)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`,
+			inv.ID,
+			inv.FlowToken,
+			string(inv.ActionURI),
+			argsJSON,
+			inv.Seq,
+			secCtxJSON,
+			inv.SpecHash,
+			inv.EngineVersion,
+			inv.IRVersion,
+		)
+		if err != nil {
+			return fmt.Errorf("import flow: insert invocation %s: %w", inv.ID, err)
+		}
+	}
+
+	for _, comp := range bundle.Completions {
+		if err := checkCollision(ctx, tx, "completions", comp.ID); err != nil {
+			return fmt.Errorf("import flow: %w", err)
+		}
+
+		resultJSON, err := marshalResult(comp.Result)
+		if err != nil {
+			return fmt.Errorf("import flow: %w", err)
+		}
+		secCtxJSON, err := marshalSecurityContext(comp.SecurityContext)
+		if err != nil {
+			return fmt.Errorf("import flow: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO completions
+			(id, invocation_id, output_case, result, seq, security_context)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`,
+			comp.ID,
+			comp.InvocationID,
+			comp.OutputCase,
+			resultJSON,
+			comp.Seq,
+			secCtxJSON,
+		)
+		if err != nil {
+			return fmt.Errorf("import flow: insert completion %s: %w", comp.ID, err)
+		}
+	}
+
+	for _, f := range bundle.SyncFirings {
+		if err := checkCollision(ctx, tx, "sync_firings", f.ID); err != nil {
+			return fmt.Errorf("import flow: %w", err)
+		}
+
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO sync_firings
+			(id, completion_id, sync_id, binding_hash, seq)
+			VALUES (?, ?, ?, ?, ?)
+		`, f.ID, f.CompletionID, f.SyncID, f.BindingHash, f.Seq)
+		if err != nil {
+			return fmt.Errorf("import flow: insert sync firing %d: %w", f.ID, err)
+		}
+	}
+
+	for _, e := range bundle.ProvenanceEdges {
+		if err := checkCollision(ctx, tx, "provenance_edges", e.ID); err != nil {
+			return fmt.Errorf("import flow: %w", err)
+		}
+
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO provenance_edges
+			(id, sync_firing_id, invocation_id)
+			VALUES (?, ?, ?)
+		`, e.ID, e.SyncFiringID, e.InvocationID)
+		if err != nil {
+			return fmt.Errorf("import flow: insert provenance edge %d: %w", e.ID, err)
+		}
+	}
+
+	for _, a := range bundle.AbandonedFirings {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO abandoned_firings (sync_firing_id, reason) VALUES (?, ?)
+		`, a.SyncFiringID, a.Reason)
+		if err != nil {
+			return fmt.Errorf("import flow: insert abandoned firing %d: %w", a.SyncFiringID, err)
+		}
+	}
+
+	for _, f := range bundle.ScheduledFirings {
+		if err := checkCollision(ctx, tx, "scheduled_firings", f.ID); err != nil {
+			return fmt.Errorf("import flow: %w", err)
+		}
+
+		bindingsJSON, err := marshalArgs(f.Bindings)
+		if err != nil {
+			return fmt.Errorf("import flow: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO scheduled_firings
+			(id, completion_id, sync_id, binding_hash, flow_token, bindings, target_seq)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+		`, f.ID, f.CompletionID, f.SyncID, f.BindingHash, f.FlowToken, bindingsJSON, f.TargetSeq)
+		if err != nil {
+			return fmt.Errorf("import flow: insert scheduled firing %d: %w", f.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("import flow: commit: %w", err)
+	}
+
+	return nil
+}
+
+// checkCollision returns an error if a row with the given primary key exists.
+// The table name is always a package constant, never user input.
+func checkCollision(ctx context.Context, tx *sql.Tx, table string, id any) error {
+	var exists bool
+	err := tx.QueryRowContext(ctx,
+		"SELECT EXISTS(SELECT 1 FROM "+table+" WHERE id = ?)", id,
+	).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("check %s collision: %w", table, err)
+	}
+	if exists {
+		return fmt.Errorf("%s id %v already exists", table, id)
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+func TestArchiveFlow_RoundTrip(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+
+	writePrunableFlow(t, store, "flow-1", "a-", 1)
+	writePrunableFlow(t, store, "flow-2", "b-", 10)
+
+	before, err := store.ReplayFlow(ctx, "flow-1")
+	if err != nil {
+		t.Fatalf("ReplayFlow failed: %v", err)
+	}
+
+	bundle, err := store.ArchiveFlow(ctx, "flow-1")
+	if err != nil {
+		t.Fatalf("ArchiveFlow failed: %v", err)
+	}
+	if len(bundle.Invocations) != 2 || len(bundle.Completions) != 2 ||
+		len(bundle.SyncFirings) != 1 || len(bundle.ProvenanceEdges) != 1 {
+		t.Fatalf("bundle has %d/%d/%d/%d records, want 2/2/1/1",
+			len(bundle.Invocations), len(bundle.Completions),
+			len(bundle.SyncFirings), len(bundle.ProvenanceEdges))
+	}
+
+	// The bundle is portable: serialize through JSON before importing
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		t.Fatalf("marshal bundle: %v", err)
+	}
+	var decoded FlowBundle
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal bundle: %v", err)
+	}
+
+	if _, err := store.PruneFlow(ctx, "flow-1"); err != nil {
+		t.Fatalf("PruneFlow failed: %v", err)
+	}
+	if err := store.ImportFlow(ctx, decoded); err != nil {
+		t.Fatalf("ImportFlow failed: %v", err)
+	}
+
+	after, err := store.ReplayFlow(ctx, "flow-1")
+	if err != nil {
+		t.Fatalf("ReplayFlow failed: %v", err)
+	}
+	if !reflect.DeepEqual(before, after) {
+		t.Errorf("ReplayFlow after import differs:\nbefore: %+v\nafter:  %+v", before, after)
+	}
+
+	rearchived, err := store.ArchiveFlow(ctx, "flow-1")
+	if err != nil {
+		t.Fatalf("ArchiveFlow failed: %v", err)
+	}
+	if !reflect.DeepEqual(bundle, rearchived) {
+		t.Errorf("re-archived bundle differs:\nbefore: %+v\nafter:  %+v", bundle, rearchived)
+	}
+}
+
+func TestArchiveFlow_UnknownFlow(t *testing.T) {
+	store := createTestStore(t)
+
+	bundle, err := store.ArchiveFlow(context.Background(), "no-such-flow")
+	if err != nil {
+		t.Fatalf("ArchiveFlow failed: %v", err)
+	}
+	if bundle.Invocations == nil || bundle.SyncFirings == nil || bundle.ProvenanceEdges == nil ||
+		bundle.ScheduledFirings == nil {
+		t.Error("expected empty slices, got nil")
+	}
+}
+
+func TestArchiveFlow_RoundTripScheduledFiring(t *testing.T) {
+	source := createTestStore(t)
+	ctx := context.Background()
+	scheduled := seedScheduledFlow(t, source)
+
+	bundle, err := source.ArchiveFlow(ctx, "flow-1")
+	if err != nil {
+		t.Fatalf("ArchiveFlow failed: %v", err)
+	}
+	if !reflect.DeepEqual(bundle.ScheduledFirings, []ScheduledFiring{scheduled}) {
+		t.Fatalf("bundle scheduled firings = %+v, want [%+v]", bundle.ScheduledFirings, scheduled)
+	}
+
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		t.Fatalf("marshal bundle: %v", err)
+	}
+	var decoded FlowBundle
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal bundle: %v", err)
+	}
+
+	target := createTestStore(t)
+	if err := target.ImportFlow(ctx, decoded); err != nil {
+		t.Fatalf("ImportFlow failed: %v", err)
+	}
+
+	// The deferred firing survives the round trip and keeps the flow incomplete
+	state, err := target.GetFlowState(ctx, "flow-1")
+	if err != nil {
+		t.Fatalf("GetFlowState failed: %v", err)
+	}
+	if state.ScheduledCount != 1 || state.IsComplete {
+		t.Errorf("ScheduledCount = %d, IsComplete = %v; want 1, false", state.ScheduledCount, state.IsComplete)
+	}
+
+	rearchived, err := target.ArchiveFlow(ctx, "flow-1")
+	if err != nil {
+		t.Fatalf("ArchiveFlow failed: %v", err)
+	}
+	if !reflect.DeepEqual(bundle, rearchived) {
+		t.Errorf("re-archived bundle differs:\nbefore: %+v\nafter:  %+v", bundle, rearchived)
+	}
+
+	// Importing the same scheduled firing again is a collision
+	target2 := createTestStore(t)
+	seedScheduledFlow(t, target2)
+	err = target2.ImportFlow(ctx, FlowBundle{FlowToken: "flow-1", ScheduledFirings: decoded.ScheduledFirings})
+	if err == nil || !strings.Contains(err.Error(), "scheduled_firings id") {
+		t.Errorf("ImportFlow error = %v, want scheduled firing collision", err)
+	}
+}
+
+func TestImportFlow_RejectsCollision(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+
+	writePrunableFlow(t, store, "flow-1", "a-", 1)
+
+	bundle, err := store.ArchiveFlow(ctx, "flow-1")
+	if err != nil {
+		t.Fatalf("ArchiveFlow failed: %v", err)
+	}
+
+	// Add a record that does not yet exist, so a partial import would be visible
+	bundle.Invocations = append([]ir.Invocation{
+		createTestInvocation("a-inv-new", "flow-1", "Cart.confirm", 30),
+	}, bundle.Invocations...)
+
+	err = store.ImportFlow(ctx, bundle)
+	if err == nil {
+		t.Fatal("ImportFlow succeeded, want collision error")
+	}
+	if !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("error = %v, want collision error", err)
+	}
+
+	if got := countRows(t, store, "invocations"); got != 2 {
+		t.Errorf("invocations rows = %d, want 2 (import must be atomic)", got)
+	}
+}
+
+func TestImportFlow_RejectsForeignFlowToken(t *testing.T) {
+	store := createTestStore(t)
+
+	bundle := FlowBundle{
+		FlowToken:   "flow-1",
+		Invocations: []ir.Invocation{createTestInvocation("inv-1", "flow-2", "Cart.checkout", 1)},
+	}
+
+	err := store.ImportFlow(context.Background(), bundle)
+	if err == nil {
+		t.Fatal("ImportFlow succeeded, want flow token error")
+	}
+	if !strings.Contains(err.Error(), "flow token") {
+		t.Errorf("error = %v, want flow token error", err)
+	}
+}
@@ -0,0 +1,138 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+// seedCheckpointLog writes a checkout invocation, its completion, and a sync
+// firing that generated a reserve invocation (seqs 1-4).
+func seedCheckpointLog(t *testing.T, s *Store) {
+	t.Helper()
+	ctx := context.Background()
+
+	if err := s.WriteInvocation(ctx, createTestInvocation("inv-1", "flow-1", "Cart.checkout", 1)); err != nil {
+		t.Fatalf("WriteInvocation failed: %v", err)
+	}
+	if err := s.WriteCompletion(ctx, createTestCompletion("comp-1", "inv-1", "Success", 2)); err != nil {
+		t.Fatalf("WriteCompletion failed: %v", err)
+	}
+	firing := ir.SyncFiring{CompletionID: "comp-1", SyncID: "reserve", BindingHash: "binding-1", Seq: 3}
+	if _, _, err := s.WriteSyncFiringAtomic(ctx, firing, createTestInvocation("inv-2", "flow-1", "Inventory.reserve", 4)); err != nil {
+		t.Fatalf("WriteSyncFiringAtomic failed: %v", err)
+	}
+}
+
+func TestCheckpoint_VerifiesUnchangedStore(t *testing.T) {
+	store := createTestStore(t)
+	seedCheckpointLog(t, store)
+	ctx := context.Background()
+
+	cp, err := store.Checkpoint(ctx)
+	if err != nil {
+		t.Fatalf("Checkpoint failed: %v", err)
+	}
+	if cp.Seq != 4 {
+		t.Errorf("Seq = %d, want 4", cp.Seq)
+	}
+	if len(cp.Hash) != 64 {
+		t.Errorf("Hash = %q, want 64 hex chars", cp.Hash)
+	}
+
+	ok, err := store.VerifyCheckpoint(ctx, cp)
+	if err != nil {
+		t.Fatalf("VerifyCheckpoint failed: %v", err)
+	}
+	if !ok {
+		t.Error("VerifyCheckpoint = false for an unchanged store, want true")
+	}
+
+	// A separate store with the same log (e.g., a backup) verifies too
+	backup := createTestStore(t)
+	seedCheckpointLog(t, backup)
+	ok, err = backup.VerifyCheckpoint(ctx, cp)
+	if err != nil {
+		t.Fatalf("VerifyCheckpoint failed: %v", err)
+	}
+	if !ok {
+		t.Error("VerifyCheckpoint = false for an identical store, want true")
+	}
+}
+
+func TestCheckpoint_FailsAfterNewWrites(t *testing.T) {
+	store := createTestStore(t)
+	seedCheckpointLog(t, store)
+	ctx := context.Background()
+
+	cp, err := store.Checkpoint(ctx)
+	if err != nil {
+		t.Fatalf("Checkpoint failed: %v", err)
+	}
+
+	if err := store.WriteCompletion(ctx, createTestCompletion("comp-2", "inv-2", "Success", 5)); err != nil {
+		t.Fatalf("WriteCompletion failed: %v", err)
+	}
+
+	ok, err := store.VerifyCheckpoint(ctx, cp)
+	if err != nil {
+		t.Fatalf("VerifyCheckpoint failed: %v", err)
+	}
+	if ok {
+		t.Error("VerifyCheckpoint = true after a new write, want false")
+	}
+
+	next, err := store.Checkpoint(ctx)
+	if err != nil {
+		t.Fatalf("Checkpoint failed: %v", err)
+	}
+	if next.Seq != 5 || next.Hash == cp.Hash {
+		t.Errorf("Checkpoint = %+v, want seq 5 and a new hash", next)
+	}
+}
+
+func TestCheckpoint_FailsAfterModifiedRow(t *testing.T) {
+	store := createTestStore(t)
+	seedCheckpointLog(t, store)
+	ctx := context.Background()
+
+	cp, err := store.Checkpoint(ctx)
+	if err != nil {
+		t.Fatalf("Checkpoint failed: %v", err)
+	}
+
+	// Same seqs, different content
+	if _, err := store.db.Exec(`UPDATE completions SET result = '{"tampered":true}' WHERE id = 'comp-1'`); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+
+	ok, err := store.VerifyCheckpoint(ctx, cp)
+	if err != nil {
+		t.Fatalf("VerifyCheckpoint failed: %v", err)
+	}
+	if ok {
+		t.Error("VerifyCheckpoint = true after a row was modified, want false")
+	}
+}
+
+func TestCheckpoint_EmptyStore(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+
+	cp, err := store.Checkpoint(ctx)
+	if err != nil {
+		t.Fatalf("Checkpoint failed: %v", err)
+	}
+	if cp.Seq != 0 {
+		t.Errorf("Seq = %d, want 0", cp.Seq)
+	}
+
+	ok, err := store.VerifyCheckpoint(ctx, cp)
+	if err != nil {
+		t.Fatalf("VerifyCheckpoint failed: %v", err)
+	}
+	if !ok {
+		t.Error("VerifyCheckpoint = false for an empty store, want true")
+	}
+}
@@ -0,0 +1,137 @@
+package store
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+// IntegrityReport summarizes a VerifyIntegrity pass over the event log.
+type IntegrityReport struct {
+	InvocationsChecked int
+	CompletionsChecked int
+	SyncFiringsChecked int
+
+	// Mismatches lists every record whose stored identity does not match
+	// its content, ordered by table, then seq ASC, id ASC (CP-4).
+	Mismatches []IntegrityMismatch
+}
+
+// OK reports whether the verification found no mismatches.
+func (r IntegrityReport) OK() bool {
+	return len(r.Mismatches) == 0
+}
+
+// IntegrityMismatch describes one record that failed verification.
+type IntegrityMismatch struct {
+	Table      string // "invocations", "completions", or "sync_firings"
+	ID         string // Stored record ID (sync firing IDs are decimal)
+	Field      string // Column that failed ("id" or "binding_hash")
+	Stored     string // Value in the store
+	Recomputed string // Value derived from the record's content ("" if not derivable)
+	Reason     string // Human-readable explanation
+}
+
+// String formats the mismatch for CLI output and logs.
+func (m IntegrityMismatch) String() string {
+	if m.Recomputed == "" {
+		return fmt.Sprintf("%s %s: %s %s", m.Table, m.ID, m.Field, m.Reason)
+	}
+	return fmt.Sprintf("%s %s: %s %s (stored %s, recomputed %s)", m.Table, m.ID, m.Field, m.Reason, m.Stored, m.Recomputed)
+}
+
+// VerifyIntegrity re-derives content-addressed identities from stored
+// inputs and reports any record whose persisted ID does not match.
+//
+// Checks:
+//   - Invocations: ir.InvocationID(flow_token, action_uri, args, seq) == id
+//   - Completions: ir.CompletionID(invocation_id, output_case, result, seq) == id
+//   - Sync firings: binding_hash is a well-formed hash. The binding values
+//     themselves are not persisted, so the hash cannot be recomputed from
+//     the store alone; the engine re-derives bindings from sync rules
+//     during recovery (see engine.Recover).
+//
+// A mismatch means the row was modified after it was written (tampering)
+// or was written by an engine with a different hashing scheme (version
+// skew). Verification is read-only and never repairs rows.
+//
+// Returns an error only if the store cannot be read.
+func (s *Store) VerifyIntegrity(ctx context.Context) (IntegrityReport, error) {
+	var report IntegrityReport
+
+	invocations, err := s.ReadAllInvocations(ctx)
+	if err != nil {
+		return IntegrityReport{}, fmt.Errorf("verify integrity: %w", err)
+	}
+	for _, inv := range invocations {
+		report.InvocationsChecked++
+		want, err := ir.InvocationID(inv.FlowToken, string(inv.ActionURI), inv.Args, inv.Seq)
+		report.checkID("invocations", inv.ID, want, err)
+	}
+
+	completions, err := s.ReadAllCompletions(ctx)
+	if err != nil {
+		return IntegrityReport{}, fmt.Errorf("verify integrity: %w", err)
+	}
+	for _, comp := range completions {
+		report.CompletionsChecked++
+		want, err := ir.CompletionID(comp.InvocationID, comp.OutputCase, comp.Result, comp.Seq)
+		report.checkID("completions", comp.ID, want, err)
+	}
+
+	firings, err := s.ReadAllSyncFirings(ctx)
+	if err != nil {
+		return IntegrityReport{}, fmt.Errorf("verify integrity: %w", err)
+	}
+	for _, f := range firings {
+		report.SyncFiringsChecked++
+		if !isWellFormedHash(f.BindingHash) {
+			report.Mismatches = append(report.Mismatches, IntegrityMismatch{
+				Table:  "sync_firings",
+				ID:     strconv.FormatInt(f.ID, 10),
+				Field:  "binding_hash",
+				Stored: f.BindingHash,
+				Reason: "is not a SHA-256 hex digest",
+			})
+		}
+	}
+
+	return report, nil
+}
+
+// checkID records a mismatch if a stored ID differs from the recomputed one.
+func (r *IntegrityReport) checkID(table, stored, recomputed string, err error) {
+	if err != nil {
+		r.Mismatches = append(r.Mismatches, IntegrityMismatch{
+			Table:  table,
+			ID:     stored,
+			Field:  "id",
+			Stored: stored,
+			Reason: fmt.Sprintf("cannot be recomputed: %v", err),
+		})
+		return
+	}
+	if stored != recomputed {
+		r.Mismatches = append(r.Mismatches, IntegrityMismatch{
+			Table:      table,
+			ID:         stored,
+			Field:      "id",
+			Stored:     stored,
+			Recomputed: recomputed,
+			Reason:     "does not match content",
+		})
+	}
+}
+
+// isWellFormedHash reports whether s looks like a hex-encoded SHA-256 digest,
+// the format produced by the ir hashing functions.
+func isWellFormedHash(s string) bool {
+	if len(s) != 64 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
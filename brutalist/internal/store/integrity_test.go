@@ -0,0 +1,148 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+// writeContentAddressedFlow writes an invocation, its completion, and a sync
+// firing whose IDs are derived from content, as the engine would.
+func writeContentAddressedFlow(t *testing.T, s *Store) (ir.Invocation, ir.Completion) {
+	t.Helper()
+	ctx := context.Background()
+
+	inv := createTestInvocation("", "flow-1", "Cart.addItem", 1)
+	inv.Args = ir.IRObject{"item_id": ir.IRString("widget"), "quantity": ir.IRInt(2)}
+	inv.ID = ir.MustInvocationID(inv.FlowToken, string(inv.ActionURI), inv.Args, inv.Seq)
+	if err := s.WriteInvocation(ctx, inv); err != nil {
+		t.Fatalf("WriteInvocation() failed: %v", err)
+	}
+
+	comp := createTestCompletion("", inv.ID, "Success", 2)
+	comp.Result = ir.IRObject{"item_id": ir.IRString("widget")}
+	comp.ID = ir.MustCompletionID(comp.InvocationID, comp.OutputCase, comp.Result, comp.Seq)
+	if err := s.WriteCompletion(ctx, comp); err != nil {
+		t.Fatalf("WriteCompletion() failed: %v", err)
+	}
+
+	bindingHash := ir.MustBindingHash(ir.IRObject{"item": ir.IRString("widget")})
+	if _, _, err := s.WriteSyncFiring(ctx, ir.SyncFiring{
+		CompletionID: comp.ID,
+		SyncID:       "cart-inventory",
+		BindingHash:  bindingHash,
+		Seq:          3,
+	}); err != nil {
+		t.Fatalf("WriteSyncFiring() failed: %v", err)
+	}
+
+	return inv, comp
+}
+
+func TestVerifyIntegrity_CleanStore(t *testing.T) {
+	s := createTestStore(t)
+	writeContentAddressedFlow(t, s)
+
+	report, err := s.VerifyIntegrity(context.Background())
+	if err != nil {
+		t.Fatalf("VerifyIntegrity() failed: %v", err)
+	}
+
+	if !report.OK() {
+		t.Errorf("expected no mismatches, got %v", report.Mismatches)
+	}
+	if report.InvocationsChecked != 1 || report.CompletionsChecked != 1 || report.SyncFiringsChecked != 1 {
+		t.Errorf("checked = (%d, %d, %d), want (1, 1, 1)",
+			report.InvocationsChecked, report.CompletionsChecked, report.SyncFiringsChecked)
+	}
+}
+
+func TestVerifyIntegrity_EmptyStore(t *testing.T) {
+	s := createTestStore(t)
+
+	report, err := s.VerifyIntegrity(context.Background())
+	if err != nil {
+		t.Fatalf("VerifyIntegrity() failed: %v", err)
+	}
+	if !report.OK() {
+		t.Errorf("expected no mismatches, got %v", report.Mismatches)
+	}
+}
+
+func TestVerifyIntegrity_CorruptedInvocationArgs(t *testing.T) {
+	s := createTestStore(t)
+	inv, _ := writeContentAddressedFlow(t, s)
+
+	// Tamper with the args after the fact; the ID no longer matches content.
+	if _, err := s.DB().Exec(
+		`UPDATE invocations SET args = ? WHERE id = ?`,
+		`{"item_id":"widget","quantity":200}`, inv.ID,
+	); err != nil {
+		t.Fatalf("corrupt invocation: %v", err)
+	}
+
+	report, err := s.VerifyIntegrity(context.Background())
+	if err != nil {
+		t.Fatalf("VerifyIntegrity() failed: %v", err)
+	}
+
+	if len(report.Mismatches) != 1 {
+		t.Fatalf("expected 1 mismatch, got %d: %v", len(report.Mismatches), report.Mismatches)
+	}
+	m := report.Mismatches[0]
+	if m.Table != "invocations" || m.ID != inv.ID || m.Field != "id" {
+		t.Errorf("mismatch = %+v, want invocations %s id", m, inv.ID)
+	}
+	if m.Recomputed == "" || m.Recomputed == inv.ID {
+		t.Errorf("Recomputed = %q, want a different ID", m.Recomputed)
+	}
+	if !strings.Contains(m.String(), "does not match content") {
+		t.Errorf("String() = %q", m.String())
+	}
+}
+
+func TestVerifyIntegrity_CorruptedCompletionResult(t *testing.T) {
+	s := createTestStore(t)
+	_, comp := writeContentAddressedFlow(t, s)
+
+	if _, err := s.DB().Exec(
+		`UPDATE completions SET output_case = 'Failure' WHERE id = ?`, comp.ID,
+	); err != nil {
+		t.Fatalf("corrupt completion: %v", err)
+	}
+
+	report, err := s.VerifyIntegrity(context.Background())
+	if err != nil {
+		t.Fatalf("VerifyIntegrity() failed: %v", err)
+	}
+
+	if len(report.Mismatches) != 1 {
+		t.Fatalf("expected 1 mismatch, got %d: %v", len(report.Mismatches), report.Mismatches)
+	}
+	if m := report.Mismatches[0]; m.Table != "completions" || m.ID != comp.ID {
+		t.Errorf("mismatch = %+v, want completions %s", m, comp.ID)
+	}
+}
+
+func TestVerifyIntegrity_MalformedBindingHash(t *testing.T) {
+	s := createTestStore(t)
+	writeContentAddressedFlow(t, s)
+
+	if _, err := s.DB().Exec(`UPDATE sync_firings SET binding_hash = 'not-a-hash'`); err != nil {
+		t.Fatalf("corrupt sync firing: %v", err)
+	}
+
+	report, err := s.VerifyIntegrity(context.Background())
+	if err != nil {
+		t.Fatalf("VerifyIntegrity() failed: %v", err)
+	}
+
+	if len(report.Mismatches) != 1 {
+		t.Fatalf("expected 1 mismatch, got %d: %v", len(report.Mismatches), report.Mismatches)
+	}
+	if m := report.Mismatches[0]; m.Table != "sync_firings" || m.Field != "binding_hash" || m.Stored != "not-a-hash" {
+		t.Errorf("mismatch = %+v, want sync_firings binding_hash", m)
+	}
+}
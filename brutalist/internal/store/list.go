@@ -0,0 +1,199 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// FlowStatus filters flows by completeness in ListFlows.
+type FlowStatus string
+
+const (
+	// FlowStatusAny matches every flow (no filtering).
+	FlowStatusAny FlowStatus = ""
+
+	// FlowStatusComplete matches flows with no pending invocations, no orphaned
+	// firings, and no scheduled firings.
+	FlowStatusComplete FlowStatus = "complete"
+
+	// FlowStatusIncomplete matches flows with pending invocations, orphaned firings,
+	// or scheduled firings, the same set FindIncompleteFlows returns.
+	FlowStatusIncomplete FlowStatus = "incomplete"
+
+	// FlowStatusOrphaned matches flows with at least one orphaned sync firing
+	// (crash recovery indicator).
+	FlowStatusOrphaned FlowStatus = "orphaned"
+)
+
+// ListFlowsOptions controls filtering and pagination for ListFlows.
+type ListFlowsOptions struct {
+	// Status restricts results to flows of the given status.
+	Status FlowStatus
+
+	// AfterSeq and AfterFlowToken form a keyset cursor: only flows ordered
+	// strictly after (AfterSeq, AfterFlowToken) are returned. To fetch the
+	// next page, pass the FirstSeq and FlowToken of the last summary returned.
+	// The zero value starts from the beginning.
+	AfterSeq       int64
+	AfterFlowToken string
+
+	// Limit caps the number of summaries returned. Zero means no limit.
+	Limit int
+}
+
+// FlowSummary is a compact description of a flow, without its records.
+type FlowSummary struct {
+	FlowToken       string
+	FirstSeq        int64 // Lowest invocation seq in the flow
+	LastSeq         int64 // Highest invocation or completion seq (matches GetLastSeqForFlow)
+	InvocationCount int
+	PendingCount    int  // Invocations without completions
+	OrphanedFirings int  // Sync firings without provenance edges
+	ScheduledCount  int  // Deferred firings not yet released
+	IsComplete      bool // Same rule as FlowState.IsComplete
+}
+
+// ListFlows enumerates flows with optional status filtering and keyset pagination.
+// Results are ordered by first seq ASC, then flow token ASC (COLLATE BINARY),
+// so paging with the cursor visits every flow exactly once.
+//
+// Returns an empty slice (not nil) if no flows match.
+func (s *Store) ListFlows(ctx context.Context, opts ListFlowsOptions) ([]FlowSummary, error) {
+	if opts.Limit < 0 {
+		return nil, fmt.Errorf("list flows: limit must be non-negative, got %d", opts.Limit)
+	}
+
+	var conditions []string
+	var args []any
+
+	switch opts.Status {
+	case FlowStatusAny:
+	case FlowStatusComplete:
+		conditions = append(conditions,
+			"f.pending = 0 AND COALESCE(o.orphaned, 0) = 0 AND COALESCE(sc.scheduled, 0) = 0")
+	case FlowStatusIncomplete:
+		conditions = append(conditions,
+			"(f.pending > 0 OR COALESCE(o.orphaned, 0) > 0 OR COALESCE(sc.scheduled, 0) > 0)")
+	case FlowStatusOrphaned:
+		conditions = append(conditions, "COALESCE(o.orphaned, 0) > 0")
+	default:
+		return nil, fmt.Errorf("list flows: unknown status %q", opts.Status)
+	}
+
+	if opts.AfterSeq != 0 || opts.AfterFlowToken != "" {
+		conditions = append(conditions,
+			"(f.first_seq > ? OR (f.first_seq = ? AND f.flow_token > ? COLLATE BINARY))")
+		args = append(args, opts.AfterSeq, opts.AfterSeq, opts.AfterFlowToken)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	limit := -1 // SQLite: negative LIMIT means no limit
+	if opts.Limit > 0 {
+		limit = opts.Limit
+	}
+	args = append(args, limit)
+
+	// CP-4: Deterministic ordering - first_seq ASC, flow_token COLLATE BINARY ASC
+	rows, err := s.db.QueryContext(ctx, `
+		WITH flows AS (
+			SELECT
+				i.flow_token,
+				MIN(i.seq) AS first_seq,
+				MAX(MAX(i.seq), COALESCE(MAX(c.seq), 0)) AS last_seq,
+				COUNT(*) AS invocation_count,
+				SUM(CASE WHEN c.id IS NULL THEN 1 ELSE 0 END) AS pending
+			FROM invocations i
+			LEFT JOIN completions c ON c.invocation_id = i.id
+			GROUP BY i.flow_token
+		),
+		orphans AS (
+			SELECT i.flow_token, COUNT(*) AS orphaned
+			FROM sync_firings sf
+			LEFT JOIN provenance_edges pe ON sf.id = pe.sync_firing_id
+			JOIN completions c ON sf.completion_id = c.id
+			JOIN invocations i ON c.invocation_id = i.id
+			WHERE pe.id IS NULL
+			  AND NOT EXISTS (SELECT 1 FROM abandoned_firings af WHERE af.sync_firing_id = sf.id)
+			GROUP BY i.flow_token
+		),
+		scheduled AS (
+			SELECT flow_token, COUNT(*) AS scheduled
+			FROM scheduled_firings
+			GROUP BY flow_token
+		)
+		SELECT f.flow_token, f.first_seq, f.last_seq, f.invocation_count, f.pending,
+			COALESCE(o.orphaned, 0), COALESCE(sc.scheduled, 0)
+		FROM flows f
+		LEFT JOIN orphans o ON o.flow_token = f.flow_token
+		LEFT JOIN scheduled sc ON sc.flow_token = f.flow_token
+		`+where+`
+		ORDER BY f.first_seq ASC, f.flow_token COLLATE BINARY ASC
+		LIMIT ?
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list flows: %w", err)
+	}
+	defer rows.Close()
+
+	summaries := []FlowSummary{}
+	for rows.Next() {
+		var sum FlowSummary
+		if err := rows.Scan(
+			&sum.FlowToken, &sum.FirstSeq, &sum.LastSeq,
+			&sum.InvocationCount, &sum.PendingCount, &sum.OrphanedFirings,
+			&sum.ScheduledCount,
+		); err != nil {
+			return nil, fmt.Errorf("scan flow summary: %w", err)
+		}
+		sum.IsComplete = sum.PendingCount == 0 && sum.OrphanedFirings == 0 && sum.ScheduledCount == 0
+		summaries = append(summaries, sum)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate flow summaries: %w", err)
+	}
+
+	return summaries, nil
+}
+
+// FindFlowsByTenant returns the distinct flow tokens whose invocations carry
+// the given tenant_id in their security context (CP-6).
+// Results are ordered by each flow's first matching seq, then flow token.
+//
+// Uses the idx_invocations_tenant expression index on
+// json_extract(security_context, '$.tenant_id').
+//
+// Returns an empty slice (not nil) if no flows match.
+func (s *Store) FindFlowsByTenant(ctx context.Context, tenantID string) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT flow_token
+		FROM invocations
+		WHERE json_extract(security_context, '$.tenant_id') = ?
+		GROUP BY flow_token
+		ORDER BY MIN(seq) ASC, flow_token COLLATE BINARY ASC
+	`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("find flows by tenant: %w", err)
+	}
+	defer rows.Close()
+
+	tokens := []string{}
+	for rows.Next() {
+		var token string
+		if err := rows.Scan(&token); err != nil {
+			return nil, fmt.Errorf("scan flow token: %w", err)
+		}
+		tokens = append(tokens, token)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate flow tokens: %w", err)
+	}
+
+	return tokens, nil
+}
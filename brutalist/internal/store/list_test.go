@@ -0,0 +1,271 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+// seedListFlows writes four flows of mixed status:
+//   - flow-a: complete, first seq 1
+//   - flow-b: incomplete (pending invocation), first seq 10
+//   - flow-d: orphaned sync firing, first seq 20
+//   - flow-c: complete, first seq 20 (ties with flow-d, ordered by token)
+func seedListFlows(t *testing.T, s *Store) {
+	t.Helper()
+	ctx := context.Background()
+
+	writePrunableFlow(t, s, "flow-a", "a-", 1)
+
+	if err := s.WriteInvocation(ctx, createTestInvocation("b-inv-1", "flow-b", "Cart.checkout", 10)); err != nil {
+		t.Fatalf("WriteInvocation failed: %v", err)
+	}
+
+	if err := s.WriteInvocation(ctx, createTestInvocation("d-inv-1", "flow-d", "Cart.checkout", 20)); err != nil {
+		t.Fatalf("WriteInvocation failed: %v", err)
+	}
+	if err := s.WriteCompletion(ctx, createTestCompletion("d-comp-1", "d-inv-1", "Success", 21)); err != nil {
+		t.Fatalf("WriteCompletion failed: %v", err)
+	}
+	if _, _, err := s.WriteSyncFiring(ctx, ir.SyncFiring{
+		CompletionID: "d-comp-1", SyncID: "sync-1", BindingHash: "h1", Seq: 22,
+	}); err != nil {
+		t.Fatalf("WriteSyncFiring failed: %v", err)
+	}
+
+	writePrunableFlow(t, s, "flow-c", "c-", 20)
+}
+
+func flowTokens(summaries []FlowSummary) []string {
+	tokens := make([]string, len(summaries))
+	for i, sum := range summaries {
+		tokens[i] = sum.FlowToken
+	}
+	return tokens
+}
+
+func TestListFlows_All(t *testing.T) {
+	store := createTestStore(t)
+	seedListFlows(t, store)
+
+	flows, err := store.ListFlows(context.Background(), ListFlowsOptions{})
+	if err != nil {
+		t.Fatalf("ListFlows failed: %v", err)
+	}
+
+	got := flowTokens(flows)
+	want := []string{"flow-a", "flow-b", "flow-c", "flow-d"}
+	if len(got) != len(want) {
+		t.Fatalf("flows = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("flows[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+
+	a := flows[0]
+	if a.FirstSeq != 1 || a.LastSeq != 5 || a.InvocationCount != 2 || !a.IsComplete {
+		t.Errorf("flow-a summary = %+v, want FirstSeq=1 LastSeq=5 InvocationCount=2 IsComplete=true", a)
+	}
+
+	b := flows[1]
+	if b.PendingCount != 1 || b.IsComplete {
+		t.Errorf("flow-b summary = %+v, want PendingCount=1 IsComplete=false", b)
+	}
+
+	d := flows[3]
+	if d.OrphanedFirings != 1 || d.IsComplete {
+		t.Errorf("flow-d summary = %+v, want OrphanedFirings=1 IsComplete=false", d)
+	}
+}
+
+func TestListFlows_StatusFilter(t *testing.T) {
+	store := createTestStore(t)
+	seedListFlows(t, store)
+
+	tests := []struct {
+		status FlowStatus
+		want   []string
+	}{
+		{FlowStatusComplete, []string{"flow-a", "flow-c"}},
+		{FlowStatusIncomplete, []string{"flow-b", "flow-d"}},
+		{FlowStatusOrphaned, []string{"flow-d"}},
+	}
+
+	for _, tt := range tests {
+		flows, err := store.ListFlows(context.Background(), ListFlowsOptions{Status: tt.status})
+		if err != nil {
+			t.Fatalf("ListFlows(%s) failed: %v", tt.status, err)
+		}
+		got := flowTokens(flows)
+		if len(got) != len(tt.want) {
+			t.Errorf("ListFlows(%s) = %v, want %v", tt.status, got, tt.want)
+			continue
+		}
+		for i := range tt.want {
+			if got[i] != tt.want[i] {
+				t.Errorf("ListFlows(%s)[%d] = %s, want %s", tt.status, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestListFlows_CursorPaging(t *testing.T) {
+	store := createTestStore(t)
+	seedListFlows(t, store)
+	ctx := context.Background()
+
+	var visited []string
+	opts := ListFlowsOptions{Limit: 1}
+	for page := 0; page < 10; page++ {
+		flows, err := store.ListFlows(ctx, opts)
+		if err != nil {
+			t.Fatalf("ListFlows failed: %v", err)
+		}
+		if len(flows) == 0 {
+			break
+		}
+		if len(flows) > 1 {
+			t.Fatalf("page %d has %d flows, want at most 1", page, len(flows))
+		}
+		visited = append(visited, flows[0].FlowToken)
+		opts.AfterSeq = flows[0].FirstSeq
+		opts.AfterFlowToken = flows[0].FlowToken
+	}
+
+	want := []string{"flow-a", "flow-b", "flow-c", "flow-d"}
+	if len(visited) != len(want) {
+		t.Fatalf("visited = %v, want %v", visited, want)
+	}
+	for i := range want {
+		if visited[i] != want[i] {
+			t.Errorf("visited[%d] = %s, want %s", i, visited[i], want[i])
+		}
+	}
+}
+
+func TestListFlows_CursorWithStatus(t *testing.T) {
+	store := createTestStore(t)
+	seedListFlows(t, store)
+
+	flows, err := store.ListFlows(context.Background(), ListFlowsOptions{
+		Status:         FlowStatusComplete,
+		AfterSeq:       1,
+		AfterFlowToken: "flow-a",
+		Limit:          10,
+	})
+	if err != nil {
+		t.Fatalf("ListFlows failed: %v", err)
+	}
+	if got := flowTokens(flows); len(got) != 1 || got[0] != "flow-c" {
+		t.Errorf("flows = %v, want [flow-c]", got)
+	}
+}
+
+func TestListFlows_Empty(t *testing.T) {
+	store := createTestStore(t)
+
+	flows, err := store.ListFlows(context.Background(), ListFlowsOptions{})
+	if err != nil {
+		t.Fatalf("ListFlows failed: %v", err)
+	}
+	if flows == nil {
+		t.Error("expected empty slice, got nil")
+	}
+}
+
+func TestListFlows_InvalidOptions(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+
+	if _, err := store.ListFlows(ctx, ListFlowsOptions{Status: "bogus"}); err == nil {
+		t.Error("expected error for unknown status")
+	}
+	if _, err := store.ListFlows(ctx, ListFlowsOptions{Limit: -1}); err == nil {
+		t.Error("expected error for negative limit")
+	}
+}
+
+func TestFindFlowsByTenant(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+
+	// Two tenants interleaved across four flows; flow-4 starts before flow-2
+	writes := []struct {
+		id, flow, tenant string
+		seq              int64
+	}{
+		{"inv-1", "flow-1", "acme", 1},
+		{"inv-2", "flow-3", "globex", 2},
+		{"inv-3", "flow-4", "acme", 3},
+		{"inv-4", "flow-1", "acme", 4},
+		{"inv-5", "flow-2", "acme", 5},
+		{"inv-6", "flow-3", "globex", 6},
+	}
+	for _, w := range writes {
+		inv := createTestInvocation(w.id, w.flow, "Cart.addItem", w.seq)
+		inv.SecurityContext = ir.SecurityContext{TenantID: w.tenant, UserID: "user-1"}
+		if err := store.WriteInvocation(ctx, inv); err != nil {
+			t.Fatalf("WriteInvocation failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		tenant string
+		want   []string
+	}{
+		{"acme", []string{"flow-1", "flow-4", "flow-2"}},
+		{"globex", []string{"flow-3"}},
+		{"initech", []string{}},
+	}
+
+	for _, tt := range tests {
+		got, err := store.FindFlowsByTenant(ctx, tt.tenant)
+		if err != nil {
+			t.Fatalf("FindFlowsByTenant(%s) failed: %v", tt.tenant, err)
+		}
+		if got == nil {
+			t.Errorf("FindFlowsByTenant(%s) = nil, want empty slice", tt.tenant)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("FindFlowsByTenant(%s) = %v, want %v", tt.tenant, got, tt.want)
+			continue
+		}
+		for i := range tt.want {
+			if got[i] != tt.want[i] {
+				t.Errorf("FindFlowsByTenant(%s)[%d] = %s, want %s", tt.tenant, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestFindFlowsByTenant_UsesIndex(t *testing.T) {
+	store := createTestStore(t)
+
+	rows, err := store.DB().Query(`
+		EXPLAIN QUERY PLAN
+		SELECT flow_token FROM invocations
+		WHERE json_extract(security_context, '$.tenant_id') = ?
+	`, "acme")
+	if err != nil {
+		t.Fatalf("EXPLAIN failed: %v", err)
+	}
+	defer rows.Close()
+
+	var plan strings.Builder
+	for rows.Next() {
+		var id, parent, notused int
+		var detail string
+		if err := rows.Scan(&id, &parent, &notused, &detail); err != nil {
+			t.Fatalf("scan plan: %v", err)
+		}
+		plan.WriteString(detail + "\n")
+	}
+
+	if !strings.Contains(plan.String(), "idx_invocations_tenant") {
+		t.Errorf("query plan does not use idx_invocations_tenant:\n%s", plan.String())
+	}
+}
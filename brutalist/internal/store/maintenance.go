@@ -0,0 +1,83 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// SizeStats reports how much data the store holds, for deciding when to
+// prune or compact.
+type SizeStats struct {
+	Invocations     int64
+	Completions     int64
+	SyncFirings     int64
+	ProvenanceEdges int64
+
+	// FileBytes is the on-disk size of the database, including its WAL file.
+	FileBytes int64
+
+	// PageSize and FreePages describe SQLite's allocation: FreePages*PageSize
+	// bytes are unused and would be reclaimed by Vacuum.
+	PageSize  int64
+	FreePages int64
+}
+
+// Vacuum rebuilds the database file to reclaim space left by deleted rows
+// (e.g., after PruneFlow), then checkpoints the WAL so the on-disk file
+// actually shrinks. SQLite does not reclaim space automatically.
+//
+// Vacuum is safe in WAL mode but briefly takes a write lock on the whole
+// database; concurrent writers will wait up to the busy timeout.
+func (s *Store) Vacuum(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, "VACUUM"); err != nil {
+		return fmt.Errorf("vacuum: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+		return fmt.Errorf("vacuum: checkpoint: %w", err)
+	}
+	return nil
+}
+
+// SizeStats returns per-table row counts and the database size on disk.
+func (s *Store) SizeStats(ctx context.Context) (SizeStats, error) {
+	var stats SizeStats
+
+	counts := []struct {
+		table string
+		dest  *int64
+	}{
+		{"invocations", &stats.Invocations},
+		{"completions", &stats.Completions},
+		{"sync_firings", &stats.SyncFirings},
+		{"provenance_edges", &stats.ProvenanceEdges},
+	}
+	for _, c := range counts {
+		// Table names are package constants, never user input
+		if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+c.table).Scan(c.dest); err != nil {
+			return SizeStats{}, fmt.Errorf("size stats: count %s: %w", c.table, err)
+		}
+	}
+
+	if err := s.db.QueryRowContext(ctx, "PRAGMA page_size").Scan(&stats.PageSize); err != nil {
+		return SizeStats{}, fmt.Errorf("size stats: page size: %w", err)
+	}
+	if err := s.db.QueryRowContext(ctx, "PRAGMA freelist_count").Scan(&stats.FreePages); err != nil {
+		return SizeStats{}, fmt.Errorf("size stats: freelist count: %w", err)
+	}
+
+	for _, path := range []string{s.path, s.path + "-wal"} {
+		info, err := os.Stat(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue // No WAL yet, or an in-memory database
+		}
+		if err != nil {
+			return SizeStats{}, fmt.Errorf("size stats: %w", err)
+		}
+		stats.FileBytes += info.Size()
+	}
+
+	return stats, nil
+}
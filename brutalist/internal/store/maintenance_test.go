@@ -0,0 +1,96 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+func TestSizeStats_RowCounts(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+
+	writePrunableFlow(t, store, "flow-1", "a-", 1)
+	writePrunableFlow(t, store, "flow-2", "b-", 10)
+	store.WriteInvocation(ctx, createTestInvocation("c-inv-1", "flow-3", "Cart.addItem", 20))
+
+	stats, err := store.SizeStats(ctx)
+	if err != nil {
+		t.Fatalf("SizeStats failed: %v", err)
+	}
+
+	if stats.Invocations != 5 {
+		t.Errorf("Invocations = %d, want 5", stats.Invocations)
+	}
+	if stats.Completions != 4 {
+		t.Errorf("Completions = %d, want 4", stats.Completions)
+	}
+	if stats.SyncFirings != 2 {
+		t.Errorf("SyncFirings = %d, want 2", stats.SyncFirings)
+	}
+	if stats.ProvenanceEdges != 2 {
+		t.Errorf("ProvenanceEdges = %d, want 2", stats.ProvenanceEdges)
+	}
+	if stats.FileBytes <= 0 {
+		t.Errorf("FileBytes = %d, want > 0", stats.FileBytes)
+	}
+	if stats.PageSize <= 0 {
+		t.Errorf("PageSize = %d, want > 0", stats.PageSize)
+	}
+}
+
+func TestVacuum_ReclaimsSpaceAfterPrune(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+
+	// Enough data to span many pages
+	padding := strings.Repeat("x", 512)
+	invs := make([]ir.Invocation, 2000)
+	for i := range invs {
+		invs[i] = createTestInvocation(fmt.Sprintf("inv-%d", i), "flow-big", "Cart.addItem", int64(i+1))
+		invs[i].Args = ir.IRObject{"padding": ir.IRString(padding)}
+	}
+	if err := store.WriteInvocations(ctx, invs); err != nil {
+		t.Fatalf("WriteInvocations failed: %v", err)
+	}
+	for i := range invs {
+		comp := createTestCompletion(fmt.Sprintf("comp-%d", i), invs[i].ID, "Success", int64(len(invs)+i+1))
+		if err := store.WriteCompletion(ctx, comp); err != nil {
+			t.Fatalf("WriteCompletion failed: %v", err)
+		}
+	}
+
+	// Establish a baseline with the WAL checkpointed
+	if err := store.Vacuum(ctx); err != nil {
+		t.Fatalf("Vacuum failed: %v", err)
+	}
+	before, err := store.SizeStats(ctx)
+	if err != nil {
+		t.Fatalf("SizeStats failed: %v", err)
+	}
+
+	if _, err := store.PruneFlow(ctx, "flow-big"); err != nil {
+		t.Fatalf("PruneFlow failed: %v", err)
+	}
+	if err := store.Vacuum(ctx); err != nil {
+		t.Fatalf("Vacuum failed: %v", err)
+	}
+
+	after, err := store.SizeStats(ctx)
+	if err != nil {
+		t.Fatalf("SizeStats failed: %v", err)
+	}
+
+	if after.Invocations != 0 || after.Completions != 0 {
+		t.Errorf("rows after prune = %d/%d, want 0/0", after.Invocations, after.Completions)
+	}
+	if after.FileBytes >= before.FileBytes {
+		t.Errorf("FileBytes after vacuum = %d, want < %d", after.FileBytes, before.FileBytes)
+	}
+	if after.FreePages != 0 {
+		t.Errorf("FreePages after vacuum = %d, want 0", after.FreePages)
+	}
+}
@@ -11,7 +11,11 @@ import (
 
 // marshalArgs converts IRObject to canonical JSON TEXT for storage.
 // Uses RFC 8785 canonical JSON for deterministic serialization.
+// Every write path goes through here, so this is where CP-5 is enforced.
 func marshalArgs(args ir.IRObject) (string, error) {
+	if err := validateIRObject("args", args); err != nil {
+		return "", err
+	}
 	data, err := ir.MarshalCanonical(args)
 	if err != nil {
 		return "", fmt.Errorf("marshal args: %w", err)
@@ -22,6 +26,9 @@ func marshalArgs(args ir.IRObject) (string, error) {
 // marshalResult converts IRObject to canonical JSON TEXT for storage.
 // Uses RFC 8785 canonical JSON for deterministic serialization.
 func marshalResult(result ir.IRObject) (string, error) {
+	if err := validateIRObject("result", result); err != nil {
+		return "", err
+	}
 	data, err := ir.MarshalCanonical(result)
 	if err != nil {
 		return "", fmt.Errorf("marshal result: %w", err)
@@ -33,7 +40,13 @@ func marshalResult(result ir.IRObject) (string, error) {
 // Uses json.Encoder with HTML escaping disabled for RFC 8785 compliance.
 // Note: SecurityContext is a struct (not IRValue), so we use json.Encoder
 // with sorted keys to ensure consistent output for golden traces.
+// Every write path serializes through here, so contexts failing
+// SecurityContext.Validate are rejected before reaching the database (CP-6).
 func marshalSecurityContext(ctx ir.SecurityContext) (string, error) {
+	if err := ctx.Validate(); err != nil {
+		return "", fmt.Errorf("invalid security context: %w", err)
+	}
+
 	// Build a map with sorted keys for deterministic output
 	// Go's json.Marshal sorts map keys alphabetically since Go 1.12
 	m := map[string]any{
@@ -1,6 +1,7 @@
 package store
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/roach88/nysm/internal/ir"
@@ -97,13 +98,24 @@ func TestMarshalResult_WithValues(t *testing.T) {
 
 func TestMarshalSecurityContext_Empty(t *testing.T) {
 	ctx := ir.SecurityContext{}
+	_, err := marshalSecurityContext(ctx)
+	if err == nil {
+		t.Fatal("marshalSecurityContext() should reject a context without tenant and user (CP-6)")
+	}
+	if !strings.Contains(err.Error(), "tenant_id") || !strings.Contains(err.Error(), "user_id") {
+		t.Errorf("error should name both missing fields, got: %v", err)
+	}
+}
+
+func TestMarshalSecurityContext_NilPermissions(t *testing.T) {
+	ctx := ir.SecurityContext{TenantID: "tenant-123", UserID: "user-456"}
 	json, err := marshalSecurityContext(ctx)
 	if err != nil {
 		t.Fatalf("marshalSecurityContext() failed: %v", err)
 	}
 
-	// Empty security context still serializes with null permissions
-	expected := `{"permissions":null,"tenant_id":"","user_id":""}`
+	// Nil permissions still serialize as null
+	expected := `{"permissions":null,"tenant_id":"tenant-123","user_id":"user-456"}`
 	if json != expected {
 		t.Errorf("marshalSecurityContext() = %q, want %q", json, expected)
 	}
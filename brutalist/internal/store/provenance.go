@@ -0,0 +1,239 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+// maxProvenanceDepth caps ProvenanceTree recursion. Provenance is acyclic by
+// construction (the engine rejects sync cycles), so hitting the cap indicates
+// a corrupted store rather than a legitimately deep flow.
+const maxProvenanceDepth = 256
+
+// InvocationNotFoundError is returned when a requested invocation does not exist.
+type InvocationNotFoundError struct {
+	InvocationID string
+}
+
+func (e *InvocationNotFoundError) Error() string {
+	return fmt.Sprintf("invocation %s not found", e.InvocationID)
+}
+
+// IsInvocationNotFoundError returns true if the error is an InvocationNotFoundError.
+func IsInvocationNotFoundError(err error) bool {
+	var e *InvocationNotFoundError
+	return errors.As(err, &e)
+}
+
+// ProvenanceNode is one invocation in a provenance tree.
+type ProvenanceNode struct {
+	Invocation ir.Invocation
+
+	// Completion is nil if the invocation has not completed yet.
+	Completion *ir.Completion
+
+	// Children are the invocations triggered by this node's completion,
+	// ordered by sync firing seq ASC, then invocation ID (as ReadTriggered).
+	Children []*ProvenanceNode
+}
+
+// ProvenanceTree returns the full causal subtree rooted at an invocation by
+// following completion → sync firing → invocation edges forward.
+//
+// Returns *InvocationNotFoundError if the root does not exist. Returns an
+// error if a cycle is found or the tree is deeper than maxProvenanceDepth.
+func (s *Store) ProvenanceTree(ctx context.Context, rootInvocationID string) (*ProvenanceNode, error) {
+	root, err := s.ReadInvocation(ctx, rootInvocationID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, &InvocationNotFoundError{InvocationID: rootInvocationID}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("provenance tree: %w", err)
+	}
+
+	node, err := s.buildProvenanceNode(ctx, root, map[string]bool{}, 0)
+	if err != nil {
+		return nil, fmt.Errorf("provenance tree: %w", err)
+	}
+	return node, nil
+}
+
+// buildProvenanceNode recursively expands an invocation. ancestors holds the
+// invocation IDs on the current path for cycle detection.
+func (s *Store) buildProvenanceNode(ctx context.Context, inv ir.Invocation, ancestors map[string]bool, depth int) (*ProvenanceNode, error) {
+	if depth >= maxProvenanceDepth {
+		return nil, fmt.Errorf("exceeded max depth %d at invocation %s", maxProvenanceDepth, inv.ID)
+	}
+	if ancestors[inv.ID] {
+		return nil, fmt.Errorf("cycle detected at invocation %s", inv.ID)
+	}
+
+	node := &ProvenanceNode{
+		Invocation: inv,
+		Children:   []*ProvenanceNode{},
+	}
+
+	comp, found, err := s.readCompletionForInvocation(ctx, inv.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return node, nil
+	}
+	node.Completion = &comp
+
+	triggered, err := s.ReadTriggered(ctx, comp.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	ancestors[inv.ID] = true
+	defer delete(ancestors, inv.ID)
+
+	for _, child := range triggered {
+		childNode, err := s.buildProvenanceNode(ctx, child, ancestors, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		node.Children = append(node.Children, childNode)
+	}
+
+	return node, nil
+}
+
+// readCompletionForInvocation returns the completion for an invocation, if any.
+// Each invocation has at most one completion (UNIQUE invocation_id).
+func (s *Store) readCompletionForInvocation(ctx context.Context, invocationID string) (ir.Completion, bool, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, invocation_id, output_case, result, seq, security_context
+		FROM completions
+		WHERE invocation_id = ?
+	`, invocationID)
+	if err != nil {
+		return ir.Completion{}, false, fmt.Errorf("query completion for invocation: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return ir.Completion{}, false, fmt.Errorf("iterate completion for invocation: %w", err)
+		}
+		return ir.Completion{}, false, nil
+	}
+
+	comp, err := scanCompletion(rows)
+	if err != nil {
+		return ir.Completion{}, false, err
+	}
+	return comp, true, nil
+}
+
+// ExplainProvenance renders a human-readable account of why an invocation
+// happened by walking provenance edges backward to the flow's root(s). Each
+// line explains one invocation, starting with the requested one:
+//
+//	Inventory.reserve(item=widget) was triggered by sync-reserve firing on completion of Cart.checkout (seq 42)
+//	Cart.checkout(cart_id=c1) was initiated externally
+//
+// An invocation with several provenance edges gets one line per edge, in
+// ReadProvenance order, and its causes are explained depth-first in that
+// order. Each invocation is explained once, so the output is deterministic
+// and finite.
+//
+// Returns *InvocationNotFoundError if the invocation does not exist.
+func (s *Store) ExplainProvenance(ctx context.Context, invocationID string) (string, error) {
+	inv, err := s.ReadInvocation(ctx, invocationID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", &InvocationNotFoundError{InvocationID: invocationID}
+	}
+	if err != nil {
+		return "", fmt.Errorf("explain provenance: %w", err)
+	}
+
+	var lines []string
+	if err := s.explainInvocation(ctx, inv, map[string]bool{}, 0, &lines); err != nil {
+		return "", fmt.Errorf("explain provenance: %w", err)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// explainInvocation appends the lines for inv and, recursively, its causes.
+// explained holds the invocations already rendered.
+func (s *Store) explainInvocation(ctx context.Context, inv ir.Invocation, explained map[string]bool, depth int, lines *[]string) error {
+	if depth >= maxProvenanceDepth {
+		return fmt.Errorf("exceeded max depth %d at invocation %s", maxProvenanceDepth, inv.ID)
+	}
+	if explained[inv.ID] {
+		return nil
+	}
+	explained[inv.ID] = true
+
+	subject := fmt.Sprintf("%s(%s)", inv.ActionURI, formatExplainArgs(inv.Args))
+
+	edges, err := s.ReadProvenance(ctx, inv.ID)
+	if err != nil {
+		return err
+	}
+	if len(edges) == 0 {
+		*lines = append(*lines, subject+" was initiated externally")
+		return nil
+	}
+
+	parents := make([]ir.Invocation, 0, len(edges))
+	for _, edge := range edges {
+		firing, err := s.ReadSyncFiring(ctx, edge.SyncFiringID)
+		if err != nil {
+			return fmt.Errorf("read sync firing %d: %w", edge.SyncFiringID, err)
+		}
+		comp, err := s.ReadCompletion(ctx, firing.CompletionID)
+		if err != nil {
+			return fmt.Errorf("read completion %s: %w", firing.CompletionID, err)
+		}
+		parent, err := s.ReadInvocation(ctx, comp.InvocationID)
+		if err != nil {
+			return fmt.Errorf("read invocation %s: %w", comp.InvocationID, err)
+		}
+
+		*lines = append(*lines, fmt.Sprintf("%s was triggered by %s firing on completion of %s (seq %d)",
+			subject, firing.SyncID, parent.ActionURI, comp.Seq))
+		parents = append(parents, parent)
+	}
+
+	for _, parent := range parents {
+		if err := s.explainInvocation(ctx, parent, explained, depth+1, lines); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatExplainArgs renders args as "k1=v1, k2=v2" with sorted keys. Strings
+// are shown bare; other values as canonical JSON.
+func formatExplainArgs(args ir.IRObject) string {
+	keys := make([]string, 0, len(args))
+	for k := range args {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		if str, ok := args[k].(ir.IRString); ok {
+			parts[i] = k + "=" + string(str)
+			continue
+		}
+		data, err := ir.CanonicalJSON(args[k])
+		if err != nil {
+			parts[i] = fmt.Sprintf("%s=<%v>", k, err)
+			continue
+		}
+		parts[i] = k + "=" + string(data)
+	}
+	return strings.Join(parts, ", ")
+}
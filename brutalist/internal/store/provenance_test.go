@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/roach88/nysm/internal/ir"
@@ -30,7 +31,7 @@ func TestWriteProvenanceEdge_Basic(t *testing.T) {
 	store.WriteInvocation(ctx, inv2)
 
 	// Write provenance edge
-	err := store.WriteProvenanceEdge(ctx, firingID, "inv-2")
+	_, err := store.WriteProvenanceEdge(ctx, firingID, "inv-2")
 	if err != nil {
 		t.Fatalf("WriteProvenanceEdge failed: %v", err)
 	}
@@ -69,15 +70,21 @@ func TestWriteProvenanceEdge_Idempotency(t *testing.T) {
 	store.WriteInvocation(ctx, inv2)
 
 	// Write edge twice
-	err := store.WriteProvenanceEdge(ctx, firingID, "inv-2")
+	inserted, err := store.WriteProvenanceEdge(ctx, firingID, "inv-2")
 	if err != nil {
 		t.Fatalf("First WriteProvenanceEdge failed: %v", err)
 	}
+	if !inserted {
+		t.Error("first WriteProvenanceEdge reported inserted=false")
+	}
 
-	err = store.WriteProvenanceEdge(ctx, firingID, "inv-2")
+	inserted, err = store.WriteProvenanceEdge(ctx, firingID, "inv-2")
 	if err != nil {
 		t.Fatalf("Second WriteProvenanceEdge should not fail: %v", err)
 	}
+	if inserted {
+		t.Error("second WriteProvenanceEdge reported inserted=true")
+	}
 
 	// Should only have one edge (UNIQUE constraint)
 	edges, _ := store.ReadProvenance(ctx, "inv-2")
@@ -252,3 +259,193 @@ func TestProvenanceChain(t *testing.T) {
 		t.Errorf("triggered from A = %q, want %q", triggeredA[0].ID, "inv-b")
 	}
 }
+
+// writeTriggered completes parentInvID and fires one sync per child, each
+// generating an invocation in the same flow.
+func writeTriggered(t *testing.T, s *Store, parentInvID string, seq *int64, childIDs ...string) {
+	t.Helper()
+	ctx := context.Background()
+
+	compID := "comp-" + parentInvID
+	*seq++
+	if err := s.WriteCompletion(ctx, createTestCompletion(compID, parentInvID, "Success", *seq)); err != nil {
+		t.Fatalf("WriteCompletion failed: %v", err)
+	}
+
+	for i, childID := range childIDs {
+		*seq++
+		firing := ir.SyncFiring{
+			CompletionID: compID,
+			SyncID:       "sync-fanout",
+			BindingHash:  fmt.Sprintf("h-%d", i),
+			Seq:          *seq,
+		}
+		*seq++
+		child := createTestInvocation(childID, "flow-1", "Fanout.step", *seq)
+		if _, _, err := s.WriteSyncFiringAtomic(ctx, firing, child); err != nil {
+			t.Fatalf("WriteSyncFiringAtomic failed: %v", err)
+		}
+	}
+}
+
+func TestProvenanceTree_ThreeLevelFanOut(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+
+	seq := int64(1)
+	if err := store.WriteInvocation(ctx, createTestInvocation("root", "flow-1", "Fanout.start", seq)); err != nil {
+		t.Fatalf("WriteInvocation failed: %v", err)
+	}
+
+	// root -> a, b; a -> a1, a2; b -> b1 (b1 left pending)
+	writeTriggered(t, store, "root", &seq, "a", "b")
+	writeTriggered(t, store, "a", &seq, "a1", "a2")
+	writeTriggered(t, store, "b", &seq, "b1")
+	writeTriggered(t, store, "a1", &seq)
+	writeTriggered(t, store, "a2", &seq)
+
+	tree, err := store.ProvenanceTree(ctx, "root")
+	if err != nil {
+		t.Fatalf("ProvenanceTree failed: %v", err)
+	}
+
+	// Render the tree as "id(children...)" for a compact structural comparison
+	var render func(n *ProvenanceNode) string
+	render = func(n *ProvenanceNode) string {
+		out := n.Invocation.ID
+		if n.Completion == nil {
+			out += "*" // Pending
+		}
+		if len(n.Children) > 0 {
+			out += "("
+			for i, c := range n.Children {
+				if i > 0 {
+					out += " "
+				}
+				out += render(c)
+			}
+			out += ")"
+		}
+		return out
+	}
+
+	want := "root(a(a1 a2) b(b1*))"
+	if got := render(tree); got != want {
+		t.Errorf("tree = %s, want %s", got, want)
+	}
+
+	if tree.Completion == nil || tree.Completion.ID != "comp-root" {
+		t.Errorf("root completion = %v, want comp-root", tree.Completion)
+	}
+}
+
+func TestProvenanceTree_Leaf(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+
+	store.WriteInvocation(ctx, createTestInvocation("inv-1", "flow-1", "Action.one", 1))
+
+	tree, err := store.ProvenanceTree(ctx, "inv-1")
+	if err != nil {
+		t.Fatalf("ProvenanceTree failed: %v", err)
+	}
+	if tree.Completion != nil {
+		t.Error("expected nil completion for pending invocation")
+	}
+	if tree.Children == nil || len(tree.Children) != 0 {
+		t.Errorf("children = %v, want empty slice", tree.Children)
+	}
+}
+
+func TestProvenanceTree_RootNotFound(t *testing.T) {
+	store := createTestStore(t)
+
+	_, err := store.ProvenanceTree(context.Background(), "missing")
+	if err == nil {
+		t.Fatal("expected error for missing root")
+	}
+	if !IsInvocationNotFoundError(err) {
+		t.Errorf("error = %v, want InvocationNotFoundError", err)
+	}
+}
+
+// writeCausalChain writes Cart.checkout → sync-reserve → Inventory.reserve →
+// sync-ship → Shipping.schedule in flow-1. Shipping.schedule stays pending.
+func writeCausalChain(t *testing.T, s *Store) {
+	t.Helper()
+	ctx := context.Background()
+
+	checkout := createTestInvocation("inv-checkout", "flow-1", "Cart.checkout", 1)
+	checkout.Args = ir.IRObject{"cart_id": ir.IRString("c1")}
+	if err := s.WriteInvocation(ctx, checkout); err != nil {
+		t.Fatalf("WriteInvocation failed: %v", err)
+	}
+	if err := s.WriteCompletion(ctx, createTestCompletion("comp-checkout", "inv-checkout", "Success", 2)); err != nil {
+		t.Fatalf("WriteCompletion failed: %v", err)
+	}
+
+	reserve := createTestInvocation("inv-reserve", "flow-1", "Inventory.reserve", 4)
+	reserve.Args = ir.IRObject{"item": ir.IRString("widget"), "quantity": ir.IRInt(2)}
+	firing := ir.SyncFiring{CompletionID: "comp-checkout", SyncID: "sync-reserve", BindingHash: "h-reserve", Seq: 3}
+	if _, _, err := s.WriteSyncFiringAtomic(ctx, firing, reserve); err != nil {
+		t.Fatalf("WriteSyncFiringAtomic failed: %v", err)
+	}
+	if err := s.WriteCompletion(ctx, createTestCompletion("comp-reserve", "inv-reserve", "Success", 42)); err != nil {
+		t.Fatalf("WriteCompletion failed: %v", err)
+	}
+
+	schedule := createTestInvocation("inv-schedule", "flow-1", "Shipping.schedule", 44)
+	schedule.Args = ir.IRObject{"item": ir.IRString("widget")}
+	firing = ir.SyncFiring{CompletionID: "comp-reserve", SyncID: "sync-ship", BindingHash: "h-ship", Seq: 43}
+	if _, _, err := s.WriteSyncFiringAtomic(ctx, firing, schedule); err != nil {
+		t.Fatalf("WriteSyncFiringAtomic failed: %v", err)
+	}
+}
+
+func TestExplainProvenance_TwoLevelChain(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+	writeCausalChain(t, store)
+
+	got, err := store.ExplainProvenance(ctx, "inv-schedule")
+	if err != nil {
+		t.Fatalf("ExplainProvenance failed: %v", err)
+	}
+
+	want := "Shipping.schedule(item=widget) was triggered by sync-ship firing on completion of Inventory.reserve (seq 42)\n" +
+		"Inventory.reserve(item=widget, quantity=2) was triggered by sync-reserve firing on completion of Cart.checkout (seq 2)\n" +
+		"Cart.checkout(cart_id=c1) was initiated externally"
+	if got != want {
+		t.Errorf("narrative:\n%s\nwant:\n%s", got, want)
+	}
+
+	again, err := store.ExplainProvenance(ctx, "inv-schedule")
+	if err != nil {
+		t.Fatalf("ExplainProvenance failed: %v", err)
+	}
+	if again != got {
+		t.Errorf("narrative not deterministic:\n%s\nthen:\n%s", got, again)
+	}
+}
+
+func TestExplainProvenance_Root(t *testing.T) {
+	store := createTestStore(t)
+	writeCausalChain(t, store)
+
+	got, err := store.ExplainProvenance(context.Background(), "inv-checkout")
+	if err != nil {
+		t.Fatalf("ExplainProvenance failed: %v", err)
+	}
+	if want := "Cart.checkout(cart_id=c1) was initiated externally"; got != want {
+		t.Errorf("narrative = %q, want %q", got, want)
+	}
+}
+
+func TestExplainProvenance_NotFound(t *testing.T) {
+	store := createTestStore(t)
+
+	_, err := store.ExplainProvenance(context.Background(), "missing")
+	if !IsInvocationNotFoundError(err) {
+		t.Errorf("error = %v, want InvocationNotFoundError", err)
+	}
+}
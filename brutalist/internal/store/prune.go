@@ -0,0 +1,117 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// FlowIncompleteError is returned when an operation requires a complete flow
+// but GetFlowState reports pending invocations or orphaned sync firings.
+type FlowIncompleteError struct {
+	FlowToken       string
+	PendingCount    int
+	OrphanedFirings int
+}
+
+func (e *FlowIncompleteError) Error() string {
+	return fmt.Sprintf("flow %s is incomplete: %d pending invocation(s), %d orphaned sync firing(s)",
+		e.FlowToken, e.PendingCount, e.OrphanedFirings)
+}
+
+// IsFlowIncompleteError returns true if the error is a FlowIncompleteError.
+func IsFlowIncompleteError(err error) bool {
+	var e *FlowIncompleteError
+	return errors.As(err, &e)
+}
+
+// PruneFlow deletes every record belonging to a completed flow and returns
+// the number of rows removed across all tables.
+//
+// Rows are deleted in a single transaction in foreign-key dependency order:
+// provenance edges, abandoned firings, sync firings, completions, then
+// invocations. A flow that GetFlowState reports as incomplete is refused with
+// a *FlowIncompleteError, since removing it would break in-flight causality.
+// Pruning an unknown flow is a no-op.
+func (s *Store) PruneFlow(ctx context.Context, flowToken string) (deleted int, err error) {
+	state, err := s.GetFlowState(ctx, flowToken)
+	if err != nil {
+		return 0, fmt.Errorf("prune flow: %w", err)
+	}
+	if len(state.Invocations) == 0 {
+		return 0, nil
+	}
+	if !state.IsComplete {
+		return 0, &FlowIncompleteError{
+			FlowToken:       flowToken,
+			PendingCount:    state.PendingCount,
+			OrphanedFirings: state.OrphanedFirings,
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("prune flow: begin transaction: %w", err)
+	}
+	defer tx.Rollback() // No-op if committed
+
+	// Dependency order: each table is only referenced by tables deleted before it.
+	statements := []struct {
+		table string
+		query string
+		args  []any
+	}{
+		{"provenance_edges", `
+			DELETE FROM provenance_edges
+			WHERE invocation_id IN (SELECT id FROM invocations WHERE flow_token = ?)
+			   OR sync_firing_id IN (
+				SELECT sf.id FROM sync_firings sf
+				JOIN completions c ON sf.completion_id = c.id
+				JOIN invocations i ON c.invocation_id = i.id
+				WHERE i.flow_token = ?
+			   )
+		`, []any{flowToken, flowToken}},
+		{"abandoned_firings", `
+			DELETE FROM abandoned_firings
+			WHERE sync_firing_id IN (
+				SELECT sf.id FROM sync_firings sf
+				JOIN completions c ON sf.completion_id = c.id
+				JOIN invocations i ON c.invocation_id = i.id
+				WHERE i.flow_token = ?
+			)
+		`, []any{flowToken}},
+		{"sync_firings", `
+			DELETE FROM sync_firings
+			WHERE completion_id IN (
+				SELECT c.id FROM completions c
+				JOIN invocations i ON c.invocation_id = i.id
+				WHERE i.flow_token = ?
+			)
+		`, []any{flowToken}},
+		{"completions", `
+			DELETE FROM completions
+			WHERE invocation_id IN (SELECT id FROM invocations WHERE flow_token = ?)
+		`, []any{flowToken}},
+		{"invocations", `
+			DELETE FROM invocations WHERE flow_token = ?
+		`, []any{flowToken}},
+	}
+
+	for _, stmt := range statements {
+		res, err := tx.ExecContext(ctx, stmt.query, stmt.args...)
+		if err != nil {
+			return 0, fmt.Errorf("prune flow: delete %s: %w", stmt.table, err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("prune flow: rows affected for %s: %w", stmt.table, err)
+		}
+		deleted += int(n)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("prune flow: commit: %w", err)
+	}
+
+	return deleted, nil
+}
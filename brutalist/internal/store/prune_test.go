@@ -0,0 +1,125 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+// countRows returns the number of rows in a table.
+func countRows(t *testing.T, s *Store, table string) int {
+	t.Helper()
+	var n int
+	if err := s.DB().QueryRow("SELECT COUNT(*) FROM " + table).Scan(&n); err != nil {
+		t.Fatalf("count %s: %v", table, err)
+	}
+	return n
+}
+
+// writePrunableFlow writes a complete flow: one root invocation whose
+// completion fires a sync that generates a second, completed invocation.
+func writePrunableFlow(t *testing.T, s *Store, flowToken, prefix string, baseSeq int64) {
+	t.Helper()
+	ctx := context.Background()
+
+	if err := s.WriteInvocation(ctx, createTestInvocation(prefix+"inv-1", flowToken, "Cart.checkout", baseSeq)); err != nil {
+		t.Fatalf("WriteInvocation failed: %v", err)
+	}
+	if err := s.WriteCompletion(ctx, createTestCompletion(prefix+"comp-1", prefix+"inv-1", "Success", baseSeq+1)); err != nil {
+		t.Fatalf("WriteCompletion failed: %v", err)
+	}
+	_, _, err := s.WriteSyncFiringAtomic(ctx,
+		ir.SyncFiring{CompletionID: prefix + "comp-1", SyncID: "sync-1", BindingHash: "h1", Seq: baseSeq + 2},
+		createTestInvocation(prefix+"inv-2", flowToken, "Inventory.reserve", baseSeq+3),
+	)
+	if err != nil {
+		t.Fatalf("WriteSyncFiringAtomic failed: %v", err)
+	}
+	if err := s.WriteCompletion(ctx, createTestCompletion(prefix+"comp-2", prefix+"inv-2", "Success", baseSeq+4)); err != nil {
+		t.Fatalf("WriteCompletion failed: %v", err)
+	}
+}
+
+func TestPruneFlow_CompleteFlow(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+
+	writePrunableFlow(t, store, "flow-1", "a-", 1)
+	writePrunableFlow(t, store, "flow-2", "b-", 10)
+
+	deleted, err := store.PruneFlow(ctx, "flow-1")
+	if err != nil {
+		t.Fatalf("PruneFlow failed: %v", err)
+	}
+
+	// 2 invocations + 2 completions + 1 sync firing + 1 provenance edge
+	if deleted != 6 {
+		t.Errorf("deleted = %d, want 6", deleted)
+	}
+
+	invs, comps, err := store.ReadFlow(ctx, "flow-1")
+	if err != nil {
+		t.Fatalf("ReadFlow failed: %v", err)
+	}
+	if len(invs) != 0 || len(comps) != 0 {
+		t.Errorf("flow-1 still has %d invocations, %d completions", len(invs), len(comps))
+	}
+
+	// The other flow is untouched
+	for table, want := range map[string]int{
+		"invocations":      2,
+		"completions":      2,
+		"sync_firings":     1,
+		"provenance_edges": 1,
+	} {
+		if got := countRows(t, store, table); got != want {
+			t.Errorf("%s rows = %d, want %d", table, got, want)
+		}
+	}
+}
+
+func TestPruneFlow_IncompleteFlowRefused(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+
+	writePrunableFlow(t, store, "flow-1", "a-", 1)
+	// Pending invocation: no completion yet
+	if err := store.WriteInvocation(ctx, createTestInvocation("a-inv-3", "flow-1", "Cart.confirm", 20)); err != nil {
+		t.Fatalf("WriteInvocation failed: %v", err)
+	}
+
+	deleted, err := store.PruneFlow(ctx, "flow-1")
+	if err == nil {
+		t.Fatal("PruneFlow succeeded for incomplete flow, want error")
+	}
+	if !IsFlowIncompleteError(err) {
+		t.Fatalf("error = %v, want FlowIncompleteError", err)
+	}
+	if deleted != 0 {
+		t.Errorf("deleted = %d, want 0", deleted)
+	}
+
+	for table, want := range map[string]int{
+		"invocations":      3,
+		"completions":      2,
+		"sync_firings":     1,
+		"provenance_edges": 1,
+	} {
+		if got := countRows(t, store, table); got != want {
+			t.Errorf("%s rows = %d, want %d", table, got, want)
+		}
+	}
+}
+
+func TestPruneFlow_UnknownFlow(t *testing.T) {
+	store := createTestStore(t)
+
+	deleted, err := store.PruneFlow(context.Background(), "no-such-flow")
+	if err != nil {
+		t.Fatalf("PruneFlow failed: %v", err)
+	}
+	if deleted != 0 {
+		t.Errorf("deleted = %d, want 0", deleted)
+	}
+}
@@ -98,6 +98,43 @@ func (s *Store) readFlowCompletions(ctx context.Context, flowToken string) ([]ir
 	return completions, nil
 }
 
+// readFlowSyncFiringsByCompletion returns all sync firings triggered by a
+// flow's completions. Unlike readFlowSyncFirings (archive order), firings are
+// grouped by completion in completion order (seq ASC, id ASC COLLATE BINARY),
+// then ordered by firing seq ASC, id ASC - the same result as concatenating
+// ReadSyncFiringsForCompletion over the flow's completions.
+//
+// Returns nil if the flow has no sync firings.
+func (s *Store) readFlowSyncFiringsByCompletion(ctx context.Context, flowToken string) ([]ir.SyncFiring, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT sf.id, sf.completion_id, sf.sync_id, sf.binding_hash, sf.seq
+		FROM sync_firings sf
+		JOIN completions c ON sf.completion_id = c.id
+		JOIN invocations i ON c.invocation_id = i.id
+		WHERE i.flow_token = ?
+		ORDER BY c.seq ASC, c.id COLLATE BINARY ASC, sf.seq ASC, sf.id ASC
+	`, flowToken)
+	if err != nil {
+		return nil, fmt.Errorf("query flow sync firings: %w", err)
+	}
+	defer rows.Close()
+
+	var firings []ir.SyncFiring
+	for rows.Next() {
+		var f ir.SyncFiring
+		if err := rows.Scan(&f.ID, &f.CompletionID, &f.SyncID, &f.BindingHash, &f.Seq); err != nil {
+			return nil, fmt.Errorf("scan flow sync firing: %w", err)
+		}
+		firings = append(firings, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate flow sync firings: %w", err)
+	}
+
+	return firings, nil
+}
+
 // ReadInvocation retrieves a single invocation by ID.
 // Returns sql.ErrNoRows if not found.
 func (s *Store) ReadInvocation(ctx context.Context, id string) (ir.Invocation, error) {
@@ -188,6 +225,71 @@ func (s *Store) ReadAllCompletions(ctx context.Context) ([]ir.Completion, error)
 	return completions, nil
 }
 
+// ReadCompletionsByOutputCase returns the completions in a flow with the given
+// output case (e.g., every InsufficientStock failure), joined to invocations
+// for flow filtering. Results ordered by seq ASC, id ASC per CP-4.
+// Returns an empty slice if none match.
+func (s *Store) ReadCompletionsByOutputCase(ctx context.Context, flowToken, outputCase string) ([]ir.Completion, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT c.id, c.invocation_id, c.output_case, c.result, c.seq, c.security_context
+		FROM completions c
+		JOIN invocations i ON c.invocation_id = i.id
+		WHERE i.flow_token = ? AND c.output_case = ?
+		ORDER BY c.seq ASC, c.id COLLATE BINARY ASC
+	`, flowToken, outputCase)
+	if err != nil {
+		return nil, fmt.Errorf("query completions by output case: %w", err)
+	}
+	defer rows.Close()
+
+	completions := []ir.Completion{}
+	for rows.Next() {
+		comp, err := scanCompletion(rows)
+		if err != nil {
+			return nil, err
+		}
+		completions = append(completions, comp)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate completions: %w", err)
+	}
+
+	return completions, nil
+}
+
+// ReadInvocationsByAction returns the invocations of action in a flow (e.g.,
+// every Inventory.reserve), whether invoked directly or generated by syncs.
+// Results ordered by seq ASC, id ASC per CP-4.
+// Returns an empty slice if none match.
+func (s *Store) ReadInvocationsByAction(ctx context.Context, flowToken string, action ir.ActionRef) ([]ir.Invocation, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, flow_token, action_uri, args, seq, security_context, spec_hash, engine_version, ir_version
+		FROM invocations
+		WHERE flow_token = ? AND action_uri = ?
+		ORDER BY seq ASC, id COLLATE BINARY ASC
+	`, flowToken, string(action))
+	if err != nil {
+		return nil, fmt.Errorf("query invocations by action: %w", err)
+	}
+	defer rows.Close()
+
+	invocations := []ir.Invocation{}
+	for rows.Next() {
+		inv, err := scanInvocation(rows)
+		if err != nil {
+			return nil, err
+		}
+		invocations = append(invocations, inv)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate invocations: %w", err)
+	}
+
+	return invocations, nil
+}
+
 // scanInvocation scans a row into an Invocation struct.
 func scanInvocation(rows *sql.Rows) (ir.Invocation, error) {
 	var inv ir.Invocation
@@ -498,6 +600,7 @@ func (s *Store) countOrphanedFiringsForCompletions(ctx context.Context, completi
 		LEFT JOIN provenance_edges pe ON sf.id = pe.sync_firing_id
 		WHERE sf.completion_id IN (` + string(placeholders) + `)
 		AND pe.id IS NULL
+		AND NOT EXISTS (SELECT 1 FROM abandoned_firings af WHERE af.sync_firing_id = sf.id)
 	`
 	err := s.db.QueryRowContext(ctx, query, args...).Scan(&count)
 	if err != nil {
@@ -508,7 +611,8 @@ func (s *Store) countOrphanedFiringsForCompletions(ctx context.Context, completi
 
 // FindOrphanedSyncFirings returns all sync firings that don't have provenance edges.
 // These represent incomplete crash recovery scenarios where sync fired but the
-// triggered invocation was never created.
+// triggered invocation was never created. Firings already recorded as abandoned
+// by RepairOrphanedFiring are excluded.
 // Results ordered by seq ASC, id ASC per CP-4.
 func (s *Store) FindOrphanedSyncFirings(ctx context.Context) ([]ir.SyncFiring, error) {
 	rows, err := s.db.QueryContext(ctx, `
@@ -516,6 +620,7 @@ func (s *Store) FindOrphanedSyncFirings(ctx context.Context) ([]ir.SyncFiring, e
 		FROM sync_firings sf
 		LEFT JOIN provenance_edges pe ON sf.id = pe.sync_firing_id
 		WHERE pe.id IS NULL
+		  AND NOT EXISTS (SELECT 1 FROM abandoned_firings af WHERE af.sync_firing_id = sf.id)
 		ORDER BY sf.seq ASC, sf.id ASC
 	`)
 	if err != nil {
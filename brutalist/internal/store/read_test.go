@@ -112,7 +112,7 @@ func TestReadFlow_InvocationWithCompletion(t *testing.T) {
 		ActionURI:       ir.ActionRef("Cart.addItem"),
 		Args:            ir.IRObject{},
 		Seq:             1,
-		SecurityContext: ir.SecurityContext{},
+		SecurityContext: testSecurityContext,
 		SpecHash:        "hash",
 		EngineVersion:   "0.1.0",
 		IRVersion:       "1",
@@ -129,6 +129,7 @@ func TestReadFlow_InvocationWithCompletion(t *testing.T) {
 		Seq: 2,
 		SecurityContext: ir.SecurityContext{
 			TenantID: "tenant-1",
+			UserID:   "user-1",
 		},
 	}
 	s.WriteCompletion(context.Background(), comp)
@@ -180,7 +181,7 @@ func TestReadFlow_DeterministicOrdering(t *testing.T) {
 			ActionURI:       ir.ActionRef("Test.action"),
 			Args:            ir.IRObject{},
 			Seq:             seq,
-			SecurityContext: ir.SecurityContext{},
+			SecurityContext: testSecurityContext,
 			SpecHash:        "hash",
 			EngineVersion:   "0.1.0",
 			IRVersion:       "1",
@@ -225,7 +226,7 @@ func TestReadFlow_DeterministicOrderingWithSameSeq(t *testing.T) {
 			ActionURI:       ir.ActionRef("Test.action"),
 			Args:            ir.IRObject{},
 			Seq:             1, // Same seq for all
-			SecurityContext: ir.SecurityContext{},
+			SecurityContext: testSecurityContext,
 			SpecHash:        "hash",
 			EngineVersion:   "0.1.0",
 			IRVersion:       "1",
@@ -269,7 +270,7 @@ func TestReadFlow_CompletionOrdering(t *testing.T) {
 			ActionURI:       ir.ActionRef("Test.action"),
 			Args:            ir.IRObject{},
 			Seq:             int64(i * 2 - 1), // 1, 3, 5
-			SecurityContext: ir.SecurityContext{},
+			SecurityContext: testSecurityContext,
 			SpecHash:        "hash",
 			EngineVersion:   "0.1.0",
 			IRVersion:       "1",
@@ -282,7 +283,7 @@ func TestReadFlow_CompletionOrdering(t *testing.T) {
 			OutputCase:      "Success",
 			Result:          ir.IRObject{},
 			Seq:             int64(i * 2), // 2, 4, 6
-			SecurityContext: ir.SecurityContext{},
+			SecurityContext: testSecurityContext,
 		}
 		s.WriteCompletion(context.Background(), comp)
 	}
@@ -321,7 +322,7 @@ func TestReadFlow_MultipleFlows(t *testing.T) {
 			ActionURI:       ir.ActionRef("Test.action"),
 			Args:            ir.IRObject{},
 			Seq:             int64(i),
-			SecurityContext: ir.SecurityContext{},
+			SecurityContext: testSecurityContext,
 			SpecHash:        "hash",
 			EngineVersion:   "0.1.0",
 			IRVersion:       "1",
@@ -334,7 +335,7 @@ func TestReadFlow_MultipleFlows(t *testing.T) {
 			ActionURI:       ir.ActionRef("Test.action"),
 			Args:            ir.IRObject{},
 			Seq:             int64(i + 10),
-			SecurityContext: ir.SecurityContext{},
+			SecurityContext: testSecurityContext,
 			SpecHash:        "hash",
 			EngineVersion:   "0.1.0",
 			IRVersion:       "1",
@@ -391,6 +392,7 @@ func TestReadInvocation_Exists(t *testing.T) {
 		Seq: 1,
 		SecurityContext: ir.SecurityContext{
 			TenantID: "tenant-1",
+			UserID:   "user-1",
 		},
 		SpecHash:      "hash",
 		EngineVersion: "0.1.0",
@@ -448,7 +450,7 @@ func TestReadCompletion_Exists(t *testing.T) {
 		ActionURI:       ir.ActionRef("Test.action"),
 		Args:            ir.IRObject{},
 		Seq:             1,
-		SecurityContext: ir.SecurityContext{},
+		SecurityContext: testSecurityContext,
 		SpecHash:        "hash",
 		EngineVersion:   "0.1.0",
 		IRVersion:       "1",
@@ -464,7 +466,8 @@ func TestReadCompletion_Exists(t *testing.T) {
 		},
 		Seq: 2,
 		SecurityContext: ir.SecurityContext{
-			UserID: "user-1",
+			TenantID: "tenant-1",
+			UserID:   "user-1",
 		},
 	}
 	s.WriteCompletion(context.Background(), comp)
@@ -543,7 +546,7 @@ func TestReadAllInvocations_DeterministicOrdering(t *testing.T) {
 			ActionURI:       ir.ActionRef("Test.action"),
 			Args:            ir.IRObject{},
 			Seq:             seq,
-			SecurityContext: ir.SecurityContext{},
+			SecurityContext: testSecurityContext,
 			SpecHash:        "hash",
 			EngineVersion:   "0.1.0",
 			IRVersion:       "1",
@@ -607,7 +610,7 @@ func TestReadAllCompletions_DeterministicOrdering(t *testing.T) {
 			ActionURI:       ir.ActionRef("Test.action"),
 			Args:            ir.IRObject{},
 			Seq:             seq - 1,
-			SecurityContext: ir.SecurityContext{},
+			SecurityContext: testSecurityContext,
 			SpecHash:        "hash",
 			EngineVersion:   "0.1.0",
 			IRVersion:       "1",
@@ -620,7 +623,7 @@ func TestReadAllCompletions_DeterministicOrdering(t *testing.T) {
 			OutputCase:      "Success",
 			Result:          ir.IRObject{},
 			Seq:             seq,
-			SecurityContext: ir.SecurityContext{},
+			SecurityContext: testSecurityContext,
 		}
 		s.WriteCompletion(context.Background(), comp)
 	}
@@ -663,7 +666,7 @@ func TestReadFlow_ArgsUnmarshaling(t *testing.T) {
 			"nested":  ir.IRObject{"inner": ir.IRString("value")},
 		},
 		Seq:             1,
-		SecurityContext: ir.SecurityContext{},
+		SecurityContext: testSecurityContext,
 		SpecHash:        "hash",
 		EngineVersion:   "0.1.0",
 		IRVersion:       "1",
@@ -716,3 +719,132 @@ func TestReadFlow_ArgsUnmarshaling(t *testing.T) {
 		}
 	}
 }
+
+func TestReadCompletionsByOutputCase_MixedCases(t *testing.T) {
+	s := createTestStore(t)
+	ctx := context.Background()
+
+	// Written out of seq order, with an ID tie-break at seq 12
+	records := []struct {
+		invID, compID, flow, outputCase string
+		invSeq, compSeq                 int64
+	}{
+		{"inv-c", "comp-c", "flow-a", "InsufficientStock", 5, 12},
+		{"inv-a", "comp-a", "flow-a", "Success", 1, 10},
+		{"inv-b", "comp-b", "flow-a", "InsufficientStock", 2, 11},
+		{"inv-d", "comp-b2", "flow-a", "InsufficientStock", 6, 12},
+		{"inv-e", "comp-e", "flow-b", "InsufficientStock", 3, 13},
+	}
+	for _, r := range records {
+		if err := s.WriteInvocation(ctx, createTestInvocation(r.invID, r.flow, "Inventory.reserve", r.invSeq)); err != nil {
+			t.Fatalf("WriteInvocation(%s) failed: %v", r.invID, err)
+		}
+		if err := s.WriteCompletion(ctx, createTestCompletion(r.compID, r.invID, r.outputCase, r.compSeq)); err != nil {
+			t.Fatalf("WriteCompletion(%s) failed: %v", r.compID, err)
+		}
+	}
+
+	completions, err := s.ReadCompletionsByOutputCase(ctx, "flow-a", "InsufficientStock")
+	if err != nil {
+		t.Fatalf("ReadCompletionsByOutputCase() failed: %v", err)
+	}
+
+	want := []string{"comp-b", "comp-b2", "comp-c"}
+	if len(completions) != len(want) {
+		t.Fatalf("len(completions) = %d, want %d", len(completions), len(want))
+	}
+	for i, id := range want {
+		if completions[i].ID != id {
+			t.Errorf("completions[%d].ID = %q, want %q", i, completions[i].ID, id)
+		}
+		if completions[i].OutputCase != "InsufficientStock" {
+			t.Errorf("completions[%d].OutputCase = %q, want InsufficientStock", i, completions[i].OutputCase)
+		}
+	}
+}
+
+func TestReadInvocationsByAction_MultipleActions(t *testing.T) {
+	s := createTestStore(t)
+	ctx := context.Background()
+
+	// Written out of seq order, with an ID tie-break at seq 4
+	records := []struct {
+		id, flow, action string
+		seq              int64
+	}{
+		{"inv-e", "flow-a", "Inventory.reserve", 7},
+		{"inv-a", "flow-a", "Cart.checkout", 1},
+		{"inv-c", "flow-a", "Inventory.reserve", 4},
+		{"inv-b", "flow-a", "Inventory.reserve", 4},
+		{"inv-d", "flow-a", "Payment.charge", 5},
+		{"inv-f", "flow-b", "Inventory.reserve", 2},
+	}
+	for _, r := range records {
+		if err := s.WriteInvocation(ctx, createTestInvocation(r.id, r.flow, r.action, r.seq)); err != nil {
+			t.Fatalf("WriteInvocation(%s) failed: %v", r.id, err)
+		}
+	}
+
+	invocations, err := s.ReadInvocationsByAction(ctx, "flow-a", "Inventory.reserve")
+	if err != nil {
+		t.Fatalf("ReadInvocationsByAction() failed: %v", err)
+	}
+
+	want := []string{"inv-b", "inv-c", "inv-e"}
+	if len(invocations) != len(want) {
+		t.Fatalf("len(invocations) = %d, want %d", len(invocations), len(want))
+	}
+	for i, id := range want {
+		if invocations[i].ID != id {
+			t.Errorf("invocations[%d].ID = %q, want %q", i, invocations[i].ID, id)
+		}
+		if invocations[i].ActionURI != "Inventory.reserve" || invocations[i].FlowToken != "flow-a" {
+			t.Errorf("invocations[%d] = %s in %s, want Inventory.reserve in flow-a", i, invocations[i].ActionURI, invocations[i].FlowToken)
+		}
+	}
+
+	// No match: empty slice, not nil
+	for _, tc := range []struct {
+		flow   string
+		action ir.ActionRef
+	}{
+		{"flow-a", "Shipping.schedule"},
+		{"flow-missing", "Inventory.reserve"},
+	} {
+		invocations, err := s.ReadInvocationsByAction(ctx, tc.flow, tc.action)
+		if err != nil {
+			t.Fatalf("ReadInvocationsByAction(%s, %s) failed: %v", tc.flow, tc.action, err)
+		}
+		if invocations == nil || len(invocations) != 0 {
+			t.Errorf("ReadInvocationsByAction(%s, %s) = %v, want empty slice", tc.flow, tc.action, invocations)
+		}
+	}
+}
+
+func TestReadCompletionsByOutputCase_NoMatch(t *testing.T) {
+	s := createTestStore(t)
+	ctx := context.Background()
+
+	if err := s.WriteInvocation(ctx, createTestInvocation("inv-1", "flow-a", "Cart.checkout", 1)); err != nil {
+		t.Fatalf("WriteInvocation() failed: %v", err)
+	}
+	if err := s.WriteCompletion(ctx, createTestCompletion("comp-1", "inv-1", "Success", 2)); err != nil {
+		t.Fatalf("WriteCompletion() failed: %v", err)
+	}
+
+	for _, tc := range []struct{ flow, outputCase string }{
+		{"flow-a", "InsufficientStock"},
+		{"flow-missing", "Success"},
+	} {
+		completions, err := s.ReadCompletionsByOutputCase(ctx, tc.flow, tc.outputCase)
+		if err != nil {
+			t.Fatalf("ReadCompletionsByOutputCase(%s, %s) failed: %v", tc.flow, tc.outputCase, err)
+		}
+		if completions == nil {
+			t.Errorf("ReadCompletionsByOutputCase(%s, %s) returned nil, want empty slice", tc.flow, tc.outputCase)
+		}
+		if len(completions) != 0 {
+			t.Errorf("len(completions) = %d, want 0", len(completions))
+		}
+	}
+}
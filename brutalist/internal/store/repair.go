@@ -0,0 +1,185 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+// RepairOutcome describes how an orphaned sync firing was resolved.
+type RepairOutcome string
+
+const (
+	// RepairNotOrphaned means the firing already had a provenance edge or was
+	// already abandoned; nothing was changed.
+	RepairNotOrphaned RepairOutcome = "not_orphaned"
+
+	// RepairLinked means the intended invocation was already in the store
+	// (crash between invocation write and provenance write) and was linked.
+	RepairLinked RepairOutcome = "linked"
+
+	// RepairRegenerated means the intended invocation was reconstructed and
+	// written along with its provenance edge.
+	RepairRegenerated RepairOutcome = "regenerated"
+
+	// RepairAbandoned means the intended invocation could not be recovered;
+	// the firing is recorded in abandoned_firings and no longer counts as orphaned.
+	RepairAbandoned RepairOutcome = "abandoned"
+)
+
+// RepairOrphanedFiring resolves an orphaned sync firing using only data in
+// the store. The engine generates a sync's invocation immediately before the
+// firing (seq = firing seq - 1), so an unlinked invocation at that seq in the
+// same flow is the one the firing produced; it is linked with a provenance
+// edge. Otherwise the firing is recorded as abandoned with reason, or a
+// generic reason if it is empty.
+//
+// Engine.Recover tries to regenerate the invocation from the sync rule first
+// and only falls back to this method when that is not possible, passing why
+// regeneration failed as the reason.
+func (s *Store) RepairOrphanedFiring(ctx context.Context, firingID int64, reason string) (RepairOutcome, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("repair orphaned firing: begin tx: %w", err)
+	}
+	defer tx.Rollback() // No-op if committed
+
+	firing, flowToken, orphaned, err := readOrphanCandidate(ctx, tx, firingID)
+	if err != nil {
+		return "", fmt.Errorf("repair orphaned firing: %w", err)
+	}
+	if !orphaned {
+		return RepairNotOrphaned, nil
+	}
+
+	var invocationID string
+	err = tx.QueryRowContext(ctx, `
+		SELECT i.id
+		FROM invocations i
+		LEFT JOIN provenance_edges pe ON pe.invocation_id = i.id
+		WHERE i.flow_token = ? AND i.seq = ? AND pe.id IS NULL
+		ORDER BY i.id COLLATE BINARY ASC
+		LIMIT 1
+	`, flowToken, firing.Seq-1).Scan(&invocationID)
+
+	outcome := RepairLinked
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		outcome = RepairAbandoned
+		if reason == "" {
+			reason = "invocation not recoverable from store"
+		}
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO abandoned_firings (sync_firing_id, reason) VALUES (?, ?)
+		`, firingID, reason)
+		if err != nil {
+			return "", fmt.Errorf("repair orphaned firing: record abandoned: %w", err)
+		}
+	case err != nil:
+		return "", fmt.Errorf("repair orphaned firing: find invocation: %w", err)
+	default:
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO provenance_edges (sync_firing_id, invocation_id) VALUES (?, ?)
+		`, firingID, invocationID)
+		if err != nil {
+			return "", fmt.Errorf("repair orphaned firing: write provenance: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("repair orphaned firing: commit: %w", err)
+	}
+	return outcome, nil
+}
+
+// CompleteOrphanedFiring finishes an orphaned firing with a regenerated
+// invocation, writing the invocation (idempotent on its content-addressed ID)
+// and the provenance edge in one transaction.
+//
+// The invocation must belong to the same flow as the firing's completion.
+// Returns RepairNotOrphaned without writing if the firing is already resolved.
+func (s *Store) CompleteOrphanedFiring(ctx context.Context, firingID int64, inv ir.Invocation) (RepairOutcome, error) {
+	argsJSON, err := marshalArgs(inv.Args)
+	if err != nil {
+		return "", fmt.Errorf("complete orphaned firing: %w", err)
+	}
+	secCtxJSON, err := marshalSecurityContext(inv.SecurityContext)
+	if err != nil {
+		return "", fmt.Errorf("complete orphaned firing: %w", err)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("complete orphaned firing: begin tx: %w", err)
+	}
+	defer tx.Rollback() // No-op if committed
+
+	_, flowToken, orphaned, err := readOrphanCandidate(ctx, tx, firingID)
+	if err != nil {
+		return "", fmt.Errorf("complete orphaned firing: %w", err)
+	}
+	if !orphaned {
+		return RepairNotOrphaned, nil
+	}
+	if inv.FlowToken != flowToken {
+		return "", fmt.Errorf("complete orphaned firing: invocation flow token %q does not match firing flow %q",
+			inv.FlowToken, flowToken)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO invocations
+		(id, flow_token, action_uri, args, seq, security_context, spec_hash, engine_version, ir_version)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO NOTHING
+	`,
+		inv.ID,
+		inv.FlowToken,
+		string(inv.ActionURI),
+		argsJSON,
+		inv.Seq,
+		secCtxJSON,
+		inv.SpecHash,
+		inv.EngineVersion,
+		inv.IRVersion,
+	)
+	if err != nil {
+		return "", fmt.Errorf("complete orphaned firing: write invocation: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO provenance_edges (sync_firing_id, invocation_id) VALUES (?, ?)
+	`, firingID, inv.ID)
+	if err != nil {
+		return "", fmt.Errorf("complete orphaned firing: write provenance: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("complete orphaned firing: commit: %w", err)
+	}
+	return RepairRegenerated, nil
+}
+
+// readOrphanCandidate loads a firing and its flow token, and reports whether it
+// is still orphaned (no provenance edge and not abandoned).
+// Returns an error wrapping sql.ErrNoRows if the firing does not exist.
+func readOrphanCandidate(ctx context.Context, tx *sql.Tx, firingID int64) (ir.SyncFiring, string, bool, error) {
+	var f ir.SyncFiring
+	var flowToken string
+	var resolved bool
+	err := tx.QueryRowContext(ctx, `
+		SELECT sf.id, sf.completion_id, sf.sync_id, sf.binding_hash, sf.seq, i.flow_token,
+			EXISTS(SELECT 1 FROM provenance_edges pe WHERE pe.sync_firing_id = sf.id)
+			OR EXISTS(SELECT 1 FROM abandoned_firings af WHERE af.sync_firing_id = sf.id)
+		FROM sync_firings sf
+		JOIN completions c ON sf.completion_id = c.id
+		JOIN invocations i ON c.invocation_id = i.id
+		WHERE sf.id = ?
+	`, firingID).Scan(&f.ID, &f.CompletionID, &f.SyncID, &f.BindingHash, &f.Seq, &flowToken, &resolved)
+	if err != nil {
+		return ir.SyncFiring{}, "", false, fmt.Errorf("read sync firing %d: %w", firingID, err)
+	}
+	return f, flowToken, !resolved, nil
+}
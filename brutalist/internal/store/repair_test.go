@@ -0,0 +1,155 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+// writeOrphanedFiring writes inv-1 → comp-1 and a firing at seq 4 with no
+// provenance edge.
+func writeOrphanedFiring(t *testing.T, s *Store) int64 {
+	t.Helper()
+	ctx := context.Background()
+
+	s.WriteInvocation(ctx, createTestInvocation("inv-1", "flow-1", "Cart.checkout", 1))
+	s.WriteCompletion(ctx, createTestCompletion("comp-1", "inv-1", "Success", 2))
+
+	id, _, err := s.WriteSyncFiring(ctx, ir.SyncFiring{
+		CompletionID: "comp-1", SyncID: "sync-1", BindingHash: "h1", Seq: 4,
+	})
+	if err != nil {
+		t.Fatalf("WriteSyncFiring failed: %v", err)
+	}
+	return id
+}
+
+func TestRepairOrphanedFiring_LinksExistingInvocation(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+
+	firingID := writeOrphanedFiring(t, store)
+	// Crash between invocation write and provenance write (non-atomic path)
+	store.WriteInvocation(ctx, createTestInvocation("inv-2", "flow-1", "Inventory.reserve", 3))
+
+	outcome, err := store.RepairOrphanedFiring(ctx, firingID, "")
+	if err != nil {
+		t.Fatalf("RepairOrphanedFiring failed: %v", err)
+	}
+	if outcome != RepairLinked {
+		t.Errorf("outcome = %s, want %s", outcome, RepairLinked)
+	}
+
+	triggered, err := store.ReadTriggered(ctx, "comp-1")
+	if err != nil {
+		t.Fatalf("ReadTriggered failed: %v", err)
+	}
+	if len(triggered) != 1 || triggered[0].ID != "inv-2" {
+		t.Errorf("triggered = %v, want [inv-2]", triggered)
+	}
+}
+
+func TestRepairOrphanedFiring_Abandons(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+
+	firingID := writeOrphanedFiring(t, store)
+
+	outcome, err := store.RepairOrphanedFiring(ctx, firingID, "")
+	if err != nil {
+		t.Fatalf("RepairOrphanedFiring failed: %v", err)
+	}
+	if outcome != RepairAbandoned {
+		t.Errorf("outcome = %s, want %s", outcome, RepairAbandoned)
+	}
+
+	orphans, err := store.FindOrphanedSyncFirings(ctx)
+	if err != nil {
+		t.Fatalf("FindOrphanedSyncFirings failed: %v", err)
+	}
+	if len(orphans) != 0 {
+		t.Errorf("len(orphans) = %d, want 0 after abandoning", len(orphans))
+	}
+
+	// A second repair is a no-op
+	outcome, err = store.RepairOrphanedFiring(ctx, firingID, "")
+	if err != nil {
+		t.Fatalf("RepairOrphanedFiring failed: %v", err)
+	}
+	if outcome != RepairNotOrphaned {
+		t.Errorf("outcome = %s, want %s", outcome, RepairNotOrphaned)
+	}
+
+	// Abandoned firings are archived and pruned with their flow
+	bundle, err := store.ArchiveFlow(ctx, "flow-1")
+	if err != nil {
+		t.Fatalf("ArchiveFlow failed: %v", err)
+	}
+	if len(bundle.AbandonedFirings) != 1 {
+		t.Errorf("len(AbandonedFirings) = %d, want 1", len(bundle.AbandonedFirings))
+	} else if got := bundle.AbandonedFirings[0].Reason; got != "invocation not recoverable from store" {
+		t.Errorf("Reason = %q, want the generic reason", got)
+	}
+	if _, err := store.PruneFlow(ctx, "flow-1"); err != nil {
+		t.Fatalf("PruneFlow failed: %v", err)
+	}
+	if got := countRows(t, store, "abandoned_firings"); got != 0 {
+		t.Errorf("abandoned_firings rows = %d, want 0", got)
+	}
+}
+
+func TestRepairOrphanedFiring_RecordsReason(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+
+	firingID := writeOrphanedFiring(t, store)
+
+	if _, err := store.RepairOrphanedFiring(ctx, firingID, `sync rule "sync-1" is not registered`); err != nil {
+		t.Fatalf("RepairOrphanedFiring failed: %v", err)
+	}
+
+	bundle, err := store.ArchiveFlow(ctx, "flow-1")
+	if err != nil {
+		t.Fatalf("ArchiveFlow failed: %v", err)
+	}
+	if len(bundle.AbandonedFirings) != 1 {
+		t.Fatalf("len(AbandonedFirings) = %d, want 1", len(bundle.AbandonedFirings))
+	}
+	if got := bundle.AbandonedFirings[0].Reason; got != `sync rule "sync-1" is not registered` {
+		t.Errorf("Reason = %q, want the caller's reason", got)
+	}
+}
+
+func TestCompleteOrphanedFiring(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+
+	firingID := writeOrphanedFiring(t, store)
+	inv := createTestInvocation("inv-2", "flow-1", "Inventory.reserve", 3)
+
+	outcome, err := store.CompleteOrphanedFiring(ctx, firingID, inv)
+	if err != nil {
+		t.Fatalf("CompleteOrphanedFiring failed: %v", err)
+	}
+	if outcome != RepairRegenerated {
+		t.Errorf("outcome = %s, want %s", outcome, RepairRegenerated)
+	}
+
+	edges, err := store.ReadProvenanceEdgesForFiring(ctx, firingID)
+	if err != nil {
+		t.Fatalf("ReadProvenanceEdgesForFiring failed: %v", err)
+	}
+	if len(edges) != 1 || edges[0].InvocationID != "inv-2" {
+		t.Errorf("edges = %v, want one edge to inv-2", edges)
+	}
+
+	// Wrong flow is rejected
+	otherID, _, _ := store.WriteSyncFiring(ctx, ir.SyncFiring{
+		CompletionID: "comp-1", SyncID: "sync-1", BindingHash: "h2", Seq: 6,
+	})
+	foreign := createTestInvocation("inv-3", "flow-2", "Inventory.reserve", 5)
+	if _, err := store.CompleteOrphanedFiring(ctx, otherID, foreign); err == nil {
+		t.Error("expected error for invocation from another flow")
+	}
+}
@@ -2,7 +2,10 @@ package store
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"math"
 
 	"github.com/roach88/nysm/internal/ir"
 )
@@ -14,9 +17,10 @@ type FlowState struct {
 	Completions     []ir.Completion
 	SyncFirings     []ir.SyncFiring
 	LastSeq         int64
-	IsComplete      bool   // True if all invocations complete AND all sync firings have triggered invocations
+	IsComplete      bool   // True if all invocations complete, all sync firings have triggered invocations, and none are scheduled
 	PendingCount    int    // Invocations without completions
 	OrphanedFirings int    // Sync firings without provenance edges (crash recovery indicator)
+	ScheduledCount  int    // Deferred firings not yet released (see ScheduledFiring)
 	TerminalStatus  string // Empty, "Success", or error case
 }
 
@@ -60,14 +64,12 @@ func (s *Store) GetFlowState(ctx context.Context, flowToken string) (FlowState,
 		}
 	}
 
-	// Get sync firings for the flow (via completions)
-	for _, comp := range completions {
-		firings, err := s.ReadSyncFiringsForCompletion(ctx, comp.ID)
-		if err != nil {
-			return state, fmt.Errorf("get flow state: %w", err)
-		}
-		state.SyncFirings = append(state.SyncFirings, firings...)
+	// Get sync firings for the flow in a single query (avoids N+1)
+	firings, err := s.readFlowSyncFiringsByCompletion(ctx, flowToken)
+	if err != nil {
+		return state, fmt.Errorf("get flow state: %w", err)
 	}
+	state.SyncFirings = firings
 
 	// Count orphaned firings in a single batch query (avoids N+1)
 	// These indicate crash recovery is needed - sync fired but didn't generate invocation
@@ -81,12 +83,20 @@ func (s *Store) GetFlowState(ctx context.Context, flowToken string) (FlowState,
 	}
 	state.OrphanedFirings = orphanCount
 
+	scheduledCount, err := s.countScheduledFiringsForFlow(ctx, flowToken)
+	if err != nil {
+		return state, fmt.Errorf("get flow state: %w", err)
+	}
+	state.ScheduledCount = scheduledCount
+
 	// Determine if flow is complete
 	// A flow is complete if:
 	// 1. All invocations have completions (PendingCount == 0)
 	// 2. No sync firings are orphaned (all triggered invocations exist)
-	// 3. At least one invocation exists (not an empty flow)
-	state.IsComplete = state.PendingCount == 0 && state.OrphanedFirings == 0 && len(invocations) > 0
+	// 3. No firings are scheduled (a deferred firing will still add an invocation)
+	// 4. At least one invocation exists (not an empty flow)
+	state.IsComplete = state.PendingCount == 0 && state.OrphanedFirings == 0 &&
+		state.ScheduledCount == 0 && len(invocations) > 0
 
 	// Get terminal status from last completion
 	if len(completions) > 0 {
@@ -99,11 +109,13 @@ func (s *Store) GetFlowState(ctx context.Context, flowToken string) (FlowState,
 // FindIncompleteFlows returns all flows that need recovery attention.
 // A flow is incomplete if:
 // 1. Some invocations don't have corresponding completions, OR
-// 2. Some sync firings don't have provenance edges (orphaned firings)
+// 2. Some sync firings don't have provenance edges (orphaned firings), OR
+// 3. Some deferred firings are still scheduled
 //
 // Used for crash recovery to identify flows that need to be resumed.
 func (s *Store) FindIncompleteFlows(ctx context.Context) ([]FlowState, error) {
-	// Get all unique flow tokens that have pending invocations OR orphaned firings
+	// Get all unique flow tokens that have pending invocations, orphaned
+	// firings, or scheduled firings
 	rows, err := s.db.QueryContext(ctx, `
 		SELECT DISTINCT flow_token FROM (
 			-- Flows with pending invocations (no completion)
@@ -121,6 +133,12 @@ func (s *Store) FindIncompleteFlows(ctx context.Context) ([]FlowState, error) {
 			JOIN completions c ON sf.completion_id = c.id
 			JOIN invocations i ON c.invocation_id = i.id
 			WHERE pe.id IS NULL
+			  AND NOT EXISTS (SELECT 1 FROM abandoned_firings af WHERE af.sync_firing_id = sf.id)
+
+			UNION
+
+			-- Flows with deferred firings not yet released
+			SELECT flow_token FROM scheduled_firings
 		)
 		ORDER BY flow_token
 	`)
@@ -159,7 +177,7 @@ func (s *Store) FindIncompleteFlows(ctx context.Context) ([]FlowState, error) {
 // Used for recovery to identify which actions need to be re-executed.
 // Results ordered by seq ASC, id ASC per CP-4.
 func (s *Store) GetPendingInvocations(ctx context.Context, flowToken string) ([]ir.Invocation, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	return s.queryPendingInvocations(ctx, `
 		SELECT i.id, i.flow_token, i.action_uri, i.args, i.seq,
 		       i.security_context, i.spec_hash, i.engine_version, i.ir_version
 		FROM invocations i
@@ -167,6 +185,26 @@ func (s *Store) GetPendingInvocations(ctx context.Context, flowToken string) ([]
 		WHERE i.flow_token = ? AND c.id IS NULL
 		ORDER BY i.seq ASC, i.id COLLATE BINARY ASC
 	`, flowToken)
+}
+
+// GetAllPendingInvocations returns invocations without completions across all flows.
+// Used by external executors that poll for work regardless of flow.
+// Results ordered by seq ASC, id ASC per CP-4.
+func (s *Store) GetAllPendingInvocations(ctx context.Context) ([]ir.Invocation, error) {
+	return s.queryPendingInvocations(ctx, `
+		SELECT i.id, i.flow_token, i.action_uri, i.args, i.seq,
+		       i.security_context, i.spec_hash, i.engine_version, i.ir_version
+		FROM invocations i
+		LEFT JOIN completions c ON i.id = c.invocation_id
+		WHERE c.id IS NULL
+		ORDER BY i.seq ASC, i.id COLLATE BINARY ASC
+	`)
+}
+
+// queryPendingInvocations runs a pending-invocation query and scans the results.
+// Always returns an empty slice (not nil) when nothing is pending.
+func (s *Store) queryPendingInvocations(ctx context.Context, query string, args ...any) ([]ir.Invocation, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("get pending invocations: %w", err)
 	}
@@ -233,6 +271,180 @@ func (s *Store) ReplayFlow(ctx context.Context, flowToken string) ([]FlowEvent,
 	return events, nil
 }
 
+// ReadFlowPaged returns one page of a flow's events, ordered exactly as
+// ReplayFlow orders them, without loading the whole flow into memory.
+//
+// Events with seq > afterSeq are returned, at most limit per page with one
+// exception: a page never splits events that share a seq, since the seq
+// cursor could not resume between them. Pass 0 as afterSeq for the first page.
+//
+// The returned cursor is the afterSeq for the next page, or 0 when this page
+// reaches the end of the flow.
+func (s *Store) ReadFlowPaged(ctx context.Context, flowToken string, afterSeq int64, limit int) ([]FlowEvent, int64, error) {
+	if limit <= 0 {
+		return nil, 0, fmt.Errorf("read flow paged: limit must be positive, got %d", limit)
+	}
+
+	// Find the seq of the limit-th remaining event; the page ends there.
+	var boundary int64
+	err := s.db.QueryRowContext(ctx, `
+		SELECT seq FROM (
+			SELECT seq FROM invocations WHERE flow_token = ? AND seq > ?
+			UNION ALL
+			SELECT c.seq FROM completions c
+			JOIN invocations i ON c.invocation_id = i.id
+			WHERE i.flow_token = ? AND c.seq > ?
+		)
+		ORDER BY seq ASC
+		LIMIT 1 OFFSET ?
+	`, flowToken, afterSeq, flowToken, afterSeq, limit-1).Scan(&boundary)
+	lastPage := errors.Is(err, sql.ErrNoRows)
+	if err != nil && !lastPage {
+		return nil, 0, fmt.Errorf("read flow paged: find page boundary: %w", err)
+	}
+
+	upper := boundary
+	if lastPage {
+		upper = math.MaxInt64
+	}
+
+	events, err := s.queryEvents(ctx, `
+		SELECT id, flow_token, action_uri, args, seq, security_context, spec_hash, engine_version, ir_version
+		FROM invocations
+		WHERE flow_token = ? AND seq > ? AND seq <= ?
+	`, `
+		SELECT c.id, c.invocation_id, c.output_case, c.result, c.seq, c.security_context
+		FROM completions c
+		JOIN invocations i ON c.invocation_id = i.id
+		WHERE i.flow_token = ? AND c.seq > ? AND c.seq <= ?
+	`, flowToken, afterSeq, upper)
+	if err != nil {
+		return nil, 0, fmt.Errorf("read flow paged: %w", err)
+	}
+
+	if lastPage {
+		return events, 0, nil
+	}
+
+	// The boundary event may be the flow's last; only hand out a cursor if more remain.
+	var more bool
+	err = s.db.QueryRowContext(ctx, `
+		SELECT EXISTS(SELECT 1 FROM invocations WHERE flow_token = ? AND seq > ?)
+			OR EXISTS(
+				SELECT 1 FROM completions c
+				JOIN invocations i ON c.invocation_id = i.id
+				WHERE i.flow_token = ? AND c.seq > ?
+			)
+	`, flowToken, boundary, flowToken, boundary).Scan(&more)
+	if err != nil {
+		return nil, 0, fmt.Errorf("read flow paged: check remaining: %w", err)
+	}
+	if !more {
+		return events, 0, nil
+	}
+
+	return events, boundary, nil
+}
+
+// ReadFlowSince returns a flow's events with seq > afterSeq, ordered exactly
+// as ReplayFlow orders them. The result is the suffix of ReplayFlow after
+// afterSeq.
+//
+// Intended for tailing a live flow: poll with the seq of the last event seen
+// (0 initially) to receive only the new events, without re-reading the
+// whole flow. Events sharing a seq are always returned together.
+//
+// Returns an empty slice (not nil) if no events follow afterSeq.
+func (s *Store) ReadFlowSince(ctx context.Context, flowToken string, afterSeq int64) ([]FlowEvent, error) {
+	events, err := s.queryEvents(ctx, `
+		SELECT id, flow_token, action_uri, args, seq, security_context, spec_hash, engine_version, ir_version
+		FROM invocations
+		WHERE flow_token = ? AND seq > ?
+	`, `
+		SELECT c.id, c.invocation_id, c.output_case, c.result, c.seq, c.security_context
+		FROM completions c
+		JOIN invocations i ON c.invocation_id = i.id
+		WHERE i.flow_token = ? AND c.seq > ?
+	`, flowToken, afterSeq)
+	if err != nil {
+		return nil, fmt.Errorf("read flow %s since %d: %w", flowToken, afterSeq, err)
+	}
+	return events, nil
+}
+
+// ReplayFrom returns every invocation and completion event with seq > afterSeq
+// across all flows, in global replay order: seq ASC, invocations before
+// completions on ties, then ID.
+//
+// Combined with GetLastSeq, this enables checkpointed recovery: persist the
+// last applied seq, then resume with ReplayFrom(checkpoint) instead of
+// rescanning the whole store. ReplayFrom(0) is a full global replay.
+//
+// Returns an empty slice (not nil) if no events follow afterSeq.
+func (s *Store) ReplayFrom(ctx context.Context, afterSeq int64) ([]FlowEvent, error) {
+	events, err := s.queryEvents(ctx, `
+		SELECT id, flow_token, action_uri, args, seq, security_context, spec_hash, engine_version, ir_version
+		FROM invocations
+		WHERE seq > ?
+	`, `
+		SELECT id, invocation_id, output_case, result, seq, security_context
+		FROM completions
+		WHERE seq > ?
+	`, afterSeq)
+	if err != nil {
+		return nil, fmt.Errorf("replay from %d: %w", afterSeq, err)
+	}
+	return events, nil
+}
+
+// queryEvents runs an invocation query and a completion query with the same
+// args and merges the rows into a single event stream in replay order.
+// The queries must select the columns expected by scanInvocation and
+// scanCompletion respectively.
+func (s *Store) queryEvents(ctx context.Context, invQuery, compQuery string, args ...any) ([]FlowEvent, error) {
+	events := []FlowEvent{}
+
+	invRows, err := s.db.QueryContext(ctx, invQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query invocations: %w", err)
+	}
+	defer invRows.Close()
+
+	for invRows.Next() {
+		inv, err := scanInvocation(invRows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, FlowEvent{Type: EventInvocation, Seq: inv.Seq, ID: inv.ID, Invocation: &inv})
+	}
+	if err := invRows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate invocations: %w", err)
+	}
+	invRows.Close()
+
+	compRows, err := s.db.QueryContext(ctx, compQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query completions: %w", err)
+	}
+	defer compRows.Close()
+
+	for compRows.Next() {
+		comp, err := scanCompletion(compRows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, FlowEvent{Type: EventCompletion, Seq: comp.Seq, ID: comp.ID, Completion: &comp})
+	}
+	if err := compRows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate completions: %w", err)
+	}
+
+	// Same ordering as ReplayFlow
+	sortFlowEvents(events)
+
+	return events, nil
+}
+
 // FlowEvent represents a single event in a flow (invocation or completion).
 type FlowEvent struct {
 	Type       FlowEventType
@@ -2,6 +2,12 @@ package store
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
 	"testing"
 
 	"github.com/roach88/nysm/internal/ir"
@@ -211,6 +217,46 @@ func TestGetPendingInvocations_Empty(t *testing.T) {
 	}
 }
 
+func TestGetAllPendingInvocations(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+
+	// Pending invocations spread across two flows, interleaved by seq
+	store.WriteInvocation(ctx, createTestInvocation("inv-a1", "flow-a", "Action.one", 1))
+	store.WriteInvocation(ctx, createTestInvocation("inv-b1", "flow-b", "Action.one", 2))
+	store.WriteCompletion(ctx, createTestCompletion("comp-b1", "inv-b1", "Success", 3))
+	store.WriteInvocation(ctx, createTestInvocation("inv-b2", "flow-b", "Action.two", 4))
+	store.WriteInvocation(ctx, createTestInvocation("inv-a2", "flow-a", "Action.two", 5))
+
+	pending, err := store.GetAllPendingInvocations(ctx)
+	if err != nil {
+		t.Fatalf("GetAllPendingInvocations failed: %v", err)
+	}
+
+	want := []string{"inv-a1", "inv-b2", "inv-a2"}
+	if len(pending) != len(want) {
+		t.Fatalf("len(pending) = %d, want %d", len(pending), len(want))
+	}
+	for i, id := range want {
+		if pending[i].ID != id {
+			t.Errorf("pending[%d].ID = %q, want %q", i, pending[i].ID, id)
+		}
+	}
+}
+
+func TestGetAllPendingInvocations_Empty(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+
+	pending, err := store.GetAllPendingInvocations(ctx)
+	if err != nil {
+		t.Fatalf("GetAllPendingInvocations failed: %v", err)
+	}
+	if pending == nil {
+		t.Error("expected empty slice, got nil")
+	}
+}
+
 func TestReplayFlow(t *testing.T) {
 	store := createTestStore(t)
 	ctx := context.Background()
@@ -281,6 +327,267 @@ func TestReplayFlow_SameSeqOrdering(t *testing.T) {
 	}
 }
 
+// writePagedFlow writes a flow with 20 events, including seq ties between an
+// invocation and a completion, plus an unrelated flow that must not leak in.
+func writePagedFlow(t *testing.T, s *Store) {
+	t.Helper()
+	ctx := context.Background()
+
+	seq := int64(1)
+	for i := 0; i < 10; i++ {
+		invID := fmt.Sprintf("inv-%02d", i)
+		if err := s.WriteInvocation(ctx, createTestInvocation(invID, "flow-1", "Action.step", seq)); err != nil {
+			t.Fatalf("WriteInvocation failed: %v", err)
+		}
+		// Every third completion shares its invocation's seq
+		if i%3 != 0 {
+			seq++
+		}
+		if err := s.WriteCompletion(ctx, createTestCompletion("comp-"+invID, invID, "Success", seq)); err != nil {
+			t.Fatalf("WriteCompletion failed: %v", err)
+		}
+		seq++
+	}
+
+	if err := s.WriteInvocation(ctx, createTestInvocation("other-inv", "flow-2", "Action.step", 3)); err != nil {
+		t.Fatalf("WriteInvocation failed: %v", err)
+	}
+}
+
+func TestReadFlowPaged_MatchesReplayFlow(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+	writePagedFlow(t, store)
+
+	want, err := store.ReplayFlow(ctx, "flow-1")
+	if err != nil {
+		t.Fatalf("ReplayFlow failed: %v", err)
+	}
+	if len(want) != 20 {
+		t.Fatalf("len(ReplayFlow) = %d, want 20", len(want))
+	}
+
+	for _, limit := range []int{1, 2, 3, 7, 20, 100} {
+		var got []FlowEvent
+		var cursor int64
+		for pages := 0; ; pages++ {
+			if pages > len(want) {
+				t.Fatalf("limit %d: paging did not terminate", limit)
+			}
+			page, next, err := store.ReadFlowPaged(ctx, "flow-1", cursor, limit)
+			if err != nil {
+				t.Fatalf("limit %d: ReadFlowPaged failed: %v", limit, err)
+			}
+			got = append(got, page...)
+			if next == 0 {
+				break
+			}
+			if next <= cursor {
+				t.Fatalf("limit %d: cursor did not advance (%d -> %d)", limit, cursor, next)
+			}
+			cursor = next
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("limit %d: paged events differ from ReplayFlow", limit)
+		}
+	}
+}
+
+func TestReadFlowPaged_DoesNotSplitSeq(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+	writePagedFlow(t, store)
+
+	// inv-00 and comp-inv-00 share seq 1; a page of one must include both
+	page, next, err := store.ReadFlowPaged(ctx, "flow-1", 0, 1)
+	if err != nil {
+		t.Fatalf("ReadFlowPaged failed: %v", err)
+	}
+	if len(page) != 2 {
+		t.Fatalf("len(page) = %d, want 2", len(page))
+	}
+	if page[0].Type != EventInvocation || page[1].Type != EventCompletion {
+		t.Error("expected invocation before completion for same seq")
+	}
+	if next != 1 {
+		t.Errorf("next = %d, want 1", next)
+	}
+}
+
+func TestReadFlowPaged_Empty(t *testing.T) {
+	store := createTestStore(t)
+
+	page, next, err := store.ReadFlowPaged(context.Background(), "no-such-flow", 0, 10)
+	if err != nil {
+		t.Fatalf("ReadFlowPaged failed: %v", err)
+	}
+	if page == nil || len(page) != 0 {
+		t.Errorf("page = %v, want empty slice", page)
+	}
+	if next != 0 {
+		t.Errorf("next = %d, want 0", next)
+	}
+
+	if _, _, err := store.ReadFlowPaged(context.Background(), "flow-1", 0, 0); err == nil {
+		t.Error("expected error for zero limit")
+	}
+}
+
+func TestReadFlowSince_MatchesReplayFlowSuffix(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+	writePagedFlow(t, store)
+
+	all, err := store.ReplayFlow(ctx, "flow-1")
+	if err != nil {
+		t.Fatalf("ReplayFlow failed: %v", err)
+	}
+	last := all[len(all)-1].Seq
+
+	for after := int64(0); after <= last; after++ {
+		want := []FlowEvent{}
+		for _, ev := range all {
+			if ev.Seq > after {
+				want = append(want, ev)
+			}
+		}
+
+		got, err := store.ReadFlowSince(ctx, "flow-1", after)
+		if err != nil {
+			t.Fatalf("ReadFlowSince(%d) failed: %v", after, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ReadFlowSince(%d) returned %d events, want the %d-event suffix of ReplayFlow", after, len(got), len(want))
+		}
+	}
+}
+
+func TestReadFlowSince_Tail(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+	writeInterleavedFlows(t, store)
+
+	// Only flow-a events after seq 3; flow-b and flow-c events in between are excluded
+	got, err := store.ReadFlowSince(ctx, "flow-a", 3)
+	if err != nil {
+		t.Fatalf("ReadFlowSince failed: %v", err)
+	}
+	var ids []string
+	for _, ev := range got {
+		ids = append(ids, ev.ID)
+	}
+	wantIDs := []string{"a-inv-2", "a-comp-2"}
+	if !reflect.DeepEqual(ids, wantIDs) {
+		t.Errorf("ids = %v, want %v", ids, wantIDs)
+	}
+
+	// Caught up, and unknown flows: empty, non-nil
+	for _, flow := range []string{"flow-a", "no-such-flow"} {
+		tail, err := store.ReadFlowSince(ctx, flow, 7)
+		if err != nil {
+			t.Fatalf("ReadFlowSince(%s) failed: %v", flow, err)
+		}
+		if tail == nil || len(tail) != 0 {
+			t.Errorf("ReadFlowSince(%s, 7) = %v, want empty slice", flow, tail)
+		}
+	}
+}
+
+// writeInterleavedFlows writes three flows whose events interleave in seq,
+// including an invocation/completion tie at seq 4.
+func writeInterleavedFlows(t *testing.T, s *Store) {
+	t.Helper()
+	ctx := context.Background()
+
+	s.WriteInvocation(ctx, createTestInvocation("a-inv-1", "flow-a", "Action.one", 1))
+	s.WriteInvocation(ctx, createTestInvocation("b-inv-1", "flow-b", "Action.one", 2))
+	s.WriteCompletion(ctx, createTestCompletion("a-comp-1", "a-inv-1", "Success", 3))
+	s.WriteInvocation(ctx, createTestInvocation("c-inv-1", "flow-c", "Action.one", 4))
+	s.WriteCompletion(ctx, createTestCompletion("b-comp-1", "b-inv-1", "Success", 4))
+	s.WriteInvocation(ctx, createTestInvocation("a-inv-2", "flow-a", "Action.two", 5))
+	s.WriteCompletion(ctx, createTestCompletion("c-comp-1", "c-inv-1", "Success", 6))
+	s.WriteCompletion(ctx, createTestCompletion("a-comp-2", "a-inv-2", "Success", 7))
+}
+
+func TestReplayFrom_ZeroIsFullGlobalReplay(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+	writeInterleavedFlows(t, store)
+
+	// Build the expected global stream from per-flow replays
+	var want []FlowEvent
+	for _, flow := range []string{"flow-a", "flow-b", "flow-c"} {
+		events, err := store.ReplayFlow(ctx, flow)
+		if err != nil {
+			t.Fatalf("ReplayFlow(%s) failed: %v", flow, err)
+		}
+		want = append(want, events...)
+	}
+	sortFlowEvents(want)
+
+	got, err := store.ReplayFrom(ctx, 0)
+	if err != nil {
+		t.Fatalf("ReplayFrom failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReplayFrom(0) differs from merged per-flow replay")
+	}
+
+	var ids []string
+	for _, ev := range got {
+		ids = append(ids, ev.ID)
+	}
+	wantIDs := []string{"a-inv-1", "b-inv-1", "a-comp-1", "c-inv-1", "b-comp-1", "a-inv-2", "c-comp-1", "a-comp-2"}
+	if !reflect.DeepEqual(ids, wantIDs) {
+		t.Errorf("order = %v, want %v", ids, wantIDs)
+	}
+}
+
+func TestReplayFrom_ReturnsTail(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+	writeInterleavedFlows(t, store)
+
+	all, err := store.ReplayFrom(ctx, 0)
+	if err != nil {
+		t.Fatalf("ReplayFrom failed: %v", err)
+	}
+
+	for k := int64(0); k <= 7; k++ {
+		var want []FlowEvent
+		for _, ev := range all {
+			if ev.Seq > k {
+				want = append(want, ev)
+			}
+		}
+		if want == nil {
+			want = []FlowEvent{}
+		}
+
+		got, err := store.ReplayFrom(ctx, k)
+		if err != nil {
+			t.Fatalf("ReplayFrom(%d) failed: %v", k, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ReplayFrom(%d) returned %d events, want the %d-event tail", k, len(got), len(want))
+		}
+	}
+
+	// Checkpoint at the last seq: nothing left to replay
+	last, err := store.GetLastSeq(ctx)
+	if err != nil {
+		t.Fatalf("GetLastSeq failed: %v", err)
+	}
+	tail, err := store.ReplayFrom(ctx, last)
+	if err != nil {
+		t.Fatalf("ReplayFrom failed: %v", err)
+	}
+	if tail == nil || len(tail) != 0 {
+		t.Errorf("ReplayFrom(last) = %v, want empty slice", tail)
+	}
+}
+
 func TestGetLastSeq(t *testing.T) {
 	store := createTestStore(t)
 	ctx := context.Background()
@@ -600,3 +907,208 @@ func TestReplayDeterminism(t *testing.T) {
 		}
 	}
 }
+
+// seedManyFiringsFlow writes a flow of n completed invocations, each
+// completion firing two syncs. Every firing links to the next invocation
+// except the second firing of every seventh completion (orphaned), and the
+// last two invocations are left pending.
+func seedManyFiringsFlow(t testing.TB, s *Store, n int) {
+	t.Helper()
+	ctx := context.Background()
+
+	seq := int64(0)
+	next := func() int64 { seq++; return seq }
+
+	for i := 0; i < n+2; i++ {
+		inv := createTestInvocation(fmt.Sprintf("inv-%03d", i), "flow-many", "Cart.step", next())
+		if err := s.WriteInvocation(ctx, inv); err != nil {
+			t.Fatalf("WriteInvocation failed: %v", err)
+		}
+	}
+	for i := 0; i < n; i++ {
+		comp := createTestCompletion(fmt.Sprintf("comp-%03d", i), fmt.Sprintf("inv-%03d", i), "Success", next())
+		if err := s.WriteCompletion(ctx, comp); err != nil {
+			t.Fatalf("WriteCompletion failed: %v", err)
+		}
+	}
+	for i := 0; i < n; i++ {
+		for _, syncID := range []string{"sync-a", "sync-b"} {
+			firingID, _, err := s.WriteSyncFiring(ctx, ir.SyncFiring{
+				CompletionID: fmt.Sprintf("comp-%03d", i),
+				SyncID:       syncID,
+				BindingHash:  fmt.Sprintf("h-%03d", i),
+				Seq:          next(),
+			})
+			if err != nil {
+				t.Fatalf("WriteSyncFiring failed: %v", err)
+			}
+			if syncID == "sync-b" && i%7 == 0 {
+				continue // Orphaned
+			}
+			if _, err := s.WriteProvenanceEdge(ctx, firingID, fmt.Sprintf("inv-%03d", i+1)); err != nil {
+				t.Fatalf("WriteProvenanceEdge failed: %v", err)
+			}
+		}
+	}
+}
+
+// naiveFlowState computes a FlowState with one sync-firing query per
+// completion and one edge check per firing, as GetFlowState used to.
+// Abandoned firings are not considered; callers must not write any.
+func naiveFlowState(ctx context.Context, s *Store, flowToken string) (FlowState, error) {
+	state := FlowState{FlowToken: flowToken}
+
+	invocations, completions, err := s.ReadFlow(ctx, flowToken)
+	if err != nil {
+		return state, err
+	}
+	state.Invocations = invocations
+	state.Completions = completions
+
+	completed := make(map[string]bool)
+	for _, comp := range completions {
+		completed[comp.InvocationID] = true
+		state.LastSeq = max(state.LastSeq, comp.Seq)
+	}
+	for _, inv := range invocations {
+		state.LastSeq = max(state.LastSeq, inv.Seq)
+		if !completed[inv.ID] {
+			state.PendingCount++
+		}
+	}
+
+	for _, comp := range completions {
+		firings, err := s.ReadSyncFiringsForCompletion(ctx, comp.ID)
+		if err != nil {
+			return state, err
+		}
+		for _, f := range firings {
+			hasEdge, err := s.hasFiringEdge(ctx, f.ID)
+			if err != nil {
+				return state, err
+			}
+			if !hasEdge {
+				state.OrphanedFirings++
+			}
+		}
+		state.SyncFirings = append(state.SyncFirings, firings...)
+	}
+
+	state.IsComplete = state.PendingCount == 0 && state.OrphanedFirings == 0 && len(invocations) > 0
+	if len(completions) > 0 {
+		state.TerminalStatus = completions[len(completions)-1].OutputCase
+	}
+	return state, nil
+}
+
+// countingConnector opens connections that count every statement prepared.
+// The wrapped connections expose only driver.Conn, so database/sql routes
+// every query and exec through Prepare.
+type countingConnector struct {
+	drv     driver.Driver
+	dsn     string
+	queries *atomic.Int64
+}
+
+func (c countingConnector) Connect(context.Context) (driver.Conn, error) {
+	conn, err := c.drv.Open(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	return countingConn{Conn: conn, queries: c.queries}, nil
+}
+
+func (c countingConnector) Driver() driver.Driver { return c.drv }
+
+type countingConn struct {
+	driver.Conn
+	queries *atomic.Int64
+}
+
+func (c countingConn) Prepare(query string) (driver.Stmt, error) {
+	c.queries.Add(1)
+	return c.Conn.Prepare(query)
+}
+
+// openCountingStore reopens the database at path through countingConnector.
+func openCountingStore(t testing.TB, path string, queries *atomic.Int64) *Store {
+	t.Helper()
+	base, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("sql.Open failed: %v", err)
+	}
+	drv := base.Driver()
+	base.Close()
+
+	db := sql.OpenDB(countingConnector{drv: drv, dsn: path, queries: queries})
+	t.Cleanup(func() { db.Close() })
+	return &Store{db: db, path: path}
+}
+
+func TestGetFlowState_ManyFiringsMatchesNaive(t *testing.T) {
+	const completions = 50
+
+	path := filepath.Join(t.TempDir(), "test.db")
+	seeded, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() failed: %v", err)
+	}
+	seedManyFiringsFlow(t, seeded, completions)
+	seeded.Close()
+
+	var queries atomic.Int64
+	store := openCountingStore(t, path, &queries)
+	ctx := context.Background()
+
+	want, err := naiveFlowState(ctx, store, "flow-many")
+	if err != nil {
+		t.Fatalf("naiveFlowState failed: %v", err)
+	}
+	naiveQueries := queries.Swap(0)
+
+	got, err := store.GetFlowState(ctx, "flow-many")
+	if err != nil {
+		t.Fatalf("GetFlowState failed: %v", err)
+	}
+	gotQueries := queries.Load()
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetFlowState differs from naive path:\ngot:  %+v\nwant: %+v", got, want)
+	}
+	if want.OrphanedFirings != 8 || want.PendingCount != 2 || want.IsComplete {
+		t.Fatalf("unexpected seed state: orphaned=%d pending=%d complete=%v",
+			want.OrphanedFirings, want.PendingCount, want.IsComplete)
+	}
+
+	// Invocations, completions, sync firings, orphan count, scheduled count -
+	// independent of the number of completions and firings
+	if gotQueries != 5 {
+		t.Errorf("GetFlowState ran %d queries, want 5", gotQueries)
+	}
+	t.Logf("queries: naive=%d batched=%d", naiveQueries, gotQueries)
+}
+
+func BenchmarkGetFlowState(b *testing.B) {
+	s, err := Open(filepath.Join(b.TempDir(), "bench.db"))
+	if err != nil {
+		b.Fatalf("Open() failed: %v", err)
+	}
+	defer s.Close()
+	seedManyFiringsFlow(b, s, 200)
+	ctx := context.Background()
+
+	b.Run("batched", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := s.GetFlowState(ctx, "flow-many"); err != nil {
+				b.Fatalf("GetFlowState failed: %v", err)
+			}
+		}
+	})
+	b.Run("naive", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := naiveFlowState(ctx, s, "flow-many"); err != nil {
+				b.Fatalf("naiveFlowState failed: %v", err)
+			}
+		}
+	})
+}
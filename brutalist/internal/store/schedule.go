@@ -0,0 +1,174 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+// ScheduledFiring is a sync firing deferred by ThenClause.AfterSteps that
+// the engine has not released yet.
+//
+// Scheduled firings are engine bookkeeping, not part of the event log: the
+// firing itself is written to sync_firings (with its invocation) only when
+// it is released. Persisting them lets a restarted engine reload its
+// schedule, and lets GetFlowState report the flow as incomplete meanwhile.
+type ScheduledFiring struct {
+	ID           int64       `json:"id"` // Store-assigned, orders firings with the same target seq
+	CompletionID string      `json:"completion_id"`
+	SyncID       string      `json:"sync_id"`
+	BindingHash  string      `json:"binding_hash"`
+	FlowToken    string      `json:"flow_token"`
+	Bindings     ir.IRObject `json:"bindings"`
+	TargetSeq    int64       `json:"target_seq"`
+}
+
+// WriteScheduledFiring records a scheduled firing and returns its ID.
+//
+// Scheduling is idempotent per (completion_id, sync_id, binding_hash), like
+// sync firings (CP-1): if the firing is already scheduled, the existing row
+// is left unchanged and inserted is false.
+func (s *Store) WriteScheduledFiring(ctx context.Context, f ScheduledFiring) (id int64, inserted bool, err error) {
+	bindingsJSON, err := marshalArgs(f.Bindings)
+	if err != nil {
+		return 0, false, fmt.Errorf("write scheduled firing: %w", err)
+	}
+
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO scheduled_firings
+		(completion_id, sync_id, binding_hash, flow_token, bindings, target_seq)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(completion_id, sync_id, binding_hash) DO NOTHING
+	`,
+		f.CompletionID,
+		f.SyncID,
+		f.BindingHash,
+		f.FlowToken,
+		bindingsJSON,
+		f.TargetSeq,
+	)
+	if err != nil {
+		return 0, false, fmt.Errorf("write scheduled firing: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, false, fmt.Errorf("write scheduled firing: rows affected: %w", err)
+	}
+	if rowsAffected > 0 {
+		id, err := result.LastInsertId()
+		if err != nil {
+			return 0, false, fmt.Errorf("write scheduled firing: last insert id: %w", err)
+		}
+		return id, true, nil
+	}
+
+	err = s.db.QueryRowContext(ctx, `
+		SELECT id FROM scheduled_firings
+		WHERE completion_id = ? AND sync_id = ? AND binding_hash = ?
+	`, f.CompletionID, f.SyncID, f.BindingHash).Scan(&id)
+	if err != nil {
+		return 0, false, fmt.Errorf("write scheduled firing: %w", err)
+	}
+	return id, false, nil
+}
+
+// ReadScheduledFirings returns every scheduled firing not yet released.
+// Results ordered by target_seq ASC, id ASC (CP-4), the order the engine
+// releases them in.
+//
+// Returns an empty slice (not nil) if nothing is scheduled.
+func (s *Store) ReadScheduledFirings(ctx context.Context) ([]ScheduledFiring, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, completion_id, sync_id, binding_hash, flow_token, bindings, target_seq
+		FROM scheduled_firings
+		ORDER BY target_seq ASC, id ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("read scheduled firings: %w", err)
+	}
+	defer rows.Close()
+
+	return scanScheduledFirings(rows)
+}
+
+// DeleteScheduledFiring removes a scheduled firing once it has been released.
+// Deleting a firing that does not exist is not an error.
+func (s *Store) DeleteScheduledFiring(ctx context.Context, id int64) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM scheduled_firings WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("delete scheduled firing: %w", err)
+	}
+	return nil
+}
+
+// DeleteScheduledFiringsForFlow removes every scheduled firing of a flow,
+// e.g. when the engine drops the flow's state. Returns the number removed.
+func (s *Store) DeleteScheduledFiringsForFlow(ctx context.Context, flowToken string) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM scheduled_firings WHERE flow_token = ?`, flowToken)
+	if err != nil {
+		return 0, fmt.Errorf("delete scheduled firings: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("delete scheduled firings: rows affected: %w", err)
+	}
+	return n, nil
+}
+
+// readFlowScheduledFirings returns the flow's scheduled firings in release
+// order (target_seq ASC, id ASC), like ReadScheduledFirings.
+func (s *Store) readFlowScheduledFirings(ctx context.Context, flowToken string) ([]ScheduledFiring, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, completion_id, sync_id, binding_hash, flow_token, bindings, target_seq
+		FROM scheduled_firings
+		WHERE flow_token = ?
+		ORDER BY target_seq ASC, id ASC
+	`, flowToken)
+	if err != nil {
+		return nil, fmt.Errorf("query scheduled firings: %w", err)
+	}
+	defer rows.Close()
+
+	return scanScheduledFirings(rows)
+}
+
+// scanScheduledFirings scans scheduled_firings rows selected in column order
+// id, completion_id, sync_id, binding_hash, flow_token, bindings, target_seq.
+// Returns an empty slice (not nil) if there are no rows.
+func scanScheduledFirings(rows *sql.Rows) ([]ScheduledFiring, error) {
+	firings := []ScheduledFiring{}
+	for rows.Next() {
+		var f ScheduledFiring
+		var bindingsJSON string
+		if err := rows.Scan(&f.ID, &f.CompletionID, &f.SyncID, &f.BindingHash,
+			&f.FlowToken, &bindingsJSON, &f.TargetSeq); err != nil {
+			return nil, fmt.Errorf("scan scheduled firing: %w", err)
+		}
+		var err error
+		if f.Bindings, err = unmarshalArgs(bindingsJSON); err != nil {
+			return nil, fmt.Errorf("scan scheduled firing %d: %w", f.ID, err)
+		}
+		firings = append(firings, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate scheduled firings: %w", err)
+	}
+
+	return firings, nil
+}
+
+// countScheduledFiringsForFlow returns the number of firings of a flow that
+// are scheduled but not yet released.
+func (s *Store) countScheduledFiringsForFlow(ctx context.Context, flowToken string) (int, error) {
+	var count int
+	err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM scheduled_firings WHERE flow_token = ?
+	`, flowToken).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("count scheduled firings: %w", err)
+	}
+	return count, nil
+}
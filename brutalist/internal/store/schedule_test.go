@@ -0,0 +1,172 @@
+package store
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+// seedScheduledFlow writes a completed checkout (seqs 1-2) with a deferred
+// reserve firing scheduled for seq 5.
+func seedScheduledFlow(t *testing.T, s *Store) ScheduledFiring {
+	t.Helper()
+	ctx := context.Background()
+
+	if err := s.WriteInvocation(ctx, createTestInvocation("inv-1", "flow-1", "Cart.checkout", 1)); err != nil {
+		t.Fatalf("WriteInvocation failed: %v", err)
+	}
+	if err := s.WriteCompletion(ctx, createTestCompletion("comp-1", "inv-1", "Success", 2)); err != nil {
+		t.Fatalf("WriteCompletion failed: %v", err)
+	}
+
+	f := ScheduledFiring{
+		CompletionID: "comp-1",
+		SyncID:       "reserve",
+		BindingHash:  "binding-1",
+		FlowToken:    "flow-1",
+		Bindings:     ir.IRObject{"item_id": ir.IRString("widget")},
+		TargetSeq:    5,
+	}
+	id, inserted, err := s.WriteScheduledFiring(ctx, f)
+	if err != nil {
+		t.Fatalf("WriteScheduledFiring failed: %v", err)
+	}
+	if !inserted {
+		t.Fatal("WriteScheduledFiring inserted = false for a new firing")
+	}
+	f.ID = id
+	return f
+}
+
+func TestWriteScheduledFiring_RoundTrip(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+	want := seedScheduledFlow(t, store)
+
+	got, err := store.ReadScheduledFirings(ctx)
+	if err != nil {
+		t.Fatalf("ReadScheduledFirings failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, []ScheduledFiring{want}) {
+		t.Errorf("ReadScheduledFirings = %+v, want [%+v]", got, want)
+	}
+}
+
+func TestWriteScheduledFiring_Idempotent(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+	first := seedScheduledFlow(t, store)
+
+	again := first
+	again.ID = 0
+	again.TargetSeq = 9
+	id, inserted, err := store.WriteScheduledFiring(ctx, again)
+	if err != nil {
+		t.Fatalf("WriteScheduledFiring failed: %v", err)
+	}
+	if inserted || id != first.ID {
+		t.Errorf("WriteScheduledFiring = (%d, %v), want (%d, false)", id, inserted, first.ID)
+	}
+
+	got, err := store.ReadScheduledFirings(ctx)
+	if err != nil {
+		t.Fatalf("ReadScheduledFirings failed: %v", err)
+	}
+	if len(got) != 1 || got[0].TargetSeq != 5 {
+		t.Errorf("ReadScheduledFirings = %+v, want the original row only", got)
+	}
+}
+
+func TestReadScheduledFirings_TargetOrder(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+	seedScheduledFlow(t, store)
+
+	for _, f := range []ScheduledFiring{
+		{CompletionID: "comp-1", SyncID: "remind", BindingHash: "b", FlowToken: "flow-1", TargetSeq: 3},
+		{CompletionID: "comp-1", SyncID: "nudge", BindingHash: "b", FlowToken: "flow-1", TargetSeq: 5},
+	} {
+		if _, _, err := store.WriteScheduledFiring(ctx, f); err != nil {
+			t.Fatalf("WriteScheduledFiring failed: %v", err)
+		}
+	}
+
+	got, err := store.ReadScheduledFirings(ctx)
+	if err != nil {
+		t.Fatalf("ReadScheduledFirings failed: %v", err)
+	}
+	var syncs []string
+	for _, f := range got {
+		syncs = append(syncs, f.SyncID)
+	}
+	// Target seq first, then scheduling order
+	if want := []string{"remind", "reserve", "nudge"}; !reflect.DeepEqual(syncs, want) {
+		t.Errorf("release order = %v, want %v", syncs, want)
+	}
+}
+
+func TestGetFlowState_ScheduledFiringsKeepFlowIncomplete(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+	f := seedScheduledFlow(t, store)
+
+	state, err := store.GetFlowState(ctx, "flow-1")
+	if err != nil {
+		t.Fatalf("GetFlowState failed: %v", err)
+	}
+	if state.ScheduledCount != 1 || state.IsComplete {
+		t.Errorf("ScheduledCount = %d, IsComplete = %v; want 1, false", state.ScheduledCount, state.IsComplete)
+	}
+
+	incomplete, err := store.FindIncompleteFlows(ctx)
+	if err != nil {
+		t.Fatalf("FindIncompleteFlows failed: %v", err)
+	}
+	if len(incomplete) != 1 || incomplete[0].FlowToken != "flow-1" {
+		t.Errorf("FindIncompleteFlows = %+v, want flow-1", incomplete)
+	}
+
+	summaries, err := store.ListFlows(ctx, ListFlowsOptions{Status: FlowStatusIncomplete})
+	if err != nil {
+		t.Fatalf("ListFlows failed: %v", err)
+	}
+	if len(summaries) != 1 || summaries[0].ScheduledCount != 1 || summaries[0].IsComplete {
+		t.Errorf("ListFlows(incomplete) = %+v, want flow-1 with one scheduled firing", summaries)
+	}
+
+	// Releasing the firing completes the flow
+	if err := store.DeleteScheduledFiring(ctx, f.ID); err != nil {
+		t.Fatalf("DeleteScheduledFiring failed: %v", err)
+	}
+	state, err = store.GetFlowState(ctx, "flow-1")
+	if err != nil {
+		t.Fatalf("GetFlowState failed: %v", err)
+	}
+	if state.ScheduledCount != 0 || !state.IsComplete {
+		t.Errorf("after release: ScheduledCount = %d, IsComplete = %v; want 0, true", state.ScheduledCount, state.IsComplete)
+	}
+}
+
+func TestDeleteScheduledFiringsForFlow(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+	seedScheduledFlow(t, store)
+
+	n, err := store.DeleteScheduledFiringsForFlow(ctx, "flow-1")
+	if err != nil {
+		t.Fatalf("DeleteScheduledFiringsForFlow failed: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("removed %d, want 1", n)
+	}
+
+	got, err := store.ReadScheduledFirings(ctx)
+	if err != nil {
+		t.Fatalf("ReadScheduledFirings failed: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("ReadScheduledFirings = %+v, want empty", got)
+	}
+}
@@ -26,6 +26,10 @@ CREATE INDEX IF NOT EXISTS idx_invocations_flow_token
     ON invocations(flow_token);
 CREATE INDEX IF NOT EXISTS idx_invocations_seq
     ON invocations(seq);
+-- Expression index for tenant lookups (FindFlowsByTenant).
+-- Queries must use this exact expression for the planner to pick it up.
+CREATE INDEX IF NOT EXISTS idx_invocations_tenant
+    ON invocations(json_extract(security_context, '$.tenant_id'));
 
 -- Completions: Action completion records
 -- CRITICAL: Each invocation has exactly ONE completion (enforced by UNIQUE constraint).
@@ -70,8 +74,34 @@ CREATE TABLE IF NOT EXISTS provenance_edges (
     id INTEGER PRIMARY KEY,           -- Auto-increment (store FK only)
     sync_firing_id INTEGER NOT NULL REFERENCES sync_firings(id),
     invocation_id TEXT NOT NULL REFERENCES invocations(id),
-    UNIQUE(sync_firing_id)            -- Each firing produces exactly one invocation
+    UNIQUE(sync_firing_id, invocation_id)  -- A firing may produce several invocations, each linked once
 );
 
 CREATE INDEX IF NOT EXISTS idx_provenance_invocation
     ON provenance_edges(invocation_id);
+
+-- Abandoned Firings: Orphaned sync firings that crash recovery could not complete
+-- An abandoned firing is resolved - it no longer counts as orphaned.
+CREATE TABLE IF NOT EXISTS abandoned_firings (
+    sync_firing_id INTEGER PRIMARY KEY REFERENCES sync_firings(id),
+    reason TEXT NOT NULL              -- Why the intended invocation could not be regenerated
+);
+
+-- Scheduled Firings: Sync firings deferred by ThenClause.AfterSteps, not yet released
+-- Rows are deleted when the engine releases the firing (which writes sync_firings
+-- as usual) or drops the flow. A pending row keeps its flow incomplete.
+CREATE TABLE IF NOT EXISTS scheduled_firings (
+    id INTEGER PRIMARY KEY,           -- Auto-increment (scheduling order within a target seq)
+    completion_id TEXT NOT NULL REFERENCES completions(id),
+    sync_id TEXT NOT NULL,            -- Sync rule identifier
+    binding_hash TEXT NOT NULL,       -- Hash of binding values via ir.BindingHash()
+    flow_token TEXT NOT NULL,         -- Flow the firing belongs to
+    bindings TEXT NOT NULL,           -- Canonical JSON (IRObject)
+    target_seq INTEGER NOT NULL,      -- Released once the logical clock reaches this seq (CP-2)
+    UNIQUE(completion_id, sync_id, binding_hash)  -- Scheduled at most once per binding (CP-1)
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_firings_target
+    ON scheduled_firings(target_seq);
+CREATE INDEX IF NOT EXISTS idx_scheduled_firings_flow_token
+    ON scheduled_firings(flow_token);
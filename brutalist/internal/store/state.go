@@ -0,0 +1,244 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+// validIdentifier matches valid SQL identifiers (table/column names).
+// Identifiers cannot be parameterized, so only alphanumerics and underscore
+// are allowed, starting with a letter or underscore (same rule as the
+// harness final_state assertion).
+var validIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// eventTables are the store's own tables. They are written only through the
+// typed event API, never through ExecState.
+var eventTables = map[string]bool{
+	"invocations":       true,
+	"completions":       true,
+	"sync_firings":      true,
+	"provenance_edges":  true,
+	"abandoned_firings": true,
+	"scheduled_firings": true,
+}
+
+// ExecState inserts one row into a concept state table.
+//
+// Table and column names must be plain identifiers and must exist in the
+// table; values are always parameterized (HIGH-3). IRString, IRInt, IRBool
+// and IRNull are supported; arrays and objects are rejected. The store's
+// event tables cannot be written this way.
+func (s *Store) ExecState(ctx context.Context, table string, row ir.IRObject) error {
+	if eventTables[table] {
+		return fmt.Errorf("exec state: %s is an event table, not a state table", table)
+	}
+	if len(row) == 0 {
+		return fmt.Errorf("exec state %s: row has no columns", table)
+	}
+	columns, err := s.stateColumns(ctx, table)
+	if err != nil {
+		return fmt.Errorf("exec state: %w", err)
+	}
+
+	names := sortedStateKeys(row)
+	args := make([]any, len(names))
+	for i, name := range names {
+		if err := checkStateColumn(table, name, columns); err != nil {
+			return fmt.Errorf("exec state: %w", err)
+		}
+		args[i], err = stateParam(row[name])
+		if err != nil {
+			return fmt.Errorf("exec state %s.%s: %w", table, name, err)
+		}
+	}
+
+	// Identifiers validated above; values parameterized
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		table,
+		strings.Join(names, ", "),
+		strings.TrimSuffix(strings.Repeat("?, ", len(names)), ", "))
+	if _, err := s.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("exec state %s: %w", table, err)
+	}
+	return nil
+}
+
+// QueryState returns the rows of a concept state table whose columns equal
+// every value in where (all rows when where is empty). An IRNull value
+// matches NULL columns.
+//
+// Results are ordered by seq ASC, id COLLATE BINARY ASC (CP-4) when the
+// table has both columns, otherwise by insertion order (rowid). SQL NULL is
+// returned as IRNull; REAL values are rejected (CP-5).
+//
+// Returns an empty slice (not nil) if no rows match.
+func (s *Store) QueryState(ctx context.Context, table string, where ir.IRObject) ([]ir.IRObject, error) {
+	columns, err := s.stateColumns(ctx, table)
+	if err != nil {
+		return nil, fmt.Errorf("query state: %w", err)
+	}
+
+	var clauses []string
+	var args []any
+	for _, name := range sortedStateKeys(where) {
+		if err := checkStateColumn(table, name, columns); err != nil {
+			return nil, fmt.Errorf("query state: %w", err)
+		}
+		if _, isNull := where[name].(ir.IRNull); isNull {
+			clauses = append(clauses, name+" IS NULL")
+			continue
+		}
+		param, err := stateParam(where[name])
+		if err != nil {
+			return nil, fmt.Errorf("query state %s.%s: %w", table, name, err)
+		}
+		clauses = append(clauses, name+" = ?")
+		args = append(args, param)
+	}
+
+	// Identifiers validated above; values parameterized
+	query := "SELECT * FROM " + table
+	if len(clauses) > 0 {
+		query += " WHERE " + strings.Join(clauses, " AND ")
+	}
+	if columns["seq"] && columns["id"] {
+		query += " ORDER BY seq ASC, id COLLATE BINARY ASC"
+	} else {
+		query += " ORDER BY rowid ASC"
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query state %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	names, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("query state %s: columns: %w", table, err)
+	}
+
+	result := []ir.IRObject{}
+	for rows.Next() {
+		values := make([]any, len(names))
+		ptrs := make([]any, len(names))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, fmt.Errorf("query state %s: scan: %w", table, err)
+		}
+
+		obj := make(ir.IRObject, len(names))
+		for i, name := range names {
+			v, err := stateValue(values[i])
+			if err != nil {
+				return nil, fmt.Errorf("query state %s.%s: %w", table, name, err)
+			}
+			obj[name] = v
+		}
+		result = append(result, obj)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query state %s: iterate: %w", table, err)
+	}
+
+	return result, nil
+}
+
+// stateColumns validates a table name and returns its column set.
+// Returns an error if the table does not exist.
+func (s *Store) stateColumns(ctx context.Context, table string) (map[string]bool, error) {
+	if !validIdentifier.MatchString(table) {
+		return nil, fmt.Errorf("invalid table name %q: must match pattern %s", table, validIdentifier.String())
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT name FROM pragma_table_info(?)`, table)
+	if err != nil {
+		return nil, fmt.Errorf("read columns of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("scan column of %s: %w", table, err)
+		}
+		columns[name] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate columns of %s: %w", table, err)
+	}
+
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("unknown state table %q", table)
+	}
+	return columns, nil
+}
+
+// checkStateColumn validates a column name and that the table has it.
+func checkStateColumn(table, name string, columns map[string]bool) error {
+	if !validIdentifier.MatchString(name) {
+		return fmt.Errorf("invalid column name %q: must match pattern %s", name, validIdentifier.String())
+	}
+	if !columns[name] {
+		return fmt.Errorf("unknown column %q in state table %s", name, table)
+	}
+	return nil
+}
+
+// sortedStateKeys returns the keys of obj sorted for deterministic SQL.
+func sortedStateKeys(obj ir.IRObject) []string {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// stateParam converts a scalar IRValue to a SQL parameter.
+func stateParam(v ir.IRValue) (any, error) {
+	switch val := v.(type) {
+	case ir.IRString:
+		return string(val), nil
+	case ir.IRInt:
+		return int64(val), nil
+	case ir.IRBool:
+		return bool(val), nil
+	case ir.IRNull:
+		return nil, nil
+	case nil:
+		return nil, fmt.Errorf("value is nil")
+	default:
+		return nil, fmt.Errorf("%T cannot be stored in a state column", v)
+	}
+}
+
+// stateValue converts a scanned SQL value to an IRValue.
+// SQL NULL maps to IRNull; floats are forbidden (CP-5).
+func stateValue(v any) (ir.IRValue, error) {
+	switch val := v.(type) {
+	case nil:
+		return ir.IRNull{}, nil
+	case int64:
+		return ir.IRInt(val), nil
+	case string:
+		return ir.IRString(val), nil
+	case []byte:
+		return ir.IRString(string(val)), nil
+	case bool:
+		return ir.IRBool(val), nil
+	case float64:
+		return nil, fmt.Errorf("float values are forbidden in IR (CP-5): %v", val)
+	default:
+		return nil, fmt.Errorf("unsupported SQL type: %T", v)
+	}
+}
@@ -0,0 +1,187 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+// createStateTable creates a CartItems concept state table.
+func createStateTable(t *testing.T, s *Store) {
+	t.Helper()
+	_, err := s.db.Exec(`CREATE TABLE CartItems (id TEXT PRIMARY KEY, seq INTEGER, cart_id TEXT, item_id TEXT, quantity INTEGER, note TEXT)`)
+	if err != nil {
+		t.Fatalf("create table failed: %v", err)
+	}
+}
+
+func TestExecState_QueryState(t *testing.T) {
+	store := createTestStore(t)
+	createStateTable(t, store)
+	ctx := context.Background()
+
+	rows := []ir.IRObject{
+		{"id": ir.IRString("ci-2"), "seq": ir.IRInt(2), "cart_id": ir.IRString("cart-1"), "item_id": ir.IRString("gizmo"), "quantity": ir.IRInt(5)},
+		{"id": ir.IRString("ci-3"), "seq": ir.IRInt(1), "cart_id": ir.IRString("cart-1"), "item_id": ir.IRString("widget"), "quantity": ir.IRInt(2)},
+		{"id": ir.IRString("ci-1"), "seq": ir.IRInt(2), "cart_id": ir.IRString("cart-1"), "item_id": ir.IRString("gadget"), "quantity": ir.IRInt(1)},
+		{"id": ir.IRString("ci-4"), "seq": ir.IRInt(3), "cart_id": ir.IRString("cart-2"), "item_id": ir.IRString("widget"), "quantity": ir.IRInt(9)},
+	}
+	for _, row := range rows {
+		if err := store.ExecState(ctx, "CartItems", row); err != nil {
+			t.Fatalf("ExecState failed: %v", err)
+		}
+	}
+
+	got, err := store.QueryState(ctx, "CartItems", ir.IRObject{"cart_id": ir.IRString("cart-1")})
+	if err != nil {
+		t.Fatalf("QueryState failed: %v", err)
+	}
+
+	// Ordered by seq ASC, id ASC (CP-4), not insertion order
+	wantIDs := []string{"ci-3", "ci-1", "ci-2"}
+	if len(got) != len(wantIDs) {
+		t.Fatalf("got %d rows, want %d", len(got), len(wantIDs))
+	}
+	for i, id := range wantIDs {
+		if got[i]["id"] != ir.IRString(id) {
+			t.Errorf("row %d id = %v, want %s", i, got[i]["id"], id)
+		}
+	}
+
+	// Every column is returned; unset columns are IRNull
+	first := got[0]
+	if first["quantity"] != ir.IRInt(2) || first["item_id"] != ir.IRString("widget") {
+		t.Errorf("row = %v, want widget x2", first)
+	}
+	if first["note"] != (ir.IRNull{}) {
+		t.Errorf("note = %v, want IRNull", first["note"])
+	}
+
+	// Multiple conditions are ANDed; NULL matches with IS NULL
+	got, err = store.QueryState(ctx, "CartItems", ir.IRObject{
+		"item_id": ir.IRString("widget"),
+		"note":    ir.IRNull{},
+		"seq":     ir.IRInt(3),
+	})
+	if err != nil {
+		t.Fatalf("QueryState failed: %v", err)
+	}
+	if len(got) != 1 || got[0]["id"] != ir.IRString("ci-4") {
+		t.Errorf("got %v, want only ci-4", got)
+	}
+
+	// No match returns an empty, non-nil slice
+	got, err = store.QueryState(ctx, "CartItems", ir.IRObject{"cart_id": ir.IRString("cart-9")})
+	if err != nil {
+		t.Fatalf("QueryState failed: %v", err)
+	}
+	if got == nil || len(got) != 0 {
+		t.Errorf("got %v, want empty non-nil slice", got)
+	}
+}
+
+func TestQueryState_ValuesAreParameterized(t *testing.T) {
+	store := createTestStore(t)
+	createStateTable(t, store)
+	ctx := context.Background()
+
+	hostile := ir.IRString("x'); DROP TABLE CartItems; --")
+	if err := store.ExecState(ctx, "CartItems", ir.IRObject{"id": ir.IRString("ci-1"), "note": hostile}); err != nil {
+		t.Fatalf("ExecState failed: %v", err)
+	}
+
+	got, err := store.QueryState(ctx, "CartItems", ir.IRObject{"note": hostile})
+	if err != nil {
+		t.Fatalf("QueryState failed: %v", err)
+	}
+	if len(got) != 1 || got[0]["note"] != hostile {
+		t.Errorf("got %v, want the stored row with the literal note", got)
+	}
+}
+
+func TestQueryState_InsertionOrderWithoutSeq(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+	if _, err := store.db.Exec(`CREATE TABLE Tags (name TEXT)`); err != nil {
+		t.Fatalf("create table failed: %v", err)
+	}
+
+	for _, name := range []string{"b", "c", "a"} {
+		if err := store.ExecState(ctx, "Tags", ir.IRObject{"name": ir.IRString(name)}); err != nil {
+			t.Fatalf("ExecState failed: %v", err)
+		}
+	}
+
+	got, err := store.QueryState(ctx, "Tags", nil)
+	if err != nil {
+		t.Fatalf("QueryState failed: %v", err)
+	}
+	var names []string
+	for _, row := range got {
+		names = append(names, string(row["name"].(ir.IRString)))
+	}
+	if strings.Join(names, ",") != "b,c,a" {
+		t.Errorf("names = %v, want insertion order [b c a]", names)
+	}
+}
+
+func TestExecState_QueryState_Rejected(t *testing.T) {
+	store := createTestStore(t)
+	createStateTable(t, store)
+	ctx := context.Background()
+
+	execTests := []struct {
+		name    string
+		table   string
+		row     ir.IRObject
+		wantErr string
+	}{
+		{"invalid table", "CartItems; DROP TABLE invocations", ir.IRObject{"id": ir.IRString("x")}, "invalid table name"},
+		{"unknown table", "Missing", ir.IRObject{"id": ir.IRString("x")}, `unknown state table "Missing"`},
+		{"event table", "invocations", ir.IRObject{"id": ir.IRString("x")}, "invocations is an event table"},
+		{"invalid column", "CartItems", ir.IRObject{"id = 1 OR 1": ir.IRString("x")}, "invalid column name"},
+		{"unknown column", "CartItems", ir.IRObject{"price": ir.IRInt(1)}, `unknown column "price"`},
+		{"empty row", "CartItems", ir.IRObject{}, "row has no columns"},
+		{"object value", "CartItems", ir.IRObject{"note": ir.IRObject{}}, "cannot be stored in a state column"},
+	}
+	for _, tt := range execTests {
+		t.Run("exec "+tt.name, func(t *testing.T) {
+			err := store.ExecState(ctx, tt.table, tt.row)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ExecState error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	queryTests := []struct {
+		name    string
+		table   string
+		where   ir.IRObject
+		wantErr string
+	}{
+		{"invalid table", "CartItems WHERE 1=1", nil, "invalid table name"},
+		{"unknown table", "Missing", nil, `unknown state table "Missing"`},
+		{"invalid column", "CartItems", ir.IRObject{"1=1 --": ir.IRInt(1)}, "invalid column name"},
+		{"unknown column", "CartItems", ir.IRObject{"price": ir.IRInt(1)}, `unknown column "price"`},
+		{"array value", "CartItems", ir.IRObject{"note": ir.IRArray{}}, "cannot be stored in a state column"},
+	}
+	for _, tt := range queryTests {
+		t.Run("query "+tt.name, func(t *testing.T) {
+			_, err := store.QueryState(ctx, tt.table, tt.where)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("QueryState error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Nothing was written by the rejected calls
+	got, err := store.QueryState(ctx, "CartItems", nil)
+	if err != nil {
+		t.Fatalf("QueryState failed: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("got %v, want no rows", got)
+	}
+}
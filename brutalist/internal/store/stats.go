@@ -0,0 +1,134 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+// CountByAction returns the number of invocations per action across all flows.
+// Answers: "how many times was Inventory.reserve invoked?"
+//
+// The counts themselves are deterministic, but Go map iteration order is not;
+// sort the keys if stable output is needed.
+//
+// Returns an empty map (not nil) if the store has no invocations.
+func (s *Store) CountByAction(ctx context.Context) (map[ir.ActionRef]int64, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT action_uri, COUNT(*)
+		FROM invocations
+		GROUP BY action_uri
+		ORDER BY action_uri COLLATE BINARY ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("count by action: %w", err)
+	}
+	return scanActionCounts(rows)
+}
+
+// CountByActionForFlow returns the number of invocations per action within a flow.
+// Same semantics as CountByAction, scoped to one flow token.
+func (s *Store) CountByActionForFlow(ctx context.Context, flowToken string) (map[ir.ActionRef]int64, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT action_uri, COUNT(*)
+		FROM invocations
+		WHERE flow_token = ?
+		GROUP BY action_uri
+		ORDER BY action_uri COLLATE BINARY ASC
+	`, flowToken)
+	if err != nil {
+		return nil, fmt.Errorf("count by action for flow: %w", err)
+	}
+	return scanActionCounts(rows)
+}
+
+// scanActionCounts reads (action_uri, count) rows into a map and closes rows.
+func scanActionCounts(rows *sql.Rows) (map[ir.ActionRef]int64, error) {
+	defer rows.Close()
+
+	counts := make(map[ir.ActionRef]int64)
+	for rows.Next() {
+		var action string
+		var n int64
+		if err := rows.Scan(&action, &n); err != nil {
+			return nil, fmt.Errorf("scan action count: %w", err)
+		}
+		counts[ir.ActionRef(action)] = n
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate action counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// CountCompletionsByTenant returns the number of completions per tenant_id
+// in their security context (CP-6), across all flows.
+// The engine uses it to rebuild per-tenant rate-limit budgets on replay.
+//
+// Returns an empty map (not nil) if the store has no completions.
+func (s *Store) CountCompletionsByTenant(ctx context.Context) (map[string]int64, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT json_extract(security_context, '$.tenant_id'), COUNT(*)
+		FROM completions
+		GROUP BY 1
+		ORDER BY 1 COLLATE BINARY ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("count completions by tenant: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int64)
+	for rows.Next() {
+		var tenantID string
+		var n int64
+		if err := rows.Scan(&tenantID, &n); err != nil {
+			return nil, fmt.Errorf("scan tenant count: %w", err)
+		}
+		counts[tenantID] = n
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate tenant counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// ReadSpecHashes returns the distinct non-empty spec hashes recorded on
+// invocations, ordered by the seq at which each first appears (ties by hash).
+// The engine compares them with its own spec hash before replay.
+//
+// Returns an empty slice (not nil) if no invocation records a spec hash.
+func (s *Store) ReadSpecHashes(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT spec_hash
+		FROM invocations
+		WHERE spec_hash != ''
+		GROUP BY spec_hash
+		ORDER BY MIN(seq) ASC, spec_hash COLLATE BINARY ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("read spec hashes: %w", err)
+	}
+	defer rows.Close()
+
+	hashes := []string{}
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, fmt.Errorf("scan spec hash: %w", err)
+		}
+		hashes = append(hashes, hash)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate spec hashes: %w", err)
+	}
+
+	return hashes, nil
+}
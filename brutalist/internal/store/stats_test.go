@@ -0,0 +1,179 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+// seedMixedActions writes invocations of several actions across two flows.
+func seedMixedActions(t *testing.T, s *Store) {
+	t.Helper()
+	ctx := context.Background()
+
+	invs := []ir.Invocation{
+		createTestInvocation("inv-1", "flow-1", "Cart.addItem", 1),
+		createTestInvocation("inv-2", "flow-1", "Cart.addItem", 2),
+		createTestInvocation("inv-3", "flow-1", "Inventory.reserve", 3),
+		createTestInvocation("inv-4", "flow-2", "Inventory.reserve", 4),
+		createTestInvocation("inv-5", "flow-2", "Inventory.reserve", 5),
+		createTestInvocation("inv-6", "flow-2", "Cart.checkout", 6),
+	}
+	if err := s.WriteInvocations(ctx, invs); err != nil {
+		t.Fatalf("WriteInvocations failed: %v", err)
+	}
+}
+
+func TestCountByAction(t *testing.T) {
+	store := createTestStore(t)
+	seedMixedActions(t, store)
+
+	counts, err := store.CountByAction(context.Background())
+	if err != nil {
+		t.Fatalf("CountByAction failed: %v", err)
+	}
+
+	want := map[ir.ActionRef]int64{
+		"Cart.addItem":      2,
+		"Inventory.reserve": 3,
+		"Cart.checkout":     1,
+	}
+	if len(counts) != len(want) {
+		t.Fatalf("counts = %v, want %v", counts, want)
+	}
+	for action, n := range want {
+		if counts[action] != n {
+			t.Errorf("counts[%s] = %d, want %d", action, counts[action], n)
+		}
+	}
+}
+
+func TestCountByActionForFlow(t *testing.T) {
+	store := createTestStore(t)
+	seedMixedActions(t, store)
+
+	counts, err := store.CountByActionForFlow(context.Background(), "flow-2")
+	if err != nil {
+		t.Fatalf("CountByActionForFlow failed: %v", err)
+	}
+
+	want := map[ir.ActionRef]int64{
+		"Inventory.reserve": 2,
+		"Cart.checkout":     1,
+	}
+	if len(counts) != len(want) {
+		t.Fatalf("counts = %v, want %v", counts, want)
+	}
+	for action, n := range want {
+		if counts[action] != n {
+			t.Errorf("counts[%s] = %d, want %d", action, counts[action], n)
+		}
+	}
+}
+
+func TestCountByAction_Empty(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+
+	counts, err := store.CountByAction(ctx)
+	if err != nil {
+		t.Fatalf("CountByAction failed: %v", err)
+	}
+	if counts == nil || len(counts) != 0 {
+		t.Errorf("counts = %v, want empty map", counts)
+	}
+
+	counts, err = store.CountByActionForFlow(ctx, "no-such-flow")
+	if err != nil {
+		t.Fatalf("CountByActionForFlow failed: %v", err)
+	}
+	if counts == nil || len(counts) != 0 {
+		t.Errorf("counts = %v, want empty map", counts)
+	}
+}
+
+func TestCountCompletionsByTenant(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+
+	tenants := []string{"tenant-a", "tenant-b", "tenant-a"}
+	for i, tenant := range tenants {
+		n := int64(i + 1)
+		inv := createTestInvocation(fmt.Sprintf("inv-%d", n), "flow-1", "Cart.addItem", n*2-1)
+		if err := store.WriteInvocation(ctx, inv); err != nil {
+			t.Fatalf("WriteInvocation failed: %v", err)
+		}
+		comp := createTestCompletion(fmt.Sprintf("comp-%d", n), inv.ID, "Success", n*2)
+		comp.SecurityContext = ir.NewSecurityContext(tenant, "user-1")
+		if err := store.WriteCompletion(ctx, comp); err != nil {
+			t.Fatalf("WriteCompletion failed: %v", err)
+		}
+	}
+
+	counts, err := store.CountCompletionsByTenant(ctx)
+	if err != nil {
+		t.Fatalf("CountCompletionsByTenant failed: %v", err)
+	}
+
+	want := map[string]int64{"tenant-a": 2, "tenant-b": 1}
+	if len(counts) != len(want) {
+		t.Fatalf("counts = %v, want %v", counts, want)
+	}
+	for tenant, n := range want {
+		if counts[tenant] != n {
+			t.Errorf("counts[%s] = %d, want %d", tenant, counts[tenant], n)
+		}
+	}
+}
+
+func TestCountCompletionsByTenant_Empty(t *testing.T) {
+	store := createTestStore(t)
+
+	counts, err := store.CountCompletionsByTenant(context.Background())
+	if err != nil {
+		t.Fatalf("CountCompletionsByTenant failed: %v", err)
+	}
+	if counts == nil || len(counts) != 0 {
+		t.Errorf("counts = %v, want empty map", counts)
+	}
+}
+
+func TestReadSpecHashes(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+
+	hashes, err := store.ReadSpecHashes(ctx)
+	if err != nil {
+		t.Fatalf("ReadSpecHashes failed: %v", err)
+	}
+	if hashes == nil || len(hashes) != 0 {
+		t.Fatalf("empty store: hashes = %v, want empty non-nil slice", hashes)
+	}
+
+	invs := []ir.Invocation{
+		createTestInvocation("inv-1", "flow-1", "Cart.addItem", 1),
+		createTestInvocation("inv-2", "flow-1", "Cart.addItem", 2),
+		createTestInvocation("inv-3", "flow-2", "Cart.addItem", 3),
+		createTestInvocation("inv-4", "flow-2", "Cart.addItem", 4),
+	}
+	invs[0].SpecHash = "hash-b"
+	invs[1].SpecHash = "" // Unversioned invocations are ignored
+	invs[2].SpecHash = "hash-a"
+	invs[3].SpecHash = "hash-b"
+	if err := store.WriteInvocations(ctx, invs); err != nil {
+		t.Fatalf("WriteInvocations failed: %v", err)
+	}
+
+	hashes, err = store.ReadSpecHashes(ctx)
+	if err != nil {
+		t.Fatalf("ReadSpecHashes failed: %v", err)
+	}
+
+	// Ordered by first appearance, not by hash
+	want := []string{"hash-b", "hash-a"}
+	if fmt.Sprint(hashes) != fmt.Sprint(want) {
+		t.Errorf("hashes = %v, want %v", hashes, want)
+	}
+}
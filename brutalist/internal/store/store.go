@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	_ "embed"
 	"fmt"
+	"strings"
 
 	_ "github.com/mattn/go-sqlite3"
 )
@@ -15,12 +16,14 @@ var schemaSQL string
 // Schema version tracking:
 // 0 - Initial schema (pre-migration)
 // 1 - Added UNIQUE index on completions.invocation_id
-const currentSchemaVersion = 1
+// 2 - provenance_edges unique on (sync_firing_id, invocation_id) for multi-invocation firings
+const currentSchemaVersion = 2
 
 // Store provides durable storage for NYSM event logs.
 // Uses SQLite with WAL mode for concurrent read access.
 type Store struct {
-	db *sql.DB
+	db   *sql.DB
+	path string // Database file path, for on-disk size reporting
 }
 
 // Open creates or opens a SQLite database at the given path.
@@ -63,7 +66,7 @@ func Open(path string) (*Store, error) {
 		return nil, fmt.Errorf("failed to apply schema: %w", err)
 	}
 
-	return &Store{db: db}, nil
+	return &Store{db: db, path: path}, nil
 }
 
 // Close closes the database connection.
@@ -76,7 +79,8 @@ func (s *Store) Close() error {
 }
 
 // DB returns the underlying sql.DB for direct queries.
-// Use with caution - prefer using Store methods when available.
+// Use with caution - prefer using Store methods when available
+// (ExecState and QueryState for concept state tables).
 func (s *Store) DB() *sql.DB {
 	return s.db
 }
@@ -135,6 +139,12 @@ func runMigrations(db *sql.DB) error {
 		}
 		version = 1
 	}
+	if version < 2 {
+		if err := migrateToV2(db); err != nil {
+			return err
+		}
+		version = 2
+	}
 
 	// Set version after all migrations
 	if _, err := db.Exec(fmt.Sprintf("PRAGMA user_version = %d", currentSchemaVersion)); err != nil {
@@ -159,6 +169,54 @@ func migrateToV1(db *sql.DB) error {
 	return nil
 }
 
+// migrateToV2 relaxes the provenance_edges uniqueness from UNIQUE(sync_firing_id)
+// to UNIQUE(sync_firing_id, invocation_id) so one firing can link several
+// generated invocations. SQLite cannot drop a table constraint, so databases
+// created before v2 have the table rebuilt with its rows (and IDs) preserved.
+// Tables already created from the v2 schema.sql are left untouched.
+func migrateToV2(db *sql.DB) error {
+	var ddl string
+	err := db.QueryRow(`
+		SELECT sql FROM sqlite_master WHERE type = 'table' AND name = 'provenance_edges'
+	`).Scan(&ddl)
+	if err != nil {
+		return fmt.Errorf("migrate to v2: read provenance_edges schema: %w", err)
+	}
+	if !strings.Contains(ddl, "UNIQUE(sync_firing_id)") {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("migrate to v2: begin tx: %w", err)
+	}
+	defer tx.Rollback() // No-op if committed
+
+	stmts := []string{
+		`CREATE TABLE provenance_edges_v2 (
+			id INTEGER PRIMARY KEY,
+			sync_firing_id INTEGER NOT NULL REFERENCES sync_firings(id),
+			invocation_id TEXT NOT NULL REFERENCES invocations(id),
+			UNIQUE(sync_firing_id, invocation_id)
+		)`,
+		`INSERT INTO provenance_edges_v2 (id, sync_firing_id, invocation_id)
+			SELECT id, sync_firing_id, invocation_id FROM provenance_edges`,
+		`DROP TABLE provenance_edges`,
+		`ALTER TABLE provenance_edges_v2 RENAME TO provenance_edges`,
+		`CREATE INDEX IF NOT EXISTS idx_provenance_invocation ON provenance_edges(invocation_id)`,
+	}
+	for _, stmt := range stmts {
+		if _, err := tx.Exec(stmt); err != nil {
+			return fmt.Errorf("migrate to v2: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("migrate to v2: commit: %w", err)
+	}
+	return nil
+}
+
 // verifyPragma checks that a pragma is set to the expected value.
 // Used for testing.
 func (s *Store) verifyPragma(name, expected string) error {
@@ -1,9 +1,11 @@
 package store
 
 import (
+	"context"
 	"database/sql"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -513,13 +515,22 @@ func TestConstraint_ProvenanceEdgeUniqueSyncFiring(t *testing.T) {
 		t.Fatalf("failed to insert first provenance edge: %v", err)
 	}
 
-	// Try to insert another edge for same sync_firing_id
+	// Try to insert the same (sync_firing_id, invocation_id) edge again
 	_, err = s.db.Exec(`
 		INSERT INTO provenance_edges (sync_firing_id, invocation_id)
 		VALUES (?, 'inv2')
 	`, syncFiringID)
 	if err == nil {
-		t.Error("expected UNIQUE constraint violation on sync_firing_id, got nil")
+		t.Error("expected UNIQUE constraint violation on (sync_firing_id, invocation_id), got nil")
+	}
+
+	// A second invocation for the same firing is allowed (multi-action then-clauses)
+	_, err = s.db.Exec(`
+		INSERT INTO provenance_edges (sync_firing_id, invocation_id)
+		VALUES (?, 'inv1')
+	`, syncFiringID)
+	if err != nil {
+		t.Errorf("expected second invocation edge for same firing to be allowed, got %v", err)
 	}
 }
 
@@ -726,6 +737,74 @@ func TestMigration_UpgradeFromV0(t *testing.T) {
 	}
 }
 
+func TestMigration_V2ProvenanceEdgesRebuilt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+
+	// Build a v1 database: provenance_edges unique on sync_firing_id alone
+	v1Schema := strings.Replace(schemaSQL,
+		"UNIQUE(sync_firing_id, invocation_id)",
+		"UNIQUE(sync_firing_id)", 1)
+	if v1Schema == schemaSQL {
+		t.Fatal("schema.sql no longer contains the v2 provenance_edges constraint")
+	}
+
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	stmts := []string{
+		v1Schema,
+		"PRAGMA user_version = 1",
+		`INSERT INTO invocations (id, flow_token, action_uri, args, seq, security_context, spec_hash, engine_version, ir_version)
+			VALUES ('inv1', 'flow1', 'Test.action', '{}', 1, '{}', 'hash1', '1.0', '1.0'),
+			       ('inv2', 'flow1', 'Test.action2', '{}', 4, '{}', 'hash1', '1.0', '1.0'),
+			       ('inv3', 'flow1', 'Test.action3', '{}', 5, '{}', 'hash1', '1.0', '1.0')`,
+		`INSERT INTO completions (id, invocation_id, output_case, result, seq, security_context)
+			VALUES ('comp1', 'inv1', 'Success', '{}', 2, '{}')`,
+		`INSERT INTO sync_firings (id, completion_id, sync_id, binding_hash, seq)
+			VALUES (7, 'comp1', 'sync1', 'binding1', 3)`,
+		`INSERT INTO provenance_edges (id, sync_firing_id, invocation_id) VALUES (42, 7, 'inv2')`,
+	}
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			db.Close()
+			t.Fatalf("failed to build v1 database: %v", err)
+		}
+	}
+	db.Close()
+
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() failed: %v", err)
+	}
+	defer s.Close()
+
+	// Existing edge survives with its ID
+	var invID string
+	if err := s.db.QueryRow("SELECT invocation_id FROM provenance_edges WHERE id = 42").Scan(&invID); err != nil {
+		t.Fatalf("existing edge lost in migration: %v", err)
+	}
+	if invID != "inv2" {
+		t.Errorf("edge 42 invocation_id = %q, want %q", invID, "inv2")
+	}
+
+	// A second invocation for the same firing is now accepted
+	if _, err := s.WriteProvenanceEdge(context.Background(), 7, "inv3"); err != nil {
+		t.Fatalf("WriteProvenanceEdge failed: %v", err)
+	}
+	var count int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM provenance_edges WHERE sync_firing_id = 7").Scan(&count); err != nil {
+		t.Fatalf("count edges: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("edges for firing 7 = %d, want 2", count)
+	}
+
+	if indexes := getTableIndexes(t, s.db, "provenance_edges"); !contains(indexes, "idx_provenance_invocation") {
+		t.Errorf("expected idx_provenance_invocation after migration, got indexes: %v", indexes)
+	}
+}
+
 // Helper functions
 
 func getTableColumns(t *testing.T, db *sql.DB, table string) []string {
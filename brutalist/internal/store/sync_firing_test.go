@@ -310,3 +310,215 @@ func TestReadSyncFiringsForCompletion_Empty(t *testing.T) {
 		t.Errorf("expected 0 firings, got %d", len(firings))
 	}
 }
+
+func TestWriteSyncFiringAtomicMulti_TwoInvocations(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+
+	if err := store.WriteInvocation(ctx, createTestInvocation("inv-1", "flow-1", "Cart.checkout", 1)); err != nil {
+		t.Fatalf("WriteInvocation failed: %v", err)
+	}
+	if err := store.WriteCompletion(ctx, createTestCompletion("comp-1", "inv-1", "Success", 2)); err != nil {
+		t.Fatalf("WriteCompletion failed: %v", err)
+	}
+
+	firing := ir.SyncFiring{
+		CompletionID: "comp-1",
+		SyncID:       "checkout-fanout",
+		BindingHash:  "hash-123",
+		Seq:          3,
+	}
+	invs := []ir.Invocation{
+		createTestInvocation("inv-reserve", "flow-1", "Inventory.reserve", 4),
+		createTestInvocation("inv-charge", "flow-1", "Payment.charge", 5),
+	}
+
+	firingID, inserted, err := store.WriteSyncFiringAtomicMulti(ctx, firing, invs)
+	if err != nil {
+		t.Fatalf("WriteSyncFiringAtomicMulti failed: %v", err)
+	}
+	if !inserted {
+		t.Error("expected inserted=true for new firing")
+	}
+
+	edges, err := store.ReadProvenanceEdgesForFiring(ctx, firingID)
+	if err != nil {
+		t.Fatalf("ReadProvenanceEdgesForFiring failed: %v", err)
+	}
+	if len(edges) != 2 {
+		t.Fatalf("expected 2 provenance edges, got %d", len(edges))
+	}
+	for i, want := range []string{"inv-reserve", "inv-charge"} {
+		if edges[i].InvocationID != want {
+			t.Errorf("edges[%d].InvocationID = %q, want %q", i, edges[i].InvocationID, want)
+		}
+		if _, err := store.ReadInvocation(ctx, want); err != nil {
+			t.Errorf("ReadInvocation(%q) failed: %v", want, err)
+		}
+	}
+}
+
+func TestWriteSyncFiringAtomicMulti_IdempotentRefire(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+
+	if err := store.WriteInvocation(ctx, createTestInvocation("inv-1", "flow-1", "Cart.checkout", 1)); err != nil {
+		t.Fatalf("WriteInvocation failed: %v", err)
+	}
+	if err := store.WriteCompletion(ctx, createTestCompletion("comp-1", "inv-1", "Success", 2)); err != nil {
+		t.Fatalf("WriteCompletion failed: %v", err)
+	}
+
+	firing := ir.SyncFiring{
+		CompletionID: "comp-1",
+		SyncID:       "checkout-fanout",
+		BindingHash:  "hash-123",
+		Seq:          3,
+	}
+	firstID, _, err := store.WriteSyncFiringAtomicMulti(ctx, firing, []ir.Invocation{
+		createTestInvocation("inv-reserve", "flow-1", "Inventory.reserve", 4),
+		createTestInvocation("inv-charge", "flow-1", "Payment.charge", 5),
+	})
+	if err != nil {
+		t.Fatalf("first WriteSyncFiringAtomicMulti failed: %v", err)
+	}
+
+	// Re-fire the same (completion, sync, binding) with fresh invocations,
+	// as a replay with a different clock would.
+	secondID, inserted, err := store.WriteSyncFiringAtomicMulti(ctx, firing, []ir.Invocation{
+		createTestInvocation("inv-reserve-2", "flow-1", "Inventory.reserve", 6),
+		createTestInvocation("inv-charge-2", "flow-1", "Payment.charge", 7),
+	})
+	if err != nil {
+		t.Fatalf("second WriteSyncFiringAtomicMulti failed: %v", err)
+	}
+	if inserted {
+		t.Error("expected inserted=false for duplicate firing")
+	}
+	if secondID != firstID {
+		t.Errorf("firing ID = %d, want existing %d", secondID, firstID)
+	}
+
+	var invocations, edges int
+	if err := store.db.QueryRow("SELECT COUNT(*) FROM invocations").Scan(&invocations); err != nil {
+		t.Fatalf("count invocations: %v", err)
+	}
+	if err := store.db.QueryRow("SELECT COUNT(*) FROM provenance_edges").Scan(&edges); err != nil {
+		t.Fatalf("count provenance edges: %v", err)
+	}
+	if invocations != 3 {
+		t.Errorf("invocations = %d, want 3 (re-fire must write nothing)", invocations)
+	}
+	if edges != 2 {
+		t.Errorf("provenance edges = %d, want 2 (re-fire must write nothing)", edges)
+	}
+}
+
+func TestWriteSyncFiringAtomicMulti_NoInvocations(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+
+	_, _, err := store.WriteSyncFiringAtomicMulti(ctx, ir.SyncFiring{
+		CompletionID: "comp-1",
+		SyncID:       "checkout-fanout",
+		BindingHash:  "hash-123",
+		Seq:          3,
+	}, nil)
+	if err == nil {
+		t.Fatal("expected error for firing with no invocations")
+	}
+}
+
+// writeFiringWithInvocation writes inv-1 → comp-1 → firing, plus the
+// triggered invocation inv-2. Returns the firing ID.
+func writeFiringWithInvocation(t *testing.T, store *Store) int64 {
+	t.Helper()
+	ctx := context.Background()
+
+	if err := store.WriteInvocation(ctx, createTestInvocation("inv-1", "flow-1", "Cart.checkout", 1)); err != nil {
+		t.Fatalf("WriteInvocation failed: %v", err)
+	}
+	if err := store.WriteCompletion(ctx, createTestCompletion("comp-1", "inv-1", "Success", 2)); err != nil {
+		t.Fatalf("WriteCompletion failed: %v", err)
+	}
+	firingID, _, err := store.WriteSyncFiring(ctx, ir.SyncFiring{
+		CompletionID: "comp-1", SyncID: "sync-1", BindingHash: "h1", Seq: 3,
+	})
+	if err != nil {
+		t.Fatalf("WriteSyncFiring failed: %v", err)
+	}
+	if err := store.WriteInvocation(ctx, createTestInvocation("inv-2", "flow-1", "Inventory.reserve", 4)); err != nil {
+		t.Fatalf("WriteInvocation failed: %v", err)
+	}
+	return firingID
+}
+
+func TestWriteProvenanceEdge_DuplicateIsNoOp(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+	firingID := writeFiringWithInvocation(t, store)
+
+	inserted, err := store.WriteProvenanceEdge(ctx, firingID, "inv-2")
+	if err != nil {
+		t.Fatalf("WriteProvenanceEdge failed: %v", err)
+	}
+	if !inserted {
+		t.Error("first write: inserted = false, want true")
+	}
+
+	inserted, err = store.WriteProvenanceEdge(ctx, firingID, "inv-2")
+	if err != nil {
+		t.Fatalf("duplicate WriteProvenanceEdge failed: %v", err)
+	}
+	if inserted {
+		t.Error("duplicate write: inserted = true, want false")
+	}
+
+	edges, err := store.ReadProvenance(ctx, "inv-2")
+	if err != nil {
+		t.Fatalf("ReadProvenance failed: %v", err)
+	}
+	if len(edges) != 1 {
+		t.Errorf("len(edges) = %d, want 1", len(edges))
+	}
+}
+
+func TestWriteProvenanceEdge_MissingReferences(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+	firingID := writeFiringWithInvocation(t, store)
+
+	tests := []struct {
+		name              string
+		firingID          int64
+		invocationID      string
+		missingFiring     bool
+		missingInvocation bool
+	}{
+		{name: "missing firing", firingID: firingID + 100, invocationID: "inv-2", missingFiring: true},
+		{name: "missing invocation", firingID: firingID, invocationID: "inv-missing", missingInvocation: true},
+		{name: "both missing", firingID: firingID + 100, invocationID: "inv-missing", missingFiring: true, missingInvocation: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inserted, err := store.WriteProvenanceEdge(ctx, tt.firingID, tt.invocationID)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if inserted {
+				t.Error("inserted = true, want false")
+			}
+			if !IsProvenanceReferenceError(err) {
+				t.Fatalf("expected ProvenanceReferenceError, got %T: %v", err, err)
+			}
+			refErr := err.(*ProvenanceReferenceError)
+			if refErr.MissingFiring != tt.missingFiring {
+				t.Errorf("MissingFiring = %v, want %v", refErr.MissingFiring, tt.missingFiring)
+			}
+			if refErr.MissingInvocation != tt.missingInvocation {
+				t.Errorf("MissingInvocation = %v, want %v", refErr.MissingInvocation, tt.missingInvocation)
+			}
+		})
+	}
+}
@@ -7,6 +7,9 @@ import (
 	"github.com/roach88/nysm/internal/ir"
 )
 
+// testSecurityContext is a valid context (CP-6) for records written in tests.
+var testSecurityContext = ir.NewSecurityContext("test-tenant", "test-user")
+
 // createTestStore creates a new in-memory store for testing.
 func createTestStore(t *testing.T) *Store {
 	t.Helper()
@@ -27,7 +30,7 @@ func createTestInvocation(id, flowToken, actionURI string, seq int64) ir.Invocat
 		ActionURI:       ir.ActionRef(actionURI),
 		Args:            ir.IRObject{},
 		Seq:             seq,
-		SecurityContext: ir.SecurityContext{},
+		SecurityContext: testSecurityContext,
 		SpecHash:        "test-hash",
 		EngineVersion:   "0.1.0",
 		IRVersion:       "1",
@@ -42,6 +45,6 @@ func createTestCompletion(id, invocationID, outputCase string, seq int64) ir.Com
 		OutputCase:      outputCase,
 		Result:          ir.IRObject{},
 		Seq:             seq,
-		SecurityContext: ir.SecurityContext{},
+		SecurityContext: testSecurityContext,
 	}
 }
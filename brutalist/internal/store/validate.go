@@ -0,0 +1,60 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/roach88/nysm/internal/ir"
+)
+
+// InvalidValueError is returned when an invocation's args or a completion's
+// result contains a value that is not a sanctioned IR type.
+//
+// IRValue is a sealed interface, but a type embedding an IR type still
+// satisfies it, so a float (CP-5) or other foreign value can be smuggled in.
+// Writes reject such values at the storage boundary rather than persisting them.
+type InvalidValueError struct {
+	Path string // Location of the offending value, e.g. "result.items[1].price"
+	Type string // Go type of the offending value
+}
+
+func (e *InvalidValueError) Error() string {
+	return fmt.Sprintf("%s: unsupported IR value type %s (only string, int, bool, array, object allowed; CP-5)",
+		e.Path, e.Type)
+}
+
+// IsInvalidValueError returns true if the error is an InvalidValueError.
+func IsInvalidValueError(err error) bool {
+	var e *InvalidValueError
+	return errors.As(err, &e)
+}
+
+// validateIRObject walks an IRObject recursively and returns an
+// *InvalidValueError for the first value that is not a sanctioned IR type.
+// Keys are visited in canonical order so the reported path is deterministic.
+func validateIRObject(path string, obj ir.IRObject) error {
+	for _, key := range obj.SortedKeys() {
+		if err := validateIRValue(path+"."+key, obj[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateIRValue(path string, v ir.IRValue) error {
+	switch val := v.(type) {
+	case ir.IRString, ir.IRInt, ir.IRBool, ir.IRNull:
+		return nil
+	case ir.IRArray:
+		for i, elem := range val {
+			if err := validateIRValue(fmt.Sprintf("%s[%d]", path, i), elem); err != nil {
+				return err
+			}
+		}
+		return nil
+	case ir.IRObject:
+		return validateIRObject(path, val)
+	default:
+		return &InvalidValueError{Path: path, Type: fmt.Sprintf("%T", v)}
+	}
+}
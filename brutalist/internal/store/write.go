@@ -2,7 +2,11 @@ package store
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
 
 	"github.com/roach88/nysm/internal/ir"
 )
@@ -12,19 +16,30 @@ import (
 // Other constraint violations (e.g., NOT NULL) will still return errors.
 //
 // The invocation's Args and SecurityContext are serialized to canonical JSON
-// per RFC 8785 for deterministic replay.
+// per RFC 8785 for deterministic replay. Args containing a value that is not a
+// sanctioned IR type are rejected with *InvalidValueError (CP-5).
+//
+// Use WriteInvocationIfNew to learn whether the row was actually inserted.
 func (s *Store) WriteInvocation(ctx context.Context, inv ir.Invocation) error {
+	_, err := s.WriteInvocationIfNew(ctx, inv)
+	return err
+}
+
+// WriteInvocationIfNew is WriteInvocation that also reports whether the
+// invocation was inserted. inserted is false when a row with the same ID
+// already exists (e.g., on replay); the stored row is left unchanged.
+func (s *Store) WriteInvocationIfNew(ctx context.Context, inv ir.Invocation) (inserted bool, err error) {
 	argsJSON, err := marshalArgs(inv.Args)
 	if err != nil {
-		return fmt.Errorf("write invocation: %w", err)
+		return false, fmt.Errorf("write invocation: %w", err)
 	}
 
 	secCtxJSON, err := marshalSecurityContext(inv.SecurityContext)
 	if err != nil {
-		return fmt.Errorf("write invocation: %w", err)
+		return false, fmt.Errorf("write invocation: %w", err)
 	}
 
-	_, err = s.db.ExecContext(ctx, `
+	result, err := s.db.ExecContext(ctx, `
 		INSERT INTO invocations
 		(id, flow_token, action_uri, args, seq, security_context, spec_hash, engine_version, ir_version)
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
@@ -41,7 +56,76 @@ func (s *Store) WriteInvocation(ctx context.Context, inv ir.Invocation) error {
 		inv.IRVersion,
 	)
 	if err != nil {
-		return fmt.Errorf("write invocation: %w", err)
+		return false, fmt.Errorf("write invocation: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("write invocation: rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// WriteInvocations inserts a batch of invocations in a single transaction
+// using one prepared statement, avoiding per-row transaction overhead when
+// loading historical data or large setups.
+//
+// Per-row semantics match WriteInvocation: ON CONFLICT(id) DO NOTHING, so a
+// row whose ID already exists (in the store or earlier in the same batch) is
+// silently skipped while the remaining rows are written. Any other failure
+// (marshal error, NOT NULL violation) rolls back the entire batch.
+func (s *Store) WriteInvocations(ctx context.Context, invs []ir.Invocation) error {
+	if len(invs) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("write invocations: begin tx: %w", err)
+	}
+	defer tx.Rollback() // No-op if committed
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO invocations
+		(id, flow_token, action_uri, args, seq, security_context, spec_hash, engine_version, ir_version)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO NOTHING
+	`)
+	if err != nil {
+		return fmt.Errorf("write invocations: prepare: %w", err)
+	}
+	defer stmt.Close()
+
+	for i, inv := range invs {
+		argsJSON, err := marshalArgs(inv.Args)
+		if err != nil {
+			return fmt.Errorf("write invocations: row %d: %w", i, err)
+		}
+
+		secCtxJSON, err := marshalSecurityContext(inv.SecurityContext)
+		if err != nil {
+			return fmt.Errorf("write invocations: row %d: %w", i, err)
+		}
+
+		_, err = stmt.ExecContext(ctx,
+			inv.ID,
+			inv.FlowToken,
+			string(inv.ActionURI),
+			argsJSON,
+			inv.Seq,
+			secCtxJSON,
+			inv.SpecHash,
+			inv.EngineVersion,
+			inv.IRVersion,
+		)
+		if err != nil {
+			return fmt.Errorf("write invocations: row %d: %w", i, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("write invocations: commit: %w", err)
 	}
 
 	return nil
@@ -52,26 +136,39 @@ func (s *Store) WriteInvocation(ctx context.Context, inv ir.Invocation) error {
 // Each invocation can have exactly ONE completion (enforced by UNIQUE constraint on invocation_id).
 //
 // The completion's Result and SecurityContext are serialized to canonical JSON
-// per RFC 8785 for deterministic replay.
+// per RFC 8785 for deterministic replay. A result containing a value that is
+// not a sanctioned IR type is rejected with *InvalidValueError (CP-5).
 //
 // Note: The invocation referenced by InvocationID must exist (foreign key constraint).
 // Note: Attempting to write a second completion for an invocation will silently fail (idempotent).
+//
+// Use WriteCompletionIfNew to learn whether the row was actually inserted.
 func (s *Store) WriteCompletion(ctx context.Context, comp ir.Completion) error {
+	_, err := s.WriteCompletionIfNew(ctx, comp)
+	return err
+}
+
+// WriteCompletionIfNew is WriteCompletion that also reports whether the
+// completion was inserted. inserted is false when the completion, or another
+// completion of the same invocation, already exists; the stored row is left
+// unchanged. Callers use this to dedup resubmitted completions at the event
+// boundary.
+func (s *Store) WriteCompletionIfNew(ctx context.Context, comp ir.Completion) (inserted bool, err error) {
 	resultJSON, err := marshalResult(comp.Result)
 	if err != nil {
-		return fmt.Errorf("write completion: %w", err)
+		return false, fmt.Errorf("write completion: %w", err)
 	}
 
 	secCtxJSON, err := marshalSecurityContext(comp.SecurityContext)
 	if err != nil {
-		return fmt.Errorf("write completion: %w", err)
+		return false, fmt.Errorf("write completion: %w", err)
 	}
 
 	// ON CONFLICT DO NOTHING handles both:
 	// 1. Duplicate completion ID (same completion written twice)
 	// 2. Duplicate invocation_id (second completion for same invocation)
 	// Both are silently ignored for idempotency.
-	_, err = s.db.ExecContext(ctx, `
+	result, err := s.db.ExecContext(ctx, `
 		INSERT INTO completions
 		(id, invocation_id, output_case, result, seq, security_context)
 		VALUES (?, ?, ?, ?, ?, ?)
@@ -85,10 +182,15 @@ func (s *Store) WriteCompletion(ctx context.Context, comp ir.Completion) error {
 		secCtxJSON,
 	)
 	if err != nil {
-		return fmt.Errorf("write completion: %w", err)
+		return false, fmt.Errorf("write completion: %w", err)
 	}
 
-	return nil
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("write completion: rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
 }
 
 // WriteSyncFiring inserts a sync firing record into the store.
@@ -169,24 +271,92 @@ func (s *Store) HasFiring(ctx context.Context, completionID, syncID, bindingHash
 	return count > 0, nil
 }
 
+// ProvenanceReferenceError is returned when a provenance edge references a
+// sync firing or invocation that does not exist (foreign key violation).
+type ProvenanceReferenceError struct {
+	SyncFiringID      int64
+	InvocationID      string
+	MissingFiring     bool
+	MissingInvocation bool
+}
+
+func (e *ProvenanceReferenceError) Error() string {
+	var missing []string
+	if e.MissingFiring {
+		missing = append(missing, fmt.Sprintf("sync firing %d", e.SyncFiringID))
+	}
+	if e.MissingInvocation {
+		missing = append(missing, fmt.Sprintf("invocation %s", e.InvocationID))
+	}
+	if len(missing) == 0 {
+		// Referenced rows reappeared between the insert and the lookup
+		return fmt.Sprintf("provenance edge %d -> %s references a missing record", e.SyncFiringID, e.InvocationID)
+	}
+	return fmt.Sprintf("provenance edge references missing %s", strings.Join(missing, " and "))
+}
+
+// IsProvenanceReferenceError returns true if the error is a ProvenanceReferenceError.
+func IsProvenanceReferenceError(err error) bool {
+	var e *ProvenanceReferenceError
+	return errors.As(err, &e)
+}
+
 // WriteProvenanceEdge inserts a provenance edge linking a sync firing to its generated invocation.
-// Uses ON CONFLICT(sync_firing_id) DO NOTHING - each firing produces exactly one invocation.
+// Uses ON CONFLICT(sync_firing_id, invocation_id) DO NOTHING - each (firing, invocation)
+// pair is linked at most once; a firing may link several invocations.
+// Returns inserted=false when the edge already exists.
 //
-// Note: Both sync_firing_id and invocation_id must exist (foreign key constraints).
-func (s *Store) WriteProvenanceEdge(ctx context.Context, syncFiringID int64, invocationID string) error {
-	_, err := s.db.ExecContext(ctx, `
+// Both sync_firing_id and invocation_id must exist (foreign key constraints);
+// otherwise a *ProvenanceReferenceError names the missing record(s).
+func (s *Store) WriteProvenanceEdge(ctx context.Context, syncFiringID int64, invocationID string) (inserted bool, err error) {
+	result, err := s.db.ExecContext(ctx, `
 		INSERT INTO provenance_edges
 		(sync_firing_id, invocation_id)
 		VALUES (?, ?)
-		ON CONFLICT(sync_firing_id) DO NOTHING
+		ON CONFLICT(sync_firing_id, invocation_id) DO NOTHING
 	`,
 		syncFiringID,
 		invocationID,
 	)
 	if err != nil {
-		return fmt.Errorf("write provenance edge: %w", err)
+		if isForeignKeyViolation(err) {
+			return false, s.provenanceReferenceError(ctx, syncFiringID, invocationID)
+		}
+		return false, fmt.Errorf("write provenance edge: %w", err)
 	}
-	return nil
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("write provenance edge: rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// provenanceReferenceError builds a *ProvenanceReferenceError, looking up
+// which of the referenced records is missing.
+func (s *Store) provenanceReferenceError(ctx context.Context, syncFiringID int64, invocationID string) error {
+	var firings, invocations int
+	err := s.db.QueryRowContext(ctx, `
+		SELECT
+			(SELECT COUNT(*) FROM sync_firings WHERE id = ?),
+			(SELECT COUNT(*) FROM invocations WHERE id = ?)
+	`, syncFiringID, invocationID).Scan(&firings, &invocations)
+	if err != nil {
+		return fmt.Errorf("write provenance edge: check references: %w", err)
+	}
+	return &ProvenanceReferenceError{
+		SyncFiringID:      syncFiringID,
+		InvocationID:      invocationID,
+		MissingFiring:     firings == 0,
+		MissingInvocation: invocations == 0,
+	}
+}
+
+// isForeignKeyViolation reports whether err is a SQLite FOREIGN KEY
+// constraint failure.
+func isForeignKeyViolation(err error) bool {
+	var sqliteErr sqlite3.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey
 }
 
 // WriteSyncFiringAtomic atomically writes a sync firing, invocation, and provenance edge
@@ -205,6 +375,28 @@ func (s *Store) WriteSyncFiringAtomic(
 	firing ir.SyncFiring,
 	inv ir.Invocation,
 ) (firingID int64, inserted bool, err error) {
+	return s.WriteSyncFiringAtomicMulti(ctx, firing, []ir.Invocation{inv})
+}
+
+// WriteSyncFiringAtomicMulti is WriteSyncFiringAtomic for firings that generate
+// several invocations (multi-action then-clauses). The firing, every invocation,
+// and one provenance edge per invocation are written in a single transaction.
+//
+// The UNIQUE(completion_id, sync_id, binding_hash) constraint still guards the
+// firing: if it already exists, inserted=false and none of the invocations or
+// edges are written. Invocations are written in slice order.
+//
+// Returns an error if invs is empty - a firing with no provenance edges would
+// be indistinguishable from a crash-orphaned firing.
+func (s *Store) WriteSyncFiringAtomicMulti(
+	ctx context.Context,
+	firing ir.SyncFiring,
+	invs []ir.Invocation,
+) (firingID int64, inserted bool, err error) {
+	if len(invs) == 0 {
+		return 0, false, fmt.Errorf("atomic sync firing: no invocations for firing %s/%s", firing.CompletionID, firing.SyncID)
+	}
+
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return 0, false, fmt.Errorf("atomic sync firing: begin tx: %w", err)
@@ -254,49 +446,51 @@ func (s *Store) WriteSyncFiringAtomic(
 		return 0, false, fmt.Errorf("atomic sync firing: last insert id: %w", err)
 	}
 
-	// Step 2: Marshal and write invocation
-	argsJSON, err := marshalArgs(inv.Args)
-	if err != nil {
-		return 0, false, fmt.Errorf("atomic sync firing: marshal args: %w", err)
-	}
+	for i, inv := range invs {
+		// Step 2: Marshal and write invocation
+		argsJSON, err := marshalArgs(inv.Args)
+		if err != nil {
+			return 0, false, fmt.Errorf("atomic sync firing: invocation %d: marshal args: %w", i, err)
+		}
 
-	secCtxJSON, err := marshalSecurityContext(inv.SecurityContext)
-	if err != nil {
-		return 0, false, fmt.Errorf("atomic sync firing: marshal security context: %w", err)
-	}
+		secCtxJSON, err := marshalSecurityContext(inv.SecurityContext)
+		if err != nil {
+			return 0, false, fmt.Errorf("atomic sync firing: invocation %d: marshal security context: %w", i, err)
+		}
 
-	_, err = tx.ExecContext(ctx, `
-		INSERT INTO invocations
-		(id, flow_token, action_uri, args, seq, security_context, spec_hash, engine_version, ir_version)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
-		ON CONFLICT(id) DO NOTHING
-	`,
-		inv.ID,
-		inv.FlowToken,
-		string(inv.ActionURI),
-		argsJSON,
-		inv.Seq,
-		secCtxJSON,
-		inv.SpecHash,
-		inv.EngineVersion,
-		inv.IRVersion,
-	)
-	if err != nil {
-		return 0, false, fmt.Errorf("atomic sync firing: write invocation: %w", err)
-	}
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO invocations
+			(id, flow_token, action_uri, args, seq, security_context, spec_hash, engine_version, ir_version)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(id) DO NOTHING
+		`,
+			inv.ID,
+			inv.FlowToken,
+			string(inv.ActionURI),
+			argsJSON,
+			inv.Seq,
+			secCtxJSON,
+			inv.SpecHash,
+			inv.EngineVersion,
+			inv.IRVersion,
+		)
+		if err != nil {
+			return 0, false, fmt.Errorf("atomic sync firing: invocation %d: write invocation: %w", i, err)
+		}
 
-	// Step 3: Write provenance edge
-	_, err = tx.ExecContext(ctx, `
-		INSERT INTO provenance_edges
-		(sync_firing_id, invocation_id)
-		VALUES (?, ?)
-		ON CONFLICT(sync_firing_id) DO NOTHING
-	`,
-		firingID,
-		inv.ID,
-	)
-	if err != nil {
-		return 0, false, fmt.Errorf("atomic sync firing: write provenance: %w", err)
+		// Step 3: Write provenance edge
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO provenance_edges
+			(sync_firing_id, invocation_id)
+			VALUES (?, ?)
+			ON CONFLICT(sync_firing_id, invocation_id) DO NOTHING
+		`,
+			firingID,
+			inv.ID,
+		)
+		if err != nil {
+			return 0, false, fmt.Errorf("atomic sync firing: invocation %d: write provenance: %w", i, err)
+		}
 	}
 
 	if err := tx.Commit(); err != nil {
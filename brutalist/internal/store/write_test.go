@@ -2,7 +2,10 @@ package store
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/roach88/nysm/internal/ir"
@@ -84,7 +87,7 @@ func TestWriteInvocation_CanonicalJSON(t *testing.T) {
 			"mango": ir.IRString("m"),
 		},
 		Seq:             1,
-		SecurityContext: ir.SecurityContext{},
+		SecurityContext: testSecurityContext,
 		SpecHash:        "hash",
 		EngineVersion:   "0.1.0",
 		IRVersion:       "1",
@@ -122,7 +125,7 @@ func TestWriteInvocation_Idempotent(t *testing.T) {
 		ActionURI:       ir.ActionRef("Cart.addItem"),
 		Args:            ir.IRObject{},
 		Seq:             1,
-		SecurityContext: ir.SecurityContext{},
+		SecurityContext: testSecurityContext,
 		SpecHash:        "hash",
 		EngineVersion:   "0.1.0",
 		IRVersion:       "1",
@@ -147,6 +150,42 @@ func TestWriteInvocation_Idempotent(t *testing.T) {
 	}
 }
 
+func TestWriteInvocationIfNew_ReportsInserted(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+
+	inv := createTestInvocation("inv-1", "flow-1", "Cart.addItem", 1)
+	inv.Args = ir.IRObject{"item_id": ir.IRString("widget")}
+
+	inserted, err := store.WriteInvocationIfNew(ctx, inv)
+	if err != nil {
+		t.Fatalf("first WriteInvocationIfNew failed: %v", err)
+	}
+	if !inserted {
+		t.Error("first write: inserted = false, want true")
+	}
+
+	// Same ID, different content: skipped, stored row unchanged
+	dup := inv
+	dup.Args = ir.IRObject{"item_id": ir.IRString("gadget")}
+	dup.Seq = 99
+	inserted, err = store.WriteInvocationIfNew(ctx, dup)
+	if err != nil {
+		t.Fatalf("duplicate WriteInvocationIfNew failed: %v", err)
+	}
+	if inserted {
+		t.Error("duplicate write: inserted = true, want false")
+	}
+
+	got, err := store.ReadInvocation(ctx, "inv-1")
+	if err != nil {
+		t.Fatalf("ReadInvocation failed: %v", err)
+	}
+	if got.Seq != 1 || got.Args["item_id"] != ir.IRString("widget") {
+		t.Errorf("stored row changed: seq=%d args=%v, want seq=1 item_id=widget", got.Seq, got.Args)
+	}
+}
+
 func TestWriteInvocation_SecurityContext(t *testing.T) {
 	path := filepath.Join(t.TempDir(), "test.db")
 	s, err := Open(path)
@@ -204,7 +243,7 @@ func TestWriteCompletion_Basic(t *testing.T) {
 		ActionURI:       ir.ActionRef("Cart.addItem"),
 		Args:            ir.IRObject{},
 		Seq:             1,
-		SecurityContext: ir.SecurityContext{},
+		SecurityContext: testSecurityContext,
 		SpecHash:        "hash",
 		EngineVersion:   "0.1.0",
 		IRVersion:       "1",
@@ -273,7 +312,7 @@ func TestWriteCompletion_ErrorOutputCase(t *testing.T) {
 		ActionURI:       ir.ActionRef("Cart.addItem"),
 		Args:            ir.IRObject{},
 		Seq:             1,
-		SecurityContext: ir.SecurityContext{},
+		SecurityContext: testSecurityContext,
 		SpecHash:        "hash",
 		EngineVersion:   "0.1.0",
 		IRVersion:       "1",
@@ -289,7 +328,7 @@ func TestWriteCompletion_ErrorOutputCase(t *testing.T) {
 			"message":    ir.IRString("Item does not exist"),
 		},
 		Seq:             2,
-		SecurityContext: ir.SecurityContext{},
+		SecurityContext: testSecurityContext,
 	}
 
 	err = s.WriteCompletion(context.Background(), comp)
@@ -323,7 +362,7 @@ func TestWriteCompletion_Idempotent(t *testing.T) {
 		ActionURI:       ir.ActionRef("Cart.addItem"),
 		Args:            ir.IRObject{},
 		Seq:             1,
-		SecurityContext: ir.SecurityContext{},
+		SecurityContext: testSecurityContext,
 		SpecHash:        "hash",
 		EngineVersion:   "0.1.0",
 		IRVersion:       "1",
@@ -336,19 +375,37 @@ func TestWriteCompletion_Idempotent(t *testing.T) {
 		OutputCase:      "Success",
 		Result:          ir.IRObject{},
 		Seq:             2,
-		SecurityContext: ir.SecurityContext{},
+		SecurityContext: testSecurityContext,
 	}
 
 	// Write twice - should not error
-	err = s.WriteCompletion(context.Background(), comp)
+	inserted, err := s.WriteCompletionIfNew(context.Background(), comp)
 	if err != nil {
 		t.Fatalf("first WriteCompletion() failed: %v", err)
 	}
+	if !inserted {
+		t.Error("first WriteCompletionIfNew() inserted = false, want true")
+	}
 
-	err = s.WriteCompletion(context.Background(), comp)
+	inserted, err = s.WriteCompletionIfNew(context.Background(), comp)
 	if err != nil {
 		t.Fatalf("second WriteCompletion() failed: %v", err)
 	}
+	if inserted {
+		t.Error("second WriteCompletionIfNew() inserted = true, want false")
+	}
+
+	// A second completion of the same invocation is also not inserted
+	retry := comp
+	retry.ID = "comp-789"
+	retry.OutputCase = "Error"
+	inserted, err = s.WriteCompletionIfNew(context.Background(), retry)
+	if err != nil {
+		t.Fatalf("retry WriteCompletion() failed: %v", err)
+	}
+	if inserted {
+		t.Error("retry WriteCompletionIfNew() inserted = true, want false")
+	}
 
 	// Verify only one row exists
 	var count int
@@ -373,7 +430,7 @@ func TestWriteCompletion_ForeignKeyViolation(t *testing.T) {
 		OutputCase:      "Success",
 		Result:          ir.IRObject{},
 		Seq:             1,
-		SecurityContext: ir.SecurityContext{},
+		SecurityContext: testSecurityContext,
 	}
 
 	err = s.WriteCompletion(context.Background(), comp)
@@ -397,7 +454,7 @@ func TestWriteCompletion_SecurityContext(t *testing.T) {
 		ActionURI:       ir.ActionRef("Test.action"),
 		Args:            ir.IRObject{},
 		Seq:             1,
-		SecurityContext: ir.SecurityContext{},
+		SecurityContext: testSecurityContext,
 		SpecHash:        "hash",
 		EngineVersion:   "0.1.0",
 		IRVersion:       "1",
@@ -450,7 +507,7 @@ func TestWriteMultipleInvocations(t *testing.T) {
 			ActionURI:       ir.ActionRef("Test.action"),
 			Args:            ir.IRObject{"index": ir.IRInt(int64(i))},
 			Seq:             int64(i),
-			SecurityContext: ir.SecurityContext{},
+			SecurityContext: testSecurityContext,
 			SpecHash:        "hash",
 			EngineVersion:   "0.1.0",
 			IRVersion:       "1",
@@ -467,3 +524,230 @@ func TestWriteMultipleInvocations(t *testing.T) {
 		t.Errorf("count = %d, want 5", count)
 	}
 }
+
+func TestWriteInvocations_Batch(t *testing.T) {
+	s := createTestStore(t)
+	ctx := context.Background()
+
+	invs := []ir.Invocation{
+		createTestInvocation("inv-1", "flow-1", "Cart.addItem", 1),
+		createTestInvocation("inv-2", "flow-1", "Cart.addItem", 2),
+		createTestInvocation("inv-3", "flow-1", "Cart.checkout", 3),
+	}
+	if err := s.WriteInvocations(ctx, invs); err != nil {
+		t.Fatalf("WriteInvocations() failed: %v", err)
+	}
+
+	got, _, err := s.ReadFlow(ctx, "flow-1")
+	if err != nil {
+		t.Fatalf("ReadFlow() failed: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("len(invocations) = %d, want 3", len(got))
+	}
+	for i, inv := range got {
+		if inv.ID != invs[i].ID {
+			t.Errorf("invocations[%d].ID = %s, want %s", i, inv.ID, invs[i].ID)
+		}
+	}
+}
+
+// Duplicates follow WriteInvocation's idempotency: the duplicate row is
+// skipped and the rest of the batch commits.
+func TestWriteInvocations_DuplicateSkipped(t *testing.T) {
+	s := createTestStore(t)
+	ctx := context.Background()
+
+	existing := createTestInvocation("inv-2", "flow-1", "Cart.addItem", 2)
+	if err := s.WriteInvocation(ctx, existing); err != nil {
+		t.Fatalf("WriteInvocation() failed: %v", err)
+	}
+
+	invs := []ir.Invocation{
+		createTestInvocation("inv-1", "flow-1", "Cart.addItem", 1),
+		createTestInvocation("inv-2", "flow-1", "Cart.other", 99), // Mid-list duplicate ID
+		createTestInvocation("inv-3", "flow-1", "Cart.checkout", 3),
+		createTestInvocation("inv-1", "flow-1", "Cart.addItem", 1), // Duplicate within batch
+	}
+	if err := s.WriteInvocations(ctx, invs); err != nil {
+		t.Fatalf("WriteInvocations() failed: %v", err)
+	}
+
+	var count int
+	s.db.QueryRow("SELECT COUNT(*) FROM invocations").Scan(&count)
+	if count != 3 {
+		t.Errorf("count = %d, want 3", count)
+	}
+
+	// The original row is not overwritten
+	inv, err := s.ReadInvocation(ctx, "inv-2")
+	if err != nil {
+		t.Fatalf("ReadInvocation() failed: %v", err)
+	}
+	if inv.ActionURI != "Cart.addItem" || inv.Seq != 2 {
+		t.Errorf("inv-2 = %s@%d, want Cart.addItem@2 (first write wins)", inv.ActionURI, inv.Seq)
+	}
+}
+
+func TestWriteInvocations_FailureRollsBack(t *testing.T) {
+	s := createTestStore(t)
+	ctx := context.Background()
+
+	bad := createTestInvocation("inv-2", "flow-1", "Cart.addItem", 2)
+	bad.Args = ir.IRObject{"x": ir.IRNull{}} // Null is forbidden in canonical JSON
+
+	invs := []ir.Invocation{
+		createTestInvocation("inv-1", "flow-1", "Cart.addItem", 1),
+		bad,
+		createTestInvocation("inv-3", "flow-1", "Cart.checkout", 3),
+	}
+	if err := s.WriteInvocations(ctx, invs); err == nil {
+		t.Fatal("WriteInvocations() succeeded, want error")
+	}
+
+	var count int
+	s.db.QueryRow("SELECT COUNT(*) FROM invocations").Scan(&count)
+	if count != 0 {
+		t.Errorf("count = %d, want 0 (batch must roll back)", count)
+	}
+}
+
+func TestWriteInvocations_Empty(t *testing.T) {
+	s := createTestStore(t)
+	if err := s.WriteInvocations(context.Background(), nil); err != nil {
+		t.Errorf("WriteInvocations(nil) failed: %v", err)
+	}
+}
+
+// benchmarkInvocations builds n distinct invocations for write benchmarks.
+func benchmarkInvocations(n int) []ir.Invocation {
+	invs := make([]ir.Invocation, n)
+	for i := range invs {
+		invs[i] = createTestInvocation(fmt.Sprintf("inv-%d", i), "flow-bench", "Cart.addItem", int64(i+1))
+		invs[i].Args = ir.IRObject{"item_id": ir.IRString(fmt.Sprintf("item-%d", i))}
+	}
+	return invs
+}
+
+func BenchmarkWriteInvocation_Loop(b *testing.B) {
+	invs := benchmarkInvocations(500)
+	ctx := context.Background()
+	for b.Loop() {
+		b.StopTimer()
+		s, err := Open(filepath.Join(b.TempDir(), "bench.db"))
+		if err != nil {
+			b.Fatalf("Open() failed: %v", err)
+		}
+		b.StartTimer()
+
+		for _, inv := range invs {
+			if err := s.WriteInvocation(ctx, inv); err != nil {
+				b.Fatalf("WriteInvocation() failed: %v", err)
+			}
+		}
+
+		b.StopTimer()
+		s.Close()
+		b.StartTimer()
+	}
+}
+
+func BenchmarkWriteInvocations_Batch(b *testing.B) {
+	invs := benchmarkInvocations(500)
+	ctx := context.Background()
+	for b.Loop() {
+		b.StopTimer()
+		s, err := Open(filepath.Join(b.TempDir(), "bench.db"))
+		if err != nil {
+			b.Fatalf("Open() failed: %v", err)
+		}
+		b.StartTimer()
+
+		if err := s.WriteInvocations(ctx, invs); err != nil {
+			b.Fatalf("WriteInvocations() failed: %v", err)
+		}
+
+		b.StopTimer()
+		s.Close()
+		b.StartTimer()
+	}
+}
+
+// smuggledFloat satisfies ir.IRValue by embedding a sanctioned type, which is
+// how a float can slip past the sealed interface.
+type smuggledFloat struct {
+	ir.IRInt
+	value float64
+}
+
+func TestWriteCompletion_NestedValuesAccepted(t *testing.T) {
+	s := createTestStore(t)
+	ctx := context.Background()
+
+	s.WriteInvocation(ctx, createTestInvocation("inv-1", "flow-1", "Cart.checkout", 1))
+
+	comp := createTestCompletion("comp-1", "inv-1", "Success", 2)
+	comp.Result = ir.IRObject{
+		"order": ir.IRObject{
+			"items": ir.IRArray{
+				ir.IRObject{"sku": ir.IRString("widget"), "qty": ir.IRInt(2)},
+			},
+			"paid": ir.IRBool(true),
+		},
+	}
+	if err := s.WriteCompletion(ctx, comp); err != nil {
+		t.Fatalf("WriteCompletion() failed: %v", err)
+	}
+}
+
+func TestWriteCompletion_RejectsFloat(t *testing.T) {
+	s := createTestStore(t)
+	ctx := context.Background()
+
+	s.WriteInvocation(ctx, createTestInvocation("inv-1", "flow-1", "Cart.checkout", 1))
+
+	comp := createTestCompletion("comp-1", "inv-1", "Success", 2)
+	comp.Result = ir.IRObject{
+		"order": ir.IRObject{
+			"items": ir.IRArray{
+				ir.IRObject{"sku": ir.IRString("widget")},
+				ir.IRObject{"sku": ir.IRString("gadget"), "price": smuggledFloat{value: 9.99}},
+			},
+		},
+	}
+
+	err := s.WriteCompletion(ctx, comp)
+	if err == nil {
+		t.Fatal("WriteCompletion() succeeded, want error")
+	}
+	if !IsInvalidValueError(err) {
+		t.Fatalf("error = %v, want InvalidValueError", err)
+	}
+
+	var invalid *InvalidValueError
+	errors.As(err, &invalid)
+	if invalid.Path != "result.order.items[1].price" {
+		t.Errorf("Path = %q, want %q", invalid.Path, "result.order.items[1].price")
+	}
+
+	var count int
+	s.db.QueryRow("SELECT COUNT(*) FROM completions").Scan(&count)
+	if count != 0 {
+		t.Errorf("count = %d, want 0 (nothing persisted)", count)
+	}
+}
+
+func TestWriteInvocation_RejectsFloat(t *testing.T) {
+	s := createTestStore(t)
+
+	inv := createTestInvocation("inv-1", "flow-1", "Cart.addItem", 1)
+	inv.Args = ir.IRObject{"qty": smuggledFloat{value: 1.5}}
+
+	err := s.WriteInvocation(context.Background(), inv)
+	if !IsInvalidValueError(err) {
+		t.Fatalf("error = %v, want InvalidValueError", err)
+	}
+	if !strings.Contains(err.Error(), "args.qty") {
+		t.Errorf("error = %v, want path args.qty", err)
+	}
+}
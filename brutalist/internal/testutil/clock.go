@@ -9,8 +9,9 @@ import "sync"
 //
 // Thread-safety: All methods are safe for concurrent use via internal mutex.
 type DeterministicClock struct {
-	mu  sync.Mutex
-	seq int64
+	mu    sync.Mutex
+	seq   int64
+	start int64 // Position restored by Reset
 }
 
 // NewDeterministicClock creates a new deterministic clock starting at 0.
@@ -20,6 +21,14 @@ func NewDeterministicClock() *DeterministicClock {
 	return &DeterministicClock{seq: 0}
 }
 
+// NewDeterministicClockAt creates a deterministic clock positioned at start,
+// like engine.NewClockAt.
+//
+// The first call to Next() returns start+1.
+func NewDeterministicClockAt(start int64) *DeterministicClock {
+	return &DeterministicClock{seq: start, start: start}
+}
+
 // Next increments and returns the next sequence number.
 //
 // Thread-safe: uses mutex to protect seq access.
@@ -40,11 +49,13 @@ func (c *DeterministicClock) Current() int64 {
 	return c.seq
 }
 
-// Reset resets the clock to 0.
+// Reset resets the clock to its starting position (0 unless created with
+// NewDeterministicClockAt).
 //
-// Used for test reuse. After Reset(), the next call to Next() returns 1.
+// Used for test reuse. After Reset(), the next call to Next() returns
+// start+1 (1 for NewDeterministicClock).
 func (c *DeterministicClock) Reset() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.seq = 0
+	c.seq = c.start
 }
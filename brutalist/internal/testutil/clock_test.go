@@ -44,6 +44,17 @@ func TestDeterministicClock_Reset(t *testing.T) {
 	assert.Equal(t, int64(1), clock.Next())
 }
 
+func TestDeterministicClock_StartsAt(t *testing.T) {
+	clock := NewDeterministicClockAt(99)
+	assert.Equal(t, int64(99), clock.Current())
+	assert.Equal(t, int64(100), clock.Next())
+
+	// Reset returns to the starting position, not 0
+	clock.Next()
+	clock.Reset()
+	assert.Equal(t, int64(100), clock.Next())
+}
+
 func TestDeterministicClock_ThreadSafe(t *testing.T) {
 	clock := NewDeterministicClock()
 	const numGoroutines = 100
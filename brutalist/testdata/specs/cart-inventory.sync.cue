@@ -57,10 +57,11 @@ sync: "cart-inventory-reserve": {
 
 	// Where: Query all items in the cart
 	// Returns a SET of bindings - one per CartItem row matching the filter.
+	// Flow scope already restricts rows to this checkout's flow.
 	// Multi-binding pattern: N rows → N invocations
 	where: {
 		from:   "CartItem"
-		filter: "flow_token = :flow_token"
+		filter: "quantity > 0"
 		bind: {
 			item_id:  "item_id"
 			quantity: "quantity"
@@ -151,7 +152,7 @@ sync: "web-cart-checkout": {
 	// Uses Request state to filter by path
 	where: {
 		from:   "Request"
-		filter: "request_id = :request_id AND path = '/checkout' AND method = 'POST'"
+		filter: "request_id == bound.request_id && path == '/checkout' && method == 'POST'"
 		bind: {
 			cart_id: "flow_token" // Cart identified by flow
 		}